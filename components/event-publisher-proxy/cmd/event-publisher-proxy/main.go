@@ -59,6 +59,7 @@ func main() {
 	metricsCollector := metrics.NewCollector(latency.NewBucketsProvider())
 	prometheus.MustRegister(metricsCollector)
 	metricsCollector.SetHealthStatus(true)
+	metricsCollector.SetMaxEventTypes(opts.MaxEventTypes)
 
 	// Instantiate configured commander.
 	var c commander.Commander
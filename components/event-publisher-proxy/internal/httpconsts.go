@@ -9,4 +9,6 @@ const (
 	CeTypeHeader        = "ce-type"
 	CeSourceHeader      = "ce-source"
 	CeSpecVersionHeader = "ce-specversion"
+	// CeCorrelationIDHeader is the header of the NATS messages carrying the correlation ID of the event.
+	CeCorrelationIDHeader = "ce-correlationid"
 )
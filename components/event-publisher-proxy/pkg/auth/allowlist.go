@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// allowlistReloadInterval is the minimal time between two checks of the allowlist file for changes.
+const allowlistReloadInterval = 10 * time.Second
+
+// Allowlist authorizes the client identities by the rules of a policy file which is maintained by the
+// eventing-controller, such as the allowlist of the event types provided by the Applications. Unlike the policy file,
+// the file is reloaded when it changes, and a missing file allows nothing.
+type Allowlist struct {
+	path string
+	now  func() time.Time
+
+	mu         sync.Mutex
+	rules      []Rule
+	modTime    time.Time
+	nextReload time.Time
+}
+
+// NewAllowlist returns an Allowlist reading the rules from the given JSON file in the format of the policy file.
+func NewAllowlist(path string) *Allowlist {
+	return &Allowlist{path: path, now: time.Now}
+}
+
+// Allowed reports whether the given identity may publish events of the given type and source.
+func (a *Allowlist) Allowed(identity, eventType, eventSource string) bool {
+	for _, rule := range a.currentRules() {
+		if rule.Identity == identity && rule.Matches(eventType, eventSource) {
+			return true
+		}
+	}
+	return false
+}
+
+// currentRules returns the rules of the file, reloading them if the file changed since the last check.
+// The last rules are kept if the file cannot be read.
+func (a *Allowlist) currentRules() []Rule {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if now.Before(a.nextReload) {
+		return a.rules
+	}
+	a.nextReload = now.Add(allowlistReloadInterval)
+	// ignore the error, the file is checked again after the reload interval
+	_ = a.reload()
+	return a.rules
+}
+
+func (a *Allowlist) reload() error {
+	info, err := os.Stat(a.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		a.rules, a.modTime = nil, time.Time{}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(a.modTime) {
+		return nil
+	}
+	policy, err := LoadPolicy(a.path)
+	if err != nil {
+		return err
+	}
+	a.rules, a.modTime = policy.Rules, info.ModTime()
+	return nil
+}
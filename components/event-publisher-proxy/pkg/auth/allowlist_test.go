@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowlist_Allowed(t *testing.T) {
+	// given
+	allowlistFile := filepath.Join(t.TempDir(), "allowlist.json")
+	now := time.Now()
+	allowlist := NewAllowlist(allowlistFile)
+	allowlist.now = func() time.Time { return now }
+	writeAllowlist := func(content string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(allowlistFile, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(allowlistFile, modTime, modTime))
+	}
+
+	// when, then
+	require.False(t, allowlist.Allowed("commerce", "order.created.v1", "commerce"), "missing file")
+
+	writeAllowlist(`{"rules":[{"identity":"commerce","types":["order.created.v1"],"sources":["commerce"]}]}`,
+		now.Add(-time.Minute))
+	require.False(t, allowlist.Allowed("commerce", "order.created.v1", "commerce"), "before the reload interval")
+	now = now.Add(allowlistReloadInterval)
+	require.True(t, allowlist.Allowed("commerce", "order.created.v1", "commerce"))
+	require.False(t, allowlist.Allowed("commerce", "order.deleted.v1", "commerce"))
+
+	writeAllowlist(`invalid`, now)
+	now = now.Add(allowlistReloadInterval)
+	require.True(t, allowlist.Allowed("commerce", "order.created.v1", "commerce"), "keeps the last rules")
+
+	require.NoError(t, os.Remove(allowlistFile))
+	now = now.Add(allowlistReloadInterval)
+	require.False(t, allowlist.Allowed("commerce", "order.created.v1", "commerce"), "removed file")
+}
+
+func TestPolicy_Allowed_Allowlist(t *testing.T) {
+	// given
+	policyFile := filepath.Join(t.TempDir(), "policy.json")
+	allowlistFile := filepath.Join(t.TempDir(), "allowlist.json")
+	require.NoError(t, os.WriteFile(policyFile, []byte(`{"rules":[{"identity":"admin"}]}`), 0o600))
+	require.NoError(t, os.WriteFile(allowlistFile, []byte(`{"rules":[{"identity":"commerce"}]}`), 0o600))
+
+	// when
+	_, policy, err := New(Config{Mode: ModeMTLS, PolicyFile: policyFile, AllowlistFile: allowlistFile})
+
+	// then
+	require.NoError(t, err)
+	require.True(t, policy.Allowed("admin", "order.created.v1", "commerce"))
+	require.True(t, policy.Allowed("commerce", "order.created.v1", "commerce"))
+	require.False(t, policy.Allowed("unknown", "order.created.v1", "commerce"))
+}
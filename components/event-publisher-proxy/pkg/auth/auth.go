@@ -0,0 +1,148 @@
+// Package auth authenticates the clients of the publish endpoints and authorizes them to publish events.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ModeNone disables the authentication.
+	ModeNone = "none"
+	// ModeJWT authenticates the clients by a JWT validated against a JWKS.
+	ModeJWT = "jwt"
+	// ModeMTLS authenticates the clients by the identity of their client certificate.
+	ModeMTLS = "mtls"
+
+	// forwardedClientCertHeader is set by the Istio sidecar with the identities of the client certificates
+	// of the proxies the request passed through.
+	forwardedClientCertHeader = "X-Forwarded-Client-Cert"
+)
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidToken       = errors.New("invalid token")
+)
+
+// Authenticator returns the identity of the client of a request.
+type Authenticator interface {
+	Authenticate(request *http.Request) (string, error)
+}
+
+// Config configures the authentication and authorization of the publish endpoints.
+type Config struct {
+	Mode        string
+	JWKSURL     string
+	JWTIssuer   string
+	JWTAudience string
+	PolicyFile  string
+	// AllowlistFile extends the policy by the rules maintained by the eventing-controller, ignored without a policy.
+	AllowlistFile string
+	// TrustForwardedClientCert identifies the clients without a TLS connection by the X-Forwarded-Client-Cert header.
+	// It must only be enabled behind a sidecar which sanitizes the header, because the clients can set it otherwise.
+	TrustForwardedClientCert bool
+}
+
+// New returns the Authenticator and the Policy of the given configuration.
+// It returns a nil Authenticator if the authentication is disabled, and a nil Policy if no policy file is configured.
+func New(cfg Config) (Authenticator, *Policy, error) {
+	var authenticator Authenticator
+	switch cfg.Mode {
+	case ModeNone, "":
+		return nil, nil, nil
+	case ModeJWT:
+		if cfg.JWKSURL == "" {
+			return nil, nil, errors.New("the JWKS URL is required for the JWT authentication")
+		}
+		authenticator = NewJWTAuthenticator(NewKeySet(cfg.JWKSURL), cfg.JWTIssuer, cfg.JWTAudience)
+	case ModeMTLS:
+		authenticator = MTLSAuthenticator{TrustForwardedClientCert: cfg.TrustForwardedClientCert}
+	default:
+		return nil, nil, fmt.Errorf("invalid authentication mode: %q", cfg.Mode)
+	}
+
+	if cfg.PolicyFile == "" {
+		return authenticator, nil, nil
+	}
+	policy, err := LoadPolicy(cfg.PolicyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.AllowlistFile != "" {
+		policy.allowlist = NewAllowlist(cfg.AllowlistFile)
+	}
+	return authenticator, policy, nil
+}
+
+// MTLSAuthenticator authenticates the clients by the identity of their client certificate.
+type MTLSAuthenticator struct {
+	// TrustForwardedClientCert identifies the clients without a TLS connection by the X-Forwarded-Client-Cert header.
+	TrustForwardedClientCert bool
+}
+
+// Authenticate returns the identity of the client certificate of the given request.
+func (a MTLSAuthenticator) Authenticate(request *http.Request) (string, error) {
+	identity := ClientCertIdentity(request, a.TrustForwardedClientCert)
+	if identity == "" {
+		return "", ErrMissingCredentials
+	}
+	return identity, nil
+}
+
+// ClientCertIdentity returns the common name of the client certificate of the given request. Without a TLS
+// connection, it returns the URI of the client certificate forwarded by Istio if the forwarded header is trusted.
+// It returns an empty string if the request has no client certificate.
+func ClientCertIdentity(request *http.Request, trustForwarded bool) string {
+	if request.TLS != nil && len(request.TLS.PeerCertificates) > 0 {
+		return request.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	header := strings.Join(request.Header.Values(forwardedClientCertHeader), ",")
+	if !trustForwarded || header == "" {
+		return ""
+	}
+	// the header has the format By=<uri>;Hash=<hash>;Subject="<subject>";URI=<uri> with an element per proxy
+	// separated by commas, and the last element is the one added by the local sidecar for its downstream client
+	elements := splitUnquoted(header, ',')
+	for _, pair := range splitUnquoted(elements[len(elements)-1], ';') {
+		if uri, ok := strings.CutPrefix(strings.TrimSpace(pair), "URI="); ok {
+			return strings.Trim(uri, `"`)
+		}
+	}
+	return ""
+}
+
+// splitUnquoted splits the given value at the separators which are not within double quotes.
+func splitUnquoted(value string, separator rune) []string {
+	var parts []string
+	quoted, escaped, start := false, false, 0
+	for i, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && r == separator:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of the given context carrying the given client identity.
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the client identity of the given context, if any.
+func IdentityFromContext(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(identityKey{}).(string)
+	return identity, ok
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCertIdentity(t *testing.T) {
+	// given
+	tlsRequest := httptest.NewRequest(http.MethodPost, "http://localhost/publish", nil)
+	tlsRequest.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "orders"}}},
+	}
+	istioRequest := httptest.NewRequest(http.MethodPost, "http://localhost/publish", nil)
+	istioRequest.Header.Set(forwardedClientCertHeader, `By=spiffe://cluster.local/ns/kyma-system/sa/epp;Hash=abc;`+
+		`Subject="";URI=spiffe://cluster.local/ns/shop/sa/orders`)
+	// the client set a header which the sidecar appended its element to
+	spoofedRequest := httptest.NewRequest(http.MethodPost, "http://localhost/publish", nil)
+	spoofedRequest.Header.Set(forwardedClientCertHeader, `URI=spiffe://cluster.local/ns/shop/sa/admin,`+
+		`By=spiffe://cluster.local/ns/kyma-system/sa/epp;Hash=abc;Subject="CN=orders,O=shop;x";`+
+		`URI=spiffe://cluster.local/ns/shop/sa/orders`)
+	plainRequest := httptest.NewRequest(http.MethodPost, "http://localhost/publish", nil)
+
+	// when, then
+	require.Equal(t, "orders", ClientCertIdentity(tlsRequest, false))
+	require.Equal(t, "spiffe://cluster.local/ns/shop/sa/orders", ClientCertIdentity(istioRequest, true))
+	require.Equal(t, "spiffe://cluster.local/ns/shop/sa/orders", ClientCertIdentity(spoofedRequest, true))
+	require.Equal(t, "", ClientCertIdentity(istioRequest, false))
+	require.Equal(t, "", ClientCertIdentity(plainRequest, true))
+
+	_, err := MTLSAuthenticator{TrustForwardedClientCert: true}.Authenticate(plainRequest)
+	require.ErrorIs(t, err, ErrMissingCredentials)
+	_, err = MTLSAuthenticator{}.Authenticate(istioRequest)
+	require.ErrorIs(t, err, ErrMissingCredentials)
+}
+
+func TestIdentityFromContext(t *testing.T) {
+	// when
+	_, ok := IdentityFromContext(context.Background())
+	identity, withIdentity := IdentityFromContext(WithIdentity(context.Background(), "orders"))
+
+	// then
+	require.False(t, ok)
+	require.True(t, withIdentity)
+	require.Equal(t, "orders", identity)
+}
+
+func TestNew(t *testing.T) {
+	// given
+	policyFile := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(policyFile, []byte(`{"rules":[{"identity":"orders"}]}`), 0o600))
+
+	testCases := []struct {
+		name              string
+		givenConfig       Config
+		wantAuthenticator bool
+		wantPolicy        bool
+		wantErr           bool
+	}{
+		{name: "disabled", givenConfig: Config{Mode: ModeNone}},
+		{name: "jwt", givenConfig: Config{Mode: ModeJWT, JWKSURL: "http://jwks"}, wantAuthenticator: true},
+		{name: "jwt without JWKS URL", givenConfig: Config{Mode: ModeJWT}, wantErr: true},
+		{
+			name:              "mtls with policy",
+			givenConfig:       Config{Mode: ModeMTLS, PolicyFile: policyFile},
+			wantAuthenticator: true,
+			wantPolicy:        true,
+		},
+		{name: "missing policy file", givenConfig: Config{Mode: ModeMTLS, PolicyFile: "missing.json"}, wantErr: true},
+		{name: "invalid mode", givenConfig: Config{Mode: "basic"}, wantErr: true},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			authenticator, policy, err := New(tc.givenConfig)
+
+			// then
+			require.Equal(t, tc.wantErr, err != nil)
+			require.Equal(t, tc.wantAuthenticator, authenticator != nil)
+			require.Equal(t, tc.wantPolicy, policy != nil)
+		})
+	}
+}
+
+func TestPolicy_Allowed(t *testing.T) {
+	// given
+	policy := &Policy{Rules: []Rule{
+		{Identity: "orders", Types: []string{"order.created.v1", "order.updated.*"}, Sources: []string{"commerce"}},
+		{Identity: "admin"},
+	}}
+
+	// when, then
+	require.True(t, policy.Allowed("orders", "order.created.v1", "commerce"))
+	require.True(t, policy.Allowed("orders", "order.updated.v2", "commerce"))
+	require.False(t, policy.Allowed("orders", "order.deleted.v1", "commerce"))
+	require.False(t, policy.Allowed("orders", "order.created.v1", "marketing"))
+	require.True(t, policy.Allowed("admin", "customer.created.v1", "marketing"))
+	require.False(t, policy.Allowed("unknown", "order.created.v1", "commerce"))
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// minRefreshInterval limits the JWKS refreshes triggered by tokens with unknown key IDs.
+	minRefreshInterval = time.Minute
+	fetchTimeout       = 10 * time.Second
+)
+
+// KeySet provides the public keys of a JWKS endpoint by their key ID.
+// The keys are fetched on first use and refreshed when a token refers to an unknown key.
+type KeySet struct {
+	url       string
+	client    *http.Client
+	mutex     sync.Mutex
+	keys      map[string]crypto.PublicKey
+	lastFetch time.Time
+	// fetching is closed when the ongoing fetch completes, and nil if no fetch is ongoing.
+	fetching chan struct{}
+}
+
+// NewKeySet returns a KeySet for the given JWKS URL.
+func NewKeySet(url string) *KeySet {
+	return &KeySet{
+		url:    url,
+		client: &http.Client{Timeout: fetchTimeout},
+	}
+}
+
+// Key returns the public key with the given key ID.
+// The known keys are returned while the keys are fetched, and the callers with unknown key IDs wait for the fetch.
+func (k *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	k.mutex.Lock()
+	for {
+		if key, ok := k.keys[kid]; ok {
+			k.mutex.Unlock()
+			return key, nil
+		}
+		if k.fetching == nil {
+			break
+		}
+		fetching := k.fetching
+		k.mutex.Unlock()
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		k.mutex.Lock()
+	}
+	if time.Since(k.lastFetch) < minRefreshInterval {
+		k.mutex.Unlock()
+		return nil, fmt.Errorf("unknown key ID: %q", kid)
+	}
+	k.lastFetch = time.Now()
+	fetching := make(chan struct{})
+	k.fetching = fetching
+	k.mutex.Unlock()
+
+	keys, err := k.fetch(ctx)
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.fetching = nil
+	close(fetching)
+	if err != nil {
+		return nil, err
+	}
+	k.keys = keys
+	if key, ok := k.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key ID: %q", kid)
+}
+
+// jsonWebKey is an RSA or EC public key of a JWKS.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *KeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := k.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the JWKS: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the JWKS: unexpected status code %d", response.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err = json.NewDecoder(response.Body).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("failed to parse the JWKS: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// keys of unsupported types are skipped
+		if key, keyErr := jwk.publicKey(); keyErr == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve: %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
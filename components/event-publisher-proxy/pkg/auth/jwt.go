@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	bearerPrefix = "Bearer "
+	// es256SignatureSize is the size of the concatenated r and s values of an ES256 signature.
+	es256SignatureSize = 64
+)
+
+// KeyProvider returns the public key with the given key ID.
+type KeyProvider interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// JWTAuthenticator authenticates the clients by an RS256 or ES256 signed JWT in the Authorization header.
+// The identity of the client is the subject of the token.
+type JWTAuthenticator struct {
+	keys     KeyProvider
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// NewJWTAuthenticator returns a JWTAuthenticator verifying the tokens with the given keys.
+// The issuer and audience of the tokens are only checked if set.
+func NewJWTAuthenticator(keys KeyProvider, issuer, audience string) *JWTAuthenticator {
+	return &JWTAuthenticator{keys: keys, issuer: issuer, audience: audience, now: time.Now}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+}
+
+// audience is the aud claim, which is either a string or a list of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// Authenticate returns the subject of the valid JWT of the given request.
+func (a *JWTAuthenticator) Authenticate(request *http.Request) (string, error) {
+	token, ok := strings.CutPrefix(request.Header.Get("Authorization"), bearerPrefix)
+	if !ok {
+		return "", ErrMissingCredentials
+	}
+	claims, err := a.verify(request.Context(), token)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims.Subject, nil
+}
+
+func (a *JWTAuthenticator) verify(ctx context.Context, token string) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+	header := &jwtHeader{}
+	if err := decodeSegment(parts[0], header); err != nil {
+		return nil, fmt.Errorf("malformed header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature: %w", err)
+	}
+	key, err := a.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = verifySignature(header.Alg, key, digest[:], signature); err != nil {
+		return nil, err
+	}
+
+	claims := &jwtClaims{}
+	if err = decodeSegment(parts[1], claims); err != nil {
+		return nil, fmt.Errorf("malformed claims: %w", err)
+	}
+	if err = a.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, digest, signature []byte) error {
+	switch alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key does not match the algorithm %s", alg)
+		}
+		if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest, signature); err != nil {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key does not match the algorithm %s", alg)
+		}
+		if len(signature) != es256SignatureSize {
+			return fmt.Errorf("invalid signature")
+		}
+		r := new(big.Int).SetBytes(signature[:es256SignatureSize/2])
+		s := new(big.Int).SetBytes(signature[es256SignatureSize/2:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported algorithm: %q", alg)
+	}
+}
+
+func (a *JWTAuthenticator) validateClaims(claims *jwtClaims) error {
+	now := a.now().Unix()
+	if claims.ExpiresAt == nil || now >= *claims.ExpiresAt {
+		return fmt.Errorf("token is expired")
+	}
+	if claims.NotBefore != nil && now < *claims.NotBefore {
+		return fmt.Errorf("token is not valid yet")
+	}
+	if a.issuer != "" && claims.Issuer != a.issuer {
+		return fmt.Errorf("unexpected issuer: %q", claims.Issuer)
+	}
+	if a.audience != "" && !contains(claims.Audience, a.audience) {
+		return fmt.Errorf("unexpected audience: %v", claims.Audience)
+	}
+	if claims.Subject == "" {
+		return fmt.Errorf("missing subject")
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	// given
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwksServer := newJWKSServer(t, rsaKey, ecKey)
+	defer jwksServer.Close()
+
+	now := time.Now()
+	authenticator := NewJWTAuthenticator(NewKeySet(jwksServer.URL), "https://issuer", "eventing")
+	authenticator.now = func() time.Time { return now }
+	validClaims := map[string]interface{}{
+		"iss": "https://issuer",
+		"sub": "orders",
+		"aud": []string{"eventing", "other"},
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	withClaim := func(key string, value interface{}) map[string]interface{} {
+		claims := map[string]interface{}{}
+		for k, v := range validClaims {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+	unsignedToken := encodeSegment(t, map[string]string{"alg": "none", "kid": "rsa-key"}) + "." +
+		encodeSegment(t, validClaims) + "."
+
+	testCases := []struct {
+		name         string
+		givenHeader  string
+		wantIdentity string
+		wantErr      error
+	}{
+		{
+			name:         "valid RS256 token",
+			givenHeader:  "Bearer " + signRS256(t, rsaKey, "rsa-key", validClaims),
+			wantIdentity: "orders",
+		},
+		{
+			name:         "valid ES256 token with a single audience",
+			givenHeader:  "Bearer " + signES256(t, ecKey, "ec-key", withClaim("aud", "eventing")),
+			wantIdentity: "orders",
+		},
+		{
+			name:        "missing token",
+			givenHeader: "",
+			wantErr:     ErrMissingCredentials,
+		},
+		{
+			name:        "expired token",
+			givenHeader: "Bearer " + signRS256(t, rsaKey, "rsa-key", withClaim("exp", now.Add(-time.Minute).Unix())),
+			wantErr:     ErrInvalidToken,
+		},
+		{
+			name:        "token without expiry",
+			givenHeader: "Bearer " + signRS256(t, rsaKey, "rsa-key", withClaim("exp", nil)),
+			wantErr:     ErrInvalidToken,
+		},
+		{
+			name:        "token of another issuer",
+			givenHeader: "Bearer " + signRS256(t, rsaKey, "rsa-key", withClaim("iss", "https://other")),
+			wantErr:     ErrInvalidToken,
+		},
+		{
+			name:        "token for another audience",
+			givenHeader: "Bearer " + signRS256(t, rsaKey, "rsa-key", withClaim("aud", "other")),
+			wantErr:     ErrInvalidToken,
+		},
+		{
+			name:        "token signed with the key of another key ID",
+			givenHeader: "Bearer " + signRS256(t, rsaKey, "ec-key", validClaims),
+			wantErr:     ErrInvalidToken,
+		},
+		{
+			name:        "token with an unknown key ID",
+			givenHeader: "Bearer " + signRS256(t, rsaKey, "unknown", validClaims),
+			wantErr:     ErrInvalidToken,
+		},
+		{
+			name:        "unsigned token",
+			givenHeader: "Bearer " + unsignedToken,
+			wantErr:     ErrInvalidToken,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			request := httptest.NewRequest(http.MethodPost, "http://localhost/publish", nil)
+			request.Header.Set("Authorization", tc.givenHeader)
+
+			// when
+			identity, err := authenticator.Authenticate(request)
+
+			// then
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantIdentity, identity)
+		})
+	}
+}
+
+func TestKeySet_KeyDuringFetch(t *testing.T) {
+	// given
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	requested, release := make(chan struct{}), make(chan struct{})
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(requested)
+		<-release
+		encode := base64.RawURLEncoding.EncodeToString
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "EC", "kid": "new-key", "crv": "P-256",
+				"x": encode(ecKey.X.FillBytes(make([]byte, 32))), "y": encode(ecKey.Y.FillBytes(make([]byte, 32))),
+			}},
+		}))
+	}))
+	defer jwksServer.Close()
+	keySet := NewKeySet(jwksServer.URL)
+	keySet.keys = map[string]crypto.PublicKey{"old-key": &ecKey.PublicKey}
+
+	// when a token refers to an unknown key
+	fetched := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, keyErr := keySet.Key(context.Background(), "new-key")
+			fetched <- keyErr
+		}()
+	}
+	<-requested
+
+	// then the known keys are returned during the fetch
+	key, err := keySet.Key(context.Background(), "old-key")
+	require.NoError(t, err)
+	require.Equal(t, &ecKey.PublicKey, key)
+
+	// and both callers get the fetched key once the fetch completes
+	close(release)
+	require.NoError(t, <-fetched)
+	require.NoError(t, <-fetched)
+}
+
+func newJWKSServer(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) *httptest.Server {
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	jwks := map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA", "kid": "rsa-key", "use": "sig",
+				"n": encode(rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "EC", "kid": "ec-key", "crv": "P-256",
+				"x": encode(ecKey.X.FillBytes(make([]byte, 32))), "y": encode(ecKey.Y.FillBytes(make([]byte, 32))),
+			},
+		},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(jwks))
+	}))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	signingInput := encodeSegment(t, map[string]string{"alg": "RS256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	signingInput := encodeSegment(t, map[string]string{"alg": "ES256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	signature := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Policy authorizes the client identities to publish specific event types and sources.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+
+	// allowlist authorizes the identities in addition to the rules, nil if none is configured
+	allowlist *Allowlist
+}
+
+// Rule allows an identity to publish the given event types from the given sources.
+// An empty list allows all the types or sources. A trailing `*` matches any suffix.
+type Rule struct {
+	Identity string   `json:"identity"`
+	Types    []string `json:"types,omitempty"`
+	Sources  []string `json:"sources,omitempty"`
+}
+
+// LoadPolicy reads the policy from the given JSON file.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the authorization policy: %w", err)
+	}
+	policy := &Policy{}
+	if err = json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse the authorization policy: %w", err)
+	}
+	return policy, nil
+}
+
+// Allowed reports whether the given identity may publish events of the given type and source.
+func (p *Policy) Allowed(identity, eventType, eventSource string) bool {
+	for _, rule := range p.Rules {
+		if rule.Identity == identity && rule.Matches(eventType, eventSource) {
+			return true
+		}
+	}
+	return p.allowlist != nil && p.allowlist.Allowed(identity, eventType, eventSource)
+}
+
+// Matches reports whether the given event type and source match the types and sources of the rule.
+func (r Rule) Matches(eventType, eventSource string) bool {
+	return matchesAny(r.Types, eventType) && matchesAny(r.Sources, eventSource)
+}
+
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(value, prefix) {
+			return true
+		}
+		if pattern == value {
+			return true
+		}
+	}
+	return false
+}
@@ -13,10 +13,12 @@ import (
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/informers"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/legacy"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics"
+	pkgnats "github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/nats"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/oauth"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/options"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/receiver"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender/eventmesh"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender/jetstream"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/signals"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/subscribed"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
@@ -41,6 +43,8 @@ type Commander struct {
 	logger           *logger.Logger
 	metricsCollector *metrics.Collector
 	opts             *options.Options
+	// migrationCfg configures the NATS migration backend, nil if no migration is active
+	migrationCfg *env.NATSConfig
 }
 
 // NewCommander creates the Commander for publisher to EventMesh.
@@ -58,6 +62,15 @@ func (c *Commander) Init() error {
 	if err := envconfig.Process("", c.envCfg); err != nil {
 		return xerrors.Errorf("failed to read configuration for %s : %v", commanderName, err)
 	}
+	switch c.opts.MigrationBackend {
+	case options.MigrationBackendEventMesh:
+		return xerrors.Errorf("invalid migration backend for %s : %s is the active backend", commanderName, backend)
+	case options.MigrationBackendNATS:
+		c.migrationCfg = new(env.NATSConfig)
+		if err := envconfig.Process("", c.migrationCfg); err != nil {
+			return xerrors.Errorf("failed to read migration backend configuration for %s : %v", commanderName, err)
+		}
+	}
 	return nil
 }
 
@@ -98,6 +111,11 @@ func (c *Commander) Start() error {
 		c.envCfg.EventTypePrefix,
 		applicationLister,
 	)
+	mappingRules, err := legacy.LoadMappingRules(c.opts.LegacyMappingFile)
+	if err != nil {
+		return xerrors.Errorf("failed to load legacy event mapping rules for %s : %v", commanderName, err)
+	}
+	legacyTransformer.SetMappingRules(mappingRules)
 
 	// Configure Subscription Lister
 	subDynamicSharedInfFactory := subscribed.GenerateSubscriptionInfFactory(k8sConfig)
@@ -124,7 +142,7 @@ func (c *Commander) Start() error {
 		applicationLister, c.logger)
 
 	// start handler which blocks until it receives a shutdown signal
-	if err := handler.New(
+	h := handler.New(
 		messageReceiver,
 		messageSender,
 		health.NewChecker(),
@@ -138,7 +156,28 @@ func (c *Commander) Start() error {
 		ceBuilder,
 		c.envCfg.EventTypePrefix,
 		env.EventMeshBackend,
-	).Start(ctx); err != nil {
+	)
+	if c.migrationCfg != nil {
+		connection, err := pkgnats.Connect(c.migrationCfg.URL,
+			pkgnats.WithRetryOnFailedConnect(c.migrationCfg.RetryOnFailedConnect),
+			pkgnats.WithMaxReconnects(c.migrationCfg.MaxReconnects),
+			pkgnats.WithReconnectWait(c.migrationCfg.ReconnectWait),
+			pkgnats.WithName("Kyma Publisher"),
+		)
+		if err != nil {
+			return xerrors.Errorf("failed to connect to migration backend server for %s : %v", commanderName, err)
+		}
+		defer connection.Close()
+		h.MigrationSender = jetstream.NewSender(ctx, connection, c.migrationCfg, c.opts, c.logger)
+		migrationCleaner, err := c.migrationCfg.NewCleaner(c.logger)
+		if err != nil {
+			return xerrors.Errorf("failed to create migration event type cleaner for %s : %v", commanderName, err)
+		}
+		h.MigrationBuilder = builder.NewGenericBuilder(env.JetStreamSubjectPrefix, migrationCleaner,
+			applicationLister, c.logger)
+		c.namedLogger().Infow("Publishing the events to the migration backend", "backend", options.MigrationBackendNATS)
+	}
+	if err := h.Start(ctx); err != nil {
 		return xerrors.Errorf("failed to start handler for %s : %v", commanderName, err)
 	}
 	c.namedLogger().Info("Event Publisher was shut down")
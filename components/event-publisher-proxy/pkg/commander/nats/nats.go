@@ -13,8 +13,10 @@ import (
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/legacy"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics"
 	pkgnats "github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/nats"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/oauth"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/options"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/receiver"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender/eventmesh"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender/jetstream"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/signals"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/subscribed"
@@ -40,6 +42,8 @@ type Commander struct {
 	logger           *logger.Logger
 	envCfg           *env.NATSConfig
 	opts             *options.Options
+	// migrationCfg configures the EventMesh migration backend, nil if no migration is active
+	migrationCfg *env.EventMeshConfig
 }
 
 // NewCommander creates the Commander for publisher to NATS.
@@ -57,6 +61,16 @@ func (c *Commander) Init() error {
 	if err := envconfig.Process("", c.envCfg); err != nil {
 		return xerrors.Errorf("failed to read configuration for %s : %v", natsCommanderName, err)
 	}
+	switch c.opts.MigrationBackend {
+	case options.MigrationBackendNATS:
+		return xerrors.Errorf("invalid migration backend for %s : %s is the active backend",
+			natsCommanderName, natsBackend)
+	case options.MigrationBackendEventMesh:
+		c.migrationCfg = new(env.EventMeshConfig)
+		if err := envconfig.Process("", c.migrationCfg); err != nil {
+			return xerrors.Errorf("failed to read migration backend configuration for %s : %v", natsCommanderName, err)
+		}
+	}
 	return nil
 }
 
@@ -86,6 +100,15 @@ func (c *Commander) Start() error {
 	// configure the message sender
 	messageSender := jetstream.NewSender(ctx, connection, c.envCfg, c.opts, c.logger)
 
+	// align the maximum request size with the maximum message size of the stream
+	if maxMessageSize, err := messageSender.MaxMessageSize(); err != nil {
+		c.namedLogger().Warnw("Failed to get the maximum message size of the stream", "error", err)
+	} else if c.opts.MaxRequestSize > maxMessageSize {
+		c.namedLogger().Infow("Limiting the maximum request size to the maximum message size of the stream",
+			"max-request-size", maxMessageSize)
+		c.opts.MaxRequestSize = maxMessageSize
+	}
+
 	// cluster config
 	k8sConfig := config.GetConfigOrDie()
 
@@ -105,6 +128,11 @@ func (c *Commander) Start() error {
 		c.envCfg.ToConfig().EventTypePrefix,
 		applicationLister,
 	)
+	mappingRules, err := legacy.LoadMappingRules(c.opts.LegacyMappingFile)
+	if err != nil {
+		return xerrors.Errorf("failed to load legacy event mapping rules for %s : %v", natsCommanderName, err)
+	}
+	legacyTransformer.SetMappingRules(mappingRules)
 
 	// configure Subscription Lister
 	subDynamicSharedInfFactory := subscribed.GenerateSubscriptionInfFactory(k8sConfig)
@@ -125,7 +153,10 @@ func (c *Commander) Start() error {
 	eventTypeCleanerV1 := eventtype.NewCleaner(c.envCfg.EventTypePrefix, applicationLister, c.logger)
 
 	// configure event type cleaner for subscription CRD v1alpha2
-	eventTypeCleaner := cleaner.NewJetStreamCleaner(c.logger)
+	eventTypeCleaner, err := c.envCfg.NewCleaner(c.logger)
+	if err != nil {
+		return xerrors.Errorf("failed to create event type cleaner for %s : %v", natsCommanderName, err)
+	}
 
 	// configure cloud event builder for subscription CRD v1alpha2
 	ceBuilder := builder.NewGenericBuilder(env.JetStreamSubjectPrefix, eventTypeCleaner,
@@ -147,6 +178,25 @@ func (c *Commander) Start() error {
 		c.envCfg.EventTypePrefix,
 		env.JetStreamBackend,
 	)
+	h.EventTailer = messageSender
+	h.StatusReporter = messageSender
+	if c.opts.AsyncPublish {
+		asyncSender, err := jetstream.NewAsyncSender(messageSender, c.opts.AsyncBufferSize)
+		if err != nil {
+			return xerrors.Errorf("failed to create async sender for %s : %v", natsCommanderName, err)
+		}
+		// the buffered events are drained after the handler is shut down, within the drain timeout of the sender
+		defer asyncSender.Close()
+		h.AsyncSender = asyncSender
+	}
+	if c.migrationCfg != nil {
+		client := oauth.NewClient(ctx, c.migrationCfg)
+		defer client.CloseIdleConnections()
+		h.MigrationSender = eventmesh.NewSender(c.migrationCfg.EventMeshPublishURL, client, c.logger)
+		h.MigrationBuilder = builder.NewEventMeshBuilder(c.migrationCfg.EventTypePrefix,
+			c.migrationCfg.EventMeshNamespace, cleaner.NewEventMeshCleaner(c.logger), applicationLister, c.logger)
+		c.namedLogger().Infow("Publishing the events to the migration backend", "backend", options.MigrationBackendEventMesh)
+	}
 	if err := h.Start(ctx); err != nil {
 		return xerrors.Errorf("failed to start handler for %s : %v", natsCommanderName, err)
 	}
@@ -3,6 +3,9 @@ package env
 import (
 	"fmt"
 	"time"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
 )
 
 // compile time check.
@@ -28,6 +31,18 @@ type NATSConfig struct {
 
 	// JetStream-specific configs
 	JSStreamName string `envconfig:"JS_STREAM_NAME" default:"kyma"`
+
+	// Event type cleaner configs, set by the eventing-controller to the values used for the consumer subjects
+	// CleanerStrategy is the name of the registered strategy cleaning the event types and sources.
+	CleanerStrategy string `envconfig:"NATS_CLEANER_STRATEGY" default:"jetstream"`
+	// CleanerAllowedCharacters is the regular expression character class of the characters kept by the cleaner.
+	CleanerAllowedCharacters string `envconfig:"NATS_CLEANER_ALLOWED_CHARACTERS" default:""`
+	// CleanerReplacement substitutes the characters removed by the cleaner.
+	CleanerReplacement string `envconfig:"NATS_CLEANER_REPLACEMENT" default:""`
+	// CleanerHashCollisions appends a hash suffix to the event types changed by the cleaner.
+	CleanerHashCollisions bool `envconfig:"NATS_CLEANER_HASH_COLLISIONS" default:"false"`
+	// CleanerMaxSegmentLength shortens the longer segments of the cleaned event types and appends a hash suffix.
+	CleanerMaxSegmentLength int `envconfig:"NATS_CLEANER_MAX_SEGMENT_LENGTH" default:"0"`
 }
 
 // ToConfig converts to a default EventMeshConfig.
@@ -39,6 +54,27 @@ func (c *NATSConfig) ToConfig() *EventMeshConfig {
 	return cfg
 }
 
+// NewCleaner returns the event type cleaner of the configured cleaning strategy, character policy and collision
+// policy, which must match the ones of the eventing-controller, so that the events are published to the subjects
+// of the consumers.
+func (c *NATSConfig) NewCleaner(logger *logger.Logger) (cleaner.Cleaner, error) {
+	eventTypeCleaner, err := cleaner.New(c.CleanerStrategy, logger, cleaner.CharacterPolicy{
+		AllowedCharacters: c.CleanerAllowedCharacters,
+		Replacement:       c.CleanerReplacement,
+	})
+	if err != nil {
+		return nil, err
+	}
+	collisionPolicy := cleaner.CollisionPolicy{
+		HashCollisions:   c.CleanerHashCollisions,
+		MaxSegmentLength: c.CleanerMaxSegmentLength,
+	}
+	if collisionPolicy.Enabled() {
+		eventTypeCleaner = cleaner.NewCollisionProofCleaner(eventTypeCleaner, collisionPolicy, logger)
+	}
+	return eventTypeCleaner, nil
+}
+
 // String implements the fmt.Stringer interface.
 func (c *NATSConfig) String() string {
 	return fmt.Sprintf("%#v", c)
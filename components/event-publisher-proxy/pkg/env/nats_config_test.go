@@ -0,0 +1,54 @@
+package env
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+)
+
+func TestNATSConfigNewCleaner(t *testing.T) {
+	t.Parallel()
+
+	l, err := logger.New("json", "info")
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	cfg := NATSConfig{CleanerStrategy: cleaner.StrategyJetStream}
+	c, err := cfg.NewCleaner(l)
+	if err != nil {
+		t.Fatalf("failed to create cleaner: %v", err)
+	}
+	if got, _ := c.CleanEventType("order.cre>ated.v1"); got != "order.created.v1" {
+		t.Errorf("cleaned event type is wrong want: %s but got: %s", "order.created.v1", got)
+	}
+
+	cfg = NATSConfig{
+		CleanerStrategy:          cleaner.StrategyJetStream,
+		CleanerAllowedCharacters: "a-z0-9_",
+		CleanerReplacement:       "_",
+	}
+	c, err = cfg.NewCleaner(l)
+	if err != nil {
+		t.Fatalf("failed to create cleaner: %v", err)
+	}
+	if got, _ := c.CleanEventType("order.cre-ated.v1"); got != "order.cre_ated.v1" {
+		t.Errorf("cleaned event type is wrong want: %s but got: %s", "order.cre_ated.v1", got)
+	}
+
+	cfg = NATSConfig{CleanerStrategy: cleaner.StrategyJetStream, CleanerHashCollisions: true}
+	c, err = cfg.NewCleaner(l)
+	if err != nil {
+		t.Fatalf("failed to create cleaner: %v", err)
+	}
+	if got, _ := c.CleanEventType("order.created>.v1"); got != "order.created.v1-9137da14" {
+		t.Errorf("cleaned event type is wrong want: %s but got: %s", "order.created.v1-9137da14", got)
+	}
+
+	cfg = NATSConfig{CleanerStrategy: "unknown"}
+	if _, err := cfg.NewCleaner(l); !errors.Is(err, cleaner.ErrUnknownStrategy) {
+		t.Errorf("unknown strategy error is wrong want: %v but got: %v", cleaner.ErrUnknownStrategy, err)
+	}
+}
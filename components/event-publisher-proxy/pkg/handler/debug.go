@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/auth"
+)
+
+const (
+	// typeQueryParam and sourceQueryParam filter the events streamed by the debug endpoint.
+	// Both can be repeated, and a trailing `*` matches any suffix.
+	typeQueryParam   = "type"
+	sourceQueryParam = "source"
+
+	// tailSourcePlaceholder is the source of the event built to find the published type of a requested type,
+	// replaced by a wildcard if no single source is requested.
+	tailSourcePlaceholder = "tailsourceplaceholder"
+)
+
+// EventTailer tails the events published to the backend.
+type EventTailer interface {
+	// Tail calls fn for every event published to the backend with the given event type until the given context
+	// is done. The event type is the type of the published events and may contain `*` tokens, all events are
+	// tailed if it is empty.
+	Tail(ctx context.Context, eventType string, fn func(*cev2event.Event)) error
+}
+
+// tailEvents streams the published events matching the type and source query parameters as Server-Sent Events,
+// until the client disconnects. Clients only receive the events the authorization policy allows them to publish.
+func (h *Handler) tailEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		if e := writeResponse(w, http.StatusInternalServerError, []byte("streaming is not supported")); e != nil {
+			h.namedLogger().Error(e)
+		}
+		return
+	}
+
+	// the number of concurrent streams is limited, since each of them reads the published events from the backend
+	if h.tails != nil {
+		select {
+		case h.tails <- struct{}{}:
+			defer func() { <-h.tails }()
+		default:
+			if e := writeResponse(w, http.StatusTooManyRequests, []byte("too many event streams")); e != nil {
+				h.namedLogger().Error(e)
+			}
+			return
+		}
+	}
+
+	filter := auth.Rule{
+		Types:   r.URL.Query()[typeQueryParam],
+		Sources: r.URL.Query()[sourceQueryParam],
+	}
+	identity, _ := auth.IdentityFromContext(r.Context())
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-h.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	err := h.EventTailer.Tail(ctx, h.publishedEventType(filter), func(event *cev2event.Event) {
+		eventType := h.originalEventType(event)
+		if !filter.Matches(eventType, event.Source()) {
+			return
+		}
+		if h.authPolicy != nil && !h.authPolicy.Allowed(identity, eventType, event.Source()) {
+			return
+		}
+		if err := writeServerSentEvent(w, eventType, event); err != nil {
+			h.namedLogger().Debugw("Failed to stream event", "error", err)
+			cancel()
+			return
+		}
+		flusher.Flush()
+	})
+	if err != nil {
+		h.namedLogger().Errorw("Failed to tail events", "error", err)
+	}
+}
+
+// publishedEventType returns the type the events matching the given filter are published with, so that the backend
+// only reads these events. The source is a `*` token unless a single source is requested. It returns an empty type,
+// matching all the events, unless a single type without a wildcard is requested.
+func (h *Handler) publishedEventType(filter auth.Rule) string {
+	if len(filter.Types) != 1 || strings.HasSuffix(filter.Types[0], "*") {
+		return ""
+	}
+	eventType := filter.Types[0]
+	// the events of the Subscription v1alpha1 API are published with the cleaned type, which includes the source
+	if strings.HasPrefix(eventType, h.OldEventTypePrefix) {
+		cleanType, err := h.eventTypeCleaner.Clean(eventType)
+		if err != nil {
+			return ""
+		}
+		return cleanType
+	}
+
+	source := tailSourcePlaceholder
+	if len(filter.Sources) == 1 && !strings.HasSuffix(filter.Sources[0], "*") {
+		source = filter.Sources[0]
+	}
+	event := cev2event.New()
+	event.SetID(tailSourcePlaceholder)
+	event.SetType(eventType)
+	event.SetSource(source)
+	built, err := h.ceBuilder.Build(event)
+	if err != nil {
+		return ""
+	}
+	// the built type is the prefix, the cleaned source, and the cleaned type
+	return strings.Replace(built.Type(), "."+tailSourcePlaceholder+".", ".*.", 1)
+}
+
+// writeServerSentEvent writes the given event in the Server-Sent Events format, with the event type as event name.
+func writeServerSentEvent(w http.ResponseWriter, eventType string, event *cev2event.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID(), eventType, data)
+	return err
+}
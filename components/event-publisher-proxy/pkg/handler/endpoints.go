@@ -4,4 +4,12 @@ const (
 	PublishEndpoint           = "/publish"
 	LegacyEndpointPattern     = "/{application}/v1/events"
 	SubscribedEndpointPattern = "/{application}/v1/events/subscribed"
+	DebugEventsEndpoint       = "/debug/events"
+
+	retryAfterHeader      = "Retry-After"
+	wwwAuthenticateHeader = "WWW-Authenticate"
+	contentEncodingHeader = "Content-Encoding"
+	contentLengthHeader   = "Content-Length"
+
+	requestBodyTooLargeErrorMessage = "http: request body too large"
 )
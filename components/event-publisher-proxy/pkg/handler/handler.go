@@ -1,9 +1,15 @@
 package handler
 
 import (
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +20,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/cloudevent/validation"
 	"go.uber.org/zap"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
@@ -21,13 +28,17 @@ import (
 	cev2event "github.com/cloudevents/sdk-go/v2/event"
 	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
 
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/auth"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/cloudevents/builder"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/cloudevents/eventtype"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/idempotency"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/legacy"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/options"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/ratelimit"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/receiver"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender/common"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/subscribed"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/tracing"
 )
@@ -65,6 +76,32 @@ type Handler struct {
 	router             *mux.Router
 	activeBackend      env.ActiveBackend
 	OldEventTypePrefix string
+	// rateLimiter limits the publish requests of each client, nil if the rate limit is disabled
+	rateLimiter *ratelimit.Limiter
+	// authenticator authenticates the clients of the publish endpoints, nil if the authentication is disabled
+	authenticator auth.Authenticator
+	// authPolicy authorizes the authenticated clients to publish events, nil if all the clients are authorized
+	authPolicy *auth.Policy
+	// validator checks the published events against the CloudEvents specification, nil if they are not checked
+	validator *validation.Validator
+	// idempotencyCache replays the responses of the retried requests, nil if the idempotency keys are ignored
+	idempotencyCache *idempotency.Cache
+	// AsyncSender buffers the events to be published in the background, nil if the events are published synchronously
+	AsyncSender sender.AsyncSender
+	// MigrationSender sends the events to the migration backend in addition to the Sender, nil if no migration is active
+	MigrationSender sender.GenericSender
+	// MigrationBuilder builds the events sent by the MigrationSender
+	MigrationBuilder builder.CloudEventBuilder
+	// migrationQueue buffers the events to be sent by the MigrationSender, nil if no migration is active
+	migrationQueue chan migrationJob
+	// StatusReporter reports the detailed status of the backend, nil if the backend does not support it
+	StatusReporter health.StatusReporter
+	// EventTailer streams the published events to the debug endpoint, nil if the backend does not support it
+	EventTailer EventTailer
+	// tails limits the number of concurrent streams of the debug endpoint, nil if they are not limited
+	tails chan struct{}
+	// done is closed when the Handler shuts down
+	done <-chan struct{}
 }
 
 // New returns a new HTTP Handler instance.
@@ -97,18 +134,56 @@ func New(receiver *receiver.HTTPMessageReceiver, sender sender.GenericSender, he
 func (h *Handler) setupMux() {
 	router := mux.NewRouter()
 	router.Use(h.collector.MetricsMiddleware())
-	router.HandleFunc(PublishEndpoint, h.maxBytes(h.publishCloudEvents)).Methods(http.MethodPost)
-	router.HandleFunc(LegacyEndpointPattern, h.maxBytes(h.publishLegacyEventsAsCE)).Methods(http.MethodPost)
+	if h.Options.RateLimit > 0 {
+		h.rateLimiter = ratelimit.NewLimiter(h.Options.RateLimit, h.Options.RateLimitBurst)
+	}
+	if h.Options.IdempotencyWindow > 0 {
+		h.idempotencyCache = idempotency.NewCache(h.Options.IdempotencyWindow, h.Options.IdempotencyMaxKeys)
+	}
+	router.HandleFunc(PublishEndpoint,
+		h.rateLimit(h.authenticate(h.maxBytes(h.idempotent(h.decompress(h.publishCloudEvents)))))).
+		Methods(http.MethodPost)
+	router.HandleFunc(LegacyEndpointPattern,
+		h.rateLimit(h.authenticate(h.maxBytes(h.idempotent(h.decompress(h.publishLegacyEventsAsCE)))))).
+		Methods(http.MethodPost)
 	router.HandleFunc(
 		SubscribedEndpointPattern,
 		h.maxBytes(h.SubscribedProcessor.ExtractEventsFromSubscriptions)).Methods(http.MethodGet)
+	if h.Options.AsyncPublish && h.AsyncSender == nil {
+		h.namedLogger().Info("The async publish is not supported by the active backend")
+	}
+	if h.Options.DebugEndpoint {
+		if h.EventTailer != nil {
+			h.tails = make(chan struct{}, h.Options.DebugMaxStreams)
+			router.HandleFunc(DebugEventsEndpoint,
+				h.rateLimit(h.authenticate(h.tailEvents))).Methods(http.MethodGet)
+		} else {
+			h.namedLogger().Info("The debug endpoint is not supported by the active backend")
+		}
+	}
 	router.HandleFunc(health.ReadinessURI, h.maxBytes(h.HealthChecker.ReadinessCheck))
 	router.HandleFunc(health.LivenessURI, h.maxBytes(h.HealthChecker.LivenessCheck))
+	if h.StatusReporter != nil {
+		// the status exposes the stream and the publish errors, so it is protected like the publish endpoints
+		router.HandleFunc(health.StatusURI,
+			h.rateLimit(h.authenticate(h.maxBytes(h.StatusReporter.StatusCheck)))).Methods(http.MethodGet)
+	}
 	h.router = router
 }
 
 // Start starts the Handler with the given context.
 func (h *Handler) Start(ctx context.Context) error {
+	authenticator, authPolicy, err := auth.New(h.Options.Auth)
+	if err != nil {
+		return err
+	}
+	h.authenticator, h.authPolicy = authenticator, authPolicy
+	h.validator, err = validation.NewValidator(h.Options.ValidationPolicy, h.Options.MaxEventSize)
+	if err != nil {
+		return err
+	}
+	h.done = ctx.Done()
+	h.startMigration(ctx)
 	h.setupMux()
 	return h.Receiver.StartListen(ctx, h.router, h.Logger)
 }
@@ -122,6 +197,113 @@ func (h *Handler) maxBytes(f http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// decompress decompresses the gzip or deflate encoded request bodies. The decompressed body is limited
+// to the maximum request size as well, so that small compressed requests cannot expand to huge events.
+// Requests with other encodings are rejected with 415 Unsupported Media Type.
+func (h *Handler) decompress(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(strings.TrimSpace(r.Header.Get(contentEncodingHeader)))
+		var decompressed io.ReadCloser
+		var err error
+		switch encoding {
+		case "", "identity":
+			f(w, r)
+			return
+		case "gzip":
+			decompressed, err = gzip.NewReader(r.Body)
+		case "deflate":
+			// the deflate content encoding is the zlib format wrapping the deflate compressed data
+			decompressed, err = zlib.NewReader(r.Body)
+		default:
+			msg := fmt.Sprintf("unsupported content encoding: %s", encoding)
+			if e := writeResponse(w, http.StatusUnsupportedMediaType, []byte(msg)); e != nil {
+				h.namedLogger().Error(e)
+			}
+			return
+		}
+		if err != nil {
+			if e := writeResponse(w, http.StatusBadRequest, []byte(err.Error())); e != nil {
+				h.namedLogger().Error(e)
+			}
+			return
+		}
+		defer func() { _ = decompressed.Close() }()
+
+		r.Body = http.MaxBytesReader(w, readCloser{Reader: decompressed, Closer: r.Body}, h.Options.MaxRequestSize)
+		r.Header.Del(contentEncodingHeader)
+		r.Header.Del(contentLengthHeader)
+		r.ContentLength = -1
+		f(w, r)
+	}
+}
+
+// readCloser reads from a Reader and closes a Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// rateLimit rejects the requests of the clients exceeding the rate limit with 429 Too Many Requests
+// and the Retry-After header set to the number of seconds to wait.
+func (h *Handler) rateLimit(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.rateLimiter == nil {
+			f(w, r)
+			return
+		}
+		key := ratelimit.RequestKey(h.Options.RateLimitKey, h.Options.Auth.TrustForwardedClientCert, r)
+		allowed, retryAfter := h.rateLimiter.Allow(key, time.Now())
+		if allowed {
+			f(w, r)
+			return
+		}
+		h.collector.RecordRateLimited(key)
+		h.namedLogger().Debugw("Rate limit exceeded", "client", key, "retry-after", retryAfter)
+		w.Header().Set(retryAfterHeader, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		if e := writeResponse(w, http.StatusTooManyRequests, []byte("rate limit exceeded")); e != nil {
+			h.namedLogger().Error(e)
+		}
+	}
+}
+
+// authenticate rejects the requests of unauthenticated clients with 401 Unauthorized
+// and passes the identity of the authenticated clients in the request context.
+func (h *Handler) authenticate(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.authenticator == nil {
+			f(w, r)
+			return
+		}
+		identity, err := h.authenticator.Authenticate(r)
+		if err != nil {
+			h.namedLogger().Debugw("Authentication failed", "error", err)
+			if h.Options.Auth.Mode == auth.ModeJWT {
+				w.Header().Set(wwwAuthenticateHeader, "Bearer")
+			}
+			if e := writeResponse(w, http.StatusUnauthorized, []byte(err.Error())); e != nil {
+				h.namedLogger().Error(e)
+			}
+			return
+		}
+		f(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+	}
+}
+
+// authorize returns an error if the client of the given context is not allowed to publish the given event.
+func (h *Handler) authorize(ctx context.Context, event *cev2event.Event) error {
+	if h.authPolicy == nil {
+		return nil
+	}
+	identity, _ := auth.IdentityFromContext(ctx)
+	eventType := h.originalEventType(event)
+	if h.authPolicy.Allowed(identity, eventType, event.Source()) {
+		return nil
+	}
+	h.eventLogger(event).Debugw("Publishing not allowed", "identity", identity,
+		"type", eventType, "source", event.Source())
+	return common.ErrPublishNotAllowed
+}
+
 // handleSendEventAndRecordMetricsLegacy handles the publishing of metrics.
 // It writes to the user request if any error occurs.
 // Otherwise, returns the result.
@@ -129,7 +311,7 @@ func (h *Handler) handleSendEventAndRecordMetricsLegacy(
 	writer http.ResponseWriter, request *http.Request, event *cev2event.Event) error {
 	err := h.sendEventAndRecordMetrics(request.Context(), event, h.Sender.URL(), request.Header)
 	if err != nil {
-		h.namedLogger().Error(err)
+		h.eventLogger(event).Error(err)
 		httpStatus := http.StatusInternalServerError
 		var pubErr sender.PublishError
 		if errors.As(err, &pubErr) {
@@ -151,6 +333,14 @@ func (h *Handler) handlePublishLegacyEvent(w http.ResponseWriter, r *http.Reques
 		legacy.WriteJSONResponse(w, legacy.ErrorResponse(http.StatusInternalServerError, err))
 		return nil, nil
 	}
+	if err = h.validate(ceEvent); err != nil {
+		legacy.WriteJSONResponse(w, legacy.ErrorResponseBadRequest(err.Error()))
+		return nil, err
+	}
+
+	// the correlation ID is set before the event is copied for the migration backend
+	tracing.AddCorrelationIDToCEExtensions(r.Header, ceEvent)
+	migrationEvent := h.prepareMigration(ceEvent)
 
 	// build a new cloud event instance as per specifications per backend
 	event, err := h.ceBuilder.Build(*ceEvent)
@@ -160,6 +350,7 @@ func (h *Handler) handlePublishLegacyEvent(w http.ResponseWriter, r *http.Reques
 	}
 
 	err = h.handleSendEventAndRecordMetricsLegacy(w, r, event)
+	h.publishToMigrationBackend(r.Context(), migrationEvent, err)
 	if err != nil {
 		return nil, err
 	}
@@ -192,6 +383,9 @@ func (h *Handler) publishLegacyEventsAsCE(w http.ResponseWriter, r *http.Request
 	// extract publish data from request
 	publishRequestData, errResp, _ := h.LegacyTransformer.ExtractPublishRequestData(r)
 	if errResp != nil {
+		if errResp.Error != nil && errResp.Error.Status == http.StatusRequestEntityTooLarge {
+			h.collector.RecordPayloadTooLarge()
+		}
 		legacy.WriteJSONResponse(w, errResp)
 		return
 	}
@@ -220,7 +414,7 @@ func (h *Handler) publishLegacyEventsAsCE(w http.ResponseWriter, r *http.Request
 
 	// return success response to user
 	// change response as per old error codes
-	h.LegacyTransformer.WriteCEResponseAsLegacyResponse(w, http.StatusNoContent, publishedEvent, "")
+	h.LegacyTransformer.WriteCEResponseAsLegacyResponse(w, h.publishedStatus(), publishedEvent, "")
 }
 
 // publishCloudEvents validates an incoming cloudevent and dispatches it using
@@ -231,17 +425,33 @@ func (h *Handler) publishCloudEvents(w http.ResponseWriter, r *http.Request) {
 	event, err := extractCloudEventFromRequest(r)
 	if err != nil {
 		h.namedLogger().With().Error(err)
-		e := writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
+		httpStatus := http.StatusBadRequest
+		if isRequestBodyTooLarge(err) {
+			h.collector.RecordPayloadTooLarge()
+			httpStatus = http.StatusRequestEntityTooLarge
+		}
+		e := writeResponse(w, httpStatus, []byte(err.Error()))
 		if e != nil {
 			h.namedLogger().Error(e)
 		}
 		return
 	}
+	if err = h.validate(event); err != nil {
+		if e := writeResponse(w, http.StatusBadRequest, []byte(err.Error())); e != nil {
+			h.namedLogger().Error(e)
+		}
+		return
+	}
 
 	eventTypeOriginal := event.Type()
+	// the correlation ID is set before the event is copied for the migration backend
+	tracing.AddCorrelationIDToCEExtensions(r.Header, event)
 
+	var migrationEvent *cev2event.Event
 	//nolint:nestif // it will be improved when v1alpha1 is deprecated.
 	if !strings.HasPrefix(eventTypeOriginal, h.OldEventTypePrefix) {
+		migrationEvent = h.prepareMigration(event)
+
 		// build a new cloud event instance as per specifications per backend
 		event, err = h.ceBuilder.Build(*event)
 		if err != nil {
@@ -252,9 +462,10 @@ func (h *Handler) publishCloudEvents(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	} else {
+		// the events of the Subscription v1alpha1 API are not published to the migration backend
 		eventTypeClean, err := h.eventTypeCleaner.Clean(eventTypeOriginal)
 		if err != nil {
-			h.namedLogger().Error(err)
+			h.eventLogger(event).Error(err)
 			e := writeResponse(w, http.StatusBadRequest, []byte(err.Error()))
 			if e != nil {
 				h.namedLogger().Error(e)
@@ -265,6 +476,7 @@ func (h *Handler) publishCloudEvents(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.sendEventAndRecordMetrics(ctx, event, h.Sender.URL(), r.Header)
+	h.publishToMigrationBackend(ctx, migrationEvent, err)
 	if err != nil {
 		httpStatus := http.StatusInternalServerError
 		var pubErr sender.PublishError
@@ -272,15 +484,21 @@ func (h *Handler) publishCloudEvents(w http.ResponseWriter, r *http.Request) {
 			httpStatus = pubErr.Code()
 		}
 		w.WriteHeader(httpStatus)
-		h.namedLogger().With().Error(err)
+		h.eventLogger(event).Error(err)
 		return
 	}
-	err = writeResponse(w, http.StatusNoContent, []byte(""))
+	err = writeResponse(w, h.publishedStatus(), []byte(""))
 	if err != nil {
-		h.namedLogger().With().Error(err)
+		h.eventLogger(event).Error(err)
 	}
 }
 
+// isRequestBodyTooLarge reports whether the given error was caused by a request body exceeding the maximum size.
+func isRequestBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr) || strings.Contains(err.Error(), requestBodyTooLargeErrorMessage)
+}
+
 // extractCloudEventFromRequest converts an incoming CloudEvent request to an Event.
 func extractCloudEventFromRequest(r *http.Request) (*cev2event.Event, error) {
 	message := cev2http.NewMessageFromHttpRequest(r)
@@ -298,41 +516,102 @@ func extractCloudEventFromRequest(r *http.Request) (*cev2event.Event, error) {
 	return event, nil
 }
 
+// validate checks the published event against the CloudEvents specification and records its violations.
+// It returns an error listing them if the validation policy rejects the event.
+func (h *Handler) validate(event *cev2event.Event) error {
+	if h.validator == nil {
+		return nil
+	}
+	violations, err := h.validator.Validate(event)
+	for _, violation := range violations {
+		h.collector.RecordInvalidEvent(violation.Rule, err != nil)
+	}
+	if err != nil {
+		h.eventLogger(event).Debugw("Rejected an invalid CloudEvent", "error", err)
+	} else if len(violations) > 0 {
+		h.eventLogger(event).Debugw("Accepted a CloudEvent violating the CloudEvents specification",
+			"violations", (&validation.Error{Violations: violations}).Error())
+	}
+	return err
+}
+
 // sendEventAndRecordMetrics dispatches an Event and records metrics based on dispatch success.
 func (h *Handler) sendEventAndRecordMetrics(ctx context.Context, event *cev2event.Event,
 	host string, header http.Header) error {
 	ctx, cancel := context.WithTimeout(ctx, h.RequestTimeout)
 	defer cancel()
 	h.applyDefaults(ctx, event)
+	tracing.AddCorrelationIDToCEExtensions(header, event)
+	if err := h.authorize(ctx, event); err != nil {
+		return err
+	}
 	tracing.AddTracingContextToCEExtensions(header, event)
+	if key := header.Get(idempotency.KeyHeader); key != "" && h.idempotencyCache != nil {
+		event.SetExtension(idempotency.KeyExtension, key)
+	}
+	if h.AsyncSender != nil {
+		return h.sendEventAsyncAndRecordMetrics(event)
+	}
 	start := time.Now()
 	err := h.Sender.Send(ctx, event)
 	duration := time.Since(start)
+	eventType := h.originalEventType(event)
 	if err != nil {
 		var pubErr sender.PublishError
 		code := 500
 		if errors.As(err, &pubErr) {
 			code = pubErr.Code()
 		}
+		if code == http.StatusRequestEntityTooLarge {
+			h.collector.RecordPayloadTooLarge()
+		}
 		h.collector.RecordBackendLatency(duration, code, host)
+		h.collector.RecordEventTypePublish(eventType, len(event.Data()), duration, code)
 		return err
 	}
-	originalEventType := event.Type()
+	h.eventLogger(event).Debugw("Published the CloudEvent", "id", event.ID(), "type", eventType)
+	h.collector.RecordEventType(eventType, event.Source(), http.StatusNoContent)
+	h.collector.RecordBackendLatency(duration, http.StatusNoContent, host)
+	h.collector.RecordEventTypePublish(eventType, len(event.Data()), duration, http.StatusNoContent)
+	return nil
+}
+
+// sendEventAsyncAndRecordMetrics buffers an Event to be published in the background
+// and records metrics based on buffering success.
+func (h *Handler) sendEventAsyncAndRecordMetrics(event *cev2event.Event) error {
+	if err := h.AsyncSender.SendAsync(event); err != nil {
+		return err
+	}
+	eventType := h.originalEventType(event)
+	h.eventLogger(event).Debugw("Buffered the CloudEvent to be published", "id", event.ID(), "type", eventType)
+	h.collector.RecordEventType(eventType, event.Source(), http.StatusAccepted)
+	return nil
+}
+
+// publishedStatus returns the status code of the successfully published requests,
+// 202 Accepted if the events are published in the background.
+func (h *Handler) publishedStatus() int {
+	if h.AsyncSender != nil {
+		return http.StatusAccepted
+	}
+	return http.StatusNoContent
+}
+
+// originalEventType returns the event type as published by the client.
+func (h *Handler) originalEventType(event *cev2event.Event) string {
 	originalTypeHeader, ok := event.Extensions()[builder.OriginalTypeHeaderName]
 	if !ok {
-		h.namedLogger().With().Debugw("event header doesn't exist", "header",
+		h.eventLogger(event).Debugw("event header doesn't exist", "header",
 			builder.OriginalTypeHeaderName)
-	} else {
-		originalEventType, ok = originalTypeHeader.(string)
-		if !ok {
-			h.namedLogger().With().Warnw("failed to convert event original event type extension value to string",
-				builder.OriginalTypeHeaderName, originalTypeHeader)
-			originalEventType = event.Type()
-		}
+		return event.Type()
 	}
-	h.collector.RecordEventType(originalEventType, event.Source(), http.StatusNoContent)
-	h.collector.RecordBackendLatency(duration, http.StatusNoContent, host)
-	return nil
+	originalEventType, ok := originalTypeHeader.(string)
+	if !ok {
+		h.eventLogger(event).Warnw("failed to convert event original event type extension value to string",
+			builder.OriginalTypeHeaderName, originalTypeHeader)
+		return event.Type()
+	}
+	return originalEventType
 }
 
 // writeResponse writes the HTTP response given the status code and response body.
@@ -357,3 +636,8 @@ func (h *Handler) applyDefaults(ctx context.Context, event *cev2event.Event) {
 func (h *Handler) namedLogger() *zap.SugaredLogger {
 	return h.Logger.WithContext().Named(h.Name)
 }
+
+// eventLogger returns the named logger decorated with the correlation ID of the given event.
+func (h *Handler) eventLogger(event *cev2event.Event) *zap.SugaredLogger {
+	return h.namedLogger().With(tracing.CorrelationIDLogKey, tracing.CorrelationID(event))
+}
@@ -2,14 +2,22 @@
 package handler
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/legacy"
@@ -20,19 +28,25 @@ import (
 
 	eclogger "github.com/kyma-project/kyma/components/eventing-controller/logger"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/cloudevent/validation"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/application/applicationtest"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/application/fake"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/auth"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/cloudevents/builder"
 
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/cloudevents/eventtype"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/cloudevents/eventtype/eventtypetest"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/idempotency"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics/histogram/mocks"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics/metricstest"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/options"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/ratelimit"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/subscribed"
 	testingutils "github.com/kyma-project/kyma/components/event-publisher-proxy/testing"
 )
 
@@ -74,7 +88,9 @@ func TestHandler_publishCloudEvents(t *testing.T) {
 			},
 			wantStatus: 204,
 			wantTEF: metricstest.MakeTEFBackendDuration(204, "FOO") +
-				metricstest.MakeTEFEventTypePublished(204, "/default/sap.kyma/id", ""),
+				metricstest.MakeTEFEventTypePublished(204, "/default/sap.kyma/id", "") +
+				metricstest.MakeTEFEventTypePublishDuration(204, "") +
+				metricstest.MakeTEFEventTypePayloadSize("", 13),
 		},
 		{
 			name: "Publish binary Cloudevent for Subscription v1alpha1",
@@ -91,7 +107,9 @@ func TestHandler_publishCloudEvents(t *testing.T) {
 			},
 			wantStatus: 204,
 			wantTEF: metricstest.MakeTEFBackendDuration(204, "FOO") +
-				metricstest.MakeTEFEventTypePublished(204, "/default/sap.kyma/id", ""),
+				metricstest.MakeTEFEventTypePublished(204, "/default/sap.kyma/id", "") +
+				metricstest.MakeTEFEventTypePublishDuration(204, "") +
+				metricstest.MakeTEFEventTypePayloadSize("", 13),
 		},
 		{
 			name: "Publish structured Cloudevent",
@@ -108,7 +126,9 @@ func TestHandler_publishCloudEvents(t *testing.T) {
 			},
 			wantStatus: 204,
 			wantTEF: metricstest.MakeTEFBackendDuration(204, "FOO") +
-				metricstest.MakeTEFEventTypePublished(204, "testapp1023", "order.created.v1"),
+				metricstest.MakeTEFEventTypePublished(204, "testapp1023", "order.created.v1") +
+				metricstest.MakeTEFEventTypePublishDuration(204, "order.created.v1") +
+				metricstest.MakeTEFEventTypePayloadSize("order.created.v1", 20),
 		},
 		{
 			name: "Publish binary Cloudevent",
@@ -125,7 +145,9 @@ func TestHandler_publishCloudEvents(t *testing.T) {
 			},
 			wantStatus: 204,
 			wantTEF: metricstest.MakeTEFBackendDuration(204, "FOO") +
-				metricstest.MakeTEFEventTypePublished(204, "testapp1023", "order.created.v1"),
+				metricstest.MakeTEFEventTypePublished(204, "testapp1023", "order.created.v1") +
+				metricstest.MakeTEFEventTypePublishDuration(204, "order.created.v1") +
+				metricstest.MakeTEFEventTypePayloadSize("order.created.v1", 13),
 		},
 		{
 			name: "Publish invalid structured CloudEvent",
@@ -165,7 +187,9 @@ func TestHandler_publishCloudEvents(t *testing.T) {
 				request: CreateValidBinaryRequest(t),
 			},
 			wantStatus: 500,
-			wantTEF:    metricstest.MakeTEFBackendDuration(500, ""),
+			wantTEF: metricstest.MakeTEFBackendDuration(500, "") +
+				metricstest.MakeTEFEventTypePublishDuration(500, "order.created.v1") +
+				metricstest.MakeTEFEventTypePayloadSize("order.created.v1", 13),
 		},
 		{
 			name: "Publish binary CloudEvent but backend is full",
@@ -180,7 +204,9 @@ func TestHandler_publishCloudEvents(t *testing.T) {
 				request: CreateValidBinaryRequest(t),
 			},
 			wantStatus: 507,
-			wantTEF:    metricstest.MakeTEFBackendDuration(507, ""),
+			wantTEF: metricstest.MakeTEFBackendDuration(507, "") +
+				metricstest.MakeTEFEventTypePublishDuration(507, "order.created.v1") +
+				metricstest.MakeTEFEventTypePayloadSize("order.created.v1", 13),
 		},
 	}
 	for _, tt := range tests {
@@ -221,6 +247,559 @@ func TestHandler_publishCloudEvents(t *testing.T) {
 	}
 }
 
+func TestHandler_publishCloudEvents_Async(t *testing.T) {
+	const bucketsFunc = "Buckets"
+	latency := new(mocks.BucketsProvider)
+	latency.On(bucketsFunc).Return(nil)
+	latency.Test(t)
+	tests := []struct {
+		name             string
+		givenAsyncSender *AsyncSenderStub
+		wantStatus       int
+		wantTEF          string
+	}{
+		{
+			name:             "Accept Cloudevent buffered to be published",
+			givenAsyncSender: &AsyncSenderStub{},
+			wantStatus:       http.StatusAccepted,
+			wantTEF:          metricstest.MakeTEFEventTypePublished(202, "testapp1023", "order.created.v1"),
+		},
+		{
+			name:             "Reject Cloudevent if the buffer is full",
+			givenAsyncSender: &AsyncSenderStub{Err: common.BackendPublishError{HTTPCode: http.StatusServiceUnavailable}},
+			wantStatus:       http.StatusServiceUnavailable,
+			wantTEF:          "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			logger, err := eclogger.New("text", "debug")
+			assert.NoError(t, err)
+
+			app := applicationtest.NewApplication("appName1", nil)
+			appLister := fake.NewApplicationListerOrDie(context.Background(), app)
+
+			ceBuilder := builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), appLister, logger)
+
+			h := &Handler{
+				Sender:             &GenericSenderStub{},
+				AsyncSender:        tt.givenAsyncSender,
+				Logger:             logger,
+				collector:          metrics.NewCollector(latency),
+				eventTypeCleaner:   &eventtypetest.CleanerStub{},
+				ceBuilder:          ceBuilder,
+				Options:            &options.Options{},
+				OldEventTypePrefix: testingutils.OldEventTypePrefix,
+			}
+			writer := httptest.NewRecorder()
+
+			// when
+			h.publishCloudEvents(writer, CreateValidBinaryRequest(t))
+
+			// then
+			assert.Equal(t, tt.wantStatus, writer.Result().StatusCode)
+			if tt.wantStatus == http.StatusAccepted {
+				assert.NotNil(t, tt.givenAsyncSender.ReceivedEvent)
+			}
+			metricstest.EnsureMetricMatchesTextExpositionFormat(t, h.collector, tt.wantTEF)
+		})
+	}
+}
+
+func TestHandler_publishCloudEvents_Migration(t *testing.T) {
+	const bucketsFunc = "Buckets"
+	latency := new(mocks.BucketsProvider)
+	latency.On(bucketsFunc).Return(nil)
+	latency.Test(t)
+	tests := []struct {
+		name                 string
+		givenSenderErr       sender.PublishError
+		givenMigrationErr    sender.PublishError
+		wantStatus           int
+		wantMigrationTEF     string
+		wantMigrationEventID string
+	}{
+		{
+			name:             "Publish Cloudevent to both backends",
+			wantStatus:       http.StatusNoContent,
+			wantMigrationTEF: metricstest.MakeTEFMigrationPublish(metrics.MigrationBothSucceeded),
+		},
+		{
+			name:             "Publish Cloudevent to the migration backend only",
+			givenSenderErr:   common.ErrInternalBackendError,
+			wantStatus:       http.StatusInternalServerError,
+			wantMigrationTEF: metricstest.MakeTEFMigrationPublish(metrics.MigrationActiveFailed),
+		},
+		{
+			name:              "Publish Cloudevent to the active backend only",
+			givenMigrationErr: common.ErrInternalBackendError,
+			wantStatus:        http.StatusNoContent,
+			wantMigrationTEF:  metricstest.MakeTEFMigrationPublish(metrics.MigrationMigrationFailed),
+		},
+		{
+			name:              "Fail to publish Cloudevent to both backends",
+			givenSenderErr:    common.ErrInternalBackendError,
+			givenMigrationErr: common.ErrInternalBackendError,
+			wantStatus:        http.StatusInternalServerError,
+			wantMigrationTEF:  metricstest.MakeTEFMigrationPublish(metrics.MigrationBothFailed),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			logger, err := eclogger.New("text", "debug")
+			require.NoError(t, err)
+
+			app := applicationtest.NewApplication("appName1", nil)
+			appLister := fake.NewApplicationListerOrDie(context.Background(), app)
+
+			activeSender := &GenericSenderStub{Err: tt.givenSenderErr}
+			migrationSender := &GenericSenderStub{Err: tt.givenMigrationErr}
+			h := &Handler{
+				Sender:          activeSender,
+				MigrationSender: migrationSender,
+				MigrationBuilder: builder.NewEventMeshBuilder("prefix", "/default/ns",
+					cleaner.NewEventMeshCleaner(logger), appLister, logger),
+				Logger:             logger,
+				collector:          metrics.NewCollector(latency),
+				eventTypeCleaner:   &eventtypetest.CleanerStub{},
+				ceBuilder:          builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), appLister, logger),
+				Options:            &options.Options{MigrationQueueSize: 1, MigrationWorkers: 1},
+				OldEventTypePrefix: testingutils.OldEventTypePrefix,
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			h.startMigration(ctx)
+			writer := httptest.NewRecorder()
+
+			// when
+			h.publishCloudEvents(writer, CreateValidBinaryRequest(t))
+
+			// then
+			assert.Equal(t, tt.wantStatus, writer.Result().StatusCode)
+			require.Eventually(t, func() bool {
+				return testutil.CollectAndCount(h.collector, metrics.MigrationPublishKey) == 1
+			}, time.Second, 10*time.Millisecond)
+			metricstest.EnsureMetricMatchesTextExpositionFormat(t, h.collector, tt.wantMigrationTEF,
+				metrics.MigrationPublishKey)
+
+			const wantDedupID = "testapp1023/8945ec08-256b-11eb-9928-acde48001122"
+			require.NotNil(t, activeSender.ReceivedEvent)
+			assert.Equal(t, wantDedupID, activeSender.ReceivedEvent.Extensions()[dedupIDExtension])
+			assert.Equal(t, "prefix.testapp1023.order.created.v1", activeSender.ReceivedEvent.Type())
+			require.NotNil(t, migrationSender.ReceivedEvent)
+			assert.Equal(t, wantDedupID, migrationSender.ReceivedEvent.Extensions()[dedupIDExtension])
+			assert.Equal(t, "/default/ns", migrationSender.ReceivedEvent.Source())
+		})
+	}
+}
+
+func TestHandler_publishCloudEvents_Migration_NotAllowed(t *testing.T) {
+	// given
+	const bucketsFunc = "Buckets"
+	latency := new(mocks.BucketsProvider)
+	latency.On(bucketsFunc).Return(nil)
+	latency.Test(t)
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+
+	app := applicationtest.NewApplication("appName1", nil)
+	appLister := fake.NewApplicationListerOrDie(context.Background(), app)
+
+	migrationSender := &GenericSenderStub{}
+	h := &Handler{
+		Sender:          &GenericSenderStub{},
+		MigrationSender: migrationSender,
+		MigrationBuilder: builder.NewEventMeshBuilder("prefix", "/default/ns",
+			cleaner.NewEventMeshCleaner(logger), appLister, logger),
+		Logger:             logger,
+		collector:          metrics.NewCollector(latency),
+		eventTypeCleaner:   &eventtypetest.CleanerStub{},
+		ceBuilder:          builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), appLister, logger),
+		Options:            &options.Options{},
+		OldEventTypePrefix: testingutils.OldEventTypePrefix,
+		authPolicy:         &auth.Policy{},
+	}
+	writer := httptest.NewRecorder()
+
+	// when
+	h.publishCloudEvents(writer, CreateValidBinaryRequest(t))
+
+	// then
+	assert.Equal(t, http.StatusForbidden, writer.Result().StatusCode)
+	assert.Nil(t, migrationSender.ReceivedEvent)
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, h.collector, "", metrics.MigrationPublishKey)
+}
+
+func TestHandler_publishCloudEvents_Migration_QueueFull(t *testing.T) {
+	// given
+	const bucketsFunc = "Buckets"
+	latency := new(mocks.BucketsProvider)
+	latency.On(bucketsFunc).Return(nil)
+	latency.Test(t)
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+
+	app := applicationtest.NewApplication("appName1", nil)
+	appLister := fake.NewApplicationListerOrDie(context.Background(), app)
+
+	activeSender, migrationSender := &GenericSenderStub{}, &GenericSenderStub{}
+	h := &Handler{
+		Sender:          activeSender,
+		MigrationSender: migrationSender,
+		MigrationBuilder: builder.NewEventMeshBuilder("prefix", "/default/ns",
+			cleaner.NewEventMeshCleaner(logger), appLister, logger),
+		Logger:             logger,
+		collector:          metrics.NewCollector(latency),
+		eventTypeCleaner:   &eventtypetest.CleanerStub{},
+		ceBuilder:          builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), appLister, logger),
+		Options:            &options.Options{},
+		OldEventTypePrefix: testingutils.OldEventTypePrefix,
+		// a queue without capacity and workers is always full
+		migrationQueue: make(chan migrationJob),
+	}
+	writer := httptest.NewRecorder()
+
+	// when
+	h.publishCloudEvents(writer, CreateValidBinaryRequest(t))
+
+	// then the event is published to the active backend only, and counted as dropped
+	assert.Equal(t, http.StatusNoContent, writer.Result().StatusCode)
+	assert.NotNil(t, activeSender.ReceivedEvent)
+	assert.Nil(t, migrationSender.ReceivedEvent)
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, h.collector,
+		metricstest.MakeTEFMigrationPublish(metrics.MigrationDropped), metrics.MigrationPublishKey)
+}
+
+func TestHandler_publishCloudEvents_Migration_V1Alpha1(t *testing.T) {
+	// given
+	const bucketsFunc = "Buckets"
+	latency := new(mocks.BucketsProvider)
+	latency.On(bucketsFunc).Return(nil)
+	latency.Test(t)
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+
+	app := applicationtest.NewApplication("appName1", nil)
+	appLister := fake.NewApplicationListerOrDie(context.Background(), app)
+
+	activeSender := &GenericSenderStub{}
+	h := &Handler{
+		Sender:          activeSender,
+		MigrationSender: &GenericSenderStub{},
+		MigrationBuilder: builder.NewEventMeshBuilder("prefix", "/default/ns",
+			cleaner.NewEventMeshCleaner(logger), appLister, logger),
+		Logger:             logger,
+		collector:          metrics.NewCollector(latency),
+		eventTypeCleaner:   &eventtypetest.CleanerStub{},
+		ceBuilder:          builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), appLister, logger),
+		Options:            &options.Options{},
+		OldEventTypePrefix: testingutils.OldEventTypePrefix,
+		// a queue without workers keeps the queued events
+		migrationQueue: make(chan migrationJob, 1),
+	}
+	writer := httptest.NewRecorder()
+
+	// when an event of the Subscription v1alpha1 API is published
+	h.publishCloudEvents(writer, CreateValidStructuredRequestV1Alpha1(t))
+
+	// then it is excluded from the migration
+	assert.Equal(t, http.StatusNoContent, writer.Result().StatusCode)
+	assert.NotNil(t, activeSender.ReceivedEvent)
+	assert.Empty(t, h.migrationQueue)
+	metricstest.EnsureMetricMatchesTextExpositionFormat(t, h.collector, "", metrics.MigrationPublishKey)
+}
+
+func TestHandler_idempotent(t *testing.T) {
+	const bucketsFunc = "Buckets"
+	latency := new(mocks.BucketsProvider)
+	latency.On(bucketsFunc).Return(nil)
+	latency.Test(t)
+	tests := []struct {
+		name            string
+		givenSenderErr  sender.PublishError
+		givenRetryBody  string
+		wantStatus      int
+		wantRetryStatus int
+		wantReplayed    bool
+	}{
+		{
+			name:            "Replay the response of a published Cloudevent",
+			wantStatus:      http.StatusNoContent,
+			wantRetryStatus: http.StatusNoContent,
+			wantReplayed:    true,
+		},
+		{
+			name:            "Publish the Cloudevent again after a backend error",
+			givenSenderErr:  common.ErrInternalBackendError,
+			wantStatus:      http.StatusInternalServerError,
+			wantRetryStatus: http.StatusInternalServerError,
+			wantReplayed:    false,
+		},
+		{
+			name:            "Reject the reused key for another payload",
+			givenRetryBody:  `{"foo":"baz"}`,
+			wantStatus:      http.StatusNoContent,
+			wantRetryStatus: http.StatusUnprocessableEntity,
+			wantReplayed:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			logger, err := eclogger.New("text", "debug")
+			require.NoError(t, err)
+
+			app := applicationtest.NewApplication("appName1", nil)
+			appLister := fake.NewApplicationListerOrDie(context.Background(), app)
+
+			activeSender := &GenericSenderStub{Err: tt.givenSenderErr}
+			h := &Handler{
+				Sender:             activeSender,
+				Logger:             logger,
+				collector:          metrics.NewCollector(latency),
+				eventTypeCleaner:   &eventtypetest.CleanerStub{},
+				ceBuilder:          builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), appLister, logger),
+				Options:            &options.Options{},
+				OldEventTypePrefix: testingutils.OldEventTypePrefix,
+				idempotencyCache:   idempotency.NewCache(time.Minute, 10),
+			}
+			publish := func(body string) *httptest.ResponseRecorder {
+				request := CreateValidBinaryRequest(t)
+				if body != "" {
+					request.Body = io.NopCloser(strings.NewReader(body))
+				}
+				request.Header.Set(idempotency.KeyHeader, "key1")
+				writer := httptest.NewRecorder()
+				h.idempotent(h.publishCloudEvents)(writer, request)
+				return writer
+			}
+
+			// when
+			first := publish("")
+
+			// then
+			assert.Equal(t, tt.wantStatus, first.Code)
+			require.NotNil(t, activeSender.ReceivedEvent)
+			assert.Equal(t, "key1", activeSender.ReceivedEvent.Extensions()[idempotency.KeyExtension])
+
+			// when the request is retried
+			activeSender.ReceivedEvent = nil
+			retried := publish(tt.givenRetryBody)
+
+			// then
+			assert.Equal(t, tt.wantRetryStatus, retried.Code)
+			switch {
+			case tt.wantReplayed:
+				assert.Equal(t, "true", retried.Header().Get(idempotency.ReplayedHeader))
+				assert.Nil(t, activeSender.ReceivedEvent)
+			case tt.wantRetryStatus == http.StatusUnprocessableEntity:
+				assert.Empty(t, retried.Header().Get(idempotency.ReplayedHeader))
+				assert.Nil(t, activeSender.ReceivedEvent)
+			default:
+				assert.Empty(t, retried.Header().Get(idempotency.ReplayedHeader))
+				assert.NotNil(t, activeSender.ReceivedEvent)
+			}
+		})
+	}
+}
+
+func TestHandler_publishCloudEvents_PayloadTooLarge(t *testing.T) {
+	// given
+	const bucketsFunc = "Buckets"
+	latency := new(mocks.BucketsProvider)
+	latency.On(bucketsFunc).Return(nil)
+	latency.Test(t)
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+	h := &Handler{
+		Sender:    &GenericSenderStub{},
+		Logger:    logger,
+		collector: metrics.NewCollector(latency),
+		Options:   &options.Options{MaxRequestSize: 10},
+	}
+	writer := httptest.NewRecorder()
+
+	// when
+	h.maxBytes(h.publishCloudEvents)(writer, CreateValidStructuredRequest(t))
+
+	// then
+	assert.Equal(t, http.StatusRequestEntityTooLarge, writer.Result().StatusCode)
+	metricstest.EnsureMetricPayloadTooLarge(t, h.collector, 1)
+}
+
+func TestHandler_publishCloudEvents_Validation(t *testing.T) {
+	const bucketsFunc = "Buckets"
+	latency := new(mocks.BucketsProvider)
+	latency.On(bucketsFunc).Return(nil)
+	latency.Test(t)
+	tests := []struct {
+		name       string
+		policy     string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Accept a Cloudevent violating a recommended rule with the lenient policy",
+			policy:     validation.PolicyLenient,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Reject a Cloudevent violating a recommended rule with the strict policy",
+			policy:     validation.PolicyStrict,
+			wantStatus: http.StatusBadRequest,
+			wantBody: "invalid CloudEvent: averyverylongextension: " +
+				"extension attribute names SHOULD NOT exceed 20 characters",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			logger, err := eclogger.New("text", "debug")
+			require.NoError(t, err)
+			validator, err := validation.NewValidator(tt.policy, validation.DefaultMaxEventSize)
+			require.NoError(t, err)
+
+			app := applicationtest.NewApplication("appName1", nil)
+			appLister := fake.NewApplicationListerOrDie(context.Background(), app)
+
+			activeSender := &GenericSenderStub{}
+			h := &Handler{
+				Sender:             activeSender,
+				Logger:             logger,
+				collector:          metrics.NewCollector(latency),
+				eventTypeCleaner:   &eventtypetest.CleanerStub{},
+				ceBuilder:          builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), appLister, logger),
+				Options:            &options.Options{},
+				OldEventTypePrefix: testingutils.OldEventTypePrefix,
+				validator:          validator,
+			}
+			request := CreateValidBinaryRequest(t)
+			request.Header.Add("Ce-Averyverylongextension", "value")
+			writer := httptest.NewRecorder()
+
+			// when
+			h.publishCloudEvents(writer, request)
+
+			// then
+			assert.Equal(t, tt.wantStatus, writer.Code)
+			assert.Equal(t, tt.wantBody, writer.Body.String())
+			assert.Equal(t, tt.wantStatus == http.StatusNoContent, activeSender.ReceivedEvent != nil)
+			metricstest.EnsureMetricInvalidEvents(t, h.collector, 1)
+		})
+	}
+}
+
+func TestHandler_decompress(t *testing.T) {
+	const maxRequestSize = 65536
+	structuredEvent := `{"specversion":"1.0","type":"order.created.v1","source":"testapp1023",` +
+		`"id":"8945ec08-256b-11eb-9928-acde48001122","data":{"foo":"%s"}}`
+	gzipped := func(body string) []byte {
+		var b bytes.Buffer
+		w := gzip.NewWriter(&b)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return b.Bytes()
+	}
+	deflated := func(body string) []byte {
+		var b bytes.Buffer
+		w := zlib.NewWriter(&b)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return b.Bytes()
+	}
+	rawDeflated := func(body string) []byte {
+		var b bytes.Buffer
+		w, err := flate.NewWriter(&b, flate.BestCompression)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(body))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return b.Bytes()
+	}
+
+	tests := []struct {
+		name          string
+		givenEncoding string
+		givenBody     []byte
+		wantStatus    int
+	}{
+		{
+			name:          "Publish gzip encoded Cloudevent",
+			givenEncoding: "gzip",
+			givenBody:     gzipped(fmt.Sprintf(structuredEvent, "bar")),
+			wantStatus:    http.StatusNoContent,
+		},
+		{
+			name:          "Publish deflate encoded Cloudevent",
+			givenEncoding: "deflate",
+			givenBody:     deflated(fmt.Sprintf(structuredEvent, "bar")),
+			wantStatus:    http.StatusNoContent,
+		},
+		{
+			name:          "Reject gzip encoded Cloudevent exceeding the maximum size once decompressed",
+			givenEncoding: "gzip",
+			givenBody:     gzipped(fmt.Sprintf(structuredEvent, strings.Repeat("a", 10*maxRequestSize))),
+			wantStatus:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "Reject invalid gzip encoded Cloudevent",
+			givenEncoding: "gzip",
+			givenBody:     []byte(fmt.Sprintf(structuredEvent, "bar")),
+			wantStatus:    http.StatusBadRequest,
+		},
+		{
+			name:          "Reject raw deflate encoded Cloudevent without the zlib wrapper",
+			givenEncoding: "deflate",
+			givenBody:     rawDeflated(fmt.Sprintf(structuredEvent, "bar")),
+			wantStatus:    http.StatusBadRequest,
+		},
+		{
+			name:          "Reject unsupported content encoding",
+			givenEncoding: "br",
+			givenBody:     []byte(fmt.Sprintf(structuredEvent, "bar")),
+			wantStatus:    http.StatusUnsupportedMediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			const bucketsFunc = "Buckets"
+			latency := new(mocks.BucketsProvider)
+			latency.On(bucketsFunc).Return(nil)
+			latency.Test(t)
+			logger, err := eclogger.New("text", "debug")
+			require.NoError(t, err)
+
+			app := applicationtest.NewApplication("appName1", nil)
+			appLister := fake.NewApplicationListerOrDie(context.Background(), app)
+
+			h := &Handler{
+				Sender:             &GenericSenderStub{},
+				Logger:             logger,
+				collector:          metrics.NewCollector(latency),
+				eventTypeCleaner:   &eventtypetest.CleanerStub{},
+				ceBuilder:          builder.NewGenericBuilder("prefix", cleaner.NewJetStreamCleaner(logger), appLister, logger),
+				Options:            &options.Options{MaxRequestSize: maxRequestSize},
+				OldEventTypePrefix: testingutils.OldEventTypePrefix,
+			}
+			request := httptest.NewRequest(http.MethodPost, "http://localhost/publish", bytes.NewReader(tt.givenBody))
+			request.Header.Add("Content-Type", "application/cloudevents+json")
+			request.Header.Add("Content-Encoding", tt.givenEncoding)
+			writer := httptest.NewRecorder()
+
+			// when
+			h.maxBytes(h.decompress(h.publishCloudEvents))(writer, request)
+
+			// then
+			assert.Equal(t, tt.wantStatus, writer.Result().StatusCode)
+		})
+	}
+}
+
 func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 	// define common given variables
 	appLister := NewApplicationListerOrDie(context.Background(), "testapp")
@@ -248,7 +827,9 @@ func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 			givenRequest:           legacytest.ValidLegacyRequestOrDie(t, "v1", "testapp", "object.created"),
 			wantHTTPStatus:         http.StatusOK,
 			wantTEF: metricstest.MakeTEFBackendDuration(204, "FOO") +
-				metricstest.MakeTEFEventTypePublished(204, "testapp", "object.created.v1"),
+				metricstest.MakeTEFEventTypePublished(204, "testapp", "object.created.v1") +
+				metricstest.MakeTEFEventTypePublishDuration(204, "object.created.v1") +
+				metricstest.MakeTEFEventTypePayloadSize("object.created.v1", 24),
 		},
 		{
 			name: "Send valid legacy event but cannot send to backend due to target not found (e.g. stream missing)",
@@ -260,7 +841,9 @@ func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 			givenCollector:         metrics.NewCollector(latency),
 			givenRequest:           legacytest.ValidLegacyRequestOrDie(t, "v1", "testapp", "object.created"),
 			wantHTTPStatus:         http.StatusNotFound,
-			wantTEF:                metricstest.MakeTEFBackendDuration(404, "FOO"),
+			wantTEF: metricstest.MakeTEFBackendDuration(404, "FOO") +
+				metricstest.MakeTEFEventTypePublishDuration(404, "object.created.v1") +
+				metricstest.MakeTEFEventTypePayloadSize("object.created.v1", 24),
 		},
 		{
 			name: "Send valid legacy event but cannot send to backend due to full storage",
@@ -272,7 +855,9 @@ func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 			givenCollector:         metrics.NewCollector(latency),
 			givenRequest:           legacytest.ValidLegacyRequestOrDie(t, "v1", "testapp", "object.created"),
 			wantHTTPStatus:         507,
-			wantTEF:                metricstest.MakeTEFBackendDuration(507, "FOO"),
+			wantTEF: metricstest.MakeTEFBackendDuration(507, "FOO") +
+				metricstest.MakeTEFEventTypePublishDuration(507, "object.created.v1") +
+				metricstest.MakeTEFEventTypePayloadSize("object.created.v1", 24),
 		},
 		{
 			name: "Send valid legacy event but cannot send to backend",
@@ -284,7 +869,9 @@ func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 			givenCollector:         metrics.NewCollector(latency),
 			givenRequest:           legacytest.ValidLegacyRequestOrDie(t, "v1", "testapp", "object.created"),
 			wantHTTPStatus:         500,
-			wantTEF:                metricstest.MakeTEFBackendDuration(500, "FOO"),
+			wantTEF: metricstest.MakeTEFBackendDuration(500, "FOO") +
+				metricstest.MakeTEFEventTypePublishDuration(500, "object.created.v1") +
+				metricstest.MakeTEFEventTypePayloadSize("object.created.v1", 24),
 		},
 		{
 			name: "Send invalid legacy event",
@@ -341,6 +928,285 @@ func TestHandler_publishLegacyEventsAsCE(t *testing.T) {
 }
 
 // CreateValidStructuredRequestV1Alpha2 creates a structured cloudevent as http request.
+func TestHandler_rateLimit(t *testing.T) {
+	// given
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+	buckets := new(mocks.BucketsProvider)
+	buckets.On("Buckets").Return(nil)
+	h := &Handler{
+		Logger:      logger,
+		collector:   metrics.NewCollector(buckets),
+		Options:     &options.Options{RateLimitKey: ratelimit.KeyApplication},
+		rateLimiter: ratelimit.NewLimiter(1, 1),
+	}
+	publish := h.rateLimit(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	newRequest := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "http://localhost/commerce/v1/events", nil)
+	}
+
+	// when
+	writer := httptest.NewRecorder()
+	publish(writer, newRequest())
+
+	// then
+	require.Equal(t, http.StatusNoContent, writer.Result().StatusCode)
+	metricstest.EnsureMetricRateLimited(t, h.collector, 0)
+
+	// when the rate limit is exceeded
+	writer = httptest.NewRecorder()
+	publish(writer, newRequest())
+
+	// then
+	require.Equal(t, http.StatusTooManyRequests, writer.Result().StatusCode)
+	require.Equal(t, "1", writer.Result().Header.Get(retryAfterHeader))
+	metricstest.EnsureMetricRateLimited(t, h.collector, 1)
+}
+
+func TestHandler_authenticate(t *testing.T) {
+	// given
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+	h := &Handler{
+		Logger:        logger,
+		Options:       &options.Options{Auth: auth.Config{Mode: auth.ModeMTLS, TrustForwardedClientCert: true}},
+		authenticator: auth.MTLSAuthenticator{TrustForwardedClientCert: true},
+	}
+	var gotIdentity string
+	publish := h.authenticate(func(w http.ResponseWriter, r *http.Request) {
+		gotIdentity, _ = auth.IdentityFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// when
+	writer := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "http://localhost/publish", nil)
+	request.Header.Set("X-Forwarded-Client-Cert", "URI=spiffe://cluster.local/ns/shop/sa/orders")
+	publish(writer, request)
+
+	// then
+	require.Equal(t, http.StatusNoContent, writer.Result().StatusCode)
+	require.Equal(t, "spiffe://cluster.local/ns/shop/sa/orders", gotIdentity)
+
+	// when the client has no certificate
+	writer = httptest.NewRecorder()
+	publish(writer, httptest.NewRequest(http.MethodPost, "http://localhost/publish", nil))
+
+	// then
+	require.Equal(t, http.StatusUnauthorized, writer.Result().StatusCode)
+}
+
+func TestHandler_StatusAuthenticated(t *testing.T) {
+	// given
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+	const bucketsFunc = "Buckets"
+	latency := new(mocks.BucketsProvider)
+	latency.On(bucketsFunc).Return(nil)
+	latency.Test(t)
+	h := &Handler{
+		Logger:              logger,
+		Options:             &options.Options{Auth: auth.Config{Mode: auth.ModeMTLS, TrustForwardedClientCert: true}},
+		authenticator:       auth.MTLSAuthenticator{TrustForwardedClientCert: true},
+		collector:           metrics.NewCollector(latency),
+		SubscribedProcessor: &subscribed.Processor{},
+		HealthChecker:       health.NewChecker(),
+		StatusReporter:      statusReporterStub{},
+	}
+	h.setupMux()
+
+	// when the client has no certificate
+	writer := httptest.NewRecorder()
+	h.router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "http://localhost"+health.StatusURI, nil))
+
+	// then
+	require.Equal(t, http.StatusUnauthorized, writer.Result().StatusCode)
+
+	// when the client is authenticated
+	writer = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost"+health.StatusURI, nil)
+	request.Header.Set("X-Forwarded-Client-Cert", "URI=spiffe://cluster.local/ns/shop/sa/orders")
+	h.router.ServeHTTP(writer, request)
+
+	// then
+	require.Equal(t, http.StatusOK, writer.Result().StatusCode)
+
+	// when, then the probes stay unauthenticated
+	writer = httptest.NewRecorder()
+	h.router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "http://localhost"+health.ReadinessURI, nil))
+	require.Equal(t, http.StatusOK, writer.Result().StatusCode)
+}
+
+// statusReporterStub reports an empty status.
+type statusReporterStub struct{}
+
+func (statusReporterStub) StatusCheck(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestHandler_authorize(t *testing.T) {
+	// given
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+	h := &Handler{
+		Logger: logger,
+		authPolicy: &auth.Policy{Rules: []auth.Rule{
+			{Identity: "orders", Types: []string{"order.created.v1"}},
+		}},
+	}
+	event := cev2event.New()
+	event.SetType("prefix.order.created.v1")
+	event.SetSource("commerce")
+	event.SetExtension(builder.OriginalTypeHeaderName, "order.created.v1")
+
+	// when, then the original event type is authorized
+	require.NoError(t, h.authorize(auth.WithIdentity(context.Background(), "orders"), &event))
+
+	// when, then
+	err = h.authorize(auth.WithIdentity(context.Background(), "marketing"), &event)
+	var pubErr sender.PublishError
+	require.ErrorAs(t, err, &pubErr)
+	require.Equal(t, http.StatusForbidden, pubErr.Code())
+}
+
+func TestHandler_tailEvents(t *testing.T) {
+	// given
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+	newEvent := func(id, eventType, source string) *cev2event.Event {
+		event := cev2event.New()
+		event.SetID(id)
+		event.SetType("prefix." + eventType)
+		event.SetSource(source)
+		event.SetExtension(builder.OriginalTypeHeaderName, eventType)
+		return &event
+	}
+	h := &Handler{
+		Logger: logger,
+		authPolicy: &auth.Policy{Rules: []auth.Rule{
+			{Identity: "orders", Types: []string{"order.*"}},
+		}},
+		EventTailer: &EventTailerStub{Events: []*cev2event.Event{
+			newEvent("1", "order.created.v1", "commerce"),
+			newEvent("2", "order.created.v1", "marketing"),
+			newEvent("3", "order.updated.v1", "commerce"),
+			newEvent("4", "customer.created.v1", "commerce"),
+		}},
+	}
+	request := httptest.NewRequest(http.MethodGet, "http://localhost/debug/events?source=commerce", nil)
+	request = request.WithContext(auth.WithIdentity(request.Context(), "orders"))
+	writer := httptest.NewRecorder()
+
+	// when
+	h.tailEvents(writer, request)
+
+	// then only the events matching the filter and allowed by the policy are streamed
+	require.Equal(t, http.StatusOK, writer.Code)
+	require.Equal(t, "text/event-stream", writer.Header().Get("Content-Type"))
+	body := writer.Body.String()
+	require.Contains(t, body, "id: 1\nevent: order.created.v1\ndata: {")
+	require.Contains(t, body, "id: 3\nevent: order.updated.v1\ndata: {")
+	require.NotContains(t, body, "id: 2")
+	require.NotContains(t, body, "id: 4")
+}
+
+func TestHandler_tailEvents_MaxStreams(t *testing.T) {
+	// given
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+	h := &Handler{
+		Logger:      logger,
+		EventTailer: &EventTailerStub{blockUntilEnd: true},
+		tails:       make(chan struct{}, 1),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	streaming := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.tailEvents(streaming, httptest.NewRequest(http.MethodGet, "http://localhost/debug/events", nil).
+			WithContext(ctx))
+	}()
+	require.Eventually(t, func() bool { return len(h.tails) == 1 }, time.Second, 10*time.Millisecond)
+
+	// when another client streams the events
+	writer := httptest.NewRecorder()
+	h.tailEvents(writer, httptest.NewRequest(http.MethodGet, "http://localhost/debug/events", nil))
+
+	// then it is rejected
+	require.Equal(t, http.StatusTooManyRequests, writer.Code)
+
+	// when the first client disconnects
+	cancel()
+	<-done
+
+	// then its stream is released
+	require.Empty(t, h.tails)
+}
+
+func TestHandler_publishedEventType(t *testing.T) {
+	logger, err := eclogger.New("text", "debug")
+	require.NoError(t, err)
+	h := &Handler{
+		Logger:             logger,
+		ceBuilder:          builder.NewGenericBuilder("kyma", cleaner.NewJetStreamCleaner(logger), nil, logger),
+		eventTypeCleaner:   eventtypetest.CleanerStub{CleanType: "sap.kyma.custom.commerce.order.created.v1"},
+		OldEventTypePrefix: testingutils.OldEventTypePrefix,
+	}
+	testCases := []struct {
+		name         string
+		givenTypes   []string
+		givenSources []string
+		wantType     string
+	}{
+		{
+			name:     "all events without a type",
+			wantType: "",
+		},
+		{
+			name:         "type and source",
+			givenTypes:   []string{"order.created.v1"},
+			givenSources: []string{"commerce"},
+			wantType:     "kyma.commerce.order.created.v1",
+		},
+		{
+			name:       "type of any source",
+			givenTypes: []string{"order.created.v1"},
+			wantType:   "kyma.*.order.created.v1",
+		},
+		{
+			name:         "type of several sources",
+			givenTypes:   []string{"order.created.v1"},
+			givenSources: []string{"commerce", "marketing"},
+			wantType:     "kyma.*.order.created.v1",
+		},
+		{
+			name:       "type with a wildcard",
+			givenTypes: []string{"order.*"},
+			wantType:   "",
+		},
+		{
+			name:       "several types",
+			givenTypes: []string{"order.created.v1", "order.updated.v1"},
+			wantType:   "",
+		},
+		{
+			name:       "type of the Subscription v1alpha1 API",
+			givenTypes: []string{"sap.kyma.custom.commerce.order.created.v1"},
+			wantType:   "sap.kyma.custom.commerce.order.created.v1",
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc
+		t.Run(testCase.name, func(t *testing.T) {
+			filter := auth.Rule{Types: testCase.givenTypes, Sources: testCase.givenSources}
+			require.Equal(t, testCase.wantType, h.publishedEventType(filter))
+		})
+	}
+}
+
 func CreateValidStructuredRequest(t *testing.T) *http.Request {
 	t.Helper()
 	reader := strings.NewReader(`{
@@ -403,3 +1269,31 @@ func CreateInvalidBinaryRequest(t *testing.T) *http.Request {
 	req.Header.Add("Ce-ID", "8945ec08-256b-11eb-9928-acde48001122")
 	return req
 }
+
+type EventTailerStub struct {
+	Events        []*cev2event.Event
+	ReceivedType  string
+	blockUntilEnd bool
+}
+
+func (s *EventTailerStub) Tail(ctx context.Context, eventType string, fn func(*cev2event.Event)) error {
+	s.ReceivedType = eventType
+	if s.blockUntilEnd {
+		<-ctx.Done()
+		return nil
+	}
+	for _, event := range s.Events {
+		fn(event)
+	}
+	return nil
+}
+
+type AsyncSenderStub struct {
+	Err           sender.PublishError
+	ReceivedEvent *cev2event.Event
+}
+
+func (s *AsyncSenderStub) SendAsync(event *cev2event.Event) sender.PublishError {
+	s.ReceivedEvent = event
+	return s.Err
+}
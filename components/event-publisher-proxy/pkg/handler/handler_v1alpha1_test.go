@@ -203,7 +203,9 @@ func TestHandler_publishCloudEvents_v1alpha1(t *testing.T) {
 			},
 			wantStatus: 204,
 			wantTEF: metricstest.MakeTEFBackendDuration(204, "FOO") +
-				metricstest.MakeTEFEventTypePublished(204, "/default/sap.kyma/id", ""),
+				metricstest.MakeTEFEventTypePublished(204, "/default/sap.kyma/id", "") +
+				metricstest.MakeTEFEventTypePublishDuration(204, "") +
+				metricstest.MakeTEFEventTypePayloadSize("", 13),
 		},
 		{
 			name: "Publish binary Cloudevent",
@@ -220,7 +222,9 @@ func TestHandler_publishCloudEvents_v1alpha1(t *testing.T) {
 			},
 			wantStatus: 204,
 			wantTEF: metricstest.MakeTEFBackendDuration(204, "FOO") +
-				metricstest.MakeTEFEventTypePublished(204, "/default/sap.kyma/id", ""),
+				metricstest.MakeTEFEventTypePublished(204, "/default/sap.kyma/id", "") +
+				metricstest.MakeTEFEventTypePublishDuration(204, "") +
+				metricstest.MakeTEFEventTypePayloadSize("", 13),
 		},
 		{
 			name: "Publish invalid structured CloudEvent",
@@ -277,7 +281,9 @@ func TestHandler_publishCloudEvents_v1alpha1(t *testing.T) {
 				request: CreateValidBinaryRequestV1Alpha1(t),
 			},
 			wantStatus: 500,
-			wantTEF:    metricstest.MakeTEFBackendDuration(500, ""),
+			wantTEF: metricstest.MakeTEFBackendDuration(500, "") +
+				metricstest.MakeTEFEventTypePublishDuration(500, "") +
+				metricstest.MakeTEFEventTypePayloadSize("", 13),
 		},
 		{
 			name: "Publish binary CloudEvent but backend is full",
@@ -292,7 +298,9 @@ func TestHandler_publishCloudEvents_v1alpha1(t *testing.T) {
 				request: CreateValidBinaryRequestV1Alpha1(t),
 			},
 			wantStatus: http.StatusInsufficientStorage,
-			wantTEF:    metricstest.MakeTEFBackendDuration(507, ""),
+			wantTEF: metricstest.MakeTEFBackendDuration(507, "") +
+				metricstest.MakeTEFEventTypePublishDuration(507, "") +
+				metricstest.MakeTEFEventTypePayloadSize("", 13),
 		},
 	}
 	for _, tt := range tests {
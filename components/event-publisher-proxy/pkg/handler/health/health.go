@@ -10,6 +10,8 @@ const (
 
 	// ReadinessURI is the endpoint URI used for readiness check.
 	ReadinessURI = "/readyz"
+	// StatusURI is the endpoint URI used for the detailed status of the backend.
+	StatusURI = "/status"
 
 	// StatusCodeHealthy is the status code which indicates a healthy state.
 	StatusCodeHealthy = http.StatusOK
@@ -23,6 +25,11 @@ type Checker interface {
 	LivenessCheck(w http.ResponseWriter, r *http.Request)
 }
 
+// StatusReporter reports the detailed status of the backend.
+type StatusReporter interface {
+	StatusCheck(w http.ResponseWriter, r *http.Request)
+}
+
 // ConfigurableChecker represents a health checker.
 type ConfigurableChecker struct {
 	livenessCheck  http.HandlerFunc
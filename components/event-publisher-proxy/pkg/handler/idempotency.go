@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/auth"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/idempotency"
+)
+
+// idempotent replays the response of the first request with an idempotency key to the retried requests of the same
+// client with the same key within the idempotency window, so that retried events are not published twice.
+// Responses with a 5XX or 429 status code are not replayed, so that the retried requests publish the event again.
+// Requests reusing a key for a different payload are rejected with 422 Unprocessable Entity.
+// The body is read to compare the payloads, so the handler must be wrapped by maxBytes.
+func (h *Handler) idempotent(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(idempotency.KeyHeader)
+		if h.idempotencyCache == nil || idempotencyKey == "" {
+			f(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		// the body is passed on as read, so that the handler responds to a failed read as without the key
+		r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+		if err != nil {
+			f(w, r)
+			return
+		}
+		identity, _ := auth.IdentityFromContext(r.Context())
+		key := strings.Join([]string{identity, r.URL.Path, idempotencyKey}, "\n")
+		fingerprint := payloadFingerprint(r.Header, body)
+		response, replayed, err := h.idempotencyCache.Do(r.Context(), key, fingerprint, time.Now(),
+			func() *idempotency.Response {
+				recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+				f(recorder, r)
+				if recorder.statusCode >= http.StatusInternalServerError ||
+					recorder.statusCode == http.StatusTooManyRequests {
+					return nil
+				}
+				return &idempotency.Response{
+					StatusCode: recorder.statusCode,
+					Header:     w.Header().Clone(),
+					Body:       recorder.body.Bytes(),
+				}
+			})
+		if errors.Is(err, idempotency.ErrKeyReused) {
+			if e := writeResponse(w, http.StatusUnprocessableEntity, []byte(err.Error())); e != nil {
+				h.namedLogger().Error(e)
+			}
+			return
+		}
+		if err != nil {
+			h.namedLogger().Debugw("Request canceled while waiting for the request in flight", "error", err)
+			return
+		}
+		if !replayed {
+			return
+		}
+		for name, values := range response.Header {
+			w.Header()[name] = values
+		}
+		w.Header().Set(idempotency.ReplayedHeader, "true")
+		if e := writeResponse(w, response.StatusCode, response.Body); e != nil {
+			h.namedLogger().Error(e)
+		}
+	}
+}
+
+// payloadFingerprint returns the SHA-256 hash of the given body and of the CloudEvent attributes
+// passed in the headers of binary-mode CloudEvents.
+func payloadFingerprint(header http.Header, body []byte) string {
+	hash := sha256.New()
+	names := make([]string, 0, len(header))
+	for name := range header {
+		if strings.HasPrefix(name, "Ce-") || name == "Content-Type" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hash.Write([]byte(name + ": " + strings.Join(header.Values(name), ",") + "\n"))
+	}
+	hash.Write(body)
+	return string(hash.Sum(nil))
+}
+
+// responseRecorder records the status code and the body written to a http.ResponseWriter.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender/common"
+)
+
+// dedupIDExtension is the CloudEvent extension identifying the events published to both the active and the migration
+// backend, so that the subscribers receiving an event from both backends can drop the duplicate.
+const dedupIDExtension = "dedupid"
+
+// prepareMigration sets the dedup extension on the given event before it is built for the active backend,
+// and returns a copy of it to be built for the migration backend. It returns nil if the migration is disabled.
+func (h *Handler) prepareMigration(event *cev2event.Event) *cev2event.Event {
+	if h.MigrationSender == nil {
+		return nil
+	}
+	event.SetExtension(dedupIDExtension, fmt.Sprintf("%s/%s", event.Source(), event.ID()))
+	migrationEvent := event.Clone()
+	return &migrationEvent
+}
+
+// migrationJob is an event queued to be published to the migration backend.
+type migrationJob struct {
+	// ctx is the context of the request, without its cancellation
+	ctx       context.Context
+	event     *cev2event.Event
+	activeErr error
+}
+
+// startMigration starts the workers publishing the queued events to the migration backend
+// until the given context is done. It does nothing if the migration is disabled.
+func (h *Handler) startMigration(ctx context.Context) {
+	if h.MigrationSender == nil {
+		return
+	}
+	h.migrationQueue = make(chan migrationJob, h.Options.MigrationQueueSize)
+	for i := 0; i < h.Options.MigrationWorkers; i++ {
+		go h.runMigrationWorker(ctx)
+	}
+}
+
+// publishToMigrationBackend queues the given event to be published to the migration backend in the background.
+// If the queue is full, the event is dropped and recorded as such.
+func (h *Handler) publishToMigrationBackend(ctx context.Context, event *cev2event.Event, activeErr error) {
+	if event == nil || errors.Is(activeErr, common.ErrPublishNotAllowed) {
+		return
+	}
+	// the request context is canceled once the response is written
+	job := migrationJob{ctx: context.WithoutCancel(ctx), event: event, activeErr: activeErr}
+	select {
+	case h.migrationQueue <- job:
+	default:
+		h.eventLogger(event).Warnw("Dropped event for the migration backend, the queue is full",
+			"id", event.ID(), "source", event.Source(), "type", event.Type())
+		h.collector.RecordMigrationPublish(metrics.MigrationDropped)
+	}
+}
+
+// runMigrationWorker publishes the queued events to the migration backend until the given context is done.
+func (h *Handler) runMigrationWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-h.migrationQueue:
+			h.sendToMigrationBackend(job)
+		}
+	}
+}
+
+// sendToMigrationBackend publishes the event of the given job to the migration backend,
+// and records whether the result diverged from the result of publishing it to the active backend.
+func (h *Handler) sendToMigrationBackend(job migrationJob) {
+	ctx, cancel := context.WithTimeout(job.ctx, h.RequestTimeout)
+	defer cancel()
+	migrationEvent, err := h.MigrationBuilder.Build(*job.event)
+	if err == nil {
+		err = h.MigrationSender.Send(ctx, migrationEvent)
+	}
+	if err != nil {
+		h.eventLogger(job.event).Warnw("Failed to publish event to the migration backend",
+			"id", job.event.ID(), "source", job.event.Source(), "type", job.event.Type(), "error", err)
+	}
+	h.collector.RecordMigrationPublish(migrationResult(job.activeErr, err))
+}
+
+// migrationResult returns the result of publishing an event to both the active and the migration backend.
+func migrationResult(activeErr, migrationErr error) string {
+	switch {
+	case activeErr == nil && migrationErr == nil:
+		return metrics.MigrationBothSucceeded
+	case activeErr != nil && migrationErr != nil:
+		return metrics.MigrationBothFailed
+	case activeErr != nil:
+		return metrics.MigrationActiveFailed
+	default:
+		return metrics.MigrationMigrationFailed
+	}
+}
@@ -0,0 +1,155 @@
+package idempotency
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// KeyHeader is the request header with the idempotency key chosen by the client for a published event.
+	KeyHeader = "Idempotency-Key"
+	// ReplayedHeader is set on the responses replayed for retried requests.
+	ReplayedHeader = "Idempotent-Replayed"
+	// KeyExtension is the CloudEvent extension passing the idempotency key to the backends,
+	// which drop the events published again with the same key.
+	KeyExtension = "idempotencykey"
+)
+
+// ErrKeyReused is returned for a request reusing the idempotency key of a request with a different payload.
+var ErrKeyReused = errors.New("the idempotency key was used for a different payload")
+
+// Response is the response of a request, replayed for the retried requests with the same idempotency key.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Cache caches the response of the first request of each idempotency key for a time window.
+// It keeps at most a maximum number of keys and evicts the oldest keys first.
+type Cache struct {
+	window     time.Duration
+	maxEntries int
+	mutex      sync.Mutex
+	entries    map[string]*entry
+	// order lists the keys of the entries from the oldest to the newest.
+	order     *list.List
+	nextSweep time.Time
+}
+
+// entry is the response of a key, nil while the first request is in flight.
+type entry struct {
+	done        chan struct{}
+	fingerprint string
+	response    *Response
+	expires     time.Time
+	element     *list.Element
+}
+
+// NewCache returns a Cache keeping the responses of at most maxEntries keys for the given time window.
+func NewCache(window time.Duration, maxEntries int) *Cache {
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+	return &Cache{
+		window:     window,
+		maxEntries: maxEntries,
+		entries:    map[string]*entry{},
+		order:      list.New(),
+	}
+}
+
+// Do returns the cached response of the given key and true, if there is one.
+// Otherwise, it calls fn and caches its response for the time window starting now, unless fn returns nil.
+// While a request of the same key is in flight, Do waits for its response until the given context is done.
+// The fingerprint identifies the payload of the request, and Do returns ErrKeyReused if it differs from the one
+// of the cached or in flight request.
+func (c *Cache) Do(ctx context.Context, key, fingerprint string, now time.Time,
+	fn func() *Response) (*Response, bool, error) {
+	for {
+		c.mutex.Lock()
+		c.sweep(now)
+		e, ok := c.entries[key]
+		if !ok || e.expired(now) {
+			if ok {
+				c.remove(key, e)
+			}
+			e = c.add(key, fingerprint)
+			c.mutex.Unlock()
+			return c.call(key, e, now, fn), false, nil
+		}
+		c.mutex.Unlock()
+		if e.fingerprint != fingerprint {
+			return nil, false, ErrKeyReused
+		}
+
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+		if e.response != nil {
+			return e.response, true, nil
+		}
+		// the response of the request in flight was not cached, so this request is not a retry of it
+	}
+}
+
+// call calls fn and completes the given entry with its response.
+func (c *Cache) call(key string, e *entry, now time.Time, fn func() *Response) (response *Response) {
+	defer func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		if response == nil {
+			if c.entries[key] == e {
+				c.remove(key, e)
+			}
+		} else {
+			e.response = response
+			e.expires = now.Add(c.window)
+		}
+		close(e.done)
+	}()
+	return fn()
+}
+
+// sweep removes the expired entries, at most once per time window.
+func (c *Cache) sweep(now time.Time) {
+	if now.Before(c.nextSweep) {
+		return
+	}
+	for key, e := range c.entries {
+		if e.expired(now) {
+			c.remove(key, e)
+		}
+	}
+	c.nextSweep = now.Add(c.window)
+}
+
+// add adds an entry for the given key, evicting the oldest entries if the cache is full.
+// The caller must hold the lock.
+func (c *Cache) add(key, fingerprint string) *entry {
+	for len(c.entries) >= c.maxEntries {
+		oldest := c.order.Front().Value.(string)
+		c.remove(oldest, c.entries[oldest])
+	}
+	e := &entry{done: make(chan struct{}), fingerprint: fingerprint}
+	e.element = c.order.PushBack(key)
+	c.entries[key] = e
+	return e
+}
+
+// remove removes the given entry of the given key. The caller must hold the lock.
+func (c *Cache) remove(key string, e *entry) {
+	c.order.Remove(e.element)
+	delete(c.entries, key)
+}
+
+// expired reports whether the response of the entry expired at the given time.
+func (e *entry) expired(now time.Time) bool {
+	return e.response != nil && !now.Before(e.expires)
+}
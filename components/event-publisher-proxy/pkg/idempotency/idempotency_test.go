@@ -0,0 +1,164 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_Do(t *testing.T) {
+	// given
+	cache := NewCache(time.Minute, 10)
+	now := time.Now()
+	calls := 0
+	fn := func() *Response {
+		calls++
+		return &Response{StatusCode: http.StatusNoContent}
+	}
+
+	// when the first request is sent
+	response, replayed, err := cache.Do(context.Background(), "key1", "payload", now, fn)
+
+	// then
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.Equal(t, 1, calls)
+
+	// when the request is retried within the window
+	response, replayed, err = cache.Do(context.Background(), "key1", "payload", now.Add(time.Second), fn)
+
+	// then the response is replayed
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.Equal(t, 1, calls)
+
+	// when another key is sent
+	_, replayed, err = cache.Do(context.Background(), "key2", "payload", now, fn)
+
+	// then
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, 2, calls)
+
+	// when the request is retried after the window
+	_, replayed, err = cache.Do(context.Background(), "key1", "payload", now.Add(time.Minute), fn)
+
+	// then it is handled again
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, 3, calls)
+}
+
+func TestCache_Do_NotCached(t *testing.T) {
+	// given
+	cache := NewCache(time.Minute, 10)
+	now := time.Now()
+	calls := 0
+	fn := func() *Response {
+		calls++
+		return nil
+	}
+
+	// when
+	_, _, err1 := cache.Do(context.Background(), "key", "payload", now, fn)
+	_, replayed, err2 := cache.Do(context.Background(), "key", "payload", now, fn)
+
+	// then the request is handled again
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	assert.False(t, replayed)
+	assert.Equal(t, 2, calls)
+}
+
+func TestCache_Do_InFlight(t *testing.T) {
+	// given
+	cache := NewCache(time.Minute, 10)
+	now := time.Now()
+	started, release := make(chan struct{}), make(chan struct{})
+	go func() {
+		_, _, _ = cache.Do(context.Background(), "key", "payload", now, func() *Response {
+			close(started)
+			<-release
+			return &Response{StatusCode: http.StatusAccepted}
+		})
+	}()
+	<-started
+
+	// when the request in flight is not done before the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err := cache.Do(ctx, "key", "payload", now, func() *Response {
+		t.Fatal("the request in flight must not be handled twice")
+		return nil
+	})
+
+	// then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// when the request in flight is done
+	close(release)
+	response, replayed, err := cache.Do(context.Background(), "key", "payload", now, func() *Response {
+		t.Fatal("the request in flight must not be handled twice")
+		return nil
+	})
+
+	// then its response is replayed
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, http.StatusAccepted, response.StatusCode)
+}
+
+func TestCache_Do_KeyReused(t *testing.T) {
+	// given
+	cache := NewCache(time.Minute, 10)
+	now := time.Now()
+	fn := func() *Response {
+		return &Response{StatusCode: http.StatusNoContent}
+	}
+	_, _, err := cache.Do(context.Background(), "key", "payload", now, fn)
+	require.NoError(t, err)
+
+	// when the key is sent with another payload
+	_, replayed, err := cache.Do(context.Background(), "key", "other payload", now, func() *Response {
+		t.Fatal("the request reusing the key must not be handled")
+		return nil
+	})
+
+	// then
+	assert.ErrorIs(t, err, ErrKeyReused)
+	assert.False(t, replayed)
+}
+
+func TestCache_Do_MaxEntries(t *testing.T) {
+	// given
+	cache := NewCache(time.Minute, 2)
+	now := time.Now()
+	calls := 0
+	fn := func() *Response {
+		calls++
+		return &Response{StatusCode: http.StatusNoContent}
+	}
+
+	// when more keys are sent than the cache keeps
+	for _, key := range []string{"key1", "key2", "key3"} {
+		_, _, err := cache.Do(context.Background(), key, "payload", now, fn)
+		require.NoError(t, err)
+	}
+
+	// then the oldest key is evicted
+	assert.Len(t, cache.entries, 2)
+	assert.Equal(t, 2, cache.order.Len())
+	_, replayed, err := cache.Do(context.Background(), "key3", "payload", now, fn)
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	_, replayed, err = cache.Do(context.Background(), "key1", "payload", now, fn)
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, 4, calls)
+}
@@ -38,6 +38,7 @@ type Transformer struct {
 	eventMeshNamespace string
 	eventTypePrefix    string
 	applicationLister  *application.Lister // applicationLister will be nil when disabled.
+	mappingRules       *MappingRules       // mappingRules will be nil when disabled.
 }
 
 func NewTransformer(bebNamespace string, eventTypePrefix string, applicationLister *application.Lister) *Transformer {
@@ -48,6 +49,12 @@ func NewTransformer(bebNamespace string, eventTypePrefix string, applicationList
 	}
 }
 
+// SetMappingRules sets the rules mapping the legacy event types to CloudEvent types and sources,
+// nil keeps the default mapping for all the legacy events.
+func (t *Transformer) SetMappingRules(rules *MappingRules) {
+	t.mappingRules = rules
+}
+
 func (t *Transformer) isApplicationListerEnabled() bool {
 	return t.applicationLister != nil
 }
@@ -181,7 +188,17 @@ func (t *Transformer) TransformPublishRequestToCloudEvent(publishRequestData *ap
 	eventTypeVersion := publishRequest.PublishrequestV1.EventTypeVersion
 
 	// set type by combining type and version (<type>.<version>) e.g. order.created.v1
-	event.SetType(fmt.Sprintf("%s.%s", eventName, eventTypeVersion))
+	eventType := fmt.Sprintf("%s.%s", eventName, eventTypeVersion)
+	if t.mappingRules != nil {
+		mappedType, mappedSource, ok, err := t.mappingRules.Map(source, eventName, eventTypeVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to map the legacy event type")
+		}
+		if ok {
+			eventType, source = mappedType, mappedSource
+		}
+	}
+	event.SetType(eventType)
 	event.SetSource(source)
 	event.SetExtension(eventTypeVersionExtensionKey, eventTypeVersion)
 	event.SetDataContentType(internal.ContentTypeApplicationJSON)
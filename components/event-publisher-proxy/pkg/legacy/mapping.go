@@ -0,0 +1,145 @@
+package legacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+const (
+	// mappingApplicationKey, mappingEventTypeKey and mappingVersionKey are the values of the legacy event
+	// available in the templates of the mapping rules, in addition to the named groups of the eventType regex.
+	mappingApplicationKey = "application"
+	mappingEventTypeKey   = "eventType"
+	mappingVersionKey     = "version"
+)
+
+// MappingRules map the legacy event types to CloudEvent types and sources.
+// The first matching rule is applied, the legacy events not matching any rule keep the default mapping.
+type MappingRules struct {
+	Rules []MappingRule `json:"rules"`
+}
+
+// MappingRule maps the legacy events of the matching applications and event types.
+type MappingRule struct {
+	// Application matches the application name, empty matches all. A trailing `*` matches any suffix.
+	Application string `json:"application,omitempty"`
+	// EventType is a regular expression matching the legacy event type, empty matches all.
+	// Its named groups are available in the templates.
+	EventType string `json:"eventType,omitempty"`
+	// Type is the template of the CloudEvent type.
+	Type string `json:"type"`
+	// Source is the template of the CloudEvent source, the application name if empty.
+	Source string `json:"source,omitempty"`
+
+	eventTypeRegexp *regexp.Regexp
+	typeTemplate    *template.Template
+	sourceTemplate  *template.Template
+}
+
+// LoadMappingRules reads the mapping rules from the given JSON file, nil if the path is empty.
+func LoadMappingRules(path string) (*MappingRules, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the legacy event mapping rules: %w", err)
+	}
+	rules := &MappingRules{}
+	if err = json.Unmarshal(data, rules); err != nil {
+		return nil, fmt.Errorf("failed to parse the legacy event mapping rules: %w", err)
+	}
+	for i := range rules.Rules {
+		if err = rules.Rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("invalid legacy event mapping rule %d: %w", i, err)
+		}
+	}
+	return rules, nil
+}
+
+func (r *MappingRule) compile() error {
+	if r.Type == "" {
+		return fmt.Errorf("type must not be empty")
+	}
+	var err error
+	if r.EventType != "" {
+		if r.eventTypeRegexp, err = regexp.Compile(r.EventType); err != nil {
+			return err
+		}
+	}
+	if r.typeTemplate, err = template.New("type").Option("missingkey=error").Parse(r.Type); err != nil {
+		return err
+	}
+	if r.Source != "" {
+		if r.sourceTemplate, err = template.New("source").Option("missingkey=error").Parse(r.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Map returns the CloudEvent type and source of the given legacy event,
+// and whether any rule matched the legacy event.
+func (m *MappingRules) Map(application, eventType, version string) (string, string, bool, error) {
+	for i := range m.Rules {
+		rule := &m.Rules[i]
+		values, ok := rule.match(application, eventType)
+		if !ok {
+			continue
+		}
+		values[mappingApplicationKey] = application
+		values[mappingEventTypeKey] = eventType
+		values[mappingVersionKey] = version
+
+		ceType, err := execute(rule.typeTemplate, values)
+		if err != nil {
+			return "", "", false, err
+		}
+		ceSource := application
+		if rule.sourceTemplate != nil {
+			if ceSource, err = execute(rule.sourceTemplate, values); err != nil {
+				return "", "", false, err
+			}
+		}
+		return ceType, ceSource, true, nil
+	}
+	return "", "", false, nil
+}
+
+// match returns the named groups of the event type regex if the rule matches the given legacy event.
+func (r *MappingRule) match(application, eventType string) (map[string]string, bool) {
+	if prefix, ok := strings.CutSuffix(r.Application, "*"); ok {
+		if !strings.HasPrefix(application, prefix) {
+			return nil, false
+		}
+	} else if r.Application != "" && r.Application != application {
+		return nil, false
+	}
+
+	values := make(map[string]string)
+	if r.eventTypeRegexp == nil {
+		return values, true
+	}
+	groups := r.eventTypeRegexp.FindStringSubmatch(eventType)
+	if groups == nil {
+		return nil, false
+	}
+	for i, name := range r.eventTypeRegexp.SubexpNames() {
+		if name != "" {
+			values[name] = groups[i]
+		}
+	}
+	return values, true
+}
+
+func execute(t *template.Template, values map[string]string) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, values); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,173 @@
+package legacy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	legacyapi "github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/legacy/api"
+)
+
+func TestLoadMappingRules(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		givenJSON string
+		wantErr   bool
+	}{
+		{
+			name:      "should load valid rules",
+			givenJSON: `{"rules":[{"eventType":"^(?P<object>\\w+)$","type":"{{.object}}.{{.version}}"}]}`,
+		},
+		{
+			name:      "should fail if the type is missing",
+			givenJSON: `{"rules":[{"eventType":"^order$"}]}`,
+			wantErr:   true,
+		},
+		{
+			name:      "should fail if the event type regex is invalid",
+			givenJSON: `{"rules":[{"eventType":"(","type":"order"}]}`,
+			wantErr:   true,
+		},
+		{
+			name:      "should fail if the source template is invalid",
+			givenJSON: `{"rules":[{"type":"order","source":"{{.application"}]}`,
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			path := filepath.Join(t.TempDir(), "rules.json")
+			require.NoError(t, os.WriteFile(path, []byte(tc.givenJSON), 0600))
+
+			// when
+			rules, err := LoadMappingRules(path)
+
+			// then
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, rules.Rules, 1)
+		})
+	}
+}
+
+func TestMappingRules_Map(t *testing.T) {
+	t.Parallel()
+
+	// given
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"rules":[
+		{"application":"erp-*","eventType":"^BO_(?P<object>[A-Z]+)_(?P<action>[A-Z]+)$",
+			"type":"{{.object}}.{{.action}}.{{.version}}","source":"erp"},
+		{"application":"legacy","type":"legacy.{{.eventType}}.{{.version}}"},
+		{"application":"broken","type":"{{.missing}}"}
+	]}`), 0600))
+	rules, err := LoadMappingRules(path)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		givenApplication string
+		givenEventType   string
+		wantType         string
+		wantSource       string
+		wantMatched      bool
+		wantErr          bool
+	}{
+		{
+			name:             "should map the named groups of the event type",
+			givenApplication: "erp-eu",
+			givenEventType:   "BO_ORDER_CREATED",
+			wantType:         "ORDER.CREATED.v1",
+			wantSource:       "erp",
+			wantMatched:      true,
+		},
+		{
+			name:             "should keep the application as source",
+			givenApplication: "legacy",
+			givenEventType:   "order.created",
+			wantType:         "legacy.order.created.v1",
+			wantSource:       "legacy",
+			wantMatched:      true,
+		},
+		{
+			name:             "should not match other event types",
+			givenApplication: "erp-eu",
+			givenEventType:   "order.created",
+		},
+		{
+			name:             "should not match other applications",
+			givenApplication: "shop",
+			givenEventType:   "BO_ORDER_CREATED",
+		},
+		{
+			name:             "should fail if the template refers to unknown values",
+			givenApplication: "broken",
+			givenEventType:   "order.created",
+			wantErr:          true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			gotType, gotSource, gotMatched, err := rules.Map(tc.givenApplication, tc.givenEventType, "v1")
+
+			// then
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantMatched, gotMatched)
+			require.Equal(t, tc.wantType, gotType)
+			require.Equal(t, tc.wantSource, gotSource)
+		})
+	}
+}
+
+func TestTransformPublishRequestToCloudEvent_WithMappingRules(t *testing.T) {
+	t.Parallel()
+
+	// given
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"rules":[
+		{"eventType":"^BO_(?P<object>[A-Z]+)_(?P<action>[A-Z]+)$","type":"{{.object}}.{{.action}}.{{.version}}"}
+	]}`), 0600))
+	rules, err := LoadMappingRules(path)
+	require.NoError(t, err)
+	transformer := NewTransformer("test", "prefix", nil)
+	transformer.SetMappingRules(rules)
+	givenPublishRequestData := &legacyapi.PublishRequestData{
+		PublishEventParameters: &legacyapi.PublishEventParametersV1{
+			PublishrequestV1: legacyapi.PublishRequestV1{
+				EventType:        "BO_ORDER_CREATED",
+				EventTypeVersion: "v1",
+				EventTime:        "2020-04-02T21:37:00Z",
+				Data:             map[string]string{"key": "value"},
+			},
+		},
+		ApplicationName: "erp",
+	}
+
+	// when
+	ceEvent, err := transformer.TransformPublishRequestToCloudEvent(givenPublishRequestData)
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "ORDER.CREATED.v1", ceEvent.Type())
+	require.Equal(t, "erp", ceEvent.Source())
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import "sync"
+
+const (
+	// DefaultMaxEventTypes is the default number of distinct event types used as label values.
+	DefaultMaxEventTypes = 100
+	// OtherEventType is the event type label value of the event types exceeding the cardinality limit.
+	OtherEventType = otherLabelValue
+
+	// maxClients is the number of distinct rate limited clients used as label values.
+	maxClients = 100
+	// OtherClient is the client label value of the rate limited clients exceeding the cardinality limit.
+	OtherClient = otherLabelValue
+
+	otherLabelValue = "other"
+)
+
+// labelLimiter bounds the cardinality of the values of a label.
+// The first max values are recorded as they are, all further values are recorded as otherLabelValue.
+type labelLimiter struct {
+	mu   sync.Mutex
+	max  int
+	seen map[string]struct{}
+}
+
+func newLabelLimiter(max int) *labelLimiter {
+	return &labelLimiter{max: max, seen: make(map[string]struct{})}
+}
+
+// setMax sets the maximum number of distinct values, 0 removes the limit.
+func (l *labelLimiter) setMax(max int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.max = max
+}
+
+// label returns the label value to record the given value with.
+func (l *labelLimiter) label(value string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.seen[value]; ok {
+		return value
+	}
+	if l.max > 0 && len(l.seen) >= l.max {
+		return otherLabelValue
+	}
+	l.seen[value] = struct{}{}
+	return value
+}
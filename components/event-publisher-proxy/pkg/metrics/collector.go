@@ -3,6 +3,7 @@ package metrics
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -37,8 +38,48 @@ const (
 	EventTypePublishedMetricKey = "eventing_epp_event_type_published_total"
 	// eventTypePublishedMetricHelp help text for the eventTypeLabel metric.
 	eventTypePublishedMetricHelp = "The total number of events published for a given eventTypeLabel"
-	// methodLabel label for the method used in the http request.
+	// EventTypePublishLatencyKey name of the eventTypePublishLatency metric.
+	EventTypePublishLatencyKey = "eventing_epp_event_type_publish_duration_milliseconds"
+	// eventTypePublishLatencyHelp help text for the eventTypePublishLatency metric.
+	eventTypePublishLatencyHelp = "The publish-to-ack duration of the events of a given event type in milliseconds"
+	// EventTypePayloadSizeKey name of the eventTypePayloadSize metric.
+	EventTypePayloadSizeKey = "eventing_epp_event_type_payload_bytes"
+	// eventTypePayloadSizeHelp help text for the eventTypePayloadSize metric.
+	eventTypePayloadSizeHelp = "The payload size of the events published for a given event type in bytes"
+	// RateLimitedKey name of the rateLimited metric.
+	RateLimitedKey = "eventing_epp_rate_limited_requests_total"
+	// rateLimitedHelp help text for the rateLimited metric.
+	rateLimitedHelp = "The total number of publish requests rejected by the rate limit for a given client"
+
+	// PayloadTooLargeKey name of the payloadTooLarge metric.
+	PayloadTooLargeKey = "eventing_epp_payload_too_large_total"
+	// payloadTooLargeHelp help text for the payloadTooLarge metric.
+	payloadTooLargeHelp = "The total number of events rejected for exceeding the maximum event size"
+
+	// InvalidEventsKey name of the invalidEvents metric.
+	InvalidEventsKey = "eventing_epp_invalid_events_total"
+	// invalidEventsHelp help text for the invalidEvents metric.
+	invalidEventsHelp = "The total number of violations of the CloudEvents specification for a given rule, " +
+		"and whether the events were rejected"
 
+	// MigrationPublishKey name of the migrationPublish metric.
+	MigrationPublishKey = "eventing_epp_migration_publish_total"
+	// migrationPublishHelp help text for the migrationPublish metric.
+	migrationPublishHelp = "The total number of events published to both the active and the migration backend " +
+		"for a given result"
+
+	// MigrationBothSucceeded is the migrationPublish result of events published to both backends.
+	MigrationBothSucceeded = "both_succeeded"
+	// MigrationBothFailed is the migrationPublish result of events failed to be published to both backends.
+	MigrationBothFailed = "both_failed"
+	// MigrationActiveFailed is the migrationPublish result of events published to the migration backend only.
+	MigrationActiveFailed = "active_failed"
+	// MigrationMigrationFailed is the migrationPublish result of events published to the active backend only.
+	MigrationMigrationFailed = "migration_failed"
+	// MigrationDropped is the migrationPublish result of events dropped because the migration queue was full.
+	MigrationDropped = "dropped"
+
+	// methodLabel label for the method used in the http request.
 	methodLabel = "method"
 	// responseCodeLabel name of the status code labels used by multiple metrics.
 	responseCodeLabel = "code"
@@ -50,6 +91,14 @@ const (
 	eventTypeLabel = "event_type"
 	// eventSourceLabel name of the event source label used by metrics.
 	eventSourceLabel = "event_source"
+	// clientLabel name of the rate limited client label used by metrics.
+	clientLabel = "client"
+	// resultLabel name of the migration result label used by metrics.
+	resultLabel = "result"
+	// ruleLabel name of the violated CloudEvents rule label used by metrics.
+	ruleLabel = "rule"
+	// rejectedLabel name of the label used by metrics telling whether an event was rejected.
+	rejectedLabel = "rejected"
 )
 
 // PublishingMetricsCollector interface provides a Prometheus compatible Collector with additional convenience methods
@@ -58,11 +107,19 @@ type PublishingMetricsCollector interface {
 	prometheus.Collector
 	RecordBackendLatency(duration time.Duration, statusCode int, destSvc string)
 	RecordEventType(eventType, eventSource string, statusCode int)
+	RecordEventTypePublish(eventType string, payloadSize int, duration time.Duration, statusCode int)
+	RecordRateLimited(client string)
+	RecordPayloadTooLarge()
+	RecordInvalidEvent(rule string, rejected bool)
+	RecordMigrationPublish(result string)
 	MetricsMiddleware() mux.MiddlewareFunc
 }
 
 var _ PublishingMetricsCollector = &Collector{}
 
+// PayloadSizeBuckets are the histogram buckets of the eventTypePayloadSize metric, from 64B up to 1MiB.
+var PayloadSizeBuckets = prometheus.ExponentialBuckets(64, 4, 8)
+
 // Collector implements the prometheus.Collector interface.
 type Collector struct {
 	backendLatency *prometheus.HistogramVec
@@ -70,9 +127,21 @@ type Collector struct {
 	duration *prometheus.HistogramVec
 	requests *prometheus.CounterVec
 
-	eventType *prometheus.CounterVec
+	eventType               *prometheus.CounterVec
+	eventTypePublishLatency *prometheus.HistogramVec
+	eventTypePayloadSize    *prometheus.HistogramVec
+	eventTypes              *labelLimiter
 
 	health *prometheus.GaugeVec
+
+	rateLimited *prometheus.CounterVec
+	clients     *labelLimiter
+
+	payloadTooLarge *prometheus.CounterVec
+
+	invalidEvents *prometheus.CounterVec
+
+	migrationPublish *prometheus.CounterVec
 }
 
 // NewCollector creates a new instance of Collector.
@@ -94,6 +163,25 @@ func NewCollector(latency histogram.BucketsProvider) *Collector {
 			},
 			[]string{eventTypeLabel, eventSourceLabel, responseCodeLabel},
 		),
+		//nolint:promlinter // we follow the same pattern as istio. so a millisecond unit if fine here
+		eventTypePublishLatency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    EventTypePublishLatencyKey,
+				Help:    eventTypePublishLatencyHelp,
+				Buckets: latency.Buckets(),
+			},
+			[]string{eventTypeLabel, responseCodeLabel},
+		),
+		eventTypePayloadSize: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    EventTypePayloadSizeKey,
+				Help:    eventTypePayloadSizeHelp,
+				Buckets: PayloadSizeBuckets,
+			},
+			[]string{eventTypeLabel},
+		),
+		eventTypes: newLabelLimiter(DefaultMaxEventTypes),
+		clients:    newLabelLimiter(maxClients),
 
 		duration: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
@@ -119,6 +207,34 @@ func NewCollector(latency histogram.BucketsProvider) *Collector {
 			},
 			nil,
 		),
+		rateLimited: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: RateLimitedKey,
+				Help: rateLimitedHelp,
+			},
+			[]string{clientLabel},
+		),
+		payloadTooLarge: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: PayloadTooLargeKey,
+				Help: payloadTooLargeHelp,
+			},
+			nil,
+		),
+		invalidEvents: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: InvalidEventsKey,
+				Help: invalidEventsHelp,
+			},
+			[]string{ruleLabel, rejectedLabel},
+		),
+		migrationPublish: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MigrationPublishKey,
+				Help: migrationPublishHelp,
+			},
+			[]string{resultLabel},
+		),
 	}
 }
 
@@ -126,18 +242,30 @@ func NewCollector(latency histogram.BucketsProvider) *Collector {
 func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	c.backendLatency.Describe(ch)
 	c.eventType.Describe(ch)
+	c.eventTypePublishLatency.Describe(ch)
+	c.eventTypePayloadSize.Describe(ch)
 	c.requests.Describe(ch)
 	c.duration.Describe(ch)
 	c.health.Describe(ch)
+	c.rateLimited.Describe(ch)
+	c.payloadTooLarge.Describe(ch)
+	c.invalidEvents.Describe(ch)
+	c.migrationPublish.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface Collect method.
 func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	c.backendLatency.Collect(ch)
 	c.eventType.Collect(ch)
+	c.eventTypePublishLatency.Collect(ch)
+	c.eventTypePayloadSize.Collect(ch)
 	c.requests.Collect(ch)
 	c.duration.Collect(ch)
 	c.health.Collect(ch)
+	c.rateLimited.Collect(ch)
+	c.payloadTooLarge.Collect(ch)
+	c.invalidEvents.Collect(ch)
+	c.migrationPublish.Collect(ch)
 }
 
 // RecordLatency records a backendLatencyHelp metric.
@@ -156,7 +284,42 @@ func (c *Collector) SetHealthStatus(healthy bool) {
 
 // RecordEventType records an eventType metric.
 func (c *Collector) RecordEventType(eventType, eventSource string, statusCode int) {
-	c.eventType.WithLabelValues(eventType, eventSource, fmt.Sprint(statusCode)).Inc()
+	c.eventType.WithLabelValues(c.eventTypes.label(eventType), eventSource, fmt.Sprint(statusCode)).Inc()
+}
+
+// RecordEventTypePublish records the eventTypePublishLatency and eventTypePayloadSize metrics.
+func (c *Collector) RecordEventTypePublish(eventType string, payloadSize int, duration time.Duration, statusCode int) {
+	eventType = c.eventTypes.label(eventType)
+	c.eventTypePublishLatency.WithLabelValues(eventType, fmt.Sprint(statusCode)).
+		Observe(float64(duration.Milliseconds()))
+	c.eventTypePayloadSize.WithLabelValues(eventType).Observe(float64(payloadSize))
+}
+
+// SetMaxEventTypes sets the maximum number of distinct event types recorded by the per event type metrics.
+// Further event types are recorded as OtherEventType, 0 removes the limit.
+func (c *Collector) SetMaxEventTypes(max int) {
+	c.eventTypes.setMax(max)
+}
+
+// RecordRateLimited records a rateLimited metric.
+// The clients are chosen by the publishers, so further clients than the first ones are recorded as OtherClient.
+func (c *Collector) RecordRateLimited(client string) {
+	c.rateLimited.WithLabelValues(c.clients.label(client)).Inc()
+}
+
+// RecordPayloadTooLarge records a payloadTooLarge metric.
+func (c *Collector) RecordPayloadTooLarge() {
+	c.payloadTooLarge.WithLabelValues().Inc()
+}
+
+// RecordInvalidEvent records an invalidEvents metric.
+func (c *Collector) RecordInvalidEvent(rule string, rejected bool) {
+	c.invalidEvents.WithLabelValues(rule, strconv.FormatBool(rejected)).Inc()
+}
+
+// RecordMigrationPublish records a migrationPublish metric.
+func (c *Collector) RecordMigrationPublish(result string) {
+	c.migrationPublish.WithLabelValues(result).Inc()
 }
 
 // MetricsMiddleware returns a http.Handler that can be used as middleware in gorilla.mux to track
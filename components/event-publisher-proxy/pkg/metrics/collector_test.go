@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -29,9 +30,46 @@ func TestNewCollector(t *testing.T) {
 	assert.NotNil(t, collector.backendLatency.MetricVec)
 	assert.NotNil(t, collector.eventType)
 	assert.NotNil(t, collector.eventType.MetricVec)
+	assert.NotNil(t, collector.eventTypePublishLatency)
+	assert.NotNil(t, collector.eventTypePayloadSize)
 	latency.AssertExpectations(t)
 }
 
+func TestCollector_RecordEventTypePublish_MaxEventTypes(t *testing.T) {
+	// given
+	c := NewCollector(latency.BucketsProvider{})
+	c.SetMaxEventTypes(2)
+
+	// when
+	c.RecordEventTypePublish("order.created.v1", 10, time.Millisecond, http.StatusNoContent)
+	c.RecordEventTypePublish("order.updated.v1", 10, time.Millisecond, http.StatusNoContent)
+	c.RecordEventTypePublish("order.deleted.v1", 10, time.Millisecond, http.StatusNoContent)
+	c.RecordEventTypePublish("order.created.v1", 10, time.Millisecond, http.StatusInternalServerError)
+	c.RecordEventType("order.archived.v1", "commerce", http.StatusNoContent)
+
+	// then
+	assert.Equal(t, 3, testutil.CollectAndCount(c, EventTypePayloadSizeKey))
+	assert.Equal(t, 4, testutil.CollectAndCount(c, EventTypePublishLatencyKey))
+	assert.Equal(t, 1, testutil.CollectAndCount(c, EventTypePublishedMetricKey))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.eventType.WithLabelValues(OtherEventType, "commerce", "204")))
+}
+
+func TestCollector_RecordRateLimited_MaxClients(t *testing.T) {
+	// given
+	c := NewCollector(latency.BucketsProvider{})
+
+	// when more clients are rate limited than recorded by their own label value
+	for i := 0; i <= maxClients; i++ {
+		c.RecordRateLimited(fmt.Sprintf("client-%d", i))
+	}
+	c.RecordRateLimited("client-0")
+
+	// then
+	assert.Equal(t, maxClients+1, testutil.CollectAndCount(c, RateLimitedKey))
+	assert.Equal(t, float64(2), testutil.ToFloat64(c.rateLimited.WithLabelValues("client-0")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(c.rateLimited.WithLabelValues(OtherClient)))
+}
+
 func TestCollector_MetricsMiddleware(t *testing.T) {
 	router := mux.NewRouter()
 	c := NewCollector(latency.BucketsProvider{})
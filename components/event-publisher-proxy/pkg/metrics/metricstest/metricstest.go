@@ -2,6 +2,7 @@
 package metricstest
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"testing"
@@ -23,6 +24,21 @@ func EnsureMetricEventTypePublished(t *testing.T, collector metrics.PublishingMe
 	ensureMetricCount(t, collector, metrics.EventTypePublishedMetricKey, count)
 }
 
+// EnsureMetricRateLimited ensures metric eventing_epp_rate_limited_requests_total exists.
+func EnsureMetricRateLimited(t *testing.T, collector metrics.PublishingMetricsCollector, count int) {
+	ensureMetricCount(t, collector, metrics.RateLimitedKey, count)
+}
+
+// EnsureMetricPayloadTooLarge ensures metric eventing_epp_payload_too_large_total exists.
+func EnsureMetricPayloadTooLarge(t *testing.T, collector metrics.PublishingMetricsCollector, count int) {
+	ensureMetricCount(t, collector, metrics.PayloadTooLargeKey, count)
+}
+
+// EnsureMetricInvalidEvents ensures metric eventing_epp_invalid_events_total exists.
+func EnsureMetricInvalidEvents(t *testing.T, collector metrics.PublishingMetricsCollector, count int) {
+	ensureMetricCount(t, collector, metrics.InvalidEventsKey, count)
+}
+
 func ensureMetricCount(t *testing.T, collector metrics.PublishingMetricsCollector, metric string, expectedCount int) {
 	if count := testutil.CollectAndCount(collector, metric); count != expectedCount {
 		t.Fatalf("invalid count for metric:%s, want:%d, got:%d", metric, expectedCount, count)
@@ -56,9 +72,24 @@ func (p PublishingMetricsCollectorStub) RecordLatency(_ time.Duration, _ int, _
 func (p PublishingMetricsCollectorStub) RecordEventType(_, _ string, _ int) {
 }
 
+func (p PublishingMetricsCollectorStub) RecordEventTypePublish(_ string, _ int, _ time.Duration, _ int) {
+}
+
 func (p PublishingMetricsCollectorStub) RecordRequests(_ int, _ string) {
 }
 
+func (p PublishingMetricsCollectorStub) RecordPayloadTooLarge() {
+}
+
+func (p PublishingMetricsCollectorStub) RecordInvalidEvent(_ string, _ bool) {
+}
+
+func (p PublishingMetricsCollectorStub) RecordMigrationPublish(_ string) {
+}
+
+func (p PublishingMetricsCollectorStub) RecordRateLimited(_ string) {
+}
+
 //nolint:lll // that's how TEF has to look like
 func MakeTEFBackendDuration(code int, service string) string {
 	tef := strings.ReplaceAll(`# HELP eventing_epp_backend_duration_milliseconds The duration of sending events to the messaging server in milliseconds
@@ -101,8 +132,58 @@ func MakeTEFBackendErrors() string {
 func MakeTEFEventTypePublished(code int, source, eventtype string) string {
 	tef := strings.ReplaceAll(`# HELP eventing_epp_event_type_published_total The total number of events published for a given eventTypeLabel
         # TYPE eventing_epp_event_type_published_total counter
-        eventing_epp_event_type_published_total{code="204",event_source="%%source%%",event_type="%%type%%"} 1
+        eventing_epp_event_type_published_total{code="%%code%%",event_source="%%source%%",event_type="%%type%%"} 1
 					`, "%%code%%", strconv.Itoa(code))
 	tef = strings.ReplaceAll(tef, "%%source%%", source)
 	return strings.ReplaceAll(tef, "%%type%%", eventtype)
 }
+
+//nolint:lll // that's how TEF has to look like
+func MakeTEFEventTypePublishDuration(code int, eventType string) string {
+	tef := strings.ReplaceAll(`# HELP eventing_epp_event_type_publish_duration_milliseconds The publish-to-ack duration of the events of a given event type in milliseconds
+					# TYPE eventing_epp_event_type_publish_duration_milliseconds histogram
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="0.005"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="0.01"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="0.025"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="0.05"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="0.1"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="0.25"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="0.5"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="1"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="2.5"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="5"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="10"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_bucket{code="%%code%%",event_type="%%type%%",le="+Inf"} 1
+					eventing_epp_event_type_publish_duration_milliseconds_sum{code="%%code%%",event_type="%%type%%"} 0
+					eventing_epp_event_type_publish_duration_milliseconds_count{code="%%code%%",event_type="%%type%%"} 1
+					`, "%%code%%", strconv.Itoa(code))
+	return strings.ReplaceAll(tef, "%%type%%", eventType)
+}
+
+//nolint:lll // that's how TEF has to look like
+func MakeTEFEventTypePayloadSize(eventType string, size int) string {
+	var tef strings.Builder
+	tef.WriteString(`# HELP eventing_epp_event_type_payload_bytes The payload size of the events published for a given event type in bytes
+					# TYPE eventing_epp_event_type_payload_bytes histogram
+					`)
+	for _, bucket := range metrics.PayloadSizeBuckets {
+		count := 0
+		if float64(size) <= bucket {
+			count = 1
+		}
+		tef.WriteString(fmt.Sprintf("eventing_epp_event_type_payload_bytes_bucket{event_type=%q,le=\"%g\"} %d\n",
+			eventType, bucket, count))
+	}
+	tef.WriteString(fmt.Sprintf("eventing_epp_event_type_payload_bytes_bucket{event_type=%q,le=\"+Inf\"} 1\n", eventType))
+	tef.WriteString(fmt.Sprintf("eventing_epp_event_type_payload_bytes_sum{event_type=%q} %d\n", eventType, size))
+	tef.WriteString(fmt.Sprintf("eventing_epp_event_type_payload_bytes_count{event_type=%q} 1\n", eventType))
+	return tef.String()
+}
+
+//nolint:lll // that's how TEF has to look like
+func MakeTEFMigrationPublish(result string) string {
+	return strings.ReplaceAll(`# HELP eventing_epp_migration_publish_total The total number of events published to both the active and the migration backend for a given result
+					# TYPE eventing_epp_migration_publish_total counter
+					eventing_epp_migration_publish_total{result="%%result%%"} 1
+					`, "%%result%%", result)
+}
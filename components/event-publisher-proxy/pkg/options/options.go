@@ -3,20 +3,109 @@ package options
 import (
 	"flag"
 	"fmt"
+	"time"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/cloudevent/validation"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/auth"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/metrics"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/ratelimit"
 )
 
 const (
 	maxRequestSize     = 65536
 	metricEndpointPort = ":9090"
+	maxEventTypes      = metrics.DefaultMaxEventTypes
+	rateLimitBurst     = 100
+	asyncBufferSize    = 1000
+	migrationQueueSize = 1000
+	migrationWorkers   = 10
+	debugMaxStreams    = 10
+	idempotencyMaxKeys = 10000
+	rateLimitKey       = ratelimit.KeyApplication
+
+	// MigrationBackendNATS publishes the events to JetStream in addition to the active backend.
+	MigrationBackendNATS = "nats"
+	// MigrationBackendEventMesh publishes the events to EventMesh in addition to the active backend.
+	MigrationBackendEventMesh = "beb"
 
 	// All the available arguments.
 	argMaxRequestSize = "max-request-size"
 	argMetricsAddress = "metrics-addr"
+	argMaxEventTypes  = "metrics-max-event-types"
+	argRateLimit      = "rate-limit"
+	argRateLimitBurst = "rate-limit-burst"
+	argRateLimitKey   = "rate-limit-key"
+	argAuthMode       = "auth-mode"
+	argJWKSURL        = "jwks-url"
+	argJWTIssuer      = "jwt-issuer"
+	argJWTAudience    = "jwt-audience"
+	argAuthPolicyFile = "auth-policy-file"
+	argAllowlistFile  = "application-allowlist-file"
+	argTrustXFCC      = "trust-forwarded-client-cert"
+	argDebugEndpoint  = "debug-endpoint"
+	argDebugStreams   = "debug-max-streams"
+	argLegacyMapping  = "legacy-mapping-file"
+	argAsyncPublish   = "async-publish"
+	argAsyncBuffer    = "async-buffer-size"
+	argMigration      = "migration-backend"
+	argMigrationQueue = "migration-queue-size"
+	argMigrationPool  = "migration-workers"
+	argIdempotency    = "idempotency-window"
+	argIdempotencyMax = "idempotency-max-keys"
+	argValidation     = "validation-policy"
+	argMaxEventSize   = "max-event-size"
 )
 
 type Options struct {
 	MaxRequestSize int64
 	MetricsAddress string
+	// MaxEventTypes is the number of distinct event types recorded by the per event type metrics, 0 disables the limit.
+	MaxEventTypes int
+
+	// RateLimit is the number of publish requests per second allowed for each client, 0 disables the rate limit.
+	RateLimit float64
+	// RateLimitBurst is the number of publish requests allowed for each client in a burst.
+	RateLimitBurst int
+	// RateLimitKey identifies the clients of the rate limit, either by "application" or by "client" certificate.
+	RateLimitKey string
+
+	// Auth configures the authentication and authorization of the publish endpoints.
+	Auth auth.Config
+
+	// DebugEndpoint enables the endpoint streaming the published events to authenticated clients.
+	DebugEndpoint bool
+	// DebugMaxStreams is the number of clients the debug endpoint streams the events to concurrently.
+	DebugMaxStreams int
+
+	// LegacyMappingFile is the JSON file with the rules mapping the legacy event types to CloudEvent types and sources.
+	LegacyMappingFile string
+
+	// AsyncPublish accepts the events with 202 Accepted and publishes them in the background.
+	AsyncPublish bool
+	// AsyncBufferSize is the number of events buffered to be published in the background.
+	AsyncBufferSize int
+
+	// MigrationBackend is the backend the events are published to in addition to the active backend,
+	// either "nats" or "beb", disabled if empty.
+	MigrationBackend string
+	// MigrationQueueSize is the number of events queued to be published to the migration backend,
+	// the events are dropped if the queue is full.
+	MigrationQueueSize int
+	// MigrationWorkers is the number of events published to the migration backend concurrently.
+	MigrationWorkers int
+
+	// IdempotencyWindow is the time the responses are replayed for the requests retried with the same idempotency key,
+	// 0 ignores the idempotency keys.
+	IdempotencyWindow time.Duration
+	// IdempotencyMaxKeys is the number of idempotency keys the responses are kept for, the oldest keys are evicted first.
+	IdempotencyMaxKeys int
+
+	// ValidationPolicy rejects the events violating any rule of the CloudEvents specification if "strict",
+	// or only the events violating its required rules if "lenient".
+	ValidationPolicy string
+	// MaxEventSize is the size in bytes the events should not exceed, checked only by the strict validation policy.
+	MaxEventSize int
 }
 
 func New() *Options {
@@ -26,14 +115,114 @@ func New() *Options {
 func (o *Options) Parse() error {
 	flag.Int64Var(&o.MaxRequestSize, argMaxRequestSize, maxRequestSize, "The maximum request size in bytes.")
 	flag.StringVar(&o.MetricsAddress, argMetricsAddress, metricEndpointPort, "The address the metric endpoint binds to.")
+	flag.IntVar(&o.MaxEventTypes, argMaxEventTypes, maxEventTypes,
+		"The number of distinct event types recorded by the per event type metrics, 0 disables the limit.")
+	flag.Float64Var(&o.RateLimit, argRateLimit, 0,
+		"The number of publish requests per second allowed for each client, 0 disables the rate limit.")
+	flag.IntVar(&o.RateLimitBurst, argRateLimitBurst, rateLimitBurst,
+		"The number of publish requests allowed for each client in a burst.")
+	flag.StringVar(&o.RateLimitKey, argRateLimitKey, rateLimitKey,
+		"Identifies the clients of the rate limit, either by \"application\" or by \"client\" certificate.")
+	flag.StringVar(&o.Auth.Mode, argAuthMode, auth.ModeNone,
+		"The authentication of the publish endpoints, either none, jwt, or mtls.")
+	flag.StringVar(&o.Auth.JWKSURL, argJWKSURL, "", "The URL of the JWKS to validate the JWTs against.")
+	flag.StringVar(&o.Auth.JWTIssuer, argJWTIssuer, "", "The expected issuer of the JWTs, not checked if empty.")
+	flag.StringVar(&o.Auth.JWTAudience, argJWTAudience, "", "The expected audience of the JWTs, not checked if empty.")
+	flag.StringVar(&o.Auth.PolicyFile, argAuthPolicyFile, "",
+		"The JSON file authorizing the client identities to publish event types and sources.")
+	flag.StringVar(&o.Auth.AllowlistFile, argAllowlistFile, "",
+		"The JSON file of the Application event types allowed in addition to the auth-policy-file, reloaded on changes.")
+	flag.BoolVar(&o.Auth.TrustForwardedClientCert, argTrustXFCC, false,
+		"Identifies the clients by the X-Forwarded-Client-Cert header, only safe behind a sidecar sanitizing it.")
+	flag.BoolVar(&o.DebugEndpoint, argDebugEndpoint, false,
+		"Enables the endpoint streaming the published events to authenticated clients, requires an auth-mode.")
+	flag.IntVar(&o.DebugMaxStreams, argDebugStreams, debugMaxStreams,
+		"The number of clients the debug endpoint streams the events to concurrently.")
+	flag.StringVar(&o.LegacyMappingFile, argLegacyMapping, "",
+		"The JSON file with the rules mapping the legacy event types to CloudEvent types and sources.")
+	flag.BoolVar(&o.AsyncPublish, argAsyncPublish, false,
+		"Accepts the events with 202 Accepted and publishes them in the background.")
+	flag.IntVar(&o.AsyncBufferSize, argAsyncBuffer, asyncBufferSize,
+		"The number of events buffered to be published in the background.")
+	flag.StringVar(&o.MigrationBackend, argMigration, "",
+		"The backend the events are published to in addition to the active backend, either nats or beb.")
+	flag.IntVar(&o.MigrationQueueSize, argMigrationQueue, migrationQueueSize,
+		"The number of events queued to be published to the migration backend, dropped if the queue is full.")
+	flag.IntVar(&o.MigrationWorkers, argMigrationPool, migrationWorkers,
+		"The number of events published to the migration backend concurrently.")
+	flag.DurationVar(&o.IdempotencyWindow, argIdempotency, 0,
+		"The time the responses are replayed for the requests retried with the same idempotency key, 0 disables it.")
+	flag.IntVar(&o.IdempotencyMaxKeys, argIdempotencyMax, idempotencyMaxKeys,
+		"The number of idempotency keys the responses are kept for, the oldest keys are evicted first.")
+	flag.StringVar(&o.ValidationPolicy, argValidation, validation.PolicyLenient,
+		"Rejects the events violating any rule of the CloudEvents specification if strict, "+
+			"or only the events violating its required rules if lenient.")
+	flag.IntVar(&o.MaxEventSize, argMaxEventSize, validation.DefaultMaxEventSize,
+		"The size in bytes the events should not exceed, checked only by the strict validation-policy, 0 disables it.")
 	flag.Parse()
 
+	if o.RateLimitKey != ratelimit.KeyApplication && o.RateLimitKey != ratelimit.KeyClient {
+		return fmt.Errorf("invalid %s: %q, must be either application or client", argRateLimitKey, o.RateLimitKey)
+	}
+	if o.AsyncPublish && o.AsyncBufferSize <= 0 {
+		return fmt.Errorf("invalid %s: %d, must be positive", argAsyncBuffer, o.AsyncBufferSize)
+	}
+	if o.MigrationBackend != "" && o.MigrationBackend != MigrationBackendNATS &&
+		o.MigrationBackend != MigrationBackendEventMesh {
+		return fmt.Errorf("invalid %s: %q, must be either nats or beb", argMigration, o.MigrationBackend)
+	}
+	if o.MigrationBackend != "" && o.MigrationQueueSize <= 0 {
+		return fmt.Errorf("invalid %s: %d, must be positive", argMigrationQueue, o.MigrationQueueSize)
+	}
+	if o.MigrationBackend != "" && o.MigrationWorkers <= 0 {
+		return fmt.Errorf("invalid %s: %d, must be positive", argMigrationPool, o.MigrationWorkers)
+	}
+	if o.IdempotencyWindow < 0 {
+		return fmt.Errorf("invalid %s: %v, must not be negative", argIdempotency, o.IdempotencyWindow)
+	}
+	if o.IdempotencyWindow > 0 && o.IdempotencyMaxKeys <= 0 {
+		return fmt.Errorf("invalid %s: %d, must be positive", argIdempotencyMax, o.IdempotencyMaxKeys)
+	}
+	if _, err := validation.NewValidator(o.ValidationPolicy, o.MaxEventSize); err != nil {
+		return fmt.Errorf("invalid %s or %s: %w", argValidation, argMaxEventSize, err)
+	}
+	if o.DebugEndpoint && o.Auth.Mode == auth.ModeNone {
+		return fmt.Errorf("%s requires the %s jwt or mtls", argDebugEndpoint, argAuthMode)
+	}
+	if o.DebugEndpoint && o.DebugMaxStreams <= 0 {
+		return fmt.Errorf("invalid %s: %d, must be positive", argDebugStreams, o.DebugMaxStreams)
+	}
 	return nil
 }
 
 func (o Options) String() string {
-	return fmt.Sprintf("--%s=%v --%s=%v",
+	return fmt.Sprintf("--%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v"+
+		" --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v"+
+		" --%s=%v",
 		argMaxRequestSize, o.MaxRequestSize,
 		argMetricsAddress, o.MetricsAddress,
+		argMaxEventTypes, o.MaxEventTypes,
+		argRateLimit, o.RateLimit,
+		argRateLimitBurst, o.RateLimitBurst,
+		argRateLimitKey, o.RateLimitKey,
+		argAuthMode, o.Auth.Mode,
+		argJWKSURL, o.Auth.JWKSURL,
+		argJWTIssuer, o.Auth.JWTIssuer,
+		argJWTAudience, o.Auth.JWTAudience,
+		argAuthPolicyFile, o.Auth.PolicyFile,
+		argAllowlistFile, o.Auth.AllowlistFile,
+		argTrustXFCC, o.Auth.TrustForwardedClientCert,
+		argDebugEndpoint, o.DebugEndpoint,
+		argDebugStreams, o.DebugMaxStreams,
+		argLegacyMapping, o.LegacyMappingFile,
+		argAsyncPublish, o.AsyncPublish,
+		argAsyncBuffer, o.AsyncBufferSize,
+		argMigration, o.MigrationBackend,
+		argMigrationQueue, o.MigrationQueueSize,
+		argMigrationPool, o.MigrationWorkers,
+		argIdempotency, o.IdempotencyWindow,
+		argIdempotencyMax, o.IdempotencyMaxKeys,
+		argValidation, o.ValidationPolicy,
+		argMaxEventSize, o.MaxEventSize,
 	)
 }
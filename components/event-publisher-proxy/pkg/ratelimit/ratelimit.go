@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/auth"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/legacy"
+)
+
+const (
+	// KeyApplication keys the rate limits by the application name of legacy events,
+	// or by the source of binary-mode CloudEvents.
+	KeyApplication = "application"
+	// KeyClient keys the rate limits by the identity of the client certificate.
+	KeyClient = "client"
+
+	ceSourceHeader = "Ce-Source"
+
+	// minIdleTimeout is the minimal time after which the token bucket of an unused key is removed.
+	minIdleTimeout = time.Second
+)
+
+// Limiter limits the number of requests per key with a token bucket for each key.
+// The token buckets of the keys unused until their bucket is full again are removed,
+// so that the buckets of clients which stopped publishing do not pile up.
+type Limiter struct {
+	limit       rate.Limit
+	burst       int
+	idleTimeout time.Duration
+	mutex       sync.Mutex
+	limiters    map[string]*bucket
+	nextSweep   time.Time
+}
+
+// bucket is the token bucket of a key and the time the key was last used.
+type bucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// NewLimiter returns a Limiter allowing the given number of requests per second and key,
+// with bursts of up to the given size.
+func NewLimiter(requestsPerSecond float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	// an unused bucket is full again once the burst is refilled, so removing it does not change the rate limit
+	idleTimeout := minIdleTimeout
+	if requestsPerSecond > 0 {
+		if refill := time.Duration(float64(burst) / requestsPerSecond * float64(time.Second)); refill > idleTimeout {
+			idleTimeout = refill
+		}
+	}
+	return &Limiter{
+		limit:       rate.Limit(requestsPerSecond),
+		burst:       burst,
+		idleTimeout: idleTimeout,
+		limiters:    map[string]*bucket{},
+	}
+}
+
+// Allow reports whether a request of the given key is allowed now.
+// If not, it returns the duration after which the request can be retried.
+func (l *Limiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	reservation := l.limiterFor(key, now).ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, time.Second
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// limiterFor returns the token bucket of the given key and marks the key as used at the given time.
+func (l *Limiter) limiterFor(key string, now time.Time) *rate.Limiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.sweep(now)
+	b, ok := l.limiters[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = b
+	}
+	b.lastUsed = now
+	return b.limiter
+}
+
+// sweep removes the buckets of the keys unused for the idle timeout, at most once per idle timeout.
+// The caller must hold the lock.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Before(l.nextSweep) {
+		return
+	}
+	for key, b := range l.limiters {
+		if now.Sub(b.lastUsed) >= l.idleTimeout {
+			delete(l.limiters, key)
+		}
+	}
+	l.nextSweep = now.Add(l.idleTimeout)
+}
+
+// RequestKey returns the rate limit key of the given request for the given key type, trusting the client certificate
+// forwarded by Istio if trustForwarded is set. Requests without a key share the rate limit of the empty key.
+func RequestKey(keyType string, trustForwarded bool, request *http.Request) string {
+	if keyType == KeyClient {
+		return auth.ClientCertIdentity(request, trustForwarded)
+	}
+	if application := legacy.ParseApplicationNameFromPath(request.URL.Path); application != "" {
+		return application
+	}
+	return request.Header.Get(ceSourceHeader)
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	// given
+	limiter := NewLimiter(1, 2)
+	now := time.Now()
+
+	// when the burst is used up
+	allowed1, _ := limiter.Allow("app1", now)
+	allowed2, _ := limiter.Allow("app1", now)
+	allowed3, retryAfter := limiter.Allow("app1", now)
+
+	// then
+	assert.True(t, allowed1)
+	assert.True(t, allowed2)
+	assert.False(t, allowed3)
+	assert.Equal(t, time.Second, retryAfter)
+
+	// when another key publishes
+	allowed, _ := limiter.Allow("app2", now)
+
+	// then it has its own bucket
+	assert.True(t, allowed)
+
+	// when a token was refilled
+	allowed, _ = limiter.Allow("app1", now.Add(time.Second))
+
+	// then
+	assert.True(t, allowed)
+}
+
+func TestLimiter_AllowRemovesIdleBuckets(t *testing.T) {
+	// given
+	limiter := NewLimiter(1, 2)
+	now := time.Now()
+	limiter.Allow("app1", now)
+	limiter.Allow("app1", now)
+	limiter.Allow("app2", now)
+
+	// when app2 publishes again before the bucket of app1 is refilled
+	allowed, _ := limiter.Allow("app2", now.Add(time.Second))
+
+	// then app1 is still limited
+	assert.True(t, allowed)
+	assert.Len(t, limiter.limiters, 2)
+	allowed, _ = limiter.Allow("app1", now.Add(500*time.Millisecond))
+	assert.False(t, allowed)
+
+	// when only app2 publishes after the bucket of app1 is refilled
+	allowed, _ = limiter.Allow("app2", now.Add(3*time.Second))
+
+	// then the bucket of app1 is removed
+	assert.True(t, allowed)
+	assert.Len(t, limiter.limiters, 1)
+	assert.Contains(t, limiter.limiters, "app2")
+}
+
+func TestRequestKey(t *testing.T) {
+	newRequest := func(path string, header map[string]string) *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "http://localhost"+path, nil)
+		for k, v := range header {
+			request.Header.Set(k, v)
+		}
+		return request
+	}
+	tlsRequest := newRequest("/publish", nil)
+	tlsRequest.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "orders"}}},
+	}
+
+	testCases := []struct {
+		name         string
+		givenKeyType string
+		givenRequest *http.Request
+		wantKey      string
+	}{
+		{
+			name:         "application of a legacy event",
+			givenKeyType: KeyApplication,
+			givenRequest: newRequest("/commerce/v1/events", nil),
+			wantKey:      "commerce",
+		},
+		{
+			name:         "source of a binary-mode CloudEvent",
+			givenKeyType: KeyApplication,
+			givenRequest: newRequest("/publish", map[string]string{"ce-source": "commerce"}),
+			wantKey:      "commerce",
+		},
+		{
+			name:         "structured-mode CloudEvent",
+			givenKeyType: KeyApplication,
+			givenRequest: newRequest("/publish", nil),
+			wantKey:      "",
+		},
+		{
+			name:         "common name of the client certificate",
+			givenKeyType: KeyClient,
+			givenRequest: tlsRequest,
+			wantKey:      "orders",
+		},
+		{
+			name:         "URI of the client certificate forwarded by Istio",
+			givenKeyType: KeyClient,
+			givenRequest: newRequest("/publish", map[string]string{
+				"X-Forwarded-Client-Cert": `By=spiffe://cluster.local/ns/kyma-system/sa/epp;Hash=abc;` +
+					`Subject="";URI=spiffe://cluster.local/ns/shop/sa/orders`,
+			}),
+			wantKey: "spiffe://cluster.local/ns/shop/sa/orders",
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantKey, RequestKey(tc.givenKeyType, true, tc.givenRequest))
+		})
+	}
+}
@@ -15,6 +15,7 @@ var (
 	ErrClientNoConnection     = BackendPublishError{HTTPCode: http.StatusBadGateway, Info: "no connection to backend"}
 	ErrInternalBackendError   = BackendPublishError{HTTPCode: http.StatusInternalServerError, Info: "internal error on backend"}
 	ErrClientConversionFailed = BackendPublishError{HTTPCode: http.StatusBadRequest, Info: "conversion to target format failed"}
+	ErrPublishNotAllowed      = BackendPublishError{HTTPCode: http.StatusForbidden, Info: "publishing the event is not allowed"}
 )
 
 type BackendPublishError struct {
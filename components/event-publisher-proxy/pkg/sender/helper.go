@@ -24,5 +24,20 @@ func (p PublishingMetricsCollectorStub) RecordLatency(time.Duration, int, string
 func (p PublishingMetricsCollectorStub) RecordEventType(string, string, int) {
 }
 
+func (p PublishingMetricsCollectorStub) RecordEventTypePublish(string, int, time.Duration, int) {
+}
+
 func (p PublishingMetricsCollectorStub) RecordRequests(int, string) {
 }
+
+func (p PublishingMetricsCollectorStub) RecordPayloadTooLarge() {
+}
+
+func (p PublishingMetricsCollectorStub) RecordInvalidEvent(string, bool) {
+}
+
+func (p PublishingMetricsCollectorStub) RecordMigrationPublish(_ string) {
+}
+
+func (p PublishingMetricsCollectorStub) RecordRateLimited(string) {
+}
@@ -0,0 +1,182 @@
+package jetstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/nats-io/nats.go"
+
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender/common"
+)
+
+const (
+	// asyncRetryInterval is the time to wait before publishing an event again after a transient error.
+	asyncRetryInterval = time.Second
+	// asyncAckTimeout is the maximum time to wait for the acknowledgement of a published event before publishing it again.
+	asyncAckTimeout = 5 * time.Second
+	// asyncDrainTimeout is the maximum time to publish the buffered events when closing the AsyncSender.
+	asyncDrainTimeout = 10 * time.Second
+)
+
+// compile time check.
+var _ sender.AsyncSender = &AsyncSender{}
+
+// AsyncSender buffers the events in a bounded in-memory queue and publishes them to NATS JetStream in the background.
+// The events failing with a transient error are published again until they are acknowledged by the stream.
+type AsyncSender struct {
+	*Sender
+	// ctx is cancelled when Close fails to publish the buffered events within the drainTimeout,
+	// which stops retrying them
+	ctx           context.Context
+	cancel        context.CancelFunc
+	jsCtx         nats.JetStreamContext
+	queue         chan *nats.Msg
+	pending       chan pendingMsg
+	done          chan struct{}
+	retryInterval time.Duration
+	ackTimeout    time.Duration
+	drainTimeout  time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// pendingMsg is a message published to the stream, waiting for its acknowledgement.
+type pendingMsg struct {
+	msg    *nats.Msg
+	future nats.PubAckFuture
+}
+
+// NewAsyncSender returns a new AsyncSender buffering up to bufferSize events,
+// retrying the transient errors until the events are published or the AsyncSender fails to drain them on Close.
+// The AsyncSender does not depend on the context of the publisher, which is already done when it is closed.
+func NewAsyncSender(s *Sender, bufferSize int) (*AsyncSender, error) {
+	jsCtx, err := s.connection.JetStream(nats.PublishAsyncMaxPending(bufferSize))
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	as := &AsyncSender{
+		Sender:        s,
+		ctx:           ctx,
+		cancel:        cancel,
+		jsCtx:         jsCtx,
+		queue:         make(chan *nats.Msg, bufferSize),
+		pending:       make(chan pendingMsg, bufferSize),
+		done:          make(chan struct{}),
+		retryInterval: asyncRetryInterval,
+		ackTimeout:    asyncAckTimeout,
+		drainTimeout:  asyncDrainTimeout,
+	}
+	go as.publish()
+	go as.handleAcks()
+	return as, nil
+}
+
+// SendAsync buffers the event to be published in the background.
+// If the buffer is full, it returns an error.
+func (s *AsyncSender) SendAsync(event *event.Event) sender.PublishError {
+	msg, err := s.eventToNATSMsg(event)
+	if err != nil {
+		s.eventLogger(event).Error("error", err)
+		e := common.ErrClientConversionFailed
+		e.Wrap(err)
+		return e
+	}
+	// deduplicate the events published again after a lost acknowledgement
+	if msg.Header.Get(nats.MsgIdHdr) == "" {
+		msg.Header.Set(nats.MsgIdHdr, fmt.Sprintf("%s/%s", msg.Subject, event.ID()))
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrNotConnected
+	}
+	select {
+	case s.queue <- msg:
+		return nil
+	default:
+		return ErrBufferFull
+	}
+}
+
+// Close stops accepting events and waits until the buffered events are published, retrying the transient errors
+// for at most the drain timeout. The events which are not published by then are dropped.
+func (s *AsyncSender) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	defer s.cancel()
+
+	select {
+	case <-s.done:
+	case <-time.After(s.drainTimeout):
+		s.namedLogger().Errorw("Failed to publish all the buffered events", "buffered", len(s.queue))
+	}
+}
+
+// publish publishes the buffered events without waiting for their acknowledgements.
+func (s *AsyncSender) publish() {
+	defer close(s.pending)
+	for msg := range s.queue {
+		future, err := s.jsCtx.PublishMsgAsync(msg)
+		if err != nil {
+			s.retry(msg, err)
+			continue
+		}
+		s.pending <- pendingMsg{msg: msg, future: future}
+	}
+}
+
+// handleAcks waits for the acknowledgements of the published events and retries the failed ones.
+func (s *AsyncSender) handleAcks() {
+	defer close(s.done)
+	for p := range s.pending {
+		select {
+		case <-p.future.Ok():
+		case err := <-p.future.Err():
+			s.retry(p.msg, err)
+		case <-time.After(s.ackTimeout):
+			s.retry(p.msg, nats.ErrTimeout)
+		}
+	}
+}
+
+// retry publishes the event again until it is acknowledged, the error is not transient,
+// or the AsyncSender fails to drain the events on Close.
+func (s *AsyncSender) retry(msg *nats.Msg, err error) {
+	s.recordPublishError(err)
+	for isTransientError(err) {
+		s.msgLogger(msg).Debugw("Retrying to publish event", "subject", msg.Subject, "error", err)
+		select {
+		case <-s.ctx.Done():
+			s.msgLogger(msg).Errorw("Dropped event on shutdown", "subject", msg.Subject, "error", err)
+			return
+		case <-time.After(s.retryInterval):
+		}
+		if _, err = s.jsCtx.PublishMsg(msg); err == nil {
+			return
+		}
+		s.recordPublishError(err)
+	}
+	s.msgLogger(msg).Errorw("Dropped event", "subject", msg.Subject, "error", err)
+}
+
+// isTransientError reports whether publishing an event again might succeed after the given error.
+func isTransientError(err error) bool {
+	return errors.Is(err, nats.ErrNoStreamResponse) ||
+		errors.Is(err, nats.ErrNoResponders) ||
+		errors.Is(err, nats.ErrTimeout) ||
+		errors.Is(err, nats.ErrDisconnected) ||
+		errors.Is(err, nats.ErrConnectionReconnecting) ||
+		errors.Is(err, nats.ErrConnectionClosed)
+}
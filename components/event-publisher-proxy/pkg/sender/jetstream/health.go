@@ -1,18 +1,122 @@
 package jetstream
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
+
+	"github.com/nats-io/nats.go"
 
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/handler/health"
 )
 
-// ReadinessCheck returns an instance of http.HandlerFunc that checks the readiness of the given NATS Handler.
-// It checks the NATS server connection status and reports 2XX if connected, otherwise reports 5XX.
-// It panics if the given NATS Handler is nil.
+// compile time check.
+var _ health.StatusReporter = &Sender{}
+
+// Status is the detailed status of the NATS JetStream backend reported by the status endpoint.
+type Status struct {
+	// Ready reports whether the Sender is connected to NATS and the stream exists.
+	Ready bool `json:"ready"`
+	// Connection is the status of the NATS connection, such as CONNECTED or RECONNECTING.
+	Connection string `json:"connection"`
+	// Stream is the status of the stream the events are published to.
+	Stream StreamStatus `json:"stream"`
+	// LastPublishError is the most recent error publishing an event, nil if no publishing failed yet.
+	LastPublishError *PublishErrorStatus `json:"lastPublishError,omitempty"`
+}
+
+// StreamStatus is the status of the stream the events are published to.
+type StreamStatus struct {
+	Name     string `json:"name"`
+	Found    bool   `json:"found"`
+	Messages uint64 `json:"messages"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PublishErrorStatus is an error publishing an event.
+type PublishErrorStatus struct {
+	Error string    `json:"error"`
+	Time  time.Time `json:"time"`
+}
+
+// ReadinessCheck checks the readiness of the Sender.
+// It reports 2XX if the Sender is connected to NATS and the stream exists, otherwise reports 5XX.
 func (s *Sender) ReadinessCheck(w http.ResponseWriter, _ *http.Request) {
+	if !s.Status().Ready {
+		w.WriteHeader(health.StatusCodeNotHealthy)
+		return
+	}
 	w.WriteHeader(health.StatusCodeHealthy)
 }
 
+// LivenessCheck always reports 2XX, as restarting the Sender does not help if NATS is not available.
 func (s *Sender) LivenessCheck(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(health.StatusCodeHealthy)
 }
+
+// StatusCheck writes the detailed Status of the Sender as JSON, with the status code of the ReadinessCheck.
+func (s *Sender) StatusCheck(w http.ResponseWriter, _ *http.Request) {
+	status := s.Status()
+	statusCode := health.StatusCodeHealthy
+	if !status.Ready {
+		statusCode = health.StatusCodeNotHealthy
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		s.namedLogger().Errorw("Failed to write status", "error", err)
+	}
+}
+
+// Status returns the detailed Status of the Sender.
+func (s *Sender) Status() Status {
+	connectionStatus := s.ConnectionStatus()
+	status := Status{
+		Connection:       connectionStatus.String(),
+		Stream:           StreamStatus{Name: s.envCfg.JSStreamName},
+		LastPublishError: s.lastPublishError(),
+	}
+	if connectionStatus != nats.CONNECTED {
+		status.Stream.Error = ErrNotConnected.Error()
+		return status
+	}
+	status.Stream = s.streamStatus()
+	status.Ready = status.Stream.Found
+	return status
+}
+
+// streamStatus returns the status of the stream the events are published to.
+func (s *Sender) streamStatus() StreamStatus {
+	status := StreamStatus{Name: s.envCfg.JSStreamName}
+	jsCtx, err := s.connection.JetStream()
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	info, err := jsCtx.StreamInfo(s.envCfg.JSStreamName)
+	if err != nil {
+		if !errors.Is(err, nats.ErrStreamNotFound) {
+			s.namedLogger().Warnw("Failed to get stream info", "stream", s.envCfg.JSStreamName, "error", err)
+		}
+		status.Error = err.Error()
+		return status
+	}
+	status.Found = true
+	status.Messages = info.State.Msgs
+	return status
+}
+
+// recordPublishError records the given error as the most recent error publishing an event.
+func (s *Sender) recordPublishError(err error) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.lastPublishErr = &PublishErrorStatus{Error: err.Error(), Time: time.Now()}
+}
+
+// lastPublishError returns the most recent error publishing an event, nil if no publishing failed yet.
+func (s *Sender) lastPublishError() *PublishErrorStatus {
+	s.statusMu.RLock()
+	defer s.statusMu.RUnlock()
+	return s.lastPublishErr
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/nats-io/nats.go"
 
@@ -20,12 +21,15 @@ import (
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/internal"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/env"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/idempotency"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/sender/common"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/tracing"
 )
 
 const (
 	JSStoreFailedCode     = 10077
+	JSMessageTooLargeCode = 10054
 	natsBackend           = "nats"
 	handlerName           = "jetstream-handler"
 	noSpaceLeftErrMessage = "no space left on device"
@@ -40,6 +44,8 @@ var (
 	ErrNotConnected        = common.BackendPublishError{HTTPCode: http.StatusBadGateway, Info: "no connection to NATS JetStream server"}
 	ErrCannotSendToStream  = common.BackendPublishError{HTTPCode: http.StatusGatewayTimeout, Info: "cannot send to stream"}
 	ErrNoSpaceLeftOnDevice = common.BackendPublishError{HTTPCode: http.StatusInsufficientStorage, Info: "insufficient resources on target stream"}
+	ErrBufferFull          = common.BackendPublishError{HTTPCode: http.StatusServiceUnavailable, Info: "publish buffer is full"}
+	ErrPayloadTooLarge     = common.BackendPublishError{HTTPCode: http.StatusRequestEntityTooLarge, Info: "event exceeds the maximum message size"}
 )
 
 // Sender is responsible for sending messages over HTTP.
@@ -49,6 +55,9 @@ type Sender struct {
 	connection *nats.Conn
 	envCfg     *env.NATSConfig
 	opts       *options.Options
+
+	statusMu       sync.RWMutex
+	lastPublishErr *PublishErrorStatus
 }
 
 func (s *Sender) URL() string {
@@ -69,18 +78,19 @@ func (s *Sender) ConnectionStatus() nats.Status {
 // If the NATS connection is not open, it returns an error.
 func (s *Sender) Send(_ context.Context, event *event.Event) sender.PublishError {
 	if s.ConnectionStatus() != nats.CONNECTED {
+		s.recordPublishError(ErrNotConnected)
 		return ErrNotConnected
 	}
 
 	jsCtx, err := s.connection.JetStream()
 	if err != nil {
-		s.namedLogger().Error("error", err)
+		s.eventLogger(event).Error("error", err)
 		return common.ErrClientNoConnection
 	}
 
 	msg, err := s.eventToNATSMsg(event)
 	if err != nil {
-		s.namedLogger().Error("error", err)
+		s.eventLogger(event).Error("error", err)
 		e := common.ErrClientConversionFailed
 		e.Wrap(err)
 		return e
@@ -89,17 +99,43 @@ func (s *Sender) Send(_ context.Context, event *event.Event) sender.PublishError
 	// send the event
 	_, err = jsCtx.PublishMsg(msg)
 	if err != nil {
-		s.namedLogger().Errorw("Cannot send event to backend", "error", err)
+		s.eventLogger(event).Errorw("Cannot send event to backend", "error", err)
+		s.recordPublishError(err)
 		return natsErrorToPublishError(err)
 	}
 	return nil
 }
 
+// MaxMessageSize returns the maximum size of the messages accepted by both the NATS server and the stream.
+// If the stream does not exist yet, it returns the maximum payload of the NATS server.
+func (s *Sender) MaxMessageSize() (int64, error) {
+	size := s.connection.MaxPayload()
+	jsCtx, err := s.connection.JetStream()
+	if err != nil {
+		return 0, err
+	}
+	info, err := jsCtx.StreamInfo(s.envCfg.JSStreamName)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		return size, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if maxMsgSize := int64(info.Config.MaxMsgSize); maxMsgSize > 0 && maxMsgSize < size {
+		size = maxMsgSize
+	}
+	return size, nil
+}
+
 func natsErrorToPublishError(err error) sender.PublishError {
 	if errors.Is(err, nats.ErrNoStreamResponse) {
 		return ErrCannotSendToStream
 	}
 
+	if errors.Is(err, nats.ErrMaxPayload) {
+		return ErrPayloadTooLarge
+	}
+
 	if strings.Contains(err.Error(), noSpaceLeftErrMessage) {
 		return ErrNoSpaceLeftOnDevice
 	}
@@ -110,6 +146,9 @@ func natsErrorToPublishError(err error) sender.PublishError {
 		if apiErr.APIError().ErrorCode == JSStoreFailedCode {
 			return ErrNoSpaceLeftOnDevice
 		}
+		if apiErr.APIError().ErrorCode == JSMessageTooLargeCode {
+			return ErrPayloadTooLarge
+		}
 		e.HTTPCode = apiErr.APIError().Code
 		e.Info = apiErr.APIError().Description
 		e.Wrap(err)
@@ -126,14 +165,21 @@ func (s *Sender) eventToNATSMsg(event *event.Event) (*nats.Msg, error) {
 	header.Set(internal.CeTypeHeader, event.Type())
 	header.Set(internal.CeSourceHeader, event.Source())
 	header.Set(internal.CeIDHeader, event.ID())
+	header.Set(internal.CeCorrelationIDHeader, tracing.CorrelationID(event))
 
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
 		return nil, err
 	}
 
+	subject := s.getJsSubjectToPublish(event.Type())
+	// deduplicate the events published again with the same idempotency key
+	if key, ok := event.Extensions()[idempotency.KeyExtension].(string); ok && key != "" {
+		header.Set(nats.MsgIdHdr, fmt.Sprintf("%s/%s", subject, key))
+	}
+
 	return &nats.Msg{
-		Subject: s.getJsSubjectToPublish(event.Type()),
+		Subject: subject,
 		Header:  header,
 		Data:    eventJSON,
 	}, err
@@ -153,3 +199,13 @@ func (s *Sender) getJsSubjectToPublish(subject string) string {
 func (s *Sender) namedLogger() *zap.SugaredLogger {
 	return s.logger.WithContext().Named(handlerName).With("backend", natsBackend, "jetstream enabled", true)
 }
+
+// eventLogger returns the named logger decorated with the correlation ID of the given event.
+func (s *Sender) eventLogger(event *event.Event) *zap.SugaredLogger {
+	return s.namedLogger().With(tracing.CorrelationIDLogKey, tracing.CorrelationID(event))
+}
+
+// msgLogger returns the named logger decorated with the correlation ID of the given message.
+func (s *Sender) msgLogger(msg *nats.Msg) *zap.SugaredLogger {
+	return s.namedLogger().With(tracing.CorrelationIDLogKey, msg.Header.Get(internal.CeCorrelationIDHeader))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -20,7 +22,11 @@ import (
 	"github.com/nats-io/nats.go"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/internal"
 	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/env"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/handler/health"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/idempotency"
+	"github.com/kyma-project/kyma/components/event-publisher-proxy/pkg/tracing"
 	testingutils "github.com/kyma-project/kyma/components/event-publisher-proxy/testing"
 )
 
@@ -29,6 +35,7 @@ func TestJetStreamMessageSender(t *testing.T) {
 		name                      string
 		givenStream               bool
 		givenStreamMaxBytes       int64
+		givenStreamMaxMsgSize     int32
 		givenNATSConnectionClosed bool
 		wantErr                   error
 		wantStatusCode            int
@@ -46,6 +53,14 @@ func TestJetStreamMessageSender(t *testing.T) {
 			givenNATSConnectionClosed: false,
 			wantErr:                   ErrNoSpaceLeftOnDevice,
 		},
+		{
+			name:                      "send in jetstream mode should not succeed if event exceeds the maximum message size",
+			givenStream:               true,
+			givenStreamMaxBytes:       5000,
+			givenStreamMaxMsgSize:     10,
+			givenNATSConnectionClosed: false,
+			wantErr:                   ErrPayloadTooLarge,
+		},
 		{
 			name:                      "send in jetstream mode should succeed if NATS connection is open and the stream exists",
 			givenStream:               true,
@@ -74,6 +89,7 @@ func TestJetStreamMessageSender(t *testing.T) {
 
 			if tc.givenStream {
 				sc := getStreamConfig(tc.givenStreamMaxBytes)
+				sc.MaxMsgSize = tc.givenStreamMaxMsgSize
 				cc := getConsumerConfig()
 				addStream(t, connection, sc)
 				addConsumer(t, connection, sc, cc)
@@ -99,6 +115,334 @@ func TestJetStreamMessageSender(t *testing.T) {
 	}
 }
 
+func TestSender_MaxMessageSize(t *testing.T) {
+	// arrange
+	testEnv := setupTestEnvironment(t)
+	natsServer, connection := testEnv.Server, testEnv.Connection
+	defer func() {
+		natsServer.Shutdown()
+		connection.Close()
+	}()
+
+	// act, assert the maximum payload of the NATS server without stream
+	size, err := testEnv.Sender.MaxMessageSize()
+	require.NoError(t, err)
+	assert.Equal(t, connection.MaxPayload(), size)
+
+	// act, assert the maximum message size of the stream
+	sc := getStreamConfig(5000)
+	sc.MaxMsgSize = 1024
+	addStream(t, connection, sc)
+	size, err = testEnv.Sender.MaxMessageSize()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024), size)
+}
+
+func TestSender_Status(t *testing.T) {
+	// arrange
+	testEnv := setupTestEnvironment(t)
+	natsServer, connection := testEnv.Server, testEnv.Connection
+	defer func() {
+		natsServer.Shutdown()
+		connection.Close()
+	}()
+
+	// act, assert not ready without stream
+	status := testEnv.Sender.Status()
+	assert.False(t, status.Ready)
+	assert.Equal(t, nats.CONNECTED.String(), status.Connection)
+	assert.False(t, status.Stream.Found)
+	assert.NotEmpty(t, status.Stream.Error)
+	assert.Equal(t, health.StatusCodeNotHealthy, readinessStatusCode(testEnv.Sender))
+
+	// act, assert ready with stream
+	addStream(t, connection, getStreamConfig(5000))
+	status = testEnv.Sender.Status()
+	assert.True(t, status.Ready)
+	assert.True(t, status.Stream.Found)
+	assert.Empty(t, status.Stream.Error)
+	assert.Nil(t, status.LastPublishError)
+	assert.Equal(t, health.StatusCodeHealthy, readinessStatusCode(testEnv.Sender))
+
+	// act, assert not ready and the publish error reported without connection
+	natsServer.Shutdown()
+	require.Eventually(t, func() bool {
+		return connection.Status() != nats.CONNECTED
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, testEnv.Sender.Send(context.Background(), createCloudEvent(t)), ErrNotConnected)
+	writer := httptest.NewRecorder()
+	testEnv.Sender.StatusCheck(writer, httptest.NewRequest(http.MethodGet, health.StatusURI, nil))
+	assert.Equal(t, health.StatusCodeNotHealthy, writer.Code)
+	status = Status{}
+	require.NoError(t, json.NewDecoder(writer.Body).Decode(&status))
+	assert.False(t, status.Ready)
+	assert.NotEqual(t, nats.CONNECTED.String(), status.Connection)
+	require.NotNil(t, status.LastPublishError)
+	assert.Equal(t, ErrNotConnected.Error(), status.LastPublishError.Error)
+	assert.Equal(t, health.StatusCodeNotHealthy, readinessStatusCode(testEnv.Sender))
+}
+
+func TestSender_eventToNATSMsg_IdempotencyKey(t *testing.T) {
+	// given
+	sender := &Sender{envCfg: CreateNATSJsConfig("")}
+	ce := createCloudEvent(t)
+
+	// when
+	msg, err := sender.eventToNATSMsg(ce)
+
+	// then the events without idempotency key are not deduplicated
+	require.NoError(t, err)
+	assert.Empty(t, msg.Header.Get(nats.MsgIdHdr))
+
+	// when
+	ce.SetExtension(idempotency.KeyExtension, "key1")
+	msg, err = sender.eventToNATSMsg(ce)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, msg.Subject+"/key1", msg.Header.Get(nats.MsgIdHdr))
+}
+
+func TestSender_eventToNATSMsg_CorrelationID(t *testing.T) {
+	// given
+	sender := &Sender{envCfg: CreateNATSJsConfig("")}
+	ce := createCloudEvent(t)
+
+	// when
+	msg, err := sender.eventToNATSMsg(ce)
+
+	// then the CloudEvent ID is the default correlation ID
+	require.NoError(t, err)
+	assert.Equal(t, ce.ID(), msg.Header.Get(internal.CeCorrelationIDHeader))
+
+	// when
+	ce.SetExtension(tracing.CorrelationIDCEExtensionsKey, "order-42")
+	msg, err = sender.eventToNATSMsg(ce)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "order-42", msg.Header.Get(internal.CeCorrelationIDHeader))
+}
+
+func TestSender_Tail(t *testing.T) {
+	// arrange
+	testEnv := setupTestEnvironment(t)
+	natsServer, connection := testEnv.Server, testEnv.Connection
+	defer func() {
+		natsServer.Shutdown()
+		connection.Close()
+	}()
+	sc := getStreamConfig(5000)
+	addStream(t, connection, sc)
+	addConsumer(t, connection, sc, getConsumerConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tailed := make(chan *event.Event, 10)
+	tailErr := make(chan error, 1)
+	go func() {
+		tailErr <- testEnv.Sender.Tail(ctx, "", func(e *event.Event) { tailed <- e })
+	}()
+
+	// act
+	ce := createCloudEvent(t)
+	var got *event.Event
+	require.Eventually(t, func() bool {
+		require.Nil(t, testEnv.Sender.Send(context.Background(), ce))
+		select {
+		case got = <-tailed:
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+
+	// assert
+	assert.Equal(t, ce.ID(), got.ID())
+	assert.Equal(t, ce.Type(), got.Type())
+	assert.NoError(t, <-tailErr)
+	info, err := (*testEnv.JsContext).StreamInfo(sc.Name)
+	require.NoError(t, err)
+	assert.NotZero(t, info.State.Msgs, "tailing must not remove events from the stream")
+}
+
+func TestSender_TailEventType(t *testing.T) {
+	// arrange
+	testEnv := setupTestEnvironment(t)
+	natsServer, connection := testEnv.Server, testEnv.Connection
+	defer func() {
+		natsServer.Shutdown()
+		connection.Close()
+	}()
+	sc := getStreamConfig(5000)
+	addStream(t, connection, sc)
+	addConsumer(t, connection, sc, getConsumerConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tailed := make(chan *event.Event, 10)
+	tailErr := make(chan error, 1)
+	other := createCloudEvent(t)
+	other.SetType(other.Type() + "other")
+	ce := createCloudEvent(t)
+	go func() {
+		tailErr <- testEnv.Sender.Tail(ctx, ce.Type(), func(e *event.Event) { tailed <- e })
+	}()
+
+	// act
+	var got *event.Event
+	require.Eventually(t, func() bool {
+		require.Nil(t, testEnv.Sender.Send(context.Background(), other))
+		require.Nil(t, testEnv.Sender.Send(context.Background(), ce))
+		select {
+		case got = <-tailed:
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+
+	// assert only the events of the type are read from the stream
+	assert.Equal(t, ce.Type(), got.Type())
+	assert.NoError(t, <-tailErr)
+	for len(tailed) > 0 {
+		assert.Equal(t, ce.Type(), (<-tailed).Type())
+	}
+}
+
+func TestAsyncSender_SendAsync(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenStreamAdded bool
+	}{
+		{
+			name:             "should publish the event to the stream",
+			givenStreamAdded: true,
+		},
+		{
+			name:             "should publish the event again until the stream is added",
+			givenStreamAdded: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			testEnv := setupTestEnvironment(t)
+			natsServer, connection := testEnv.Server, testEnv.Connection
+			defer func() {
+				natsServer.Shutdown()
+				connection.Close()
+			}()
+			sc := getStreamConfig(5000)
+			sc.Retention = nats.LimitsPolicy
+			if tc.givenStreamAdded {
+				addStream(t, connection, sc)
+			}
+			asyncSender, err := NewAsyncSender(testEnv.Sender, 10)
+			require.NoError(t, err)
+			asyncSender.retryInterval = 10 * time.Millisecond
+			asyncSender.ackTimeout = 100 * time.Millisecond
+
+			// act
+			require.Nil(t, asyncSender.SendAsync(createCloudEvent(t)))
+			if !tc.givenStreamAdded {
+				// let the first publish time out
+				time.Sleep(2 * asyncSender.ackTimeout)
+				addStream(t, connection, sc)
+			}
+
+			// assert
+			require.Eventually(t, func() bool {
+				info, err := (*testEnv.JsContext).StreamInfo(sc.Name)
+				return err == nil && info.State.Msgs == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			asyncSender.Close()
+		})
+	}
+}
+
+func TestAsyncSender_Close(t *testing.T) {
+	// arrange
+	testEnv := setupTestEnvironment(t)
+	defer func() {
+		testEnv.Server.Shutdown()
+		testEnv.Connection.Close()
+	}()
+	sc := getStreamConfig(5000)
+	sc.Retention = nats.LimitsPolicy
+	asyncSender, err := NewAsyncSender(testEnv.Sender, 10)
+	require.NoError(t, err)
+	asyncSender.retryInterval = 10 * time.Millisecond
+	asyncSender.ackTimeout = 100 * time.Millisecond
+	require.Nil(t, asyncSender.SendAsync(createCloudEvent(t)))
+
+	// act, the stream is added while the sender is closed
+	closed := make(chan struct{})
+	go func() {
+		asyncSender.Close()
+		close(closed)
+	}()
+	time.Sleep(2 * asyncSender.ackTimeout)
+	addStream(t, testEnv.Connection, sc)
+
+	// assert the buffered event failing with a transient error is published before the sender is closed
+	select {
+	case <-closed:
+	case <-time.After(asyncDrainTimeout):
+		t.Fatal("the async sender was not closed within the drain timeout")
+	}
+	info, err := (*testEnv.JsContext).StreamInfo(sc.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), info.State.Msgs)
+	assert.ErrorIs(t, asyncSender.SendAsync(createCloudEvent(t)), ErrNotConnected)
+}
+
+func TestAsyncSender_Close_DrainTimeout(t *testing.T) {
+	// arrange
+	testEnv := setupTestEnvironment(t)
+	defer func() {
+		testEnv.Server.Shutdown()
+		testEnv.Connection.Close()
+	}()
+	asyncSender, err := NewAsyncSender(testEnv.Sender, 10)
+	require.NoError(t, err)
+	asyncSender.retryInterval = 10 * time.Millisecond
+	asyncSender.ackTimeout = 100 * time.Millisecond
+	asyncSender.drainTimeout = 300 * time.Millisecond
+	require.Nil(t, asyncSender.SendAsync(createCloudEvent(t)))
+
+	// act, the stream is never added
+	start := time.Now()
+	asyncSender.Close()
+
+	// assert the sender stops retrying after the drain timeout
+	assert.Less(t, time.Since(start), asyncDrainTimeout)
+	assert.Eventually(t, func() bool {
+		select {
+		case <-asyncSender.done:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestAsyncSender_SendAsync_BufferFull(t *testing.T) {
+	// arrange
+	testEnv := setupTestEnvironment(t)
+	defer func() {
+		testEnv.Server.Shutdown()
+		testEnv.Connection.Close()
+	}()
+	asyncSender := &AsyncSender{Sender: testEnv.Sender, queue: make(chan *nats.Msg, 1)}
+
+	// act, assert
+	require.Nil(t, asyncSender.SendAsync(createCloudEvent(t)))
+	assert.ErrorIs(t, asyncSender.SendAsync(createCloudEvent(t)), ErrBufferFull)
+}
+
 // helper functions and structs
 
 type TestEnvironment struct {
@@ -143,6 +487,13 @@ func setupTestEnvironment(t *testing.T) *TestEnvironment {
 	}
 }
 
+// readinessStatusCode returns the status code of the readiness check of the given Sender.
+func readinessStatusCode(s *Sender) int {
+	writer := httptest.NewRecorder()
+	s.ReadinessCheck(writer, httptest.NewRequest(http.MethodGet, health.ReadinessURI, nil))
+	return writer.Code
+}
+
 // createCloudEvent build a cloud event.
 func createCloudEvent(t *testing.T) *event.Event {
 	jsType := fmt.Sprintf("%s.%s", testingutils.StreamName, testingutils.CloudEventTypeWithPrefix)
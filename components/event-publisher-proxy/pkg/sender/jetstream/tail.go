@@ -0,0 +1,63 @@
+package jetstream
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/nats-io/nats.go"
+)
+
+// tailBufferSize is the number of tailed events buffered for a slow client before further events are dropped.
+const tailBufferSize = 100
+
+// Tail calls fn for every event published to the stream with the given event type until the given context is done.
+// The event type is the type of the published events and may contain `*` tokens, all events are tailed if it is empty.
+// The events are read by an ephemeral ordered consumer starting with the next published event,
+// so tailing neither acknowledges nor removes any event from the stream.
+func (s *Sender) Tail(ctx context.Context, eventType string, fn func(*event.Event)) error {
+	if s.ConnectionStatus() != nats.CONNECTED {
+		return ErrNotConnected
+	}
+
+	jsCtx, err := s.connection.JetStream()
+	if err != nil {
+		return err
+	}
+
+	// the stream filters the events by the subject they are published to, instead of sending all of them
+	subject := ""
+	if eventType != "" {
+		subject = s.getJsSubjectToPublish(eventType)
+	}
+	events := make(chan *event.Event, tailBufferSize)
+	sub, err := jsCtx.Subscribe(subject, func(msg *nats.Msg) {
+		e := event.New()
+		if err := json.Unmarshal(msg.Data, &e); err != nil {
+			s.namedLogger().Debugw("Cannot decode tailed event", "subject", msg.Subject, "error", err)
+			return
+		}
+		select {
+		case events <- &e:
+		default:
+			s.namedLogger().Debugw("Dropped tailed event for slow client", "id", e.ID())
+		}
+	}, nats.BindStream(s.envCfg.JSStreamName), nats.OrderedConsumer(), nats.DeliverNew())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := sub.Unsubscribe(); err != nil {
+			s.namedLogger().Debugw("Cannot unsubscribe tail consumer", "error", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-events:
+			fn(e)
+		}
+	}
+}
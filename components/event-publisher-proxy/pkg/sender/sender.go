@@ -11,6 +11,11 @@ type GenericSender interface {
 	URL() string
 }
 
+// AsyncSender accepts events to be published in the background.
+type AsyncSender interface {
+	SendAsync(*event.Event) PublishError
+}
+
 type PublishError interface {
 	error
 	Code() int
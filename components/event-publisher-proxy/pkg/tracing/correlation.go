@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"net/http"
+
+	cev2 "github.com/cloudevents/sdk-go/v2/event"
+)
+
+const (
+	// CorrelationIDHeader is the request header of the correlation ID chosen by the publisher.
+	CorrelationIDHeader = "X-Correlation-ID"
+	// CorrelationIDCEExtensionsKey is the CloudEvent extension carrying the correlation ID chosen by the publisher
+	// to the eventing-controller.
+	CorrelationIDCEExtensionsKey = "correlationid"
+	// CorrelationIDLogKey is the key of the correlation ID in the log lines of an event.
+	CorrelationIDLogKey = "correlationid"
+)
+
+// AddCorrelationIDToCEExtensions sets the correlation ID of the request headers on the event,
+// unless the event already has one.
+func AddCorrelationIDToCEExtensions(reqHeaders http.Header, event *cev2.Event) {
+	if _, ok := event.Extensions()[CorrelationIDCEExtensionsKey]; ok {
+		return
+	}
+	if correlationID := reqHeaders.Get(CorrelationIDHeader); correlationID != "" {
+		event.SetExtension(CorrelationIDCEExtensionsKey, correlationID)
+	}
+}
+
+// CorrelationID returns the correlation ID of the event, which is the CloudEvent ID
+// unless the publisher has chosen a different one.
+func CorrelationID(event *cev2.Event) string {
+	if correlationID, ok := event.Extensions()[CorrelationIDCEExtensionsKey].(string); ok && correlationID != "" {
+		return correlationID
+	}
+	return event.ID()
+}
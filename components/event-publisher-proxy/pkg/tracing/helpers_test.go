@@ -68,3 +68,50 @@ func TestAddTracingContextToCEExtensions(t *testing.T) {
 		})
 	}
 }
+
+func TestAddCorrelationIDToCEExtensions(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	testCases := []struct {
+		name                  string
+		headers               http.Header
+		givenCorrelationID    string
+		expectedCorrelationID string
+	}{
+		{
+			name:                  "event without correlation ID",
+			headers:               http.Header{},
+			expectedCorrelationID: "id",
+		}, {
+			name:                  "request with correlation ID",
+			headers:               correlationIDHeader("order-42"),
+			expectedCorrelationID: "order-42",
+		}, {
+			name:                  "event with correlation ID",
+			headers:               correlationIDHeader("order-42"),
+			givenCorrelationID:    "order-43",
+			expectedCorrelationID: "order-43",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			event := cev2event.New()
+			event.SetID("id")
+			if tc.givenCorrelationID != "" {
+				event.SetExtension(CorrelationIDCEExtensionsKey, tc.givenCorrelationID)
+			}
+			AddCorrelationIDToCEExtensions(tc.headers, &event)
+			g.Expect(CorrelationID(&event)).To(Equal(tc.expectedCorrelationID))
+		})
+	}
+}
+
+func correlationIDHeader(correlationID string) http.Header {
+	headers := http.Header{}
+	headers.Set(CorrelationIDHeader, correlationID)
+	return headers
+}
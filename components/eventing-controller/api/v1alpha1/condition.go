@@ -17,6 +17,8 @@ const (
 
 	ConditionPublisherProxyReady ConditionType = "Publisher Proxy Ready"
 	ConditionControllerReady     ConditionType = "Subscription Controller Ready"
+
+	ConditionEventTypeRegistered ConditionType = "Event type registered"
 )
 
 var allSubscriptionConditions = MakeSubscriptionConditions()
@@ -63,6 +65,14 @@ const (
 	ConditionReasonControllerStopFailed           ConditionReason = "Stopping the controller failed"
 	ConditionReasonPublisherProxySecretError      ConditionReason = "Publisher proxy secret sync failed"
 	ConditionDuplicateSecrets                     ConditionReason = "Multiple eventing backend labeled secrets exist"
+	ConditionReasonBackendSecretNotFound          ConditionReason = "Eventing backend secret not found"
+	ConditionReasonDispatchPaused                 ConditionReason = "Event dispatch paused"
+	ConditionReasonPublisherNotRequired           ConditionReason = "Publisher proxy not required"
+
+	// EventType Conditions.
+	ConditionReasonEventTypeRegistered ConditionReason = "Event type registered"
+	ConditionReasonEventTypeInvalid    ConditionReason = "Event type invalid"
+	ConditionReasonEventTypeDuplicate  ConditionReason = "Event type duplicate"
 )
 
 // initializeConditions sets unset conditions to Unknown.
@@ -4,24 +4,71 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// +kubebuilder:validation:Enum=BEB;NATS
+// +kubebuilder:validation:Enum=BEB;NATS;Kafka
 
 type BackendType string
 
 const (
-	BEBBackendType  BackendType = "BEB"
-	NatsBackendType BackendType = "NATS"
+	BEBBackendType   BackendType = "BEB"
+	NatsBackendType  BackendType = "NATS"
+	KafkaBackendType BackendType = "Kafka"
 )
 
 // EventingBackendSpec defines the desired state of EventingBackend.
 type EventingBackendSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Selects the active backend. The value is either `BEB`, `NATS`, or `Kafka`.
+	// If not set, BEB is used if the BEB Secret is referenced or a Secret with the
+	// `kyma-project.io/eventing-backend: beb` label exists, otherwise NATS is used.
+	// +optional
+	Type BackendType `json:"type,omitempty"`
+
+	// Connection parameters of the NATS backend. They take precedence over the environment configuration.
+	// +optional
+	NATS *NATSBackendSpec `json:"nats,omitempty"`
+
+	// Connection parameters of the BEB backend.
+	// +optional
+	BEB *BEBBackendSpec `json:"beb,omitempty"`
+
+	// Connection parameters of the Kafka backend. They take precedence over the environment configuration.
+	// +optional
+	Kafka *KafkaBackendSpec `json:"kafka,omitempty"`
+
+	// Pauses the dispatch of events to the sinks during maintenance windows of the NATS or Kafka backend.
+	// The events are retained in the stream or topics and dispatched once the dispatch is resumed.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// NATSBackendSpec defines the connection parameters of the NATS backend.
+type NATSBackendSpec struct {
+	// URL of the NATS server.
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// KafkaBackendSpec defines the connection parameters of the Kafka backend.
+type KafkaBackendSpec struct {
+	// Addresses of the Kafka bootstrap brokers.
+	// +optional
+	Brokers []string `json:"brokers,omitempty"`
+}
+
+// BEBBackendSpec defines the connection parameters of the BEB backend.
+type BEBBackendSpec struct {
+	// Name of the Secret containing the BEB access tokens.
+	// If not set, the Secret with the `kyma-project.io/eventing-backend: beb` label is used.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Namespace of the Secret containing the BEB access tokens.
+	// +optional
+	SecretNamespace string `json:"secretNamespace,omitempty"`
 }
 
 // EventingBackendStatus defines the observed state of EventingBackend.
 type EventingBackendStatus struct {
-	// Specifies the backend type used. The value is either `BEB`, or `NATS`.
+	// Specifies the backend type used. The value is either `BEB`, `NATS`, or `Kafka`.
 	// +optional
 	Backend BackendType `json:"backendType"`
 
@@ -40,6 +87,49 @@ type EventingBackendStatus struct {
 	// Namespace of the Secret containing BEB access tokens, required for BEB only.
 	// +optional
 	BEBSecretNamespace string `json:"bebSecretNamespace,omitempty"`
+
+	// Aggregated health of the Subscriptions and the stream of the backend.
+	// +optional
+	Summary *EventingSummary `json:"summary,omitempty"`
+}
+
+// EventingSummary aggregates the eventing health of the cluster.
+type EventingSummary struct {
+	// Number of Subscriptions by readiness.
+	Subscriptions SubscriptionSummary `json:"subscriptions"`
+
+	// Usage of the JetStream stream, NATS only.
+	// +optional
+	Stream *StreamUsage `json:"stream,omitempty"`
+
+	// Latest errors reported by the Subscriptions which are not ready, newest first.
+	// +optional
+	RecentErrors []string `json:"recentErrors,omitempty"`
+
+	// Defines the date of the last summary update.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// SubscriptionSummary defines the number of Subscriptions by readiness.
+type SubscriptionSummary struct {
+	Total    int `json:"total"`
+	Ready    int `json:"ready"`
+	NotReady int `json:"notReady"`
+}
+
+// StreamUsage defines the usage of the JetStream stream.
+type StreamUsage struct {
+	// Name of the stream.
+	Name string `json:"name"`
+
+	// Number of messages stored in the stream.
+	Messages int64 `json:"messages"`
+
+	// Number of bytes stored in the stream.
+	Bytes int64 `json:"bytes"`
+
+	// Number of consumers of the stream.
+	Consumers int `json:"consumers"`
 }
 
 // +kubebuilder:object:root=true
@@ -48,6 +138,7 @@ type EventingBackendStatus struct {
 // +kubebuilder:printcolumn:name="EventingReady",type=boolean,JSONPath=`.status.eventingReady`
 // +kubebuilder:printcolumn:name="SubscriptionControllerReady",type=string,JSONPath=`.status.conditions[?(@.type=="Subscription Controller Ready")].status`
 // +kubebuilder:printcolumn:name="PublisherProxyReady",type=string,JSONPath=`.status.conditions[?(@.type=="Publisher Proxy Ready")].status`
+// +kubebuilder:printcolumn:name="SubscriptionsNotReady",type=integer,JSONPath=`.status.summary.subscriptions.notReady`,priority=1
 
 // EventingBackend is the Schema for the eventingbackends API.
 type EventingBackend struct {
@@ -0,0 +1,74 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EventTypeSpec defines the event type published to Eventing.
+type EventTypeSpec struct {
+	// Type of the published events, as in their CloudEvents `type` attribute.
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+
+	// Source of the published events, as in their CloudEvents `source` attribute.
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+
+	// Absolute URI of the schema of the event data, as in the CloudEvents `dataschema` attribute.
+	// +optional
+	SchemaRef string `json:"schemaRef,omitempty"`
+
+	// Application or team publishing the events.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// Human-readable description of the events.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// EventTypeStatus defines the observed state of the EventType.
+type EventTypeStatus struct {
+	// Current state of the EventType.
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// Overall readiness of the EventType. An EventType is ready if it is valid and no older EventType
+	// of the Namespace has the same type and source.
+	Ready bool `json:"ready"`
+
+	// Names of the Subscriptions of the Namespace subscribing to the event type.
+	// +optional
+	Subscriptions []string `json:"subscriptions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.source"
+// +kubebuilder:printcolumn:name="Owner",type="string",JSONPath=".spec.owner"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// EventType registers an event type in the catalog of its Namespace, so that the Subscriptions of the Namespace
+// are validated against the catalog and the available event types can be discovered.
+type EventType struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EventTypeSpec   `json:"spec,omitempty"`
+	Status EventTypeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EventTypeList contains a list of EventType.
+type EventTypeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EventType `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&EventType{}, &EventTypeList{})
+}
@@ -1,13 +1,16 @@
 package v1alpha1
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/eventtype"
 
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
@@ -16,8 +19,50 @@ import (
 const (
 	ErrorHubVersionMsg     = "hub version is not the expected v1alpha2 version"
 	ErrorMultipleSourceMsg = "subscription contains more than 1 eventSource"
+
+	// V1Alpha1FilterAnnotation keeps the v1alpha1 filter on the v1alpha2 Subscription
+	// if it cannot be restored from the v1alpha2 source and types.
+	V1Alpha1FilterAnnotation = "eventing.kyma-project.io/v1alpha1-filter"
+	// V1Alpha2SpecAnnotation keeps the v1alpha2 spec fields without a v1alpha1 equivalent on the v1alpha1 Subscription.
+	V1Alpha2SpecAnnotation = "eventing.kyma-project.io/v1alpha2-spec"
 )
 
+// v1alpha2SpecFields holds the v1alpha2 spec fields which are lost in the conversion to v1alpha1.
+// The source and types are kept to detect if the v1alpha1 filter was changed after the conversion.
+type v1alpha2SpecFields struct {
+	Source       string                        `json:"source,omitempty"`
+	Types        []string                      `json:"types,omitempty"`
+	TypeMatching v1alpha2.TypeMatching         `json:"typeMatching,omitempty"`
+	Filters      []v1alpha2.SubscriptionFilter `json:"filters,omitempty"`
+	Config       map[string]string             `json:"config,omitempty"`
+	SinkTLS      *v1alpha2.SinkTLSConfig       `json:"sinkTLS,omitempty"`
+	SinkRef      *v1alpha2.SinkReference       `json:"sinkRef,omitempty"`
+
+	FilterExpression   string `json:"filterExpression,omitempty"`
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	ExpiryDate     *metav1.Time     `json:"expiryDate,omitempty"`
+	TTL            *metav1.Duration `json:"ttl,omitempty"`
+	DeleteOnExpiry bool             `json:"deleteOnExpiry,omitempty"`
+}
+
+// v1alpha1ConfigKeys are the v1alpha2 config keys which have a v1alpha1 equivalent.
+//
+//nolint:gochecknoglobals // used as a constant lookup table.
+var v1alpha1ConfigKeys = map[string]bool{
+	v1alpha2.MaxInFlightMessages:             true,
+	v1alpha2.Protocol:                        true,
+	v1alpha2.ProtocolSettingsContentMode:     true,
+	v1alpha2.ProtocolSettingsExemptHandshake: true,
+	v1alpha2.ProtocolSettingsQos:             true,
+	v1alpha2.WebhookAuthType:                 true,
+	v1alpha2.WebhookAuthGrantType:            true,
+	v1alpha2.WebhookAuthClientID:             true,
+	v1alpha2.WebhookAuthClientSecret:         true,
+	v1alpha2.WebhookAuthTokenURL:             true,
+	v1alpha2.WebhookAuthScope:                true,
+}
+
 var v1alpha1TypeCleaner eventtype.Cleaner //nolint:gochecknoglobals // using global var because there is no runtime
 // object to hold this instance.
 
@@ -37,7 +82,7 @@ func (src *Subscription) ConvertTo(dstRaw conversion.Hub) error {
 // V1ToV2 copies the v1alpha1-type field values into v1alpha2-type field values.
 func V1ToV2(src *Subscription, dst *v1alpha2.Subscription) error {
 	// ObjectMeta
-	dst.ObjectMeta = src.ObjectMeta
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
 
 	// SPEC fields
 
@@ -58,7 +103,13 @@ func V1ToV2(src *Subscription, dst *v1alpha2.Subscription) error {
 	// Config
 	src.natsSpecConfigToV2(dst)
 
-	return nil
+	// v1alpha2 fields which were lost in a previous conversion to v1alpha1
+	if err := src.restoreV2SpecFields(dst); err != nil {
+		return err
+	}
+
+	// v1alpha1 fields which cannot be restored from the v1alpha2 spec
+	return src.preserveV1Filter(dst)
 }
 
 // ConvertFrom converts this Subscription from the Hub version (v2) to v1.
@@ -73,31 +124,21 @@ func (dst *Subscription) ConvertFrom(srcRaw conversion.Hub) error { //nolint:rev
 // V2ToV1 copies the v1alpha2-type field values into v1alpha1-type field values.
 func V2ToV1(dst *Subscription, src *v1alpha2.Subscription) error {
 	// ObjectMeta
-	dst.ObjectMeta = src.ObjectMeta
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
 
 	dst.Spec.ID = src.Spec.ID
 	dst.Spec.Sink = src.Spec.Sink
 
 	dst.setV1ProtocolFields(src)
 
-	dst.Spec.Filter = &BEBFilters{
-		Filters: []*EventMeshFilter{},
-	}
+	dst.Spec.Filter = v1FilterFromV2(src.Spec.Source, src.Spec.Types)
 
-	for _, eventType := range src.Spec.Types {
-		filter := &EventMeshFilter{
-			EventSource: &Filter{
-				Property: "source",
-				Type:     fmt.Sprint(v1alpha2.TypeMatchingExact),
-				Value:    src.Spec.Source,
-			},
-			EventType: &Filter{
-				Type:     fmt.Sprint(v1alpha2.TypeMatchingExact),
-				Property: "type",
-				Value:    eventType,
-			},
-		}
-		dst.Spec.Filter.Filters = append(dst.Spec.Filter.Filters, filter)
+	// v1alpha1 filter which was lost in a previous conversion to v1alpha2
+	dst.restoreV1Filter(src)
+
+	// v1alpha2 fields which cannot be represented in v1alpha1
+	if err := dst.preserveV2SpecFields(src); err != nil {
+		return err
 	}
 
 	if src.Spec.Config != nil {
@@ -318,3 +359,154 @@ func ConditionV2ToV1(condition v1alpha2.Condition) Condition {
 		Message:            condition.Message,
 	}
 }
+
+// v1FilterFromV2 creates the v1alpha1 filter for the given v1alpha2 source and types.
+func v1FilterFromV2(source string, types []string) *BEBFilters {
+	filters := &BEBFilters{
+		Filters: []*EventMeshFilter{},
+	}
+	for _, eventType := range types {
+		filter := &EventMeshFilter{
+			EventSource: &Filter{
+				Property: "source",
+				Type:     fmt.Sprint(v1alpha2.TypeMatchingExact),
+				Value:    source,
+			},
+			EventType: &Filter{
+				Type:     fmt.Sprint(v1alpha2.TypeMatchingExact),
+				Property: "type",
+				Value:    eventType,
+			},
+		}
+		filters.Filters = append(filters.Filters, filter)
+	}
+	return filters
+}
+
+// preserveV1Filter stores the v1alpha1 filter in the v1alpha2 annotations
+// if it cannot be restored from the converted v1alpha2 source and types.
+func (src *Subscription) preserveV1Filter(dst *v1alpha2.Subscription) error {
+	if src.Spec.Filter == nil || reflect.DeepEqual(src.Spec.Filter, v1FilterFromV2(dst.Spec.Source, dst.Spec.Types)) {
+		return nil
+	}
+	value, err := json.Marshal(src.Spec.Filter)
+	if err != nil {
+		return err
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[V1Alpha1FilterAnnotation] = string(value)
+	return nil
+}
+
+// restoreV1Filter restores the v1alpha1 filter from the v1alpha2 annotations
+// if the v1alpha2 source and types were not changed since the conversion.
+func (dst *Subscription) restoreV1Filter(src *v1alpha2.Subscription) { //nolint:revive
+	value, ok := dst.Annotations[V1Alpha1FilterAnnotation]
+	if !ok {
+		return
+	}
+	delete(dst.Annotations, V1Alpha1FilterAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	filter := &BEBFilters{}
+	if err := json.Unmarshal([]byte(value), filter); err != nil {
+		return
+	}
+	converted := &v1alpha2.Subscription{}
+	if err := (&Subscription{Spec: SubscriptionSpec{Filter: filter}}).setV2SpecTypes(converted); err != nil {
+		return
+	}
+	if src.Spec.TypeMatching == v1alpha2.TypeMatchingExact && converted.Spec.Source == src.Spec.Source &&
+		reflect.DeepEqual(converted.Spec.Types, src.Spec.Types) {
+		dst.Spec.Filter = filter
+	}
+}
+
+// preserveV2SpecFields stores the v1alpha2 spec fields without a v1alpha1 equivalent in the v1alpha1 annotations.
+func (dst *Subscription) preserveV2SpecFields(src *v1alpha2.Subscription) error { //nolint:revive
+	fields := v1alpha2SpecFields{
+		Source:  src.Spec.Source,
+		Types:   src.Spec.Types,
+		Filters: src.Spec.Filters,
+		SinkTLS: src.Spec.SinkTLS,
+		SinkRef: src.Spec.SinkRef,
+
+		FilterExpression:   src.Spec.FilterExpression,
+		ServiceAccountName: src.Spec.ServiceAccountName,
+
+		ExpiryDate:     src.Spec.ExpiryDate,
+		TTL:            src.Spec.TTL,
+		DeleteOnExpiry: src.Spec.DeleteOnExpiry,
+	}
+	if src.Spec.TypeMatching != v1alpha2.TypeMatchingExact {
+		fields.TypeMatching = src.Spec.TypeMatching
+	}
+	for key, value := range src.Spec.Config {
+		if v1alpha1ConfigKeys[key] {
+			continue
+		}
+		if fields.Config == nil {
+			fields.Config = map[string]string{}
+		}
+		fields.Config[key] = value
+	}
+	if fields.TypeMatching == "" && len(fields.Filters) == 0 && len(fields.Config) == 0 &&
+		fields.SinkTLS == nil && fields.SinkRef == nil && fields.FilterExpression == "" &&
+		fields.ServiceAccountName == "" && fields.ExpiryDate == nil && fields.TTL == nil && !fields.DeleteOnExpiry {
+		return nil
+	}
+
+	value, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[V1Alpha2SpecAnnotation] = string(value)
+	return nil
+}
+
+// restoreV2SpecFields restores the v1alpha2 spec fields from the v1alpha1 annotations.
+// The source, types and typeMatching are only restored if the v1alpha1 filter was not changed since the conversion.
+func (src *Subscription) restoreV2SpecFields(dst *v1alpha2.Subscription) error {
+	value, ok := dst.Annotations[V1Alpha2SpecAnnotation]
+	if !ok {
+		return nil
+	}
+	delete(dst.Annotations, V1Alpha2SpecAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	fields := v1alpha2SpecFields{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return errors.Wrapf(err, "failed to parse annotation %s", V1Alpha2SpecAnnotation)
+	}
+	if fields.TypeMatching != "" && reflect.DeepEqual(src.Spec.Filter, v1FilterFromV2(fields.Source, fields.Types)) {
+		dst.Spec.TypeMatching = fields.TypeMatching
+		dst.Spec.Source = fields.Source
+		dst.Spec.Types = fields.Types
+	}
+	dst.Spec.Filters = fields.Filters
+	dst.Spec.FilterExpression = fields.FilterExpression
+	dst.Spec.SinkTLS = fields.SinkTLS
+	dst.Spec.ServiceAccountName = fields.ServiceAccountName
+	dst.Spec.ExpiryDate = fields.ExpiryDate
+	dst.Spec.TTL = fields.TTL
+	dst.Spec.DeleteOnExpiry = fields.DeleteOnExpiry
+	// the sinkRef is only restored if no sink URL was set after the conversion
+	if src.Spec.Sink == "" {
+		dst.Spec.SinkRef = fields.SinkRef
+	}
+	for key, value := range fields.Config {
+		if _, ok := dst.Spec.Config[key]; !ok {
+			dst.Spec.Config[key] = value
+		}
+	}
+	return nil
+}
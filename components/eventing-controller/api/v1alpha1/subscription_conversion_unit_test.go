@@ -1,7 +1,9 @@
 package v1alpha1_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
 
@@ -407,3 +409,99 @@ func v2ToV1Assertions(t *testing.T, wantSub, convertedSub *v1alpha1.Subscription
 
 	assert.Equal(t, wantSub.Status.Config, convertedSub.Status.Config)
 }
+
+// Test_RoundTripConversion tests that no configuration is lost when converting back and forth between the versions.
+func Test_RoundTripConversion(t *testing.T) {
+	// initialize a cleaner which changes the event types
+	cleaner := eventtype.CleanerFunc(func(et string) (string, error) { return strings.ReplaceAll(et, "_&", ""), nil })
+	v1alpha1.InitializeEventTypeCleaner(cleaner)
+
+	t.Run("v2 to v1 to v2 should keep the v1alpha2 only fields", func(t *testing.T) {
+		// given
+		alpha2Sub := newV2DefaultSubscription(
+			eventingtesting.WithEventSource(eventSource),
+			eventingtesting.WithTypes([]string{orderCreatedEventType}),
+			eventingtesting.WithTypeMatchingStandard(),
+			eventingtesting.WithConfigValue("custom", "value"),
+			eventingtesting.WithFilters(v1alpha2.SubscriptionFilter{
+				Exact: map[string]string{"region": "eu"},
+			}),
+			eventingtesting.WithFilterExpression("subject LIKE 'orders/%'"),
+			eventingtesting.WithSinkTLS(&v1alpha2.SinkTLSConfig{InsecureSkipVerify: true}),
+			eventingtesting.WithServiceAccountName("dispatcher"),
+			eventingtesting.WithTTL(72*time.Hour),
+		)
+
+		// when
+		alpha1Sub := &v1alpha1.Subscription{}
+		require.NoError(t, v1alpha1.V2ToV1(alpha1Sub, alpha2Sub))
+		convertedAlpha2Sub := &v1alpha2.Subscription{}
+		require.NoError(t, v1alpha1.V1ToV2(alpha1Sub, convertedAlpha2Sub))
+
+		// then
+		require.Contains(t, alpha1Sub.Annotations, v1alpha1.V1Alpha2SpecAnnotation)
+		require.Equal(t, alpha2Sub.ObjectMeta, convertedAlpha2Sub.ObjectMeta)
+		require.Equal(t, alpha2Sub.Spec, convertedAlpha2Sub.Spec)
+	})
+
+	t.Run("v2 to v1 to v2 should keep the sinkRef", func(t *testing.T) {
+		// given
+		alpha2Sub := newV2DefaultSubscription(
+			eventingtesting.WithEventSource(eventSource),
+			eventingtesting.WithTypes([]string{orderCreatedEventType}),
+			eventingtesting.WithSinkRef("orders", 8080),
+		)
+
+		// when
+		alpha1Sub := &v1alpha1.Subscription{}
+		require.NoError(t, v1alpha1.V2ToV1(alpha1Sub, alpha2Sub))
+		convertedAlpha2Sub := &v1alpha2.Subscription{}
+		require.NoError(t, v1alpha1.V1ToV2(alpha1Sub, convertedAlpha2Sub))
+
+		// then
+		require.Empty(t, alpha1Sub.Spec.Sink)
+		require.Equal(t, alpha2Sub.Spec.SinkRef, convertedAlpha2Sub.Spec.SinkRef)
+		require.Empty(t, convertedAlpha2Sub.Spec.Sink)
+	})
+
+	t.Run("v1 to v2 to v1 should keep the v1alpha1 filter", func(t *testing.T) {
+		// given
+		alpha1Sub := newDefaultSubscription(
+			eventingtesting.WithV1alpha1Filter(eventSource, orderDeletedEventTypeNonClean),
+		)
+		alpha1Sub.Spec.Filter.Dialect = "beb"
+
+		// when
+		alpha2Sub := &v1alpha2.Subscription{}
+		require.NoError(t, v1alpha1.V1ToV2(alpha1Sub, alpha2Sub))
+		convertedAlpha1Sub := &v1alpha1.Subscription{}
+		require.NoError(t, v1alpha1.V2ToV1(convertedAlpha1Sub, alpha2Sub))
+
+		// then
+		require.Contains(t, alpha2Sub.Annotations, v1alpha1.V1Alpha1FilterAnnotation)
+		require.Equal(t, []string{orderDeletedEventType}, alpha2Sub.Spec.Types)
+		require.Equal(t, alpha1Sub.ObjectMeta, convertedAlpha1Sub.ObjectMeta)
+		require.Equal(t, alpha1Sub.Spec.Filter, convertedAlpha1Sub.Spec.Filter)
+	})
+
+	t.Run("v1 to v2 to v1 should not restore the v1alpha1 filter if the v2 types changed", func(t *testing.T) {
+		// given
+		alpha1Sub := newDefaultSubscription(
+			eventingtesting.WithV1alpha1Filter(eventSource, orderDeletedEventTypeNonClean),
+		)
+		alpha1Sub.Spec.Filter.Dialect = "beb"
+
+		// when
+		alpha2Sub := &v1alpha2.Subscription{}
+		require.NoError(t, v1alpha1.V1ToV2(alpha1Sub, alpha2Sub))
+		alpha2Sub.Spec.Types = []string{orderProcessedEventType}
+		convertedAlpha1Sub := &v1alpha1.Subscription{}
+		require.NoError(t, v1alpha1.V2ToV1(convertedAlpha1Sub, alpha2Sub))
+
+		// then
+		require.NotContains(t, convertedAlpha1Sub.Annotations, v1alpha1.V1Alpha1FilterAnnotation)
+		require.Len(t, convertedAlpha1Sub.Spec.Filter.Filters, 1)
+		require.Empty(t, convertedAlpha1Sub.Spec.Filter.Dialect)
+		require.Equal(t, orderProcessedEventType, convertedAlpha1Sub.Spec.Filter.Filters[0].EventType.Value)
+	})
+}
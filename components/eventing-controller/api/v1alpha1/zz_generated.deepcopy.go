@@ -9,6 +9,21 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BEBBackendSpec) DeepCopyInto(out *BEBBackendSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BEBBackendSpec.
+func (in *BEBBackendSpec) DeepCopy() *BEBBackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BEBBackendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BEBFilters) DeepCopyInto(out *BEBFilters) {
 	*out = *in
@@ -92,7 +107,7 @@ func (in *EventMeshFilter) DeepCopy() *EventMeshFilter {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EventingBackend) DeepCopyInto(out *EventingBackend) {
+func (in *EventType) DeepCopyInto(out *EventType) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -100,6 +115,107 @@ func (in *EventingBackend) DeepCopyInto(out *EventingBackend) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventType.
+func (in *EventType) DeepCopy() *EventType {
+	if in == nil {
+		return nil
+	}
+	out := new(EventType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventType) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTypeList) DeepCopyInto(out *EventTypeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EventType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTypeList.
+func (in *EventTypeList) DeepCopy() *EventTypeList {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTypeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventTypeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTypeSpec) DeepCopyInto(out *EventTypeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTypeSpec.
+func (in *EventTypeSpec) DeepCopy() *EventTypeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTypeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTypeStatus) DeepCopyInto(out *EventTypeStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Subscriptions != nil {
+		in, out := &in.Subscriptions, &out.Subscriptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTypeStatus.
+func (in *EventTypeStatus) DeepCopy() *EventTypeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTypeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventingBackend) DeepCopyInto(out *EventingBackend) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingBackend.
 func (in *EventingBackend) DeepCopy() *EventingBackend {
 	if in == nil {
@@ -153,6 +269,21 @@ func (in *EventingBackendList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EventingBackendSpec) DeepCopyInto(out *EventingBackendSpec) {
 	*out = *in
+	if in.NATS != nil {
+		in, out := &in.NATS, &out.NATS
+		*out = new(NATSBackendSpec)
+		**out = **in
+	}
+	if in.BEB != nil {
+		in, out := &in.BEB, &out.BEB
+		*out = new(BEBBackendSpec)
+		**out = **in
+	}
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(KafkaBackendSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingBackendSpec.
@@ -180,6 +311,11 @@ func (in *EventingBackendStatus) DeepCopyInto(out *EventingBackendStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(EventingSummary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingBackendStatus.
@@ -192,6 +328,33 @@ func (in *EventingBackendStatus) DeepCopy() *EventingBackendStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventingSummary) DeepCopyInto(out *EventingSummary) {
+	*out = *in
+	out.Subscriptions = in.Subscriptions
+	if in.Stream != nil {
+		in, out := &in.Stream, &out.Stream
+		*out = new(StreamUsage)
+		**out = **in
+	}
+	if in.RecentErrors != nil {
+		in, out := &in.RecentErrors, &out.RecentErrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingSummary.
+func (in *EventingSummary) DeepCopy() *EventingSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(EventingSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Filter) DeepCopyInto(out *Filter) {
 	*out = *in
@@ -207,6 +370,41 @@ func (in *Filter) DeepCopy() *Filter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaBackendSpec) DeepCopyInto(out *KafkaBackendSpec) {
+	*out = *in
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaBackendSpec.
+func (in *KafkaBackendSpec) DeepCopy() *KafkaBackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaBackendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NATSBackendSpec) DeepCopyInto(out *NATSBackendSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NATSBackendSpec.
+func (in *NATSBackendSpec) DeepCopy() *NATSBackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NATSBackendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProtocolSettings) DeepCopyInto(out *ProtocolSettings) {
 	*out = *in
@@ -242,6 +440,21 @@ func (in *ProtocolSettings) DeepCopy() *ProtocolSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StreamUsage) DeepCopyInto(out *StreamUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StreamUsage.
+func (in *StreamUsage) DeepCopy() *StreamUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(StreamUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subscription) DeepCopyInto(out *Subscription) {
 	*out = *in
@@ -383,6 +596,21 @@ func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionSummary) DeepCopyInto(out *SubscriptionSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSummary.
+func (in *SubscriptionSummary) DeepCopy() *SubscriptionSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookAuth) DeepCopyInto(out *WebhookAuth) {
 	*out = *in
@@ -2,6 +2,7 @@ package v1alpha2
 
 import (
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,6 +15,12 @@ const (
 	ConditionSubscriptionActive ConditionType = "Subscription active"
 	ConditionAPIRuleStatus      ConditionType = "APIRule status"
 	ConditionWebhookCallStatus  ConditionType = "Webhook call status"
+	ConditionSinkReachable      ConditionType = "SinkReachable"
+	ConditionSinkHealthy        ConditionType = "SinkHealthy"
+	ConditionCleanedUp          ConditionType = "CleanedUp"
+	ConditionExpired            ConditionType = "Expired"
+
+	ConditionSubscriptionsSynced ConditionType = "Subscriptions synced"
 
 	ConditionPublisherProxyReady ConditionType = "Publisher Proxy Ready"
 	ConditionControllerReady     ConditionType = "Subscription Controller Ready"
@@ -43,6 +50,26 @@ const (
 	ConditionReasonNATSSubscriptionActive    ConditionReason = "NATS Subscription active"
 	ConditionReasonNATSSubscriptionNotActive ConditionReason = "NATS Subscription not active"
 
+	// Kafka Conditions.
+	ConditionReasonKafkaSubscriptionActive    ConditionReason = "Kafka Subscription active"
+	ConditionReasonKafkaSubscriptionNotActive ConditionReason = "Kafka Subscription not active"
+
+	// Sink Conditions.
+	ConditionReasonSinkReachable    ConditionReason = "Sink reachable"
+	ConditionReasonSinkNotReachable ConditionReason = "Sink not reachable"
+	ConditionReasonSinkHealthy      ConditionReason = "Sink healthy"
+	ConditionReasonSinkUnhealthy    ConditionReason = "Sink unhealthy"
+
+	// Cleanup Conditions.
+	ConditionReasonCleanupFailed ConditionReason = "Cleanup failed"
+
+	// Expiry Conditions.
+	ConditionReasonSubscriptionExpired ConditionReason = "Subscription expired"
+
+	// SubscriptionTemplate Conditions.
+	ConditionReasonSubscriptionsSynced     ConditionReason = "Subscriptions synced"
+	ConditionReasonSubscriptionsSyncFailed ConditionReason = "Subscriptions sync failed"
+
 	// EventMesh Conditions.
 	ConditionReasonSubscriptionCreated        ConditionReason = "EventMesh Subscription created"
 	ConditionReasonSubscriptionCreationFailed ConditionReason = "EventMesh Subscription creation failed"
@@ -221,6 +248,64 @@ func (s *SubscriptionStatus) SetConditionAPIRuleStatus(err error) {
 	s.Conditions = newConditions
 }
 
+// SetConditionSinkReachable sets the ConditionSinkReachable condition based on the given probe error.
+// The LastTransitionTime is only updated if the condition status changes.
+func (s *SubscriptionStatus) SetConditionSinkReachable(err error) {
+	reason := ConditionReasonSinkReachable
+	status := corev1.ConditionTrue
+	message := ""
+	if err != nil {
+		reason = ConditionReasonSinkNotReachable
+		status = corev1.ConditionFalse
+		message = err.Error()
+	}
+	s.setCondition(MakeCondition(ConditionSinkReachable, reason, status, message))
+}
+
+// SetConditionSinkHealthy sets the ConditionSinkHealthy condition to false with the given message if the sink host
+// is unhealthy. Otherwise, it only sets the condition to true if it was set before, so that the condition is only
+// shown on the subscriptions which are or were affected by an unhealthy sink host.
+// The LastTransitionTime is only updated if the condition status changes.
+func (s *SubscriptionStatus) SetConditionSinkHealthy(healthy bool, message string) {
+	if !healthy {
+		s.setCondition(MakeCondition(ConditionSinkHealthy, ConditionReasonSinkUnhealthy, corev1.ConditionFalse, message))
+		return
+	}
+	if s.FindCondition(ConditionSinkHealthy) != nil {
+		s.setCondition(MakeCondition(ConditionSinkHealthy, ConditionReasonSinkHealthy, corev1.ConditionTrue, ""))
+	}
+}
+
+// SetConditionCleanupFailed sets the ConditionCleanedUp condition to false with the given cleanup error.
+// The LastTransitionTime is only updated if the condition status changes.
+func (s *SubscriptionStatus) SetConditionCleanupFailed(err error) {
+	s.setCondition(MakeCondition(ConditionCleanedUp, ConditionReasonCleanupFailed, corev1.ConditionFalse, err.Error()))
+}
+
+// SetConditionExpired sets the ConditionExpired condition to true with the given expiry time.
+// The LastTransitionTime is only updated if the condition status changes.
+func (s *SubscriptionStatus) SetConditionExpired(expiryTime time.Time) {
+	message := fmt.Sprintf("Subscription expired at %s, the delivery of events is stopped",
+		expiryTime.UTC().Format(time.RFC3339))
+	s.setCondition(MakeCondition(ConditionExpired, ConditionReasonSubscriptionExpired, corev1.ConditionTrue, message))
+}
+
+// setCondition replaces the condition of the same type or appends it if it does not exist yet.
+// The LastTransitionTime of an existing condition is kept if its status does not change.
+func (s *SubscriptionStatus) setCondition(condition Condition) {
+	for i, c := range s.Conditions {
+		if c.Type != condition.Type {
+			continue
+		}
+		if c.Status == condition.Status {
+			condition.LastTransitionTime = c.LastTransitionTime
+		}
+		s.Conditions[i] = condition
+		return
+	}
+	s.Conditions = append(s.Conditions, condition)
+}
+
 // ConditionsEquals checks if two list of conditions are equal.
 func ConditionsEquals(existing, expected []Condition) bool {
 	// not equal if length is different
@@ -263,16 +348,29 @@ func CreateMessageForConditionReasonSubscriptionCreated(eventMeshName string) st
 
 // GetSubscriptionActiveCondition updates the ConditionSubscriptionActive condition based on the given error value.
 func GetSubscriptionActiveCondition(sub *Subscription, err error) []Condition {
+	return getSubscriptionActiveCondition(sub, err,
+		ConditionReasonNATSSubscriptionActive, ConditionReasonNATSSubscriptionNotActive)
+}
+
+// GetKafkaSubscriptionActiveCondition updates the ConditionSubscriptionActive condition of a Subscription of the
+// Kafka backend based on the given error value.
+func GetKafkaSubscriptionActiveCondition(sub *Subscription, err error) []Condition {
+	return getSubscriptionActiveCondition(sub, err,
+		ConditionReasonKafkaSubscriptionActive, ConditionReasonKafkaSubscriptionNotActive)
+}
+
+func getSubscriptionActiveCondition(sub *Subscription, err error,
+	activeReason, notActiveReason ConditionReason) []Condition {
 	subscriptionActiveCondition := Condition{
 		Type:               ConditionSubscriptionActive,
 		LastTransitionTime: metav1.Now(),
 	}
 	if err == nil {
 		subscriptionActiveCondition.Status = corev1.ConditionTrue
-		subscriptionActiveCondition.Reason = ConditionReasonNATSSubscriptionActive
+		subscriptionActiveCondition.Reason = activeReason
 	} else {
 		subscriptionActiveCondition.Message = err.Error()
-		subscriptionActiveCondition.Reason = ConditionReasonNATSSubscriptionNotActive
+		subscriptionActiveCondition.Reason = notActiveReason
 		subscriptionActiveCondition.Status = corev1.ConditionFalse
 	}
 	for _, activeCond := range sub.Status.Conditions {
@@ -551,3 +551,149 @@ func Test_SetConditionSubscriptionActive(t *testing.T) {
 		})
 	}
 }
+
+func Test_SetConditionSinkReachable(t *testing.T) {
+	err := errors.New("connection refused")
+	conditionActive := v1alpha2.MakeCondition(
+		v1alpha2.ConditionSubscriptionActive,
+		v1alpha2.ConditionReasonNATSSubscriptionActive,
+		corev1.ConditionTrue, "")
+	conditionReachable := v1alpha2.MakeCondition(
+		v1alpha2.ConditionSinkReachable,
+		v1alpha2.ConditionReasonSinkReachable,
+		corev1.ConditionTrue, "")
+	conditionReachable.LastTransitionTime = metav1.NewTime(time.Now().AddDate(0, 0, -1))
+	conditionNotReachable := v1alpha2.MakeCondition(
+		v1alpha2.ConditionSinkReachable,
+		v1alpha2.ConditionReasonSinkNotReachable,
+		corev1.ConditionFalse, err.Error())
+
+	testCases := []struct {
+		name                   string
+		givenConditions        []v1alpha2.Condition
+		givenError             error
+		wantConditions         []v1alpha2.Condition
+		wantLastTransitionTime *metav1.Time
+	}{
+		{
+			name:            "no error should add the reachable condition",
+			givenConditions: []v1alpha2.Condition{conditionActive},
+			givenError:      nil,
+			wantConditions:  []v1alpha2.Condition{conditionActive, conditionReachable},
+		},
+		{
+			name:            "error should replace the reachable condition",
+			givenConditions: []v1alpha2.Condition{conditionActive, conditionReachable},
+			givenError:      err,
+			wantConditions:  []v1alpha2.Condition{conditionActive, conditionNotReachable},
+		},
+		{
+			name:                   "the same status should not change the lastTransitionTime",
+			givenConditions:        []v1alpha2.Condition{conditionReachable},
+			givenError:             nil,
+			wantConditions:         []v1alpha2.Condition{conditionReachable},
+			wantLastTransitionTime: &conditionReachable.LastTransitionTime,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			status := v1alpha2.SubscriptionStatus{
+				Conditions: append([]v1alpha2.Condition{}, tc.givenConditions...),
+			}
+
+			// when
+			status.SetConditionSinkReachable(tc.givenError)
+
+			// then
+			require.True(t, v1alpha2.ConditionsEquals(status.Conditions, tc.wantConditions))
+			if tc.wantLastTransitionTime != nil {
+				c := status.FindCondition(v1alpha2.ConditionSinkReachable)
+				require.NotNil(t, c)
+				require.Equal(t, *tc.wantLastTransitionTime, c.LastTransitionTime)
+			}
+		})
+	}
+}
+
+func Test_SetConditionSinkHealthy(t *testing.T) {
+	const message = "sink host is unhealthy"
+	conditionActive := v1alpha2.MakeCondition(
+		v1alpha2.ConditionSubscriptionActive,
+		v1alpha2.ConditionReasonNATSSubscriptionActive,
+		corev1.ConditionTrue, "")
+	conditionHealthy := v1alpha2.MakeCondition(
+		v1alpha2.ConditionSinkHealthy,
+		v1alpha2.ConditionReasonSinkHealthy,
+		corev1.ConditionTrue, "")
+	conditionUnhealthy := v1alpha2.MakeCondition(
+		v1alpha2.ConditionSinkHealthy,
+		v1alpha2.ConditionReasonSinkUnhealthy,
+		corev1.ConditionFalse, message)
+
+	testCases := []struct {
+		name            string
+		givenConditions []v1alpha2.Condition
+		givenHealthy    bool
+		wantConditions  []v1alpha2.Condition
+	}{
+		{
+			name:            "a healthy sink should not add the condition",
+			givenConditions: []v1alpha2.Condition{conditionActive},
+			givenHealthy:    true,
+			wantConditions:  []v1alpha2.Condition{conditionActive},
+		},
+		{
+			name:            "an unhealthy sink should add the condition",
+			givenConditions: []v1alpha2.Condition{conditionActive},
+			givenHealthy:    false,
+			wantConditions:  []v1alpha2.Condition{conditionActive, conditionUnhealthy},
+		},
+		{
+			name:            "a recovered sink should replace the condition",
+			givenConditions: []v1alpha2.Condition{conditionActive, conditionUnhealthy},
+			givenHealthy:    true,
+			wantConditions:  []v1alpha2.Condition{conditionActive, conditionHealthy},
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			status := v1alpha2.SubscriptionStatus{
+				Conditions: append([]v1alpha2.Condition{}, tc.givenConditions...),
+			}
+
+			// when
+			status.SetConditionSinkHealthy(tc.givenHealthy, message)
+
+			// then
+			require.True(t, v1alpha2.ConditionsEquals(status.Conditions, tc.wantConditions))
+		})
+	}
+}
+
+func Test_SetConditionCleanupFailed(t *testing.T) {
+	// given
+	err := errors.New("consumer not deleted")
+	conditionActive := v1alpha2.MakeCondition(
+		v1alpha2.ConditionSubscriptionActive,
+		v1alpha2.ConditionReasonNATSSubscriptionActive,
+		corev1.ConditionTrue, "")
+	status := v1alpha2.SubscriptionStatus{Conditions: []v1alpha2.Condition{conditionActive}}
+
+	// when
+	status.SetConditionCleanupFailed(err)
+	firstCondition := status.FindCondition(v1alpha2.ConditionCleanedUp)
+	status.SetConditionCleanupFailed(errors.New("consumer still not deleted"))
+
+	// then
+	require.Len(t, status.Conditions, 2)
+	c := status.FindCondition(v1alpha2.ConditionCleanedUp)
+	require.NotNil(t, c)
+	require.Equal(t, corev1.ConditionFalse, c.Status)
+	require.Equal(t, v1alpha2.ConditionReasonCleanupFailed, c.Reason)
+	require.Equal(t, "consumer still not deleted", c.Message)
+	require.Equal(t, firstCondition.LastTransitionTime, c.LastTransitionTime)
+}
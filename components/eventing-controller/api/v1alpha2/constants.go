@@ -6,6 +6,11 @@ const (
 
 	// config fields.
 	MaxInFlightMessages = "maxInFlightMessages"
+	DeliveryMode        = "deliveryMode"
+
+	// delivery modes of the NATS backend.
+	DeliveryModeJetStream = "jetstream"
+	DeliveryModeCore      = "core"
 
 	// protocol settings.
 	Protocol                        = "protocol"
@@ -20,4 +25,9 @@ const (
 	WebhookAuthClientSecret = "clientSecret"
 	WebhookAuthTokenURL     = "tokenUrl"
 	WebhookAuthScope        = "scope"
+
+	// sink TLS fields.
+	CABundleKindConfigMap = "ConfigMap"
+	CABundleKindSecret    = "Secret"
+	DefaultCABundleKey    = "ca.crt"
 )
@@ -11,11 +11,21 @@ import (
 
 //nolint:gochecknoglobals // these are required for testing
 var (
-	SourcePath = field.NewPath("spec").Child("source")
-	TypesPath  = field.NewPath("spec").Child("types")
-	ConfigPath = field.NewPath("spec").Child("config")
-	SinkPath   = field.NewPath("spec").Child("sink")
-	NSPath     = field.NewPath("metadata").Child("namespace")
+	SourcePath  = field.NewPath("spec").Child("source")
+	TypesPath   = field.NewPath("spec").Child("types")
+	ConfigPath  = field.NewPath("spec").Child("config")
+	SinkPath    = field.NewPath("spec").Child("sink")
+	SinkRefPath = field.NewPath("spec").Child("sinkRef")
+	SinkTLSPath = field.NewPath("spec").Child("sinkTLS")
+	TTLPath     = field.NewPath("spec").Child("ttl")
+	FilterPath  = field.NewPath("spec").Child("filters")
+	NSPath      = field.NewPath("metadata").Child("namespace")
+	BackendPath = field.NewPath("metadata").Child("annotations").Key(BackendAnnotation)
+
+	FilterExpressionPath   = field.NewPath("spec").Child("filterExpression")
+	ServiceAccountNamePath = field.NewPath("spec").Child("serviceAccountName")
+
+	ForceDeletePath = field.NewPath("metadata").Child("annotations").Key(ForceDeleteAnnotation)
 
 	EmptyErrDetail          = "must not be empty"
 	InvalidURIErrDetail     = "must be valid as per RFC 3986"
@@ -24,6 +34,17 @@ var (
 	MinSegmentErrDetail     = fmt.Sprintf("must have minimum %s segments", strconv.Itoa(minEventTypeSegments))
 	InvalidPrefixErrDetail  = fmt.Sprintf("must not have %s as type prefix", InvalidPrefix)
 	StringIntErrDetail      = fmt.Sprintf("%s must be a stringified int value", MaxInFlightMessages)
+	PositiveIntErrDetail    = fmt.Sprintf("%s must be greater than 0", MaxInFlightMessages)
+	DeliveryModeErrDetail   = fmt.Sprintf("%s must be a valid delivery mode %s or %s",
+		DeliveryMode, DeliveryModeJetStream, DeliveryModeCore)
+	// MaxInFlightLimitErrDetail is formatted with the cluster-wide limit.
+	MaxInFlightLimitErrDetail = MaxInFlightMessages + " must not exceed the cluster limit of %d"
+
+	WildcardTypeMatchingErrDetail = fmt.Sprintf("must use type matching %s for wildcard types", TypeMatchingExact)
+	WildcardSegmentErrDetail      = fmt.Sprintf("must only use %s as a whole segment and %s as the whole last segment",
+		TypeWildcardToken, TypeWildcardTailToken)
+	WildcardFirstSegmentErrDetail = "must not start with a wildcard segment"
+	WildcardBreadthErrDetail      = fmt.Sprintf("must not have more than %d wildcard segments", maxWildcardSegments)
 
 	InvalidQosErrDetail = fmt.Sprintf("must be a valid QoS value %s or %s",
 		types.QosAtLeastOnce, types.QosAtMostOnce)
@@ -31,9 +52,50 @@ var (
 	InvalidGrantTypeErrDetail = fmt.Sprintf("must be a valid Grant Type value %s", types.GrantTypeClientCredentials)
 
 	MissingSchemeErrDetail = "must have URL scheme 'http' or 'https'"
-	SuffixMissingErrDetail = fmt.Sprintf("must have valid sink URL suffix %s", ClusterLocalURLSuffix)
-	SubDomainsErrDetail    = fmt.Sprintf("must have sink URL with %d sub-domains: ", subdomainSegments)
-	NSMismatchErrDetail    = "must have the same namespace as the subscriber: "
+	SuffixMissingErrDetail = fmt.Sprintf("must have valid sink URL suffix %s, "+
+		"external sinks are not permitted by the cluster policy", ClusterLocalURLSuffix)
+	SubDomainsErrDetail = fmt.Sprintf("must have sink URL with %d sub-domains: ", subdomainSegments)
+	NSMismatchErrDetail = "must have the same namespace as the subscriber: "
+
+	ExternalSinkNotAnnotatedErrDetail = fmt.Sprintf("must have valid sink URL suffix %s, "+
+		"or the annotation %s set to \"true\" to use an external sink", ClusterLocalURLSuffix, AllowExternalSinkAnnotation)
+	ServiceNotFoundErrDetail = "must reference an existing Service, " +
+		"but Service %q was not found in namespace %q; create the Service or fix the sink URL"
+
+	SinkAndSinkRefErrDetail = "must not be set together with the sink"
+	SinkRefKindErrDetail    = "must reference a Service of apiVersion v1 or a Function of apiVersion " +
+		FunctionAPIVersion
+	SinkRefPortErrDetail = fmt.Sprintf("must be a valid port between 1 and %d", maxPort)
+
+	SinkTLSSchemeErrDetail   = "must only be set for sinks with URL scheme 'https'"
+	CABundleRefKindErrDetail = fmt.Sprintf("must reference a %s or %s", CABundleKindConfigMap, CABundleKindSecret)
+	CABundleRefNameErrDetail = "must reference a CA bundle by name"
+
+	// ServiceAccountNameErrDetail is formatted with the validation messages of the ServiceAccount name.
+	ServiceAccountNameErrDetail = "must be a valid ServiceAccount name: %s"
+
+	// The quota error details are formatted with the namespace quota and the usage of the other Subscriptions.
+	QuotaSubscriptionsErrDetail = "must not exceed the namespace quota of %d Subscriptions"
+	QuotaEventTypesErrDetail    = "must not exceed the namespace quota of %d event types, " +
+		"the other Subscriptions of the namespace use %d"
+	QuotaMaxInFlightErrDetail = "the sum of " + MaxInFlightMessages + " must not exceed the namespace quota of %d, " +
+		"the other Subscriptions of the namespace use %d"
+
+	TTLAndExpiryDateErrDetail = "must not be set together with the expiryDate"
+	PositiveTTLErrDetail      = "must be greater than 0"
+
+	// PendingMessagesErrDetail is formatted with the pending messages and the deletion protection threshold.
+	PendingMessagesErrDetail = "must be set to \"true\" to delete a Subscription with %d pending messages, " +
+		"which exceeds the deletion protection threshold of %d"
+
+	InvalidBackendErrDetail = fmt.Sprintf("must select the backend %s or %s", BackendNATS, BackendEventMesh)
+
+	EmptyFilterErrDetail       = "must define at least one exact, prefix or suffix attribute"
+	InvalidFilterAttrErrDetail = "must only contain lower-case alphanumeric CloudEvent attribute names: "
+	EmptyFilterPrefixErrDetail = "must not have empty prefix or suffix values for attribute: "
+	UnsupportedFilterErrDetail = "must not be set for Subscriptions of the backend " + BackendEventMesh
+
+	InvalidFilterExpressionErrDetail = "must be a valid CloudEvents SQL expression: "
 )
 
 func MakeInvalidFieldError(path *field.Path, subName, detail string) *field.Error {
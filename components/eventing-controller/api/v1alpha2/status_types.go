@@ -50,6 +50,14 @@ type Backend struct {
 	// List of mappings from event type to EventMesh compatible types. Used only with EventMesh as the backend.
 	// +optional
 	EmsTypes []EventMeshTypes `json:"emsTypes,omitempty"`
+
+	// List of event type to topic mappings for the Kafka backend.
+	// +optional
+	KafkaTypes []KafkaTypes `json:"kafkaTypes,omitempty"`
+
+	// Name of the Kafka consumer group consuming the topics of the Subscription.
+	// +optional
+	KafkaConsumerGroup string `json:"kafkaConsumerGroup,omitempty"`
 }
 
 type EventMeshSubscriptionStatus struct {
@@ -79,6 +87,9 @@ type JetStreamTypes struct {
 	OriginalType string `json:"originalType"`
 	// Name of the JetStream consumer created for the event type.
 	ConsumerName string `json:"consumerName,omitempty"`
+	// Number of concrete subjects in the stream currently matched by a wildcard event type.
+	// +optional
+	MatchedSubjects *int `json:"matchedSubjects,omitempty"`
 }
 
 type EventMeshTypes struct {
@@ -88,6 +99,13 @@ type EventMeshTypes struct {
 	EventMeshType string `json:"eventMeshType"`
 }
 
+type KafkaTypes struct {
+	// Event type that was originally used to subscribe.
+	OriginalType string `json:"originalType"`
+	// Name of the Kafka topic of the event type.
+	Topic string `json:"topic"`
+}
+
 // CopyHashes copies the precomputed hashes from the given backend.
 func (b *Backend) CopyHashes(src Backend) {
 	b.Ev2hash = src.Ev2hash
@@ -2,7 +2,14 @@ package v1alpha2
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
+
+	cesql "github.com/cloudevents/sdk-go/sql/v2"
+	cesqlparser "github.com/cloudevents/sdk-go/sql/v2/parser"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 
@@ -11,6 +18,7 @@ import (
 
 	"github.com/kyma-project/kyma/components/eventing-controller/utils"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -18,6 +26,30 @@ type TypeMatching string
 
 var Finalizer = GroupVersion.Group
 
+// ForceDeleteAnnotation allows removing the Subscription finalizer even if the backend resources
+// could not be cleaned up. It is only honored if set to "true".
+const ForceDeleteAnnotation = "eventing.kyma-project.io/force-delete"
+
+// ExplainAnnotation makes the controller explain in the Subscription status why the Subscription is not ready.
+// It is only honored if set to "true".
+const ExplainAnnotation = "eventing.kyma-project.io/explain"
+
+// BackendAnnotation selects the backend of an individual Subscription if a secondary backend runs next to
+// the primary one. Its value is either BackendNATS or BackendEventMesh.
+const BackendAnnotation = "eventing.kyma-project.io/backend"
+
+const (
+	BackendNATS      = "nats"
+	BackendEventMesh = "eventmesh"
+)
+
+// The backend types shown in the Subscription status.
+const (
+	BackendTypeNATS      = "NATS"
+	BackendTypeKafka     = "Kafka"
+	BackendTypeEventMesh = "EventMesh"
+)
+
 // Defines the desired state of the Subscription.
 type SubscriptionSpec struct {
 	// Unique identifier of the Subscription, read-only.
@@ -25,8 +57,15 @@ type SubscriptionSpec struct {
 	ID string `json:"id,omitempty"`
 
 	// Kubernetes Service that should be used as a target for the events that match the Subscription.
-	// Must exist in the same Namespace as the Subscription.
-	Sink string `json:"sink"`
+	// Must exist in the same Namespace as the Subscription. Either the sink or the sinkRef must be set.
+	// +optional
+	Sink string `json:"sink,omitempty"`
+
+	// Reference to the Kubernetes Service or serverless Function that should be used as a target for the events that
+	// match the Subscription. The controller resolves it to an `http` sink URL. Either the sink or the sinkRef must
+	// be set.
+	// +optional
+	SinkRef *SinkReference `json:"sinkRef,omitempty"`
 
 	// Defines how types should be handled.<br />
 	// - `standard`: backend-specific logic will be applied to the configured source and types.<br />
@@ -37,11 +76,118 @@ type SubscriptionSpec struct {
 	Source string `json:"source"`
 
 	// List of event types that will be used for subscribing on the backend.
+	// With the `exact` type matching, a type segment can be the wildcard `*`, matching any single segment,
+	// or the last segment can be `>`, matching one or more segments. Wildcards are only supported by the NATS backend.
 	Types []string `json:"types"`
 
 	// Map of configuration options that will be applied on the backend.
 	// +optional
 	Config map[string]string `json:"config,omitempty"`
+
+	// List of filters on CloudEvent context attributes and extensions which an event must match
+	// in addition to the configured source and types. All filters must match for the event to be dispatched.
+	// +optional
+	Filters []SubscriptionFilter `json:"filters,omitempty"`
+
+	// CloudEvents SQL (CESQL) expression on CloudEvent context attributes and extensions which an event must match
+	// in addition to the configured source, types and filters, for example `subject LIKE 'order-%' AND priority > 2`.
+	// Not supported by the EventMesh backend.
+	// +optional
+	FilterExpression string `json:"filterExpression,omitempty"`
+
+	// Defines how the certificate of an HTTPS sink is verified when dispatching events to it. Requires a sink URL
+	// with the `https` scheme, so it cannot be used with a sinkRef. Not supported by the EventMesh backend.
+	// +optional
+	SinkTLS *SinkTLSConfig `json:"sinkTLS,omitempty"`
+
+	// Name of a ServiceAccount in the Namespace of the Subscription. A token of the ServiceAccount with the sink URL
+	// as audience is sent as bearer token in the Authorization header of the dispatched events, for sinks protected
+	// by the Kubernetes token review or an Istio RequestAuthentication. Not supported by the EventMesh backend.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Date after which the Subscription expires. An expired Subscription stops the delivery of events
+	// and is marked as `Expired`. Must not be set together with the ttl.
+	// +optional
+	ExpiryDate *metav1.Time `json:"expiryDate,omitempty"`
+
+	// Time to live of the Subscription, counted from its creation, for example `72h`.
+	// Must not be set together with the expiryDate.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// Deletes the Subscription when it expires instead of only stopping the delivery of events.
+	// +optional
+	DeleteOnExpiry bool `json:"deleteOnExpiry,omitempty"`
+}
+
+// SinkReference references the Kubernetes object used as the sink of a Subscription, either a Service or a
+// serverless Function.
+type SinkReference struct {
+	// API version of the referenced object.
+	// +kubebuilder:validation:Enum=v1;serverless.kyma-project.io/v1alpha2
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referenced object.
+	// +kubebuilder:validation:Enum=Service;Function
+	Kind string `json:"kind"`
+
+	// Name of the referenced object.
+	Name string `json:"name"`
+
+	// Namespace of the referenced object, defaults to the Namespace of the Subscription.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Port of the referenced Service, defaults to the scheme default port.
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Path appended to the resolved sink URL.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// SinkTLSConfig defines the TLS trust used by the dispatcher for the sink of a single Subscription.
+type SinkTLSConfig struct {
+	// Reference to the PEM-encoded CA bundle used to verify the sink certificate
+	// in addition to the system trust store.
+	// +optional
+	CABundleRef *CABundleReference `json:"caBundleRef,omitempty"`
+
+	// Disables the verification of the sink certificate. Must only be used for testing.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// CABundleReference references a key of a ConfigMap or Secret in the Namespace of the Subscription.
+type CABundleReference struct {
+	// Kind of the referenced resource.
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	Kind string `json:"kind"`
+
+	// Name of the referenced resource.
+	Name string `json:"name"`
+
+	// Key of the CA bundle in the referenced resource, defaults to `ca.crt`.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// SubscriptionFilter defines matching rules on CloudEvent context attributes and extensions.
+// All the attributes given in a filter must match for the filter to match.
+type SubscriptionFilter struct {
+	// Map of attribute names to values which must be equal to the event attribute values.
+	// +optional
+	Exact map[string]string `json:"exact,omitempty"`
+
+	// Map of attribute names to values which must be a prefix of the event attribute values.
+	// +optional
+	Prefix map[string]string `json:"prefix,omitempty"`
+
+	// Map of attribute names to values which must be a suffix of the event attribute values.
+	// +optional
+	Suffix map[string]string `json:"suffix,omitempty"`
 }
 
 // SubscriptionStatus defines the observed state of Subscription.
@@ -59,12 +205,37 @@ type SubscriptionStatus struct {
 
 	// Backend-specific status which is applicable to the active backend only.
 	Backend Backend `json:"backend,omitempty"`
+
+	// Sink URL resolved from the sinkRef.
+	// +optional
+	SinkURI string `json:"sinkURI,omitempty"`
+
+	// Human-readable explanation of why the Subscription is not ready.
+	// Only set if the Subscription has the `eventing.kyma-project.io/explain: "true"` annotation.
+	// +optional
+	Explanation string `json:"explanation,omitempty"`
+
+	// Type of the backend which reconciles the Subscription, either `NATS`, `Kafka`, or `EventMesh`.
+	// +optional
+	BackendType string `json:"backendType,omitempty"`
+
+	// Number of the event types of the Subscription.
+	// +optional
+	TypeCount int `json:"typeCount,omitempty"`
+
+	// Number of the messages which are not delivered or not acknowledged yet. Only set for the NATS backend,
+	// and refreshed at most every 30 seconds when the Subscription is reconciled.
+	// +optional
+	Backlog *int `json:"backlog,omitempty"`
 }
 
 // +kubebuilder:storageversion
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Backend",type="string",JSONPath=".status.backendType"
+// +kubebuilder:printcolumn:name="Types",type="integer",JSONPath=".status.typeCount"
+// +kubebuilder:printcolumn:name="Backlog",type="integer",JSONPath=".status.backlog"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Subscription is the Schema for the subscriptions API.
@@ -90,6 +261,8 @@ func (s Subscription) MarshalJSON() ([]byte, error) {
 }
 
 // GetMaxInFlightMessages tries to convert the string-type maxInFlight to the integer.
+// The defaulting webhook only runs on create and update, so the backends can still receive Subscriptions without
+// maxInFlight, e.g. the ones stored as v1alpha1 without a config, which are converted on read. These use the defaults.
 func (s *Subscription) GetMaxInFlightMessages(defaults *env.DefaultSubscriptionConfig) int {
 	val, err := strconv.Atoi(s.Spec.Config[MaxInFlightMessages])
 	if err != nil {
@@ -98,6 +271,194 @@ func (s *Subscription) GetMaxInFlightMessages(defaults *env.DefaultSubscriptionC
 	return val
 }
 
+// GetDeliveryMode returns the NATS delivery mode of the Subscription, or the default one if it is not configured.
+func (s *Subscription) GetDeliveryMode(defaults *env.DefaultSubscriptionConfig) string {
+	if mode, ok := s.Spec.Config[DeliveryMode]; ok {
+		return mode
+	}
+	if defaults.DeliveryMode == "" {
+		return DeliveryModeJetStream
+	}
+	return defaults.DeliveryMode
+}
+
+// ParseFilterExpression parses a CloudEvents SQL filter expression, it returns nil for an empty expression.
+func ParseFilterExpression(expression string) (parsed cesql.Expression, err error) {
+	if expression == "" {
+		return nil, nil
+	}
+	// the parser panics instead of returning an error for some syntax errors, e.g. an incomplete LIKE
+	defer func() {
+		if r := recover(); r != nil {
+			parsed, err = nil, errors.New("syntax error")
+		}
+	}()
+	if parsed, err = cesqlparser.Parse(expression); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// IsForceDeleteRequested returns true if the Subscription has the ForceDeleteAnnotation set to "true".
+func (s *Subscription) IsForceDeleteRequested() bool {
+	return s.Annotations[ForceDeleteAnnotation] == "true"
+}
+
+// SelectedBackend returns the backend selected by the BackendAnnotation or an empty string if none is selected.
+func (s *Subscription) SelectedBackend() string {
+	return s.Annotations[BackendAnnotation]
+}
+
+// IsReconciledBy returns true if the Subscription is reconciled by the given backend. Only the Subscriptions
+// selecting the secondary backend are reconciled by it, all the others by the primary backend.
+// If no secondary backend runs, the primary backend reconciles all Subscriptions.
+func (s *Subscription) IsReconciledBy(backend, secondaryBackend string) bool {
+	if secondaryBackend == "" {
+		return true
+	}
+	return (s.SelectedBackend() == secondaryBackend) == (backend == secondaryBackend)
+}
+
+// ExpiryTime returns the time at which the Subscription expires, based on its expiryDate or ttl.
+// It returns false if the Subscription does not expire.
+func (s *Subscription) ExpiryTime() (time.Time, bool) {
+	if s.Spec.ExpiryDate != nil {
+		return s.Spec.ExpiryDate.Time, true
+	}
+	if s.Spec.TTL != nil {
+		return s.CreationTimestamp.Add(s.Spec.TTL.Duration), true
+	}
+	return time.Time{}, false
+}
+
+// RequeueUntilExpiry shortens the given requeue delay of a reconciliation to the expiry of the Subscription,
+// so that the Subscription is reconciled again when it expires. A zero delay means that it is not requeued.
+func (s *Subscription) RequeueUntilExpiry(requeueAfter time.Duration, now time.Time) time.Duration {
+	expiryTime, ok := s.ExpiryTime()
+	if !ok {
+		return requeueAfter
+	}
+	if untilExpiry := expiryTime.Sub(now); requeueAfter == 0 || untilExpiry < requeueAfter {
+		return untilExpiry
+	}
+	return requeueAfter
+}
+
+// IsExpired returns true if the Subscription expired at the given time.
+func (s *Subscription) IsExpired(now time.Time) bool {
+	expiryTime, ok := s.ExpiryTime()
+	return ok && !now.Before(expiryTime)
+}
+
+// IsExplainRequested returns true if the Subscription has the ExplainAnnotation set to "true".
+func (s *Subscription) IsExplainRequested() bool {
+	return s.Annotations[ExplainAnnotation] == "true"
+}
+
+// SyncExplanation sets the status explanation if it is requested by the ExplainAnnotation, otherwise clears it.
+func (s *Subscription) SyncExplanation() {
+	if !s.IsExplainRequested() {
+		s.Status.Explanation = ""
+		return
+	}
+	s.Status.Explanation = s.Explain()
+}
+
+// SyncSummary sets the status fields which summarize the Subscription in the output of
+// `kubectl get subscriptions`, apart from the backlog which is counted by the backend.
+func (s *Subscription) SyncSummary(backendType string) {
+	s.Status.BackendType = backendType
+	s.Status.TypeCount = len(s.Status.Types)
+}
+
+// Explain returns a human-readable explanation of why the Subscription is not ready, based on its
+// conditions and backend status. It returns an empty string if the Subscription is ready.
+func (s *Subscription) Explain() string {
+	if s.Status.Ready {
+		return ""
+	}
+
+	var reasons []string
+	for _, c := range s.Status.Conditions {
+		if c.Status == corev1.ConditionTrue {
+			continue
+		}
+		reason := fmt.Sprintf("condition %q is %s", c.Type, c.Status)
+		if c.Reason != "" {
+			reason += fmt.Sprintf(" with reason %q", c.Reason)
+		}
+		if c.Message != "" {
+			reason += ": " + c.Message
+		}
+		reasons = append(reasons, reason)
+	}
+
+	backend := s.Status.Backend
+	if backend.FailedActivation != "" {
+		reasons = append(reasons, "EventMesh activation failed: "+backend.FailedActivation)
+	}
+	if emsStatus := backend.EventMeshSubscriptionStatus; emsStatus != nil && emsStatus.Status != "" {
+		reason := "EventMesh reports the status " + emsStatus.Status
+		if emsStatus.StatusReason != "" {
+			reason += ": " + emsStatus.StatusReason
+		}
+		reasons = append(reasons, reason)
+	}
+	if len(backend.Types) > 0 {
+		consumers := make([]string, 0, len(backend.Types))
+		for _, t := range backend.Types {
+			consumers = append(consumers, fmt.Sprintf("%s (type %s)", t.ConsumerName, t.OriginalType))
+		}
+		reasons = append(reasons, "JetStream consumers: "+strings.Join(consumers, ", "))
+	}
+	if len(backend.KafkaTypes) > 0 {
+		topics := make([]string, 0, len(backend.KafkaTypes))
+		for _, t := range backend.KafkaTypes {
+			topics = append(topics, fmt.Sprintf("%s (type %s)", t.Topic, t.OriginalType))
+		}
+		reasons = append(reasons, fmt.Sprintf("Kafka topics consumed by the group %s: %s",
+			backend.KafkaConsumerGroup, strings.Join(topics, ", ")))
+	}
+
+	if len(reasons) == 0 {
+		return "Subscription is not ready: no failure is reported yet, the Subscription is still being reconciled."
+	}
+	return "Subscription is not ready: " + strings.Join(reasons, "; ") + "."
+}
+
+// IsFunction returns true if the reference is to a serverless Function.
+func (r *SinkReference) IsFunction() bool {
+	return r.APIVersion == FunctionAPIVersion && r.Kind == SinkRefKindFunction
+}
+
+// URL returns the cluster-local URL of the referenced Service. A Function is served by the Service of its name.
+// The given namespace is used if the reference does not set one.
+func (r *SinkReference) URL(namespace string) string {
+	if r.Namespace != "" {
+		namespace = r.Namespace
+	}
+	host := fmt.Sprintf("%s.%s.%s", r.Name, namespace, ClusterLocalURLSuffix)
+	if r.Port != 0 {
+		host = fmt.Sprintf("%s:%d", host, r.Port)
+	}
+	path := r.Path
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return "http://" + host + path
+}
+
+// ResolveSink sets the spec sink and the status sinkURI to the URL of the sinkRef if it is set.
+// The resolved sink is meant for the backends and is not persisted in the spec.
+func (s *Subscription) ResolveSink() {
+	if s.Spec.SinkRef == nil {
+		s.Status.SinkURI = ""
+		return
+	}
+	s.Spec.Sink = s.Spec.SinkRef.URL(s.Namespace)
+	s.Status.SinkURI = s.Spec.Sink
+}
+
 // InitializeEventTypes initializes the SubscriptionStatus.Types with an empty slice of EventType.
 func (s *SubscriptionStatus) InitializeEventTypes() {
 	s.Types = []EventType{}
@@ -115,6 +476,11 @@ func (s *Subscription) GetUniqueTypes() []string {
 	return result
 }
 
+// IsWildcardType returns true if the given event type contains a wildcard segment.
+func IsWildcardType(eventType string) bool {
+	return strings.ContainsAny(eventType, TypeWildcardToken+TypeWildcardTailToken)
+}
+
 func (s *Subscription) DuplicateWithStatusDefaults() *Subscription {
 	desiredSub := s.DeepCopy()
 	desiredSub.Status = SubscriptionStatus{}
@@ -2,11 +2,14 @@ package v1alpha2_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestGetMaxInFlightMessages(t *testing.T) {
@@ -66,3 +69,369 @@ func TestGetMaxInFlightMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDeliveryMode(t *testing.T) {
+	testCases := []struct {
+		name              string
+		givenDefaults     env.DefaultSubscriptionConfig
+		givenSubscription *v1alpha2.Subscription
+		wantResult        string
+	}{
+		{
+			name:              "should give jetstream if neither the Subscription nor the defaults set a delivery mode",
+			givenDefaults:     env.DefaultSubscriptionConfig{},
+			givenSubscription: &v1alpha2.Subscription{},
+			wantResult:        v1alpha2.DeliveryModeJetStream,
+		},
+		{
+			name:              "should give the default delivery mode if it is missing in the Subscription config",
+			givenDefaults:     env.DefaultSubscriptionConfig{DeliveryMode: v1alpha2.DeliveryModeCore},
+			givenSubscription: &v1alpha2.Subscription{},
+			wantResult:        v1alpha2.DeliveryModeCore,
+		},
+		{
+			name:          "should give the delivery mode of the Subscription config",
+			givenDefaults: env.DefaultSubscriptionConfig{DeliveryMode: v1alpha2.DeliveryModeCore},
+			givenSubscription: &v1alpha2.Subscription{
+				Spec: v1alpha2.SubscriptionSpec{
+					Config: map[string]string{v1alpha2.DeliveryMode: v1alpha2.DeliveryModeJetStream},
+				},
+			},
+			wantResult: v1alpha2.DeliveryModeJetStream,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			result := tc.givenSubscription.GetDeliveryMode(&tc.givenDefaults)
+
+			assert.Equal(t, tc.wantResult, result)
+		})
+	}
+}
+
+func Test_ResolveSink(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		givenRef    *v1alpha2.SinkReference
+		givenSink   string
+		wantSink    string
+		wantSinkURI string
+	}{
+		{
+			name:      "sink without sinkRef should not be changed",
+			givenSink: "http://orders.shop.svc.cluster.local",
+			wantSink:  "http://orders.shop.svc.cluster.local",
+		},
+		{
+			name:        "sinkRef should be resolved to the service URL",
+			givenRef:    &v1alpha2.SinkReference{APIVersion: "v1", Kind: "Service", Name: "orders"},
+			wantSink:    "http://orders.shop.svc.cluster.local",
+			wantSinkURI: "http://orders.shop.svc.cluster.local",
+		},
+		{
+			name: "sinkRef with port and path should be resolved to the service URL",
+			givenRef: &v1alpha2.SinkReference{
+				APIVersion: "v1", Kind: "Service", Name: "orders", Namespace: "shop", Port: 8080, Path: "events",
+			},
+			wantSink:    "http://orders.shop.svc.cluster.local:8080/events",
+			wantSinkURI: "http://orders.shop.svc.cluster.local:8080/events",
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// given
+			sub := &v1alpha2.Subscription{}
+			sub.Namespace = "shop"
+			sub.Spec.Sink = tc.givenSink
+			sub.Spec.SinkRef = tc.givenRef
+
+			// when
+			sub.ResolveSink()
+
+			// then
+			assert.Equal(t, tc.wantSink, sub.Spec.Sink)
+			assert.Equal(t, tc.wantSinkURI, sub.Status.SinkURI)
+		})
+	}
+}
+
+func Test_SyncExplanation(t *testing.T) {
+	t.Parallel()
+	notActive := v1alpha2.MakeCondition(v1alpha2.ConditionSubscriptionActive,
+		v1alpha2.ConditionReasonNATSSubscriptionNotActive, corev1.ConditionFalse, "failed to add a consumer")
+	reachable := v1alpha2.MakeCondition(v1alpha2.ConditionSinkReachable,
+		v1alpha2.ConditionReasonSinkReachable, corev1.ConditionTrue, "")
+
+	testCases := []struct {
+		name            string
+		givenAnnotation string
+		givenStatus     v1alpha2.SubscriptionStatus
+		wantExplanation string
+	}{
+		{
+			name:        "explanation should not be set without the annotation",
+			givenStatus: v1alpha2.SubscriptionStatus{Conditions: []v1alpha2.Condition{notActive}},
+		},
+		{
+			name:            "explanation should be empty for a ready subscription",
+			givenAnnotation: "true",
+			givenStatus:     v1alpha2.SubscriptionStatus{Ready: true, Conditions: []v1alpha2.Condition{reachable}},
+		},
+		{
+			name:            "explanation should list the failing conditions and the consumers",
+			givenAnnotation: "true",
+			givenStatus: v1alpha2.SubscriptionStatus{
+				Conditions: []v1alpha2.Condition{notActive, reachable},
+				Backend: v1alpha2.Backend{Types: []v1alpha2.JetStreamTypes{
+					{OriginalType: "order.created.v1", ConsumerName: "abc123"},
+				}},
+			},
+			wantExplanation: `Subscription is not ready: condition "Subscription active" is False ` +
+				`with reason "NATS Subscription not active": failed to add a consumer; ` +
+				`JetStream consumers: abc123 (type order.created.v1).`,
+		},
+		{
+			name:            "explanation should report the EventMesh status",
+			givenAnnotation: "true",
+			givenStatus: v1alpha2.SubscriptionStatus{
+				Backend: v1alpha2.Backend{EventMeshSubscriptionStatus: &v1alpha2.EventMeshSubscriptionStatus{
+					Status: "Paused", StatusReason: "paused by the user",
+				}},
+			},
+			wantExplanation: "Subscription is not ready: EventMesh reports the status Paused: paused by the user.",
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// given
+			sub := &v1alpha2.Subscription{Status: tc.givenStatus}
+			sub.Status.Explanation = "stale"
+			sub.Annotations = map[string]string{v1alpha2.ExplainAnnotation: tc.givenAnnotation}
+
+			// when
+			sub.SyncExplanation()
+
+			// then
+			assert.Equal(t, tc.wantExplanation, sub.Status.Explanation)
+		})
+	}
+}
+
+func Test_SyncSummary(t *testing.T) {
+	t.Parallel()
+	sub := &v1alpha2.Subscription{Status: v1alpha2.SubscriptionStatus{Types: []v1alpha2.EventType{
+		{OriginalType: "order.created.v1", CleanType: "order.created.v1"},
+		{OriginalType: "order.updated.v1", CleanType: "order.updated.v1"},
+	}}}
+
+	sub.SyncSummary(v1alpha2.BackendTypeKafka)
+
+	assert.Equal(t, v1alpha2.BackendTypeKafka, sub.Status.BackendType)
+	assert.Equal(t, 2, sub.Status.TypeCount)
+	assert.Nil(t, sub.Status.Backlog)
+}
+
+func Test_IsReconciledBy(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name             string
+		givenAnnotation  string
+		secondaryBackend string
+		wantNATS         bool
+		wantEventMesh    bool
+	}{
+		{
+			name:          "both backends reconcile all subscriptions without a secondary backend",
+			wantNATS:      true,
+			wantEventMesh: true,
+		},
+		{
+			name:             "primary backend reconciles subscriptions without a selected backend",
+			secondaryBackend: v1alpha2.BackendEventMesh,
+			wantNATS:         true,
+			wantEventMesh:    false,
+		},
+		{
+			name:             "secondary backend reconciles subscriptions selecting it",
+			givenAnnotation:  v1alpha2.BackendEventMesh,
+			secondaryBackend: v1alpha2.BackendEventMesh,
+			wantNATS:         false,
+			wantEventMesh:    true,
+		},
+		{
+			name:             "primary backend reconciles subscriptions selecting it",
+			givenAnnotation:  v1alpha2.BackendNATS,
+			secondaryBackend: v1alpha2.BackendEventMesh,
+			wantNATS:         true,
+			wantEventMesh:    false,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// given
+			sub := &v1alpha2.Subscription{}
+			if tc.givenAnnotation != "" {
+				sub.Annotations = map[string]string{v1alpha2.BackendAnnotation: tc.givenAnnotation}
+			}
+
+			// when, then
+			assert.Equal(t, tc.wantNATS, sub.IsReconciledBy(v1alpha2.BackendNATS, tc.secondaryBackend))
+			assert.Equal(t, tc.wantEventMesh, sub.IsReconciledBy(v1alpha2.BackendEventMesh, tc.secondaryBackend))
+		})
+	}
+}
+
+func Test_IsExpired(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name           string
+		givenSpec      v1alpha2.SubscriptionSpec
+		wantExpiryTime time.Time
+		wantExpires    bool
+		wantExpired    bool
+	}{
+		{
+			name:        "subscription without expiry never expires",
+			wantExpires: false,
+			wantExpired: false,
+		},
+		{
+			name:           "subscription expires at the expiry date",
+			givenSpec:      v1alpha2.SubscriptionSpec{ExpiryDate: &metav1.Time{Time: created.Add(time.Hour)}},
+			wantExpiryTime: created.Add(time.Hour),
+			wantExpires:    true,
+			wantExpired:    true,
+		},
+		{
+			name:           "subscription expires after the ttl counted from its creation",
+			givenSpec:      v1alpha2.SubscriptionSpec{TTL: &metav1.Duration{Duration: 3 * time.Hour}},
+			wantExpiryTime: created.Add(3 * time.Hour),
+			wantExpires:    true,
+			wantExpired:    false,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// given
+			sub := &v1alpha2.Subscription{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Time{Time: created}},
+				Spec:       tc.givenSpec,
+			}
+
+			// when
+			expiryTime, expires := sub.ExpiryTime()
+
+			// then
+			assert.Equal(t, tc.wantExpires, expires)
+			assert.Equal(t, tc.wantExpiryTime, expiryTime)
+			assert.Equal(t, tc.wantExpired, sub.IsExpired(created.Add(2*time.Hour)))
+		})
+	}
+}
+
+func Test_RequeueUntilExpiry(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	testCases := []struct {
+		name              string
+		givenExpiryDate   *metav1.Time
+		givenRequeueAfter time.Duration
+		wantRequeueAfter  time.Duration
+	}{
+		{
+			name:              "subscription without expiry",
+			givenRequeueAfter: time.Minute,
+			wantRequeueAfter:  time.Minute,
+		},
+		{
+			name:              "subscription expiring before the requeue",
+			givenExpiryDate:   &metav1.Time{Time: now.Add(time.Second)},
+			givenRequeueAfter: time.Minute,
+			wantRequeueAfter:  time.Second,
+		},
+		{
+			name:              "subscription expiring after the requeue",
+			givenExpiryDate:   &metav1.Time{Time: now.Add(time.Hour)},
+			givenRequeueAfter: time.Minute,
+			wantRequeueAfter:  time.Minute,
+		},
+		{
+			name:             "subscription expiring without a requeue",
+			givenExpiryDate:  &metav1.Time{Time: now.Add(time.Hour)},
+			wantRequeueAfter: time.Hour,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// given
+			sub := &v1alpha2.Subscription{Spec: v1alpha2.SubscriptionSpec{ExpiryDate: tc.givenExpiryDate}}
+
+			// when
+			requeueAfter := sub.RequeueUntilExpiry(tc.givenRequeueAfter, now)
+
+			// then
+			assert.Equal(t, tc.wantRequeueAfter, requeueAfter)
+		})
+	}
+}
+
+func Test_ParseFilterExpression(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name            string
+		givenExpression string
+		wantExpression  bool
+		wantErr         bool
+	}{
+		{
+			name:            "empty expression",
+			givenExpression: "",
+			wantExpression:  false,
+			wantErr:         false,
+		},
+		{
+			name:            "valid expression",
+			givenExpression: "subject LIKE 'orders/%' AND EXISTS region",
+			wantExpression:  true,
+			wantErr:         false,
+		},
+		{
+			name:            "incomplete expression",
+			givenExpression: "subject = 'orders' AND",
+			wantExpression:  false,
+			wantErr:         true,
+		},
+		{
+			name:            "expression on which the parser panics",
+			givenExpression: "subject LIKE",
+			wantExpression:  false,
+			wantErr:         true,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// when
+			expression, err := v1alpha2.ParseFilterExpression(tc.givenExpression)
+
+			// then
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.wantExpression, expression != nil)
+		})
+	}
+}
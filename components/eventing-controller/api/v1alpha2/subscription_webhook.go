@@ -1,33 +1,186 @@
 package v1alpha2
 
 import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
 
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 	"github.com/kyma-project/kyma/components/eventing-controller/utils"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+// validAttributeName matches CloudEvent attribute names as defined by the CloudEvents specification.
+var validAttributeName = regexp.MustCompile(`^[a-z0-9]+$`)
+
 const (
 	DefaultMaxInFlightMessages = "10"
+	defaultMaxInFlightMessages = 10
 	minEventTypeSegments       = 2
 	subdomainSegments          = 5
+	maxPort                    = 65535
+	maxWildcardSegments        = 2
 	InvalidPrefix              = "sap.kyma.custom"
 	ClusterLocalURLSuffix      = "svc.cluster.local"
 	ValidSource                = "source"
+
+	// TypeWildcardToken matches any single segment of an event type.
+	TypeWildcardToken = "*"
+	// TypeWildcardTailToken matches one or more trailing segments of an event type.
+	TypeWildcardTailToken = ">"
+
+	// AllowExternalSinkAnnotation marks a Subscription as allowed to use a sink outside the cluster.
+	// It only takes effect if the ExternalSinkPolicyAnnotated policy is active.
+	AllowExternalSinkAnnotation = "eventing.kyma-project.io/allow-external-sink"
+
+	// ExternalSinkPolicyDeny rejects all sinks which are not cluster local.
+	ExternalSinkPolicyDeny = "deny"
+	// ExternalSinkPolicyAnnotated accepts external sinks only for Subscriptions with the AllowExternalSinkAnnotation.
+	ExternalSinkPolicyAnnotated = "annotated"
+	// ExternalSinkPolicyAllow accepts all external sinks.
+	ExternalSinkPolicyAllow = "allow"
+
+	// SinkRefKindService is the kind of the sinkRef referencing a Service.
+	SinkRefKindService = "Service"
+	// SinkRefKindFunction is the kind of the sinkRef referencing a serverless Function.
+	SinkRefKindFunction = "Function"
+	// FunctionAPIVersion is the API version of the serverless Functions.
+	FunctionAPIVersion = "serverless.kyma-project.io/v1alpha2"
 )
 
+//nolint:gochecknoglobals // using global vars because there is no runtime object to hold these instances.
+var (
+	// sinkServiceLookup reports whether the Service with the given name exists in the given namespace.
+	sinkServiceLookup    func(namespace, name string) (bool, error)
+	externalSinkPolicy   = ExternalSinkPolicyDeny
+	subscriptionDefaults = env.DefaultSubscriptionConfig{
+		MaxInFlightMessages: defaultMaxInFlightMessages,
+	}
+
+	// namespaceSubscriptions lists the Subscriptions of the given namespace for the quota validation.
+	namespaceSubscriptions func(namespace string) ([]Subscription, error)
+	subscriptionQuota      env.SubscriptionQuota
+
+	// pendingMessages counts the pending messages of a Subscription for the deletion protection.
+	pendingMessages             func(sub *Subscription) (int, error)
+	deletionProtectionThreshold int
+
+	// cleanedTypesValidation validates the event types as cleaned by the active backend.
+	cleanedTypesValidation func(eventTypes []string) error
+
+	// subscriptionAudit records the changes of the Subscriptions admitted by the validating webhook.
+	subscriptionAudit func(req admission.Request, oldSub, newSub *Subscription)
+
+	// primaryBackend and secondaryBackend determine the backend reconciling a Subscription,
+	// to reject the fields it does not support.
+	primaryBackend, secondaryBackend string
+
+	// initializeMutex guards the values above which are initialized again when the configuration is reloaded
+	// or the backend is restarted, while the webhook reads them.
+	initializeMutex sync.RWMutex
+)
+
+// InitializeDefaults sets the values used by the defaulting webhook for the omitted Subscription spec fields.
+func InitializeDefaults(defaults env.DefaultSubscriptionConfig) {
+	initializeMutex.Lock()
+	defer initializeMutex.Unlock()
+	subscriptionDefaults = defaults
+}
+
+// getSubscriptionDefaults returns the values used for the omitted Subscription spec fields.
+func getSubscriptionDefaults() env.DefaultSubscriptionConfig {
+	initializeMutex.RLock()
+	defer initializeMutex.RUnlock()
+	return subscriptionDefaults
+}
+
+// InitializeSinkValidation sets the Service lookup and the cluster-wide external sink policy used by the webhook.
+// The existence of the sink Service is not verified if the lookup is nil.
+func InitializeSinkValidation(lookup func(namespace, name string) (bool, error), policy string) error {
+	switch policy {
+	case ExternalSinkPolicyDeny, ExternalSinkPolicyAnnotated, ExternalSinkPolicyAllow:
+	default:
+		return fmt.Errorf("invalid external sink policy: %q", policy)
+	}
+	initializeMutex.Lock()
+	defer initializeMutex.Unlock()
+	sinkServiceLookup = lookup
+	externalSinkPolicy = policy
+	return nil
+}
+
+// InitializeQuota sets the per-namespace quota enforced by the webhook and the lister of the namespace Subscriptions.
+// The quota is not enforced if the lister is nil.
+func InitializeQuota(quota env.SubscriptionQuota, lister func(namespace string) ([]Subscription, error)) {
+	initializeMutex.Lock()
+	defer initializeMutex.Unlock()
+	subscriptionQuota = quota
+	namespaceSubscriptions = lister
+}
+
+// InitializeDeletionProtection sets the threshold of pending messages above which the webhook rejects
+// the deletion of a Subscription and the counter of its pending messages.
+// The deletion is not protected if the threshold is 0 or the counter is nil.
+func InitializeDeletionProtection(threshold int, counter func(sub *Subscription) (int, error)) {
+	initializeMutex.Lock()
+	defer initializeMutex.Unlock()
+	deletionProtectionThreshold = threshold
+	pendingMessages = counter
+}
+
+// InitializeCleanedTypesValidation sets the validation of the event types as cleaned by the active backend,
+// which rejects the event types the backend cannot use after cleaning. They are not validated if it is nil.
+func InitializeCleanedTypesValidation(validation func(eventTypes []string) error) {
+	initializeMutex.Lock()
+	defer initializeMutex.Unlock()
+	cleanedTypesValidation = validation
+}
+
+// InitializeAudit sets the recorder of the Subscription changes admitted by the validating webhook, which gets the
+// admission request of each change. The old Subscription is nil on create, the new one on delete.
+// The changes are not recorded if the recorder is nil.
+func InitializeAudit(recorder func(req admission.Request, oldSub, newSub *Subscription)) {
+	initializeMutex.Lock()
+	defer initializeMutex.Unlock()
+	subscriptionAudit = recorder
+}
+
+// InitializeBackends sets the primary backend reconciling the Subscriptions and the secondary backend reconciling
+// the Subscriptions selecting it, if any. The Subscriptions are not validated against a backend if it is empty.
+func InitializeBackends(primary, secondary string) {
+	initializeMutex.Lock()
+	defer initializeMutex.Unlock()
+	primaryBackend, secondaryBackend = primary, secondary
+}
+
+// reconcilingBackend returns the backend reconciling the Subscription, or an empty string if it is unknown.
+func (s *Subscription) reconcilingBackend() string {
+	initializeMutex.RLock()
+	defer initializeMutex.RUnlock()
+	if secondaryBackend != "" && s.SelectedBackend() == secondaryBackend {
+		return secondaryBackend
+	}
+	return primaryBackend
+}
+
 func (s *Subscription) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(s).
+		WithValidator(subscriptionValidator{}).
 		Complete()
 }
 
@@ -37,39 +190,122 @@ func (s *Subscription) SetupWebhookWithManager(mgr ctrl.Manager) error {
 var _ webhook.Defaulter = &Subscription{}
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
+// The omitted fields are set from the defaults passed to InitializeDefaults, so the stored objects are explicit.
 func (s *Subscription) Default() {
+	defaults := getSubscriptionDefaults()
 	if s.Spec.TypeMatching == "" {
 		s.Spec.TypeMatching = TypeMatchingStandard
 	}
+	// The source is only required for the standard type matching.
+	if s.Spec.Source == "" && s.Spec.TypeMatching == TypeMatchingStandard {
+		s.Spec.Source = defaults.Source
+	}
 	if s.Spec.Config[MaxInFlightMessages] == "" {
 		if s.Spec.Config == nil {
 			s.Spec.Config = map[string]string{}
 		}
-		s.Spec.Config[MaxInFlightMessages] = DefaultMaxInFlightMessages
+		s.Spec.Config[MaxInFlightMessages] = strconv.Itoa(defaults.MaxInFlightMessages)
 	}
 }
 
 //nolint: lll
-//+kubebuilder:webhook:path=/validate-eventing-kyma-project-io-v1alpha2-subscription,mutating=false,failurePolicy=fail,sideEffects=None,groups=eventing.kyma-project.io,resources=subscriptions,verbs=create;update,versions=v1alpha2,name=vsubscription.kb.io,admissionReviewVersions=v1beta1
+//+kubebuilder:webhook:path=/validate-eventing-kyma-project-io-v1alpha2-subscription,mutating=false,failurePolicy=fail,sideEffects=None,groups=eventing.kyma-project.io,resources=subscriptions,verbs=create;update;delete,versions=v1alpha2,name=vsubscription.kb.io,admissionReviewVersions=v1beta1
 
 var _ webhook.Validator = &Subscription{}
 
+// subscriptionValidator validates the Subscriptions with their webhook.Validator implementation, and records the
+// admitted changes with the admission request, which is only passed to a custom validator.
+type subscriptionValidator struct{}
+
+var _ admission.CustomValidator = subscriptionValidator{}
+
+// ValidateCreate implements admission.CustomValidator.
+func (subscriptionValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	sub, ok := obj.(*Subscription)
+	if !ok {
+		return nil, fmt.Errorf("expected a Subscription but got %T", obj)
+	}
+	warnings, err := sub.ValidateCreate()
+	if err == nil {
+		recordAdmitted(ctx, nil, sub)
+	}
+	return warnings, err
+}
+
+// ValidateUpdate implements admission.CustomValidator.
+func (subscriptionValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings,
+	error) {
+	oldSub, ok := oldObj.(*Subscription)
+	if !ok {
+		return nil, fmt.Errorf("expected a Subscription but got %T", oldObj)
+	}
+	newSub, ok := newObj.(*Subscription)
+	if !ok {
+		return nil, fmt.Errorf("expected a Subscription but got %T", newObj)
+	}
+	warnings, err := newSub.ValidateUpdate(oldSub)
+	if err == nil {
+		recordAdmitted(ctx, oldSub, newSub)
+	}
+	return warnings, err
+}
+
+// ValidateDelete implements admission.CustomValidator.
+func (subscriptionValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	sub, ok := obj.(*Subscription)
+	if !ok {
+		return nil, fmt.Errorf("expected a Subscription but got %T", obj)
+	}
+	warnings, err := sub.ValidateDelete()
+	if err == nil {
+		recordAdmitted(ctx, sub, nil)
+	}
+	return warnings, err
+}
+
+// recordAdmitted records the admitted change of a Subscription with the admission request of the given context,
+// if the changes are audited.
+func recordAdmitted(ctx context.Context, oldSub, newSub *Subscription) {
+	initializeMutex.RLock()
+	recorder := subscriptionAudit
+	initializeMutex.RUnlock()
+	if recorder == nil {
+		return
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return
+	}
+	recorder(req, oldSub, newSub)
+}
+
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (s *Subscription) ValidateCreate() (admission.Warnings, error) {
 	return s.ValidateSubscription()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (s *Subscription) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
-	return s.ValidateSubscription()
+// A Subscription which is being deleted is not validated, so that its finalizer can always be removed.
+func (s *Subscription) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	if s.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	oldSub, _ := old.(*Subscription)
+	return s.validateSubscription(oldSub)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (s *Subscription) ValidateDelete() (admission.Warnings, error) {
-	return nil, nil
+	return s.validateDeletionProtection()
 }
 
+// ValidateSubscription validates the Subscription as it is created.
 func (s *Subscription) ValidateSubscription() (admission.Warnings, error) {
+	return s.validateSubscription(nil)
+}
+
+// validateSubscription validates the Subscription which replaces the given old one, or is created if it is nil.
+func (s *Subscription) validateSubscription(old *Subscription) (admission.Warnings, error) {
 	var allErrs field.ErrorList
 
 	if err := s.validateSubscriptionSource(); err != nil {
@@ -81,9 +317,32 @@ func (s *Subscription) ValidateSubscription() (admission.Warnings, error) {
 	if err := s.validateSubscriptionConfig(); err != nil {
 		allErrs = append(allErrs, err...)
 	}
-	if err := s.validateSubscriptionSink(); err != nil {
+	// the sink Service is only looked up if the sink changes, because it may be deleted before the Subscription
+	if err := s.validateSubscriptionSink(old == nil || s.isSinkChanged(old)); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := s.validateSubscriptionFilters(); err != nil {
+		allErrs = append(allErrs, err...)
+	}
+	if err := s.validateSubscriptionFilterExpression(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := s.validateSubscriptionSinkTLS(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := s.validateSubscriptionServiceAccountName(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := s.validateSubscriptionBackend(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if err := s.validateSubscriptionExpiry(); err != nil {
 		allErrs = append(allErrs, err)
 	}
+	if len(allErrs) == 0 {
+		// the quota is only checked for otherwise valid Subscriptions, because it has to list the namespace
+		allErrs = s.validateNamespaceQuota(old)
+	}
 	if len(allErrs) == 0 {
 		return nil, nil
 	}
@@ -119,11 +378,68 @@ func (s *Subscription) validateSubscriptionTypes() *field.Error {
 		if s.Spec.TypeMatching != TypeMatchingExact && strings.HasPrefix(etype, InvalidPrefix) {
 			return MakeInvalidFieldError(TypesPath, s.Name, InvalidPrefixErrDetail)
 		}
+		if IsWildcardType(etype) {
+			if errDetail := s.validateWildcardType(etype); errDetail != "" {
+				return MakeInvalidFieldError(TypesPath, s.Name, errDetail)
+			}
+		}
 		// Check only is the event type is valid for the cloud event, with a valid source.
 		if IsInvalidCE(ValidSource, etype) {
 			return MakeInvalidFieldError(TypesPath, s.Name, InvalidURIErrDetail)
 		}
 	}
+	// the event types are only cleaned for the standard type matching
+	initializeMutex.RLock()
+	validation := cleanedTypesValidation
+	initializeMutex.RUnlock()
+	if s.Spec.TypeMatching != TypeMatchingExact && validation != nil {
+		if err := validation(s.Spec.Types); err != nil {
+			return MakeInvalidFieldError(TypesPath, s.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// validateWildcardType returns the error detail if the given wildcard event type is not permitted.
+// Wildcards must be whole segments, the first segment must not be a wildcard and the number of
+// wildcard segments is capped to limit the number of subjects a single type can match.
+func (s *Subscription) validateWildcardType(etype string) string {
+	if s.Spec.TypeMatching != TypeMatchingExact {
+		return WildcardTypeMatchingErrDetail
+	}
+	segments := strings.Split(etype, ".")
+	wildcards := 0
+	for i, segment := range segments {
+		if segment != TypeWildcardToken && segment != TypeWildcardTailToken {
+			if IsWildcardType(segment) {
+				return WildcardSegmentErrDetail
+			}
+			continue
+		}
+		if i == 0 {
+			return WildcardFirstSegmentErrDetail
+		}
+		if segment == TypeWildcardTailToken && i != len(segments)-1 {
+			return WildcardSegmentErrDetail
+		}
+		wildcards++
+	}
+	if wildcards > maxWildcardSegments {
+		return WildcardBreadthErrDetail
+	}
+	return ""
+}
+
+// validateMaxInFlightMessagesLimit rejects a maxInFlightMessages value which is not positive
+// or exceeds the cluster-wide limit.
+func (s *Subscription) validateMaxInFlightMessagesLimit() *field.Error {
+	maxInFlight, _ := strconv.Atoi(s.Spec.Config[MaxInFlightMessages])
+	if maxInFlight < 1 {
+		return MakeInvalidFieldError(ConfigPath, s.Name, PositiveIntErrDetail)
+	}
+	if limit := getSubscriptionDefaults().MaxInFlightMessagesLimit; limit > 0 && maxInFlight > limit {
+		return MakeInvalidFieldError(ConfigPath, s.Name, fmt.Sprintf(MaxInFlightLimitErrDetail, limit))
+	}
 	return nil
 }
 
@@ -131,6 +447,12 @@ func (s *Subscription) validateSubscriptionConfig() field.ErrorList {
 	var allErrs field.ErrorList
 	if isNotInt(s.Spec.Config[MaxInFlightMessages]) {
 		allErrs = append(allErrs, MakeInvalidFieldError(ConfigPath, s.Name, StringIntErrDetail))
+	} else if err := s.validateMaxInFlightMessagesLimit(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+	if s.ifKeyExistsInConfig(DeliveryMode) && s.Spec.Config[DeliveryMode] != DeliveryModeJetStream &&
+		s.Spec.Config[DeliveryMode] != DeliveryModeCore {
+		allErrs = append(allErrs, MakeInvalidFieldError(ConfigPath, s.Name, DeliveryModeErrDetail))
 	}
 	if s.ifKeyExistsInConfig(ProtocolSettingsQos) && types.IsInvalidQoS(s.Spec.Config[ProtocolSettingsQos]) {
 		allErrs = append(allErrs, MakeInvalidFieldError(ConfigPath, s.Name, InvalidQosErrDetail))
@@ -144,7 +466,17 @@ func (s *Subscription) validateSubscriptionConfig() field.ErrorList {
 	return allErrs
 }
 
-func (s *Subscription) validateSubscriptionSink() *field.Error {
+// isSinkChanged returns true if the sink or the sinkRef of the Subscription differs from the given old one.
+func (s *Subscription) isSinkChanged(old *Subscription) bool {
+	return s.Spec.Sink != old.Spec.Sink || !reflect.DeepEqual(s.Spec.SinkRef, old.Spec.SinkRef)
+}
+
+// validateSubscriptionSink validates the sink or the sinkRef of the Subscription, and that it resolves to an
+// existing Service if lookupService is true.
+func (s *Subscription) validateSubscriptionSink(lookupService bool) *field.Error {
+	if s.Spec.SinkRef != nil {
+		return s.validateSubscriptionSinkRef(lookupService)
+	}
 	if s.Spec.Sink == "" {
 		return MakeInvalidFieldError(SinkPath, s.Name, EmptyErrDetail)
 	}
@@ -158,8 +490,14 @@ func (s *Subscription) validateSubscriptionSink() *field.Error {
 		return MakeInvalidFieldError(SinkPath, s.Name, err.Error())
 	}
 
-	// Validate sink URL is a cluster local URL.
+	// Validate sink URL is a cluster local URL, unless external sinks are permitted.
 	if !strings.HasSuffix(trimmedHost, ClusterLocalURLSuffix) {
+		if s.IsExternalSinkAllowed() {
+			return nil
+		}
+		if getExternalSinkPolicy() == ExternalSinkPolicyAnnotated {
+			return MakeInvalidFieldError(SinkPath, s.Name, ExternalSinkNotAnnotatedErrDetail)
+		}
 		return MakeInvalidFieldError(SinkPath, s.Name, SuffixMissingErrDetail)
 	}
 
@@ -174,6 +512,238 @@ func (s *Subscription) validateSubscriptionSink() *field.Error {
 		return MakeInvalidFieldError(NSPath, s.Name, NSMismatchErrDetail+svcNs)
 	}
 
+	// Validate the sink resolves to an existing Service.
+	if !lookupService {
+		return nil
+	}
+	return s.validateSinkService(SinkPath, svcNs, subDomains[0])
+}
+
+func (s *Subscription) validateSubscriptionSinkRef(lookupService bool) *field.Error {
+	ref := s.Spec.SinkRef
+	if s.Spec.Sink != "" {
+		return MakeInvalidFieldError(SinkRefPath, s.Name, SinkAndSinkRefErrDetail)
+	}
+	if (ref.APIVersion != "v1" || ref.Kind != SinkRefKindService) && !ref.IsFunction() {
+		return MakeInvalidFieldError(SinkRefPath, s.Name, SinkRefKindErrDetail)
+	}
+	if ref.Name == "" {
+		return MakeInvalidFieldError(SinkRefPath.Child("name"), s.Name, EmptyErrDetail)
+	}
+	if ref.Namespace != "" && ref.Namespace != s.Namespace {
+		return MakeInvalidFieldError(NSPath, s.Name, NSMismatchErrDetail+ref.Namespace)
+	}
+	if ref.Port < 0 || ref.Port > maxPort {
+		return MakeInvalidFieldError(SinkRefPath.Child("port"), s.Name, SinkRefPortErrDetail)
+	}
+	if _, err := url.ParseRequestURI(ref.URL(s.Namespace)); err != nil {
+		return MakeInvalidFieldError(SinkRefPath.Child("path"), s.Name, InvalidURIErrDetail)
+	}
+	// the Service of a Function is only created once the Function is built, so it is checked by the controller
+	if ref.IsFunction() || !lookupService {
+		return nil
+	}
+	return s.validateSinkService(SinkRefPath, s.Namespace, ref.Name)
+}
+
+// validateSinkService validates that the sink Service exists if a Service lookup is initialized.
+func (s *Subscription) validateSinkService(path *field.Path, svcNs, svcName string) *field.Error {
+	initializeMutex.RLock()
+	lookup := sinkServiceLookup
+	initializeMutex.RUnlock()
+	if lookup == nil {
+		return nil
+	}
+	exists, err := lookup(svcNs, svcName)
+	if err != nil {
+		return field.InternalError(path, fmt.Errorf("failed to look up sink service %s/%s: %w", svcNs, svcName, err))
+	}
+	if !exists {
+		return MakeInvalidFieldError(path, s.Name, fmt.Sprintf(ServiceNotFoundErrDetail, svcName, svcNs))
+	}
+	return nil
+}
+
+// IsExternalSinkAllowed returns true if the cluster-wide policy permits the Subscription to use a sink
+// outside the cluster.
+func (s *Subscription) IsExternalSinkAllowed() bool {
+	switch getExternalSinkPolicy() {
+	case ExternalSinkPolicyAllow:
+		return true
+	case ExternalSinkPolicyAnnotated:
+		return s.Annotations[AllowExternalSinkAnnotation] == "true"
+	}
+	return false
+}
+
+// getExternalSinkPolicy returns the cluster-wide external sink policy.
+func getExternalSinkPolicy() string {
+	initializeMutex.RLock()
+	defer initializeMutex.RUnlock()
+	return externalSinkPolicy
+}
+
+func (s *Subscription) validateSubscriptionSinkTLS() *field.Error {
+	sinkTLS := s.Spec.SinkTLS
+	if sinkTLS == nil {
+		return nil
+	}
+	if !strings.HasPrefix(s.Spec.Sink, "https://") {
+		return MakeInvalidFieldError(SinkTLSPath, s.Name, SinkTLSSchemeErrDetail)
+	}
+	if sinkTLS.CABundleRef == nil {
+		return nil
+	}
+	if sinkTLS.CABundleRef.Kind != CABundleKindConfigMap && sinkTLS.CABundleRef.Kind != CABundleKindSecret {
+		return MakeInvalidFieldError(SinkTLSPath.Child("caBundleRef"), s.Name, CABundleRefKindErrDetail)
+	}
+	if sinkTLS.CABundleRef.Name == "" {
+		return MakeInvalidFieldError(SinkTLSPath.Child("caBundleRef"), s.Name, CABundleRefNameErrDetail)
+	}
+	return nil
+}
+
+func (s *Subscription) validateSubscriptionServiceAccountName() *field.Error {
+	if s.Spec.ServiceAccountName == "" {
+		return nil
+	}
+	if msgs := validation.IsDNS1123Subdomain(s.Spec.ServiceAccountName); len(msgs) > 0 {
+		return MakeInvalidFieldError(ServiceAccountNamePath, s.Name,
+			fmt.Sprintf(ServiceAccountNameErrDetail, strings.Join(msgs, ", ")))
+	}
+	return nil
+}
+
+func (s *Subscription) validateSubscriptionExpiry() *field.Error {
+	if s.Spec.TTL == nil {
+		return nil
+	}
+	if s.Spec.ExpiryDate != nil {
+		return MakeInvalidFieldError(TTLPath, s.Name, TTLAndExpiryDateErrDetail)
+	}
+	if s.Spec.TTL.Duration <= 0 {
+		return MakeInvalidFieldError(TTLPath, s.Name, PositiveTTLErrDetail)
+	}
+	return nil
+}
+
+// validateDeletionProtection rejects the deletion of a Subscription with more pending messages than the deletion
+// protection threshold, unless the force-delete annotation is set. The deletion is allowed with a warning
+// if the pending messages cannot be counted.
+func (s *Subscription) validateDeletionProtection() (admission.Warnings, error) {
+	initializeMutex.RLock()
+	threshold, counter := deletionProtectionThreshold, pendingMessages
+	initializeMutex.RUnlock()
+	if counter == nil || threshold <= 0 || s.IsForceDeleteRequested() {
+		return nil, nil
+	}
+	pending, err := counter(s)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("failed to count the pending messages of the subscription: %v", err)}, nil
+	}
+	if pending <= threshold {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GroupKind, s.Name, field.ErrorList{MakeInvalidFieldError(ForceDeletePath, s.Name,
+		fmt.Sprintf(PendingMessagesErrDetail, pending, threshold))})
+}
+
+// validateNamespaceQuota rejects the Subscription if the namespace exceeds the Subscription quota with it.
+// On update, only the quota of the usage the Subscription grows compared to the given old one is enforced,
+// so that a namespace exceeding a lowered quota can still update its Subscriptions, e.g. their finalizers.
+func (s *Subscription) validateNamespaceQuota(old *Subscription) field.ErrorList {
+	initializeMutex.RLock()
+	quota, lister := subscriptionQuota, namespaceSubscriptions
+	initializeMutex.RUnlock()
+	if lister == nil || !quota.IsEnabled() {
+		return nil
+	}
+	defaults := getSubscriptionDefaults()
+	checkSubscriptions := old == nil
+	checkEventTypes := old == nil || len(s.GetUniqueTypes()) > len(old.GetUniqueTypes())
+	checkMaxInFlight := old == nil || s.GetMaxInFlightMessages(&defaults) > old.GetMaxInFlightMessages(&defaults)
+	if !checkSubscriptions && !checkEventTypes && !checkMaxInFlight {
+		return nil
+	}
+	subs, err := lister(s.Namespace)
+	if err != nil {
+		return field.ErrorList{field.InternalError(NSPath, fmt.Errorf("failed to list subscriptions: %w", err))}
+	}
+
+	// sum up the usage of the other Subscriptions, the Subscription itself is replaced on update
+	var otherSubs, eventTypes, maxInFlight int
+	for i := range subs {
+		if subs[i].Name == s.Name {
+			continue
+		}
+		otherSubs++
+		eventTypes += len(subs[i].GetUniqueTypes())
+		maxInFlight += subs[i].GetMaxInFlightMessages(&defaults)
+	}
+
+	var allErrs field.ErrorList
+	if checkSubscriptions && quota.MaxSubscriptions > 0 && otherSubs+1 > quota.MaxSubscriptions {
+		allErrs = append(allErrs, MakeInvalidFieldError(NSPath, s.Name,
+			fmt.Sprintf(QuotaSubscriptionsErrDetail, quota.MaxSubscriptions)))
+	}
+	if checkEventTypes && quota.MaxEventTypes > 0 && eventTypes+len(s.GetUniqueTypes()) > quota.MaxEventTypes {
+		allErrs = append(allErrs, MakeInvalidFieldError(TypesPath, s.Name,
+			fmt.Sprintf(QuotaEventTypesErrDetail, quota.MaxEventTypes, eventTypes)))
+	}
+	if checkMaxInFlight && quota.MaxInFlightMessages > 0 &&
+		maxInFlight+s.GetMaxInFlightMessages(&defaults) > quota.MaxInFlightMessages {
+		allErrs = append(allErrs, MakeInvalidFieldError(ConfigPath, s.Name,
+			fmt.Sprintf(QuotaMaxInFlightErrDetail, quota.MaxInFlightMessages, maxInFlight)))
+	}
+	return allErrs
+}
+
+func (s *Subscription) validateSubscriptionBackend() *field.Error {
+	backend, ok := s.Annotations[BackendAnnotation]
+	if !ok || backend == BackendNATS || backend == BackendEventMesh {
+		return nil
+	}
+	return MakeInvalidFieldError(BackendPath, s.Name, InvalidBackendErrDetail)
+}
+
+func (s *Subscription) validateSubscriptionFilters() field.ErrorList {
+	// EventMesh does not support the attribute filters, which would be ignored
+	if len(s.Spec.Filters) > 0 && s.reconcilingBackend() == BackendEventMesh {
+		return field.ErrorList{MakeInvalidFieldError(FilterPath, s.Name, UnsupportedFilterErrDetail)}
+	}
+	var allErrs field.ErrorList
+	for i, f := range s.Spec.Filters {
+		path := FilterPath.Index(i)
+		if len(f.Exact) == 0 && len(f.Prefix) == 0 && len(f.Suffix) == 0 {
+			allErrs = append(allErrs, MakeInvalidFieldError(path, s.Name, EmptyFilterErrDetail))
+			continue
+		}
+		for _, attributes := range []map[string]string{f.Exact, f.Prefix, f.Suffix} {
+			for name := range attributes {
+				if !validAttributeName.MatchString(name) {
+					allErrs = append(allErrs, MakeInvalidFieldError(path, s.Name, InvalidFilterAttrErrDetail+name))
+				}
+			}
+		}
+		for _, attributes := range []map[string]string{f.Prefix, f.Suffix} {
+			for name, value := range attributes {
+				if value == "" {
+					allErrs = append(allErrs, MakeInvalidFieldError(path, s.Name, EmptyFilterPrefixErrDetail+name))
+				}
+			}
+		}
+	}
+	return allErrs
+}
+
+func (s *Subscription) validateSubscriptionFilterExpression() *field.Error {
+	// EventMesh does not support the filter expressions either, which would be ignored
+	if s.Spec.FilterExpression != "" && s.reconcilingBackend() == BackendEventMesh {
+		return MakeInvalidFieldError(FilterExpressionPath, s.Name, UnsupportedFilterErrDetail)
+	}
+	if _, err := ParseFilterExpression(s.Spec.FilterExpression); err != nil {
+		return MakeInvalidFieldError(FilterExpressionPath, s.Name, InvalidFilterExpressionErrDetail+err.Error())
+	}
 	return nil
 }
 
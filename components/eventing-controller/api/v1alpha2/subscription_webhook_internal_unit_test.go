@@ -0,0 +1,62 @@
+package v1alpha2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func Test_subscriptionValidator_Audit(t *testing.T) {
+	// given
+	type admitted struct {
+		user           string
+		oldSub, newSub *Subscription
+	}
+	var got []admitted
+	InitializeAudit(func(req admission.Request, oldSub, newSub *Subscription) {
+		got = append(got, admitted{user: req.UserInfo.Username, oldSub: oldSub, newSub: newSub})
+	})
+	defer InitializeAudit(nil)
+
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "alice"}},
+	})
+	sub := &Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "test"},
+		Spec: SubscriptionSpec{
+			Source: "source",
+			Types:  []string{"order.created.v1"},
+			Sink:   "https://eventing-nats.test.svc.cluster.local:8080",
+			Config: map[string]string{MaxInFlightMessages: "10"},
+		},
+	}
+	updatedSub := sub.DeepCopy()
+	updatedSub.Spec.Types = []string{"order.updated.v1"}
+	invalidSub := sub.DeepCopy()
+	invalidSub.Spec.Types = nil
+	validator := subscriptionValidator{}
+
+	// when
+	_, createErr := validator.ValidateCreate(ctx, sub)
+	_, invalidErr := validator.ValidateCreate(ctx, invalidSub)
+	_, updateErr := validator.ValidateUpdate(ctx, sub, updatedSub)
+	_, deleteErr := validator.ValidateDelete(ctx, updatedSub)
+	_, noRequestErr := validator.ValidateCreate(context.Background(), sub)
+
+	// then the admitted changes are recorded with the user of the admission request
+	require.NoError(t, createErr)
+	require.Error(t, invalidErr)
+	require.NoError(t, updateErr)
+	require.NoError(t, deleteErr)
+	require.NoError(t, noRequestErr)
+	require.Equal(t, []admitted{
+		{user: "alice", newSub: sub},
+		{user: "alice", oldSub: sub, newSub: updatedSub},
+		{user: "alice", oldSub: updatedSub},
+	}, got)
+}
@@ -1,13 +1,22 @@
 package v1alpha2_test
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
 )
 
@@ -79,8 +88,74 @@ func Test_Default(t *testing.T) {
 	}
 }
 
+// Test_DefaultWithInitializedDefaults is not parallel because it changes the package-wide defaults.
+func Test_DefaultWithInitializedDefaults(t *testing.T) {
+	// given
+	v1alpha2.InitializeDefaults(env.DefaultSubscriptionConfig{MaxInFlightMessages: 25, Source: "kyma"})
+	defer v1alpha2.InitializeDefaults(env.DefaultSubscriptionConfig{MaxInFlightMessages: 10})
+
+	testCases := []struct {
+		name     string
+		givenSub *v1alpha2.Subscription
+		wantSub  *v1alpha2.Subscription
+	}{
+		{
+			name: "Add configured source and MaxInFlightMessages value",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			),
+			wantSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource("kyma"),
+				eventingtesting.WithMaxInFlightMessages("25"),
+			),
+		},
+		{
+			name: "Keep the given source",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			),
+			wantSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithMaxInFlightMessages("25"),
+			),
+		},
+		{
+			name: "Do not add a source for exact type matching",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithTypeMatchingExact(),
+			),
+			wantSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithTypeMatchingExact(),
+				eventingtesting.WithMaxInFlightMessages("25"),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			tc.givenSub.Default()
+
+			// then
+			require.Equal(t, tc.wantSub, tc.givenSub)
+		})
+	}
+}
+
 func Test_validateSubscription(t *testing.T) {
 	t.Parallel()
+	invalidFilterExpression := "subject = 'orders' AND"
+	_, invalidFilterExpressionErr := v1alpha2.ParseFilterExpression(invalidFilterExpression)
+	require.Error(t, invalidFilterExpressionErr)
+
 	type TestCase struct {
 		name     string
 		givenSub *v1alpha2.Subscription
@@ -256,6 +331,35 @@ func Test_validateSubscription(t *testing.T) {
 				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.ConfigPath,
 					subName, v1alpha2.StringIntErrDetail)}),
 		},
+		{
+			name: "non-positive maxInFlight value should return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages("0"),
+				eventingtesting.WithSink(sink),
+			),
+			wantErr: apierrors.NewInvalid(
+				v1alpha2.GroupKind, subName,
+				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.ConfigPath,
+					subName, v1alpha2.PositiveIntErrDetail)}),
+		},
+		{
+			name: "invalid delivery mode should return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithConfigValue(v1alpha2.DeliveryMode, "stream"),
+				eventingtesting.WithSink(sink),
+			),
+			wantErr: apierrors.NewInvalid(
+				v1alpha2.GroupKind, subName,
+				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.ConfigPath,
+					subName, v1alpha2.DeliveryModeErrDetail)}),
+		},
 		{
 			name: "invalid QoS value should return error",
 			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
@@ -399,6 +503,159 @@ func Test_validateSubscription(t *testing.T) {
 				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.NSPath,
 					subName, v1alpha2.NSMismatchErrDetail+"kyma-system")}),
 		},
+		{
+			name: "valid attribute filters should not return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithSink(sink),
+				eventingtesting.WithFilters(v1alpha2.SubscriptionFilter{
+					Exact:  map[string]string{"region": "eu"},
+					Prefix: map[string]string{"subject": "orders/"},
+				}),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "empty attribute filter should return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithSink(sink),
+				eventingtesting.WithFilters(v1alpha2.SubscriptionFilter{}),
+			),
+			wantErr: apierrors.NewInvalid(
+				v1alpha2.GroupKind, subName,
+				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.FilterPath.Index(0),
+					subName, v1alpha2.EmptyFilterErrDetail)}),
+		},
+		{
+			name: "invalid attribute name and empty prefix in filter should return errors",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithSink(sink),
+				eventingtesting.WithFilters(v1alpha2.SubscriptionFilter{
+					Exact:  map[string]string{"Region-1": "eu"},
+					Prefix: map[string]string{"subject": ""},
+				}),
+			),
+			wantErr: apierrors.NewInvalid(
+				v1alpha2.GroupKind, subName,
+				field.ErrorList{
+					v1alpha2.MakeInvalidFieldError(v1alpha2.FilterPath.Index(0),
+						subName, v1alpha2.InvalidFilterAttrErrDetail+"Region-1"),
+					v1alpha2.MakeInvalidFieldError(v1alpha2.FilterPath.Index(0),
+						subName, v1alpha2.EmptyFilterPrefixErrDetail+"subject"),
+				}),
+		},
+		{
+			name: "valid filter expression should not return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithSink(sink),
+				eventingtesting.WithFilterExpression("subject LIKE 'orders/%' AND region IN ('eu', 'us')"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "invalid filter expression should return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithSink(sink),
+				eventingtesting.WithFilterExpression(invalidFilterExpression),
+			),
+			wantErr: apierrors.NewInvalid(
+				v1alpha2.GroupKind, subName,
+				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.FilterExpressionPath,
+					subName, v1alpha2.InvalidFilterExpressionErrDetail+invalidFilterExpressionErr.Error())}),
+		},
+		{
+			name: "sink TLS settings with a CA bundle reference should not return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithSink(sink),
+				eventingtesting.WithSinkTLS(&v1alpha2.SinkTLSConfig{
+					CABundleRef: &v1alpha2.CABundleReference{Kind: v1alpha2.CABundleKindSecret, Name: "ca"},
+				}),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "sink TLS settings for a http sink should return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithSink("http://eventing-nats.test.svc.cluster.local"),
+				eventingtesting.WithSinkTLS(&v1alpha2.SinkTLSConfig{InsecureSkipVerify: true}),
+			),
+			wantErr: apierrors.NewInvalid(
+				v1alpha2.GroupKind, subName,
+				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.SinkTLSPath,
+					subName, v1alpha2.SinkTLSSchemeErrDetail)}),
+		},
+		{
+			name: "sink TLS settings with an invalid CA bundle reference kind should return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithSink(sink),
+				eventingtesting.WithSinkTLS(&v1alpha2.SinkTLSConfig{
+					CABundleRef: &v1alpha2.CABundleReference{Kind: "Pod", Name: "ca"},
+				}),
+			),
+			wantErr: apierrors.NewInvalid(
+				v1alpha2.GroupKind, subName,
+				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.SinkTLSPath.Child("caBundleRef"),
+					subName, v1alpha2.CABundleRefKindErrDetail)}),
+		},
+		{
+			name: "valid service account name should not return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithSink(sink),
+				eventingtesting.WithServiceAccountName("event-dispatcher"),
+			),
+			wantErr: nil,
+		},
+		{
+			name: "invalid service account name should return error",
+			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
+				eventingtesting.WithTypeMatchingStandard(),
+				eventingtesting.WithSource(eventingtesting.EventSourceClean),
+				eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+				eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+				eventingtesting.WithSink(sink),
+				eventingtesting.WithServiceAccountName("Event_Dispatcher"),
+			),
+			wantErr: apierrors.NewInvalid(
+				v1alpha2.GroupKind, subName,
+				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.ServiceAccountNamePath,
+					subName, fmt.Sprintf(v1alpha2.ServiceAccountNameErrDetail,
+						strings.Join(validation.IsDNS1123Subdomain("Event_Dispatcher"), ", ")))}),
+		},
 		{
 			name: "multiple errors should be reported if exists",
 			givenSub: eventingtesting.NewSubscription(subName, subNamespace,
@@ -426,6 +683,686 @@ func Test_validateSubscription(t *testing.T) {
 	}
 }
 
+// Test_validateSubscriptionSinkPolicy is not parallel because it changes the package-wide sink validation settings.
+func Test_validateSubscriptionSinkPolicy(t *testing.T) {
+	externalSink := "https://example.com/events"
+	lookupErr := errors.New("connection refused")
+	newSub := func(sink string, annotated bool) *v1alpha2.Subscription {
+		sub := eventingtesting.NewSubscription(subName, subNamespace,
+			eventingtesting.WithTypeMatchingStandard(),
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+			eventingtesting.WithSink(sink),
+		)
+		if annotated {
+			sub.Annotations = map[string]string{v1alpha2.AllowExternalSinkAnnotation: "true"}
+		}
+		return sub
+	}
+	invalidSinkErr := func(fieldErr *field.Error) error {
+		return apierrors.NewInvalid(v1alpha2.GroupKind, subName, field.ErrorList{fieldErr})
+	}
+
+	testCases := []struct {
+		name        string
+		givenPolicy string
+		givenLookup func(namespace, name string) (bool, error)
+		givenSub    *v1alpha2.Subscription
+		wantErr     error
+	}{
+		{
+			name:        "external sink should be rejected by the deny policy",
+			givenPolicy: v1alpha2.ExternalSinkPolicyDeny,
+			givenSub:    newSub(externalSink, true),
+			wantErr: invalidSinkErr(v1alpha2.MakeInvalidFieldError(v1alpha2.SinkPath,
+				subName, v1alpha2.SuffixMissingErrDetail)),
+		},
+		{
+			name:        "external sink without annotation should be rejected by the annotated policy",
+			givenPolicy: v1alpha2.ExternalSinkPolicyAnnotated,
+			givenSub:    newSub(externalSink, false),
+			wantErr: invalidSinkErr(v1alpha2.MakeInvalidFieldError(v1alpha2.SinkPath,
+				subName, v1alpha2.ExternalSinkNotAnnotatedErrDetail)),
+		},
+		{
+			name:        "external sink with annotation should be accepted by the annotated policy",
+			givenPolicy: v1alpha2.ExternalSinkPolicyAnnotated,
+			givenSub:    newSub(externalSink, true),
+			wantErr:     nil,
+		},
+		{
+			name:        "external sink should be accepted by the allow policy",
+			givenPolicy: v1alpha2.ExternalSinkPolicyAllow,
+			givenSub:    newSub(externalSink, false),
+			wantErr:     nil,
+		},
+		{
+			name:        "existing sink service should be accepted",
+			givenPolicy: v1alpha2.ExternalSinkPolicyDeny,
+			givenLookup: func(_, _ string) (bool, error) { return true, nil },
+			givenSub:    newSub(sink, false),
+			wantErr:     nil,
+		},
+		{
+			name:        "missing sink service should return error",
+			givenPolicy: v1alpha2.ExternalSinkPolicyDeny,
+			givenLookup: func(_, _ string) (bool, error) { return false, nil },
+			givenSub:    newSub(sink, false),
+			wantErr: invalidSinkErr(v1alpha2.MakeInvalidFieldError(v1alpha2.SinkPath,
+				subName, fmt.Sprintf(v1alpha2.ServiceNotFoundErrDetail, "eventing-nats", subNamespace))),
+		},
+		{
+			name:        "failed sink service lookup should return error",
+			givenPolicy: v1alpha2.ExternalSinkPolicyDeny,
+			givenLookup: func(_, _ string) (bool, error) { return false, lookupErr },
+			givenSub:    newSub(sink, false),
+			wantErr: invalidSinkErr(field.InternalError(v1alpha2.SinkPath,
+				fmt.Errorf("failed to look up sink service %s/%s: %w", subNamespace, "eventing-nats", lookupErr))),
+		},
+	}
+
+	defer func() {
+		require.NoError(t, v1alpha2.InitializeSinkValidation(nil, v1alpha2.ExternalSinkPolicyDeny))
+	}()
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, v1alpha2.InitializeSinkValidation(tc.givenLookup, tc.givenPolicy))
+			_, err := tc.givenSub.ValidateSubscription()
+			require.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_validateSubscriptionSinkRef(t *testing.T) {
+	newSub := func(opts ...eventingtesting.SubscriptionOpt) *v1alpha2.Subscription {
+		opts = append([]eventingtesting.SubscriptionOpt{
+			eventingtesting.WithTypeMatchingStandard(),
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+		}, opts...)
+		return eventingtesting.NewSubscription(subName, subNamespace, opts...)
+	}
+	invalidSinkErr := func(fieldErr *field.Error) error {
+		return apierrors.NewInvalid(v1alpha2.GroupKind, subName, field.ErrorList{fieldErr})
+	}
+	withRefNamespace := func(namespace string) eventingtesting.SubscriptionOpt {
+		return func(sub *v1alpha2.Subscription) {
+			sub.Spec.SinkRef.Namespace = namespace
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		givenLookup func(namespace, name string) (bool, error)
+		givenSub    *v1alpha2.Subscription
+		wantErr     error
+	}{
+		{
+			name:        "sinkRef to an existing service should be accepted",
+			givenLookup: func(namespace, name string) (bool, error) { return namespace == subNamespace && name == "orders", nil },
+			givenSub:    newSub(eventingtesting.WithSinkRef("orders", 8080)),
+			wantErr:     nil,
+		},
+		{
+			name:        "sinkRef to a missing service should return error",
+			givenLookup: func(_, _ string) (bool, error) { return false, nil },
+			givenSub:    newSub(eventingtesting.WithSinkRef("orders", 0)),
+			wantErr: invalidSinkErr(v1alpha2.MakeInvalidFieldError(v1alpha2.SinkRefPath,
+				subName, fmt.Sprintf(v1alpha2.ServiceNotFoundErrDetail, "orders", subNamespace))),
+		},
+		{
+			name: "sinkRef together with sink should return error",
+			givenSub: newSub(eventingtesting.WithSinkRef("orders", 0),
+				eventingtesting.WithSink(sink)),
+			wantErr: invalidSinkErr(v1alpha2.MakeInvalidFieldError(v1alpha2.SinkRefPath,
+				subName, v1alpha2.SinkAndSinkRefErrDetail)),
+		},
+		{
+			name:     "sinkRef to another namespace should return error",
+			givenSub: newSub(eventingtesting.WithSinkRef("orders", 0), withRefNamespace("other")),
+			wantErr: invalidSinkErr(v1alpha2.MakeInvalidFieldError(v1alpha2.NSPath,
+				subName, v1alpha2.NSMismatchErrDetail+"other")),
+		},
+		{
+			name:        "sinkRef to a function should be accepted before its service exists",
+			givenLookup: func(_, _ string) (bool, error) { return false, nil },
+			givenSub:    newSub(eventingtesting.WithFunctionSinkRef("orders")),
+			wantErr:     nil,
+		},
+		{
+			name: "sinkRef to a function of another API version should return error",
+			givenSub: newSub(eventingtesting.WithFunctionSinkRef("orders"), func(sub *v1alpha2.Subscription) {
+				sub.Spec.SinkRef.APIVersion = "v1"
+			}),
+			wantErr: invalidSinkErr(v1alpha2.MakeInvalidFieldError(v1alpha2.SinkRefPath,
+				subName, v1alpha2.SinkRefKindErrDetail)),
+		},
+		{
+			name:     "sinkRef with an invalid port should return error",
+			givenSub: newSub(eventingtesting.WithSinkRef("orders", 70000)),
+			wantErr: invalidSinkErr(v1alpha2.MakeInvalidFieldError(v1alpha2.SinkRefPath.Child("port"),
+				subName, v1alpha2.SinkRefPortErrDetail)),
+		},
+	}
+
+	defer func() {
+		require.NoError(t, v1alpha2.InitializeSinkValidation(nil, v1alpha2.ExternalSinkPolicyDeny))
+	}()
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, v1alpha2.InitializeSinkValidation(tc.givenLookup, v1alpha2.ExternalSinkPolicyDeny))
+			_, err := tc.givenSub.ValidateSubscription()
+			require.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_validateSubscriptionUpdateSinkService(t *testing.T) {
+	// given the sink Service is deleted
+	newSub := func(opts ...eventingtesting.SubscriptionOpt) *v1alpha2.Subscription {
+		opts = append([]eventingtesting.SubscriptionOpt{
+			eventingtesting.WithTypeMatchingStandard(),
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+		}, opts...)
+		return eventingtesting.NewSubscription(subName, subNamespace, opts...)
+	}
+	defer func() {
+		require.NoError(t, v1alpha2.InitializeSinkValidation(nil, v1alpha2.ExternalSinkPolicyDeny))
+	}()
+	require.NoError(t, v1alpha2.InitializeSinkValidation(func(_, _ string) (bool, error) { return false, nil },
+		v1alpha2.ExternalSinkPolicyDeny))
+	oldSub := newSub(eventingtesting.WithSinkRef("orders", 0))
+
+	// when the sink is unchanged
+	_, errUnchanged := newSub(eventingtesting.WithSinkRef("orders", 0),
+		eventingtesting.WithFinalizers([]string{v1alpha2.Finalizer})).ValidateUpdate(oldSub)
+
+	// then the Service is not looked up
+	require.NoError(t, errUnchanged)
+
+	// when the sink is changed
+	_, errChanged := newSub(eventingtesting.WithSinkRef("payments", 0)).ValidateUpdate(oldSub)
+
+	// then the Service is looked up
+	require.Equal(t, apierrors.NewInvalid(v1alpha2.GroupKind, subName, field.ErrorList{
+		v1alpha2.MakeInvalidFieldError(v1alpha2.SinkRefPath,
+			subName, fmt.Sprintf(v1alpha2.ServiceNotFoundErrDetail, "payments", subNamespace)),
+	}), errChanged)
+
+	// when the Subscription is being deleted
+	deletedSub := newSub(eventingtesting.WithSinkRef("payments", 0))
+	deletedSub.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	_, errDeleted := deletedSub.ValidateUpdate(oldSub)
+
+	// then it is not validated
+	require.NoError(t, errDeleted)
+}
+
+func Test_validateSubscriptionMaxInFlightLimit(t *testing.T) {
+	// given
+	defer v1alpha2.InitializeDefaults(env.DefaultSubscriptionConfig{MaxInFlightMessages: 10})
+	v1alpha2.InitializeDefaults(env.DefaultSubscriptionConfig{MaxInFlightMessages: 10, MaxInFlightMessagesLimit: 100})
+	newSub := func(maxInFlight string) *v1alpha2.Subscription {
+		return eventingtesting.NewSubscription(subName, subNamespace,
+			eventingtesting.WithTypeMatchingStandard(),
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			eventingtesting.WithMaxInFlightMessages(maxInFlight),
+			eventingtesting.WithSink(sink),
+		)
+	}
+
+	// when
+	_, errWithinLimit := newSub("100").ValidateSubscription()
+	_, errAboveLimit := newSub("101").ValidateSubscription()
+
+	// then
+	require.NoError(t, errWithinLimit)
+	require.Equal(t, apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+		field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.ConfigPath,
+			subName, fmt.Sprintf(v1alpha2.MaxInFlightLimitErrDetail, 100))}), errAboveLimit)
+}
+
+func Test_validateSubscriptionWildcardTypes(t *testing.T) {
+	t.Parallel()
+	newSub := func(eventType string, matchingOpt eventingtesting.SubscriptionOpt) *v1alpha2.Subscription {
+		return eventingtesting.NewSubscription(subName, subNamespace,
+			matchingOpt,
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithTypes([]string{eventType}),
+			eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+			eventingtesting.WithSink(sink),
+		)
+	}
+	invalidTypeErr := func(errDetail string) error {
+		return apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+			field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.TypesPath, subName, errDetail)})
+	}
+
+	testCases := []struct {
+		name     string
+		givenSub *v1alpha2.Subscription
+		wantErr  error
+	}{
+		{
+			name:     "single segment wildcard should be accepted",
+			givenSub: newSub("order.*.v1", eventingtesting.WithTypeMatchingExact()),
+			wantErr:  nil,
+		},
+		{
+			name:     "trailing wildcard should be accepted",
+			givenSub: newSub("order.created.>", eventingtesting.WithTypeMatchingExact()),
+			wantErr:  nil,
+		},
+		{
+			name:     "wildcard with standard type matching should return error",
+			givenSub: newSub("order.*.v1", eventingtesting.WithTypeMatchingStandard()),
+			wantErr:  invalidTypeErr(v1alpha2.WildcardTypeMatchingErrDetail),
+		},
+		{
+			name:     "wildcard within a segment should return error",
+			givenSub: newSub("order.created*.v1", eventingtesting.WithTypeMatchingExact()),
+			wantErr:  invalidTypeErr(v1alpha2.WildcardSegmentErrDetail),
+		},
+		{
+			name:     "trailing wildcard which is not the last segment should return error",
+			givenSub: newSub("order.>.v1", eventingtesting.WithTypeMatchingExact()),
+			wantErr:  invalidTypeErr(v1alpha2.WildcardSegmentErrDetail),
+		},
+		{
+			name:     "wildcard as first segment should return error",
+			givenSub: newSub("*.created.v1", eventingtesting.WithTypeMatchingExact()),
+			wantErr:  invalidTypeErr(v1alpha2.WildcardFirstSegmentErrDetail),
+		},
+		{
+			name:     "too many wildcard segments should return error",
+			givenSub: newSub("order.*.*.>", eventingtesting.WithTypeMatchingExact()),
+			wantErr:  invalidTypeErr(v1alpha2.WildcardBreadthErrDetail),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := tc.givenSub.ValidateSubscription()
+			require.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_validateSubscriptionCleanedTypes(t *testing.T) {
+	// given
+	newSub := func(matchingOpt eventingtesting.SubscriptionOpt, eventTypes ...string) *v1alpha2.Subscription {
+		return eventingtesting.NewSubscription(subName, subNamespace,
+			matchingOpt,
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithTypes(eventTypes),
+			eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+			eventingtesting.WithSink(sink),
+		)
+	}
+	defaultLogger, err := logger.New("json", "info")
+	require.NoError(t, err)
+	jsCleaner := cleaner.NewJetStreamCleaner(defaultLogger)
+	v1alpha2.InitializeCleanedTypesValidation(func(eventTypes []string) error {
+		return cleaner.ValidateEventTypes(jsCleaner, eventTypes)
+	})
+	defer v1alpha2.InitializeCleanedTypesValidation(nil)
+
+	testCases := []struct {
+		name        string
+		givenSub    *v1alpha2.Subscription
+		wantErrText string
+	}{
+		{
+			name:     "should accept types which clean to distinct types",
+			givenSub: newSub(eventingtesting.WithTypeMatchingStandard(), "order.created.v1", "order.created/.v2"),
+		},
+		{
+			name:     "should reject a type which cleans to an empty segment",
+			givenSub: newSub(eventingtesting.WithTypeMatchingStandard(), "order.//.v1"),
+			wantErrText: `type "order.//.v1" is cleaned to "order..v1" with the empty segment 2, ` +
+				`because the characters '/' are not supported`,
+		},
+		{
+			name:     "should reject types which clean to the same type",
+			givenSub: newSub(eventingtesting.WithTypeMatchingStandard(), "order.created.v1", "order.created/.v1"),
+			wantErrText: `types "order.created.v1" and "order.created/.v1" are both cleaned to "order.created.v1", ` +
+				`because the characters '/' are not supported`,
+		},
+		{
+			name:     "should not validate the cleaned types for the exact type matching",
+			givenSub: newSub(eventingtesting.WithTypeMatchingExact(), "order.created.v1", "order.created/.v1"),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			_, err := tc.givenSub.ValidateSubscription()
+
+			// then
+			if tc.wantErrText == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.TypesPath, subName, tc.wantErrText)}), err)
+		})
+	}
+}
+
+func Test_validateSubscriptionBackend(t *testing.T) {
+	t.Parallel()
+	newSub := func(backend string) *v1alpha2.Subscription {
+		sub := eventingtesting.NewSubscription(subName, subNamespace,
+			eventingtesting.WithTypeMatchingStandard(),
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+			eventingtesting.WithSink(sink),
+		)
+		sub.Annotations = map[string]string{v1alpha2.BackendAnnotation: backend}
+		return sub
+	}
+
+	// when
+	_, errNATS := newSub(v1alpha2.BackendNATS).ValidateSubscription()
+	_, errEventMesh := newSub(v1alpha2.BackendEventMesh).ValidateSubscription()
+	_, errInvalid := newSub("kafka").ValidateSubscription()
+
+	// then
+	require.NoError(t, errNATS)
+	require.NoError(t, errEventMesh)
+	require.Equal(t, apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+		field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.BackendPath,
+			subName, v1alpha2.InvalidBackendErrDetail)}), errInvalid)
+}
+
+// Test_validateSubscriptionFiltersBackend is not parallel because it changes the package-wide backends.
+func Test_validateSubscriptionFiltersBackend(t *testing.T) {
+	// given
+	newSub := func(backend string) *v1alpha2.Subscription {
+		sub := eventingtesting.NewSubscription(subName, subNamespace,
+			eventingtesting.WithTypeMatchingStandard(),
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+			eventingtesting.WithSink(sink),
+			eventingtesting.WithFilters(v1alpha2.SubscriptionFilter{Exact: map[string]string{"region": "eu"}}),
+		)
+		if backend != "" {
+			sub.Annotations = map[string]string{v1alpha2.BackendAnnotation: backend}
+		}
+		return sub
+	}
+	wantErr := apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+		field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.FilterPath,
+			subName, v1alpha2.UnsupportedFilterErrDetail)})
+	defer v1alpha2.InitializeBackends("", "")
+
+	// when EventMesh runs next to NATS
+	v1alpha2.InitializeBackends(v1alpha2.BackendNATS, v1alpha2.BackendEventMesh)
+	_, errNATS := newSub("").ValidateSubscription()
+	_, errEventMesh := newSub(v1alpha2.BackendEventMesh).ValidateSubscription()
+
+	// then only the Subscriptions of EventMesh must not have filters
+	require.NoError(t, errNATS)
+	require.Equal(t, wantErr, errEventMesh)
+
+	// when EventMesh is the primary backend
+	v1alpha2.InitializeBackends(v1alpha2.BackendEventMesh, "")
+	_, err := newSub("").ValidateSubscription()
+
+	// then
+	require.Equal(t, wantErr, err)
+}
+
+// Test_validateSubscriptionFilterExpressionBackend is not parallel because it changes the package-wide backends.
+func Test_validateSubscriptionFilterExpressionBackend(t *testing.T) {
+	// given
+	newSub := func(backend string) *v1alpha2.Subscription {
+		sub := eventingtesting.NewSubscription(subName, subNamespace,
+			eventingtesting.WithTypeMatchingStandard(),
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+			eventingtesting.WithSink(sink),
+			eventingtesting.WithFilterExpression("region = 'eu'"),
+		)
+		if backend != "" {
+			sub.Annotations = map[string]string{v1alpha2.BackendAnnotation: backend}
+		}
+		return sub
+	}
+	wantErr := apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+		field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.FilterExpressionPath,
+			subName, v1alpha2.UnsupportedFilterErrDetail)})
+	defer v1alpha2.InitializeBackends("", "")
+
+	// when EventMesh runs next to NATS
+	v1alpha2.InitializeBackends(v1alpha2.BackendNATS, v1alpha2.BackendEventMesh)
+	_, errNATS := newSub("").ValidateSubscription()
+	_, errEventMesh := newSub(v1alpha2.BackendEventMesh).ValidateSubscription()
+
+	// then only the Subscriptions of EventMesh must not have a filter expression
+	require.NoError(t, errNATS)
+	require.Equal(t, wantErr, errEventMesh)
+
+	// when EventMesh is the primary backend
+	v1alpha2.InitializeBackends(v1alpha2.BackendEventMesh, "")
+	_, err := newSub("").ValidateSubscription()
+
+	// then
+	require.Equal(t, wantErr, err)
+}
+
+func Test_validateSubscriptionExpiry(t *testing.T) {
+	t.Parallel()
+	newSub := func(opts ...eventingtesting.SubscriptionOpt) *v1alpha2.Subscription {
+		opts = append([]eventingtesting.SubscriptionOpt{
+			eventingtesting.WithTypeMatchingStandard(),
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			eventingtesting.WithMaxInFlightMessages(v1alpha2.DefaultMaxInFlightMessages),
+			eventingtesting.WithSink(sink),
+		}, opts...)
+		return eventingtesting.NewSubscription(subName, subNamespace, opts...)
+	}
+
+	// when
+	_, errTTL := newSub(eventingtesting.WithTTL(time.Hour)).ValidateSubscription()
+	_, errExpiryDate := newSub(eventingtesting.WithExpiryDate(time.Now())).ValidateSubscription()
+	_, errBoth := newSub(eventingtesting.WithTTL(time.Hour),
+		eventingtesting.WithExpiryDate(time.Now())).ValidateSubscription()
+	_, errNegativeTTL := newSub(eventingtesting.WithTTL(-time.Hour)).ValidateSubscription()
+
+	// then
+	require.NoError(t, errTTL)
+	require.NoError(t, errExpiryDate)
+	require.Equal(t, apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+		field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.TTLPath,
+			subName, v1alpha2.TTLAndExpiryDateErrDetail)}), errBoth)
+	require.Equal(t, apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+		field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.TTLPath,
+			subName, v1alpha2.PositiveTTLErrDetail)}), errNegativeTTL)
+}
+
+func Test_validateDeletionProtection(t *testing.T) {
+	// given
+	newSub := func(forceDelete bool) *v1alpha2.Subscription {
+		sub := eventingtesting.NewSubscription(subName, subNamespace,
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithEventType(eventingtesting.OrderCreatedV1Event),
+			eventingtesting.WithSink(sink),
+		)
+		if forceDelete {
+			sub.Annotations = map[string]string{v1alpha2.ForceDeleteAnnotation: "true"}
+		}
+		return sub
+	}
+	defer v1alpha2.InitializeDeletionProtection(0, nil)
+
+	testCases := []struct {
+		name             string
+		givenThreshold   int
+		givenPending     int
+		givenCountErr    error
+		givenForceDelete bool
+		wantWarnings     bool
+		wantErr          error
+	}{
+		{
+			name:           "deletion should be accepted if the protection is disabled",
+			givenThreshold: 0,
+			givenPending:   100,
+			wantErr:        nil,
+		},
+		{
+			name:           "deletion should be accepted with pending messages within the threshold",
+			givenThreshold: 10,
+			givenPending:   10,
+			wantErr:        nil,
+		},
+		{
+			name:           "deletion should be rejected with pending messages above the threshold",
+			givenThreshold: 10,
+			givenPending:   11,
+			wantErr: apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.ForceDeletePath, subName,
+					fmt.Sprintf(v1alpha2.PendingMessagesErrDetail, 11, 10))}),
+		},
+		{
+			name:             "deletion should be accepted with pending messages above the threshold if forced",
+			givenThreshold:   10,
+			givenPending:     11,
+			givenForceDelete: true,
+			wantErr:          nil,
+		},
+		{
+			name:           "deletion should be accepted with a warning if the pending messages cannot be counted",
+			givenThreshold: 10,
+			givenCountErr:  errors.New("not connected"),
+			wantWarnings:   true,
+			wantErr:        nil,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			v1alpha2.InitializeDeletionProtection(tc.givenThreshold, func(sub *v1alpha2.Subscription) (int, error) {
+				require.Equal(t, subName, sub.Name)
+				return tc.givenPending, tc.givenCountErr
+			})
+
+			// when
+			warnings, err := newSub(tc.givenForceDelete).ValidateDelete()
+
+			// then
+			require.Equal(t, tc.wantWarnings, len(warnings) > 0)
+			if tc.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_validateSubscriptionNamespaceQuota(t *testing.T) {
+	// given
+	newSub := func(name string, types []string, maxInFlight string) *v1alpha2.Subscription {
+		return eventingtesting.NewSubscription(name, subNamespace,
+			eventingtesting.WithTypeMatchingStandard(),
+			eventingtesting.WithSource(eventingtesting.EventSourceClean),
+			eventingtesting.WithTypes(types),
+			eventingtesting.WithMaxInFlightMessages(maxInFlight),
+			eventingtesting.WithSink(sink),
+		)
+	}
+	existingSubs := []v1alpha2.Subscription{
+		*newSub("existing-1", []string{"order.created.v1", "order.updated.v1"}, "20"),
+		*newSub(subName, []string{"order.deleted.v1"}, "10"),
+	}
+	defer v1alpha2.InitializeQuota(env.SubscriptionQuota{}, nil)
+	v1alpha2.InitializeQuota(env.SubscriptionQuota{MaxSubscriptions: 2, MaxEventTypes: 4, MaxInFlightMessages: 50},
+		func(namespace string) ([]v1alpha2.Subscription, error) {
+			require.Equal(t, subNamespace, namespace)
+			return existingSubs, nil
+		})
+
+	testCases := []struct {
+		name     string
+		givenSub *v1alpha2.Subscription
+		wantErr  error
+	}{
+		{
+			name:     "update of an existing subscription within the quota should be accepted",
+			givenSub: newSub(subName, []string{"order.paid.v1"}, "30"),
+			wantErr:  nil,
+		},
+		{
+			name:     "new subscription exceeding the subscription quota should be rejected",
+			givenSub: newSub("new", []string{"order.paid.v1"}, "10"),
+			wantErr: apierrors.NewInvalid(v1alpha2.GroupKind, "new",
+				field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.NSPath, "new",
+					fmt.Sprintf(v1alpha2.QuotaSubscriptionsErrDetail, 2))}),
+		},
+		{
+			name:     "update exceeding the event type and maxInFlight quota should be rejected",
+			givenSub: newSub(subName, []string{"order.paid.v1", "order.shipped.v1", "order.returned.v1"}, "31"),
+			wantErr: apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+				field.ErrorList{
+					v1alpha2.MakeInvalidFieldError(v1alpha2.TypesPath, subName,
+						fmt.Sprintf(v1alpha2.QuotaEventTypesErrDetail, 4, 2)),
+					v1alpha2.MakeInvalidFieldError(v1alpha2.ConfigPath, subName,
+						fmt.Sprintf(v1alpha2.QuotaMaxInFlightErrDetail, 50, 20)),
+				}),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			_, err := tc.givenSub.ValidateSubscription()
+
+			// then
+			require.Equal(t, tc.wantErr, err)
+		})
+	}
+
+	// when the namespace exceeds a lowered quota
+	v1alpha2.InitializeQuota(env.SubscriptionQuota{MaxSubscriptions: 1, MaxEventTypes: 2, MaxInFlightMessages: 20},
+		func(string) ([]v1alpha2.Subscription, error) { return existingSubs, nil })
+	oldSub := &existingSubs[1]
+	withFinalizer := newSub(subName, []string{"order.deleted.v1"}, "10")
+	withFinalizer.Finalizers = []string{v1alpha2.Finalizer}
+	_, errUnchanged := withFinalizer.ValidateUpdate(oldSub)
+	_, errShrinking := newSub(subName, []string{"order.deleted.v1"}, "5").ValidateUpdate(oldSub)
+	_, errGrowing := newSub(subName, []string{"order.deleted.v1"}, "11").ValidateUpdate(oldSub)
+
+	// then only the update growing the usage of the Subscription is rejected
+	require.NoError(t, errUnchanged)
+	require.NoError(t, errShrinking)
+	require.Equal(t, apierrors.NewInvalid(v1alpha2.GroupKind, subName,
+		field.ErrorList{v1alpha2.MakeInvalidFieldError(v1alpha2.ConfigPath, subName,
+			fmt.Sprintf(v1alpha2.QuotaMaxInFlightErrDetail, 20, 20))}), errGrowing)
+}
+
+func Test_InitializeSinkValidationWithInvalidPolicy(t *testing.T) {
+	t.Parallel()
+	require.Error(t, v1alpha2.InitializeSinkValidation(nil, "sometimes"))
+}
+
 func Test_IsInvalidCESource(t *testing.T) {
 	t.Parallel()
 	type TestCase struct {
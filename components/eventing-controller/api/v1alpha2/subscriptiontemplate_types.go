@@ -0,0 +1,81 @@
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SubscriptionTemplateLabel is set on the Subscriptions created from a SubscriptionTemplate.
+// Its value is the name of the SubscriptionTemplate.
+const SubscriptionTemplateLabel = "eventing.kyma-project.io/subscription-template"
+
+// Defines the desired state of the SubscriptionTemplate.
+type SubscriptionTemplateSpec struct {
+	// Selects the Namespaces in which a Subscription is created from the template.
+	// An empty selector selects all Namespaces.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// Template of the Subscriptions created in the selected Namespaces.
+	Template SubscriptionTemplateObject `json:"template"`
+}
+
+// SubscriptionTemplateObject describes the Subscriptions created from a SubscriptionTemplate.
+type SubscriptionTemplateObject struct {
+	// Labels and annotations added to the created Subscriptions.
+	// +optional
+	Metadata SubscriptionTemplateMetadata `json:"metadata,omitempty"`
+
+	// Spec of the created Subscriptions. The sink, source, and types are Go templates which can use
+	// the `{{ .Namespace }}` field to refer to the Namespace of the created Subscription.
+	Spec SubscriptionSpec `json:"spec"`
+}
+
+// SubscriptionTemplateMetadata defines the metadata added to the created Subscriptions.
+type SubscriptionTemplateMetadata struct {
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// SubscriptionTemplateStatus defines the observed state of the SubscriptionTemplate.
+type SubscriptionTemplateStatus struct {
+	// Current state of the SubscriptionTemplate.
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// Overall readiness of the SubscriptionTemplate.
+	Ready bool `json:"ready"`
+
+	// Namespaces in which a Subscription is managed by the SubscriptionTemplate.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SubscriptionTemplate creates Subscriptions in all the Namespaces matching a label selector.
+type SubscriptionTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SubscriptionTemplateSpec   `json:"spec,omitempty"`
+	Status SubscriptionTemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SubscriptionTemplateList contains a list of SubscriptionTemplate.
+type SubscriptionTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SubscriptionTemplate `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&SubscriptionTemplate{}, &SubscriptionTemplateList{})
+}
@@ -6,6 +6,7 @@
 package v1alpha2
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -20,13 +21,20 @@ func (in *Backend) DeepCopyInto(out *Backend) {
 	if in.Types != nil {
 		in, out := &in.Types, &out.Types
 		*out = make([]JetStreamTypes, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.EmsTypes != nil {
 		in, out := &in.EmsTypes, &out.EmsTypes
 		*out = make([]EventMeshTypes, len(*in))
 		copy(*out, *in)
 	}
+	if in.KafkaTypes != nil {
+		in, out := &in.KafkaTypes, &out.KafkaTypes
+		*out = make([]KafkaTypes, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backend.
@@ -39,6 +47,21 @@ func (in *Backend) DeepCopy() *Backend {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CABundleReference) DeepCopyInto(out *CABundleReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CABundleReference.
+func (in *CABundleReference) DeepCopy() *CABundleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CABundleReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -103,6 +126,11 @@ func (in *EventType) DeepCopy() *EventType {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JetStreamTypes) DeepCopyInto(out *JetStreamTypes) {
 	*out = *in
+	if in.MatchedSubjects != nil {
+		in, out := &in.MatchedSubjects, &out.MatchedSubjects
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JetStreamTypes.
@@ -115,6 +143,56 @@ func (in *JetStreamTypes) DeepCopy() *JetStreamTypes {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaTypes) DeepCopyInto(out *KafkaTypes) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaTypes.
+func (in *KafkaTypes) DeepCopy() *KafkaTypes {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaTypes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SinkReference) DeepCopyInto(out *SinkReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SinkReference.
+func (in *SinkReference) DeepCopy() *SinkReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SinkReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SinkTLSConfig) DeepCopyInto(out *SinkTLSConfig) {
+	*out = *in
+	if in.CABundleRef != nil {
+		in, out := &in.CABundleRef, &out.CABundleRef
+		*out = new(CABundleReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SinkTLSConfig.
+func (in *SinkTLSConfig) DeepCopy() *SinkTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SinkTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subscription) DeepCopyInto(out *Subscription) {
 	*out = *in
@@ -142,6 +220,42 @@ func (in *Subscription) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionFilter) DeepCopyInto(out *SubscriptionFilter) {
+	*out = *in
+	if in.Exact != nil {
+		in, out := &in.Exact, &out.Exact
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Suffix != nil {
+		in, out := &in.Suffix, &out.Suffix
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionFilter.
+func (in *SubscriptionFilter) DeepCopy() *SubscriptionFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubscriptionList) DeepCopyInto(out *SubscriptionList) {
 	*out = *in
@@ -177,6 +291,11 @@ func (in *SubscriptionList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 	*out = *in
+	if in.SinkRef != nil {
+		in, out := &in.SinkRef, &out.SinkRef
+		*out = new(SinkReference)
+		**out = **in
+	}
 	if in.Types != nil {
 		in, out := &in.Types, &out.Types
 		*out = make([]string, len(*in))
@@ -189,6 +308,27 @@ func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]SubscriptionFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SinkTLS != nil {
+		in, out := &in.SinkTLS, &out.SinkTLS
+		*out = new(SinkTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpiryDate != nil {
+		in, out := &in.ExpiryDate, &out.ExpiryDate
+		*out = (*in).DeepCopy()
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSpec.
@@ -228,3 +368,152 @@ func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTemplate) DeepCopyInto(out *SubscriptionTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTemplate.
+func (in *SubscriptionTemplate) DeepCopy() *SubscriptionTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTemplateList) DeepCopyInto(out *SubscriptionTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SubscriptionTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTemplateList.
+func (in *SubscriptionTemplateList) DeepCopy() *SubscriptionTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTemplateMetadata) DeepCopyInto(out *SubscriptionTemplateMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTemplateMetadata.
+func (in *SubscriptionTemplateMetadata) DeepCopy() *SubscriptionTemplateMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTemplateMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTemplateObject) DeepCopyInto(out *SubscriptionTemplateObject) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTemplateObject.
+func (in *SubscriptionTemplateObject) DeepCopy() *SubscriptionTemplateObject {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTemplateObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTemplateSpec) DeepCopyInto(out *SubscriptionTemplateSpec) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTemplateSpec.
+func (in *SubscriptionTemplateSpec) DeepCopy() *SubscriptionTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTemplateStatus) DeepCopyInto(out *SubscriptionTemplateStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTemplateStatus.
+func (in *SubscriptionTemplateStatus) DeepCopy() *SubscriptionTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
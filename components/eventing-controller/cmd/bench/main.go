@@ -0,0 +1,87 @@
+// The bench command measures the SyncSubscription throughput and the dispatch throughput and latency of the
+// JetStream backend, and writes a JSON report to track its performance regressions.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/testing/bench"
+)
+
+func main() {
+	var (
+		natsURL = flag.String("nats-url", "",
+			"The URL of the NATS server with JetStream, an embedded server is started if it is empty")
+		output   = flag.String("output", "", "The file of the JSON report, it is written to stdout if it is empty")
+		logLevel = flag.String("log-level", string(kymalogger.WARN), "The log level of the JetStream backend")
+
+		syncSubscriptions = flag.Int("sync-subscriptions", 1000,
+			"The number of synced Subscriptions, the sync benchmark is skipped if it is 0")
+		typesPerSubscription = flag.Int("types-per-subscription", 1, "The number of event types of each synced Subscription")
+
+		dispatchSubscriptions = flag.Int("dispatch-subscriptions", 10,
+			"The number of Subscriptions the events are dispatched to, the dispatch benchmark is skipped if it is 0")
+		sinkLatency  = flag.Duration("sink-latency", 0, "How long the sink takes to respond to each event")
+		rate         = flag.Int("rate", 1000, "The number of events published per second")
+		duration     = flag.Duration("duration", 30*time.Second, "How long the events are published")
+		eventSize    = flag.Int("event-size", 64, "The size of the data of each event in bytes")
+		maxInFlight  = flag.Int("max-in-flight", 10, "The maxInFlightMessages of each Subscription")
+		drainTimeout = flag.Duration("drain-timeout", time.Minute,
+			"How long to wait for the delivery of the published events")
+	)
+	flag.Parse()
+
+	benchLogger, err := logger.New(string(kymalogger.JSON), *logLevel)
+	if err != nil {
+		log.Fatalf("Failed to create the logger, error: %v", err)
+	}
+	environment, err := bench.NewEnvironment(*natsURL, benchLogger)
+	if err != nil {
+		log.Fatalf("Failed to set up the JetStream backend, error: %v", err)
+	}
+	defer environment.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	report := bench.NewReport()
+	if *syncSubscriptions > 0 {
+		syncReport, syncErr := environment.RunSync(bench.SyncConfig{
+			Subscriptions:        *syncSubscriptions,
+			TypesPerSubscription: *typesPerSubscription,
+		})
+		if syncErr != nil {
+			log.Fatalf("Failed to run the sync benchmark, error: %v", syncErr)
+		}
+		report.Sync = &syncReport
+	}
+	if *dispatchSubscriptions > 0 {
+		dispatchReport, dispatchErr := environment.RunDispatch(ctx, bench.DispatchConfig{
+			Subscriptions: *dispatchSubscriptions,
+			SinkLatency:   bench.Duration(*sinkLatency),
+			Rate:          *rate,
+			Duration:      bench.Duration(*duration),
+			EventSize:     *eventSize,
+			MaxInFlight:   *maxInFlight,
+			DrainTimeout:  bench.Duration(*drainTimeout),
+		})
+		// the report of undelivered events is still written, since it shows the regression
+		if dispatchErr != nil {
+			log.Printf("The dispatch benchmark failed, error: %v", dispatchErr)
+		}
+		report.Dispatch = &dispatchReport
+	}
+
+	fmt.Fprintln(os.Stderr, report)
+	if err := report.WriteFile(*output); err != nil {
+		log.Fatalf("Failed to write the report, error: %v", err)
+	}
+}
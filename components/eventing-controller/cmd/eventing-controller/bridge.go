@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/bridge"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+// runBridge connects to the local NATS server and to the target of the bridge, and forwards the events
+// until the context is cancelled.
+func runBridge(ctx context.Context, bridgeConfig env.BridgeConfig, natsConfig env.NATSConfig,
+	ctrLogger *logger.Logger) error {
+	// NATS is not available while another backend is active, so the bridge waits for it
+	// instead of stopping the eventing-controller
+	var conn jetstream.ConnectionInterface
+	for conn == nil {
+		var err error
+		if conn, err = jetstream.NewConnectionBuilder(natsConfig).Build(); err != nil {
+			ctrLogger.WithContext().Named("bridge").Errorw("Failed to connect the bridge to NATS, retrying", "error", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(bridgeConfig.RetryDelay):
+			}
+		}
+	}
+	defer conn.Close()
+	jsCtx, err := conn.JetStream()
+	if err != nil {
+		return fmt.Errorf("failed to create the JetStream context of the bridge: %w", err)
+	}
+
+	var forwarder bridge.Forwarder
+	if bridgeConfig.TargetPublishURL != "" {
+		forwarder = bridge.NewHTTPForwarder(bridgeConfig.TargetPublishURL,
+			&http.Client{Timeout: bridgeConfig.RequestTimeout})
+	} else {
+		opts := []nats.Option{
+			nats.RetryOnFailedConnect(true),
+			nats.MaxReconnects(natsConfig.MaxReconnects),
+			nats.ReconnectWait(natsConfig.ReconnectWait),
+			nats.Name("Kyma Bridge"),
+		}
+		if bridgeConfig.TargetNATSCredentialsFile != "" {
+			opts = append(opts, nats.UserCredentials(bridgeConfig.TargetNATSCredentialsFile))
+		}
+		targetConn, err := nats.Connect(bridgeConfig.TargetNATSURL, opts...)
+		if err != nil {
+			return fmt.Errorf("failed to connect the bridge to the target NATS server: %w", err)
+		}
+		defer targetConn.Close()
+		targetJSCtx, err := targetConn.JetStream()
+		if err != nil {
+			return fmt.Errorf("failed to create the JetStream context of the bridge target: %w", err)
+		}
+		forwarder = bridge.NewJetStreamForwarder(targetJSCtx)
+	}
+
+	return bridge.New(bridgeConfig, natsConfig, jsCtx, forwarder, ctrLogger).Run(ctx)
+}
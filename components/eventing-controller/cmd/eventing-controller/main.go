@@ -2,28 +2,53 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"io"
 	"log"
+	"net/http"
+	"os"
+	"time"
 
 	"github.com/go-logr/zapr"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
 	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	applicationcontroller "github.com/kyma-project/kyma/components/eventing-controller/controllers/application"
 	"github.com/kyma-project/kyma/components/eventing-controller/controllers/backend"
+	"github.com/kyma-project/kyma/components/eventing-controller/controllers/eventtype"
+	featureflagscontroller "github.com/kyma-project/kyma/components/eventing-controller/controllers/featureflags"
+	"github.com/kyma-project/kyma/components/eventing-controller/controllers/subscriptiontemplate"
 	"github.com/kyma-project/kyma/components/eventing-controller/internal/featureflags"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
 	"github.com/kyma-project/kyma/components/eventing-controller/options"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/audit"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
 	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/deadletter"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
-	"github.com/kyma-project/kyma/components/eventing-controller/pkg/subscriptionmanager"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/profiling"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/subscriptionmanager/eventmesh"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/subscriptionmanager/jetstream"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/subscriptionmanager/kafka"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/webhookcert"
 )
 
 const webhookServerPort = 9443
@@ -38,6 +63,9 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize logger, error: %v", err)
 	}
+	if err = ctrLogger.SetComponentLevels(opts.LogLevels); err != nil {
+		log.Fatalf("Failed to initialize logger, error: APP_LOG_LEVELS: %v", err)
+	}
 	defer func() {
 		if err = ctrLogger.WithContext().Sync(); err != nil {
 			log.Printf("Failed to flush logger, error: %v", err)
@@ -50,20 +78,64 @@ func main() {
 	// prepare the setup logger
 	setupLogger := ctrLogger.WithContext().Named("setup")
 
+	// Load and validate the configuration before connecting to any cluster or backend.
+	natsConfig, err := env.GetNATSConfig(opts.MaxReconnects, opts.ReconnectWait)
+	if err != nil {
+		setupLogger.Fatalw("Failed to load configuration", "error", err)
+	}
+	envConfig := env.GetConfig()
+	backendConfig := env.GetBackendConfig()
+	kafkaConfig, err := env.GetKafkaConfig()
+	if err != nil {
+		setupLogger.Fatalw("Failed to load configuration", "backend", v1alpha1.KafkaBackendType, "error", err)
+	}
+	bridgeConfig, err := env.GetBridgeConfig()
+	if err != nil {
+		setupLogger.Fatalw("Failed to load the bridge configuration", "error", err)
+	}
+	webhookCertConfig, err := env.GetWebhookCertConfig()
+	if err != nil {
+		setupLogger.Fatalw("Failed to load the webhook certificate configuration", "error", err)
+	}
+	if opts.PrintEffectiveConfig {
+		if err = printEffectiveConfig(os.Stdout, envConfig, natsConfig, backendConfig); err != nil {
+			setupLogger.Fatalw("Failed to print the effective configuration", "error", err)
+		}
+		return
+	}
+	if err = errors.Join(natsConfig.Validate(), envConfig.Validate(), backendConfig.Validate(),
+		kafkaConfig.Validate(), bridgeConfig.Validate(), webhookCertConfig.Validate()); err != nil {
+		setupLogger.Fatalw("Invalid configuration", "error", err)
+	}
+
 	// Instantiate and initialize all the subscription managers.
 	restCfg := ctrl.GetConfigOrDie()
 	scheme := runtime.NewScheme()
 
 	metricsCollector := backendmetrics.NewCollector()
+	metricsCollector.SetCardinalityLimit(opts.MetricsLabelLimit, ctrLogger.WithContext().Named("metrics-collector"))
+	metricsCollector.SetSinkHealthPolicy(backendmetrics.SinkHealthPolicy{
+		Window:          opts.SinkHealthWindow,
+		MinDeliveries:   opts.SinkHealthMinDeliveries,
+		MinSuccessRatio: opts.SinkHealthMinSuccessRatio,
+	})
 	metricsCollector.RegisterMetrics()
-
-	var natsSubMgr subscriptionmanager.Manager
-
-	natsConfig, err := env.GetNATSConfig(opts.MaxReconnects, opts.ReconnectWait)
-	if err != nil {
-		setupLogger.Fatalw("Failed to load configuration", "error", err)
+	if opts.OTLPMetricsEndpoint != "" {
+		stopOTLPExport, err := metricsCollector.StartOTLPExport(context.Background(),
+			opts.OTLPMetricsEndpoint, opts.OTLPMetricsInterval)
+		if err != nil {
+			setupLogger.Fatalw("Failed to start OTLP metrics export", "error", err)
+		}
+		defer func() {
+			if err := stopOTLPExport(context.Background()); err != nil {
+				setupLogger.Warnw("Failed to stop OTLP metrics export", "error", err)
+			}
+		}()
 	}
-	natsSubMgr = jetstream.NewSubscriptionManager(restCfg, natsConfig, opts.MetricsAddr, metricsCollector, ctrLogger)
+
+	natsSubMgr := jetstream.NewSubscriptionManager(restCfg, natsConfig, opts.MetricsAddr, metricsCollector, ctrLogger)
+	metrics.Registry.MustRegister(backendmetrics.NewJetStreamCollector(natsSubMgr,
+		ctrLogger.WithContext().Named("jetstream-collector")))
 	if err = jetstream.AddToScheme(scheme); err != nil {
 		setupLogger.Fatalw("Failed to start manager", "backend", v1alpha1.NatsBackendType, "error", err)
 	}
@@ -71,8 +143,7 @@ func main() {
 		setupLogger.Fatalw("Failed to start manager", "backend", v1alpha1.NatsBackendType, "error", err)
 	}
 
-	// Get env config and set feature flags
-	envConfig := env.GetConfig()
+	// Set feature flags
 	featureflags.SetEventingWebhookAuthEnabled(envConfig.EventingWebhookAuthEnabled)
 	featureflags.SetNATSProvisioningEnabled(envConfig.NATSProvisioningEnabled)
 
@@ -88,20 +159,99 @@ func main() {
 		setupLogger.Fatalw("Failed to start subscription manager", "backend", v1alpha1.BEBBackendType, "error", err)
 	}
 
+	kafkaSubMgr := kafka.NewSubscriptionManager(restCfg, kafkaConfig, metricsCollector, ctrLogger)
+
+	// Restrict the subscription managers to the Subscriptions of this replica's shard.
+	shard, err := sharding.New(envConfig.ShardIndex, envConfig.ShardCount)
+	if err != nil {
+		setupLogger.Fatalw("Failed to load sharding configuration", "error", err)
+	}
+	natsSubMgr.SetShard(shard)
+	bebSubMgr.SetShard(shard)
+	kafkaSubMgr.SetShard(shard)
+	natsSubMgr.SetControllerOptions(opts.ControllerOptions())
+	bebSubMgr.SetControllerOptions(opts.ControllerOptions())
+	kafkaSubMgr.SetControllerOptions(opts.ControllerOptions())
+
+	// Record the cleaned event types of both backends to trace them back to their original event types.
+	cleanerMapping := cleaner.NewMapping(ctrLogger)
+	natsSubMgr.SetCleanerMapping(cleanerMapping)
+	bebSubMgr.SetCleanerMapping(cleanerMapping)
+
+	// Record the changes to the Subscriptions admitted by the webhook and the changes to their consumers.
+	auditor, err := audit.New(envConfig.AuditLogOutput)
+	if err != nil {
+		setupLogger.Fatalw("Failed to create the auditor", "error", err)
+	}
+	defer func() { _ = auditor.Sync() }()
+	if auditor != nil {
+		v1alpha2.InitializeAudit(auditor.SubscriptionAdmitted)
+	}
+	natsSubMgr.SetAuditor(auditor)
+
+	featureFlagsConfigMap := types.NamespacedName{
+		Namespace: envConfig.FeatureFlagsConfigMapNamespace,
+		Name:      envConfig.FeatureFlagsConfigMapName,
+	}
+
+	metricsHandlers := map[string]http.Handler{
+		backendmetrics.OpenMetricsEndpoint: backendmetrics.OpenMetricsHandler(),
+		cleaner.MappingEndpoint:            cleaner.MappingHandler(cleanerMapping),
+	}
+	// Serve the profiling endpoints only if they are guarded by a token.
+	if opts.ProfilingTokenFile != "" {
+		for path, handler := range profiling.Handlers(opts.ProfilingTokenFile, opts.ProfilingSnapshotDir, ctrLogger) {
+			metricsHandlers[path] = handler
+		}
+	}
+	// Serve the dead-letter admin endpoints only if they are guarded by a token.
+	if opts.AdminTokenFile != "" {
+		for path, handler := range deadletter.Handlers(natsSubMgr, opts.AdminTokenFile, ctrLogger) {
+			metricsHandlers[path] = handler
+		}
+	}
+
+	// Generate and rotate the webhook serving certificate, unless it is managed externally.
+	var webhookCertManager *webhookcert.Manager
+	var webhookTLSOpts []func(*tls.Config)
+	if webhookCertConfig.Enabled {
+		if err = apiextensionsv1.AddToScheme(scheme); err != nil {
+			setupLogger.Fatalw("Failed to start webhook certificate manager", "error", err)
+		}
+		certClient, err := client.New(restCfg, client.Options{Scheme: scheme})
+		if err != nil {
+			setupLogger.Fatalw("Failed to start webhook certificate manager", "error", err)
+		}
+		webhookCertManager = webhookcert.NewManager(certClient, webhookCertConfig, ctrLogger)
+		webhookTLSOpts = append(webhookTLSOpts, func(config *tls.Config) {
+			config.GetCertificate = webhookCertManager.GetCertificate
+		})
+	}
+
 	// Init the manager.
 	mgr, err := ctrl.NewManager(restCfg, ctrl.Options{
 		Scheme:                 scheme,
 		HealthProbeBindAddress: opts.ProbeAddr,
-		Cache:                  cache.Options{SyncPeriod: &opts.ReconcilePeriod}, // CHECK Only used in BEB so far.
-		Metrics:                server.Options{BindAddress: opts.MetricsAddr},
+		Cache:                  cacheOptions(opts.ReconcilePeriod, featureFlagsConfigMap),
+		Metrics: server.Options{
+			BindAddress:   opts.MetricsAddr,
+			ExtraHandlers: metricsHandlers,
+		},
 		WebhookServer: webhook.NewServer(webhook.Options{
-			Port: webhookServerPort,
+			Port:    webhookServerPort,
+			TLSOpts: webhookTLSOpts,
 		}),
 	})
 	if err != nil {
 		setupLogger.Fatalw("Failed to start manager", "error", err)
 	}
 
+	if webhookCertManager != nil {
+		if err = mgr.Add(webhookCertManager); err != nil {
+			setupLogger.Fatalw("Failed to start webhook certificate manager", "error", err)
+		}
+	}
+
 	if err = natsSubMgr.Init(mgr); err != nil {
 		setupLogger.Fatalw("Failed to initialize subscription manager", "backend", v1alpha1.NatsBackendType, "error", err)
 	}
@@ -110,12 +260,56 @@ func main() {
 		setupLogger.Fatalw("Failed to initialize subscription manager", "backend", v1alpha1.BEBBackendType, "error", err)
 	}
 
+	if err = kafkaSubMgr.Init(mgr); err != nil {
+		setupLogger.Fatalw("Failed to initialize subscription manager", "backend", v1alpha1.KafkaBackendType, "error", err)
+	}
+
 	setupLogger.Infow("Starting the webhook server")
 
 	if err = (&v1alpha1.Subscription{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLogger.Fatalw("Failed to create webhook", "error", err)
 	}
 
+	if err = v1alpha2.InitializeSinkValidation(
+		sink.NewServiceLookup(context.Background(), mgr.GetAPIReader()), envConfig.ExternalSinkPolicy); err != nil {
+		setupLogger.Fatalw("Failed to initialize sink validation", "error", err)
+	}
+
+	v1alpha2.InitializeDefaults(backendConfig.DefaultSubscriptionConfig)
+	listNamespaceSubscriptions := func(namespace string) ([]v1alpha2.Subscription, error) {
+		subs := &v1alpha2.SubscriptionList{}
+		if err := mgr.GetAPIReader().List(context.Background(), subs, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		return subs.Items, nil
+	}
+	v1alpha2.InitializeQuota(backendConfig.SubscriptionQuota, listNamespaceSubscriptions)
+
+	v1alpha2.InitializeDeletionProtection(backendConfig.DeletionProtectionPendingMessages, natsSubMgr.CountPendingMessages)
+
+	// Apply the changes of the configuration files at runtime where it is safe.
+	if configWatcher := opts.ConfigWatcher(); configWatcher != nil {
+		configWatcher.SetReloadable(func(name string) bool { return reloadableEnvs[name] })
+		reloader := &configReloader{
+			logger:                     ctrLogger,
+			listNamespaceSubscriptions: listNamespaceSubscriptions,
+			countPendingMessages:       natsSubMgr.CountPendingMessages,
+		}
+		if err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			return configWatcher.Watch(ctx, reloader.namedLogger(), reloader.apply)
+		})); err != nil {
+			setupLogger.Fatalw("Failed to watch the configuration files", "error", err)
+		}
+	}
+	// Forward the selected event types to another cluster. The consumers of the bridge are shared by all shards,
+	// so only the primary shard runs the bridge.
+	if bridgeConfig.Enabled() && shard.IsPrimary() {
+		if err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			return runBridge(ctx, bridgeConfig, natsConfig, ctrLogger)
+		})); err != nil {
+			setupLogger.Fatalw("Failed to start the bridge", "error", err)
+		}
+	}
 	if err = (&v1alpha2.Subscription{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLogger.Fatalw("Failed to create webhook", "error", err)
 	}
@@ -130,16 +324,89 @@ func main() {
 	// Start the backend manager.
 	ctx := context.Background()
 	recorder := mgr.GetEventRecorderFor("backend-controller")
-	backendConfig := env.GetBackendConfig()
 	backendReconciler := backend.NewReconciler(ctx, natsSubMgr, natsConfig, envConfig, backendConfig, bebSubMgr,
 		mgr.GetClient(), ctrLogger, recorder)
+	backendReconciler.SetKafkaSubscriptionManager(kafkaSubMgr, kafkaConfig.Brokers)
+	backendReconciler.SetShard(shard)
 	if err = backendReconciler.SetupWithManager(mgr); err != nil {
 		setupLogger.Fatalw("Failed to start backend controller", "error", err)
 	}
 
+	// The SubscriptionTemplates are cluster-wide, so only the primary shard reconciles them.
+	if shard.IsPrimary() {
+		templateReconciler := subscriptiontemplate.NewReconciler(mgr.GetClient(), ctrLogger,
+			mgr.GetEventRecorderFor("subscription-template-controller"))
+		if err = templateReconciler.SetupWithManager(mgr); err != nil {
+			setupLogger.Fatalw("Failed to start subscription template controller", "error", err)
+		}
+	}
+
+	// The event type catalogs are reconciled per Namespace independent of the shards, so only the primary
+	// shard reconciles them.
+	if shard.IsPrimary() {
+		eventTypeReconciler := eventtype.NewReconciler(mgr.GetClient(), ctrLogger,
+			mgr.GetEventRecorderFor("event-type-controller"))
+		if err = eventTypeReconciler.SetupWithManager(mgr); err != nil {
+			setupLogger.Fatalw("Failed to start event type controller", "error", err)
+		}
+	}
+
+	// The event types of the Application CRs are registered for the whole cluster, so only the primary shard
+	// reconciles them.
+	if shard.IsPrimary() && len(envConfig.ApplicationEventTypesNamespaces) > 0 {
+		applicationReconciler := applicationcontroller.NewReconciler(mgr.GetClient(), mgr.GetAPIReader(),
+			envConfig.ApplicationEventTypesNamespaces,
+			types.NamespacedName{
+				Namespace: envConfig.ApplicationAllowlistConfigMapNamespace,
+				Name:      envConfig.ApplicationAllowlistConfigMapName,
+			},
+			ctrLogger)
+		if err = applicationReconciler.SetupWithManager(mgr); err != nil {
+			setupLogger.Fatalw("Failed to start application controller", "error", err)
+		}
+	}
+
+	// The experimental capabilities are enabled per cluster by the feature flags ConfigMap.
+	if featureFlagsConfigMap.Name != "" {
+		featureFlagsReconciler := featureflagscontroller.NewReconciler(mgr.GetClient(), featureFlagsConfigMap,
+			ctrLogger, metricsCollector)
+		if err = featureFlagsReconciler.SetupWithManager(mgr); err != nil {
+			setupLogger.Fatalw("Failed to start feature flags controller", "error", err)
+		}
+	}
+
 	// Start the controller manager.
 	ctrLogger.WithContext().With("options", opts).Info("start controller manager")
 	if err = mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLogger.Fatalw("Failed to start controller manager", "error", err)
 	}
 }
+
+// cacheOptions returns the options of the manager cache. The ConfigMaps are restricted to the given feature flags
+// ConfigMap, because no other ConfigMap is read from the cache, and the EndpointSlices to those of a Service.
+func cacheOptions(syncPeriod time.Duration, featureFlagsConfigMap types.NamespacedName) cache.Options {
+	options := cache.Options{
+		SyncPeriod: &syncPeriod,
+		ByObject: map[client.Object]cache.ByObject{
+			sink.NewEndpointSliceMetadata(): {Label: sink.EndpointSliceSelector()},
+		},
+	}
+	if featureFlagsConfigMap.Name != "" {
+		options.ByObject[&corev1.ConfigMap{}] = cache.ByObject{
+			Namespaces: map[string]cache.Config{featureFlagsConfigMap.Namespace: {}},
+			Field:      fields.OneTermEqualSelector("metadata.name", featureFlagsConfigMap.Name),
+		}
+	}
+	return options
+}
+
+// printEffectiveConfig writes the effective configuration in the format of the configuration file.
+func printEffectiveConfig(w io.Writer, envConfig env.Config, natsConfig env.NATSConfig,
+	backendConfig env.BackendConfig) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(env.EffectiveConfigFile(envConfig, natsConfig, backendConfig)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/options"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+const configReloaderName = "config-reloader"
+
+// reloadableEnvs are the environment variables which are applied at runtime when the configuration files change.
+// The changes of all other environment variables require a restart of the eventing-controller.
+//
+//nolint:gochecknoglobals // constant lookup table
+var reloadableEnvs = map[string]bool{
+	"APP_LOG_LEVEL":                        true,
+	"APP_LOG_LEVELS":                       true,
+	"DEFAULT_MAX_IN_FLIGHT_MESSAGES":       true,
+	"MAX_IN_FLIGHT_MESSAGES_LIMIT":         true,
+	"DEFAULT_SUBSCRIPTION_SOURCE":          true,
+	"NAMESPACE_MAX_SUBSCRIPTIONS":          true,
+	"NAMESPACE_MAX_EVENT_TYPES":            true,
+	"NAMESPACE_MAX_IN_FLIGHT_MESSAGES":     true,
+	"DELETION_PROTECTION_PENDING_MESSAGES": true,
+}
+
+// configReloader applies the changed environment variables to the log level and the Subscription webhook.
+type configReloader struct {
+	logger                     *logger.Logger
+	listNamespaceSubscriptions func(namespace string) ([]v1alpha2.Subscription, error)
+	countPendingMessages       func(sub *v1alpha2.Subscription) (int, error)
+}
+
+// apply applies the given changed environment variables which are reloadable,
+// and reports the ones which require a restart. The config watcher does not change the latter in the environment,
+// since the components read them again, for example when the backend is switched.
+func (r *configReloader) apply(changed []string) {
+	var applied, restartRequired []string
+	for _, name := range changed {
+		if reloadableEnvs[name] {
+			applied = append(applied, name)
+		} else {
+			restartRequired = append(restartRequired, name)
+		}
+	}
+	if len(restartRequired) > 0 {
+		r.namedLogger().Warnw("The changed configuration is not applied until the eventing-controller is restarted",
+			"variables", restartRequired)
+	}
+	if len(applied) == 0 {
+		return
+	}
+
+	controllerEnv, err := options.LoadEnv()
+	if err != nil {
+		r.namedLogger().Errorw("Failed to apply the changed configuration", "variables", applied, "error", err)
+		return
+	}
+	backendConfig, err := env.LoadBackendConfig()
+	if err == nil {
+		err = backendConfig.Validate()
+	}
+	if err != nil {
+		r.namedLogger().Errorw("Failed to apply the changed configuration", "variables", applied, "error", err)
+		return
+	}
+	if err := r.logger.SetLevel(controllerEnv.LogLevel); err != nil {
+		r.namedLogger().Errorw("Failed to apply the changed log level", "level", controllerEnv.LogLevel, "error", err)
+	}
+	if err := r.logger.SetComponentLevels(controllerEnv.LogLevels); err != nil {
+		r.namedLogger().Errorw("Failed to apply the changed component log levels", "levels", controllerEnv.LogLevels,
+			"error", err)
+	}
+	v1alpha2.InitializeDefaults(backendConfig.DefaultSubscriptionConfig)
+	v1alpha2.InitializeQuota(backendConfig.SubscriptionQuota, r.listNamespaceSubscriptions)
+	v1alpha2.InitializeDeletionProtection(backendConfig.DeletionProtectionPendingMessages, r.countPendingMessages)
+	r.namedLogger().Infow("Applied the changed configuration", "variables", applied)
+}
+
+func (r *configReloader) namedLogger() *zap.SugaredLogger {
+	return r.logger.WithContext().Named(configReloaderName)
+}
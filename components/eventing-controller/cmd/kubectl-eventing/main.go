@@ -0,0 +1,391 @@
+// The kubectl-eventing command shows the JetStream consumers and EventMesh subscriptions of the Subscriptions
+// with their pending messages and last errors, tests the connectivity to the sinks, finds events in the stream by
+// their CloudEvent id, and exports and imports the Subscriptions to migrate them to another cluster or backend.
+// Installed in the PATH, it runs as the kubectl plugin `kubectl eventing`.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	backendeventmesh "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/eventmesh"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
+	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/diagnostics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/migration"
+)
+
+const usage = `Usage:
+  kubectl eventing subscriptions [flags]        Show the backend resources of the Subscriptions
+  kubectl eventing test-sink [flags] <name>     Test the connectivity to the sink of a Subscription
+  kubectl eventing find-event [flags] <id>      Find an event in the stream and show its delivery state
+  kubectl eventing export [flags]               Export the Subscriptions to a manifest
+  kubectl eventing import [flags]               Import the Subscriptions of a manifest
+
+Run "kubectl eventing <command> -h" to show the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch command, args := os.Args[1], os.Args[2:]; command {
+	case "subscriptions":
+		err = runSubscriptions(args)
+	case "test-sink":
+		err = runTestSink(args)
+	case "find-event":
+		err = runFindEvent(args)
+	case "export":
+		err = runExport(args)
+	case "import":
+		err = runImport(args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func runSubscriptions(args []string) error {
+	flags := flag.NewFlagSet("subscriptions", flag.ExitOnError)
+	namespace := flags.String("n", "default", "The namespace of the Subscriptions")
+	allNamespaces := flags.Bool("A", false, "Show the Subscriptions of all namespaces")
+	natsURL := flags.String("nats-url", "", "The URL of NATS to query the consumer states, "+
+		"for example nats://localhost:4222 after `kubectl port-forward -n kyma-system svc/eventing-nats 4222`")
+	streamName := flags.String("stream", "sap", "The name of the JetStream stream")
+	domain := flags.String("domain", "", "The cluster domain to show the names of the EventMesh subscriptions")
+	output := flags.String("o", "table", "The output format, either table or json")
+	timeout := flags.Duration("timeout", 30*time.Second, "The timeout of the command")
+	_ = flags.Parse(args)
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("invalid output format %q, must be either table or json", *output)
+	}
+	if *allNamespaces {
+		*namespace = ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	var jsCtx nats.JetStreamContext
+	if *natsURL != "" {
+		conn, connErr := nats.Connect(*natsURL)
+		if connErr != nil {
+			return fmt.Errorf("failed to connect to NATS: %w", connErr)
+		}
+		defer conn.Close()
+		if jsCtx, err = conn.JetStream(); err != nil {
+			return fmt.Errorf("failed to create the JetStream context: %w", err)
+		}
+	}
+	var nameMapper backendutils.NameMapper
+	if *domain != "" {
+		nameMapper = backendutils.NewBEBSubscriptionNameMapper(*domain, backendeventmesh.MaxSubscriptionNameLength)
+	}
+
+	inspector := diagnostics.NewInspector(k8sClient, jsCtx, *streamName, nameMapper)
+	reports, err := inspector.Subscriptions(ctx, *namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list the Subscriptions: %w", err)
+	}
+	if *output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	}
+	return printReports(os.Stdout, reports, jsCtx != nil)
+}
+
+func runTestSink(args []string) error {
+	flags := flag.NewFlagSet("test-sink", flag.ExitOnError)
+	namespace := flags.String("n", "default", "The namespace of the Subscription")
+	sinkURL := flags.String("sink-url", "", "The URL to test instead of the sink of the Subscription, "+
+		"for example the local address of a `kubectl port-forward` to the sink")
+	method := flags.String("method", sink.ProbeMethodHTTP, "The probe method, either tcp or http")
+	timeout := flags.Duration("timeout", 5*time.Second, "The timeout of the connectivity test")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("the name of exactly one Subscription is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	url := *sinkURL
+	if url == "" {
+		k8sClient, err := newClient()
+		if err != nil {
+			return err
+		}
+		sub := &eventingv1alpha2.Subscription{}
+		if err = k8sClient.Get(ctx, types.NamespacedName{Namespace: *namespace, Name: flags.Arg(0)}, sub); err != nil {
+			return fmt.Errorf("failed to get the Subscription: %w", err)
+		}
+		if url = diagnostics.SinkURL(sub); url == "" {
+			return fmt.Errorf("the Subscription %s/%s has no sink", *namespace, flags.Arg(0))
+		}
+	}
+
+	prober, err := sink.NewProber(*method, *timeout)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	if err = prober.Probe(ctx, url); err != nil {
+		return err
+	}
+	fmt.Printf("Sink %s is reachable (%s probe, %s)\n", url, *method, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func runFindEvent(args []string) error {
+	flags := flag.NewFlagSet("find-event", flag.ExitOnError)
+	natsURL := flags.String("nats-url", "nats://localhost:4222", "The URL of NATS to search the stream, "+
+		"for example nats://localhost:4222 after `kubectl port-forward -n kyma-system svc/eventing-nats 4222`")
+	streamName := flags.String("stream", "sap", "The name of the JetStream stream")
+	since := flags.Duration("since", time.Hour, "Search the events published within this duration")
+	until := flags.Duration("until", 0, "Search the events published before this duration, 0 for now")
+	output := flags.String("o", "table", "The output format, either table or json")
+	timeout := flags.Duration("timeout", time.Minute, "The timeout of the command")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("the CloudEvent id of exactly one event is required")
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("invalid output format %q, must be either table or json", *output)
+	}
+	if *until >= *since {
+		return fmt.Errorf("the until duration %s must be shorter than the since duration %s", *until, *since)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	conn, err := nats.Connect(*natsURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	defer conn.Close()
+	jsCtx, err := conn.JetStream()
+	if err != nil {
+		return fmt.Errorf("failed to create the JetStream context: %w", err)
+	}
+
+	now := time.Now()
+	inspector := diagnostics.NewInspector(nil, jsCtx, *streamName, nil)
+	events, err := inspector.FindEvents(ctx, flags.Arg(0), now.Add(-*since), now.Add(-*until))
+	if err != nil {
+		return fmt.Errorf("failed to search the stream: %w", err)
+	}
+	if *output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(events)
+	}
+	if len(events) == 0 {
+		fmt.Printf("Event %s not found in the stream %s, it was either not published in the time window "+
+			"or acknowledged by all consumers and removed from the stream\n", flags.Arg(0), *streamName)
+		return nil
+	}
+	return printEvents(os.Stdout, events)
+}
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	namespace := flags.String("n", "default", "The namespace of the Subscriptions")
+	allNamespaces := flags.Bool("A", false, "Export the Subscriptions of all namespaces")
+	output := flags.String("o", "-", "The file to write the manifest to, - for stdout")
+	maxInFlight := flags.Int("default-max-in-flight", 10, "The default maxInFlightMessages of the exported cluster")
+	source := flags.String("default-source", "", "The default source of the exported cluster")
+	timeout := flags.Duration("timeout", 30*time.Second, "The timeout of the command")
+	_ = flags.Parse(args)
+	if *allNamespaces {
+		*namespace = ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	subs, err := migration.Export(ctx, k8sClient, *namespace,
+		env.DefaultSubscriptionConfig{MaxInFlightMessages: *maxInFlight, Source: *source})
+	if err != nil {
+		return fmt.Errorf("failed to list the Subscriptions: %w", err)
+	}
+	if *output == "-" {
+		return migration.WriteManifest(os.Stdout, subs)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err = migration.WriteManifest(file, subs); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d Subscriptions to %s\n", len(subs), *output)
+	return nil
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	input := flags.String("f", "-", "The manifest written by the export command, - for stdin")
+	var opts migration.Options
+	flags.Func("sink-rule", "Replaces the prefix of the sinks, for example "+
+		"http://orders.shop.svc.cluster.local=https://orders.example.com, can be repeated", func(s string) error {
+		rule, err := migration.ParseSinkRule(s)
+		if err != nil {
+			return err
+		}
+		opts.SinkRules = append(opts.SinkRules, rule)
+		return nil
+	})
+	flags.StringVar(&opts.Backend, "backend", "", "The backend of the imported Subscriptions, either nats or eventmesh")
+	dryRun := flags.Bool("dry-run", false, "Print the manifest of the Subscriptions to import without importing them")
+	timeout := flags.Duration("timeout", 30*time.Second, "The timeout of the command")
+	_ = flags.Parse(args)
+	if opts.Backend != "" && opts.Backend != eventingv1alpha2.BackendNATS &&
+		opts.Backend != eventingv1alpha2.BackendEventMesh {
+		return fmt.Errorf("invalid backend %q, must be either nats or eventmesh", opts.Backend)
+	}
+
+	in := io.Reader(os.Stdin)
+	if *input != "-" {
+		file, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		in = file
+	}
+	subs, err := migration.ReadManifest(in)
+	if err != nil {
+		return err
+	}
+	subs = migration.Prepare(subs, opts)
+	if *dryRun {
+		return migration.WriteManifest(os.Stdout, subs)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range migration.Import(ctx, k8sClient, subs) {
+		if r.Error != nil {
+			failed++
+			fmt.Printf("Subscription %s/%s %s: %v\n", r.Namespace, r.Name, r.Action, r.Error)
+			continue
+		}
+		fmt.Printf("Subscription %s/%s %s\n", r.Namespace, r.Name, r.Action)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to import %d of %d Subscriptions", failed, len(subs))
+	}
+	return nil
+}
+
+func newClient() (client.Client, error) {
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig: %w", err)
+	}
+	scheme := runtime.NewScheme()
+	if err = eventingv1alpha2.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return client.New(config, client.Options{Scheme: scheme})
+}
+
+// printReports prints one row per JetStream consumer or EventMesh subscription of each Subscription.
+func printReports(out io.Writer, reports []diagnostics.SubscriptionReport, withConsumerStates bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tREADY\tEVENT TYPE\tBACKEND RESOURCE\tPENDING\tACK PENDING\tLAST ERROR")
+	for _, r := range reports {
+		prefix := fmt.Sprintf("%s\t%s\t%t", r.Namespace, r.Name, r.Ready)
+		switch {
+		case len(r.Consumers) > 0:
+			for _, c := range r.Consumers {
+				pending, ackPending := "-", "-"
+				if withConsumerStates && c.Error == "" {
+					pending, ackPending = strconv.FormatUint(c.Pending, 10), strconv.Itoa(c.AckPending)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", prefix, c.EventType, c.Name, pending, ackPending,
+					oneLine(firstNonEmpty(c.Error, r.LastError)))
+			}
+		case r.EventMesh != nil:
+			fmt.Fprintf(w, "%s\t%s\t%s\t-\t-\t%s\n", prefix, "-", firstNonEmpty(r.EventMesh.Name, "-"),
+				oneLine(r.LastError))
+		default:
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\t%s\n", prefix, oneLine(r.LastError))
+		}
+	}
+	return w.Flush()
+}
+
+// printEvents prints one row per consumer of each found event.
+func printEvents(out io.Writer, events []diagnostics.EventReport) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEQUENCE\tPUBLISHED\tSUBJECT\tCONSUMER\tSUBSCRIPTION\tSTATE\tDELIVERIES")
+	for _, e := range events {
+		prefix := fmt.Sprintf("%d\t%s\t%s", e.Sequence, e.Published.Format(time.RFC3339), e.Subject)
+		if len(e.Consumers) == 0 {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\n", prefix)
+		}
+		for _, c := range e.Consumers {
+			deliveries := strconv.Itoa(c.Deliveries)
+			if c.Redelivered > 0 {
+				// the redeliveries are only known for the whole consumer
+				deliveries += fmt.Sprintf(" (%d redelivered by the consumer)", c.Redelivered)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", prefix, c.Name, firstNonEmpty(c.Subscription, "-"), c.State,
+				deliveries)
+		}
+	}
+	return w.Flush()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// oneLine keeps the table rows on a single line.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
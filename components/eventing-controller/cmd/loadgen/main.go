@@ -0,0 +1,90 @@
+// The loadgen command publishes events at a configurable rate to the event-publisher-proxy or directly
+// to the JetStream backend, and prints the achieved throughput and latency.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
+	"github.com/kyma-project/kyma/components/eventing-controller/testing/loadgen"
+)
+
+func main() {
+	var (
+		publishURL    = flag.String("publish-url", "", "The publish URL of the event-publisher-proxy")
+		natsURL       = flag.String("nats-url", "", "The URL of NATS to publish to the JetStream backend directly")
+		subjectPrefix = flag.String("subject-prefix", "kyma", "The prefix of the JetStream subjects")
+		binary        = flag.Bool("binary", true, "Publish to the event-publisher-proxy in the binary content mode")
+		rate          = flag.Int("rate", 100, "The number of events published per second")
+		duration      = flag.Duration("duration", time.Minute, "How long the events are published")
+		eventSize     = flag.Int("event-size", 64, "The size of the data of each event in bytes")
+		eventTypes    = flag.String("event-types", "", "The comma-separated types of the events")
+		source        = flag.String("source", "loadgen", "The source of the events")
+		concurrency   = flag.Int("concurrency", 10, "The maximum number of events published at the same time")
+		timeout       = flag.Duration("timeout", 10*time.Second, "The timeout of publishing a single event")
+	)
+	flag.Parse()
+
+	publisher, closePublisher, err := newPublisher(*publishURL, *natsURL, *subjectPrefix, *binary, *timeout)
+	if err != nil {
+		log.Fatalf("Failed to create the publisher, error: %v", err)
+	}
+	defer closePublisher()
+
+	var typeList []string
+	if *eventTypes != "" {
+		typeList = strings.Split(*eventTypes, ",")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	report, err := loadgen.Run(ctx, publisher, loadgen.Config{
+		Rate:        *rate,
+		Duration:    *duration,
+		EventSize:   *eventSize,
+		EventTypes:  typeList,
+		Source:      *source,
+		Concurrency: *concurrency,
+	})
+	if err != nil {
+		log.Fatalf("Failed to generate the load, error: %v", err)
+	}
+	fmt.Println(report)
+}
+
+func newPublisher(publishURL, natsURL, subjectPrefix string, binary bool,
+	timeout time.Duration) (loadgen.Publisher, func(), error) {
+	switch {
+	case publishURL != "" && natsURL != "":
+		return nil, nil, fmt.Errorf("either -publish-url or -nats-url must be set, not both")
+	case publishURL != "":
+		contentMode := types.ContentModeStructured
+		if binary {
+			contentMode = types.ContentModeBinary
+		}
+		return loadgen.NewHTTPPublisher(publishURL, &http.Client{Timeout: timeout}, contentMode), func() {}, nil
+	case natsURL != "":
+		conn, err := nats.Connect(natsURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		jsCtx, err := conn.JetStream(nats.MaxWait(timeout))
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		return loadgen.NewJetStreamPublisher(jsCtx, subjectPrefix), conn.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("either -publish-url or -nats-url must be set")
+	}
+}
@@ -0,0 +1,81 @@
+// The stream-migration command migrates the events and consumers of the JetStream stream to a stream with
+// a different name, subject prefix or storage. It is run by the chart before upgrades changing the stream.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/nats-io/nats.go"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream/migration"
+)
+
+func main() {
+	var (
+		natsURL           = flag.String("nats-url", nats.DefaultURL, "The URL of the NATS server with JetStream")
+		fromStream        = flag.String("from-stream", "", "The name of the stream to migrate")
+		fromSubjectPrefix = flag.String("from-subject-prefix", "", "The subject prefix of the stream to migrate")
+		toStream          = flag.String("to-stream", "", "The name of the target stream")
+		toSubjectPrefix   = flag.String("to-subject-prefix", "", "The subject prefix of the target stream")
+		toStorage         = flag.String("to-storage", "",
+			"The storage of the target stream, either file or memory, the storage of the migrated stream is kept if empty")
+		deleteSource  = flag.Bool("delete-source", true, "Delete the migrated stream with its consumers")
+		verifyTimeout = flag.Duration("verify-timeout", migration.DefaultVerifyTimeout,
+			"How long to wait for the target stream to hold all events of the migrated stream")
+		dryRun   = flag.Bool("dry-run", false, "Print the migration plan without running it")
+		logLevel = flag.String("log-level", string(kymalogger.INFO), "The log level")
+	)
+	flag.Parse()
+
+	migrationLogger, err := logger.New(string(kymalogger.JSON), *logLevel)
+	if err != nil {
+		log.Fatalf("Failed to create the logger, error: %v", err)
+	}
+	migrationLog := migrationLogger.WithContext().Named("stream-migration")
+
+	conn, err := nats.Connect(*natsURL, nats.Name("Kyma Stream Migration"))
+	if err != nil {
+		migrationLog.Fatalw("Failed to connect to NATS", "url", *natsURL, "error", err)
+	}
+	defer conn.Close()
+	js, err := conn.JetStream()
+	if err != nil {
+		migrationLog.Fatalw("Failed to create the JetStream context", "error", err)
+	}
+
+	plan, err := migration.NewPlan(js, migration.Config{
+		From:          migration.StreamConfig{Name: *fromStream, SubjectPrefix: *fromSubjectPrefix},
+		To:            migration.StreamConfig{Name: *toStream, SubjectPrefix: *toSubjectPrefix, Storage: *toStorage},
+		DeleteSource:  *deleteSource,
+		VerifyTimeout: *verifyTimeout,
+	})
+	// the job runs on every upgrade, so a missing or unchanged stream is not an error
+	if errors.Is(err, migration.ErrNothingToMigrate) || errors.Is(err, nats.ErrStreamNotFound) {
+		migrationLog.Infow("Nothing to migrate", "reason", err)
+		return
+	}
+	if err != nil {
+		migrationLog.Fatalw("Failed to generate the migration plan", "error", err)
+	}
+	fmt.Fprint(os.Stdout, plan)
+	if *dryRun {
+		return
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	start := time.Now()
+	if err = plan.Run(ctx, js, migrationLog); err != nil {
+		migrationLog.Fatalw("Failed to migrate the stream", "error", err)
+	}
+	migrationLog.Infow("Migrated the stream", "from", *fromStream, "to", *toStream, "duration", time.Since(start))
+}
@@ -0,0 +1,315 @@
+// Package application registers the event types declared by the Application CRs in the event type catalogs, and
+// maintains the allowlist of the publisher proxy accepting them, so that the events of the connected systems are
+// routable without manual configuration.
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+const (
+	reconcilerName = "application-reconciler"
+
+	// EventTypesAnnotation declares the comma-separated event types provided by an Application,
+	// such as `order.created.v1,order.updated.v1`.
+	EventTypesAnnotation = "eventing.kyma-project.io/event-types"
+	// ApplicationLabel labels the EventTypes registered for an Application with the name of the Application.
+	ApplicationLabel = "eventing.kyma-project.io/application"
+	// AllowlistKey is the key of the allowlist in the ConfigMap, in the format of the publisher proxy policy file.
+	AllowlistKey = "allowlist.json"
+)
+
+var (
+	// GroupVersionKind is the kind of the Application CRs. The CRs are read as unstructured objects, since only their
+	// metadata is used.
+	GroupVersionKind = schema.GroupVersionKind{
+		Group:   "applicationconnector.kyma-project.io",
+		Version: "v1alpha1",
+		Kind:    "Application",
+	}
+
+	// invalidNameCharacters are the characters of the event types not allowed in the names of the EventTypes.
+	invalidNameCharacters = regexp.MustCompile(`[^a-z0-9.-]`)
+)
+
+// allowlist is the allowlist of the publisher proxy, in the format of its policy file.
+type allowlist struct {
+	Rules []allowlistRule `json:"rules"`
+}
+
+// allowlistRule allows an Application to publish its declared event types with its name as the source.
+type allowlistRule struct {
+	Identity string   `json:"identity"`
+	Types    []string `json:"types"`
+	Sources  []string `json:"sources"`
+}
+
+// Reconciler reconciles the EventTypes of an Application and the allowlist of all Applications. The requests only
+// have the name of the Application set.
+type Reconciler struct {
+	client.Client
+	// apiReader reads the allowlist ConfigMap, since the cache only holds the feature flags ConfigMap.
+	apiReader  client.Reader
+	namespaces []string
+	allowlist  types.NamespacedName
+	logger     *logger.Logger
+}
+
+// NewReconciler returns a Reconciler registering the event types of the Applications in the given Namespaces,
+// and maintaining the given allowlist ConfigMap unless its name is empty.
+func NewReconciler(client client.Client, apiReader client.Reader, namespaces []string,
+	allowlist types.NamespacedName, logger *logger.Logger) *Reconciler {
+	return &Reconciler{
+		Client:     client,
+		apiReader:  apiReader,
+		namespaces: namespaces,
+		allowlist:  allowlist,
+		logger:     logger,
+	}
+}
+
+// +kubebuilder:rbac:groups=applicationconnector.kyma-project.io,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=eventtypes,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	app := newApplication()
+	if err := r.Get(ctx, types.NamespacedName{Name: req.Name}, app); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, xerrors.Errorf("failed to get application %s: %v", req.Name, err)
+		}
+		app = nil
+	}
+	if err := r.syncEventTypes(ctx, req.Name, app); err != nil {
+		return ctrl.Result{}, err
+	}
+	if r.allowlist.Name == "" {
+		return ctrl.Result{}, nil
+	}
+	if err := r.syncAllowlist(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncEventTypes creates, updates and deletes the EventTypes of the Application with the given name, so that they
+// match its declared event types. The Application is nil if it does not exist.
+func (r *Reconciler) syncEventTypes(ctx context.Context, name string, app *unstructured.Unstructured) error {
+	desired := make(map[types.NamespacedName]*eventingv1alpha1.EventType)
+	for _, eventType := range r.desiredEventTypes(app) {
+		desired[types.NamespacedName{Namespace: eventType.Namespace, Name: eventType.Name}] = eventType
+	}
+
+	existing := &eventingv1alpha1.EventTypeList{}
+	if err := r.List(ctx, existing, client.MatchingLabels{ApplicationLabel: name}); err != nil {
+		return xerrors.Errorf("failed to list event types of application %s: %v", name, err)
+	}
+	for i := range existing.Items {
+		eventType := &existing.Items[i]
+		key := types.NamespacedName{Namespace: eventType.Namespace, Name: eventType.Name}
+		want, ok := desired[key]
+		delete(desired, key)
+		if !ok {
+			if err := r.Delete(ctx, eventType); client.IgnoreNotFound(err) != nil {
+				return xerrors.Errorf("failed to delete event type %s: %v", key, err)
+			}
+			r.namedLogger().Debugw("Deleted event type", "namespace", key.Namespace, "name", key.Name)
+			continue
+		}
+		// the owner is updated as well, since a recreated Application has another UID
+		if reflect.DeepEqual(eventType.Spec, want.Spec) &&
+			reflect.DeepEqual(eventType.OwnerReferences, want.OwnerReferences) {
+			continue
+		}
+		eventType.Spec, eventType.OwnerReferences = want.Spec, want.OwnerReferences
+		if err := r.Update(ctx, eventType); err != nil {
+			return xerrors.Errorf("failed to update event type %s: %v", key, err)
+		}
+		r.namedLogger().Debugw("Updated event type", "namespace", key.Namespace, "name", key.Name)
+	}
+
+	for key, eventType := range desired {
+		err := r.Create(ctx, eventType)
+		if k8serrors.IsAlreadyExists(err) {
+			// an EventType of the same name is not managed for the Application, so it is kept as it is
+			r.namedLogger().Warnw("Skipped event type of application, because an unmanaged event type exists",
+				"namespace", key.Namespace, "name", key.Name, "application", name)
+			continue
+		}
+		if err != nil {
+			return xerrors.Errorf("failed to create event type %s: %v", key, err)
+		}
+		r.namedLogger().Debugw("Created event type", "namespace", key.Namespace, "name", key.Name)
+	}
+	return nil
+}
+
+// desiredEventTypes returns the EventTypes of the declared event types of the Application in every Namespace.
+// They are owned by the Application, so that they are deleted with it.
+func (r *Reconciler) desiredEventTypes(app *unstructured.Unstructured) []*eventingv1alpha1.EventType {
+	if app == nil || !app.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	var eventTypes []*eventingv1alpha1.EventType
+	for _, namespace := range r.namespaces {
+		for _, declared := range DeclaredEventTypes(app) {
+			eventTypes = append(eventTypes, &eventingv1alpha1.EventType{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      EventTypeName(app.GetName(), declared),
+					Namespace: namespace,
+					Labels:    map[string]string{ApplicationLabel: app.GetName()},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: GroupVersionKind.GroupVersion().String(),
+						Kind:       GroupVersionKind.Kind,
+						Name:       app.GetName(),
+						UID:        app.GetUID(),
+					}},
+				},
+				Spec: eventingv1alpha1.EventTypeSpec{
+					Type:        declared,
+					Source:      app.GetName(),
+					Owner:       app.GetName(),
+					Description: fmt.Sprintf("Provided by the application %s.", app.GetName()),
+				},
+			})
+		}
+	}
+	return eventTypes
+}
+
+// syncAllowlist writes the allowlist of all Applications to the ConfigMap if it changed.
+func (r *Reconciler) syncAllowlist(ctx context.Context) error {
+	apps := &unstructured.UnstructuredList{}
+	apps.SetGroupVersionKind(GroupVersionKind.GroupVersion().WithKind(GroupVersionKind.Kind + "List"))
+	if err := r.List(ctx, apps); err != nil {
+		return xerrors.Errorf("failed to list applications: %v", err)
+	}
+	data, err := json.Marshal(newAllowlist(apps.Items))
+	if err != nil {
+		return xerrors.Errorf("failed to marshal allowlist: %v", err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = r.apiReader.Get(ctx, r.allowlist, cm)
+	if k8serrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: r.allowlist.Name, Namespace: r.allowlist.Namespace},
+			Data:       map[string]string{AllowlistKey: string(data)},
+		}
+		if err = r.Create(ctx, cm); err != nil {
+			return xerrors.Errorf("failed to create allowlist ConfigMap %s: %v", r.allowlist, err)
+		}
+		r.namedLogger().Debugw("Created allowlist", "configmap", r.allowlist)
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("failed to get allowlist ConfigMap %s: %v", r.allowlist, err)
+	}
+	if cm.Data[AllowlistKey] == string(data) {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = make(map[string]string, 1)
+	}
+	cm.Data[AllowlistKey] = string(data)
+	if err = r.Update(ctx, cm); err != nil {
+		return xerrors.Errorf("failed to update allowlist ConfigMap %s: %v", r.allowlist, err)
+	}
+	r.namedLogger().Debugw("Updated allowlist", "configmap", r.allowlist)
+	return nil
+}
+
+// newAllowlist returns the allowlist of the given Applications, with a rule per Application declaring event types.
+func newAllowlist(apps []unstructured.Unstructured) *allowlist {
+	list := &allowlist{Rules: []allowlistRule{}}
+	for i := range apps {
+		app := &apps[i]
+		declared := DeclaredEventTypes(app)
+		if len(declared) == 0 || !app.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		list.Rules = append(list.Rules, allowlistRule{
+			Identity: app.GetName(),
+			Types:    declared,
+			Sources:  []string{app.GetName()},
+		})
+	}
+	sort.Slice(list.Rules, func(i, j int) bool { return list.Rules[i].Identity < list.Rules[j].Identity })
+	return list
+}
+
+// DeclaredEventTypes returns the sorted event types declared by the EventTypesAnnotation of the given Application.
+func DeclaredEventTypes(app client.Object) []string {
+	seen := make(map[string]bool)
+	var eventTypes []string
+	for _, eventType := range strings.Split(app.GetAnnotations()[EventTypesAnnotation], ",") {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" || seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	return eventTypes
+}
+
+// EventTypeName returns the name of the EventType of the given Application and event type, as the name of the
+// Application followed by the event type in lower case, with the characters not allowed in names replaced by `-`.
+func EventTypeName(appName, eventType string) string {
+	name := invalidNameCharacters.ReplaceAllString(strings.ToLower(eventType), "-")
+	return appName + "." + strings.Trim(name, ".-")
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(reconcilerName).
+		For(newApplication(), builder.WithPredicates(predicate.AnnotationChangedPredicate{})).
+		Watches(&eventingv1alpha1.EventType{}, handler.EnqueueRequestsFromMapFunc(mapToApplication),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// mapToApplication enqueues the Application of the given EventType, so that its changes are reverted.
+func mapToApplication(_ context.Context, obj client.Object) []reconcile.Request {
+	name, ok := obj.GetLabels()[ApplicationLabel]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+}
+
+func newApplication() *unstructured.Unstructured {
+	app := &unstructured.Unstructured{}
+	app.SetGroupVersionKind(GroupVersionKind)
+	return app
+}
+
+func (r *Reconciler) namedLogger() *zap.SugaredLogger {
+	return r.logger.WithContext().Named(reconcilerName)
+}
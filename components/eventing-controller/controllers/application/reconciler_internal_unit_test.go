@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+const appName = "commerce"
+
+var allowlistConfigMap = types.NamespacedName{Namespace: "kyma-system", Name: "eventing-application-allowlist"}
+
+func Test_Reconcile(t *testing.T) {
+	// given
+	ctx := context.Background()
+	app := newTestApplication(appName, " order.updated.v1,order.created.v1,, order.created.v1")
+	withoutTypes := newTestApplication("crm", "")
+	stale := newManagedEventType("shop", "order.deleted.v1")
+	changed := newManagedEventType("shop", "order.updated.v1")
+	changed.Spec.Source = "changed"
+	unmanaged := &eventingv1alpha1.EventType{
+		ObjectMeta: metav1.ObjectMeta{Name: EventTypeName(appName, "order.created.v1"), Namespace: "marketing"},
+		Spec:       eventingv1alpha1.EventTypeSpec{Type: "order.created.v1", Source: "manual"},
+	}
+	r := newTestReconciler(t, app, withoutTypes, stale, changed, unmanaged)
+
+	// when
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: appName}})
+
+	// then the EventTypes of the declared event types are registered in every Namespace
+	require.NoError(t, err)
+	eventTypes := &eventingv1alpha1.EventTypeList{}
+	require.NoError(t, r.List(ctx, eventTypes, client.MatchingLabels{ApplicationLabel: appName}))
+	var got []string
+	for _, eventType := range eventTypes.Items {
+		got = append(got, eventType.Namespace+"/"+eventType.Name)
+		require.Equal(t, appName, eventType.Spec.Source)
+		require.Equal(t, appName, eventType.Spec.Owner)
+		require.Len(t, eventType.OwnerReferences, 1)
+		require.Equal(t, app.GetUID(), eventType.OwnerReferences[0].UID)
+	}
+	require.ElementsMatch(t, []string{
+		"shop/commerce.order.created.v1",
+		"shop/commerce.order.updated.v1",
+		"marketing/commerce.order.updated.v1",
+	}, got)
+
+	// and the unmanaged EventType of the same name is kept
+	require.NoError(t, r.Get(ctx, client.ObjectKeyFromObject(unmanaged), unmanaged))
+	require.Equal(t, "manual", unmanaged.Spec.Source)
+
+	// and the allowlist allows the declared event types
+	requireAllowlist(t, r, `{"rules":[{"identity":"commerce","types":["order.created.v1","order.updated.v1"],`+
+		`"sources":["commerce"]}]}`)
+
+	// when the Application is deleted
+	require.NoError(t, r.Delete(ctx, app))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: appName}})
+
+	// then its EventTypes and rules are removed
+	require.NoError(t, err)
+	require.NoError(t, r.List(ctx, eventTypes, client.MatchingLabels{ApplicationLabel: appName}))
+	require.Empty(t, eventTypes.Items)
+	requireAllowlist(t, r, `{"rules":[]}`)
+}
+
+func Test_EventTypeName(t *testing.T) {
+	require.Equal(t, "commerce.order.created.v1", EventTypeName("commerce", "order.created.v1"))
+	require.Equal(t, "commerce.order-created--v1", EventTypeName("commerce", "Order Created_ V1"))
+	require.Equal(t, "commerce.order.created", EventTypeName("commerce", ".order.created."))
+}
+
+func requireAllowlist(t *testing.T, r *Reconciler, want string) {
+	t.Helper()
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, r.Get(context.Background(), allowlistConfigMap, cm))
+	require.JSONEq(t, want, cm.Data[AllowlistKey])
+}
+
+func newTestApplication(name, eventTypes string) *unstructured.Unstructured {
+	app := newApplication()
+	app.SetName(name)
+	app.SetUID(types.UID(name + "-uid"))
+	if eventTypes != "" {
+		app.SetAnnotations(map[string]string{EventTypesAnnotation: eventTypes})
+	}
+	return app
+}
+
+func newManagedEventType(namespace, eventType string) *eventingv1alpha1.EventType {
+	return &eventingv1alpha1.EventType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EventTypeName(appName, eventType),
+			Namespace: namespace,
+			Labels:    map[string]string{ApplicationLabel: appName},
+		},
+		Spec: eventingv1alpha1.EventTypeSpec{Type: eventType, Source: appName, Owner: appName},
+	}
+}
+
+func newTestReconciler(t *testing.T, objs ...client.Object) *Reconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, eventingv1alpha1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+
+	return NewReconciler(fakeClient, fakeClient, []string{"shop", "marketing"}, allowlistConfigMap, l)
+}
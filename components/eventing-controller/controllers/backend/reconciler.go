@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"time"
 
 	"github.com/pkg/errors"
@@ -23,13 +24,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
 	"github.com/kyma-project/kyma/components/eventing-controller/internal/featureflags"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/deployment"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 	pkgerrors "github.com/kyma-project/kyma/components/eventing-controller/pkg/errors"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/object"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/subscriptionmanager"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/webhookcert"
 	"github.com/kyma-project/kyma/components/eventing-controller/utils"
 )
 
@@ -102,6 +106,22 @@ type Reconciler struct {
 	backendType eventingv1alpha1.BackendType
 	// credentials that are passed to the BEB subscription reconciler
 	credentials oauth2Credentials
+	// envNATSURL is the NATS URL of the environment configuration, used if the EventingBackend spec has none
+	envNATSURL string
+	// secondaryBackend is the backend which runs next to the primary one for the Subscriptions selecting it
+	secondaryBackend string
+	// natsSubMgrPaused is true if the NATS subscription manager was paused with its events retained in the stream
+	natsSubMgrPaused bool
+	// kafkaSubMgr is the subscription manager of the Kafka backend, it is nil if Kafka is not configured
+	kafkaSubMgr        subscriptionmanager.Manager
+	kafkaSubMgrStarted bool
+	// kafkaSubMgrPaused is true if the Kafka subscription manager was paused with its consumer groups retained
+	kafkaSubMgrPaused bool
+	// envKafkaBrokers are the Kafka brokers of the environment configuration, used if the EventingBackend spec has none
+	envKafkaBrokers []string
+	// shard is the Subscription shard of this replica. Only the primary shard writes the cluster-wide resources,
+	// such as the EventingBackend and the publisher proxy, while every shard runs its subscription managers.
+	shard sharding.Shard
 }
 
 func NewReconciler(
@@ -118,6 +138,7 @@ func NewReconciler(
 		ctx:        ctx,
 		natsSubMgr: natsSubMgr,
 		natsConfig: natsConfig,
+		envNATSURL: natsConfig.URL,
 		envCfg:     envCfg,
 		bebSubMgr:  bebSubMgr,
 		Client:     client,
@@ -129,12 +150,25 @@ func NewReconciler(
 
 func (r *Reconciler) SetNatsConfig(natsConfig env.NATSConfig) {
 	r.natsConfig = natsConfig
+	r.envNATSURL = natsConfig.URL
 }
 
 func (r *Reconciler) SetBackendConfig(backendCfg env.BackendConfig) {
 	r.cfg = backendCfg
 }
 
+// SetKafkaSubscriptionManager enables the Kafka backend with the given subscription manager
+// and the brokers of the environment configuration.
+func (r *Reconciler) SetKafkaSubscriptionManager(kafkaSubMgr subscriptionmanager.Manager, brokers []string) {
+	r.kafkaSubMgr = kafkaSubMgr
+	r.envKafkaBrokers = brokers
+}
+
+// SetShard sets the Subscription shard of this replica.
+func (r *Reconciler) SetShard(shard sharding.Shard) {
+	r.shard = shard
+}
+
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;patch;create;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update;patch;create;delete
 // +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=eventingbackends,verbs=get;list;watch;create;update;patch;delete
@@ -143,51 +177,226 @@ func (r *Reconciler) SetBackendConfig(backendCfg env.BackendConfig) {
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
 
 func (r *Reconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
-	if err := r.updateMutatingValidatingWebhookWithCABundle(ctx); err != nil {
-		return ctrl.Result{}, err
+	// the cluster-wide resources are written by the primary shard only,
+	// the other shards start the subscription managers of the selected backend
+	if r.shard.IsPrimary() {
+		if err := r.updateMutatingValidatingWebhookWithCABundle(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	// Create NATS Secret for the eventing-nats statefulset
-	if featureflags.IsNATSProvisioningEnabled() {
+	if featureflags.IsNATSProvisioningEnabled() && r.shard.IsPrimary() {
 		if createErr := r.createNATSSecret(ctx); createErr != nil {
 			return ctrl.Result{}, createErr
 		}
 	}
 
-	var secretList v1.SecretList
 	// the default status has all conditions and eventingReady set to true.
 	// if something breaks during reconciliation, the condition and eventingReady is updated to false.
 	defaultStatus := getDefaultBackendStatus()
 
+	spec, err := r.getBackendSpec(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	backendType, bebSecret, reason, err := r.selectBackend(ctx, spec)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if reason != "" {
+		// the selected backend cannot be reconciled until its configuration is fixed
+		defaultStatus.Backend = backendType
+		defaultStatus.SetSubscriptionControllerReadyCondition(false, reason, "")
+		if updateErr := r.syncBackendStatus(ctx, &defaultStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(updateErr, "update EventingBackend status failed")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	var result ctrl.Result
+	switch backendType {
+	case eventingv1alpha1.KafkaBackendType:
+		result, err = r.reconcileKafkaBackend(ctx, &defaultStatus, spec.Kafka, spec.Paused)
+	case eventingv1alpha1.BEBBackendType:
+		result, err = r.reconcileBEBBackend(ctx, bebSecret, &defaultStatus)
+	default:
+		result, err = r.reconcileNATSBackend(ctx, &defaultStatus, spec.NATS, spec.Paused, bebSecret)
+	}
+	// reconcile periodically to keep the summary of the EventingBackend status up-to-date
+	if err == nil && result.IsZero() {
+		result.RequeueAfter = summaryInterval
+	}
+	return result, err
+}
+
+// getBackendSpec returns the spec of the EventingBackend or an empty spec if it does not exist yet.
+func (r *Reconciler) getBackendSpec(ctx context.Context) (eventingv1alpha1.EventingBackendSpec, error) {
+	currentBackend, err := r.getCurrentBackendCR(ctx)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return eventingv1alpha1.EventingBackendSpec{}, nil
+		}
+		return eventingv1alpha1.EventingBackendSpec{}, errors.Wrapf(err, "get EventingBackend failed")
+	}
+	return currentBackend.Spec, nil
+}
+
+// selectBackend returns the backend type selected by the EventingBackend spec together with the BEB Secret if BEB
+// is selected. If the selected backend is misconfigured, it also returns the reason of the not ready condition.
+// If the spec does not select a backend, BEB is selected if a Secret with the BEB label exists, otherwise NATS.
+// If NATS is selected and a BEB Secret is referenced, the Secret is returned to run BEB as the secondary backend.
+// Kafka is only selected explicitly by the spec.
+func (r *Reconciler) selectBackend(ctx context.Context, spec eventingv1alpha1.EventingBackendSpec) (
+	eventingv1alpha1.BackendType, *v1.Secret, eventingv1alpha1.ConditionReason, error) {
+	if spec.Type == eventingv1alpha1.KafkaBackendType {
+		if r.kafkaSubMgr == nil {
+			return eventingv1alpha1.KafkaBackendType, nil, eventingv1alpha1.ConditionReasonControllerStartFailed, nil
+		}
+		return eventingv1alpha1.KafkaBackendType, nil, "", nil
+	}
+
+	bebSecret, err := r.getReferencedBEBSecret(ctx, spec.BEB)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	if spec.Type == eventingv1alpha1.NatsBackendType {
+		return eventingv1alpha1.NatsBackendType, bebSecret, "", nil
+	}
+
+	if spec.BEB != nil && spec.BEB.SecretName != "" {
+		if bebSecret == nil {
+			return eventingv1alpha1.BEBBackendType, nil, eventingv1alpha1.ConditionReasonBackendSecretNotFound, nil
+		}
+		return eventingv1alpha1.BEBBackendType, bebSecret, "", nil
+	}
+
+	var secretList v1.SecretList
 	if err := r.List(ctx, &secretList, client.MatchingLabels{
 		BEBBackendSecretLabelKey: BEBBackendSecretLabelValue,
 	}); err != nil {
-		return ctrl.Result{}, err
+		return "", nil, "", err
 	}
 
-	if len(secretList.Items) > 1 {
+	switch {
+	case len(secretList.Items) > 1:
 		// This is not allowed!
 		r.namedLogger().Debugw("More than one secret with the EventingBackend label exist", "key", BEBBackendSecretLabelKey, "value", BEBBackendSecretLabelValue, "count", len(secretList.Items))
-		defaultStatus.Backend = eventingv1alpha1.BEBBackendType
-		defaultStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionDuplicateSecrets, "")
-		if updateErr := r.syncBackendStatus(ctx, &defaultStatus, nil); updateErr != nil {
-			return ctrl.Result{}, errors.Wrapf(updateErr, "update EventingBackend status failed")
+		return eventingv1alpha1.BEBBackendType, nil, eventingv1alpha1.ConditionDuplicateSecrets, nil
+	case len(secretList.Items) == 1:
+		return eventingv1alpha1.BEBBackendType, &secretList.Items[0], "", nil
+	case spec.Type == eventingv1alpha1.BEBBackendType:
+		return eventingv1alpha1.BEBBackendType, nil, eventingv1alpha1.ConditionReasonBackendSecretNotFound, nil
+	default:
+		return eventingv1alpha1.NatsBackendType, nil, "", nil
+	}
+}
+
+// getReferencedBEBSecret returns the BEB Secret referenced by the EventingBackend spec,
+// or nil if no Secret is referenced or the referenced Secret does not exist.
+func (r *Reconciler) getReferencedBEBSecret(ctx context.Context, bebSpec *eventingv1alpha1.BEBBackendSpec) (
+	*v1.Secret, error) {
+	if bebSpec == nil || bebSpec.SecretName == "" {
+		return nil, nil
+	}
+	bebSecret := &v1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: bebSpec.SecretName, Namespace: bebSpec.SecretNamespace}, bebSecret)
+	if k8serrors.IsNotFound(err) {
+		r.namedLogger().Debugw("The referenced BEB secret does not exist",
+			"name", bebSpec.SecretName, "namespace", bebSpec.SecretNamespace)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "get BEB secret failed")
+	}
+	return bebSecret, nil
+}
+
+// syncSecondaryBackend records the backend which runs next to the primary one. If it changed, the running
+// subscription managers which are still needed are stopped without cleanup, so that they are started again
+// with the new selection of Subscriptions.
+func (r *Reconciler) syncSecondaryBackend(secondaryBackend string) error {
+	if secondaryBackend == r.secondaryBackend {
+		return nil
+	}
+	r.secondaryBackend = secondaryBackend
+
+	if r.natsSubMgrStarted &&
+		(r.backendType == eventingv1alpha1.NatsBackendType || secondaryBackend == eventingv1alpha2.BackendNATS) {
+		if err := r.natsSubMgr.Stop(false); err != nil {
+			return errors.Errorf("failed to stop NATS subscription manager: %v", err)
 		}
-		return ctrl.Result{}, nil
+		r.natsSubMgrStarted = false
+	}
+	if r.bebSubMgrStarted &&
+		(r.backendType == eventingv1alpha1.BEBBackendType || secondaryBackend == eventingv1alpha2.BackendEventMesh) {
+		if err := r.bebSubMgr.Stop(false); err != nil {
+			return errors.Errorf("failed to stop BEB subscription manager: %v", err)
+		}
+		r.bebSubMgrStarted = false
 	}
+	return nil
+}
 
-	// If secret with label then BEB flow
-	if len(secretList.Items) == 1 {
-		return r.reconcileBEBBackend(ctx, &secretList.Items[0], &defaultStatus)
+// startSecondaryBEBController starts the BEB subscription controller next to NATS
+// for the Subscriptions selecting EventMesh.
+func (r *Reconciler) startSecondaryBEBController(ctx context.Context, bebSecret *v1.Secret,
+	backendStatus *eventingv1alpha1.EventingBackendStatus) error {
+	if err := r.syncOauth2ClientIDAndSecret(ctx, backendStatus); err != nil {
+		return err
+	}
+	secretForPublisher, err := getSecretForPublisher(bebSecret)
+	if err != nil {
+		return err
+	}
+	if err := setUpEnvironmentForBEBController(secretForPublisher); err != nil {
+		return errors.Wrapf(err, "failed to setup environment variables for BEB controller")
 	}
+	return r.startBEBController()
+}
 
-	// Default: NATS flow
-	return r.reconcileNATSBackend(ctx, &defaultStatus)
+// natsConfigSetter is implemented by subscription managers whose NATS configuration can be replaced at runtime.
+type natsConfigSetter interface {
+	SetNATSConfig(natsConfig env.NATSConfig)
 }
 
-func (r *Reconciler) reconcileNATSBackend(ctx context.Context, backendStatus *eventingv1alpha1.EventingBackendStatus) (ctrl.Result, error) {
+// syncNATSConfig applies the NATS URL of the EventingBackend spec, or the URL of the environment configuration
+// if the spec has none. If the URL changed, the NATS subscription manager is stopped to be started again
+// with the new configuration.
+func (r *Reconciler) syncNATSConfig(natsSpec *eventingv1alpha1.NATSBackendSpec) error {
+	url := r.envNATSURL
+	if natsSpec != nil && natsSpec.URL != "" {
+		url = natsSpec.URL
+	}
+	if url == r.natsConfig.URL {
+		return nil
+	}
+
+	r.namedLogger().Infow("NATS URL changed", "url", url)
+	r.natsConfig.URL = url
+	if r.natsSubMgrStarted {
+		if err := r.natsSubMgr.Stop(false); err != nil {
+			return errors.Errorf("failed to stop NATS subscription manager: %v", err)
+		}
+		r.natsSubMgrStarted = false
+	}
+	if setter, ok := r.natsSubMgr.(natsConfigSetter); ok {
+		setter.SetNATSConfig(r.natsConfig)
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileNATSBackend(ctx context.Context, backendStatus *eventingv1alpha1.EventingBackendStatus,
+	natsSpec *eventingv1alpha1.NATSBackendSpec, paused bool, bebSecret *v1.Secret) (ctrl.Result, error) {
 	r.backendType = eventingv1alpha1.NatsBackendType
 	backendStatus.Backend = r.backendType
+	secondaryBackend := ""
+	if bebSecret != nil {
+		secondaryBackend = eventingv1alpha2.BackendEventMesh
+		backendStatus.BEBSecretName, backendStatus.BEBSecretNamespace = bebSecret.Name, bebSecret.Namespace
+	}
 	// CreateOrUpdate CR with NATS
 	err := r.CreateOrUpdateBackendCR(ctx)
 	if err != nil {
@@ -198,17 +407,55 @@ func (r *Reconciler) reconcileNATSBackend(ctx context.Context, backendStatus *ev
 		return ctrl.Result{}, errors.Wrapf(err, "create or update EventingBackend failed, type: %s", eventingv1alpha1.NatsBackendType)
 	}
 
-	// Stop the BEB subscription controller
-	if err := r.stopBEBController(); err != nil {
+	// Restart the subscription controllers if the secondary backend changed
+	if err := r.syncSecondaryBackend(secondaryBackend); err != nil {
 		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
 		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
-			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while stopping BEB controller")
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while restarting the subscription controllers")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Stop the BEB subscription controller unless it runs next to NATS as the secondary backend
+	if bebSecret == nil {
+		if err := r.stopBEBController(); err != nil {
+			backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+			if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+				return ctrl.Result{}, errors.Wrapf(err, "failed to update status while stopping BEB controller")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Stop the Kafka subscription controller
+	if err := r.stopKafkaController(); err != nil {
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while stopping Kafka controller")
 		}
 		return ctrl.Result{}, err
 	}
 
-	// Start the NATS subscription controller
-	if err := r.startNATSController(); err != nil {
+	// Apply the NATS configuration of the EventingBackend spec
+	if err := r.syncNATSConfig(natsSpec); err != nil {
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while applying the NATS configuration")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Start the NATS subscription controller, or pause it during maintenance with the events retained in the stream
+	if paused {
+		if err := r.pauseNATSController(); err != nil {
+			backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+			if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+				return ctrl.Result{}, errors.Wrapf(err, "failed to update status while pausing NATS controller")
+			}
+			return ctrl.Result{}, err
+		}
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonDispatchPaused, "")
+	} else if err := r.startNATSController(); err != nil {
 		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStartFailed, err.Error())
 		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while starting NATS controller")
@@ -217,6 +464,17 @@ func (r *Reconciler) reconcileNATSBackend(ctx context.Context, backendStatus *ev
 		return ctrl.Result{RequeueAfter: reconcileInterval}, nil
 	}
 
+	// Start the BEB subscription controller for the Subscriptions selecting EventMesh
+	if bebSecret != nil {
+		if err := r.startSecondaryBEBController(ctx, bebSecret, backendStatus); err != nil {
+			backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStartFailed, err.Error())
+			if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+				return ctrl.Result{}, errors.Wrapf(err, "failed to update status while starting BEB controller")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Delete secret for publisher proxy if it exists
 	err = r.deletePublisherProxySecret(ctx)
 	if err != nil {
@@ -260,6 +518,15 @@ func (r *Reconciler) reconcileBEBBackend(ctx context.Context, bebSecret *v1.Secr
 		return ctrl.Result{}, errors.Wrapf(err, "create/update EventingBackend failed, type: %s", eventingv1alpha1.BEBBackendType)
 	}
 
+	// Restart the BEB subscription controller if it ran as the secondary backend next to NATS
+	if err := r.syncSecondaryBackend(""); err != nil {
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while restarting the subscription controllers")
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Stop the NATS subscription controller
 	if err := r.stopNATSController(); err != nil {
 		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
@@ -269,6 +536,15 @@ func (r *Reconciler) reconcileBEBBackend(ctx context.Context, bebSecret *v1.Secr
 		return ctrl.Result{}, err
 	}
 
+	// Stop the Kafka subscription controller
+	if err := r.stopKafkaController(); err != nil {
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while stopping Kafka controller")
+		}
+		return ctrl.Result{}, err
+	}
+
 	// gets oauth2ClientID and secret and stops the BEB controller if changed
 	err = r.syncOauth2ClientIDAndSecret(ctx, backendStatus)
 	if err != nil {
@@ -332,6 +608,133 @@ func (r *Reconciler) reconcileBEBBackend(ctx context.Context, bebSecret *v1.Secr
 	return ctrl.Result{}, nil
 }
 
+// reconcileKafkaBackend runs the Kafka subscription controller. There is no publisher proxy for Kafka,
+// the producers publish the events to the topics directly.
+func (r *Reconciler) reconcileKafkaBackend(ctx context.Context, backendStatus *eventingv1alpha1.EventingBackendStatus,
+	kafkaSpec *eventingv1alpha1.KafkaBackendSpec, paused bool) (ctrl.Result, error) {
+	r.backendType = eventingv1alpha1.KafkaBackendType
+	backendStatus.Backend = r.backendType
+
+	// CreateOrUpdate CR with Kafka
+	err := r.CreateOrUpdateBackendCR(ctx)
+	if err != nil {
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonBackendCRSyncFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while creating/updating EventingBackend")
+		}
+		return ctrl.Result{}, errors.Wrapf(err, "create/update EventingBackend failed, type: %s", eventingv1alpha1.KafkaBackendType)
+	}
+
+	// Kafka does not run next to a secondary backend
+	if err := r.syncSecondaryBackend(""); err != nil {
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while restarting the subscription controllers")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Stop the NATS and BEB subscription controllers
+	if err := r.stopNATSController(); err != nil {
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while stopping NATS controller")
+		}
+		return ctrl.Result{}, err
+	}
+	if err := r.stopBEBController(); err != nil {
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while stopping BEB controller")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Apply the Kafka brokers of the EventingBackend spec
+	if err := r.syncKafkaConfig(kafkaSpec); err != nil {
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while applying the Kafka configuration")
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Start the Kafka subscription controller, or pause it during maintenance with the events retained in the topics
+	if paused {
+		if err := r.pauseKafkaController(); err != nil {
+			backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStopFailed, err.Error())
+			if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+				return ctrl.Result{}, errors.Wrapf(err, "failed to update status while pausing Kafka controller")
+			}
+			return ctrl.Result{}, err
+		}
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonDispatchPaused, "")
+	} else if err := r.startKafkaController(); err != nil {
+		backendStatus.SetSubscriptionControllerReadyCondition(false, eventingv1alpha1.ConditionReasonControllerStartFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while starting Kafka controller")
+		}
+		r.namedLogger().Errorf("failed to start Kafka controller: %v", err)
+		return ctrl.Result{RequeueAfter: reconcileInterval}, nil
+	}
+
+	// Delete the publisher proxy and its secret, the Event Publisher does not support Kafka
+	if err := r.deletePublisherProxy(ctx); err != nil {
+		backendStatus.SetPublisherReadyCondition(false, eventingv1alpha1.ConditionReasonPublisherProxySyncFailed, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while deleting Event Publisher deployment")
+		}
+		return ctrl.Result{}, errors.Wrapf(err, "delete eventing Event Publisher deployment failed")
+	}
+	if err := r.deletePublisherProxySecret(ctx); err != nil {
+		backendStatus.SetPublisherReadyCondition(false, eventingv1alpha1.ConditionReasonPublisherProxySecretError, err.Error())
+		if updateErr := r.syncBackendStatus(ctx, backendStatus, nil); updateErr != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to update status while deleting Event Publisher secret")
+		}
+		return ctrl.Result{}, errors.Wrapf(err, "delete eventing Event Publisher secret failed")
+	}
+
+	if r.kafkaSubMgrStarted && !backendStatus.IsSubscriptionControllerStatusReady() {
+		backendStatus.SetSubscriptionControllerReadyCondition(true, eventingv1alpha1.ConditionReasonSubscriptionControllerReady, "")
+	}
+	return ctrl.Result{}, r.syncBackendStatus(ctx, backendStatus, nil)
+}
+
+// kafkaConfigSetter is implemented by subscription managers whose Kafka configuration can be replaced at runtime.
+type kafkaConfigSetter interface {
+	GetKafkaConfig() env.KafkaConfig
+	SetKafkaConfig(kafkaConfig env.KafkaConfig)
+}
+
+// syncKafkaConfig applies the Kafka brokers of the EventingBackend spec, or the brokers of the environment
+// configuration if the spec has none. If the brokers changed, the Kafka subscription manager is stopped to be
+// started again with the new configuration.
+func (r *Reconciler) syncKafkaConfig(kafkaSpec *eventingv1alpha1.KafkaBackendSpec) error {
+	setter, ok := r.kafkaSubMgr.(kafkaConfigSetter)
+	if !ok {
+		return nil
+	}
+	brokers := r.envKafkaBrokers
+	if kafkaSpec != nil && len(kafkaSpec.Brokers) > 0 {
+		brokers = kafkaSpec.Brokers
+	}
+	kafkaConfig := setter.GetKafkaConfig()
+	if reflect.DeepEqual(brokers, kafkaConfig.Brokers) {
+		return nil
+	}
+
+	r.namedLogger().Infow("Kafka brokers changed", "brokers", brokers)
+	if r.kafkaSubMgrStarted {
+		if err := r.kafkaSubMgr.Stop(false); err != nil {
+			return errors.Errorf("failed to stop Kafka subscription manager: %v", err)
+		}
+		r.kafkaSubMgrStarted = false
+	}
+	kafkaConfig.Brokers = brokers
+	setter.SetKafkaConfig(kafkaConfig)
+	return nil
+}
+
 func (r *Reconciler) syncOauth2ClientIDAndSecret(ctx context.Context, backendStatus *eventingv1alpha1.EventingBackendStatus) error {
 	// Following could return an error when the OAuth2Client CR is created for the first time, until the secret is
 	// created by the Hydra operator. However, eventually it should get resolved in the next few reconciliation loops.
@@ -402,6 +805,10 @@ func setUpEnvironmentForBEBController(secret *v1.Secret) error {
 }
 
 func (r *Reconciler) syncBackendStatus(ctx context.Context, backendStatus *eventingv1alpha1.EventingBackendStatus, publisher *appsv1.Deployment) error {
+	// the EventingBackend status is owned by the primary shard
+	if !r.shard.IsPrimary() {
+		return nil
+	}
 	currentBackend, err := r.getCurrentBackendCR(ctx)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
@@ -417,11 +824,16 @@ func (r *Reconciler) syncBackendStatus(ctx context.Context, backendStatus *event
 	}
 
 	var publisherReady bool
-	if publisher == nil {
+	switch {
+	case backendStatus.Backend == eventingv1alpha1.KafkaBackendType:
+		// the producers publish to the Kafka topics directly
+		publisherReady = true
+		backendStatus.SetPublisherReadyCondition(true, eventingv1alpha1.ConditionReasonPublisherNotRequired, "")
+	case publisher == nil:
 		if backendStatus.IsPublisherStatusReady() {
 			backendStatus.SetPublisherReadyCondition(false, eventingv1alpha1.ConditionReasonPublisherDeploymentNotReady, "")
 		}
-	} else {
+	default:
 		publisherReady = r.isPublisherDeploymentReady(publisher)
 		if !publisherReady {
 			backendStatus.SetPublisherReadyCondition(false, eventingv1alpha1.ConditionReasonPublisherDeploymentNotReady, "")
@@ -431,6 +843,7 @@ func (r *Reconciler) syncBackendStatus(ctx context.Context, backendStatus *event
 	}
 	// mark eventing as ready if subscription controller and publisher are ready
 	backendStatus.EventingReady = utils.BoolPtr(backendStatus.IsSubscriptionControllerStatusReady() && publisherReady)
+	r.syncSummary(ctx, currentBackend.Status.Summary, backendStatus, time.Now())
 	return r.updateStatusAndEmitEvent(ctx, currentBackend, backendStatus)
 }
 
@@ -498,6 +911,9 @@ func getDefaultBackendStatus() eventingv1alpha1.EventingBackendStatus {
 }
 
 func (r *Reconciler) deletePublisherProxySecret(ctx context.Context) error {
+	if !r.shard.IsPrimary() {
+		return nil
+	}
 	secretNamespacedName := types.NamespacedName{
 		Namespace: deployment.PublisherNamespace,
 		Name:      deployment.PublisherName,
@@ -541,6 +957,10 @@ func (r *Reconciler) SyncPublisherProxySecret(ctx context.Context, secret *v1.Se
 	if err != nil {
 		return nil, errors.Wrapf(err, "invalid secret for Event Publisher")
 	}
+	// the secondary shards only need the secret to configure their BEB subscription manager
+	if !r.shard.IsPrimary() {
+		return desiredSecret, nil
+	}
 	err = r.Get(ctx, secretNamespacedName, currentSecret)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
@@ -639,6 +1059,10 @@ func getSecretStringData(clientID, clientSecret, tokenEndpoint, grantType, publi
 }
 
 func (r *Reconciler) CreateOrUpdatePublisherProxy(ctx context.Context, backend eventingv1alpha1.BackendType) (*appsv1.Deployment, error) {
+	// the publisher proxy is owned by the primary shard
+	if !r.shard.IsPrimary() {
+		return nil, nil
+	}
 	return r.CreateOrUpdatePublisherProxyDeployment(ctx, backend, true)
 }
 
@@ -703,6 +1127,10 @@ func (r *Reconciler) CreateOrUpdatePublisherProxyDeployment(
 }
 
 func (r *Reconciler) CreateOrUpdateBackendCR(ctx context.Context) error {
+	// the EventingBackend is owned by the primary shard
+	if !r.shard.IsPrimary() {
+		return nil
+	}
 	labels := map[string]string{
 		BackendCRLabelKey: BackendCRLabelValue,
 	}
@@ -727,6 +1155,8 @@ func (r *Reconciler) CreateOrUpdateBackendCR(ctx context.Context) error {
 		return errors.Wrapf(err, "get EventingBackend failed")
 	}
 
+	// the spec is owned by the user
+	desiredBackend.Spec = currentBackend.Spec
 	desiredBackend.ResourceVersion = currentBackend.ResourceVersion
 	if object.Semantic.DeepEqual(&currentBackend, &desiredBackend) {
 		return nil
@@ -873,26 +1303,57 @@ func (r *Reconciler) createNATSSecret(ctx context.Context) error {
 
 func (r *Reconciler) startNATSController() error {
 	if !r.natsSubMgrStarted {
-		if err := r.natsSubMgr.Start(r.cfg.DefaultSubscriptionConfig, subscriptionmanager.Params{}); err != nil {
+		natsSubMgrParams := subscriptionmanager.Params{
+			subscriptionmanager.ParamNameSecondaryBackend: r.secondaryBackend,
+		}
+		if err := r.natsSubMgr.Start(r.cfg.DefaultSubscriptionConfig, natsSubMgrParams); err != nil {
 			return errors.Errorf("failed to start NATS subscription manager: %v", err)
 		}
 		r.natsSubMgrStarted = true
+		r.natsSubMgrPaused = false
 		r.namedLogger().Info("NATS subscription manager was started")
 	}
 	return nil
 }
 
 func (r *Reconciler) stopNATSController() error {
-	if r.natsSubMgrStarted {
+	if r.natsSubMgrStarted || r.natsSubMgrPaused {
 		if err := r.natsSubMgr.Stop(true); err != nil {
 			return errors.Errorf("failed to stop NATS subscription manager: %v", err)
 		}
 		r.natsSubMgrStarted = false
+		r.natsSubMgrPaused = false
 		r.namedLogger().Info("NATS subscription manager was stopped")
 	}
 	return nil
 }
 
+// dispatchPauser is implemented by subscription managers which can pause the dispatch while retaining the events.
+type dispatchPauser interface {
+	Pause() error
+}
+
+// pauseNATSController stops the dispatch of the NATS subscription manager without deleting its consumers.
+// Subscription managers which cannot pause are stopped without cleanup.
+func (r *Reconciler) pauseNATSController() error {
+	if !r.natsSubMgrStarted {
+		return nil
+	}
+	var err error
+	if pauser, ok := r.natsSubMgr.(dispatchPauser); ok {
+		err = pauser.Pause()
+	} else {
+		err = r.natsSubMgr.Stop(false)
+	}
+	if err != nil {
+		return errors.Errorf("failed to pause NATS subscription manager: %v", err)
+	}
+	r.natsSubMgrStarted = false
+	r.natsSubMgrPaused = true
+	r.namedLogger().Info("NATS subscription manager was paused")
+	return nil
+}
+
 func (r *Reconciler) startBEBController() error {
 	if !r.bebSubMgrStarted {
 		bebSubMgrParams := subscriptionmanager.Params{
@@ -901,6 +1362,7 @@ func (r *Reconciler) startBEBController() error {
 			subscriptionmanager.ParamNameTokenURL:     r.credentials.tokenURL,
 			subscriptionmanager.ParamNameCertsURL:     r.credentials.certsURL,
 		}
+		bebSubMgrParams[subscriptionmanager.ParamNameSecondaryBackend] = r.secondaryBackend
 		if err := r.bebSubMgr.Start(r.cfg.DefaultSubscriptionConfig, bebSubMgrParams); err != nil {
 			return errors.Errorf("failed to start BEB subscription manager: %v", err)
 		}
@@ -921,6 +1383,45 @@ func (r *Reconciler) stopBEBController() error {
 	return nil
 }
 
+func (r *Reconciler) startKafkaController() error {
+	if !r.kafkaSubMgrStarted {
+		if err := r.kafkaSubMgr.Start(r.cfg.DefaultSubscriptionConfig, subscriptionmanager.Params{}); err != nil {
+			return errors.Errorf("failed to start Kafka subscription manager: %v", err)
+		}
+		r.kafkaSubMgrStarted = true
+		r.kafkaSubMgrPaused = false
+		r.namedLogger().Info("Kafka subscription manager was started")
+	}
+	return nil
+}
+
+func (r *Reconciler) stopKafkaController() error {
+	if r.kafkaSubMgrStarted || r.kafkaSubMgrPaused {
+		if err := r.kafkaSubMgr.Stop(true); err != nil {
+			return errors.Errorf("failed to stop Kafka subscription manager: %v", err)
+		}
+		r.kafkaSubMgrStarted = false
+		r.kafkaSubMgrPaused = false
+		r.namedLogger().Info("Kafka subscription manager was stopped")
+	}
+	return nil
+}
+
+// pauseKafkaController stops the Kafka subscription manager without deleting the consumer groups, so that the
+// dispatch continues from their committed offsets once it is resumed.
+func (r *Reconciler) pauseKafkaController() error {
+	if !r.kafkaSubMgrStarted {
+		return nil
+	}
+	if err := r.kafkaSubMgr.Stop(false); err != nil {
+		return errors.Errorf("failed to pause Kafka subscription manager: %v", err)
+	}
+	r.kafkaSubMgrStarted = false
+	r.kafkaSubMgrPaused = true
+	r.namedLogger().Info("Kafka subscription manager was paused")
+	return nil
+}
+
 // getEPPDeployment fetches the event publisher by the current active backend type.
 func (r *Reconciler) getEPPDeployment(ctx context.Context) (*appsv1.Deployment, error) {
 	var list appsv1.DeploymentList
@@ -941,6 +1442,9 @@ func (r *Reconciler) getEPPDeployment(ctx context.Context) (*appsv1.Deployment,
 
 // deletePublisherProxy removes the existing publisher proxy.
 func (r *Reconciler) deletePublisherProxy(ctx context.Context) error {
+	if !r.shard.IsPrimary() {
+		return nil
+	}
 	publisherNamespacedName := types.NamespacedName{
 		Namespace: deployment.PublisherNamespace,
 		Name:      deployment.PublisherName,
@@ -985,11 +1489,17 @@ func (r *Reconciler) updateMutatingValidatingWebhookWithCABundle(ctx context.Con
 			errors.Errorf("validatingWH %s does not have associated webhooks", r.cfg.ValidatingWebhookName))
 	}
 
+	// prefer the CA bundle of the built-in certificate management, which keeps the previous certificate trusted
+	caBundle := certificateSecret.Data[webhookcert.CABundleKey]
+	if len(caBundle) == 0 {
+		caBundle = certificateSecret.Data[tlsCertField]
+	}
+
 	// check if the CABundle present is valid
 	if !(mutatingWH.Webhooks[0].ClientConfig.CABundle != nil &&
-		bytes.Equal(mutatingWH.Webhooks[0].ClientConfig.CABundle, certificateSecret.Data[tlsCertField])) {
+		bytes.Equal(mutatingWH.Webhooks[0].ClientConfig.CABundle, caBundle)) {
 		// update the ClientConfig for mutating WH config
-		mutatingWH.Webhooks[0].ClientConfig.CABundle = certificateSecret.Data[tlsCertField]
+		mutatingWH.Webhooks[0].ClientConfig.CABundle = caBundle
 		err = r.Client.Update(ctx, mutatingWH)
 		if err != nil {
 			return errors.Wrap(err, "while updating mutatingWH with caBundle")
@@ -997,9 +1507,9 @@ func (r *Reconciler) updateMutatingValidatingWebhookWithCABundle(ctx context.Con
 	}
 
 	if !(validatingWH.Webhooks[0].ClientConfig.CABundle != nil &&
-		bytes.Equal(validatingWH.Webhooks[0].ClientConfig.CABundle, certificateSecret.Data[tlsCertField])) {
+		bytes.Equal(validatingWH.Webhooks[0].ClientConfig.CABundle, caBundle)) {
 		// update the ClientConfig for validating WH config
-		validatingWH.Webhooks[0].ClientConfig.CABundle = certificateSecret.Data[tlsCertField]
+		validatingWH.Webhooks[0].ClientConfig.CABundle = caBundle
 		err = r.Client.Update(ctx, validatingWH)
 		if err != nil {
 			return errors.Wrap(err, "while updating validatingWH with caBundle")
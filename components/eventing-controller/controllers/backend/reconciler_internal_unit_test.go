@@ -5,22 +5,30 @@ import (
 	"crypto/rand"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	admissionv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
 
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
 	"github.com/kyma-project/kyma/components/eventing-controller/internal/featureflags"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/deployment"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
 )
 
 // TestGetSecretForPublisher verifies the successful and failing retrieval
@@ -750,6 +758,442 @@ func Test_isOauth2CredentialsInitialized(t *testing.T) {
 	}
 }
 
+func Test_selectBackend(t *testing.T) {
+	ctx := context.Background()
+	bebSecret := func(name string, labeled bool) *corev1.Secret {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: kymaSystemNamespace}}
+		if labeled {
+			secret.Labels = map[string]string{BEBBackendSecretLabelKey: BEBBackendSecretLabelValue}
+		}
+		return secret
+	}
+	bebRef := &eventingv1alpha1.BEBBackendSpec{SecretName: "referenced", SecretNamespace: kymaSystemNamespace}
+
+	testCases := []struct {
+		name            string
+		givenSpec       eventingv1alpha1.EventingBackendSpec
+		givenSecrets    []client.Object
+		givenKafka      bool
+		wantBackendType eventingv1alpha1.BackendType
+		wantSecretName  string
+		wantReason      eventingv1alpha1.ConditionReason
+	}{
+		{
+			name:            "NATS is selected by default",
+			wantBackendType: eventingv1alpha1.NatsBackendType,
+		},
+		{
+			name:            "BEB is selected by the labeled secret",
+			givenSecrets:    []client.Object{bebSecret("labeled", true)},
+			wantBackendType: eventingv1alpha1.BEBBackendType,
+			wantSecretName:  "labeled",
+		},
+		{
+			name:            "NATS selected by the spec takes precedence over the labeled secret",
+			givenSpec:       eventingv1alpha1.EventingBackendSpec{Type: eventingv1alpha1.NatsBackendType},
+			givenSecrets:    []client.Object{bebSecret("labeled", true)},
+			wantBackendType: eventingv1alpha1.NatsBackendType,
+		},
+		{
+			name:            "NATS selected by the spec returns the referenced secret of the secondary backend",
+			givenSpec:       eventingv1alpha1.EventingBackendSpec{Type: eventingv1alpha1.NatsBackendType, BEB: bebRef},
+			givenSecrets:    []client.Object{bebSecret("referenced", false)},
+			wantBackendType: eventingv1alpha1.NatsBackendType,
+			wantSecretName:  "referenced",
+		},
+		{
+			name:            "BEB selected by the spec without a secret is not ready",
+			givenSpec:       eventingv1alpha1.EventingBackendSpec{Type: eventingv1alpha1.BEBBackendType},
+			wantBackendType: eventingv1alpha1.BEBBackendType,
+			wantReason:      eventingv1alpha1.ConditionReasonBackendSecretNotFound,
+		},
+		{
+			name:            "the referenced secret takes precedence over the labeled secret",
+			givenSpec:       eventingv1alpha1.EventingBackendSpec{BEB: bebRef},
+			givenSecrets:    []client.Object{bebSecret("labeled", true), bebSecret("referenced", false)},
+			wantBackendType: eventingv1alpha1.BEBBackendType,
+			wantSecretName:  "referenced",
+		},
+		{
+			name:            "a missing referenced secret is not ready",
+			givenSpec:       eventingv1alpha1.EventingBackendSpec{BEB: bebRef},
+			givenSecrets:    []client.Object{bebSecret("labeled", true)},
+			wantBackendType: eventingv1alpha1.BEBBackendType,
+			wantReason:      eventingv1alpha1.ConditionReasonBackendSecretNotFound,
+		},
+		{
+			name:            "Kafka selected by the spec takes precedence over the labeled secret",
+			givenSpec:       eventingv1alpha1.EventingBackendSpec{Type: eventingv1alpha1.KafkaBackendType},
+			givenSecrets:    []client.Object{bebSecret("labeled", true)},
+			givenKafka:      true,
+			wantBackendType: eventingv1alpha1.KafkaBackendType,
+		},
+		{
+			name:            "Kafka selected by the spec without a subscription manager is not ready",
+			givenSpec:       eventingv1alpha1.EventingBackendSpec{Type: eventingv1alpha1.KafkaBackendType},
+			wantBackendType: eventingv1alpha1.KafkaBackendType,
+			wantReason:      eventingv1alpha1.ConditionReasonControllerStartFailed,
+		},
+		{
+			name:            "multiple labeled secrets are not ready",
+			givenSecrets:    []client.Object{bebSecret("labeled", true), bebSecret("other", true)},
+			wantBackendType: eventingv1alpha1.BEBBackendType,
+			wantReason:      eventingv1alpha1.ConditionDuplicateSecrets,
+		},
+	}
+
+	for _, tc := range testCases {
+		testCase := tc
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			r := setup(testCase.givenSecrets...)
+			l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+			require.NoError(t, err)
+			r.logger = l
+			if testCase.givenKafka {
+				r.SetKafkaSubscriptionManager(&SubMgrMock{}, nil)
+			}
+
+			// when
+			backendType, secret, reason, err := r.selectBackend(ctx, testCase.givenSpec)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, testCase.wantBackendType, backendType)
+			require.Equal(t, testCase.wantReason, reason)
+			if testCase.wantSecretName == "" {
+				require.Nil(t, secret)
+				return
+			}
+			require.NotNil(t, secret)
+			require.Equal(t, testCase.wantSecretName, secret.Name)
+		})
+	}
+}
+
+func Test_syncNATSConfig(t *testing.T) {
+	// given
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+	r := Reconciler{
+		logger:     l,
+		natsConfig: env.NATSConfig{URL: "nats://env:4222"},
+		envNATSURL: "nats://env:4222",
+	}
+
+	// when
+	err = r.syncNATSConfig(&eventingv1alpha1.NATSBackendSpec{URL: "nats://spec:4222"})
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "nats://spec:4222", r.natsConfig.URL)
+
+	// when the URL is removed from the spec
+	err = r.syncNATSConfig(nil)
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "nats://env:4222", r.natsConfig.URL)
+}
+
+// kafkaSubMgrMock is a SubMgrMock whose Kafka configuration can be replaced.
+type kafkaSubMgrMock struct {
+	SubMgrMock
+	kafkaConfig env.KafkaConfig
+}
+
+func (m *kafkaSubMgrMock) GetKafkaConfig() env.KafkaConfig {
+	return m.kafkaConfig
+}
+
+func (m *kafkaSubMgrMock) SetKafkaConfig(kafkaConfig env.KafkaConfig) {
+	m.kafkaConfig = kafkaConfig
+}
+
+func Test_syncKafkaConfig(t *testing.T) {
+	// given
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+	kafkaSubMgr := &kafkaSubMgrMock{kafkaConfig: env.KafkaConfig{Brokers: []string{"env:9092"}}}
+	r := Reconciler{logger: l}
+	r.SetKafkaSubscriptionManager(kafkaSubMgr, []string{"env:9092"})
+	r.kafkaSubMgrStarted = true
+
+	// when the spec has the same brokers
+	err = r.syncKafkaConfig(&eventingv1alpha1.KafkaBackendSpec{Brokers: []string{"env:9092"}})
+
+	// then the manager keeps running
+	require.NoError(t, err)
+	require.False(t, kafkaSubMgr.StopCalledWithoutCleanup)
+	require.True(t, r.kafkaSubMgrStarted)
+
+	// when the spec has other brokers
+	err = r.syncKafkaConfig(&eventingv1alpha1.KafkaBackendSpec{Brokers: []string{"spec:9092"}})
+
+	// then the manager is stopped without cleanup to be started again with the new brokers
+	require.NoError(t, err)
+	require.Equal(t, []string{"spec:9092"}, kafkaSubMgr.kafkaConfig.Brokers)
+	require.True(t, kafkaSubMgr.StopCalledWithoutCleanup)
+	require.False(t, r.kafkaSubMgrStarted)
+
+	// when the brokers are removed from the spec
+	err = r.syncKafkaConfig(nil)
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, []string{"env:9092"}, kafkaSubMgr.kafkaConfig.Brokers)
+}
+
+func Test_syncSecondaryBackend(t *testing.T) {
+	// given
+	natsSubMgr, bebSubMgr := &SubMgrMock{}, &SubMgrMock{}
+	r := Reconciler{
+		natsSubMgr:        natsSubMgr,
+		natsSubMgrStarted: true,
+		bebSubMgr:         bebSubMgr,
+		bebSubMgrStarted:  true,
+		backendType:       eventingv1alpha1.NatsBackendType,
+	}
+
+	// when
+	err := r.syncSecondaryBackend(eventingv1alpha2.BackendEventMesh)
+
+	// then both managers are stopped without cleanup to be started again with the new selection
+	require.NoError(t, err)
+	require.Equal(t, eventingv1alpha2.BackendEventMesh, r.secondaryBackend)
+	require.True(t, natsSubMgr.StopCalledWithoutCleanup)
+	require.True(t, bebSubMgr.StopCalledWithoutCleanup)
+	require.False(t, r.natsSubMgrStarted)
+	require.False(t, r.bebSubMgrStarted)
+
+	// when the secondary backend is removed
+	natsSubMgr.resetState()
+	bebSubMgr.resetState()
+	r.natsSubMgrStarted, r.bebSubMgrStarted = true, true
+	err = r.syncSecondaryBackend("")
+
+	// then the BEB manager is left running to be stopped with cleanup
+	require.NoError(t, err)
+	require.True(t, natsSubMgr.StopCalledWithoutCleanup)
+	require.False(t, bebSubMgr.StopCalledWithoutCleanup)
+	require.True(t, r.bebSubMgrStarted)
+}
+
+func Test_pauseNATSController(t *testing.T) {
+	// given
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+	natsSubMgr := &pausableSubMgrMock{}
+	r := Reconciler{
+		natsSubMgr:        natsSubMgr,
+		natsSubMgrStarted: true,
+		logger:            l,
+	}
+
+	// when
+	err = r.pauseNATSController()
+
+	// then the dispatch is paused without stopping the manager
+	require.NoError(t, err)
+	require.True(t, natsSubMgr.PauseCalled)
+	require.False(t, natsSubMgr.StopCalledWithCleanup)
+	require.False(t, natsSubMgr.StopCalledWithoutCleanup)
+	require.False(t, r.natsSubMgrStarted)
+	require.True(t, r.natsSubMgrPaused)
+
+	// when the dispatch is resumed
+	err = r.startNATSController()
+
+	// then
+	require.NoError(t, err)
+	require.True(t, natsSubMgr.StartCalled)
+	require.True(t, r.natsSubMgrStarted)
+	require.False(t, r.natsSubMgrPaused)
+
+	// when a subscription manager which cannot pause is paused
+	plainSubMgr := &SubMgrMock{}
+	r.natsSubMgr = plainSubMgr
+	err = r.pauseNATSController()
+
+	// then it is stopped without cleanup, and stopping it later cleans up
+	require.NoError(t, err)
+	require.True(t, plainSubMgr.StopCalledWithoutCleanup)
+	require.NoError(t, r.stopNATSController())
+	require.True(t, plainSubMgr.StopCalledWithCleanup)
+	require.False(t, r.natsSubMgrPaused)
+}
+
+func Test_pauseKafkaController(t *testing.T) {
+	// given
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+	kafkaSubMgr := &SubMgrMock{}
+	r := Reconciler{
+		kafkaSubMgr:        kafkaSubMgr,
+		kafkaSubMgrStarted: true,
+		logger:             l,
+	}
+
+	// when
+	err = r.pauseKafkaController()
+
+	// then the manager is stopped without deleting the consumer groups
+	require.NoError(t, err)
+	require.True(t, kafkaSubMgr.StopCalledWithoutCleanup)
+	require.False(t, kafkaSubMgr.StopCalledWithCleanup)
+	require.False(t, r.kafkaSubMgrStarted)
+	require.True(t, r.kafkaSubMgrPaused)
+
+	// when the dispatch is resumed
+	err = r.startKafkaController()
+
+	// then
+	require.NoError(t, err)
+	require.True(t, kafkaSubMgr.StartCalled)
+	require.True(t, r.kafkaSubMgrStarted)
+	require.False(t, r.kafkaSubMgrPaused)
+
+	// when the manager is paused and then stopped for a backend switch
+	require.NoError(t, r.pauseKafkaController())
+	err = r.stopKafkaController()
+
+	// then the consumer groups are deleted
+	require.NoError(t, err)
+	require.True(t, kafkaSubMgr.StopCalledWithCleanup)
+	require.False(t, r.kafkaSubMgrPaused)
+}
+
+func Test_ReconcileSecondaryShard(t *testing.T) {
+	// given
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, eventingv1alpha1.AddToScheme(scheme))
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+	natsSubMgr := &SubMgrMock{}
+	r := Reconciler{
+		Client:     fake.NewClientBuilder().WithScheme(scheme).Build(),
+		cfg:        getTestBackendConfig(),
+		logger:     l,
+		natsSubMgr: natsSubMgr,
+		bebSubMgr:  &SubMgrMock{},
+	}
+	shard, err := sharding.New(1, 2)
+	require.NoError(t, err)
+	r.SetShard(shard)
+
+	// when
+	_, err = r.Reconcile(context.Background(), ctrl.Request{})
+
+	// then the subscription manager of the shard is started
+	require.NoError(t, err)
+	require.True(t, natsSubMgr.StartCalled)
+
+	// and the cluster-wide resources are left to the primary shard
+	backends := &eventingv1alpha1.EventingBackendList{}
+	require.NoError(t, r.List(context.Background(), backends))
+	require.Empty(t, backends.Items)
+	publishers := &appsv1.DeploymentList{}
+	require.NoError(t, r.List(context.Background(), publishers))
+	require.Empty(t, publishers.Items)
+}
+
+// pausableSubMgrMock is a subscription manager which can pause the dispatch.
+type pausableSubMgrMock struct {
+	SubMgrMock
+	PauseCalled bool
+}
+
+func (m *pausableSubMgrMock) Pause() error {
+	m.PauseCalled = true
+	return nil
+}
+
+func Test_summarizeSubscriptions(t *testing.T) {
+	// given
+	now := time.Now()
+	newSub := func(name string, ready bool, conditions ...eventingv1alpha2.Condition) eventingv1alpha2.Subscription {
+		sub := eventingv1alpha2.Subscription{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"}}
+		sub.Status.Ready = ready
+		sub.Status.Conditions = conditions
+		return sub
+	}
+	newCondition := func(status corev1.ConditionStatus, age time.Duration, reason, message string) eventingv1alpha2.Condition {
+		return eventingv1alpha2.Condition{
+			Status:             status,
+			LastTransitionTime: metav1.NewTime(now.Add(-age)),
+			Reason:             eventingv1alpha2.ConditionReason(reason),
+			Message:            message,
+		}
+	}
+	subscriptions := []eventingv1alpha2.Subscription{
+		newSub("ready", true, newCondition(corev1.ConditionTrue, time.Minute, "Sink reachable", "")),
+		newSub("old-error", false, newCondition(corev1.ConditionFalse, time.Hour, "Sink not reachable", "dial failed")),
+		newSub("new-error", false,
+			newCondition(corev1.ConditionTrue, time.Second, "Sink reachable", ""),
+			newCondition(corev1.ConditionFalse, time.Minute, "NATS Subscription not active", "")),
+	}
+
+	// when
+	summary, recentErrors := summarizeSubscriptions(subscriptions)
+
+	// then
+	require.Equal(t, eventingv1alpha1.SubscriptionSummary{Total: 3, Ready: 1, NotReady: 2}, summary)
+	require.Equal(t, []string{
+		"shop/new-error: NATS Subscription not active",
+		"shop/old-error: dial failed",
+	}, recentErrors)
+}
+
+func Test_syncSummary(t *testing.T) {
+	// given
+	scheme := runtime.NewScheme()
+	require.NoError(t, eventingv1alpha2.AddToScheme(scheme))
+	sub := &eventingv1alpha2.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"}}
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+	usage := &eventingv1alpha1.StreamUsage{Name: "kyma", Messages: 10, Bytes: 100, Consumers: 1}
+	r := Reconciler{
+		Client:     fake.NewClientBuilder().WithScheme(scheme).WithObjects(sub).Build(),
+		logger:     l,
+		natsSubMgr: &streamUsageSubMgrMock{usage: usage},
+	}
+	now := time.Now()
+	backendStatus := &eventingv1alpha1.EventingBackendStatus{Backend: eventingv1alpha1.NatsBackendType}
+
+	// when
+	r.syncSummary(context.Background(), nil, backendStatus, now)
+
+	// then
+	require.NotNil(t, backendStatus.Summary)
+	require.Equal(t, eventingv1alpha1.SubscriptionSummary{Total: 1, NotReady: 1}, backendStatus.Summary.Subscriptions)
+	require.Equal(t, usage, backendStatus.Summary.Stream)
+
+	// when the current summary is not older than the summary interval
+	currentSummary := backendStatus.Summary
+	r.syncSummary(context.Background(), currentSummary, backendStatus, now.Add(summaryInterval/2))
+
+	// then it is kept
+	require.Equal(t, currentSummary, backendStatus.Summary)
+
+	// when the current summary is older than the summary interval
+	later := now.Add(summaryInterval)
+	r.syncSummary(context.Background(), currentSummary, backendStatus, later)
+
+	// then it is updated
+	require.Equal(t, later.Unix(), backendStatus.Summary.LastUpdateTime.Unix())
+}
+
+// streamUsageSubMgrMock is a subscription manager which reports a fixed stream usage.
+type streamUsageSubMgrMock struct {
+	SubMgrMock
+	usage *eventingv1alpha1.StreamUsage
+}
+
+func (m *streamUsageSubMgrMock) StreamUsage() (*eventingv1alpha1.StreamUsage, error) {
+	return m.usage, nil
+}
+
 func setup(objs ...client.Object) Reconciler {
 	fakeClient := fake.NewClientBuilder().WithObjects(objs...).Build()
 	return Reconciler{
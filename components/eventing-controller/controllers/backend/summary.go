@@ -0,0 +1,96 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+)
+
+const (
+	// summaryInterval is the minimum interval between two updates of the EventingBackend summary.
+	// It prevents the status updates of a busy stream from triggering reconciliations in a loop.
+	summaryInterval = 30 * time.Second
+
+	// maxRecentErrors is the maximum number of Subscription errors reported in the EventingBackend summary.
+	maxRecentErrors = 5
+)
+
+// streamUsageGetter is implemented by subscription managers which can report the usage of their stream.
+type streamUsageGetter interface {
+	StreamUsage() (*eventingv1alpha1.StreamUsage, error)
+}
+
+// syncSummary sets the summary of the given status. The current summary is kept if it is not older than
+// the summary interval.
+func (r *Reconciler) syncSummary(ctx context.Context, currentSummary *eventingv1alpha1.EventingSummary,
+	backendStatus *eventingv1alpha1.EventingBackendStatus, now time.Time) {
+	if currentSummary != nil && now.Sub(currentSummary.LastUpdateTime.Time) < summaryInterval {
+		backendStatus.Summary = currentSummary.DeepCopy()
+		return
+	}
+
+	summary := &eventingv1alpha1.EventingSummary{LastUpdateTime: metav1.NewTime(now)}
+	subscriptions := &eventingv1alpha2.SubscriptionList{}
+	if err := r.List(ctx, subscriptions); err != nil {
+		r.namedLogger().Warnw("Failed to list subscriptions for the EventingBackend summary", "error", err)
+	} else {
+		summary.Subscriptions, summary.RecentErrors = summarizeSubscriptions(subscriptions.Items)
+	}
+
+	if getter, ok := r.natsSubMgr.(streamUsageGetter); ok && backendStatus.Backend == eventingv1alpha1.NatsBackendType {
+		usage, err := getter.StreamUsage()
+		if err != nil {
+			r.namedLogger().Warnw("Failed to get the stream usage for the EventingBackend summary", "error", err)
+		}
+		summary.Stream = usage
+	}
+	backendStatus.Summary = summary
+}
+
+// summarizeSubscriptions returns the number of Subscriptions by readiness and the latest errors
+// of the Subscriptions which are not ready.
+func summarizeSubscriptions(subscriptions []eventingv1alpha2.Subscription) (
+	eventingv1alpha1.SubscriptionSummary, []string) {
+	summary := eventingv1alpha1.SubscriptionSummary{Total: len(subscriptions)}
+	type subscriptionError struct {
+		time    time.Time
+		message string
+	}
+	var subErrors []subscriptionError
+	for _, sub := range subscriptions {
+		if sub.Status.Ready {
+			summary.Ready++
+			continue
+		}
+		summary.NotReady++
+		for _, condition := range sub.Status.Conditions {
+			if condition.Status != v1.ConditionFalse {
+				continue
+			}
+			message := condition.Message
+			if message == "" {
+				message = string(condition.Reason)
+			}
+			subErrors = append(subErrors, subscriptionError{
+				time:    condition.LastTransitionTime.Time,
+				message: fmt.Sprintf("%s/%s: %s", sub.Namespace, sub.Name, message),
+			})
+		}
+	}
+
+	sort.SliceStable(subErrors, func(i, j int) bool {
+		return subErrors[i].time.After(subErrors[j].time)
+	})
+	var recentErrors []string
+	for i := 0; i < len(subErrors) && i < maxRecentErrors; i++ {
+		recentErrors = append(recentErrors, subErrors[i].message)
+	}
+	return summary, recentErrors
+}
@@ -20,6 +20,20 @@ const (
 	ReasonUpdateFailed reason = "UpdateFailed"
 	// ReasonValidationFailed is used when an object validation fails.
 	ReasonValidationFailed reason = "ValidationFailed"
+	// ReasonDispatchFailed is used when dispatching events to a sink starts failing.
+	ReasonDispatchFailed reason = "DispatchFailed"
+	// ReasonDispatchRetriesExhausted is used when an event is dropped after all delivery attempts failed.
+	ReasonDispatchRetriesExhausted reason = "DispatchRetriesExhausted"
+	// ReasonCleanupFailed is used when the backend resources of a deleted object cannot be cleaned up.
+	ReasonCleanupFailed reason = "CleanupFailed"
+	// ReasonForceDeleted is used when the finalizer of an object is removed without a successful cleanup.
+	ReasonForceDeleted reason = "ForceDeleted"
+	// ReasonExpired is used when the delivery of an expired object is stopped.
+	ReasonExpired reason = "Expired"
+	// ReasonUnknownEventType is used when an object refers to event types missing in the event type catalog.
+	ReasonUnknownEventType reason = "UnknownEventType"
+	// ReasonSinkNotReady is used when the delivery to a sink is held back until the sink is ready.
+	ReasonSinkNotReady reason = "SinkNotReady"
 )
 
 // Normal records a normal event for an API object.
@@ -0,0 +1,132 @@
+package eventtype
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+)
+
+// catalog is the catalog of the event types of a Namespace, aggregated from its EventTypes.
+type catalog struct {
+	// ready are the EventTypes of the catalog, which are valid and not duplicated.
+	ready []*eventingv1alpha1.EventType
+	// status is the desired status of each EventType by name.
+	status map[string]*eventingv1alpha1.EventTypeStatus
+}
+
+// catalogKey identifies an event type in a catalog.
+type catalogKey struct {
+	eventType string
+	source    string
+}
+
+// newCatalog returns the catalog of the given EventTypes of a Namespace. Of the EventTypes with the same type and
+// source, only the oldest one is registered in the catalog.
+func newCatalog(eventTypes []eventingv1alpha1.EventType) *catalog {
+	sorted := make([]*eventingv1alpha1.EventType, 0, len(eventTypes))
+	for i := range eventTypes {
+		sorted = append(sorted, &eventTypes[i])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, tj := sorted[i].CreationTimestamp, sorted[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	c := &catalog{status: make(map[string]*eventingv1alpha1.EventTypeStatus, len(sorted))}
+	registered := make(map[catalogKey]string, len(sorted))
+	for _, eventType := range sorted {
+		condition := eventingv1alpha1.MakeCondition(eventingv1alpha1.ConditionEventTypeRegistered,
+			eventingv1alpha1.ConditionReasonEventTypeRegistered, corev1.ConditionTrue, "")
+		key := catalogKey{eventType: eventType.Spec.Type, source: eventType.Spec.Source}
+		if message := validate(eventType); message != "" {
+			condition = eventingv1alpha1.MakeCondition(eventingv1alpha1.ConditionEventTypeRegistered,
+				eventingv1alpha1.ConditionReasonEventTypeInvalid, corev1.ConditionFalse, message)
+		} else if first, ok := registered[key]; ok {
+			condition = eventingv1alpha1.MakeCondition(eventingv1alpha1.ConditionEventTypeRegistered,
+				eventingv1alpha1.ConditionReasonEventTypeDuplicate, corev1.ConditionFalse,
+				"the event type is already registered by the EventType "+first)
+		} else {
+			registered[key] = eventType.Name
+			c.ready = append(c.ready, eventType)
+		}
+		c.status[eventType.Name] = &eventingv1alpha1.EventTypeStatus{
+			Conditions: []eventingv1alpha1.Condition{condition},
+			Ready:      condition.Status == corev1.ConditionTrue,
+		}
+	}
+	return c
+}
+
+// validate returns why the given EventType is invalid, or an empty string if it is valid.
+func validate(eventType *eventingv1alpha1.EventType) string {
+	if eventingv1alpha2.IsWildcardType(eventType.Spec.Type) {
+		return "the type must not contain wildcards"
+	}
+	if ref := eventType.Spec.SchemaRef; ref != "" {
+		if schemaURL, err := url.Parse(ref); err != nil || !schemaURL.IsAbs() {
+			return "the schemaRef must be an absolute URI"
+		}
+	}
+	return ""
+}
+
+// subscribe adds the given Subscription to the status of the EventTypes it subscribes to, and returns its types
+// which match no EventType of the catalog.
+func (c *catalog) subscribe(subscription *eventingv1alpha2.Subscription) []string {
+	var unknown []string
+	for _, subscribedType := range subscription.Spec.Types {
+		matched := false
+		for _, eventType := range c.ready {
+			if !matches(subscription, subscribedType, eventType) {
+				continue
+			}
+			matched = true
+			status := c.status[eventType.Name]
+			if n := len(status.Subscriptions); n == 0 || status.Subscriptions[n-1] != subscription.Name {
+				status.Subscriptions = append(status.Subscriptions, subscription.Name)
+			}
+		}
+		if !matched {
+			unknown = append(unknown, subscribedType)
+		}
+	}
+	return unknown
+}
+
+// matches returns true if the given type of the Subscription matches the EventType. With the standard type
+// matching, the source of the Subscription must match as well.
+func matches(subscription *eventingv1alpha2.Subscription, subscribedType string,
+	eventType *eventingv1alpha1.EventType) bool {
+	if subscription.Spec.TypeMatching != eventingv1alpha2.TypeMatchingExact &&
+		subscription.Spec.Source != eventType.Spec.Source {
+		return false
+	}
+	if eventingv1alpha2.IsWildcardType(subscribedType) {
+		return matchesWildcard(subscribedType, eventType.Spec.Type)
+	}
+	return subscribedType == eventType.Spec.Type
+}
+
+// matchesWildcard returns true if the given wildcard type matches the event type. A `*` segment matches any single
+// segment, and a `>` last segment matches one or more segments.
+func matchesWildcard(wildcardType, eventType string) bool {
+	patterns := strings.Split(wildcardType, ".")
+	segments := strings.Split(eventType, ".")
+	for i, pattern := range patterns {
+		if pattern == eventingv1alpha2.TypeWildcardTailToken {
+			return len(segments) > i
+		}
+		if i >= len(segments) || (pattern != eventingv1alpha2.TypeWildcardToken && pattern != segments[i]) {
+			return false
+		}
+	}
+	return len(segments) == len(patterns)
+}
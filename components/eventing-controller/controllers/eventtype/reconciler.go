@@ -0,0 +1,130 @@
+// Package eventtype aggregates the EventTypes of each Namespace to its event type catalog, and validates the
+// Subscriptions of the Namespace against it.
+package eventtype
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/controllers/events"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+const reconcilerName = "event-type-reconciler"
+
+// Reconciler reconciles the event type catalog of a Namespace. The requests only have the Namespace set.
+type Reconciler struct {
+	client.Client
+	logger   *logger.Logger
+	recorder record.EventRecorder
+}
+
+func NewReconciler(client client.Client, logger *logger.Logger, recorder record.EventRecorder) *Reconciler {
+	return &Reconciler{
+		Client:   client,
+		logger:   logger,
+		recorder: recorder,
+	}
+}
+
+// +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=eventtypes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=eventtypes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=subscriptions,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	eventTypes := &eventingv1alpha1.EventTypeList{}
+	if err := r.List(ctx, eventTypes, client.InNamespace(req.Namespace)); err != nil {
+		return ctrl.Result{}, xerrors.Errorf("failed to list event types: %v", err)
+	}
+	// the Subscriptions of Namespaces without a catalog are not validated
+	if len(eventTypes.Items) == 0 {
+		return ctrl.Result{}, nil
+	}
+	subscriptions := &eventingv1alpha2.SubscriptionList{}
+	if err := r.List(ctx, subscriptions, client.InNamespace(req.Namespace)); err != nil {
+		return ctrl.Result{}, xerrors.Errorf("failed to list subscriptions: %v", err)
+	}
+
+	c := newCatalog(eventTypes.Items)
+	for i := range subscriptions.Items {
+		subscription := &subscriptions.Items[i]
+		if !subscription.DeletionTimestamp.IsZero() {
+			continue
+		}
+		unknown := c.subscribe(subscription)
+		if len(unknown) > 0 && len(c.ready) > 0 {
+			events.Warn(r.recorder, subscription, events.ReasonUnknownEventType,
+				"The event types %s are not registered in the event type catalog of the namespace",
+				strings.Join(unknown, ", "))
+		}
+	}
+
+	var updateErrs []error
+	for i := range eventTypes.Items {
+		if err := r.updateStatus(ctx, &eventTypes.Items[i], c.status[eventTypes.Items[i].Name]); err != nil {
+			updateErrs = append(updateErrs, err)
+		}
+	}
+	if len(updateErrs) > 0 {
+		return ctrl.Result{}, xerrors.Errorf("%d event type status update(s) failed, first error: %v",
+			len(updateErrs), updateErrs[0])
+	}
+	return ctrl.Result{}, nil
+}
+
+// updateStatus updates the EventType status if it changed.
+func (r *Reconciler) updateStatus(ctx context.Context, eventType *eventingv1alpha1.EventType,
+	status *eventingv1alpha1.EventTypeStatus) error {
+	desired := eventType.DeepCopy()
+	desired.Status = *status
+	sort.Strings(desired.Status.Subscriptions)
+	if len(eventType.Status.Conditions) == 1 &&
+		eventingv1alpha1.ConditionEquals(eventType.Status.Conditions[0], status.Conditions[0]) {
+		desired.Status.Conditions = eventType.Status.Conditions
+	}
+	if reflect.DeepEqual(eventType.Status, desired.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, desired); err != nil {
+		return xerrors.Errorf("failed to update the status of the event type %s: %v", eventType.Name, err)
+	}
+	r.namedLogger().Debugw("Updated event type status", "namespace", eventType.Namespace, "name", eventType.Name)
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager. Only the changes of the specs trigger a reconciliation,
+// so that the status updates of the Subscriptions do not.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(reconcilerName).
+		Watches(&eventingv1alpha1.EventType{}, handler.EnqueueRequestsFromMapFunc(mapToNamespace),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&eventingv1alpha2.Subscription{}, handler.EnqueueRequestsFromMapFunc(mapToNamespace),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// mapToNamespace enqueues the catalog of the Namespace of the given object.
+func mapToNamespace(_ context.Context, obj client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace()}}}
+}
+
+func (r *Reconciler) namedLogger() *zap.SugaredLogger {
+	return r.logger.WithContext().Named(reconcilerName)
+}
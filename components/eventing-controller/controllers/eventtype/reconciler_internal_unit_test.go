@@ -0,0 +1,155 @@
+package eventtype
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+const namespace = "shop"
+
+func Test_newCatalog(t *testing.T) {
+	// given
+	created := newEventType("order-created", "order.created.v1", "commerce", 0)
+	duplicate := newEventType("order-created-copy", "order.created.v1", "commerce", time.Minute)
+	otherSource := newEventType("order-created-crm", "order.created.v1", "crm", time.Minute)
+	invalidSchema := newEventType("invalid-schema", "order.updated.v1", "commerce", 0)
+	invalidSchema.Spec.SchemaRef = "order.updated.json"
+	wildcard := newEventType("wildcard", "order.*.v1", "commerce", 0)
+
+	// when
+	c := newCatalog([]eventingv1alpha1.EventType{*duplicate, *invalidSchema, *created, *otherSource, *wildcard})
+
+	// then
+	require.Len(t, c.ready, 2)
+	require.Equal(t, created.Name, c.ready[0].Name)
+	require.Equal(t, otherSource.Name, c.ready[1].Name)
+	wantReasons := map[string]eventingv1alpha1.ConditionReason{
+		created.Name:       eventingv1alpha1.ConditionReasonEventTypeRegistered,
+		duplicate.Name:     eventingv1alpha1.ConditionReasonEventTypeDuplicate,
+		otherSource.Name:   eventingv1alpha1.ConditionReasonEventTypeRegistered,
+		invalidSchema.Name: eventingv1alpha1.ConditionReasonEventTypeInvalid,
+		wildcard.Name:      eventingv1alpha1.ConditionReasonEventTypeInvalid,
+	}
+	for name, wantReason := range wantReasons {
+		status := c.status[name]
+		require.Len(t, status.Conditions, 1, name)
+		require.Equal(t, wantReason, status.Conditions[0].Reason, name)
+		require.Equal(t, wantReason == eventingv1alpha1.ConditionReasonEventTypeRegistered, status.Ready, name)
+	}
+	require.Contains(t, c.status[duplicate.Name].Conditions[0].Message, created.Name)
+}
+
+func Test_matchesWildcard(t *testing.T) {
+	testCases := []struct {
+		wildcardType string
+		eventType    string
+		want         bool
+	}{
+		{wildcardType: "order.*.v1", eventType: "order.created.v1", want: true},
+		{wildcardType: "order.*.v1", eventType: "order.created.v2"},
+		{wildcardType: "order.*.v1", eventType: "order.created.eu.v1"},
+		{wildcardType: "order.>", eventType: "order.created.eu.v1", want: true},
+		{wildcardType: "order.>", eventType: "order"},
+		{wildcardType: "order.*", eventType: "order"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.wildcardType+" "+tc.eventType, func(t *testing.T) {
+			require.Equal(t, tc.want, matchesWildcard(tc.wildcardType, tc.eventType))
+		})
+	}
+}
+
+func Test_Reconcile(t *testing.T) {
+	// given
+	ctx := context.Background()
+	created := newEventType("order-created", "order.created.v1", "commerce", 0)
+	updated := newEventType("order-updated", "order.updated.v1", "commerce", 0)
+	duplicate := newEventType("order-created-copy", "order.created.v1", "commerce", time.Minute)
+	standard := newSubscription("standard", eventingtesting.WithTypeMatchingStandard(),
+		eventingtesting.WithTypes([]string{"order.created.v1"}))
+	exact := newSubscription("exact", eventingtesting.WithExactTypeMatching(),
+		eventingtesting.WithTypes([]string{"order.updated.v1"}))
+	wildcard := newSubscription("wildcard", eventingtesting.WithExactTypeMatching(),
+		eventingtesting.WithTypes([]string{"order.*.v1"}))
+	unknown := newSubscription("unknown", eventingtesting.WithTypeMatchingStandard(),
+		eventingtesting.WithTypes([]string{"order.created.v1", "order.deleted.v1"}))
+	otherNamespace := eventingtesting.NewSubscription("other", "other",
+		eventingtesting.WithTypes([]string{"order.deleted.v1"}))
+	recorder := record.NewFakeRecorder(10)
+	r := newTestReconciler(t, recorder, created, updated, duplicate, standard, exact, wildcard, unknown, otherNamespace)
+
+	// when
+	for _, ns := range []string{namespace, "other"} {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns}})
+		require.NoError(t, err)
+	}
+
+	// then the statuses list the subscribing Subscriptions
+	wantSubscriptions := map[string][]string{
+		created.Name:   {"standard", "unknown", "wildcard"},
+		updated.Name:   {"exact", "wildcard"},
+		duplicate.Name: nil,
+	}
+	for name, want := range wantSubscriptions {
+		eventType := &eventingv1alpha1.EventType{}
+		require.NoError(t, r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, eventType))
+		require.Equal(t, want, eventType.Status.Subscriptions, name)
+		require.Equal(t, name != duplicate.Name, eventType.Status.Ready, name)
+	}
+
+	// and only the Subscription with an unknown type of a Namespace with a catalog is warned about
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, "UnknownEventType The event types order.deleted.v1 are not registered")
+}
+
+func newEventType(name, eventType, source string, age time.Duration) *eventingv1alpha1.EventType {
+	return &eventingv1alpha1.EventType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Add(age)),
+		},
+		Spec: eventingv1alpha1.EventTypeSpec{Type: eventType, Source: source},
+	}
+}
+
+func newSubscription(name string, opts ...eventingtesting.SubscriptionOpt) *eventingv1alpha2.Subscription {
+	return eventingtesting.NewSubscription(name, namespace,
+		append([]eventingtesting.SubscriptionOpt{eventingtesting.WithSource("commerce")}, opts...)...)
+}
+
+func newTestReconciler(t *testing.T, recorder record.EventRecorder, objs ...client.Object) *Reconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, eventingv1alpha1.AddToScheme(scheme))
+	require.NoError(t, eventingv1alpha2.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&eventingv1alpha1.EventType{}).
+		Build()
+
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+
+	return NewReconciler(fakeClient, l, recorder)
+}
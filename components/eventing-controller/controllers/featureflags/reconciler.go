@@ -0,0 +1,90 @@
+package featureflags
+
+import (
+	"context"
+	"reflect"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/internal/featureflags"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+)
+
+const reconcilerName = "feature-flags-reconciler"
+
+// Reconciler applies the experimental feature flags of a ConfigMap and exports their states as metrics.
+type Reconciler struct {
+	client.Client
+	configMap        types.NamespacedName
+	logger           *logger.Logger
+	metricsCollector *backendmetrics.Collector
+}
+
+func NewReconciler(client client.Client, configMap types.NamespacedName, logger *logger.Logger,
+	metricsCollector *backendmetrics.Collector) *Reconciler {
+	return &Reconciler{
+		Client:           client,
+		configMap:        configMap,
+		logger:           logger,
+		metricsCollector: metricsCollector,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if req.NamespacedName != r.configMap {
+		return ctrl.Result{}, nil
+	}
+	data := map[string]string{}
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, req.NamespacedName, configMap); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, err
+	} else if err == nil {
+		data = configMap.Data
+	}
+
+	// the invalid flags are disabled and reported, retrying would not help before the ConfigMap changes
+	enabled, err := featureflags.Parse(data)
+	if err != nil {
+		r.namedLogger().Errorw("Invalid feature flags", "configmap", r.configMap.String(), "error", err)
+	}
+	previous := featureflags.States()
+	featureflags.SetEnabledFlags(enabled)
+	r.recordStates()
+	if current := featureflags.States(); !reflect.DeepEqual(previous, current) {
+		r.namedLogger().Infow("Applied the feature flags", "configmap", r.configMap.String(), "flags", current)
+	}
+	return ctrl.Result{}, nil
+}
+
+// recordStates records the current states of the experimental feature flags in the feature flag metric.
+func (r *Reconciler) recordStates() {
+	states := map[string]bool{}
+	for flag, enabled := range featureflags.States() {
+		states[string(flag)] = enabled
+	}
+	r.metricsCollector.RecordFeatureFlags(states)
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the states are exported before the ConfigMap is reconciled, because it might not exist
+	r.recordStates()
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(reconcilerName).
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return object.GetNamespace() == r.configMap.Namespace && object.GetName() == r.configMap.Name
+		}))).
+		Complete(r)
+}
+
+func (r *Reconciler) namedLogger() *zap.SugaredLogger {
+	return r.logger.WithContext().Named(reconcilerName)
+}
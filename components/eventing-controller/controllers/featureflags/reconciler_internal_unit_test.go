@@ -0,0 +1,87 @@
+package featureflags
+
+import (
+	"context"
+	"testing"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/internal/featureflags"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+)
+
+//nolint:gochecknoglobals // test fixture
+var configMapName = types.NamespacedName{Namespace: "kyma-system", Name: "eventing-feature-flags"}
+
+func Test_Reconcile(t *testing.T) {
+	defer featureflags.SetEnabledFlags(nil)
+	ctx := context.Background()
+
+	// given
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: configMapName.Namespace, Name: configMapName.Name},
+		Data: map[string]string{
+			string(featureflags.PullConsumers): "true",
+			string(featureflags.BatchDispatch): "maybe",
+			"unknown":                          "true",
+		},
+	}
+	r := newTestReconciler(t, configMap)
+
+	// when
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: configMapName})
+
+	// then the invalid flags are disabled
+	require.NoError(t, err)
+	require.Equal(t, map[featureflags.Flag]bool{
+		featureflags.PullConsumers: true,
+		featureflags.BatchDispatch: false,
+		featureflags.DualPublish:   false,
+	}, featureflags.States())
+
+	// when the ConfigMap is deleted
+	require.NoError(t, r.Delete(ctx, configMap))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: configMapName})
+
+	// then all flags are disabled
+	require.NoError(t, err)
+	require.False(t, featureflags.IsEnabled(featureflags.PullConsumers))
+}
+
+func Test_Reconcile_OtherConfigMap(t *testing.T) {
+	defer featureflags.SetEnabledFlags(nil)
+
+	// given
+	featureflags.SetEnabledFlags(map[featureflags.Flag]bool{featureflags.DualPublish: true})
+	r := newTestReconciler(t)
+
+	// when
+	_, err := r.Reconcile(context.Background(),
+		ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: configMapName.Name}})
+
+	// then the flags are left untouched
+	require.NoError(t, err)
+	require.True(t, featureflags.IsEnabled(featureflags.DualPublish))
+}
+
+func newTestReconciler(t *testing.T, objs ...client.Object) *Reconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+
+	return NewReconciler(fakeClient, configMapName, l, backendmetrics.NewCollector())
+}
@@ -43,6 +43,7 @@ import (
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
 	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
 )
 
 type syncConditionWebhookCallStatusFunc func(subscription *eventingv1alpha2.Subscription)
@@ -62,6 +63,9 @@ type Reconciler struct {
 	sinkValidator                  sink.Validator
 	collector                      *metrics.Collector
 	syncConditionWebhookCallStatus syncConditionWebhookCallStatusFunc
+	shard                          sharding.Shard
+	controllerOptions              controller.Options
+	secondaryBackend               string
 }
 
 const (
@@ -100,6 +104,17 @@ func NewReconciler(ctx context.Context, client client.Client, logger *logger.Log
 	}
 }
 
+// SetShard restricts the reconciler to the Subscriptions which belong to the given shard.
+func (r *Reconciler) SetShard(shard sharding.Shard) {
+	r.shard = shard
+}
+
+// SetSecondaryBackend sets the backend which runs next to the primary one. The Subscriptions which are
+// reconciled by the other backend are skipped.
+func (r *Reconciler) SetSecondaryBackend(secondaryBackend string) {
+	r.secondaryBackend = secondaryBackend
+}
+
 // +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=subscriptions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=subscriptions/status,verbs=get;update;patch
 // Generate required RBAC to emit kubernetes events in the controller.
@@ -107,6 +122,11 @@ func NewReconciler(ctx context.Context, client client.Client, logger *logger.Log
 // +kubebuilder:rbac:groups=gateway.kyma-project.io,resources=apirules,verbs=get;list;watch;create;update;patch;delete
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// skip the subscriptions which are reconciled by another controller replica
+	if !r.shard.Owns(req.Namespace, req.Name) {
+		return ctrl.Result{}, nil
+	}
+
 	// fetch current subscription object and ensure the object was not deleted in the meantime
 	currentSubscription := &eventingv1alpha2.Subscription{}
 	if err := r.Client.Get(ctx, req.NamespacedName, currentSubscription); err != nil {
@@ -120,6 +140,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	log := backendutils.LoggerWithSubscription(r.namedLogger(), sub)
 	log.Debugw("Received new reconcile request")
 
+	// skip the subscriptions which are reconciled by the other backend,
+	// but delete the EventMesh subscription created before the subscription selected the other backend
+	if !sub.IsReconciledBy(eventingv1alpha2.BackendEventMesh, r.secondaryBackend) {
+		log.Debugw("Skipping subscription reconciled by the other backend")
+		if sub.Status.Backend.Ev2hash == 0 {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, r.Backend.DeleteSubscription(sub)
+	}
+
 	// instantiate a return object
 	result := ctrl.Result{}
 
@@ -135,10 +165,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		"",
 		"",
 	)
+	defer func() {
+		r.collector.RecordSubscriptionReady(backendType, sub.Namespace, sub.Name, sub.Status.Ready)
+	}()
+
+	// stop the delivery of expired Subscriptions
+	if sub.IsExpired(time.Now()) {
+		return r.handleSubscriptionExpiry(ctx, sub, log)
+	}
 
 	// sync the initial Subscription status
 	r.syncInitialStatus(sub)
 
+	// resolve the sinkRef to the sink URL used by the backend
+	sub.ResolveSink()
+
 	// sync Finalizers, ensure the finalizer is set
 	if err := r.syncFinalizer(sub, log); err != nil {
 		if updateErr := r.updateSubscription(ctx, sub, log); updateErr != nil {
@@ -159,7 +200,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	// sync the EventMesh Subscription with the Subscription CR
+	syncStart := time.Now()
 	ready, err := r.syncEventMeshSubscription(sub, apiRule, log)
+	r.collector.RecordReconcilePhaseDuration(backendType, metrics.PhaseBackendSync, time.Since(syncStart))
 	if err != nil {
 		if updateErr := r.updateSubscription(ctx, sub, log); updateErr != nil {
 			return ctrl.Result{}, xerrors.Errorf(updateErr.Error()+": %v", err)
@@ -177,9 +220,38 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	result.RequeueAfter = sub.RequeueUntilExpiry(result.RequeueAfter, time.Now())
 	return result, nil
 }
 
+// handleSubscriptionExpiry stops the delivery of an expired Subscription by deleting its EventMesh subscription
+// and marks it as expired. The Subscription itself is deleted instead if it requests so.
+func (r *Reconciler) handleSubscriptionExpiry(ctx context.Context, sub *eventingv1alpha2.Subscription,
+	log *zap.SugaredLogger) (ctrl.Result, error) {
+	if sub.Spec.DeleteOnExpiry {
+		if err := r.Delete(ctx, sub); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, xerrors.Errorf("failed to delete expired subscription: %v", err)
+		}
+		log.Infow("Deleted expired subscription")
+		return ctrl.Result{}, nil
+	}
+
+	// the EventMesh subscription is deleted only once, when the Subscription expires
+	if sub.Status.FindCondition(eventingv1alpha2.ConditionExpired) == nil {
+		if err := r.Backend.DeleteSubscription(sub); err != nil {
+			return ctrl.Result{}, xerrors.Errorf("failed to delete EventMesh subscription: %v", err)
+		}
+		log.Infow("Stopped the delivery of expired subscription")
+	}
+
+	expiryTime, _ := sub.ExpiryTime()
+	replaceStatusCondition(sub, eventingv1alpha2.MakeCondition(eventingv1alpha2.ConditionSubscriptionActive,
+		eventingv1alpha2.ConditionReasonSubscriptionNotActive, corev1.ConditionFalse, "subscription expired"))
+	sub.Status.SetConditionExpired(expiryTime)
+	sub.Status.Ready = false
+	return ctrl.Result{}, r.updateSubscription(ctx, sub, log)
+}
+
 // updateSubscription updates the subscription changes to k8s.
 func (r *Reconciler) updateSubscription(ctx context.Context, sub *eventingv1alpha2.Subscription, logger *zap.SugaredLogger) error {
 	namespacedName := &k8stypes.NamespacedName{
@@ -197,6 +269,8 @@ func (r *Reconciler) updateSubscription(ctx context.Context, sub *eventingv1alph
 	newSubscription := latestSubscription.DeepCopy()
 	newSubscription.Status = sub.Status
 	newSubscription.ObjectMeta.Finalizers = sub.ObjectMeta.Finalizers
+	newSubscription.SyncExplanation()
+	newSubscription.SyncSummary(eventingv1alpha2.BackendTypeEventMesh)
 
 	// emit the condition events if needed
 	r.emitConditionEvents(latestSubscription, newSubscription, logger)
@@ -238,7 +312,10 @@ func (r *Reconciler) updateStatus(ctx context.Context, oldSubscription, newSubsc
 	}
 
 	// update the status for subscription in k8s
-	if err := r.Status().Update(ctx, newSubscription); err != nil {
+	updateStart := time.Now()
+	err := r.Status().Update(ctx, newSubscription)
+	r.collector.RecordReconcilePhaseDuration(backendType, metrics.PhaseStatusUpdate, time.Since(updateStart))
+	if err != nil {
 		return xerrors.Errorf("failed to update subscription status: %v", err)
 	}
 	logger.Debugw("Updated subscription status", "oldStatus", oldSubscription.Status, "newStatus", newSubscription.Status)
@@ -261,7 +338,17 @@ func (r *Reconciler) handleDeleteSubscription(ctx context.Context, subscription
 	// delete EventMesh subscriptions
 	logger.Debug("Deleting subscription on EventMesh")
 	if err := r.Backend.DeleteSubscription(subscription); err != nil {
-		return ctrl.Result{}, err
+		if !subscription.IsForceDeleteRequested() {
+			// keep the finalizer and surface the failure, the request is retried with backoff
+			subscription.Status.SetConditionCleanupFailed(err)
+			if updateErr := r.updateSubscription(ctx, subscription, logger); updateErr != nil {
+				return ctrl.Result{}, xerrors.Errorf(updateErr.Error()+": %v", err)
+			}
+			return ctrl.Result{}, err
+		}
+		logger.Warnw("Force deleting subscription without cleaning up EventMesh", "error", err)
+		events.Warn(r.recorder, subscription, events.ReasonForceDeleted,
+			"Removed finalizer without deleting the EventMesh subscription: %v", err)
 	}
 
 	// update condition in subscription status
@@ -278,6 +365,7 @@ func (r *Reconciler) handleDeleteSubscription(ctx context.Context, subscription
 	}
 
 	r.collector.RemoveSubscriptionStatus(subscription.Name, subscription.Namespace, backendType, "", "")
+	r.collector.RemoveSubscriptionReady(backendType, subscription.Namespace, subscription.Name)
 	return ctrl.Result{Requeue: false}, nil
 }
 
@@ -289,7 +377,10 @@ func (r *Reconciler) syncEventMeshSubscription(subscription *eventingv1alpha2.Su
 		return false, errors.Errorf("APIRule is required")
 	}
 
-	if _, err := r.Backend.SyncSubscription(subscription, r.cleaner, apiRule); err != nil {
+	apiStart := time.Now()
+	_, err := r.Backend.SyncSubscription(subscription, r.cleaner, apiRule)
+	r.collector.RecordReconcilePhaseDuration(backendType, metrics.PhaseEventMeshAPI, time.Since(apiStart))
+	if err != nil {
 		r.syncConditionSubscribed(subscription, err)
 		return false, err
 	}
@@ -737,9 +828,17 @@ func (r *Reconciler) emitConditionEvent(subscription *eventingv1alpha2.Subscript
 	r.recorder.Event(subscription, eventType, string(condition.Reason), condition.Message)
 }
 
+// SetControllerOptions sets the workqueue options, e.g. the rate limiter, of the subscription controller.
+// It has to be called before SetupUnmanaged.
+func (r *Reconciler) SetControllerOptions(options controller.Options) {
+	r.controllerOptions = options
+}
+
 // SetupUnmanaged creates a controller under the client control.
 func (r *Reconciler) SetupUnmanaged(mgr ctrl.Manager) error {
-	ctru, err := controller.NewUnmanaged(reconcilerName, mgr, controller.Options{Reconciler: r})
+	options := r.controllerOptions
+	options.Reconciler = r
+	ctru, err := controller.NewUnmanaged(reconcilerName, mgr, options)
 	if err != nil {
 		return fmt.Errorf("failed to create unmanaged controller: %w", err)
 	}
@@ -749,6 +848,15 @@ func (r *Reconciler) SetupUnmanaged(mgr ctrl.Manager) error {
 		return fmt.Errorf("failed to watch subscriptions: %w", err)
 	}
 
+	if err := ctru.Watch(source.Kind(mgr.GetCache(), &corev1.Service{}),
+		sink.NewServiceEventHandler(r.Client, nil)); err != nil {
+		return fmt.Errorf("failed to watch services: %w", err)
+	}
+
+	if err := sink.WatchFunctions(mgr, ctru, r.Client, nil); err != nil {
+		return fmt.Errorf("failed to watch functions: %w", err)
+	}
+
 	apiRuleEventHandler := handler.EnqueueRequestForOwner(r.Scheme(), mgr.GetRESTMapper(),
 		&eventingv1alpha2.Subscription{})
 	if err := ctru.Watch(source.Kind(mgr.GetCache(), &apigatewayv1beta1.APIRule{}), apiRuleEventHandler); err != nil {
@@ -5,5 +5,7 @@ import "github.com/pkg/errors"
 var (
 	errFailedToUpdateStatus     = errors.New("failed to update JetStream subscription status")
 	errFailedToDeleteSub        = errors.New("failed to delete JetStream subscription")
+	errFailedToDeleteExpiredSub = errors.New("failed to delete expired subscription")
+	errSubscriptionExpired      = errors.New("subscription expired")
 	errFailedToUpdateFinalizers = errors.New("failed to update subscription's finalizers")
 )
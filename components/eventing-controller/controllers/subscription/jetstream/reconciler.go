@@ -2,11 +2,16 @@ package jetstream
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/controllers/events"
@@ -30,6 +35,7 @@ import (
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
 	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
 	"github.com/kyma-project/kyma/components/eventing-controller/utils"
 
 	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
@@ -41,8 +47,28 @@ const (
 	reconcilerName  = "jetstream-subscription-reconciler"
 	requeueDuration = 10 * time.Second
 	backendType     = "NATS_Jetstream"
+
+	// backlogRefreshInterval is the minimal time between two counts of the pending messages of a subscription.
+	// The backlog is not counted on every reconciliation, since each status update triggers another one.
+	backlogRefreshInterval = 30 * time.Second
+
+	// wildcardMatchesRefreshInterval is the minimal time between two counts of the stream subjects matched by
+	// a wildcard subject. Each count lists the matching subjects of the stream, so it is shared by the subscriptions.
+	wildcardMatchesRefreshInterval = 5 * time.Minute
 )
 
+// sinkProbe is the resolved sink of a subscription at the time it was probed.
+type sinkProbe struct {
+	sink   string
+	probed time.Time
+}
+
+// wildcardMatches is the number of stream subjects matched by a wildcard subject at the time it was counted.
+type wildcardMatches struct {
+	count   int
+	counted time.Time
+}
+
 type Reconciler struct {
 	client.Client
 	ctx                 context.Context
@@ -53,6 +79,18 @@ type Reconciler struct {
 	sinkValidator       sink.Validator
 	customEventsChannel chan event.GenericEvent
 	collector           *metrics.Collector
+	sinkProber          sink.Prober
+	sinkProbeInterval   time.Duration
+	sinkHealthInterval  time.Duration
+	backlogRefreshes    sync.Map
+	sinkProbes          sync.Map
+	wildcardMatches     sync.Map
+	shard               sharding.Shard
+	controllerOptions   controller.Options
+	secondaryBackend    string
+	// wildcardSubjects stores the wildcard subjects counted for each subscription, so that the counts which are
+	// not used by any subscription anymore are evicted from wildcardMatches.
+	wildcardSubjects sync.Map
 }
 
 func NewReconciler(ctx context.Context, client client.Client, jsBackend jetstream.Backend,
@@ -72,9 +110,41 @@ func NewReconciler(ctx context.Context, client client.Client, jsBackend jetstrea
 	return reconciler
 }
 
+// SetSinkProber enables the periodic sink reachability probing of the subscriptions.
+// Probing stays disabled if the given prober is nil or the interval is not positive.
+func (r *Reconciler) SetSinkProber(prober sink.Prober, interval time.Duration) {
+	r.sinkProber = prober
+	r.sinkProbeInterval = interval
+}
+
+// SetSinkHealthInterval enables the SinkHealthy condition of the subscriptions, which is refreshed from the sink health
+// tracked by the metrics collector at the given interval. The condition stays disabled if the interval is not positive.
+func (r *Reconciler) SetSinkHealthInterval(interval time.Duration) {
+	r.sinkHealthInterval = interval
+}
+
+// SetShard restricts the reconciler to the Subscriptions which belong to the given shard.
+func (r *Reconciler) SetShard(shard sharding.Shard) {
+	r.shard = shard
+}
+
+// SetSecondaryBackend sets the backend which runs next to the primary one. The Subscriptions which are
+// reconciled by the other backend are skipped.
+func (r *Reconciler) SetSecondaryBackend(secondaryBackend string) {
+	r.secondaryBackend = secondaryBackend
+}
+
+// SetControllerOptions sets the workqueue options, e.g. the rate limiter, of the subscription controller.
+// It has to be called before SetupUnmanaged.
+func (r *Reconciler) SetControllerOptions(options controller.Options) {
+	r.controllerOptions = options
+}
+
 // SetupUnmanaged creates a controller under the client control.
 func (r *Reconciler) SetupUnmanaged(mgr ctrl.Manager) error {
-	ctru, err := controller.NewUnmanaged(reconcilerName, mgr, controller.Options{Reconciler: r})
+	options := r.controllerOptions
+	options.Reconciler = r
+	ctru, err := controller.NewUnmanaged(reconcilerName, mgr, options)
 	if err != nil {
 		r.namedLogger().Errorw("Failed to create unmanaged controller", "error", err)
 		return err
@@ -86,6 +156,23 @@ func (r *Reconciler) SetupUnmanaged(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if err := ctru.Watch(source.Kind(mgr.GetCache(), &corev1.Service{}),
+		sink.NewServiceEventHandler(r.Client, r.forgetSinkProbe)); err != nil {
+		r.namedLogger().Errorw("Failed to setup watch for services", "error", err)
+		return err
+	}
+
+	if err := ctru.Watch(source.Kind(mgr.GetCache(), sink.NewEndpointSliceMetadata()),
+		sink.NewEndpointSliceEventHandler(r.Client, r.forgetSinkProbe)); err != nil {
+		r.namedLogger().Errorw("Failed to setup watch for endpointslices", "error", err)
+		return err
+	}
+
+	if err := sink.WatchFunctions(mgr, ctru, r.Client, r.forgetSinkProbe); err != nil {
+		r.namedLogger().Errorw("Failed to setup watch for functions", "error", err)
+		return err
+	}
+
 	if err := ctru.Watch(&source.Channel{Source: r.customEventsChannel},
 		&handler.EnqueueRequestForObject{}); err != nil {
 		r.namedLogger().Errorw("Failed to setup watch for custom channel", "error", err)
@@ -106,15 +193,27 @@ func (r *Reconciler) SetupUnmanaged(mgr ctrl.Manager) error {
 // +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=subscriptions/status,verbs=get;update;patch
 // Generate required RBAC to emit kubernetes events in the controller.
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+// +kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=serverless.kyma-project.io,resources=functions,verbs=get;list;watch
 
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	r.namedLogger().Debugw("Received subscription v1alpha2 reconciliation request",
 		"namespace", req.Namespace, "name", req.Name)
 
+	// skip the subscriptions which are reconciled by another controller replica
+	if !r.shard.Owns(req.Namespace, req.Name) {
+		return ctrl.Result{}, nil
+	}
+
 	// fetch current subscription object and ensure the object was not deleted in the meantime
 	currentSubscription := &eventingv1alpha2.Subscription{}
 	err := r.Client.Get(ctx, req.NamespacedName, currentSubscription)
 	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.forgetSubscription(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -124,6 +223,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// Bind fields to logger
 	log := backendutils.LoggerWithSubscription(r.namedLogger(), desiredSubscription)
 
+	// skip the subscriptions which are reconciled by the other backend,
+	// but delete the consumers created before the subscription selected the other backend
+	if !desiredSubscription.IsReconciledBy(eventingv1alpha2.BackendNATS, r.secondaryBackend) {
+		log.Debugw("Skipping subscription reconciled by the other backend")
+		if len(desiredSubscription.Status.Backend.Types) == 0 {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, r.handleOtherBackend(ctx, currentSubscription, desiredSubscription)
+	}
+
 	if isInDeletion(desiredSubscription) {
 		// The object is being deleted
 		return r.handleSubscriptionDeletion(ctx, desiredSubscription, log)
@@ -131,6 +240,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	defer r.updateSubscriptionMetrics(currentSubscription, desiredSubscription)
 
+	// stop the delivery of expired subscriptions
+	if desiredSubscription.IsExpired(time.Now()) {
+		return r.handleSubscriptionExpiry(ctx, desiredSubscription, log)
+	}
+
 	// The object is not being deleted, so if it does not have our finalizer,
 	// then lets add the finalizer and update the object.
 	if !containsFinalizer(desiredSubscription) {
@@ -145,8 +259,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	// resolve the sinkRef to the sink URL used by the backend on a copy, which is never updated in k8s,
+	// because the webhook rejects the sink together with the sinkRef
+	resolvedSubscription := desiredSubscription.DeepCopy()
+	resolvedSubscription.ResolveSink()
+	desiredSubscription.Status.SinkURI = resolvedSubscription.Status.SinkURI
+
 	// Check for valid sink
-	if err := r.sinkValidator.Validate(desiredSubscription); err != nil {
+	if err := r.sinkValidator.Validate(resolvedSubscription); err != nil {
 		if deleteErr := r.Backend.DeleteSubscriptionsOnly(desiredSubscription); deleteErr != nil {
 			r.namedLogger().Errorw(
 				"Failed to delete JetStream subscriptions",
@@ -167,7 +287,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	// Synchronize Kyma subscription to JetStream backend
-	if syncSubErr := r.Backend.SyncSubscription(desiredSubscription); syncSubErr != nil {
+	syncStart := time.Now()
+	syncSubErr := r.Backend.SyncSubscription(resolvedSubscription)
+	r.collector.RecordReconcilePhaseDuration(backendType, metrics.PhaseBackendSync, time.Since(syncStart))
+	if syncSubErr != nil {
 		result := ctrl.Result{}
 		if syncErr := r.syncSubscriptionStatus(ctx, desiredSubscription, syncSubErr, log); syncErr != nil {
 			return result, syncErr
@@ -181,8 +304,139 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return result, syncSubErr
 	}
 
-	// Update Subscription status
-	return ctrl.Result{}, r.syncSubscriptionStatus(ctx, desiredSubscription, nil, log)
+	r.syncBacklog(desiredSubscription, log)
+
+	// Probe the sink reachability and refresh the sink health if enabled
+	result := ctrl.Result{RequeueAfter: r.statusRefreshInterval()}
+	if r.isSinkProbingEnabled() {
+		untilProbe := r.probeSink(ctx, desiredSubscription, resolvedSubscription.Spec.Sink, log)
+		if untilProbe < result.RequeueAfter {
+			result.RequeueAfter = untilProbe
+		}
+	}
+	if r.isSinkHealthEnabled() {
+		r.syncSinkHealth(desiredSubscription, resolvedSubscription.Spec.Sink, log)
+	}
+
+	// Update Subscription status and requeue the request for the next probe, sink health refresh, or expiry
+	if err := r.syncSubscriptionStatus(ctx, desiredSubscription, nil, log); err != nil {
+		return ctrl.Result{}, err
+	}
+	result.RequeueAfter = desiredSubscription.RequeueUntilExpiry(result.RequeueAfter, time.Now())
+	return result, nil
+}
+
+// handleSubscriptionExpiry stops the delivery of an expired subscription by deleting its JetStream consumers
+// and marks it as expired. The subscription itself is deleted instead if it requests so.
+func (r *Reconciler) handleSubscriptionExpiry(ctx context.Context,
+	subscription *eventingv1alpha2.Subscription, log *zap.SugaredLogger) (ctrl.Result, error) {
+	if subscription.Spec.DeleteOnExpiry {
+		if err := r.Delete(ctx, subscription); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, pkgerrors.MakeError(errFailedToDeleteExpiredSub, err)
+		}
+		log.Infow("Deleted expired subscription")
+		return ctrl.Result{}, nil
+	}
+
+	// the consumers are deleted only once, when the subscription expires
+	expiredCondition := subscription.Status.FindCondition(eventingv1alpha2.ConditionExpired)
+	if expiredCondition == nil {
+		if err := r.Backend.DeleteSubscription(subscription); err != nil {
+			return ctrl.Result{}, pkgerrors.MakeError(errFailedToDeleteSub, err)
+		}
+		log.Infow("Stopped the delivery of expired subscription")
+		events.Normal(r.recorder, subscription, events.ReasonExpired,
+			"Subscription expired, deleted the JetStream consumers %v", subscription.Status.Backend.Types)
+	}
+
+	expiryTime, _ := subscription.ExpiryTime()
+	conditions := eventingv1alpha2.GetSubscriptionActiveCondition(subscription, errSubscriptionExpired)
+	if expiredCondition != nil {
+		conditions = append(conditions, *expiredCondition)
+	}
+	subscription.Status.Conditions = conditions
+	subscription.Status.SetConditionExpired(expiryTime)
+	subscription.Status.Ready = false
+	subscription.Status.Backend.Types = nil
+	subscription.Status.Backlog = nil
+	return ctrl.Result{}, r.updateSubscriptionStatus(ctx, subscription, log)
+}
+
+// syncBacklog sets the number of pending messages of the subscription in its status, unless it was counted
+// less than backlogRefreshInterval ago.
+func (r *Reconciler) syncBacklog(subscription *eventingv1alpha2.Subscription, log *zap.SugaredLogger) {
+	key := k8stypes.NamespacedName{Namespace: subscription.Namespace, Name: subscription.Name}
+	now := time.Now()
+	if refreshed, ok := r.backlogRefreshes.Load(key); ok && subscription.Status.Backlog != nil &&
+		now.Sub(refreshed.(time.Time)) < backlogRefreshInterval {
+		return
+	}
+	pending, err := r.Backend.CountPendingMessages(subscription)
+	if err != nil {
+		log.Infow("Failed to count the pending messages of the subscription", "error", err)
+		return
+	}
+	r.backlogRefreshes.Store(key, now)
+	subscription.Status.Backlog = &pending
+}
+
+func (r *Reconciler) isSinkProbingEnabled() bool {
+	return r.sinkProber != nil && r.sinkProbeInterval > 0
+}
+
+func (r *Reconciler) isSinkHealthEnabled() bool {
+	return r.collector != nil && r.sinkHealthInterval > 0
+}
+
+// statusRefreshInterval returns the shortest interval of the enabled sink probing and sink health refresh,
+// or zero if neither is enabled.
+func (r *Reconciler) statusRefreshInterval() time.Duration {
+	var interval time.Duration
+	if r.isSinkProbingEnabled() {
+		interval = r.sinkProbeInterval
+	}
+	if r.isSinkHealthEnabled() && (interval == 0 || r.sinkHealthInterval < interval) {
+		interval = r.sinkHealthInterval
+	}
+	return interval
+}
+
+// syncSinkHealth sets the SinkHealthy condition from the health of the host of the given resolved sink
+// of the subscription, which is aggregated over the deliveries of all the subscriptions sharing the host.
+func (r *Reconciler) syncSinkHealth(subscription *eventingv1alpha2.Subscription, sink string,
+	log *zap.SugaredLogger) {
+	health, ok := r.collector.SinkHealth(sink)
+	if !ok || health.Healthy {
+		subscription.Status.SetConditionSinkHealthy(true, "")
+		return
+	}
+	log.Debugw("Subscription sink host is unhealthy", "host", health.Host, "successRatio", health.SuccessRatio)
+	// the message leaves out the success ratio and latency, which would update the status on every refresh
+	subscription.Status.SetConditionSinkHealthy(false, fmt.Sprintf(
+		"Sink host %s is unhealthy and shared by %d subscriptions, see the eventing_ec_sink metrics for its details",
+		health.Host, health.Subscriptions))
+}
+
+// probeSink checks if the given resolved sink of the subscription is reachable and sets the SinkReachable condition
+// accordingly, unless the same sink was probed less than sinkProbeInterval ago. It returns the time until the next
+// probe is due.
+func (r *Reconciler) probeSink(ctx context.Context, subscription *eventingv1alpha2.Subscription, sink string,
+	log *zap.SugaredLogger) time.Duration {
+	key := k8stypes.NamespacedName{Namespace: subscription.Namespace, Name: subscription.Name}
+	now := time.Now()
+	if cached, ok := r.sinkProbes.Load(key); ok && cached.(sinkProbe).sink == sink &&
+		subscription.Status.FindCondition(eventingv1alpha2.ConditionSinkReachable) != nil {
+		if sinceProbe := now.Sub(cached.(sinkProbe).probed); sinceProbe < r.sinkProbeInterval {
+			return r.sinkProbeInterval - sinceProbe
+		}
+	}
+	err := r.sinkProber.Probe(ctx, sink)
+	if err != nil {
+		log.Infow("Subscription sink is not reachable", "sink", sink, "error", err)
+	}
+	r.sinkProbes.Store(key, sinkProbe{sink: sink, probed: now})
+	subscription.Status.SetConditionSinkReachable(err)
+	return r.sinkProbeInterval
 }
 
 func (r *Reconciler) updateSubscriptionMetrics(current, desired *eventingv1alpha2.Subscription) {
@@ -211,6 +465,7 @@ func (r *Reconciler) updateSubscriptionMetrics(current, desired *eventingv1alpha
 			r.Backend.GetConfig().JSStreamName,
 		)
 	}
+	r.collector.RecordSubscriptionReady(backendType, desired.Namespace, desired.Name, desired.Status.Ready)
 }
 
 // HandleNatsConnClose is called by NATS when the connection to the NATS server is closed. When it
@@ -225,7 +480,7 @@ func (r *Reconciler) HandleNatsConnClose(_ *nats.Conn) {
 		// there will be no future chance to retry connecting to NATS!
 		panic(err)
 	}
-	r.enqueueReconciliationForSubscriptions(subs.Items)
+	r.enqueueReconciliationForSubscriptions(r.shard.Filter(subs.Items))
 }
 
 // enqueueReconciliationForSubscriptions adds the subscriptions to the customEventsChannel
@@ -248,15 +503,27 @@ func (r *Reconciler) handleSubscriptionDeletion(ctx context.Context,
 
 	if err := r.Backend.DeleteSubscription(subscription); err != nil {
 		deleteSubErr := pkgerrors.MakeError(errFailedToDeleteSub, err)
-		// if failed to delete the external dependency here, return with error
-		// so that it can be retried
-		if syncErr := r.syncSubscriptionStatus(ctx, subscription, deleteSubErr, log); syncErr != nil {
-			return ctrl.Result{}, syncErr
+		if !subscription.IsForceDeleteRequested() {
+			// if failed to delete the external dependency here, return with error
+			// so that it can be retried with backoff
+			subscription.Status.SetConditionCleanupFailed(deleteSubErr)
+			events.Warn(r.recorder, subscription, events.ReasonCleanupFailed,
+				"Delete JetStream consumers failed: %v", deleteSubErr)
+			if syncErr := r.syncSubscriptionStatus(ctx, subscription, deleteSubErr, log); syncErr != nil {
+				return ctrl.Result{}, syncErr
+			}
+			return ctrl.Result{}, deleteSubErr
 		}
-		return ctrl.Result{}, deleteSubErr
+		// the user requested to remove the finalizer anyway, record what could not be cleaned up
+		log.Warnw("Force deleting subscription without cleaning up the JetStream consumers",
+			"consumers", subscription.Status.Backend.Types, "error", deleteSubErr)
+		events.Warn(r.recorder, subscription, events.ReasonForceDeleted,
+			"Removed finalizer without deleting the JetStream consumers %v: %v",
+			subscription.Status.Backend.Types, deleteSubErr)
 	}
 
 	types := subscription.Status.Backend.Types
+	r.forgetSubscription(k8stypes.NamespacedName{Namespace: subscription.Namespace, Name: subscription.Name})
 	// remove the eventing finalizer from the list and update the subscription.
 	subscription.ObjectMeta.Finalizers = utils.RemoveString(subscription.ObjectMeta.Finalizers,
 		eventingv1alpha2.Finalizer)
@@ -275,18 +542,64 @@ func (r *Reconciler) handleSubscriptionDeletion(ctx context.Context,
 			r.Backend.GetConfig().JSStreamName,
 		)
 	}
+	r.collector.RemoveSubscriptionReady(backendType, subscription.Namespace, subscription.Name)
 
 	return ctrl.Result{}, nil
 }
 
+// handleOtherBackend deletes the consumers of a subscription which is reconciled by the other backend now,
+// and clears their types from the status, so that they are not deleted again on the next reconciliation.
+// Only the NATS status fields are updated, the other backend owns the rest of the status.
+func (r *Reconciler) handleOtherBackend(ctx context.Context,
+	currentSubscription, desiredSubscription *eventingv1alpha2.Subscription) error {
+	if err := r.Backend.DeleteSubscription(desiredSubscription); err != nil {
+		return err
+	}
+	desiredSubscription.Status.Backend.Types = nil
+	r.updateSubscriptionMetrics(currentSubscription, desiredSubscription)
+
+	actualSubscription := &eventingv1alpha2.Subscription{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(desiredSubscription), actualSubscription); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	actualSubscription.Status.Backend.Types = nil
+	if err := r.Client.Status().Update(ctx, actualSubscription); err != nil {
+		return pkgerrors.MakeError(errFailedToUpdateStatus, err)
+	}
+	return nil
+}
+
+// forgetSinkProbe removes the last sink probe of a subscription whose sink changed, so that it is probed on the next
+// reconciliation.
+func (r *Reconciler) forgetSinkProbe(key k8stypes.NamespacedName) {
+	r.sinkProbes.Delete(key)
+}
+
+// forgetSubscription removes the cached backlog refresh, sink probe and wildcard counts of a deleted subscription.
+func (r *Reconciler) forgetSubscription(key k8stypes.NamespacedName) {
+	r.backlogRefreshes.Delete(key)
+	r.sinkProbes.Delete(key)
+	r.syncWildcardSubjects(key, nil)
+}
+
 // syncSubscriptionStatus syncs Subscription status and updates the k8s subscription.
 func (r *Reconciler) syncSubscriptionStatus(ctx context.Context,
 	desiredSubscription *eventingv1alpha2.Subscription, err error, log *zap.SugaredLogger) error {
 	// set ready state
 	desiredSubscription.Status.Ready = err == nil
 
-	// compile the desired conditions
-	desiredSubscription.Status.Conditions = eventingv1alpha2.GetSubscriptionActiveCondition(desiredSubscription, err)
+	// compile the desired conditions and keep the sink reachability and cleanup conditions if they were set
+	conditions := eventingv1alpha2.GetSubscriptionActiveCondition(desiredSubscription, err)
+	for _, t := range []eventingv1alpha2.ConditionType{
+		eventingv1alpha2.ConditionSinkReachable,
+		eventingv1alpha2.ConditionSinkHealthy,
+		eventingv1alpha2.ConditionCleanedUp,
+	} {
+		if c := desiredSubscription.Status.FindCondition(t); c != nil {
+			conditions = append(conditions, *c)
+		}
+	}
+	desiredSubscription.Status.Conditions = conditions
 
 	// Update the subscription
 	return r.updateSubscriptionStatus(ctx, desiredSubscription, log)
@@ -309,6 +622,8 @@ func (r *Reconciler) updateSubscriptionStatus(ctx context.Context,
 	// copy new changes to the latest object
 	desiredSubscription := actualSubscription.DeepCopy()
 	desiredSubscription.Status = sub.Status
+	desiredSubscription.SyncExplanation()
+	desiredSubscription.SyncSummary(eventingv1alpha2.BackendTypeNATS)
 
 	// sync subscription status with k8s
 	if err := r.updateStatus(ctx, actualSubscription, desiredSubscription, logger); err != nil {
@@ -327,7 +642,10 @@ func (r *Reconciler) updateStatus(ctx context.Context, oldSubscription,
 	}
 
 	// update the status for subscription in k8s
-	if err := r.Status().Update(ctx, newSubscription); err != nil {
+	updateStart := time.Now()
+	err := r.Status().Update(ctx, newSubscription)
+	r.collector.RecordReconcilePhaseDuration(backendType, metrics.PhaseStatusUpdate, time.Since(updateStart))
+	if err != nil {
 		events.Warn(r.recorder, newSubscription, events.ReasonUpdateFailed,
 			"Update Subscription status failed %s", newSubscription.Name)
 		return pkgerrors.MakeError(errFailedToUpdateStatus, err)
@@ -369,12 +687,78 @@ func (r *Reconciler) syncEventTypes(desiredSubscription *eventingv1alpha2.Subscr
 	if err != nil {
 		return err
 	}
+	// the wildcards reach the consumer filters with the exact type matching only, otherwise they are cleaned
+	key := k8stypes.NamespacedName{Namespace: desiredSubscription.Namespace, Name: desiredSubscription.Name}
+	if desiredSubscription.Spec.TypeMatching == eventingv1alpha2.TypeMatchingExact {
+		r.countWildcardMatches(key, jsTypes, jsSubjects)
+	} else {
+		r.syncWildcardSubjects(key, nil)
+	}
 	if !reflect.DeepEqual(desiredSubscription.Status.Backend.Types, jsTypes) {
 		desiredSubscription.Status.Backend.Types = jsTypes
 	}
 	return nil
 }
 
+// countWildcardMatches sets the number of stream subjects matched by each wildcard type, which is counted again
+// at most once per wildcardMatchesRefreshInterval for each subject.
+// A failure to count is only logged, since the consumers of the subscription are not affected by it.
+func (r *Reconciler) countWildcardMatches(key k8stypes.NamespacedName, jsTypes []eventingv1alpha2.JetStreamTypes,
+	jsSubjects []string) {
+	var subjects []string
+	defer func() { r.syncWildcardSubjects(key, subjects) }()
+	now := time.Now()
+	for i := range jsTypes {
+		if !eventingv1alpha2.IsWildcardType(jsTypes[i].OriginalType) {
+			continue
+		}
+		subjects = append(subjects, jsSubjects[i])
+		if cached, ok := r.wildcardMatches.Load(jsSubjects[i]); ok &&
+			now.Sub(cached.(wildcardMatches).counted) < wildcardMatchesRefreshInterval {
+			count := cached.(wildcardMatches).count
+			jsTypes[i].MatchedSubjects = &count
+			continue
+		}
+		count, err := r.Backend.CountMatchingSubjects(jsSubjects[i])
+		if err != nil {
+			r.namedLogger().Warnw("Failed to count the subjects matched by a wildcard type",
+				"type", jsTypes[i].OriginalType, "error", err)
+			continue
+		}
+		r.wildcardMatches.Store(jsSubjects[i], wildcardMatches{count: count, counted: now})
+		jsTypes[i].MatchedSubjects = &count
+	}
+}
+
+// syncWildcardSubjects stores the wildcard subjects counted for the subscription and evicts the counts of its
+// previous subjects which are not counted for any other subscription.
+func (r *Reconciler) syncWildcardSubjects(key k8stypes.NamespacedName, subjects []string) {
+	var previous []string
+	if value, ok := r.wildcardSubjects.Load(key); ok {
+		previous = value.([]string)
+	}
+	if len(subjects) == 0 {
+		r.wildcardSubjects.Delete(key)
+	} else {
+		r.wildcardSubjects.Store(key, subjects)
+	}
+	for _, subject := range previous {
+		if !slices.Contains(subjects, subject) && !r.isWildcardSubjectCounted(subject) {
+			r.wildcardMatches.Delete(subject)
+		}
+	}
+}
+
+// isWildcardSubjectCounted returns true if the wildcard subject is counted for any subscription.
+func (r *Reconciler) isWildcardSubjectCounted(subject string) bool {
+	counted := false
+	r.wildcardSubjects.Range(func(_, value any) bool {
+		counted = slices.Contains(value.([]string), subject)
+		return !counted
+	})
+	return counted
+}
+
 func (r *Reconciler) namedLogger() *zap.SugaredLogger {
 	return r.logger.WithContext().Named(reconcilerName)
 }
@@ -2,12 +2,15 @@ package jetstream
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -17,11 +20,13 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+	jetstreamfake "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream/fake"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream/mocks"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
@@ -79,6 +84,7 @@ func Test_Reconcile(t *testing.T) {
 			givenReconcilerSetup: func() (*Reconciler, *mocks.Backend) {
 				te := setupTestEnvironment(t, testSub)
 				te.Backend.On("SyncSubscription", mock.Anything).Return(nil)
+				te.Backend.On("CountPendingMessages", mock.Anything).Return(0, nil)
 				te.Backend.On("GetJetStreamSubjects", mock.Anything, mock.Anything, mock.Anything).Return(
 					[]string{controllertesting.JetStreamSubject})
 				te.Backend.On("GetConfig", mock.Anything).Return(env.NATSConfig{JSStreamName: "sap"})
@@ -243,11 +249,13 @@ func Test_Reconcile(t *testing.T) {
 
 func Test_handleSubscriptionDeletion(t *testing.T) {
 	testCases := []struct {
-		name            string
-		givenFinalizers []string
-		wantDeleteCall  bool
-		wantFinalizers  []string
-		wantError       error
+		name             string
+		givenFinalizers  []string
+		givenForceDelete bool
+		wantDeleteCall   bool
+		wantDeleteError  bool
+		wantFinalizers   []string
+		wantError        error
 	}{
 		{
 			name:            "With no finalizers the NATS subscription should not be deleted",
@@ -275,9 +283,20 @@ func Test_handleSubscriptionDeletion(t *testing.T) {
 			name:            "With Delete Subscription returning an error, the finalizer still remains",
 			givenFinalizers: []string{eventingv1alpha2.Finalizer},
 			wantDeleteCall:  true,
+			wantDeleteError: true,
 			wantFinalizers:  []string{eventingv1alpha2.Finalizer},
 			wantError:       errFailedToDeleteSub,
 		},
+		{
+			name: "With Delete Subscription returning an error and the force-delete annotation," +
+				" the finalizer should be cleared",
+			givenFinalizers:  []string{eventingv1alpha2.Finalizer},
+			givenForceDelete: true,
+			wantDeleteCall:   true,
+			wantDeleteError:  true,
+			wantFinalizers:   []string{},
+			wantError:        nil,
+		},
 	}
 
 	for _, tC := range testCases {
@@ -288,12 +307,15 @@ func Test_handleSubscriptionDeletion(t *testing.T) {
 			sub := controllertesting.NewSubscription(subscriptionName, namespaceName,
 				controllertesting.WithFinalizers(testCase.givenFinalizers),
 			)
+			if testCase.givenForceDelete {
+				sub.Annotations = map[string]string{eventingv1alpha2.ForceDeleteAnnotation: "true"}
+			}
 
 			testEnvironment := setupTestEnvironment(t, sub)
 			ctx, r, mockedBackend := testEnvironment.Context, testEnvironment.Reconciler, testEnvironment.Backend
 
 			if testCase.wantDeleteCall {
-				if errors.Is(testCase.wantError, errFailedToDeleteSub) {
+				if testCase.wantDeleteError {
 					mockedBackend.On("DeleteSubscription", sub).Return(errors.New("deletion error"))
 				} else {
 					mockedBackend.On("DeleteSubscription", sub).Return(nil)
@@ -324,6 +346,80 @@ func Test_handleSubscriptionDeletion(t *testing.T) {
 	}
 }
 
+func Test_handleSubscriptionExpiry(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		givenExpired         bool
+		givenDeleteOnExpiry  bool
+		wantDeleteCall       bool
+		wantSubscriptionGone bool
+	}{
+		{
+			name:           "The NATS subscription of a newly expired subscription should be deleted",
+			wantDeleteCall: true,
+		},
+		{
+			name:           "The NATS subscription of an already expired subscription should not be deleted again",
+			givenExpired:   true,
+			wantDeleteCall: false,
+		},
+		{
+			name:                 "The subscription should be deleted if it requests the deletion on expiry",
+			givenDeleteOnExpiry:  true,
+			wantDeleteCall:       false,
+			wantSubscriptionGone: true,
+		},
+	}
+
+	for _, tC := range testCases {
+		testCase := tC
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			expiryDate := time.Now().Add(-time.Minute)
+			sub := controllertesting.NewSubscription(subscriptionName, namespaceName,
+				controllertesting.WithFinalizers([]string{eventingv1alpha2.Finalizer}),
+				controllertesting.WithExpiryDate(expiryDate),
+			)
+			sub.Spec.DeleteOnExpiry = testCase.givenDeleteOnExpiry
+			sub.Status.Backend.Types = []eventingv1alpha2.JetStreamTypes{{OriginalType: "type", ConsumerName: "consumer"}}
+			if testCase.givenExpired {
+				sub.Status.SetConditionExpired(expiryDate)
+			}
+
+			testEnvironment := setupTestEnvironment(t, sub)
+			ctx, r, mockedBackend := testEnvironment.Context, testEnvironment.Reconciler, testEnvironment.Backend
+			if testCase.wantDeleteCall {
+				mockedBackend.On("DeleteSubscription", sub).Return(nil)
+			}
+
+			// when
+			result, err := r.handleSubscriptionExpiry(ctx, sub, r.namedLogger())
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, ctrl.Result{}, result)
+			mockedBackend.AssertExpectations(t)
+
+			fetchedSub, err := fetchTestSubscription(ctx, r)
+			if testCase.wantSubscriptionGone {
+				// the finalizer keeps the subscription until its deletion is reconciled
+				require.NoError(t, err)
+				require.False(t, fetchedSub.DeletionTimestamp.IsZero())
+				return
+			}
+			require.NoError(t, err)
+			require.False(t, fetchedSub.Status.Ready)
+			require.Empty(t, fetchedSub.Status.Backend.Types)
+			expiredCondition := fetchedSub.Status.FindCondition(eventingv1alpha2.ConditionExpired)
+			require.NotNil(t, expiredCondition)
+			require.Equal(t, corev1.ConditionTrue, expiredCondition.Status)
+			activeCondition := fetchedSub.Status.FindCondition(eventingv1alpha2.ConditionSubscriptionActive)
+			require.NotNil(t, activeCondition)
+			require.Equal(t, corev1.ConditionFalse, activeCondition.Status)
+		})
+	}
+}
+
 func Test_addFinalizer(t *testing.T) {
 	// given
 	ctx := context.Background()
@@ -476,6 +572,168 @@ func Test_syncSubscriptionStatus(t *testing.T) {
 	}
 }
 
+func Test_syncSinkHealth(t *testing.T) {
+	// given
+	collector := metrics.NewCollector()
+	collector.SetSinkHealthPolicy(metrics.SinkHealthPolicy{Window: time.Minute, MinDeliveries: 2, MinSuccessRatio: 0.5})
+	r := &Reconciler{collector: collector}
+	r.SetSinkHealthInterval(time.Minute)
+	sub := controllertesting.NewSubscription(subscriptionName, namespaceName,
+		controllertesting.WithSinkURL("http://receiver.test.svc.cluster.local/orders"))
+	log := zap.NewNop().Sugar()
+
+	// when the sink host has no deliveries
+	r.syncSinkHealth(sub, sub.Spec.Sink, log)
+
+	// then no condition is added
+	require.Nil(t, sub.Status.FindCondition(eventingv1alpha2.ConditionSinkHealthy))
+
+	// when the deliveries of other subscriptions to the same host fail
+	for _, name := range []string{"payments", "invoices"} {
+		collector.RecordSinkDelivery(namespaceName, name, "http://receiver.test.svc.cluster.local/"+name,
+			time.Millisecond, http.StatusServiceUnavailable)
+	}
+	r.syncSinkHealth(sub, sub.Spec.Sink, log)
+
+	// then the subscription is marked as affected
+	condition := sub.Status.FindCondition(eventingv1alpha2.ConditionSinkHealthy)
+	require.NotNil(t, condition)
+	require.Equal(t, corev1.ConditionFalse, condition.Status)
+	require.Contains(t, condition.Message, "receiver.test.svc.cluster.local")
+	require.Contains(t, condition.Message, "shared by 2 subscriptions")
+
+	// when the host recovers
+	for i := 0; i < 3; i++ {
+		collector.RecordSinkDelivery(namespaceName, subscriptionName, sub.Spec.Sink, time.Millisecond, http.StatusOK)
+	}
+	r.syncSinkHealth(sub, sub.Spec.Sink, log)
+
+	// then the condition is set to true
+	condition = sub.Status.FindCondition(eventingv1alpha2.ConditionSinkHealthy)
+	require.NotNil(t, condition)
+	require.Equal(t, corev1.ConditionTrue, condition.Status)
+}
+
+func Test_probeSink(t *testing.T) {
+	// given
+	probes := 0
+	prober := sink.ProberFunc(func(context.Context, string) error {
+		probes++
+		return nil
+	})
+	r := &Reconciler{}
+	r.SetSinkProber(prober, time.Minute)
+	sub := controllertesting.NewSubscription(subscriptionName, namespaceName,
+		controllertesting.WithSinkURL("http://receiver.test.svc.cluster.local/orders"))
+	log := zap.NewNop().Sugar()
+
+	// when the sink was not probed yet
+	untilProbe := r.probeSink(context.Background(), sub, sub.Spec.Sink, log)
+
+	// then the sink is probed and the next probe is due after the interval
+	require.Equal(t, 1, probes)
+	require.Equal(t, time.Minute, untilProbe)
+	condition := sub.Status.FindCondition(eventingv1alpha2.ConditionSinkReachable)
+	require.NotNil(t, condition)
+	require.Equal(t, corev1.ConditionTrue, condition.Status)
+
+	// when the subscription is reconciled again within the interval
+	untilProbe = r.probeSink(context.Background(), sub, sub.Spec.Sink, log)
+
+	// then the sink is not probed again
+	require.Equal(t, 1, probes)
+	require.Greater(t, untilProbe, time.Duration(0))
+	require.LessOrEqual(t, untilProbe, time.Minute)
+
+	// when the last probe is older than the interval
+	key := types.NamespacedName{Namespace: namespaceName, Name: subscriptionName}
+	r.sinkProbes.Store(key, sinkProbe{sink: sub.Spec.Sink, probed: time.Now().Add(-2 * time.Minute)})
+	untilProbe = r.probeSink(context.Background(), sub, sub.Spec.Sink, log)
+
+	// then the sink is probed again
+	require.Equal(t, 2, probes)
+	require.Equal(t, time.Minute, untilProbe)
+
+	// when the sink of the subscription changes within the interval
+	untilProbe = r.probeSink(context.Background(), sub, "http://other.test.svc.cluster.local", log)
+
+	// then the new sink is probed
+	require.Equal(t, 3, probes)
+	require.Equal(t, time.Minute, untilProbe)
+
+	// when the subscription is deleted
+	testEnvironment := setupTestEnvironment(t)
+	testEnvironment.Reconciler.sinkProbes.Store(key, sinkProbe{sink: sub.Spec.Sink, probed: time.Now()})
+	_, err := testEnvironment.Reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+
+	// then its last probe is forgotten
+	require.NoError(t, err)
+	_, ok := testEnvironment.Reconciler.sinkProbes.Load(key)
+	require.False(t, ok)
+}
+
+func Test_Reconcile_OtherBackend(t *testing.T) {
+	// given a subscription which moved to the secondary backend and still has consumers
+	sub := controllertesting.NewSubscription(subscriptionName, namespaceName,
+		controllertesting.WithStatusJSBackendTypes([]eventingv1alpha2.JetStreamTypes{
+			{OriginalType: "order.created.v1", ConsumerName: "consumer"},
+		}),
+	)
+	sub.Annotations = map[string]string{eventingv1alpha2.BackendAnnotation: eventingv1alpha2.BackendEventMesh}
+	te := setupTestEnvironment(t, sub)
+	te.Reconciler.secondaryBackend = eventingv1alpha2.BackendEventMesh
+	te.Backend.On("DeleteSubscription", mock.Anything).Return(nil)
+	te.Backend.On("GetConfig", mock.Anything).Return(env.NATSConfig{JSStreamName: "sap"})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespaceName, Name: subscriptionName}}
+
+	// when
+	_, err := te.Reconciler.Reconcile(te.Context, req)
+
+	// then the consumers are deleted and removed from the status
+	require.NoError(t, err)
+	te.Backend.AssertNumberOfCalls(t, "DeleteSubscription", 1)
+	gotSub, err := fetchTestSubscription(te.Context, te.Reconciler)
+	require.NoError(t, err)
+	require.Empty(t, gotSub.Status.Backend.Types)
+
+	// when the subscription is reconciled again
+	_, err = te.Reconciler.Reconcile(te.Context, req)
+
+	// then nothing is deleted again
+	require.NoError(t, err)
+	te.Backend.AssertNumberOfCalls(t, "DeleteSubscription", 1)
+}
+
+func Test_statusRefreshInterval(t *testing.T) {
+	testCases := []struct {
+		name                string
+		givenProbeInterval  time.Duration
+		givenHealthInterval time.Duration
+		wantInterval        time.Duration
+	}{
+		{name: "nothing enabled should not requeue"},
+		{name: "only probing", givenProbeInterval: time.Minute, wantInterval: time.Minute},
+		{name: "only sink health", givenHealthInterval: time.Minute, wantInterval: time.Minute},
+		{
+			name:                "the shorter interval should be used",
+			givenProbeInterval:  2 * time.Minute,
+			givenHealthInterval: 30 * time.Second,
+			wantInterval:        30 * time.Second,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			prober, err := sink.NewProber("tcp", time.Second)
+			require.NoError(t, err)
+			r := &Reconciler{collector: metrics.NewCollector()}
+			r.SetSinkProber(prober, tc.givenProbeInterval)
+			r.SetSinkHealthInterval(tc.givenHealthInterval)
+			require.Equal(t, tc.wantInterval, r.statusRefreshInterval())
+		})
+	}
+}
+
 func Test_syncEventTypes(t *testing.T) {
 	testEnvironment := setupTestEnvironment(t)
 	r := testEnvironment.Reconciler
@@ -573,6 +831,66 @@ func Test_syncEventTypes(t *testing.T) {
 	}
 }
 
+func Test_countWildcardMatches(t *testing.T) {
+	// given
+	te := setupTestEnvironment(t)
+	backend := jetstreamfake.NewBackend(env.NATSConfig{JSStreamName: "sap", JSSubjectPrefix: "kyma"}, te.Cleaner)
+	r := &Reconciler{Backend: backend, logger: te.Logger}
+	subject := "kyma.order.*.v1"
+	key := types.NamespacedName{Namespace: "shop", Name: "orders"}
+	backend.SetMatchingSubjects(subject, 3)
+	newTypes := func() []eventingv1alpha2.JetStreamTypes {
+		return []eventingv1alpha2.JetStreamTypes{
+			{OriginalType: "order.*.v1"},
+			{OriginalType: "order.created.v1"},
+		}
+	}
+
+	// when
+	jsTypes := newTypes()
+	r.countWildcardMatches(key, jsTypes, []string{subject, "kyma.order.created.v1"})
+
+	// then only the wildcard type is counted
+	require.NotNil(t, jsTypes[0].MatchedSubjects)
+	require.Equal(t, 3, *jsTypes[0].MatchedSubjects)
+	require.Nil(t, jsTypes[1].MatchedSubjects)
+	require.Equal(t, 1, backend.Calls("CountMatchingSubjects"))
+
+	// when the subject is counted again within the refresh interval
+	backend.SetMatchingSubjects(subject, 4)
+	jsTypes = newTypes()
+	r.countWildcardMatches(key, jsTypes, []string{subject, "kyma.order.created.v1"})
+
+	// then the cached count is used
+	require.Equal(t, 3, *jsTypes[0].MatchedSubjects)
+	require.Equal(t, 1, backend.Calls("CountMatchingSubjects"))
+
+	// when the cached count is older than the refresh interval
+	r.wildcardMatches.Store(subject, wildcardMatches{count: 3, counted: time.Now().Add(-wildcardMatchesRefreshInterval)})
+	jsTypes = newTypes()
+	r.countWildcardMatches(key, jsTypes, []string{subject, "kyma.order.created.v1"})
+
+	// then the subject is counted again
+	require.Equal(t, 4, *jsTypes[0].MatchedSubjects)
+	require.Equal(t, 2, backend.Calls("CountMatchingSubjects"))
+
+	// when another subscription counts the same subject and the first one drops its wildcard type
+	otherKey := types.NamespacedName{Namespace: "shop", Name: "other"}
+	r.countWildcardMatches(otherKey, newTypes(), []string{subject, "kyma.order.created.v1"})
+	r.countWildcardMatches(key, newTypes()[1:], []string{"kyma.order.created.v1"})
+
+	// then the count is kept for the other subscription
+	_, ok := r.wildcardMatches.Load(subject)
+	require.True(t, ok)
+
+	// when the other subscription is deleted
+	r.forgetSubscription(otherKey)
+
+	// then the count is evicted
+	_, ok = r.wildcardMatches.Load(subject)
+	require.False(t, ok)
+}
+
 func Test_updateStatus(t *testing.T) {
 	sub := controllertesting.NewSubscription(subscriptionName, namespaceName, controllertesting.WithStatus(true))
 
@@ -650,6 +968,7 @@ func Test_updateSubscription(t *testing.T) {
 			{OriginalType: "order.created.v1", CleanType: "order.created.v1"},
 		}),
 	)
+	sub.SyncSummary(eventingv1alpha2.BackendTypeNATS)
 	subWithDifferentStatus := sub.DeepCopy()
 	subWithDifferentStatus.Status.Ready = !sub.Status.Ready
 
@@ -721,6 +1040,132 @@ func Test_updateSubscription(t *testing.T) {
 	}
 }
 
+// Test_Reconcile_FakeBackend tests the reconciliation of a subscription against the in-memory JetStream backend.
+func Test_Reconcile_FakeBackend(t *testing.T) {
+	ctx := context.Background()
+
+	// given
+	sub := controllertesting.NewSubscription(subscriptionName, namespaceName,
+		controllertesting.WithFinalizers([]string{eventingv1alpha2.Finalizer}),
+		controllertesting.WithSource(controllertesting.EventSourceClean),
+		controllertesting.WithEventType(controllertesting.OrderCreatedV1Event),
+	)
+	te := setupTestEnvironment(t, sub)
+	backend := jetstreamfake.NewBackend(env.NATSConfig{JSStreamName: "sap", JSSubjectPrefix: "kyma"}, te.Cleaner)
+	happyValidator := sink.ValidatorFunc(func(s *eventingv1alpha2.Subscription) error { return nil })
+	r := NewReconciler(ctx, te.Client, backend, te.Logger, te.Recorder, te.Cleaner, happyValidator,
+		metrics.NewCollector())
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespaceName, Name: subscriptionName}}
+	backend.SetPendingMessages(req.NamespacedName, 3)
+
+	// when
+	_, err := r.Reconcile(ctx, req)
+
+	// then the subscription is synchronized and its consumer is in the status
+	require.NoError(t, err)
+	require.Equal(t, []types.NamespacedName{req.NamespacedName}, backend.Subscriptions())
+	fetchedSub, err := fetchTestSubscription(ctx, r)
+	require.NoError(t, err)
+	controllertesting.RequireReady(t, &fetchedSub)
+	controllertesting.RequireBackendConsumer(t, &fetchedSub, controllertesting.OrderCreatedV1Event)
+	require.Equal(t, []string{fetchedSub.Status.Backend.Types[0].ConsumerName}, backend.Consumers())
+
+	// and the status summarizes the subscription
+	require.Equal(t, eventingv1alpha2.BackendTypeNATS, fetchedSub.Status.BackendType)
+	require.Equal(t, 1, fetchedSub.Status.TypeCount)
+	require.NotNil(t, fetchedSub.Status.Backlog)
+	require.Equal(t, 3, *fetchedSub.Status.Backlog)
+
+	// when the subscription is reconciled again within the refresh interval
+	backend.SetPendingMessages(req.NamespacedName, 5)
+	_, err = r.Reconcile(ctx, req)
+
+	// then the backlog is not counted again
+	require.NoError(t, err)
+	fetchedSub, err = fetchTestSubscription(ctx, r)
+	require.NoError(t, err)
+	require.Equal(t, 3, *fetchedSub.Status.Backlog)
+
+	// when the synchronization fails
+	syncErr := errors.New("backend sync error")
+	backend.SetErrors(jetstreamfake.Errors{SyncSubscription: syncErr})
+	_, err = r.Reconcile(ctx, req)
+
+	// then
+	require.ErrorIs(t, err, syncErr)
+	fetchedSub, err = fetchTestSubscription(ctx, r)
+	require.NoError(t, err)
+	require.False(t, fetchedSub.Status.Ready)
+}
+
+func Test_Reconcile_SinkRefThroughWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	// given a client which validates the Subscriptions like the webhook
+	sub := controllertesting.NewSubscription(subscriptionName, namespaceName,
+		controllertesting.WithSource(controllertesting.EventSourceClean),
+		controllertesting.WithEventType(controllertesting.OrderCreatedV1Event),
+		controllertesting.WithSinkRef("orders", 8080),
+	)
+	sub.Default()
+	fakeClient := createFakeClientBuilder(t).
+		WithStatusSubresource(&eventingv1alpha2.Subscription{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if newSub, ok := obj.(*eventingv1alpha2.Subscription); ok {
+					if _, err := newSub.ValidateCreate(); err != nil {
+						return err
+					}
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if newSub, ok := obj.(*eventingv1alpha2.Subscription); ok {
+					oldSub := &eventingv1alpha2.Subscription{}
+					if err := c.Get(ctx, client.ObjectKeyFromObject(obj), oldSub); err != nil {
+						return err
+					}
+					if _, err := newSub.ValidateUpdate(oldSub); err != nil {
+						return err
+					}
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).Build()
+	require.NoError(t, fakeClient.Create(ctx, sub))
+	te := setupTestEnvironment(t)
+	backend := jetstreamfake.NewBackend(env.NATSConfig{JSStreamName: "sap", JSSubjectPrefix: "kyma"}, te.Cleaner)
+	happyValidator := sink.ValidatorFunc(func(s *eventingv1alpha2.Subscription) error { return nil })
+	r := NewReconciler(ctx, fakeClient, backend, te.Logger, te.Recorder, te.Cleaner, happyValidator,
+		metrics.NewCollector())
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespaceName, Name: subscriptionName}}
+
+	// when the finalizer is added
+	_, err := r.Reconcile(ctx, req)
+
+	// then the update is accepted and the resolved sink is not persisted in the spec
+	require.NoError(t, err)
+	fetchedSub, err := fetchTestSubscription(ctx, r)
+	require.NoError(t, err)
+	require.Equal(t, []string{eventingv1alpha2.Finalizer}, fetchedSub.Finalizers)
+	require.Empty(t, fetchedSub.Spec.Sink)
+
+	// when the subscription is synchronized
+	_, err = r.Reconcile(ctx, req)
+
+	// then the backend uses the resolved sink, which is kept in the status only
+	require.NoError(t, err)
+	wantSink := "http://orders." + namespaceName + ".svc.cluster.local:8080"
+	backendSub, ok := backend.Subscription(req.NamespacedName)
+	require.True(t, ok)
+	require.Equal(t, wantSink, backendSub.Spec.Sink)
+	fetchedSub, err = fetchTestSubscription(ctx, r)
+	require.NoError(t, err)
+	controllertesting.RequireReady(t, &fetchedSub)
+	require.Empty(t, fetchedSub.Spec.Sink)
+	require.Equal(t, wantSink, fetchedSub.Status.SinkURI)
+}
+
 // helper functions and structs
 
 // TestEnvironment provides mocked resources for tests.
@@ -0,0 +1,11 @@
+package kafka
+
+import "github.com/pkg/errors"
+
+var (
+	errFailedToUpdateStatus     = errors.New("failed to update Kafka subscription status")
+	errFailedToDeleteSub        = errors.New("failed to delete Kafka subscription")
+	errFailedToDeleteExpiredSub = errors.New("failed to delete expired subscription")
+	errSubscriptionExpired      = errors.New("subscription expired")
+	errFailedToUpdateFinalizers = errors.New("failed to update subscription's finalizers")
+)
@@ -0,0 +1,362 @@
+package kafka
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/controllers/events"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/kafka"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
+	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
+	pkgerrors "github.com/kyma-project/kyma/components/eventing-controller/pkg/errors"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/object"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
+	"github.com/kyma-project/kyma/components/eventing-controller/utils"
+)
+
+const (
+	reconcilerName = "kafka-subscription-reconciler"
+	backendType    = "Kafka"
+)
+
+type Reconciler struct {
+	client.Client
+	ctx               context.Context
+	Backend           kafka.Backend
+	recorder          record.EventRecorder
+	logger            *logger.Logger
+	cleaner           cleaner.Cleaner
+	sinkValidator     sink.Validator
+	collector         *metrics.Collector
+	shard             sharding.Shard
+	controllerOptions controller.Options
+}
+
+func NewReconciler(ctx context.Context, client client.Client, kafkaBackend kafka.Backend,
+	logger *logger.Logger, recorder record.EventRecorder, cleaner cleaner.Cleaner,
+	defaultSinkValidator sink.Validator, collector *metrics.Collector) *Reconciler {
+	return &Reconciler{
+		Client:        client,
+		ctx:           ctx,
+		Backend:       kafkaBackend,
+		recorder:      recorder,
+		logger:        logger,
+		cleaner:       cleaner,
+		sinkValidator: defaultSinkValidator,
+		collector:     collector,
+	}
+}
+
+// SetShard restricts the reconciler to the Subscriptions which belong to the given shard.
+func (r *Reconciler) SetShard(shard sharding.Shard) {
+	r.shard = shard
+}
+
+// SetControllerOptions sets the workqueue options, e.g. the rate limiter, of the subscription controller.
+// It has to be called before SetupUnmanaged.
+func (r *Reconciler) SetControllerOptions(options controller.Options) {
+	r.controllerOptions = options
+}
+
+// SetupUnmanaged creates a controller under the client control.
+func (r *Reconciler) SetupUnmanaged(mgr ctrl.Manager) error {
+	options := r.controllerOptions
+	options.Reconciler = r
+	ctru, err := controller.NewUnmanaged(reconcilerName, mgr, options)
+	if err != nil {
+		r.namedLogger().Errorw("Failed to create unmanaged controller", "error", err)
+		return err
+	}
+
+	if err := ctru.Watch(source.Kind(mgr.GetCache(), &eventingv1alpha2.Subscription{}),
+		&handler.EnqueueRequestForObject{}); err != nil {
+		r.namedLogger().Errorw("Failed to setup watch for subscriptions", "error", err)
+		return err
+	}
+
+	if err := ctru.Watch(source.Kind(mgr.GetCache(), &corev1.Service{}),
+		sink.NewServiceEventHandler(r.Client, nil)); err != nil {
+		r.namedLogger().Errorw("Failed to setup watch for services", "error", err)
+		return err
+	}
+
+	if err := sink.WatchFunctions(mgr, ctru, r.Client, nil); err != nil {
+		r.namedLogger().Errorw("Failed to setup watch for functions", "error", err)
+		return err
+	}
+
+	go func(r *Reconciler, c controller.Controller) {
+		if err := c.Start(r.ctx); err != nil {
+			r.namedLogger().Fatalw("Failed to start controller", "error", err)
+		}
+	}(r, ctru)
+
+	return nil
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.namedLogger().Debugw("Received subscription v1alpha2 reconciliation request",
+		"namespace", req.Namespace, "name", req.Name)
+
+	// skip the subscriptions which are reconciled by another controller replica
+	if !r.shard.Owns(req.Namespace, req.Name) {
+		return ctrl.Result{}, nil
+	}
+
+	// fetch current subscription object and ensure the object was not deleted in the meantime
+	currentSubscription := &eventingv1alpha2.Subscription{}
+	if err := r.Client.Get(ctx, req.NamespacedName, currentSubscription); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// copy the subscription object, so we don't modify the source object
+	desiredSubscription := currentSubscription.DeepCopy()
+
+	// Bind fields to logger
+	log := backendutils.LoggerWithSubscription(r.namedLogger(), desiredSubscription)
+
+	if isInDeletion(desiredSubscription) {
+		// The object is being deleted
+		return r.handleSubscriptionDeletion(ctx, desiredSubscription, log)
+	}
+
+	defer func() {
+		r.collector.RecordSubscriptionReady(backendType, desiredSubscription.Namespace, desiredSubscription.Name,
+			desiredSubscription.Status.Ready)
+	}()
+
+	// stop the delivery of expired subscriptions
+	if desiredSubscription.IsExpired(time.Now()) {
+		return r.handleSubscriptionExpiry(ctx, desiredSubscription, log)
+	}
+
+	// The object is not being deleted, so if it does not have our finalizer,
+	// then lets add the finalizer and update the object.
+	if !containsFinalizer(desiredSubscription) {
+		return r.addFinalizer(ctx, desiredSubscription)
+	}
+
+	// update the cleanEventTypes, topics and consumer group in the subscription status, if changed
+	if err := r.syncEventTypes(desiredSubscription); err != nil {
+		if syncErr := r.syncSubscriptionStatus(ctx, desiredSubscription, err, log); syncErr != nil {
+			return ctrl.Result{}, syncErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	// resolve the sinkRef to the sink URL used by the backend on a copy, which is never updated in k8s,
+	// because the webhook rejects the sink together with the sinkRef
+	resolvedSubscription := desiredSubscription.DeepCopy()
+	resolvedSubscription.ResolveSink()
+	desiredSubscription.Status.SinkURI = resolvedSubscription.Status.SinkURI
+
+	// Check for valid sink
+	if err := r.sinkValidator.Validate(resolvedSubscription); err != nil {
+		if deleteErr := r.Backend.DeleteSubscription(desiredSubscription); deleteErr != nil {
+			log.Errorw("Failed to stop the Kafka consumer", "error", deleteErr)
+			return ctrl.Result{}, deleteErr
+		}
+		// No point in reconciling as the sink is invalid, return latest error to requeue the reconciliation request
+		if syncErr := r.syncSubscriptionStatus(ctx, desiredSubscription, err, log); syncErr != nil {
+			return ctrl.Result{}, syncErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Synchronize Kyma subscription to the Kafka backend
+	syncStart := time.Now()
+	syncSubErr := r.Backend.SyncSubscription(resolvedSubscription)
+	r.collector.RecordReconcilePhaseDuration(backendType, metrics.PhaseBackendSync, time.Since(syncStart))
+	if syncSubErr != nil {
+		if syncErr := r.syncSubscriptionStatus(ctx, desiredSubscription, syncSubErr, log); syncErr != nil {
+			return ctrl.Result{}, syncErr
+		}
+		return ctrl.Result{}, syncSubErr
+	}
+
+	// Update Subscription status and requeue the request when the subscription expires
+	return ctrl.Result{RequeueAfter: desiredSubscription.RequeueUntilExpiry(0, time.Now())},
+		r.syncSubscriptionStatus(ctx, desiredSubscription, nil, log)
+}
+
+// handleSubscriptionExpiry stops the delivery of an expired subscription by deleting its consumer group
+// and marks it as expired. The subscription itself is deleted instead if it requests so.
+func (r *Reconciler) handleSubscriptionExpiry(ctx context.Context,
+	subscription *eventingv1alpha2.Subscription, log *zap.SugaredLogger) (ctrl.Result, error) {
+	if subscription.Spec.DeleteOnExpiry {
+		if err := r.Delete(ctx, subscription); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, pkgerrors.MakeError(errFailedToDeleteExpiredSub, err)
+		}
+		log.Infow("Deleted expired subscription")
+		return ctrl.Result{}, nil
+	}
+
+	// the consumer group is deleted only once, when the subscription expires
+	expiredCondition := subscription.Status.FindCondition(eventingv1alpha2.ConditionExpired)
+	if expiredCondition == nil {
+		if err := r.Backend.DeleteSubscription(subscription); err != nil {
+			return ctrl.Result{}, pkgerrors.MakeError(errFailedToDeleteSub, err)
+		}
+		log.Infow("Stopped the delivery of expired subscription")
+		events.Normal(r.recorder, subscription, events.ReasonExpired,
+			"Subscription expired, deleted the Kafka consumer group %s", subscription.Status.Backend.KafkaConsumerGroup)
+	}
+
+	expiryTime, _ := subscription.ExpiryTime()
+	conditions := eventingv1alpha2.GetKafkaSubscriptionActiveCondition(subscription, errSubscriptionExpired)
+	if expiredCondition != nil {
+		conditions = append(conditions, *expiredCondition)
+	}
+	subscription.Status.Conditions = conditions
+	subscription.Status.SetConditionExpired(expiryTime)
+	subscription.Status.Ready = false
+	subscription.Status.Backend.KafkaTypes = nil
+	subscription.Status.Backend.KafkaConsumerGroup = ""
+	return ctrl.Result{}, r.updateSubscriptionStatus(ctx, subscription, log)
+}
+
+// handleSubscriptionDeletion deletes the Kafka consumer group and removes the finalizer if it is set.
+func (r *Reconciler) handleSubscriptionDeletion(ctx context.Context,
+	subscription *eventingv1alpha2.Subscription, log *zap.SugaredLogger) (ctrl.Result, error) {
+	if !utils.ContainsString(subscription.ObjectMeta.Finalizers, eventingv1alpha2.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.Backend.DeleteSubscription(subscription); err != nil {
+		deleteSubErr := pkgerrors.MakeError(errFailedToDeleteSub, err)
+		if !subscription.IsForceDeleteRequested() {
+			// if failed to delete the external dependency here, return with error
+			// so that it can be retried with backoff
+			subscription.Status.SetConditionCleanupFailed(deleteSubErr)
+			events.Warn(r.recorder, subscription, events.ReasonCleanupFailed,
+				"Delete Kafka consumer group failed: %v", deleteSubErr)
+			if syncErr := r.syncSubscriptionStatus(ctx, subscription, deleteSubErr, log); syncErr != nil {
+				return ctrl.Result{}, syncErr
+			}
+			return ctrl.Result{}, deleteSubErr
+		}
+		// the user requested to remove the finalizer anyway, record what could not be cleaned up
+		log.Warnw("Force deleting subscription without deleting the Kafka consumer group",
+			"group", subscription.Status.Backend.KafkaConsumerGroup, "error", deleteSubErr)
+		events.Warn(r.recorder, subscription, events.ReasonForceDeleted,
+			"Removed finalizer without deleting the Kafka consumer group %s: %v",
+			subscription.Status.Backend.KafkaConsumerGroup, deleteSubErr)
+	}
+
+	// remove the eventing finalizer from the list and update the subscription.
+	subscription.ObjectMeta.Finalizers = utils.RemoveString(subscription.ObjectMeta.Finalizers,
+		eventingv1alpha2.Finalizer)
+	if err := r.Update(ctx, subscription); err != nil {
+		return ctrl.Result{}, pkgerrors.MakeError(errFailedToUpdateFinalizers, err)
+	}
+	r.collector.RemoveSubscriptionReady(backendType, subscription.Namespace, subscription.Name)
+
+	return ctrl.Result{}, nil
+}
+
+// syncSubscriptionStatus syncs Subscription status and updates the k8s subscription.
+func (r *Reconciler) syncSubscriptionStatus(ctx context.Context,
+	desiredSubscription *eventingv1alpha2.Subscription, err error, log *zap.SugaredLogger) error {
+	// set ready state
+	desiredSubscription.Status.Ready = err == nil
+
+	// compile the desired conditions and keep the cleanup condition if it was set
+	conditions := eventingv1alpha2.GetKafkaSubscriptionActiveCondition(desiredSubscription, err)
+	if c := desiredSubscription.Status.FindCondition(eventingv1alpha2.ConditionCleanedUp); c != nil {
+		conditions = append(conditions, *c)
+	}
+	desiredSubscription.Status.Conditions = conditions
+
+	return r.updateSubscriptionStatus(ctx, desiredSubscription, log)
+}
+
+// updateSubscriptionStatus updates the subscription's status changes to k8s.
+func (r *Reconciler) updateSubscriptionStatus(ctx context.Context,
+	sub *eventingv1alpha2.Subscription, log *zap.SugaredLogger) error {
+	namespacedName := &k8stypes.NamespacedName{
+		Name:      sub.Name,
+		Namespace: sub.Namespace,
+	}
+
+	// fetch the latest subscription object, to avoid k8s conflict errors
+	actualSubscription := &eventingv1alpha2.Subscription{}
+	if err := r.Client.Get(ctx, *namespacedName, actualSubscription); err != nil {
+		return err
+	}
+
+	// copy new changes to the latest object
+	desiredSubscription := actualSubscription.DeepCopy()
+	desiredSubscription.Status = sub.Status
+	desiredSubscription.SyncExplanation()
+	desiredSubscription.SyncSummary(eventingv1alpha2.BackendTypeKafka)
+
+	// compare the status taking into consideration lastTransitionTime in conditions
+	if object.IsSubscriptionStatusEqual(actualSubscription.Status, desiredSubscription.Status) {
+		return nil
+	}
+
+	updateStart := time.Now()
+	err := r.Status().Update(ctx, desiredSubscription)
+	r.collector.RecordReconcilePhaseDuration(backendType, metrics.PhaseStatusUpdate, time.Since(updateStart))
+	if err != nil {
+		events.Warn(r.recorder, desiredSubscription, events.ReasonUpdateFailed,
+			"Update Subscription status failed %s", desiredSubscription.Name)
+		return pkgerrors.MakeError(errFailedToUpdateStatus, err)
+	}
+	events.Normal(r.recorder, desiredSubscription, events.ReasonUpdate,
+		"Update Subscription status succeeded %s", desiredSubscription.Name)
+	log.Debugw("Updated subscription status",
+		"oldStatus", actualSubscription.Status, "newStatus", desiredSubscription.Status)
+
+	return nil
+}
+
+// addFinalizer appends the eventing finalizer to the subscription and updates it in k8s.
+func (r *Reconciler) addFinalizer(ctx context.Context, sub *eventingv1alpha2.Subscription) (ctrl.Result, error) {
+	sub.ObjectMeta.Finalizers = append(sub.ObjectMeta.Finalizers, eventingv1alpha2.Finalizer)
+
+	// update the subscription's finalizers in k8s
+	if err := r.Update(ctx, sub); err != nil {
+		return ctrl.Result{}, pkgerrors.MakeError(errFailedToUpdateFinalizers, err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// syncEventTypes sets the latest cleaned types, topics and consumer group to the subscription status.
+func (r *Reconciler) syncEventTypes(desiredSubscription *eventingv1alpha2.Subscription) error {
+	cleanedTypes := jetstream.GetCleanEventTypes(desiredSubscription, r.cleaner)
+	if !reflect.DeepEqual(desiredSubscription.Status.Types, cleanedTypes) {
+		desiredSubscription.Status.Types = cleanedTypes
+	}
+
+	kafkaTypes, err := r.Backend.GetKafkaTypes(desiredSubscription)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(desiredSubscription.Status.Backend.KafkaTypes, kafkaTypes) {
+		desiredSubscription.Status.Backend.KafkaTypes = kafkaTypes
+	}
+	desiredSubscription.Status.Backend.KafkaConsumerGroup = r.Backend.ConsumerGroup(desiredSubscription)
+	return nil
+}
+
+func (r *Reconciler) namedLogger() *zap.SugaredLogger {
+	return r.logger.WithContext().Named(reconcilerName)
+}
@@ -0,0 +1,295 @@
+package subscriptiontemplate
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"text/template"
+
+	"go.uber.org/zap"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/controllers/events"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+const reconcilerName = "subscription-template-reconciler"
+
+// Reconciler creates, updates and deletes the Subscriptions of a SubscriptionTemplate
+// in the Namespaces matching its Namespace selector.
+type Reconciler struct {
+	client.Client
+	logger   *logger.Logger
+	recorder record.EventRecorder
+}
+
+func NewReconciler(client client.Client, logger *logger.Logger, recorder record.EventRecorder) *Reconciler {
+	return &Reconciler{
+		Client:   client,
+		logger:   logger,
+		recorder: recorder,
+	}
+}
+
+// templateData is the data available to the templated fields of a SubscriptionTemplate.
+type templateData struct {
+	Namespace string
+}
+
+// +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=subscriptiontemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=subscriptiontemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=subscriptions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	subTemplate := &eventingv1alpha2.SubscriptionTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, subTemplate); err != nil {
+		// the created Subscriptions are garbage collected by their owner reference
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	log := r.namedLogger().With("name", subTemplate.Name)
+
+	namespaces, syncErr := r.syncSubscriptions(ctx, subTemplate, log)
+	if syncErr != nil {
+		events.Warn(r.recorder, subTemplate, events.ReasonUpdateFailed, "Sync Subscriptions failed: %v", syncErr)
+	}
+	if err := r.updateStatus(ctx, subTemplate, namespaces, syncErr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, syncErr
+}
+
+// syncSubscriptions makes sure that every selected Namespace has an up-to-date Subscription created from the
+// template and that no other Namespace has one. It returns the Namespaces with a Subscription of the template.
+func (r *Reconciler) syncSubscriptions(ctx context.Context, subTemplate *eventingv1alpha2.SubscriptionTemplate,
+	log *zap.SugaredLogger) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&subTemplate.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid namespace selector: %v", err)
+	}
+	namespaceList := &corev1.NamespaceList{}
+	if err = r.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, xerrors.Errorf("failed to list namespaces: %v", err)
+	}
+
+	selected := make(map[string]bool, len(namespaceList.Items))
+	var namespaces []string
+	var syncErrs []error
+	for _, ns := range namespaceList.Items {
+		if ns.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		selected[ns.Name] = true
+		if err = r.syncSubscription(ctx, subTemplate, ns.Name, log); err != nil {
+			syncErrs = append(syncErrs, err)
+			continue
+		}
+		namespaces = append(namespaces, ns.Name)
+	}
+
+	// delete the Subscriptions in the Namespaces which are not selected anymore
+	subList := &eventingv1alpha2.SubscriptionList{}
+	if err = r.List(ctx, subList, client.MatchingLabels{eventingv1alpha2.SubscriptionTemplateLabel: subTemplate.Name}); err != nil {
+		return namespaces, xerrors.Errorf("failed to list subscriptions: %v", err)
+	}
+	for i := range subList.Items {
+		sub := &subList.Items[i]
+		if selected[sub.Namespace] || !metav1.IsControlledBy(sub, subTemplate) {
+			continue
+		}
+		if err = r.Delete(ctx, sub); client.IgnoreNotFound(err) != nil {
+			syncErrs = append(syncErrs, xerrors.Errorf("failed to delete subscription %s/%s: %v",
+				sub.Namespace, sub.Name, err))
+			continue
+		}
+		log.Infow("Deleted subscription of unselected namespace", "namespace", sub.Namespace)
+	}
+
+	sort.Strings(namespaces)
+	if len(syncErrs) > 0 {
+		return namespaces, xerrors.Errorf("%d subscription(s) failed to sync, first error: %v",
+			len(syncErrs), syncErrs[0])
+	}
+	return namespaces, nil
+}
+
+// syncSubscription creates or updates the Subscription of the template in the given Namespace.
+func (r *Reconciler) syncSubscription(ctx context.Context, subTemplate *eventingv1alpha2.SubscriptionTemplate,
+	namespace string, log *zap.SugaredLogger) error {
+	desired, err := renderSubscription(subTemplate, namespace)
+	if err != nil {
+		return err
+	}
+	// apply the defaults of the webhook, otherwise the defaulted fields of the stored Subscription differ
+	// from the template and it is updated on every reconciliation
+	desired.Default()
+	if err = controllerutil.SetControllerReference(subTemplate, desired, r.Scheme()); err != nil {
+		return xerrors.Errorf("failed to set owner reference: %v", err)
+	}
+
+	current := &eventingv1alpha2.Subscription{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: desired.Name}, current)
+	if k8serrors.IsNotFound(err) {
+		if err = r.Create(ctx, desired); err != nil {
+			return xerrors.Errorf("failed to create subscription %s/%s: %v", namespace, desired.Name, err)
+		}
+		log.Infow("Created subscription", "namespace", namespace)
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("failed to get subscription %s/%s: %v", namespace, desired.Name, err)
+	}
+
+	// never take over a Subscription which was not created from this template
+	if !metav1.IsControlledBy(current, subTemplate) {
+		return xerrors.Errorf("subscription %s/%s already exists and is not managed by the template",
+			namespace, desired.Name)
+	}
+
+	updated := current.DeepCopy()
+	updated.Spec = desired.Spec
+	for k, v := range desired.Labels {
+		if updated.Labels == nil {
+			updated.Labels = map[string]string{}
+		}
+		updated.Labels[k] = v
+	}
+	for k, v := range desired.Annotations {
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		updated.Annotations[k] = v
+	}
+	if reflect.DeepEqual(current, updated) {
+		return nil
+	}
+	if err = r.Update(ctx, updated); err != nil {
+		return xerrors.Errorf("failed to update subscription %s/%s: %v", namespace, desired.Name, err)
+	}
+	log.Infow("Updated subscription", "namespace", namespace)
+	return nil
+}
+
+// updateStatus updates the SubscriptionTemplate status if it changed.
+func (r *Reconciler) updateStatus(ctx context.Context, subTemplate *eventingv1alpha2.SubscriptionTemplate,
+	namespaces []string, syncErr error) error {
+	condition := eventingv1alpha2.MakeCondition(eventingv1alpha2.ConditionSubscriptionsSynced,
+		eventingv1alpha2.ConditionReasonSubscriptionsSynced, corev1.ConditionTrue, "")
+	if syncErr != nil {
+		condition = eventingv1alpha2.MakeCondition(eventingv1alpha2.ConditionSubscriptionsSynced,
+			eventingv1alpha2.ConditionReasonSubscriptionsSyncFailed, corev1.ConditionFalse, syncErr.Error())
+	}
+
+	desired := subTemplate.DeepCopy()
+	desired.Status.Ready = syncErr == nil
+	desired.Status.Namespaces = namespaces
+	desired.Status.Conditions = []eventingv1alpha2.Condition{condition}
+	if len(subTemplate.Status.Conditions) == 1 &&
+		eventingv1alpha2.ConditionEquals(subTemplate.Status.Conditions[0], condition) {
+		desired.Status.Conditions = subTemplate.Status.Conditions
+	}
+	if reflect.DeepEqual(subTemplate.Status, desired.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, desired); err != nil {
+		return xerrors.Errorf("failed to update subscription template status: %v", err)
+	}
+	return nil
+}
+
+// renderSubscription returns the Subscription of the template for the given Namespace.
+func renderSubscription(subTemplate *eventingv1alpha2.SubscriptionTemplate,
+	namespace string) (*eventingv1alpha2.Subscription, error) {
+	data := templateData{Namespace: namespace}
+	spec := subTemplate.Spec.Template.Spec.DeepCopy()
+
+	var err error
+	if spec.Sink, err = render(spec.Sink, data); err != nil {
+		return nil, xerrors.Errorf("invalid sink template: %v", err)
+	}
+	if spec.Source, err = render(spec.Source, data); err != nil {
+		return nil, xerrors.Errorf("invalid source template: %v", err)
+	}
+	for i := range spec.Types {
+		if spec.Types[i], err = render(spec.Types[i], data); err != nil {
+			return nil, xerrors.Errorf("invalid type template: %v", err)
+		}
+	}
+
+	labels := map[string]string{}
+	for k, v := range subTemplate.Spec.Template.Metadata.Labels {
+		labels[k] = v
+	}
+	labels[eventingv1alpha2.SubscriptionTemplateLabel] = subTemplate.Name
+
+	var annotations map[string]string
+	if len(subTemplate.Spec.Template.Metadata.Annotations) > 0 {
+		annotations = make(map[string]string, len(subTemplate.Spec.Template.Metadata.Annotations))
+		for k, v := range subTemplate.Spec.Template.Metadata.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	return &eventingv1alpha2.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        subTemplate.Name,
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: *spec,
+	}, nil
+}
+
+// render executes the given Go template text with the given data.
+func render(text string, data templateData) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(reconcilerName).
+		For(&eventingv1alpha2.SubscriptionTemplate{}).
+		Owns(&eventingv1alpha2.Subscription{}).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.mapNamespaceToTemplates)).
+		Complete(r)
+}
+
+// mapNamespaceToTemplates enqueues all SubscriptionTemplates when a Namespace or its labels change.
+func (r *Reconciler) mapNamespaceToTemplates(ctx context.Context, _ client.Object) []reconcile.Request {
+	templates := &eventingv1alpha2.SubscriptionTemplateList{}
+	if err := r.List(ctx, templates); err != nil {
+		r.namedLogger().Errorw("Failed to list subscription templates", "error", err)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(templates.Items))
+	for _, t := range templates.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: t.Name}})
+	}
+	return requests
+}
+
+func (r *Reconciler) namedLogger() *zap.SugaredLogger {
+	return r.logger.WithContext().Named(reconcilerName)
+}
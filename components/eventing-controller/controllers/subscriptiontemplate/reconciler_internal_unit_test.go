@@ -0,0 +1,170 @@
+package subscriptiontemplate
+
+import (
+	"context"
+	"testing"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+const templateName = "order-created"
+
+func Test_renderSubscription(t *testing.T) {
+	// given
+	subTemplate := newTemplate()
+
+	// when
+	sub, err := renderSubscription(subTemplate, "shop")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, templateName, sub.Name)
+	require.Equal(t, "shop", sub.Namespace)
+	require.Equal(t, "http://orders.shop.svc.cluster.local", sub.Spec.Sink)
+	require.Equal(t, "shop", sub.Spec.Source)
+	require.Equal(t, []string{"order.created.v1", "shop.order.updated.v1"}, sub.Spec.Types)
+	require.Equal(t, templateName, sub.Labels[eventingv1alpha2.SubscriptionTemplateLabel])
+	require.Equal(t, "webshop", sub.Labels["product"])
+
+	// and an invalid template is rejected
+	subTemplate.Spec.Template.Spec.Sink = "http://{{ .Unknown }}"
+	_, err = renderSubscription(subTemplate, "shop")
+	require.Error(t, err)
+}
+
+func Test_Reconcile(t *testing.T) {
+	// given
+	ctx := context.Background()
+	subTemplate := newTemplate()
+	selectedNamespace := newNamespace("shop", map[string]string{"product": "webshop"})
+	unselectedNamespace := newNamespace("other", nil)
+	conflictNamespace := newNamespace("conflict", map[string]string{"product": "webshop"})
+
+	staleSub, err := renderSubscription(subTemplate, unselectedNamespace.Name)
+	require.NoError(t, err)
+	unmanagedSub := &eventingv1alpha2.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: templateName, Namespace: conflictNamespace.Name},
+	}
+
+	r := newTestReconciler(t, subTemplate, selectedNamespace, unselectedNamespace, conflictNamespace, unmanagedSub)
+	require.NoError(t, controllerutil.SetControllerReference(subTemplate, staleSub, r.Scheme()))
+	require.NoError(t, r.Create(ctx, staleSub))
+
+	// when
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: templateName}})
+
+	// then
+	// the subscription which is not managed by the template is reported
+	require.ErrorContains(t, err, "not managed by the template")
+
+	// the subscription is created in the selected namespace
+	sub := &eventingv1alpha2.Subscription{}
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Namespace: "shop", Name: templateName}, sub))
+	require.Equal(t, "http://orders.shop.svc.cluster.local", sub.Spec.Sink)
+	require.True(t, metav1.IsControlledBy(sub, subTemplate))
+
+	// the subscription of the unselected namespace is deleted
+	err = r.Get(ctx, types.NamespacedName{Namespace: "other", Name: templateName}, sub)
+	require.True(t, k8serrors.IsNotFound(err))
+
+	// the unmanaged subscription is left untouched
+	require.NoError(t, r.Get(ctx, client.ObjectKeyFromObject(unmanagedSub), sub))
+	require.Empty(t, sub.Spec.Sink)
+
+	// the status reflects the sync result
+	gotTemplate := &eventingv1alpha2.SubscriptionTemplate{}
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Name: templateName}, gotTemplate))
+	require.False(t, gotTemplate.Status.Ready)
+	require.Equal(t, []string{"shop"}, gotTemplate.Status.Namespaces)
+	require.Len(t, gotTemplate.Status.Conditions, 1)
+	require.Equal(t, eventingv1alpha2.ConditionReasonSubscriptionsSyncFailed, gotTemplate.Status.Conditions[0].Reason)
+}
+
+func Test_Reconcile_Unchanged(t *testing.T) {
+	// given a client which defaults the Subscriptions like the webhook
+	ctx := context.Background()
+	subTemplate := newTemplate()
+	updates := 0
+	r := newTestReconciler(t, subTemplate, newNamespace("shop", map[string]string{"product": "webshop"}))
+	r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if sub, ok := obj.(*eventingv1alpha2.Subscription); ok {
+				sub.Default()
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if sub, ok := obj.(*eventingv1alpha2.Subscription); ok {
+				updates++
+				sub.Default()
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: templateName}}
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	// when
+	_, err = r.Reconcile(ctx, req)
+
+	// then the defaulted subscription is not updated again
+	require.NoError(t, err)
+	require.Zero(t, updates)
+}
+
+func newTemplate() *eventingv1alpha2.SubscriptionTemplate {
+	return &eventingv1alpha2.SubscriptionTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: templateName, UID: "template-uid"},
+		Spec: eventingv1alpha2.SubscriptionTemplateSpec{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"product": "webshop"}},
+			Template: eventingv1alpha2.SubscriptionTemplateObject{
+				Metadata: eventingv1alpha2.SubscriptionTemplateMetadata{
+					Labels: map[string]string{"product": "webshop"},
+				},
+				Spec: eventingv1alpha2.SubscriptionSpec{
+					Sink:   "http://orders.{{ .Namespace }}.svc.cluster.local",
+					Source: "{{ .Namespace }}",
+					Types:  []string{"order.created.v1", "{{ .Namespace }}.order.updated.v1"},
+				},
+			},
+		},
+	}
+}
+
+func newNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newTestReconciler(t *testing.T, objs ...client.Object) *Reconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, eventingv1alpha2.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&eventingv1alpha2.SubscriptionTemplate{}).
+		Build()
+
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+
+	return NewReconciler(fakeClient, l, record.NewFakeRecorder(10))
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+)
+
+const (
+	dashboardUID   = "kyma-eventing-controller"
+	dashboardTitle = "Kyma / Eventing / Controller"
+
+	// latencyQuantile is the quantile shown by the panels of the histograms.
+	latencyQuantile = 0.95
+
+	// the panels are laid out in two columns on the 24 columns wide grid of Grafana.
+	gridWidth   = 24
+	panelWidth  = 12
+	panelHeight = 8
+	rowHeight   = 1
+
+	datasourceVariable = "${datasource}"
+)
+
+// panelSpec defines a panel showing a single metric. The query is derived from the type of the metric:
+// the rate of a counter, the latencyQuantile of a histogram or the value of a gauge.
+type panelSpec struct {
+	title  string
+	metric string
+	// by are the labels the series are aggregated by, they must be labels of the metric.
+	by []string
+	// aggregation aggregates the series of a gauge, it defaults to sum.
+	aggregation string
+	// unit is the Grafana unit of the values.
+	unit string
+}
+
+// rowSpec groups panels under a row.
+type rowSpec struct {
+	title  string
+	panels []panelSpec
+}
+
+// otherRowTitle is the title of the row showing the metrics without a panelSpec, so that new metrics appear
+// on the dashboard until they are given a panel.
+const otherRowTitle = "Other metrics"
+
+// rows are the rows of the dashboard. The generator fails if they reference a metric or label which does not
+// exist, so that the dashboard is updated together with the metrics.
+//
+//nolint:gochecknoglobals // dashboard definition
+var rows = []rowSpec{
+	{
+		title: "Delivery rates",
+		panels: []panelSpec{
+			{
+				title:  "Deliveries per namespace",
+				metric: "eventing_ec_nats_delivery_per_namespace_total",
+				by:     []string{"subscription_namespace", "response_code"},
+				unit:   "ops",
+			},
+			{
+				title:  "Delivery failures per namespace",
+				metric: "eventing_ec_nats_delivery_failures_per_namespace_total",
+				by:     []string{"subscription_namespace", "reason"},
+				unit:   "ops",
+			},
+			{
+				title:  "Deliveries per subscription",
+				metric: "eventing_ec_nats_delivery_per_subscription_total",
+				by:     []string{"subscription_name", "response_code"},
+				unit:   "ops",
+			},
+			{
+				title:  "Delivery failures per subscription",
+				metric: "eventing_ec_nats_delivery_failures_total",
+				by:     []string{"subscription_name", "reason"},
+				unit:   "ops",
+			},
+		},
+	},
+	{
+		title: "Latency",
+		panels: []panelSpec{
+			{
+				title:  "Dispatch duration per subscription",
+				metric: "eventing_ec_nats_subscriber_dispatch_duration_seconds",
+				by:     []string{"subscription_name"},
+				unit:   "s",
+			},
+			{
+				title:  "Reconcile phase duration",
+				metric: "eventing_ec_subscription_reconcile_phase_duration_seconds",
+				by:     []string{"eventing_backend", "phase"},
+				unit:   "s",
+			},
+		},
+	},
+	{
+		title: "Backlog",
+		panels: []panelSpec{
+			{
+				title:  "Pending messages per namespace",
+				metric: "eventing_ec_jetstream_pending_messages_per_namespace",
+				by:     []string{"subscription_namespace"},
+				unit:   "short",
+			},
+			{
+				title:  "Redelivered messages per consumer",
+				metric: "eventing_ec_jetstream_consumer_redelivered_messages",
+				by:     []string{"consumer_name"},
+				unit:   "short",
+			},
+			{
+				title:  "Stream messages",
+				metric: "eventing_ec_jetstream_stream_messages",
+				by:     []string{"stream_name"},
+				unit:   "short",
+			},
+			{
+				title:  "Stream bytes",
+				metric: "eventing_ec_jetstream_stream_bytes",
+				by:     []string{"stream_name"},
+				unit:   "bytes",
+			},
+		},
+	},
+	{
+		title: "Connection health",
+		panels: []panelSpec{
+			{
+				title:       "NATS connection status",
+				metric:      "eventing_ec_nats_connection_status",
+				aggregation: "min",
+				unit:        "short",
+			},
+			{
+				title:  "NATS reconnects",
+				metric: "eventing_ec_nats_reconnects_total",
+				unit:   "ops",
+			},
+			{
+				title:       "NATS round trip time",
+				metric:      "eventing_ec_nats_rtt_seconds",
+				aggregation: "max",
+				unit:        "s",
+			},
+			{
+				title:       "Last NATS disconnect reason",
+				metric:      "eventing_ec_nats_last_disconnect_reason",
+				by:          []string{"reason"},
+				aggregation: "max",
+				unit:        "short",
+			},
+			{
+				title:  "Subscriptions by ready state",
+				metric: "eventing_ec_subscriptions",
+				by:     []string{"eventing_backend", "ready"},
+				unit:   "short",
+			},
+		},
+	},
+}
+
+type dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	Editable      bool       `json:"editable"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []panel    `json:"panels"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Query string `json:"query"`
+}
+
+type panel struct {
+	ID          int          `json:"id"`
+	Type        string       `json:"type"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	GridPos     gridPos      `json:"gridPos"`
+	Datasource  *datasource  `json:"datasource,omitempty"`
+	FieldConfig *fieldConfig `json:"fieldConfig,omitempty"`
+	Targets     []target     `json:"targets,omitempty"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type fieldConfig struct {
+	Defaults fieldDefaults `json:"defaults"`
+}
+
+type fieldDefaults struct {
+	Unit string `json:"unit"`
+}
+
+type target struct {
+	RefID        string      `json:"refId"`
+	Datasource   *datasource `json:"datasource"`
+	Expr         string      `json:"expr"`
+	LegendFormat string      `json:"legendFormat"`
+}
+
+// generate returns the dashboard of the rows, followed by a row with the described metrics without a panelSpec.
+func generate(descriptors []metrics.Descriptor) (dashboard, error) {
+	byName := make(map[string]metrics.Descriptor, len(descriptors))
+	for _, d := range descriptors {
+		byName[d.Name] = d
+	}
+
+	used := map[string]bool{}
+	allRows := make([]rowSpec, 0, len(rows)+1)
+	for _, row := range rows {
+		for _, spec := range row.panels {
+			used[spec.metric] = true
+		}
+		allRows = append(allRows, row)
+	}
+	other := rowSpec{title: otherRowTitle}
+	for _, d := range descriptors {
+		if !used[d.Name] {
+			other.panels = append(other.panels, panelSpec{title: d.Name, metric: d.Name, unit: "short"})
+		}
+	}
+	if len(other.panels) > 0 {
+		allRows = append(allRows, other)
+	}
+
+	b := &builder{}
+	for _, row := range allRows {
+		b.addRow(row.title)
+		for _, spec := range row.panels {
+			descriptor, ok := byName[spec.metric]
+			if !ok {
+				return dashboard{}, fmt.Errorf("panel %q shows the unknown metric %s", spec.title, spec.metric)
+			}
+			if err := b.addPanel(spec, descriptor); err != nil {
+				return dashboard{}, err
+			}
+		}
+	}
+
+	return dashboard{
+		UID:           dashboardUID,
+		Title:         dashboardTitle,
+		Tags:          []string{"kyma", "eventing"},
+		Editable:      true,
+		SchemaVersion: 39,
+		Refresh:       "30s",
+		Time:          timeRange{From: "now-1h", To: "now"},
+		Templating: templating{List: []variable{
+			{Name: "datasource", Label: "Datasource", Type: "datasource", Query: "prometheus"},
+		}},
+		Panels: b.panels,
+	}, nil
+}
+
+// builder lays out the panels in rows of two columns.
+type builder struct {
+	panels []panel
+	// column is the column of the next panel, y is the top of the current line.
+	column, y int
+}
+
+func (b *builder) addRow(title string) {
+	if b.column > 0 {
+		b.y += panelHeight
+		b.column = 0
+	}
+	b.panels = append(b.panels, panel{
+		ID:      len(b.panels) + 1,
+		Type:    "row",
+		Title:   title,
+		GridPos: gridPos{H: rowHeight, W: gridWidth, X: 0, Y: b.y},
+	})
+	b.y += rowHeight
+}
+
+func (b *builder) addPanel(spec panelSpec, descriptor metrics.Descriptor) error {
+	expr, err := query(spec, descriptor)
+	if err != nil {
+		return err
+	}
+	legend := make([]string, 0, len(spec.by))
+	for _, label := range spec.by {
+		legend = append(legend, fmt.Sprintf("{{%s}}", label))
+	}
+	ds := &datasource{Type: "prometheus", UID: datasourceVariable}
+	b.panels = append(b.panels, panel{
+		ID:          len(b.panels) + 1,
+		Type:        "timeseries",
+		Title:       spec.title,
+		Description: descriptor.Help,
+		GridPos:     gridPos{H: panelHeight, W: panelWidth, X: b.column * panelWidth, Y: b.y},
+		Datasource:  ds,
+		FieldConfig: &fieldConfig{Defaults: fieldDefaults{Unit: spec.unit}},
+		Targets: []target{
+			{RefID: "A", Datasource: ds, Expr: expr, LegendFormat: strings.Join(legend, " ")},
+		},
+	})
+	if b.column++; b.column*panelWidth >= gridWidth {
+		b.column = 0
+		b.y += panelHeight
+	}
+	return nil
+}
+
+// query returns the PromQL query of the panel.
+func query(spec panelSpec, descriptor metrics.Descriptor) (string, error) {
+	for _, label := range spec.by {
+		if !descriptor.HasLabel(label) {
+			return "", fmt.Errorf("panel %q aggregates by the unknown label %s of the metric %s",
+				spec.title, label, descriptor.Name)
+		}
+	}
+	switch descriptor.Type {
+	case metrics.TypeCounter:
+		return aggregate("sum", spec.by, fmt.Sprintf("rate(%s[$__rate_interval])", descriptor.Name)), nil
+	case metrics.TypeHistogram:
+		rate := fmt.Sprintf("rate(%s_bucket[$__rate_interval])", descriptor.Name)
+		return fmt.Sprintf("histogram_quantile(%g, %s)", latencyQuantile,
+			aggregate("sum", append([]string{"le"}, spec.by...), rate)), nil
+	case metrics.TypeGauge:
+		aggregation := spec.aggregation
+		if aggregation == "" {
+			aggregation = "sum"
+		}
+		return aggregate(aggregation, spec.by, descriptor.Name), nil
+	default:
+		return "", fmt.Errorf("metric %s has the unsupported type %q", descriptor.Name, descriptor.Type)
+	}
+}
+
+// aggregate returns the PromQL aggregation of the expression by the given labels.
+func aggregate(aggregation string, by []string, expr string) string {
+	if len(by) == 0 {
+		return fmt.Sprintf("%s(%s)", aggregation, expr)
+	}
+	return fmt.Sprintf("%s by (%s) (%s)", aggregation, strings.Join(by, ", "), expr)
+}
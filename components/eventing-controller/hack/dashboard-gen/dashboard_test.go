@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+)
+
+// dashboardFile is the generated dashboard, relative to this package.
+const dashboardFile = "../../config/dashboards/eventing-controller.json"
+
+func Test_generate_UpToDate(t *testing.T) {
+	// given
+	descriptors, err := metrics.Descriptors()
+	require.NoError(t, err)
+
+	// when
+	d, err := generate(descriptors)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, write(&buf, d))
+
+	// then
+	want, err := os.ReadFile(dashboardFile)
+	require.NoError(t, err)
+	require.Equal(t, string(want), buf.String(),
+		"the dashboard is outdated, run `make update_dashboard` to generate it again")
+}
+
+func Test_generate(t *testing.T) {
+	// given the descriptors of all the metrics with panels and an additional metric
+	descriptors, err := metrics.Descriptors()
+	require.NoError(t, err)
+	descriptors = append(descriptors, metrics.Descriptor{
+		Name: "eventing_ec_new_total", Help: "A new metric", Type: metrics.TypeCounter,
+	})
+
+	// when
+	d, err := generate(descriptors)
+
+	// then the new metric is shown in the last row
+	require.NoError(t, err)
+	last := d.Panels[len(d.Panels)-1]
+	require.Equal(t, "eventing_ec_new_total", last.Title)
+	require.Equal(t, "A new metric", last.Description)
+	require.Equal(t, "sum(rate(eventing_ec_new_total[$__rate_interval]))", last.Targets[0].Expr)
+	for _, p := range d.Panels {
+		if p.Type == "row" {
+			require.Zero(t, p.GridPos.X)
+		}
+	}
+}
+
+func Test_generate_Errors(t *testing.T) {
+	descriptors, err := metrics.Descriptors()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		givenDescriptors func() []metrics.Descriptor
+		wantError        string
+	}{
+		{
+			name: "renamed metric",
+			givenDescriptors: func() []metrics.Descriptor {
+				renamed := append([]metrics.Descriptor{}, descriptors...)
+				for i := range renamed {
+					if renamed[i].Name == "eventing_ec_jetstream_stream_bytes" {
+						renamed[i].Name = "eventing_ec_jetstream_stream_size_bytes"
+					}
+				}
+				return renamed
+			},
+			wantError: `panel "Stream bytes" shows the unknown metric eventing_ec_jetstream_stream_bytes`,
+		},
+		{
+			name: "renamed label",
+			givenDescriptors: func() []metrics.Descriptor {
+				renamed := append([]metrics.Descriptor{}, descriptors...)
+				for i := range renamed {
+					if renamed[i].Name == "eventing_ec_jetstream_stream_bytes" {
+						renamed[i].Labels = []string{"stream"}
+					}
+				}
+				return renamed
+			},
+			wantError: `panel "Stream bytes" aggregates by the unknown label stream_name ` +
+				`of the metric eventing_ec_jetstream_stream_bytes`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := generate(tc.givenDescriptors())
+			require.EqualError(t, err, tc.wantError)
+		})
+	}
+}
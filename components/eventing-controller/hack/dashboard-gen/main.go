@@ -0,0 +1,50 @@
+// The dashboard-gen command generates the Grafana dashboard of the eventing-controller from the descriptors of
+// its metrics, so that the dashboard follows the renamed and added metrics.
+//
+//	go run ./hack/dashboard-gen -o config/dashboards/eventing-controller.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+)
+
+func main() {
+	output := flag.String("o", "-", "The file to write the dashboard JSON to, - for stdout")
+	flag.Parse()
+
+	descriptors, err := metrics.Descriptors()
+	if err != nil {
+		log.Fatalf("Failed to describe the metrics: %v", err)
+	}
+	d, err := generate(descriptors)
+	if err != nil {
+		log.Fatalf("Failed to generate the dashboard: %v", err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Failed to create the output file: %v", err)
+		}
+		defer func() { _ = file.Close() }()
+		out = file
+	}
+	if err := write(out, d); err != nil {
+		log.Fatalf("Failed to write the dashboard: %v", err)
+	}
+}
+
+// write writes the dashboard as indented JSON.
+func write(w io.Writer, d dashboard) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(d)
+}
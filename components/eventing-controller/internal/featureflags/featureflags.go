@@ -1,5 +1,14 @@
 package featureflags
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
 //nolint:gochecknoglobals // This is global only inside the package.
 var f = &flags{
 	eventingWebhookAuthEnabled: true,
@@ -31,3 +40,87 @@ func SetNATSProvisioningEnabled(enabled bool) {
 func IsNATSProvisioningEnabled() bool {
 	return f.natsProvisioningEnabled
 }
+
+// Flag is the name of an experimental capability, which is enabled per cluster in the feature flags ConfigMap.
+type Flag string
+
+const (
+	// PullConsumers gates dispatching the events of the JetStream backend through pull consumers.
+	PullConsumers Flag = "pull-consumers"
+	// BatchDispatch gates dispatching multiple events to a sink in one request.
+	BatchDispatch Flag = "batch-dispatch"
+	// DualPublish gates publishing the events to both backends during a backend migration.
+	DualPublish Flag = "dual-publish"
+)
+
+// Flags returns all the experimental flags.
+func Flags() []Flag {
+	return []Flag{PullConsumers, BatchDispatch, DualPublish}
+}
+
+//nolint:gochecknoglobals // This is global only inside the package.
+var experimental = &experimentalFlags{enabled: map[Flag]bool{}}
+
+// experimentalFlags are the states of the experimental flags, which are changed at runtime.
+type experimentalFlags struct {
+	mutex   sync.RWMutex
+	enabled map[Flag]bool
+}
+
+// SetEnabledFlags enables the given experimental flags and disables all the others.
+func SetEnabledFlags(enabled map[Flag]bool) {
+	experimental.mutex.Lock()
+	defer experimental.mutex.Unlock()
+	experimental.enabled = make(map[Flag]bool, len(enabled))
+	for flag, on := range enabled {
+		experimental.enabled[flag] = on
+	}
+}
+
+// IsEnabled returns true if the given experimental flag is enabled, otherwise returns false.
+func IsEnabled(flag Flag) bool {
+	experimental.mutex.RLock()
+	defer experimental.mutex.RUnlock()
+	return experimental.enabled[flag]
+}
+
+// States returns the states of all the experimental flags.
+func States() map[Flag]bool {
+	states := make(map[Flag]bool, len(Flags()))
+	for _, flag := range Flags() {
+		states[flag] = IsEnabled(flag)
+	}
+	return states
+}
+
+// Parse returns the states of the experimental flags of the given ConfigMap data, which maps the flags to booleans.
+// The flags which are not set are disabled. It returns an error for each unknown flag and invalid boolean,
+// which are disabled as well.
+func Parse(data map[string]string) (map[Flag]bool, error) {
+	known := map[Flag]bool{}
+	for _, flag := range Flags() {
+		known[flag] = true
+	}
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	states := make(map[Flag]bool, len(known))
+	var errs []error
+	for _, key := range keys {
+		flag := Flag(key)
+		if !known[flag] {
+			errs = append(errs, fmt.Errorf("unknown feature flag %q", key))
+			continue
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(data[key]))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("feature flag %q must be true or false, got %q", key, data[key]))
+			continue
+		}
+		states[flag] = enabled
+	}
+	return states, errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// levels are the level of a logger and the level overrides of its components.
+type levels struct {
+	level      zapcore.Level
+	components []componentLevel
+}
+
+// componentLevel is the level of the named loggers of a component.
+type componentLevel struct {
+	// words are the words of the component name, split at dots and hyphens.
+	words []string
+	level zapcore.Level
+}
+
+// parseComponentLevels parses a comma separated list of component=level pairs.
+func parseComponentLevels(componentLevels string) ([]componentLevel, error) {
+	var components []componentLevel
+	for _, pair := range strings.Split(componentLevels, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, level, ok := strings.Cut(pair, "=")
+		words := nameWords(name)
+		if !ok || len(words) == 0 {
+			return nil, fmt.Errorf("component level %q must be a component=level pair", strings.TrimSpace(pair))
+		}
+		zapLevel, err := mapLevel(strings.TrimSpace(level))
+		if err != nil {
+			return nil, fmt.Errorf("component level %q: %w", strings.TrimSpace(pair), err)
+		}
+		components = append(components, componentLevel{words: words, level: zapLevel})
+	}
+	return components, nil
+}
+
+// lowest returns the lowest level of the logger and its components.
+func (l *levels) lowest() zapcore.Level {
+	lowest := l.level
+	for _, component := range l.components {
+		if component.level < lowest {
+			lowest = component.level
+		}
+	}
+	return lowest
+}
+
+// levelOf returns the level of the logger with the given name. A component matches the name if its words are part
+// of the words of the name. If several components match, the one matching the most specific, last part of the name
+// wins, such as the level of "dispatcher" over the level of "jetstream" for the name "jetstream-handler.dispatcher".
+func (l *levels) levelOf(loggerName string) zapcore.Level {
+	level := l.level
+	if len(l.components) == 0 || loggerName == "" {
+		return level
+	}
+	words := nameWords(loggerName)
+	bestEnd, bestLength := -1, 0
+	for _, component := range l.components {
+		end := lastMatchEnd(words, component.words)
+		if end > bestEnd || (end == bestEnd && end >= 0 && len(component.words) > bestLength) {
+			bestEnd, bestLength, level = end, len(component.words), component.level
+		}
+	}
+	return level
+}
+
+// lastMatchEnd returns the index after the last occurrence of the given sequence of words, or -1 if there is none.
+func lastMatchEnd(words, sequence []string) int {
+	for start := len(words) - len(sequence); start >= 0; start-- {
+		matches := true
+		for i, word := range sequence {
+			if words[start+i] != word {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return start + len(sequence)
+		}
+	}
+	return -1
+}
+
+func nameWords(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '.' || r == '-' || r == ' '
+	})
+}
+
+// componentLevelCore drops the log entries below the level of the component of the logger they are logged with.
+type componentLevelCore struct {
+	zapcore.Core
+	levels *atomic.Pointer[levels]
+}
+
+func (c *componentLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentLevelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *componentLevelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < c.levels.Load().levelOf(entry.LoggerName) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -2,13 +2,24 @@ package logger
 
 import (
 	"context"
+	"sync/atomic"
 
+	"github.com/go-logr/zapr"
 	"github.com/kyma-project/kyma/common/logging/logger"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/klog/v2"
 )
 
 type Logger struct {
 	*logger.Logger
+
+	// level is the lowest level of the logger and its component level overrides, which can be changed at runtime
+	// by SetLevel and SetComponentLevels.
+	level zap.AtomicLevel
+	// levels are the level of the logger and the levels of its components, which filter the log entries by the name
+	// of the logger they are logged with.
+	levels atomic.Pointer[levels]
 }
 
 // New returns a new Kyma standardized Logger with the given format and level.
@@ -30,19 +41,77 @@ func build(format, level string) (KLogger, error) {
 		return nil, err
 	}
 
-	logLevel, err := logger.MapLevel(level)
+	zapLevel, err := mapLevel(level)
 	if err != nil {
 		return nil, err
 	}
+	atomicLevel := zap.NewAtomicLevelAt(zapLevel)
 
-	log, err := logger.New(logFormat, logLevel)
+	log, err := logger.NewWithAtomicLevel(logFormat, atomicLevel)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = logger.InitKlog(log, logLevel); err != nil {
-		return nil, err
+	l := &Logger{Logger: log, level: atomicLevel}
+	l.levels.Store(&levels{level: zapLevel})
+
+	// klog is used by the Kubernetes client, it logs with the component level overrides applied as well
+	klog.SetLogger(zapr.NewLogger(l.WithContext().Desugar()))
+
+	return l, nil
+}
+
+// WithContext returns a new logger, which applies the component level overrides to its named loggers
+// and masks the sensitive data.
+func (l *Logger) WithContext() *zap.SugaredLogger {
+	return l.Logger.WithContext().WithOptions(zap.WrapCore(l.wrapCore))
+}
+
+// WithTracing returns a new logger with the tracing metadata of the given context, which applies the component
+// level overrides to its named loggers and masks the sensitive data.
+func (l *Logger) WithTracing(ctx context.Context) *zap.SugaredLogger {
+	return l.Logger.WithTracing(ctx).WithOptions(zap.WrapCore(l.wrapCore))
+}
+
+// SetLevel changes the level of the logger and all loggers derived from it at runtime.
+// The components with a level override keep their level.
+func (l *Logger) SetLevel(level string) error {
+	zapLevel, err := mapLevel(level)
+	if err != nil {
+		return err
 	}
+	current := l.levels.Load()
+	l.setLevels(&levels{level: zapLevel, components: current.components})
+	return nil
+}
+
+// SetComponentLevels changes the levels of the named loggers of the given components at runtime. The components
+// are given as a comma separated list of component=level pairs, such as "jetstream=debug,reconciler=warn".
+// All component level overrides are removed if the list is empty.
+func (l *Logger) SetComponentLevels(componentLevels string) error {
+	components, err := parseComponentLevels(componentLevels)
+	if err != nil {
+		return err
+	}
+	current := l.levels.Load()
+	l.setLevels(&levels{level: current.level, components: components})
+	return nil
+}
+
+func (l *Logger) setLevels(levels *levels) {
+	l.levels.Store(levels)
+	l.level.SetLevel(levels.lowest())
+}
+
+// wrapCore applies the component level overrides and the redaction of the sensitive data to the given core.
+func (l *Logger) wrapCore(core zapcore.Core) zapcore.Core {
+	return &componentLevelCore{Core: &redactingCore{Core: core}, levels: &l.levels}
+}
 
-	return &Logger{Logger: log}, nil
+func mapLevel(level string) (zapcore.Level, error) {
+	logLevel, err := logger.MapLevel(level)
+	if err != nil {
+		return zapcore.InvalidLevel, err
+	}
+	return logLevel.ToZapLevel()
 }
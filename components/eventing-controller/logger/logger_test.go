@@ -5,6 +5,8 @@ import (
 
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func Test_Build(t *testing.T) {
@@ -12,3 +14,45 @@ func Test_Build(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, kymaLogger)
 }
+
+func Test_SetLevel(t *testing.T) {
+	kymaLogger, err := logger.New("json", "warn")
+	assert.NoError(t, err)
+	assert.False(t, kymaLogger.WithContext().Desugar().Core().Enabled(zap.DebugLevel))
+
+	assert.NoError(t, kymaLogger.SetLevel("debug"))
+	assert.True(t, kymaLogger.WithContext().Desugar().Core().Enabled(zap.DebugLevel))
+	assert.True(t, kymaLogger.WithContext().Named("derived").Desugar().Core().Enabled(zap.DebugLevel))
+
+	assert.Error(t, kymaLogger.SetLevel("verbose"))
+}
+
+func Test_SetComponentLevels(t *testing.T) {
+	kymaLogger, err := logger.New("json", "warn")
+	assert.NoError(t, err)
+
+	assert.NoError(t, kymaLogger.SetComponentLevels("jetstream=debug, dispatcher=info,reconciler=error"))
+	enabled := func(name string, level zapcore.Level) bool {
+		return kymaLogger.WithContext().Named(name).Desugar().Check(level, "message") != nil
+	}
+	assert.True(t, enabled("jetstream-handler", zap.DebugLevel))
+	assert.False(t, enabled("jetstream-handler.dispatcher", zap.DebugLevel))
+	assert.True(t, enabled("jetstream-handler.dispatcher", zap.InfoLevel))
+	assert.False(t, enabled("jetstream-subscription-reconciler", zap.WarnLevel))
+	assert.True(t, enabled("jetstream-subscription-reconciler", zap.ErrorLevel))
+	assert.False(t, enabled("event-type-cleaner", zap.InfoLevel))
+	assert.True(t, enabled("event-type-cleaner", zap.WarnLevel))
+
+	// the components keep their level when the level of the logger changes
+	assert.NoError(t, kymaLogger.SetLevel("error"))
+	assert.True(t, enabled("jetstream-handler", zap.DebugLevel))
+	assert.False(t, enabled("event-type-cleaner", zap.WarnLevel))
+
+	// the overrides are removed
+	assert.NoError(t, kymaLogger.SetComponentLevels(""))
+	assert.False(t, enabled("jetstream-handler", zap.WarnLevel))
+	assert.False(t, kymaLogger.WithContext().Desugar().Core().Enabled(zap.DebugLevel))
+
+	assert.Error(t, kymaLogger.SetComponentLevels("jetstream"))
+	assert.Error(t, kymaLogger.SetComponentLevels("jetstream=verbose"))
+}
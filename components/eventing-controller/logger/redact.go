@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redacted replaces the sensitive data in the log output.
+const redacted = "<redacted>"
+
+// sensitiveKeys are the normalized keys of the log fields whose values are always redacted.
+//
+//nolint:gochecknoglobals // constant lookup table
+var sensitiveKeys = map[string]bool{
+	"authorization":      true,
+	"proxyauthorization": true,
+	"clientsecret":       true,
+	"password":           true,
+	"token":              true,
+	"accesstoken":        true,
+	"refreshtoken":       true,
+	"credentials":        true,
+	"creds":              true,
+	"seed":               true,
+	"nkey":               true,
+}
+
+// keySeparators are removed from the keys of the log fields before they are compared to the sensitive keys.
+//
+//nolint:gochecknoglobals // constant replacer
+var keySeparators = strings.NewReplacer("-", "", "_", "", ".", "", " ", "")
+
+// sensitivePatterns match the sensitive data within the messages and the values of the log fields,
+// such as the Authorization headers, the OAuth client secrets, and the NATS credentials.
+//
+//nolint:gochecknoglobals // constant lookup table
+var sensitivePatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	// the NATS credentials files, which contain the user JWT and the NKey seed
+	{
+		pattern:     regexp.MustCompile(`(?s)-----BEGIN [A-Z ]+-----.*?-+END [A-Z ]+-+`),
+		replacement: redacted,
+	},
+	// the NKey seeds of NATS users, accounts and operators
+	{
+		pattern:     regexp.MustCompile(`\bS[UAOCNX][A-Z2-7]{56}\b`),
+		replacement: redacted,
+	},
+	// the JSON Web Tokens
+	{
+		pattern:     regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+		replacement: redacted,
+	},
+	// the key-value pairs with sensitive keys, such as the Authorization headers or the OAuth client secrets of the
+	// webhook auth in JSON or forms
+	{
+		pattern: regexp.MustCompile(
+			`(?i)((?:authorization|client_?secret|password|token|credentials)"?\s*[:=]\s*"?)` +
+				`(?:(?:bearer|basic)\s+)?[^\s"&,}]+`),
+		replacement: "${1}" + redacted,
+	},
+	// the Authorization header values without the header name
+	{
+		pattern:     regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`),
+		replacement: "$1 " + redacted,
+	},
+	// the passwords of URLs, such as the NATS URLs with user and password
+	{
+		pattern:     regexp.MustCompile(`(://[^/:@\s]+:)[^/@\s]+@`),
+		replacement: "${1}" + redacted + "@",
+	},
+}
+
+// Redact masks the sensitive data in the given text.
+func Redact(text string) string {
+	for _, p := range sensitivePatterns {
+		text = p.pattern.ReplaceAllString(text, p.replacement)
+	}
+	return text
+}
+
+// redactingCore masks the sensitive data in the messages and fields of the log entries before they are written,
+// so that it does not depend on each log call site to leave it out.
+type redactingCore struct {
+	zapcore.Core
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = Redact(entry.Message)
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+// redactFields returns the given fields with the sensitive data masked.
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redactedFields := make([]zapcore.Field, 0, len(fields))
+	for _, field := range fields {
+		redactedFields = append(redactedFields, redactField(field))
+	}
+	return redactedFields
+}
+
+func redactField(field zapcore.Field) zapcore.Field {
+	if sensitiveKeys[normalizeKey(field.Key)] {
+		return zap.String(field.Key, redacted)
+	}
+	switch field.Type {
+	case zapcore.StringType:
+		return zap.String(field.Key, Redact(field.String))
+	case zapcore.ByteStringType:
+		if value, ok := field.Interface.([]byte); ok {
+			return zap.ByteString(field.Key, []byte(Redact(string(value))))
+		}
+	case zapcore.ErrorType:
+		if err, ok := field.Interface.(error); ok {
+			return zap.String(field.Key, Redact(err.Error()))
+		}
+	case zapcore.StringerType:
+		if stringer, ok := field.Interface.(fmt.Stringer); ok {
+			return zap.String(field.Key, Redact(stringer.String()))
+		}
+	case zapcore.ReflectType:
+		// the objects are encoded as JSON, so that their sensitive fields are masked as well
+		if value, err := json.Marshal(field.Interface); err == nil {
+			return zap.Reflect(field.Key, json.RawMessage(Redact(string(value))))
+		}
+	default:
+	}
+	return field
+}
+
+// normalizeKey returns the lower case key without separators, such as "clientsecret" for "client_secret".
+func normalizeKey(key string) string {
+	return keySeparators.Replace(strings.ToLower(key))
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Sampler limits high-frequency log lines to a number of samples per second and key, so that verbose logging on a
+// busy cluster does not overwhelm the logging backend while still giving representative samples.
+type Sampler struct {
+	perSecond int
+	now       func() time.Time
+
+	mutex sync.Mutex
+	// second is the start of the current one second window.
+	second time.Time
+	// counts are the samples taken within the current window by key.
+	counts map[string]int
+}
+
+// NewSampler returns a Sampler taking the given number of samples per second and key.
+// It returns nil, which samples everything, if the number is not positive.
+func NewSampler(perSecond int) *Sampler {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &Sampler{
+		perSecond: perSecond,
+		now:       time.Now,
+		counts:    map[string]int{},
+	}
+}
+
+// Sample returns true if a log line with the given key is sampled within the current second.
+func (s *Sampler) Sample(key string) bool {
+	if s == nil {
+		return true
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	// all keys share the same window, so that the counts of the keys no longer logged are dropped as well
+	if second := s.now().Truncate(time.Second); !second.Equal(s.second) {
+		s.second = second
+		s.counts = map[string]int{}
+	}
+	if s.counts[key] >= s.perSecond {
+		return false
+	}
+	s.counts[key]++
+	return true
+}
+
+// Sampled returns the given logger if a log line with the given key is sampled. Otherwise, it returns a logger
+// dropping the debug and info lines, so that warnings and errors are never lost.
+func (s *Sampler) Sampled(log *zap.SugaredLogger, key string) *zap.SugaredLogger {
+	if s.Sample(key) {
+		return log
+	}
+	return log.WithOptions(zap.IncreaseLevel(zapcore.WarnLevel))
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_Sampler_Sample(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampler := NewSampler(2)
+	sampler.now = func() time.Time { return now }
+
+	// the samples are limited per key
+	assert.True(t, sampler.Sample("a"))
+	assert.True(t, sampler.Sample("a"))
+	assert.False(t, sampler.Sample("a"))
+	assert.True(t, sampler.Sample("b"))
+
+	// the samples are taken again in the next second
+	now = now.Add(time.Second)
+	assert.True(t, sampler.Sample("a"))
+}
+
+func Test_Sampler_Disabled(t *testing.T) {
+	sampler := NewSampler(0)
+	assert.Nil(t, sampler)
+	for i := 0; i < 10; i++ {
+		assert.True(t, sampler.Sample("a"))
+	}
+}
+
+func Test_Sampler_Sampled(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(core).Sugar()
+	sampler := NewSampler(1)
+
+	sampler.Sampled(log, "a").Debugw("sampled")
+	sampled := sampler.Sampled(log, "a")
+	sampled.Debugw("dropped")
+	sampled.Infow("dropped")
+	sampled.Errorw("kept")
+
+	var messages []string
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"sampled", "kept"}, messages)
+}
@@ -3,9 +3,15 @@ package options
 import (
 	"flag"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 )
 
 const (
@@ -18,15 +24,37 @@ const (
 	argNameReadyEndpoint   = "ready-check-endpoint"
 	argNameHealthEndpoint  = "health-check-endpoint"
 
+	argNameMaxConcurrentReconciles = "max-concurrent-reconciles"
+	argNameRateLimiterBaseDelay    = "rate-limiter-base-delay"
+	argNameRateLimiterMaxDelay     = "rate-limiter-max-delay"
+	argNameRateLimiterQPS          = "rate-limiter-qps"
+	argNameRateLimiterBurst        = "rate-limiter-burst"
+
+	argNameOTLPMetricsEndpoint = "otlp-metrics-endpoint"
+	argNameOTLPMetricsInterval = "otlp-metrics-interval"
+	argNameMetricsLabelLimit   = "metrics-label-limit"
+	argNameSinkHealthWindow    = "sink-health-window"
+	argNameSinkHealthMinCount  = "sink-health-min-deliveries"
+	argNameSinkHealthMinRatio  = "sink-health-min-success-ratio"
+	argNameConfigDir           = "config-dir"
+	argNameConfigFile          = "config-file"
+	argNamePrintConfig         = "print-effective-config"
+	argNameProfilingTokenFile  = "profiling-token-file"
+	argNameProfilingDir        = "profiling-snapshot-dir"
+	argNameAdminTokenFile      = "admin-token-file"
+
 	// All the available environment variables.
 	envNameLogFormat = "APP_LOG_FORMAT"
 	envNameLogLevel  = "APP_LOG_LEVEL"
+	envNameLogLevels = "APP_LOG_LEVELS"
 )
 
 // Options represents the controller options.
 type Options struct {
 	Args
 	Env
+
+	configWatcher *env.ConfigWatcher
 }
 
 // Args represents the controller command-line arguments.
@@ -38,12 +66,60 @@ type Args struct {
 	ProbeAddr       string
 	ReadyEndpoint   string
 	HealthEndpoint  string
+
+	// Subscription controllers workqueue settings.
+	MaxConcurrentReconciles int
+	RateLimiterBaseDelay    time.Duration
+	RateLimiterMaxDelay     time.Duration
+	RateLimiterQPS          float64
+	RateLimiterBurst        int
+
+	// OpenTelemetry metrics export settings, disabled if the endpoint is empty.
+	OTLPMetricsEndpoint string
+	OTLPMetricsInterval time.Duration
+
+	// MetricsLabelLimit caps the distinct sink and event type combinations of the delivery metrics, unlimited if 0.
+	MetricsLabelLimit int
+
+	// Sink health scoring settings, disabled if the window is 0.
+	SinkHealthWindow          time.Duration
+	SinkHealthMinDeliveries   int
+	SinkHealthMinSuccessRatio float64
+
+	// ConfigDir is the directory of the configuration files overriding the environment variables, disabled if empty.
+	ConfigDir string
+
+	// ConfigFile is the YAML file of the environment variables not set in the container, disabled if empty.
+	ConfigFile string
+
+	// PrintEffectiveConfig prints the effective configuration and exits instead of starting the controller.
+	PrintEffectiveConfig bool
+
+	// ProfilingTokenFile is the file of the bearer token guarding the profiling endpoints, disabled if empty.
+	ProfilingTokenFile string
+
+	// ProfilingSnapshotDir is the directory the heap and goroutine snapshots are written to.
+	ProfilingSnapshotDir string
+
+	// AdminTokenFile is the file of the bearer token guarding the dead-letter admin endpoints, disabled if empty.
+	AdminTokenFile string
 }
 
 // Env represents the controller environment variables.
 type Env struct {
 	LogFormat string `envconfig:"APP_LOG_FORMAT" default:"json"`
 	LogLevel  string `envconfig:"APP_LOG_LEVEL" default:"warn"`
+	// LogLevels overrides the log level of components, such as "jetstream=debug,reconciler=warn".
+	LogLevels string `envconfig:"APP_LOG_LEVELS" default:""`
+}
+
+// LoadEnv returns the controller environment variables.
+func LoadEnv() (Env, error) {
+	e := Env{}
+	if err := envconfig.Process("", &e); err != nil {
+		return Env{}, err
+	}
+	return e, nil
 }
 
 // New returns a new Options instance.
@@ -56,22 +132,132 @@ func (o *Options) Parse() error {
 	flag.IntVar(&o.MaxReconnects, argNameMaxReconnects, 10, "Maximum number of reconnect attempts (NATS).")
 	flag.StringVar(&o.MetricsAddr, argNameMetricsAddr, ":8080", "The address the metric endpoint binds to.")
 	flag.DurationVar(&o.ReconnectWait, argNameReconnectWait, 3*time.Second, "Wait time between reconnect attempts (NATS).")
-	flag.DurationVar(&o.ReconcilePeriod, argNameReconcilePeriod, time.Minute*10, "Period between triggering of reconciling calls (BEB).")
+	flag.DurationVar(&o.ReconcilePeriod, argNameReconcilePeriod, time.Minute*10, "Period between the resync of all watched resources, which triggers reconciling calls.")
 	flag.StringVar(&o.ProbeAddr, argNameProbeAddr, ":8081", "The TCP address that the controller should bind to for serving health probes.")
 	flag.StringVar(&o.ReadyEndpoint, argNameReadyEndpoint, "readyz", "The endpoint of the readiness probe.")
 	flag.StringVar(&o.HealthEndpoint, argNameHealthEndpoint, "healthz", "The endpoint of the health probe.")
+	flag.IntVar(&o.MaxConcurrentReconciles, argNameMaxConcurrentReconciles, 1, "Maximum number of concurrent Subscription reconciles.")
+	flag.DurationVar(&o.RateLimiterBaseDelay, argNameRateLimiterBaseDelay, 5*time.Millisecond, "Initial requeue delay of a failing Subscription reconcile.")
+	flag.DurationVar(&o.RateLimiterMaxDelay, argNameRateLimiterMaxDelay, 1000*time.Second, "Maximum requeue delay of a failing Subscription reconcile.")
+	flag.Float64Var(&o.RateLimiterQPS, argNameRateLimiterQPS, 10, "Overall number of Subscription requeues per second.")
+	flag.IntVar(&o.RateLimiterBurst, argNameRateLimiterBurst, 100, "Burst of Subscription requeues above the rate-limiter-qps.")
+	flag.StringVar(&o.OTLPMetricsEndpoint, argNameOTLPMetricsEndpoint, "", "The OTLP HTTP endpoint URL the metrics are pushed to, disabled if empty.")
+	flag.DurationVar(&o.OTLPMetricsInterval, argNameOTLPMetricsInterval, time.Minute, "Interval between the pushes of the metrics to the OTLP endpoint.")
+	flag.IntVar(&o.MetricsLabelLimit, argNameMetricsLabelLimit, 2000, "Maximum number of distinct sink and event type combinations of the delivery metrics, unlimited if 0.")
+	flag.DurationVar(&o.SinkHealthWindow, argNameSinkHealthWindow, 5*time.Minute, "The rolling window of the health of the sink hosts, disabled if 0.")
+	flag.IntVar(&o.SinkHealthMinDeliveries, argNameSinkHealthMinCount, 10, "Minimal number of deliveries to a sink host within the window to consider it unhealthy.")
+	flag.Float64Var(&o.SinkHealthMinSuccessRatio, argNameSinkHealthMinRatio, 0.5, "Ratio of the successful deliveries to a sink host below which it is unhealthy.")
+	flag.StringVar(&o.ConfigDir, argNameConfigDir, "", "The directory of the configuration files overriding the environment variables, such as a mounted ConfigMap, disabled if empty.")
+	flag.StringVar(&o.ConfigFile, argNameConfigFile, "", "The YAML file of the environment variables not set in the container, disabled if empty.")
+	flag.BoolVar(&o.PrintEffectiveConfig, argNamePrintConfig, false, "Print the effective configuration as a configuration file and exit.")
+	flag.StringVar(&o.ProfilingTokenFile, argNameProfilingTokenFile, "", "The file of the bearer token guarding the pprof, expvar and snapshot endpoints on the metrics address, disabled if empty.")
+	flag.StringVar(&o.ProfilingSnapshotDir, argNameProfilingDir, os.TempDir(), "The directory the heap and goroutine snapshots are written to.")
+	flag.StringVar(&o.AdminTokenFile, argNameAdminTokenFile, "", "The file of the bearer token guarding the dead-letter admin endpoints on the metrics address, disabled if empty.")
 	flag.Parse()
 
-	if err := envconfig.Process("", &o.Env); err != nil {
+	// the configuration file is loaded before the configuration files of the directory, which override it
+	if o.ConfigFile != "" {
+		if err := env.LoadConfigFile(o.ConfigFile); err != nil {
+			return fmt.Errorf("--%s: %w", argNameConfigFile, err)
+		}
+	}
+
+	// the deployment profile only presets the values which are not set otherwise
+	if name := os.Getenv(env.ProfileEnv); name != "" {
+		if err := applyProfile(name); err != nil {
+			return err
+		}
+	}
+
+	// the configuration files are loaded before the environment variables, so that they apply to all of them
+	if o.ConfigDir != "" {
+		o.configWatcher = env.NewConfigWatcher(o.ConfigDir)
+		if _, err := o.configWatcher.Load(); err != nil {
+			return fmt.Errorf("--%s: %w", argNameConfigDir, err)
+		}
+	}
+
+	var err error
+	if o.Env, err = LoadEnv(); err != nil {
 		return err
 	}
 
+	return o.validate()
+}
+
+// applyProfile sets the environment variables and command line arguments of the deployment profile of the given name
+// which are not set yet.
+func applyProfile(name string) error {
+	profile, err := env.LookupProfile(name)
+	if err != nil {
+		return err
+	}
+	if err := profile.ApplyEnvs(); err != nil {
+		return fmt.Errorf("%s: %w", env.ProfileEnv, err)
+	}
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for arg, value := range profile.Args {
+		if set[arg] {
+			continue
+		}
+		if err := flag.Set(arg, value); err != nil {
+			return fmt.Errorf("%s: --%s: %w", env.ProfileEnv, arg, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that the workqueue settings are usable.
+func (o *Options) validate() error {
+	if o.MaxConcurrentReconciles < 1 {
+		return fmt.Errorf("--%s must be at least 1", argNameMaxConcurrentReconciles)
+	}
+	if o.RateLimiterBaseDelay <= 0 || o.RateLimiterMaxDelay < o.RateLimiterBaseDelay {
+		return fmt.Errorf("--%s must be positive and not greater than --%s",
+			argNameRateLimiterBaseDelay, argNameRateLimiterMaxDelay)
+	}
+	if o.RateLimiterQPS <= 0 || o.RateLimiterBurst < 1 {
+		return fmt.Errorf("--%s and --%s must be positive", argNameRateLimiterQPS, argNameRateLimiterBurst)
+	}
+	if o.OTLPMetricsEndpoint != "" && o.OTLPMetricsInterval <= 0 {
+		return fmt.Errorf("--%s must be positive", argNameOTLPMetricsInterval)
+	}
+	if o.MetricsLabelLimit < 0 {
+		return fmt.Errorf("--%s must not be negative", argNameMetricsLabelLimit)
+	}
+	if o.SinkHealthWindow < 0 || o.SinkHealthMinDeliveries < 0 {
+		return fmt.Errorf("--%s and --%s must not be negative", argNameSinkHealthWindow, argNameSinkHealthMinCount)
+	}
+	if o.SinkHealthMinSuccessRatio < 0 || o.SinkHealthMinSuccessRatio > 1 {
+		return fmt.Errorf("--%s must be between 0 and 1", argNameSinkHealthMinRatio)
+	}
 	return nil
 }
 
+// ConfigWatcher returns the watcher of the configuration files, or nil if no configuration directory is set.
+func (o *Options) ConfigWatcher() *env.ConfigWatcher {
+	return o.configWatcher
+}
+
+// ControllerOptions returns the options of the Subscription controllers.
+// The rate limiter combines a per-item exponential backoff with an overall token bucket,
+// the same way as the controller-runtime default rate limiter does.
+func (o Options) ControllerOptions() controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: o.MaxConcurrentReconciles,
+		RateLimiter: workqueue.NewMaxOfRateLimiter(
+			workqueue.NewItemExponentialFailureRateLimiter(o.RateLimiterBaseDelay, o.RateLimiterMaxDelay),
+			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(o.RateLimiterQPS), o.RateLimiterBurst)},
+		),
+	}
+}
+
 // String implements the fmt.Stringer interface.
 func (o Options) String() string {
-	return fmt.Sprintf("--%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v %s=%v %s=%v %s=%v",
+	return fmt.Sprintf("--%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v "+
+		"--%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v --%s=%v "+
+		"%s=%v %s=%v %s=%v",
 		argNameMaxReconnects, o.MaxReconnects,
 		argNameMetricsAddr, o.MetricsAddr,
 		argNameReconnectWait, o.ReconnectWait,
@@ -79,7 +265,22 @@ func (o Options) String() string {
 		argNameProbeAddr, o.ProbeAddr,
 		argNameReadyEndpoint, o.ReadyEndpoint,
 		argNameHealthEndpoint, o.HealthEndpoint,
+		argNameMaxConcurrentReconciles, o.MaxConcurrentReconciles,
+		argNameRateLimiterBaseDelay, o.RateLimiterBaseDelay,
+		argNameRateLimiterMaxDelay, o.RateLimiterMaxDelay,
+		argNameRateLimiterQPS, o.RateLimiterQPS,
+		argNameRateLimiterBurst, o.RateLimiterBurst,
+		argNameOTLPMetricsEndpoint, o.OTLPMetricsEndpoint,
+		argNameOTLPMetricsInterval, o.OTLPMetricsInterval,
+		argNameMetricsLabelLimit, o.MetricsLabelLimit,
+		argNameSinkHealthWindow, o.SinkHealthWindow,
+		argNameSinkHealthMinCount, o.SinkHealthMinDeliveries,
+		argNameSinkHealthMinRatio, o.SinkHealthMinSuccessRatio,
+		argNameConfigDir, o.ConfigDir,
+		argNameConfigFile, o.ConfigFile,
+		argNamePrintConfig, o.PrintEffectiveConfig,
 		envNameLogFormat, o.LogFormat,
 		envNameLogLevel, o.LogLevel,
+		envNameLogLevels, o.LogLevels,
 	)
 }
@@ -0,0 +1,237 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+)
+
+// The operations of the audit records.
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// The kinds of the audited resources.
+const (
+	KindSubscription      = "Subscription"
+	KindJetStreamConsumer = "JetStreamConsumer"
+)
+
+const (
+	// ControllerUser is the user of the changes made by the eventing-controller itself.
+	ControllerUser = "eventing-controller"
+
+	// lastAppliedAnnotation is written by kubectl apply, it repeats the whole object and is not audited.
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+	// maxValueLength is the maximal length of the values in the summary of the changes.
+	maxValueLength = 120
+
+	// noValue is the value in the summary of the changes of a field which is not set.
+	noValue = "<none>"
+)
+
+// Record is the audit record of a change to the event routing.
+type Record struct {
+	// User is the name of the user who made the change.
+	User string
+	// Groups are the groups of the user.
+	Groups []string
+	// Operation is either OperationCreate, OperationUpdate, or OperationDelete.
+	Operation string
+	// Kind is the kind of the changed resource.
+	Kind      string
+	Namespace string
+	Name      string
+	// Subscription is the namespaced name of the Subscription owning a changed backend resource.
+	Subscription string
+	// Changes summarizes the changed fields as "field: old -> new".
+	Changes []string
+}
+
+// Auditor writes the audit records of the changes to the Subscriptions and their backend resources,
+// to a dedicated output separated from the controller logs. A nil Auditor writes nothing.
+type Auditor struct {
+	log *zap.Logger
+}
+
+// New returns an Auditor writing the records as JSON lines to the given output, which is either "stdout", "stderr",
+// or the path of a file. It returns nil if the output is empty, which disables the auditing.
+func New(output string) (*Auditor, error) {
+	if output == "" {
+		return nil, nil
+	}
+	cfg := zap.Config{
+		Level:             zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		Encoding:          "json",
+		DisableCaller:     true,
+		DisableStacktrace: true,
+		OutputPaths:       []string{output},
+		ErrorOutputPaths:  []string{"stderr"},
+		EncoderConfig: zapcore.EncoderConfig{
+			TimeKey:     "time",
+			MessageKey:  "message",
+			NameKey:     "logger",
+			EncodeTime:  zapcore.RFC3339NanoTimeEncoder,
+			EncodeLevel: zapcore.LowercaseLevelEncoder,
+		},
+	}
+	log, err := cfg.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the audit log output %s: %w", output, err)
+	}
+	return &Auditor{log: log.Named("audit")}, nil
+}
+
+// Write writes the given record.
+func (a *Auditor) Write(r Record) {
+	if a == nil {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("user", r.User),
+		zap.String("operation", r.Operation),
+		zap.String("kind", r.Kind),
+		zap.String("name", r.Name),
+	}
+	if len(r.Groups) > 0 {
+		fields = append(fields, zap.Strings("groups", r.Groups))
+	}
+	if r.Namespace != "" {
+		fields = append(fields, zap.String("namespace", r.Namespace))
+	}
+	if r.Subscription != "" {
+		fields = append(fields, zap.String("subscription", r.Subscription))
+	}
+	if len(r.Changes) > 0 {
+		fields = append(fields, zap.Strings("changes", r.Changes))
+	}
+	a.log.Info("Audit record", fields...)
+}
+
+// Sync flushes the buffered records.
+func (a *Auditor) Sync() error {
+	if a == nil {
+		return nil
+	}
+	return a.log.Sync()
+}
+
+// SubscriptionAdmitted records the change of a Subscription admitted by the validating webhook, made by the user of
+// the admission request. The old Subscription is nil on create, the new one on delete. Dry-run requests and updates
+// which change neither the spec nor the labels or annotations, such as the finalizer updates, are not recorded.
+func (a *Auditor) SubscriptionAdmitted(req admission.Request, oldSub, newSub *eventingv1alpha2.Subscription) {
+	if a == nil || (req.DryRun != nil && *req.DryRun) {
+		return
+	}
+	r := Record{User: req.UserInfo.Username, Groups: req.UserInfo.Groups, Kind: KindSubscription}
+	switch {
+	case oldSub == nil:
+		r.Operation, r.Namespace, r.Name = OperationCreate, newSub.Namespace, newSub.Name
+		r.Changes = Diff("", nil, newSubscriptionView(newSub))
+	case newSub == nil:
+		r.Operation, r.Namespace, r.Name = OperationDelete, oldSub.Namespace, oldSub.Name
+	default:
+		r.Operation, r.Namespace, r.Name = OperationUpdate, newSub.Namespace, newSub.Name
+		r.Changes = Diff("", newSubscriptionView(oldSub), newSubscriptionView(newSub))
+		if len(r.Changes) == 0 {
+			return
+		}
+	}
+	a.Write(r)
+}
+
+// subscriptionView contains the fields of a Subscription which determine its event routing.
+type subscriptionView struct {
+	Labels      map[string]string                 `json:"labels,omitempty"`
+	Annotations map[string]string                 `json:"annotations,omitempty"`
+	Spec        eventingv1alpha2.SubscriptionSpec `json:"spec"`
+}
+
+func newSubscriptionView(sub *eventingv1alpha2.Subscription) subscriptionView {
+	annotations := make(map[string]string, len(sub.Annotations))
+	for k, v := range sub.Annotations {
+		if k != lastAppliedAnnotation {
+			annotations[k] = v
+		}
+	}
+	return subscriptionView{Labels: sub.Labels, Annotations: annotations, Spec: sub.Spec}
+}
+
+// Diff summarizes the differences of the given objects as "field: old -> new", sorted by field. The objects are
+// compared by their JSON representation, the fields of nested objects are joined with dots and prefixed with the
+// given prefix, and lists are compared as a whole. A nil object has no fields.
+func Diff(prefix string, oldObj, newObj interface{}) []string {
+	oldFields, newFields := map[string]string{}, map[string]string{}
+	flatten(prefix, oldObj, oldFields)
+	flatten(prefix, newObj, newFields)
+
+	var changes []string
+	for field, newValue := range newFields {
+		oldValue, ok := oldFields[field]
+		if !ok {
+			oldValue = noValue
+		}
+		if oldValue != newValue {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", field, oldValue, newValue))
+		}
+	}
+	for field, oldValue := range oldFields {
+		if _, ok := newFields[field]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", field, oldValue, noValue))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// flatten adds the fields of the JSON representation of the given object to the given fields.
+func flatten(prefix string, obj interface{}, fields map[string]string) {
+	if obj == nil {
+		return
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		fields[prefix] = truncate(fmt.Sprintf("%v", obj))
+		return
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		fields[prefix] = truncate(string(data))
+		return
+	}
+	flattenValue(prefix, value, fields)
+}
+
+func flattenValue(prefix string, value interface{}, fields map[string]string) {
+	switch v := value.(type) {
+	case nil:
+		return
+	case map[string]interface{}:
+		for key, nested := range v {
+			field := key
+			if prefix != "" {
+				field = prefix + "." + key
+			}
+			flattenValue(field, nested, fields)
+		}
+	default:
+		data, _ := json.Marshal(v)
+		fields[prefix] = truncate(string(data))
+	}
+}
+
+func truncate(value string) string {
+	if len(value) <= maxValueLength {
+		return value
+	}
+	return value[:maxValueLength] + "..."
+}
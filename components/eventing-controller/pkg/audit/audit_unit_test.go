@@ -0,0 +1,159 @@
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/audit"
+)
+
+func TestNew_Disabled(t *testing.T) {
+	// when
+	auditor, err := audit.New("")
+
+	// then the nil auditor writes nothing
+	require.NoError(t, err)
+	require.Nil(t, auditor)
+	auditor.Write(audit.Record{User: "alice"})
+	auditor.SubscriptionAdmitted(admission.Request{}, nil, newSubscription())
+	require.NoError(t, auditor.Sync())
+}
+
+func TestAuditor_SubscriptionAdmitted(t *testing.T) {
+	// given
+	output := filepath.Join(t.TempDir(), "audit.log")
+	auditor, err := audit.New(output)
+	require.NoError(t, err)
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		UserInfo: authenticationv1.UserInfo{Username: "alice", Groups: []string{"admins"}},
+	}}
+	dryRun := true
+	dryRunReq := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		UserInfo: authenticationv1.UserInfo{Username: "bob"},
+		DryRun:   &dryRun,
+	}}
+
+	sub := newSubscription()
+	updatedSub := sub.DeepCopy()
+	updatedSub.Spec.Sink = "http://other.test.svc.cluster.local"
+	updatedSub.Annotations = map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+		eventingv1alpha2.BackendAnnotation:                 eventingv1alpha2.BackendEventMesh,
+	}
+	finalizedSub := updatedSub.DeepCopy()
+	finalizedSub.Finalizers = []string{eventingv1alpha2.Finalizer}
+
+	// when
+	auditor.SubscriptionAdmitted(req, nil, sub)
+	auditor.SubscriptionAdmitted(req, sub, updatedSub)
+	auditor.SubscriptionAdmitted(req, updatedSub, finalizedSub)
+	auditor.SubscriptionAdmitted(dryRunReq, finalizedSub, nil)
+	auditor.SubscriptionAdmitted(req, finalizedSub, nil)
+	require.NoError(t, auditor.Sync())
+
+	// then the changes are recorded, except for the finalizer update and the dry run
+	records := readRecords(t, output)
+	require.Len(t, records, 3)
+	for _, record := range records {
+		require.Equal(t, "alice", record["user"])
+		require.Equal(t, []interface{}{"admins"}, record["groups"])
+		require.Equal(t, audit.KindSubscription, record["kind"])
+		require.Equal(t, "test", record["namespace"])
+		require.Equal(t, "sub", record["name"])
+		require.NotEmpty(t, record["time"])
+	}
+	require.Equal(t, audit.OperationCreate, records[0]["operation"])
+	require.Contains(t, records[0]["changes"], `spec.sink: <none> -> "http://sink.test.svc.cluster.local"`)
+	require.Contains(t, records[0]["changes"], `spec.types: <none> -> ["order.created.v1"]`)
+	require.Equal(t, audit.OperationUpdate, records[1]["operation"])
+	require.Equal(t, []interface{}{
+		`annotations.eventing.kyma-project.io/backend: <none> -> "eventmesh"`,
+		`spec.sink: "http://sink.test.svc.cluster.local" -> "http://other.test.svc.cluster.local"`,
+	}, records[1]["changes"])
+	require.Equal(t, audit.OperationDelete, records[2]["operation"])
+	require.Nil(t, records[2]["changes"])
+}
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		name        string
+		givenPrefix string
+		givenOld    interface{}
+		givenNew    interface{}
+		wantChanges []string
+	}{
+		{
+			name:        "equal objects should have no changes",
+			givenOld:    map[string]interface{}{"a": 1, "b": []string{"x"}},
+			givenNew:    map[string]interface{}{"a": 1, "b": []string{"x"}},
+			wantChanges: nil,
+		},
+		{
+			name:     "nested fields should be compared",
+			givenOld: map[string]interface{}{"a": map[string]string{"b": "old", "c": "removed"}},
+			givenNew: map[string]interface{}{"a": map[string]string{"b": "new"}, "d": []int{1, 2}},
+			wantChanges: []string{
+				`a.b: "old" -> "new"`,
+				`a.c: "removed" -> <none>`,
+				`d: <none> -> [1,2]`,
+			},
+		},
+		{
+			name:        "scalars should be compared with the prefix",
+			givenPrefix: "maxAckPending",
+			givenOld:    10,
+			givenNew:    20,
+			wantChanges: []string{"maxAckPending: 10 -> 20"},
+		},
+		{
+			name:        "a nil object should have no fields",
+			givenPrefix: "metadata",
+			givenOld:    map[string]string(nil),
+			givenNew:    map[string]string{"owner": "sub"},
+			wantChanges: []string{`metadata.owner: <none> -> "sub"`},
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.wantChanges, audit.Diff(tc.givenPrefix, tc.givenOld, tc.givenNew))
+		})
+	}
+}
+
+func newSubscription() *eventingv1alpha2.Subscription {
+	return &eventingv1alpha2.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "test"},
+		Spec: eventingv1alpha2.SubscriptionSpec{
+			Source: "source",
+			Types:  []string{"order.created.v1"},
+			Sink:   "http://sink.test.svc.cluster.local",
+		},
+	}
+}
+
+func readRecords(t *testing.T, output string) []map[string]interface{} {
+	t.Helper()
+	file, err := os.Open(output)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		record := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
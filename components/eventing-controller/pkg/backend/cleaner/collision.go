@@ -0,0 +1,119 @@
+package cleaner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+const (
+	// hashSuffixLength is the number of hexadecimal digits of the hash suffix.
+	hashSuffixLength = 8
+
+	// hashSuffixSeparator separates the hash suffix from the cleaned event type. It is cleaned itself,
+	// so it can be replaced or stripped by the character policy.
+	hashSuffixSeparator = "-"
+
+	// eventTypeSegmentSeparator separates the segments of the event types, which are the tokens of the subjects.
+	eventTypeSegmentSeparator = "."
+
+	collisionProofName = "collision-proof-cleaner"
+)
+
+// CollisionPolicy configures when a CollisionProofCleaner appends a hash suffix to the cleaned event types.
+type CollisionPolicy struct {
+	// HashCollisions appends a hash suffix to the event types changed by the cleaning, so that they can neither
+	// collide with each other nor with the clean event types.
+	HashCollisions bool
+	// MaxSegmentLength shortens the segments of the cleaned event types exceeding it and appends a hash suffix
+	// to them. The segment length is not limited if it is 0.
+	MaxSegmentLength int
+}
+
+// Enabled returns true if the policy appends a hash suffix in any case.
+func (p CollisionPolicy) Enabled() bool {
+	return p.HashCollisions || p.MaxSegmentLength > 0
+}
+
+// Perform a compile-time check.
+var _ Cleaner = &CollisionProofCleaner{}
+
+// CollisionProofCleaner is a Cleaner appending a short deterministic hash of the original event type to the
+// event types cleaned by another Cleaner, if the cleaning changed them or if one of their segments exceeds
+// a maximum length, as configured by a CollisionPolicy. The segments exceeding the maximum length are shortened
+// to make room for the hash suffix, so that distinct event types never share a subject.
+// The suffix only depends on the original event type, so the eventing-controller and the Event Publisher Proxy
+// clean an event type to the same subject regardless of the other event types.
+type CollisionProofCleaner struct {
+	cleaner Cleaner
+	policy  CollisionPolicy
+	logger  *logger.Logger
+}
+
+// NewCollisionProofCleaner returns a Cleaner delegating to the given Cleaner and appending a hash suffix to the
+// cleaned event types as configured by the given policy.
+func NewCollisionProofCleaner(cleaner Cleaner, policy CollisionPolicy, logger *logger.Logger) Cleaner {
+	return &CollisionProofCleaner{cleaner: cleaner, policy: policy, logger: logger}
+}
+
+func (c *CollisionProofCleaner) CleanSource(source string) (string, error) {
+	return c.cleaner.CleanSource(source)
+}
+
+func (c *CollisionProofCleaner) CleanEventType(eventType string) (string, error) {
+	cleaned, err := c.cleaner.CleanEventType(eventType)
+	if err != nil {
+		return cleaned, err
+	}
+	suffix, err := c.hashSuffix(eventType)
+	if err != nil {
+		return "", err
+	}
+
+	reason := ""
+	if shortened, ok := c.shortenSegments(cleaned, suffix); ok {
+		cleaned, reason = shortened, "segment length"
+	} else if c.policy.HashCollisions && cleaned != eventType {
+		cleaned, reason = cleaned+suffix, "cleaned"
+	}
+	if reason != "" {
+		c.logger.WithContext().Named(collisionProofName).Debugw("Appended a hash suffix to the cleaned event type",
+			"eventType", eventType, "cleaned", cleaned, "reason", reason)
+	}
+	return cleaned, nil
+}
+
+// hashSuffix returns the cleaned hash suffix of the given original event type.
+func (c *CollisionProofCleaner) hashSuffix(eventType string) (string, error) {
+	hash := sha256.Sum256([]byte(eventType))
+	return c.cleaner.CleanEventType(hashSuffixSeparator + hex.EncodeToString(hash[:])[:hashSuffixLength])
+}
+
+// shortenSegments shortens the segments of the cleaned event type exceeding the maximum length and appends
+// the hash suffix to them. It returns false if there is no such segment.
+func (c *CollisionProofCleaner) shortenSegments(cleaned, suffix string) (string, bool) {
+	if c.policy.MaxSegmentLength <= 0 {
+		return cleaned, false
+	}
+	segments := strings.Split(cleaned, eventTypeSegmentSeparator)
+	shortened := false
+	for i, segment := range segments {
+		if len(segment) <= c.policy.MaxSegmentLength {
+			continue
+		}
+		keep := c.policy.MaxSegmentLength - len(suffix)
+		if keep < 0 {
+			keep = 0
+		}
+		// do not split a multi-byte character
+		for keep > 0 && !utf8.RuneStart(segment[keep]) {
+			keep--
+		}
+		segments[i] = segment[:keep] + suffix
+		shortened = true
+	}
+	return strings.Join(segments, eventTypeSegmentSeparator), shortened
+}
@@ -0,0 +1,89 @@
+package cleaner //nolint:testpackage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+func Test_CollisionProofCleaner(t *testing.T) {
+	hash := func(eventType string) string {
+		sum := sha256.Sum256([]byte(eventType))
+		return hex.EncodeToString(sum[:])[:hashSuffixLength]
+	}
+
+	testCases := []struct {
+		name                 string
+		givenPolicy          CollisionPolicy
+		givenCharacters      CharacterPolicy
+		givenEventType       string
+		wantCleanedEventType string
+	}{
+		{
+			name:                 "should keep a clean event type",
+			givenPolicy:          CollisionPolicy{HashCollisions: true},
+			givenEventType:       "order.created.v1",
+			wantCleanedEventType: "order.created.v1",
+		},
+		{
+			name:                 "should append a hash suffix to an event type changed by the cleaning",
+			givenPolicy:          CollisionPolicy{HashCollisions: true},
+			givenEventType:       "order.created>.v1",
+			wantCleanedEventType: "order.created.v1-" + hash("order.created>.v1"),
+		},
+		{
+			name:                 "should append a different hash suffix to an event type cleaned to the same value",
+			givenPolicy:          CollisionPolicy{HashCollisions: true},
+			givenEventType:       "order.created*.v1",
+			wantCleanedEventType: "order.created.v1-" + hash("order.created*.v1"),
+		},
+		{
+			name:                 "should clean the hash suffix by the character policy",
+			givenPolicy:          CollisionPolicy{HashCollisions: true},
+			givenCharacters:      CharacterPolicy{AllowedCharacters: "a-z0-9", Replacement: "x"},
+			givenEventType:       "order.created-v1",
+			wantCleanedEventType: "order.createdxv1x" + hash("order.created-v1"),
+		},
+		{
+			name:                 "should not append a hash suffix to a cleaned event type if disabled",
+			givenPolicy:          CollisionPolicy{MaxSegmentLength: 100},
+			givenEventType:       "order.created>.v1",
+			wantCleanedEventType: "order.created.v1",
+		},
+		{
+			name:                 "should shorten the segments exceeding the maximum length",
+			givenPolicy:          CollisionPolicy{MaxSegmentLength: 12},
+			givenEventType:       "order.created_and_confirmed.v1",
+			wantCleanedEventType: "order.cre-" + hash("order.created_and_confirmed.v1") + ".v1",
+		},
+		{
+			name:                 "should not split a multi-byte character",
+			givenPolicy:          CollisionPolicy{MaxSegmentLength: 12},
+			givenEventType:       "order.créééééé.v1",
+			wantCleanedEventType: "order.cr-" + hash("order.créééééé.v1") + ".v1",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			defaultLogger, err := logger.New("json", "info")
+			require.NoError(t, err)
+			jsCleaner, err := NewJetStreamCleanerWithPolicy(defaultLogger, tc.givenCharacters)
+			require.NoError(t, err)
+			collisionProofCleaner := NewCollisionProofCleaner(jsCleaner, tc.givenPolicy, defaultLogger)
+
+			// when
+			cleanedEventType, err := collisionProofCleaner.CleanEventType(tc.givenEventType)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCleanedEventType, cleanedEventType)
+		})
+	}
+}
@@ -26,13 +26,27 @@ func NewEventMeshCleaner(logger *logger.Logger) Cleaner {
 	return &EventMeshCleaner{logger: logger}
 }
 
+// NewEventMeshCleanerWithPolicy returns an EventMesh Cleaner applying the given character policy
+// in addition to removing the characters not supported by EventMesh.
+func NewEventMeshCleanerWithPolicy(logger *logger.Logger, policy CharacterPolicy) (Cleaner, error) {
+	sourceFilter, err := policy.newCharacterFilter(invalidEventMeshSourceSegment, "")
+	if err != nil {
+		return nil, err
+	}
+	eventTypeFilter, err := policy.newCharacterFilter(invalidEventMeshTypeSegment, ".")
+	if err != nil {
+		return nil, err
+	}
+	return &EventMeshCleaner{logger: logger, sourceFilter: sourceFilter, eventTypeFilter: eventTypeFilter}, nil
+}
+
 func (c *EventMeshCleaner) CleanSource(source string) (string, error) {
-	return invalidEventMeshSourceSegment.ReplaceAllString(source, ""), nil
+	return c.sourceFilter.clean(source, invalidEventMeshSourceSegment), nil
 }
 
 func (c *EventMeshCleaner) CleanEventType(eventType string) (string, error) {
 	mergedEventType := c.getMergedSegments(eventType)
-	return invalidEventMeshTypeSegment.ReplaceAllString(mergedEventType, ""), nil
+	return c.eventTypeFilter.clean(mergedEventType, invalidEventMeshTypeSegment), nil
 }
 
 // getMergedSegments returns the event type after merging the extra segments
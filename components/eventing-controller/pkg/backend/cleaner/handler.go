@@ -0,0 +1,47 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// MappingEndpoint is the path of the endpoint looking up the original event types of a cleaned event type.
+	MappingEndpoint = "/cleaner/mapping"
+
+	// cleanedQueryParameter is the query parameter of the cleaned event type to look up.
+	cleanedQueryParameter = "cleaned"
+)
+
+// MappingResponse is the response of the MappingEndpoint.
+type MappingResponse struct {
+	Cleaned   string   `json:"cleaned"`
+	Originals []string `json:"originals"`
+}
+
+// MappingHandler returns a http.Handler looking up the original event types of the cleaned event type given by
+// the `cleaned` query parameter in the given Mapping. It responds with 404 if the cleaned event type is unknown.
+func MappingHandler(mapping *Mapping) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		cleaned := r.URL.Query().Get(cleanedQueryParameter)
+		if cleaned == "" {
+			http.Error(w, "missing query parameter "+cleanedQueryParameter, http.StatusBadRequest)
+			return
+		}
+		originals, err := mapping.Lookup(cleaned)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(originals) == 0 {
+			http.Error(w, "unknown cleaned event type "+cleaned, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(MappingResponse{Cleaned: cleaned, Originals: originals})
+	})
+}
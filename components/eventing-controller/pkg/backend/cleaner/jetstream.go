@@ -19,10 +19,24 @@ func NewJetStreamCleaner(logger *logger.Logger) Cleaner {
 	return &JetStreamCleaner{logger: logger}
 }
 
+// NewJetStreamCleanerWithPolicy returns a JetStream Cleaner applying the given character policy
+// in addition to removing the characters reserved by NATS.
+func NewJetStreamCleanerWithPolicy(logger *logger.Logger, policy CharacterPolicy) (Cleaner, error) {
+	sourceFilter, err := policy.newCharacterFilter(invalidSourceCharacters, "")
+	if err != nil {
+		return nil, err
+	}
+	eventTypeFilter, err := policy.newCharacterFilter(invalidEventTypeCharacters, ".")
+	if err != nil {
+		return nil, err
+	}
+	return &JetStreamCleaner{logger: logger, sourceFilter: sourceFilter, eventTypeFilter: eventTypeFilter}, nil
+}
+
 func (c *JetStreamCleaner) CleanSource(source string) (string, error) {
-	return invalidSourceCharacters.ReplaceAllString(source, ""), nil
+	return c.sourceFilter.clean(source, invalidSourceCharacters), nil
 }
 
 func (c *JetStreamCleaner) CleanEventType(eventType string) (string, error) {
-	return invalidEventTypeCharacters.ReplaceAllString(eventType, ""), nil
+	return c.eventTypeFilter.clean(eventType, invalidEventTypeCharacters), nil
 }
@@ -0,0 +1,44 @@
+package cleaner
+
+import (
+	"regexp"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+var (
+	// invalidKafkaSourceCharacters matches the characters not allowed in a segment of the Kafka topic names.
+	invalidKafkaSourceCharacters = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+	// invalidKafkaEventTypeCharacters matches the characters not allowed in the Kafka topic names.
+	invalidKafkaEventTypeCharacters = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// Perform a compile-time check.
+var _ Cleaner = &KafkaCleaner{}
+
+func NewKafkaCleaner(logger *logger.Logger) Cleaner {
+	return &KafkaCleaner{logger: logger}
+}
+
+// NewKafkaCleanerWithPolicy returns a Kafka Cleaner applying the given character policy
+// in addition to removing the characters not allowed in the Kafka topic names.
+func NewKafkaCleanerWithPolicy(logger *logger.Logger, policy CharacterPolicy) (Cleaner, error) {
+	sourceFilter, err := policy.newCharacterFilter(invalidKafkaSourceCharacters, "")
+	if err != nil {
+		return nil, err
+	}
+	eventTypeFilter, err := policy.newCharacterFilter(invalidKafkaEventTypeCharacters, ".")
+	if err != nil {
+		return nil, err
+	}
+	return &KafkaCleaner{logger: logger, sourceFilter: sourceFilter, eventTypeFilter: eventTypeFilter}, nil
+}
+
+func (c *KafkaCleaner) CleanSource(source string) (string, error) {
+	return c.sourceFilter.clean(source, invalidKafkaSourceCharacters), nil
+}
+
+func (c *KafkaCleaner) CleanEventType(eventType string) (string, error) {
+	return c.eventTypeFilter.clean(eventType, invalidKafkaEventTypeCharacters), nil
+}
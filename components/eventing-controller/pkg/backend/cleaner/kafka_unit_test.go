@@ -0,0 +1,76 @@
+package cleaner
+
+import (
+	"testing"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+func Test_KafkaCleanSource(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name             string
+		givenEventSource string
+		wantEventSource  string
+	}{
+		{
+			name:             "removes all characters not allowed in topic names and dots",
+			givenEventSource: "source1-Part1/2.Part*2 Ä__t!!a@p",
+			wantEventSource:  "source1-Part12Part2__tap",
+		},
+		{
+			name:             "does nothing for allowed characters",
+			givenEventSource: "my-app_1",
+			wantEventSource:  "my-app_1",
+		},
+	}
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cleaner := NewKafkaCleaner(defaultLogger)
+			source, _ := cleaner.CleanSource(tc.givenEventSource)
+			require.Equal(t, tc.wantEventSource, source)
+		})
+	}
+}
+
+func Test_KafkaCleanEventType(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name           string
+		givenEventType string
+		wantEventType  string
+	}{
+		{
+			name:           "removes all characters not allowed in topic names",
+			givenEventType: "prefix.test app.Segment1>*.Segment2-Part1/2-Ä.order!!.v1",
+			wantEventType:  "prefix.testapp.Segment1.Segment2-Part12-.order.v1",
+		},
+		{
+			name:           "does nothing for allowed characters",
+			givenEventType: "order_1.created-now.v1",
+			wantEventType:  "order_1.created-now.v1",
+		},
+	}
+
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cleaner := NewKafkaCleaner(defaultLogger)
+			eventType, _ := cleaner.CleanEventType(tc.givenEventType)
+			require.Equal(t, tc.wantEventType, eventType)
+		})
+	}
+}
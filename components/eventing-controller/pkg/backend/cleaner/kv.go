@@ -0,0 +1,104 @@
+package cleaner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/nats-io/nats.go"
+)
+
+// kvPutAttempts is the number of attempts of updating a key modified concurrently by another replica.
+const kvPutAttempts = 3
+
+// Perform a compile-time check.
+var _ MappingStore = &KeyValueStore{}
+
+// KeyValueStore is a MappingStore persisting the mapping to a JetStream key-value bucket.
+// The keys are the base64 encoded cleaned event types, since these can contain characters not allowed in keys,
+// and the values are the JSON encoded lists of the original event types.
+type KeyValueStore struct {
+	kv nats.KeyValue
+}
+
+// NewKeyValueStore returns a MappingStore persisting the mapping to the given key-value bucket.
+func NewKeyValueStore(kv nats.KeyValue) *KeyValueStore {
+	return &KeyValueStore{kv: kv}
+}
+
+// NewKeyValueStoreForBucket returns a MappingStore persisting the mapping to the key-value bucket with the given
+// name, which is created if it does not exist yet.
+func NewKeyValueStoreForBucket(jsCtx nats.JetStreamContext, bucket string) (*KeyValueStore, error) {
+	kv, err := jsCtx.KeyValue(bucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = jsCtx.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      bucket,
+			Description: "The original event types of the cleaned event types",
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the key-value bucket %s: %w", bucket, err)
+	}
+	return NewKeyValueStore(kv), nil
+}
+
+func (s *KeyValueStore) Put(cleaned, original string) error {
+	key := base64.RawURLEncoding.EncodeToString([]byte(cleaned))
+	var err error
+	for attempt := 0; attempt < kvPutAttempts; attempt++ {
+		if err = s.put(key, original); !errors.Is(err, nats.ErrKeyExists) {
+			return err
+		}
+	}
+	return err
+}
+
+// put adds the original event type to the value of the given key, if the key was not modified concurrently.
+// Otherwise, it returns nats.ErrKeyExists.
+func (s *KeyValueStore) put(key, original string) error {
+	entry, err := s.kv.Get(key)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		value, err := json.Marshal([]string{original})
+		if err != nil {
+			return err
+		}
+		_, err = s.kv.Create(key, value)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	var originals []string
+	if err := json.Unmarshal(entry.Value(), &originals); err != nil {
+		return fmt.Errorf("failed to decode the original event types of key %s: %w", key, err)
+	}
+	i := sort.SearchStrings(originals, original)
+	if i < len(originals) && originals[i] == original {
+		return nil
+	}
+	originals = append(originals, original)
+	sort.Strings(originals)
+	value, err := json.Marshal(originals)
+	if err != nil {
+		return err
+	}
+	_, err = s.kv.Update(key, value, entry.Revision())
+	return err
+}
+
+func (s *KeyValueStore) Get(cleaned string) ([]string, error) {
+	entry, err := s.kv.Get(base64.RawURLEncoding.EncodeToString([]byte(cleaned)))
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var originals []string
+	if err := json.Unmarshal(entry.Value(), &originals); err != nil {
+		return nil, fmt.Errorf("failed to decode the original event types of %s: %w", cleaned, err)
+	}
+	return originals, nil
+}
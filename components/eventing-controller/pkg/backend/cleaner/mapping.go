@@ -0,0 +1,112 @@
+package cleaner
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+const mappingName = "cleaner-mapping"
+
+// MappingStore persists the mapping of the cleaned event types to their original event types.
+type MappingStore interface {
+	// Put adds the given original event type to the original event types of the given cleaned event type.
+	Put(cleaned, original string) error
+	// Get returns the original event types of the given cleaned event type, or nil if there is none.
+	Get(cleaned string) ([]string, error)
+}
+
+// Mapping maps the cleaned event types to the original event types they were cleaned from,
+// so that the logs and metrics which only carry the cleaned event types can be traced back.
+// It keeps the mapping in memory and optionally persists it to a MappingStore.
+type Mapping struct {
+	logger *logger.Logger
+
+	mutex     sync.RWMutex
+	originals map[string][]string
+	store     MappingStore
+}
+
+// NewMapping returns a new empty in-memory Mapping.
+func NewMapping(logger *logger.Logger) *Mapping {
+	return &Mapping{logger: logger, originals: map[string][]string{}}
+}
+
+// SetStore sets the store the mapping is persisted to, or disables the persistence if it is nil.
+func (m *Mapping) SetStore(store MappingStore) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.store = store
+}
+
+// Record adds the given original event type to the original event types of the given cleaned event type.
+// A failure of persisting it is logged, since the mapping is kept in memory anyway.
+func (m *Mapping) Record(cleaned, original string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	originals := m.originals[cleaned]
+	i := sort.SearchStrings(originals, original)
+	if i < len(originals) && originals[i] == original {
+		return
+	}
+	originals = append(originals, "")
+	copy(originals[i+1:], originals[i:])
+	originals[i] = original
+	m.originals[cleaned] = originals
+
+	if m.store == nil {
+		return
+	}
+	if err := m.store.Put(cleaned, original); err != nil {
+		m.logger.WithContext().Named(mappingName).Warnw("Failed to persist the cleaned event type mapping",
+			"cleaned", cleaned, "original", original, "error", err)
+	}
+}
+
+// Lookup returns the sorted original event types of the given cleaned event type. More than one original
+// event type means that they collide. It falls back to the store for the event types not cleaned since the start.
+func (m *Mapping) Lookup(cleaned string) ([]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if originals, ok := m.originals[cleaned]; ok {
+		return append([]string(nil), originals...), nil
+	}
+	if m.store == nil {
+		return nil, nil
+	}
+	originals, err := m.store.Get(cleaned)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(originals)
+	return originals, nil
+}
+
+// Perform a compile-time check.
+var _ Cleaner = &MappingCleaner{}
+
+// MappingCleaner is a Cleaner recording the event types cleaned by another Cleaner in a Mapping.
+type MappingCleaner struct {
+	cleaner Cleaner
+	mapping *Mapping
+}
+
+// NewMappingCleaner returns a Cleaner delegating to the given Cleaner and recording the cleaned event types
+// in the given Mapping.
+func NewMappingCleaner(cleaner Cleaner, mapping *Mapping) Cleaner {
+	return &MappingCleaner{cleaner: cleaner, mapping: mapping}
+}
+
+func (c *MappingCleaner) CleanSource(source string) (string, error) {
+	return c.cleaner.CleanSource(source)
+}
+
+func (c *MappingCleaner) CleanEventType(eventType string) (string, error) {
+	cleaned, err := c.cleaner.CleanEventType(eventType)
+	if err != nil {
+		return cleaned, err
+	}
+	c.mapping.Record(cleaned, eventType)
+	return cleaned, nil
+}
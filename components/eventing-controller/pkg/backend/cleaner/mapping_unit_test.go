@@ -0,0 +1,131 @@
+package cleaner //nolint:testpackage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	evtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func Test_MappingCleaner(t *testing.T) {
+	// given
+	defaultLogger, err := logger.New("json", "info")
+	require.NoError(t, err)
+	mapping := NewMapping(defaultLogger)
+	jsCleaner, err := NewJetStreamCleanerWithPolicy(defaultLogger, CharacterPolicy{})
+	require.NoError(t, err)
+	mappingCleaner := NewMappingCleaner(jsCleaner, mapping)
+
+	// when
+	for _, eventType := range []string{"order.created>.v1", "order.created.v1", "order.created*.v1"} {
+		_, err := mappingCleaner.CleanEventType(eventType)
+		require.NoError(t, err)
+	}
+	_, err = mappingCleaner.CleanSource("my/app")
+	require.NoError(t, err)
+
+	// then
+	originals, err := mapping.Lookup("order.created.v1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"order.created*.v1", "order.created.v1", "order.created>.v1"}, originals)
+	originals, err = mapping.Lookup("myapp")
+	require.NoError(t, err)
+	require.Empty(t, originals)
+}
+
+func Test_MappingHandler(t *testing.T) {
+	defaultLogger, err := logger.New("json", "info")
+	require.NoError(t, err)
+	mapping := NewMapping(defaultLogger)
+	mapping.Record("order.created.v1", "order.created>.v1")
+
+	testCases := []struct {
+		name         string
+		givenMethod  string
+		givenQuery   string
+		wantStatus   int
+		wantResponse *MappingResponse
+	}{
+		{
+			name:         "should return the original event types",
+			givenMethod:  http.MethodGet,
+			givenQuery:   "?cleaned=order.created.v1",
+			wantStatus:   http.StatusOK,
+			wantResponse: &MappingResponse{Cleaned: "order.created.v1", Originals: []string{"order.created>.v1"}},
+		},
+		{
+			name:        "should fail if the cleaned event type is unknown",
+			givenMethod: http.MethodGet,
+			givenQuery:  "?cleaned=order.deleted.v1",
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			name:        "should fail if the cleaned event type is missing",
+			givenMethod: http.MethodGet,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "should fail if the method is not GET",
+			givenMethod: http.MethodPost,
+			givenQuery:  "?cleaned=order.created.v1",
+			wantStatus:  http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tc.givenMethod, MappingEndpoint+tc.givenQuery, nil)
+			MappingHandler(mapping).ServeHTTP(recorder, request)
+			require.Equal(t, tc.wantStatus, recorder.Code)
+			if tc.wantResponse != nil {
+				var response MappingResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+				require.Equal(t, *tc.wantResponse, response)
+			}
+		})
+	}
+}
+
+func Test_KeyValueStore(t *testing.T) {
+	// given
+	port, err := evtesting.GetFreePort()
+	require.NoError(t, err)
+	natsServer := evtesting.RunNatsServerOnPort(evtesting.WithPort(port), evtesting.WithJetStreamEnabled())
+	defer evtesting.ShutDownNATSServer(natsServer)
+	conn, err := nats.Connect(natsServer.ClientURL())
+	require.NoError(t, err)
+	defer conn.Close()
+	jsCtx, err := conn.JetStream()
+	require.NoError(t, err)
+	store, err := NewKeyValueStoreForBucket(jsCtx, "cleaner-mapping")
+	require.NoError(t, err)
+	defaultLogger, err := logger.New("json", "info")
+	require.NoError(t, err)
+	mapping := NewMapping(defaultLogger)
+	mapping.SetStore(store)
+
+	// when
+	mapping.Record("order.created.v1", "order.created>.v1")
+	mapping.Record("order.created.v1", "order.created*.v1")
+	mapping.Record("order.created.v1", "order.created>.v1")
+
+	// then the mapping is restored from the store by a new replica
+	store, err = NewKeyValueStoreForBucket(jsCtx, "cleaner-mapping")
+	require.NoError(t, err)
+	restored := NewMapping(defaultLogger)
+	restored.SetStore(store)
+	originals, err := restored.Lookup("order.created.v1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"order.created*.v1", "order.created>.v1"}, originals)
+	originals, err = restored.Lookup("order.deleted.v1")
+	require.NoError(t, err)
+	require.Empty(t, originals)
+}
@@ -0,0 +1,55 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var ErrInvalidCharacterPolicy = errors.New("invalid character policy")
+
+// CharacterPolicy configures which characters of the event types and sources a Cleaner keeps,
+// and how it replaces the other ones. The zero value keeps the default behavior of the cleaning strategy.
+type CharacterPolicy struct {
+	// AllowedCharacters is the content of a regular expression character class matching the allowed characters,
+	// such as `a-zA-Z0-9_-`. It can only restrict the characters supported by the backend further.
+	// If empty, all the characters supported by the backend are allowed.
+	AllowedCharacters string
+	// Replacement substitutes each character which is not allowed. If empty, these characters are stripped,
+	// which can make distinct event types collide, for example `order.v-1` and `order.v1`.
+	Replacement string
+}
+
+// characterFilter replaces the invalid characters of a string.
+// The zero value strips the characters matched by the default invalid characters of the cleaning strategy.
+type characterFilter struct {
+	invalid     *regexp.Regexp
+	replacement string
+}
+
+func (f characterFilter) clean(s string, defaultInvalid *regexp.Regexp) string {
+	if f.invalid == nil {
+		return defaultInvalid.ReplaceAllString(s, "")
+	}
+	return f.invalid.ReplaceAllString(s, f.replacement)
+}
+
+// newCharacterFilter returns a filter of the characters which are either matched by the given backend invalid
+// characters or not allowed by the policy. The given separators are always allowed by the policy,
+// such as the dots separating the event type segments.
+func (p CharacterPolicy) newCharacterFilter(backendInvalid *regexp.Regexp, separators string) (characterFilter, error) {
+	invalid := backendInvalid
+	if p.AllowedCharacters != "" {
+		var err error
+		invalid, err = regexp.Compile(fmt.Sprintf("[^%s%s]|%s", p.AllowedCharacters, separators, backendInvalid))
+		if err != nil {
+			return characterFilter{}, fmt.Errorf("%w: allowed characters %q: %v",
+				ErrInvalidCharacterPolicy, p.AllowedCharacters, err)
+		}
+	}
+	if invalid.MatchString(p.Replacement) {
+		return characterFilter{}, fmt.Errorf("%w: replacement %q is not allowed itself",
+			ErrInvalidCharacterPolicy, p.Replacement)
+	}
+	return characterFilter{invalid: invalid, replacement: p.Replacement}, nil
+}
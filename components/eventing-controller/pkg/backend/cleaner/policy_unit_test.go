@@ -0,0 +1,103 @@
+package cleaner //nolint:testpackage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CharacterPolicy(t *testing.T) {
+	testCases := []struct {
+		name           string
+		givenStrategy  string
+		givenPolicy    CharacterPolicy
+		givenSource    string
+		givenEventType string
+		wantSource     string
+		wantEventType  string
+		wantError      error
+	}{
+		{
+			name:           "should strip the NATS reserved characters by default",
+			givenStrategy:  StrategyJetStream,
+			givenSource:    "my.app/1",
+			givenEventType: "order.created/v-1",
+			wantSource:     "myapp1",
+			wantEventType:  "order.createdv-1",
+		},
+		{
+			name:           "should substitute the NATS reserved characters",
+			givenStrategy:  StrategyJetStream,
+			givenPolicy:    CharacterPolicy{Replacement: "_"},
+			givenSource:    "my.app/1",
+			givenEventType: "order.created/v-1",
+			wantSource:     "my_app_1",
+			wantEventType:  "order.created_v-1",
+		},
+		{
+			name:           "should substitute the characters not allowed by the policy",
+			givenStrategy:  StrategyJetStream,
+			givenPolicy:    CharacterPolicy{AllowedCharacters: "a-z0-9_", Replacement: "_"},
+			givenSource:    "my-app",
+			givenEventType: "order.created.v-1",
+			wantSource:     "my_app",
+			wantEventType:  "order.created.v_1",
+		},
+		{
+			name:           "should keep the dots of the event type with allowed characters",
+			givenStrategy:  StrategyEventMesh,
+			givenPolicy:    CharacterPolicy{AllowedCharacters: "a-z0-9"},
+			givenSource:    "My-App",
+			givenEventType: "Order.created.v-1",
+			wantSource:     "ypp",
+			wantEventType:  "rder.created.v1",
+		},
+		{
+			name:           "should substitute the characters not supported by EventMesh",
+			givenStrategy:  StrategyEventMesh,
+			givenPolicy:    CharacterPolicy{Replacement: "x"},
+			givenSource:    "my-app",
+			givenEventType: "order.created.v-1",
+			wantSource:     "myxapp",
+			wantEventType:  "order.created.vx1",
+		},
+		{
+			name:          "should fail for a replacement which is not allowed itself",
+			givenStrategy: StrategyJetStream,
+			givenPolicy:   CharacterPolicy{Replacement: "*"},
+			wantError:     ErrInvalidCharacterPolicy,
+		},
+		{
+			name:          "should fail for a replacement which is not allowed by the policy",
+			givenStrategy: StrategyEventMesh,
+			givenPolicy:   CharacterPolicy{AllowedCharacters: "a-z", Replacement: "0"},
+			wantError:     ErrInvalidCharacterPolicy,
+		},
+		{
+			name:          "should fail for invalid allowed characters",
+			givenStrategy: StrategyJetStream,
+			givenPolicy:   CharacterPolicy{AllowedCharacters: "z-a"},
+			wantError:     ErrInvalidCharacterPolicy,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			cleaner, err := New(tc.givenStrategy, nil, tc.givenPolicy)
+
+			// then
+			require.ErrorIs(t, err, tc.wantError)
+			if tc.wantError != nil {
+				return
+			}
+			source, err := cleaner.CleanSource(tc.givenSource)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantSource, source)
+			eventType, err := cleaner.CleanEventType(tc.givenEventType)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantEventType, eventType)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+const (
+	// StrategyJetStream is the cleaning strategy of the NATS JetStream backend.
+	StrategyJetStream = "jetstream"
+	// StrategyEventMesh is the cleaning strategy of the EventMesh backend.
+	StrategyEventMesh = "eventmesh"
+	// StrategyKafka is the cleaning strategy of the Kafka backend.
+	StrategyKafka = "kafka"
+)
+
+var (
+	ErrUnknownStrategy    = errors.New("unknown cleaning strategy")
+	ErrStrategyRegistered = errors.New("cleaning strategy is already registered")
+)
+
+// Factory creates a Cleaner implementing a cleaning strategy with the given character policy.
+type Factory func(logger *logger.Logger, policy CharacterPolicy) (Cleaner, error)
+
+// registry stores the factories of the available cleaning strategies by their name.
+var (
+	registryMutex sync.RWMutex
+	registry      = map[string]Factory{
+		StrategyJetStream: NewJetStreamCleanerWithPolicy,
+		StrategyEventMesh: NewEventMeshCleanerWithPolicy,
+		StrategyKafka:     NewKafkaCleanerWithPolicy,
+	}
+)
+
+// Register makes the cleaning strategy created by the given factory available under the given name,
+// so that alternative backends or custom naming rules can provide their own Cleaner.
+// It returns ErrStrategyRegistered if a strategy with the same name is already registered.
+func Register(name string, factory Factory) error {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("%w: %s", ErrStrategyRegistered, name)
+	}
+	registry[name] = factory
+	return nil
+}
+
+// New returns a Cleaner implementing the cleaning strategy registered under the given name with the given
+// character policy. It returns ErrUnknownStrategy if no such strategy is registered.
+func New(name string, logger *logger.Logger, policy CharacterPolicy) (Cleaner, error) {
+	registryMutex.RLock()
+	factory, ok := registry[name]
+	registryMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, available strategies: %v", ErrUnknownStrategy, name, Strategies())
+	}
+	return factory(logger, policy)
+}
+
+// Strategies returns the sorted names of the registered cleaning strategies.
+func Strategies() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
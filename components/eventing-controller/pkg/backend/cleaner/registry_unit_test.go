@@ -0,0 +1,73 @@
+package cleaner //nolint:testpackage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+// upperCaseCleaner is a custom cleaning strategy for testing.
+type upperCaseCleaner struct{}
+
+func (upperCaseCleaner) CleanSource(source string) (string, error) {
+	return strings.ToUpper(source), nil
+}
+
+func (upperCaseCleaner) CleanEventType(eventType string) (string, error) {
+	return strings.ToUpper(eventType), nil
+}
+
+func Test_New(t *testing.T) {
+	require.NoError(t, Register("uppercase", func(*logger.Logger, CharacterPolicy) (Cleaner, error) { return upperCaseCleaner{}, nil }))
+
+	testCases := []struct {
+		name          string
+		givenStrategy string
+		wantCleaner   Cleaner
+		wantError     error
+	}{
+		{
+			name:          "should create the JetStream cleaner",
+			givenStrategy: StrategyJetStream,
+			wantCleaner:   &JetStreamCleaner{},
+		},
+		{
+			name:          "should create the EventMesh cleaner",
+			givenStrategy: StrategyEventMesh,
+			wantCleaner:   &EventMeshCleaner{},
+		},
+		{
+			name:          "should create a registered custom cleaner",
+			givenStrategy: "uppercase",
+			wantCleaner:   upperCaseCleaner{},
+		},
+		{
+			name:          "should fail for an unknown strategy",
+			givenStrategy: "unknown",
+			wantError:     ErrUnknownStrategy,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cleaner, err := New(tc.givenStrategy, nil, CharacterPolicy{})
+			require.ErrorIs(t, err, tc.wantError)
+			require.IsType(t, tc.wantCleaner, cleaner)
+		})
+	}
+}
+
+func Test_Register(t *testing.T) {
+	// when
+	err := Register(StrategyJetStream, func(*logger.Logger, CharacterPolicy) (Cleaner, error) { return upperCaseCleaner{}, nil })
+
+	// then
+	require.ErrorIs(t, err, ErrStrategyRegistered)
+	require.Contains(t, Strategies(), StrategyJetStream)
+	require.Contains(t, Strategies(), StrategyEventMesh)
+	require.Contains(t, Strategies(), StrategyKafka)
+}
@@ -9,9 +9,19 @@ type Cleaner interface {
 }
 
 type JetStreamCleaner struct {
-	logger *logger.Logger
+	logger          *logger.Logger
+	sourceFilter    characterFilter
+	eventTypeFilter characterFilter
 }
 
 type EventMeshCleaner struct {
-	logger *logger.Logger
+	logger          *logger.Logger
+	sourceFilter    characterFilter
+	eventTypeFilter characterFilter
+}
+
+type KafkaCleaner struct {
+	logger          *logger.Logger
+	sourceFilter    characterFilter
+	eventTypeFilter characterFilter
 }
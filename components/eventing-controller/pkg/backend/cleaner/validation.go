@@ -0,0 +1,62 @@
+package cleaner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// reservedSegments are the segments which must not remain in a cleaned event type,
+// because they are the wildcards of the NATS subjects.
+//
+//nolint:gochecknoglobals // constant lookup table
+var reservedSegments = map[string]bool{"*": true, ">": true}
+
+// ValidateEventTypes returns an error explaining the first of the given event types which the given Cleaner cleans
+// to an event type with an empty segment or a reserved segment, or to the same event type as another one of them.
+// The error message names the exact characters removed or replaced by the cleaning.
+func ValidateEventTypes(cleaner Cleaner, eventTypes []string) error {
+	originals := make(map[string]string, len(eventTypes))
+	for _, eventType := range eventTypes {
+		cleaned, err := cleaner.CleanEventType(eventType)
+		if err != nil {
+			return fmt.Errorf("type %q cannot be cleaned: %w", eventType, err)
+		}
+		for i, segment := range strings.Split(cleaned, eventTypeSegmentSeparator) {
+			if segment == "" {
+				return fmt.Errorf("type %q is cleaned to %q with the empty segment %d%s",
+					eventType, cleaned, i+1, becauseOf(cleaner, eventType))
+			}
+			if reservedSegments[segment] {
+				return fmt.Errorf("type %q is cleaned to %q with the reserved segment %q",
+					eventType, cleaned, segment)
+			}
+		}
+		if original, ok := originals[cleaned]; ok && original != eventType {
+			return fmt.Errorf("types %q and %q are both cleaned to %q%s",
+				original, eventType, cleaned, becauseOf(cleaner, original+eventType))
+		}
+		originals[cleaned] = eventType
+	}
+	return nil
+}
+
+// becauseOf returns the explanation naming the quoted characters of the given event type which the Cleaner
+// removes or replaces, or an empty string if there are none.
+func becauseOf(cleaner Cleaner, eventType string) string {
+	offending := map[rune]bool{}
+	for _, char := range eventType {
+		if cleaned, err := cleaner.CleanEventType(string(char)); err != nil || cleaned != string(char) {
+			offending[char] = true
+		}
+	}
+	chars := make([]string, 0, len(offending))
+	for char := range offending {
+		chars = append(chars, fmt.Sprintf("%q", char))
+	}
+	if len(chars) == 0 {
+		return ""
+	}
+	sort.Strings(chars)
+	return fmt.Sprintf(", because the characters %s are not supported", strings.Join(chars, ", "))
+}
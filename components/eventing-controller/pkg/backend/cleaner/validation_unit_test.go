@@ -0,0 +1,87 @@
+package cleaner //nolint:testpackage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+// wildcardCleaner is a Cleaner replacing the upper case letters of the event types by the NATS wildcard.
+type wildcardCleaner struct{}
+
+func (wildcardCleaner) CleanSource(source string) (string, error) {
+	return source, nil
+}
+
+func (wildcardCleaner) CleanEventType(eventType string) (string, error) {
+	return strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return '*'
+		}
+		return r
+	}, eventType), nil
+}
+
+func Test_ValidateEventTypes(t *testing.T) {
+	defaultLogger, err := logger.New("json", "info")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name            string
+		givenCleaner    Cleaner
+		givenEventTypes []string
+		wantErrText     string
+	}{
+		{
+			name:            "should accept event types which clean to distinct event types",
+			givenCleaner:    NewJetStreamCleaner(defaultLogger),
+			givenEventTypes: []string{"order.created.v1", "order.created>.v2"},
+		},
+		{
+			name:            "should reject an event type which cleans to an empty segment",
+			givenCleaner:    NewEventMeshCleaner(defaultLogger),
+			givenEventTypes: []string{"order.created.v1", "order.-_-.v1"},
+			wantErrText: `type "order.-_-.v1" is cleaned to "order..v1" with the empty segment 2, ` +
+				`because the characters '-', '_' are not supported`,
+		},
+		{
+			name:            "should reject an event type which has an empty segment already",
+			givenCleaner:    NewJetStreamCleaner(defaultLogger),
+			givenEventTypes: []string{"order..v1"},
+			wantErrText:     `type "order..v1" is cleaned to "order..v1" with the empty segment 2`,
+		},
+		{
+			name:            "should reject an event type which cleans to a reserved segment",
+			givenCleaner:    wildcardCleaner{},
+			givenEventTypes: []string{"order.X.v1"},
+			wantErrText:     `type "order.X.v1" is cleaned to "order.*.v1" with the reserved segment "*"`,
+		},
+		{
+			name:            "should reject event types which clean to the same event type",
+			givenCleaner:    NewJetStreamCleaner(defaultLogger),
+			givenEventTypes: []string{"order.created>.v1", "order.created.v1", "order.created *.v1"},
+			wantErrText: `types "order.created>.v1" and "order.created.v1" are both cleaned to "order.created.v1", ` +
+				`because the characters '>' are not supported`,
+		},
+		{
+			name:            "should accept duplicate event types",
+			givenCleaner:    NewJetStreamCleaner(defaultLogger),
+			givenEventTypes: []string{"order.created>.v1", "order.created>.v1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateEventTypes(tc.givenCleaner, tc.givenEventTypes)
+			if tc.wantErrText == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.wantErrText)
+		})
+	}
+}
@@ -147,6 +147,13 @@ func (em *EventMesh) SyncSubscription(subscription *eventingv1alpha2.Subscriptio
 			return false, err
 		}
 
+		// The labels are not part of the hashes, so an EventMesh subscription created before they were introduced
+		// is reported here and only stamped with its owner once it is recreated.
+		if !isEventMeshSubModified && !isOwnedBy(eventMeshServerSub, subscription) {
+			log.Infow("EventMesh subscription is not stamped with its owner", subscriptionNameLogKey,
+				eventMeshServerSub.Name, "owner", eventMeshServerSub.Labels)
+		}
+
 		// Make sure the EventMesh subscription was not deleted by checking
 		// the isEventMeshSubModified flag to be false.
 		if featureflags.IsEventingWebhookAuthEnabled() && !isEventMeshSubModified {
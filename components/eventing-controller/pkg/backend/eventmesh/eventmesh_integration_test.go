@@ -645,6 +645,12 @@ func Test_SyncSubscription(t *testing.T) {
 			changed, err := eventMesh.SyncSubscription(subscription, cleaner.NewEventMeshCleaner(defaultLogger), apiRule)
 			require.NoError(t, err)
 			require.Equal(t, tc.wantIsChanged, changed)
+
+			// then the EventMesh subscription is stamped with its owner
+			eventMeshSub, err := eventMesh.getSubscription(
+				nameMapper.MapSubscriptionName(subscription.Name, subscription.Namespace))
+			require.NoError(t, err)
+			require.True(t, isOwnedBy(eventMeshSub, subscription))
 		})
 	}
 
@@ -100,6 +100,13 @@ func statusFinalEventTypes(typeInfos []backendutils.EventTypeInfo) []eventingv1a
 	return finalEventTypes
 }
 
+// isOwnedBy returns true if the labels of the EventMesh subscription identify the given Subscription as its owner.
+func isOwnedBy(eventMeshSubscription *types.Subscription, owner *eventingv1alpha2.Subscription) bool {
+	return eventMeshSubscription.Labels[backendutils.OwnerMetadataNamespace] == owner.Namespace &&
+		eventMeshSubscription.Labels[backendutils.OwnerMetadataName] == owner.Name &&
+		eventMeshSubscription.Labels[backendutils.OwnerMetadataUID] == string(owner.UID)
+}
+
 // setEmsSubscriptionStatus sets the status of EventMesh Subscription in ev2Subscription.
 func setEmsSubscriptionStatus(subscription *eventingv1alpha2.Subscription,
 	eventMeshSubscription *types.Subscription) bool {
@@ -191,3 +191,50 @@ func Test_setEmsSubscriptionStatus(t *testing.T) {
 	require.Equal(t, kymaSubscription.Status.Backend.EventMeshSubscriptionStatus.LastFailedDeliveryReason,
 		eventMeshSubscription.LastFailedDeliveryReason)
 }
+
+func Test_isOwnedBy(t *testing.T) {
+	owner := eventingtesting.NewSubscription("test", "test")
+	owner.UID = "new-uid"
+
+	testCases := []struct {
+		name        string
+		givenLabels map[string]string
+		wantOwned   bool
+	}{
+		{
+			name:        "EventMesh subscription without labels should not be owned",
+			givenLabels: nil,
+			wantOwned:   false,
+		},
+		{
+			name: "EventMesh subscription of a previous subscription with the same name should not be owned",
+			givenLabels: map[string]string{
+				backendutils.OwnerMetadataNamespace: "test",
+				backendutils.OwnerMetadataName:      "test",
+				backendutils.OwnerMetadataUID:       "old-uid",
+			},
+			wantOwned: false,
+		},
+		{
+			name:        "EventMesh subscription stamped with the subscription should be owned",
+			givenLabels: backendutils.GetOwnerMetadata(owner),
+			wantOwned:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// given
+			eventMeshSubscription := eventingtesting.NewSampleEventMeshSubscription()
+			eventMeshSubscription.Labels = tc.givenLabels
+
+			// when
+			gotOwned := isOwnedBy(eventMeshSubscription, owner)
+
+			// then
+			require.Equal(t, tc.wantOwned, gotOwned)
+		})
+	}
+}
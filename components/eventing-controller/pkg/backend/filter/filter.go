@@ -0,0 +1,104 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cesql "github.com/cloudevents/sdk-go/sql/v2"
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+)
+
+const (
+	// AttributeType is the name of the CloudEvent type attribute in the filters.
+	AttributeType = "type"
+
+	attributeID              = "id"
+	attributeSource          = "source"
+	attributeSpecVersion     = "specversion"
+	attributeDataContentType = "datacontenttype"
+	attributeDataSchema      = "dataschema"
+	attributeSubject         = "subject"
+	attributeTime            = "time"
+)
+
+// Matches returns true if the given event matches all the given Subscription filters.
+// An empty list of filters matches every event.
+func Matches(filters []eventingv1alpha2.SubscriptionFilter, event *cev2event.Event) bool {
+	for _, f := range filters {
+		if !matchesFilter(f, event) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesExpression returns true if the given filter expression evaluates to true for the given event.
+// A nil expression matches every event. An expression which fails to evaluate, for example because it references
+// an attribute the event does not have, or which does not evaluate to a boolean does not match.
+func MatchesExpression(expression cesql.Expression, event *cev2event.Event) bool {
+	if expression == nil {
+		return true
+	}
+	result, err := expression.Evaluate(*event)
+	if err != nil {
+		return false
+	}
+	matched, ok := result.(bool)
+	return ok && matched
+}
+
+func matchesFilter(f eventingv1alpha2.SubscriptionFilter, event *cev2event.Event) bool {
+	for name, want := range f.Exact {
+		if got, ok := attributeValue(event, name); !ok || got != want {
+			return false
+		}
+	}
+	for name, want := range f.Prefix {
+		if got, ok := attributeValue(event, name); !ok || !strings.HasPrefix(got, want) {
+			return false
+		}
+	}
+	for name, want := range f.Suffix {
+		if got, ok := attributeValue(event, name); !ok || !strings.HasSuffix(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// attributeValue returns the string value of the CloudEvent context attribute or extension with the given name.
+// The second return value is false if the event does not have the attribute set.
+func attributeValue(event *cev2event.Event, name string) (string, bool) {
+	var value string
+	switch strings.ToLower(name) {
+	case attributeID:
+		value = event.ID()
+	case attributeSource:
+		value = event.Source()
+	case attributeSpecVersion:
+		value = event.SpecVersion()
+	case AttributeType:
+		value = event.Type()
+	case attributeDataContentType:
+		value = event.DataContentType()
+	case attributeDataSchema:
+		value = event.DataSchema()
+	case attributeSubject:
+		value = event.Subject()
+	case attributeTime:
+		if event.Time().IsZero() {
+			return "", false
+		}
+		value = event.Time().UTC().Format(time.RFC3339Nano)
+	default:
+		ext, ok := event.Extensions()[strings.ToLower(name)]
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("%v", ext), true
+	}
+	return value, value != ""
+}
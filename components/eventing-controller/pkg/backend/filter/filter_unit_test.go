@@ -0,0 +1,147 @@
+package filter_test
+
+import (
+	"testing"
+	"time"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/filter"
+)
+
+func Test_Matches(t *testing.T) {
+	t.Parallel()
+
+	event := cev2event.New(cev2event.CloudEventsVersionV1)
+	event.SetID("id-1")
+	event.SetType("order.created.v1")
+	event.SetSource("/default/shop")
+	event.SetSubject("orders/42")
+	event.SetExtension("region", "eu-central")
+	event.SetTime(time.Date(2023, 5, 4, 12, 30, 0, 500, time.FixedZone("CEST", 2*60*60)))
+
+	testCases := []struct {
+		name         string
+		givenFilters []eventingv1alpha2.SubscriptionFilter
+		wantMatch    bool
+	}{
+		{
+			name:         "should match if no filters are given",
+			givenFilters: nil,
+			wantMatch:    true,
+		},
+		{
+			name: "should match exact attribute and extension values",
+			givenFilters: []eventingv1alpha2.SubscriptionFilter{
+				{Exact: map[string]string{"type": "order.created.v1", "region": "eu-central"}},
+			},
+			wantMatch: true,
+		},
+		{
+			name: "should match prefix and suffix values",
+			givenFilters: []eventingv1alpha2.SubscriptionFilter{
+				{Prefix: map[string]string{"subject": "orders/"}},
+				{Suffix: map[string]string{"source": "/shop"}},
+			},
+			wantMatch: true,
+		},
+		{
+			name: "should match the time in UTC with nanoseconds",
+			givenFilters: []eventingv1alpha2.SubscriptionFilter{
+				{Exact: map[string]string{"time": "2023-05-04T10:30:00.0000005Z"}},
+			},
+			wantMatch: true,
+		},
+		{
+			name: "should not match if one filter does not match",
+			givenFilters: []eventingv1alpha2.SubscriptionFilter{
+				{Prefix: map[string]string{"subject": "orders/"}},
+				{Exact: map[string]string{"region": "us-east"}},
+			},
+			wantMatch: false,
+		},
+		{
+			name: "should not match if the attribute is missing",
+			givenFilters: []eventingv1alpha2.SubscriptionFilter{
+				{Exact: map[string]string{"tenant": "acme"}},
+			},
+			wantMatch: false,
+		},
+		{
+			name: "should not match if the suffix does not match",
+			givenFilters: []eventingv1alpha2.SubscriptionFilter{
+				{Suffix: map[string]string{"type": ".v2"}},
+			},
+			wantMatch: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.wantMatch, filter.Matches(tc.givenFilters, &event))
+		})
+	}
+}
+
+func Test_MatchesExpression(t *testing.T) {
+	t.Parallel()
+
+	event := cev2event.New(cev2event.CloudEventsVersionV1)
+	event.SetID("id-1")
+	event.SetType("order.created.v1")
+	event.SetSource("/default/shop")
+	event.SetSubject("orders/42")
+	event.SetExtension("region", "eu-central")
+	event.SetExtension("priority", 3)
+
+	testCases := []struct {
+		name            string
+		givenExpression string
+		wantMatch       bool
+	}{
+		{
+			name:            "should match if no expression is given",
+			givenExpression: "",
+			wantMatch:       true,
+		},
+		{
+			name:            "should match attributes and extensions",
+			givenExpression: "subject LIKE 'orders/%' AND region IN ('eu-central', 'us-east') AND priority > 2",
+			wantMatch:       true,
+		},
+		{
+			name:            "should not match if the expression evaluates to false",
+			givenExpression: "type = 'order.created.v1' AND region = 'us-east'",
+			wantMatch:       false,
+		},
+		{
+			name:            "should not match if the expression references a missing attribute",
+			givenExpression: "tenant = 'acme'",
+			wantMatch:       false,
+		},
+		{
+			name:            "should match a missing attribute checked with EXISTS",
+			givenExpression: "NOT EXISTS tenant",
+			wantMatch:       true,
+		},
+		{
+			name:            "should not match if the expression does not evaluate to a boolean",
+			givenExpression: "subject",
+			wantMatch:       false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			expression, err := eventingv1alpha2.ParseFilterExpression(tc.givenExpression)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantMatch, filter.MatchesExpression(expression, &event))
+		})
+	}
+}
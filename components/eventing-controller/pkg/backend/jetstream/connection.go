@@ -21,14 +21,7 @@ func NewConnectionBuilder(config env.NATSConfig) Builder {
 
 // Build connects to NATS and returns the connection. If an error occurs, ErrConnect is returned.
 func (b ConnectionBuilder) Build() (ConnectionInterface, error) {
-	config := b.config
-	jsOptions := []nats.Option{
-		nats.RetryOnFailedConnect(true),
-		nats.MaxReconnects(config.MaxReconnects),
-		nats.ReconnectWait(config.ReconnectWait),
-		nats.Name("Kyma Controller"),
-	}
-	conn, err := nats.Connect(config.URL, jsOptions...)
+	conn, err := nats.Connect(b.config.URL, connectionOptions(b.config)...)
 	if err != nil || !conn.IsConnected() {
 		return nil, pkgerrors.MakeError(ErrConnect, err)
 	}
@@ -42,4 +35,5 @@ type ConnectionInterface interface {
 	SetClosedHandler(cb nats.ConnHandler)
 	SetReconnectHandler(rcb nats.ConnHandler)
 	JetStream(opts ...nats.JSOpt) (nats.JetStreamContext, error)
+	Close()
 }
@@ -0,0 +1,112 @@
+package jetstream
+
+import (
+	"github.com/nats-io/nats.go"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	pkgerrors "github.com/kyma-project/kyma/components/eventing-controller/pkg/errors"
+)
+
+// coreSubscription is a core NATS subscription of a Subscription in the core delivery mode.
+type coreSubscription struct {
+	*nats.Subscription
+	// maxInFlight is the number of events the subscription dispatches concurrently.
+	maxInFlight int
+}
+
+// isCoreDeliveryMode returns true if the events of the subscription are delivered with core NATS.
+func (js *JetStream) isCoreDeliveryMode(subscription *eventingv1alpha2.Subscription) bool {
+	return subscription.GetDeliveryMode(&js.subsConfig) == eventingv1alpha2.DeliveryModeCore
+}
+
+// syncCoreSubscriptions subscribes to the subjects of the subscription with core NATS instead of JetStream consumers,
+// so the events are dispatched without acknowledgement and redelivery. The subjects are the same as in the JetStream
+// delivery mode, so the subscription receives the events published to the stream while it is subscribed.
+func (js *JetStream) syncCoreSubscriptions(subscription *eventingv1alpha2.Subscription,
+	callback nats.MsgHandler) error {
+	// delete the JetStream subscriptions and consumers left from the JetStream delivery mode
+	for key, jsSub := range js.subscriptions {
+		if !isJsSubAssociatedWithKymaSub(key, subscription) {
+			continue
+		}
+		if err := js.deleteSubscriptionFromJetStream(jsSub, key); err != nil {
+			return err
+		}
+	}
+
+	maxInFlight := subscription.GetMaxInFlightMessages(&js.subsConfig)
+	wanted := make(map[SubscriptionSubjectIdentifier]bool, len(subscription.Status.Types))
+	for _, eventType := range subscription.Status.Types {
+		subject := js.GetJetStreamSubject(subscription.Spec.Source, eventType.CleanType, subscription.Spec.TypeMatching)
+		key := NewSubscriptionSubjectIdentifier(subscription, subject)
+		wanted[key] = true
+
+		// the subscription is recreated if it was closed together with its connection or if maxInFlight changed
+		if current, ok := js.coreSubscriptions[key]; ok {
+			if current.IsValid() && current.maxInFlight == maxInFlight {
+				continue
+			}
+			if err := js.deleteCoreSubscription(key); err != nil {
+				return err
+			}
+		}
+
+		natsSubscription, err := js.Conn.Subscribe(subject, limitInFlight(callback, maxInFlight))
+		if err != nil {
+			return pkgerrors.MakeError(ErrFailedSubscribe, err)
+		}
+		js.coreSubscriptions[key] = &coreSubscription{Subscription: natsSubscription, maxInFlight: maxInFlight}
+		js.metricsCollector.RecordEventTypes(subscription.Name, subscription.Namespace, eventType.CleanType,
+			key.ConsumerName())
+	}
+
+	// delete the subscriptions of the removed event types
+	for key := range js.coreSubscriptions {
+		if isJsSubAssociatedWithKymaSub(key, subscription) && !wanted[key] {
+			if err := js.deleteCoreSubscription(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteCoreSubscriptions unsubscribes all core NATS subscriptions of the subscription.
+func (js *JetStream) deleteCoreSubscriptions(subscription *eventingv1alpha2.Subscription) error {
+	for key := range js.coreSubscriptions {
+		if !isJsSubAssociatedWithKymaSub(key, subscription) {
+			continue
+		}
+		if err := js.deleteCoreSubscription(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteCoreSubscription unsubscribes the core NATS subscription and removes it from the in-memory db.
+func (js *JetStream) deleteCoreSubscription(key SubscriptionSubjectIdentifier) error {
+	if current := js.coreSubscriptions[key]; current.IsValid() {
+		if err := current.Unsubscribe(); err != nil {
+			return pkgerrors.MakeError(ErrFailedUnsubscribe, err)
+		}
+	}
+	delete(js.coreSubscriptions, key)
+	return nil
+}
+
+// limitInFlight returns a message handler which dispatches the messages concurrently, at most maxInFlight at once.
+// The further messages are kept by the NATS client until its pending limits are reached, then they are dropped.
+func limitInFlight(callback nats.MsgHandler, maxInFlight int) nats.MsgHandler {
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+	inFlight := make(chan struct{}, maxInFlight)
+	return func(msg *nats.Msg) {
+		inFlight <- struct{}{}
+		go func() {
+			defer func() { <-inFlight }()
+			callback(msg)
+		}()
+	}
+}
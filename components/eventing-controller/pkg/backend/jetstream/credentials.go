@@ -0,0 +1,136 @@
+package jetstream
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+// connectionOptions returns the options of the NATS connections of the given configuration,
+// including its credentials and TLS material.
+func connectionOptions(config env.NATSConfig) []nats.Option {
+	opts := []nats.Option{
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(config.MaxReconnects),
+		nats.ReconnectWait(config.ReconnectWait),
+		nats.Name("Kyma Controller"),
+	}
+	if config.CredentialsFile != "" {
+		opts = append(opts, nats.UserCredentials(config.CredentialsFile))
+	}
+	if config.TLSCertFile != "" {
+		opts = append(opts, nats.ClientCert(config.TLSCertFile, config.TLSKeyFile))
+	}
+	if config.TLSCAFile != "" {
+		opts = append(opts, nats.RootCAs(config.TLSCAFile))
+	}
+	return opts
+}
+
+// credentialFiles returns the files of the credentials and TLS material of the given configuration.
+func credentialFiles(config env.NATSConfig) []string {
+	var files []string
+	for _, file := range []string{config.CredentialsFile, config.TLSCertFile, config.TLSKeyFile, config.TLSCAFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// WatchCredentials re-establishes the NATS connection whenever the files of the credentials or TLS material change,
+// so that they can be rotated without a restart. It blocks until the given context is done,
+// and returns immediately if no such files are configured.
+func (js *JetStream) WatchCredentials(ctx context.Context) error {
+	files := credentialFiles(js.Config)
+	if len(files) == 0 {
+		return nil
+	}
+	return watchFiles(ctx, files, js.namedLogger(), js.reconnect)
+}
+
+// reconnect closes the NATS connection and connects again with the current credentials and TLS material.
+// Closing the connection invalidates the NATS subscriptions, which are bound again by the reconciliation
+// triggered by the closed handler. It waits for the running backend operations of the reconciliations,
+// since they use the connection.
+func (js *JetStream) reconnect() {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	js.namedLogger().Infow("Re-establishing the NATS connection with the changed credentials")
+	if js.Conn != nil {
+		js.Conn.Close()
+	}
+	if err := js.initialize(js.connClosedHandler); err != nil {
+		js.namedLogger().Errorw("Failed to re-establish the NATS connection, retrying on the next reconciliation",
+			"error", err)
+	}
+}
+
+// watchFiles calls the given function whenever the content of any of the given files changes.
+// The directories of the files are watched instead of the files themselves, because the files of mounted Secrets
+// are replaced by swapping symbolic links. It blocks until the given context is done.
+func watchFiles(ctx context.Context, files []string, logger *zap.SugaredLogger, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			logger.Warnw("Failed to close the credentials watcher", "error", err)
+		}
+	}()
+	dirs := map[string]bool{}
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch the credentials directory %s: %w", dir, err)
+		}
+	}
+
+	// the initial error is ignored, because the connection reports the missing files already
+	checksum, _ := filesChecksum(files) //nolint:errcheck
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcher.Errors:
+			logger.Warnw("Failed to watch the credentials", "files", files, "error", err)
+		case <-watcher.Events:
+			// the events of unrelated files and the intermediate events of an update are skipped
+			current, err := filesChecksum(files)
+			if err != nil || bytes.Equal(current, checksum) {
+				continue
+			}
+			checksum = current
+			onChange()
+		}
+	}
+}
+
+// filesChecksum returns the checksum of the contents of the given files,
+// or an error if any of them cannot be read, such as during an update.
+func filesChecksum(files []string) ([]byte, error) {
+	hash := sha256.New()
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		hash.Write(content)
+		hash.Write([]byte{0})
+	}
+	return hash.Sum(nil), nil
+}
@@ -0,0 +1,61 @@
+//go:build unit
+
+package jetstream
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+func Test_credentialFiles(t *testing.T) {
+	// given
+	config := env.NATSConfig{
+		CredentialsFile: "/etc/nats/user.creds",
+		TLSCAFile:       "/etc/nats/ca.crt",
+	}
+
+	// when
+	files := credentialFiles(config)
+
+	// then
+	require.Equal(t, []string{"/etc/nats/user.creds", "/etc/nats/ca.crt"}, files)
+	require.Len(t, connectionOptions(config), len(connectionOptions(env.NATSConfig{}))+len(files))
+}
+
+func Test_watchFiles(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, []byte("cert"), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, []byte("key"), 0o600))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan struct{}, 10)
+	done := make(chan error)
+	go func() {
+		done <- watchFiles(ctx, []string{certFile, keyFile}, zap.NewNop().Sugar(), func() { changes <- struct{}{} })
+	}()
+
+	// when the credentials are rotated
+	rotations := 0
+	require.Eventually(t, func() bool {
+		// the file is rotated until the watcher is started and notices it
+		rotations++
+		err := os.WriteFile(keyFile, []byte(fmt.Sprintf("key %d", rotations)), 0o600)
+		return err == nil && len(changes) > 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// then
+	cancel()
+	require.NoError(t, <-done)
+}
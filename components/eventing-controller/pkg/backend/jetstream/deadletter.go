@@ -0,0 +1,105 @@
+package jetstream
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/deadletter"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+// isDeadLetterEnabled returns true if the events are moved to the dead-letter stream after their last failed delivery.
+func (js *JetStream) isDeadLetterEnabled() bool {
+	return js.Config.JSDeadLetterStreamName != ""
+}
+
+// getDeadLetterStreamConfig returns the config of the dead-letter stream. It keeps the events until they are
+// requeued or purged, or until the limits of the stream are reached, then the oldest events are discarded.
+func getDeadLetterStreamConfig(natsConfig env.NATSConfig) (*nats.StreamConfig, error) {
+	streamConfig, err := getStreamConfig(natsConfig)
+	if err != nil {
+		return nil, err
+	}
+	streamConfig.Name = natsConfig.JSDeadLetterStreamName
+	streamConfig.Subjects = []string{fmt.Sprintf("%s.>", natsConfig.JSDeadLetterSubjectPrefix)}
+	streamConfig.Retention = nats.LimitsPolicy
+	streamConfig.Discard = nats.DiscardOld
+	return streamConfig, nil
+}
+
+// ensureDeadLetterStreamExists creates or updates the dead-letter stream if it is enabled.
+func (js *JetStream) ensureDeadLetterStreamExists() error {
+	if !js.isDeadLetterEnabled() {
+		return nil
+	}
+	streamConfig, err := getDeadLetterStreamConfig(js.Config)
+	if err != nil {
+		return err
+	}
+	info, err := js.jsCtx.StreamInfo(streamConfig.Name)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		if _, err = js.jsCtx.AddStream(streamConfig); err != nil {
+			return fmt.Errorf("failed to create the dead-letter stream: %w", err)
+		}
+		js.namedLogger().Infow("Created the dead-letter stream", "stream", streamConfig.Name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !streamIsConfiguredCorrectly(info.Config, *streamConfig) {
+		if _, err = js.jsCtx.UpdateStream(streamConfig); err != nil {
+			return fmt.Errorf("failed to update the dead-letter stream: %w", err)
+		}
+		js.namedLogger().Infow("Updated the dead-letter stream", "stream", streamConfig.Name)
+	}
+	return nil
+}
+
+// deadLetter moves the event to the dead-letter stream with its original headers except the message ID, the original
+// subject and the details of the failed delivery, and terminates its redelivery.
+func (js *JetStream) deadLetter(msg *nats.Msg, subscriptionNamespace, subscriptionName, sink string, status int,
+	numDelivered uint64) error {
+	header := nats.Header{}
+	for key, values := range msg.Header {
+		header[key] = values
+	}
+	// the message ID set by the publisher would deduplicate the same event dead-lettered by another Subscription
+	header.Del(nats.MsgIdHdr)
+	header.Set(deadletter.HeaderSubject, msg.Subject)
+	header.Set(deadletter.HeaderSubscription, subscriptionNamespace+"/"+subscriptionName)
+	header.Set(deadletter.HeaderSink, sink)
+	header.Set(deadletter.HeaderStatus, strconv.Itoa(status))
+	header.Set(deadletter.HeaderDeliveries, strconv.FormatUint(numDelivered, 10))
+	deadLetterMsg := &nats.Msg{
+		Subject: js.Config.JSDeadLetterSubjectPrefix + "." + msg.Subject,
+		Header:  header,
+		Data:    msg.Data,
+	}
+	if _, err := js.GetJetStreamContext().PublishMsg(deadLetterMsg); err != nil {
+		return fmt.Errorf("failed to publish the event to the dead-letter stream: %w", err)
+	}
+	return msg.Term()
+}
+
+// nakOrDeadLetter NAKs the failed event to redeliver it after jsConsumerNakDelay, or moves it to the dead-letter
+// stream after its last delivery. If the dead-lettering fails, the event is NAKed and dropped by the consumer.
+func (js *JetStream) nakOrDeadLetter(msg *nats.Msg, subscriptionNamespace, subscriptionName, sink string,
+	status int, numDelivered uint64, ceLogger *zap.SugaredLogger) {
+	if js.isDeadLetterEnabled() && numDelivered >= jsConsumerMaxRedeliver {
+		err := js.deadLetter(msg, subscriptionNamespace, subscriptionName, sink, status, numDelivered)
+		if err == nil {
+			ceLogger.Warnw("Moved the CloudEvent to the dead-letter stream after its last delivery",
+				"stream", js.Config.JSDeadLetterStreamName, "attempts", numDelivered)
+			return
+		}
+		ceLogger.Errorw("Failed to move the CloudEvent to the dead-letter stream", "error", err)
+	}
+	if err := msg.NakWithDelay(jsConsumerNakDelay); err != nil {
+		ceLogger.Errorw("failed to NAK an event on JetStream")
+	}
+}
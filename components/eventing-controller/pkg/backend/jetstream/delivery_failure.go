@@ -0,0 +1,66 @@
+package jetstream
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	http2 "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+)
+
+// DeliveryFailureReason classifies the given result of a failed delivery to a sink into one of the bounded
+// failure reasons of the delivery failures metric.
+func DeliveryFailureReason(result error) string {
+	var res *http2.Result
+	if cev2.ResultAs(result, &res) {
+		switch {
+		case res.StatusCode >= http.StatusBadRequest && res.StatusCode < http.StatusInternalServerError:
+			return backendmetrics.FailureReasonClientError
+		case res.StatusCode >= http.StatusInternalServerError:
+			return backendmetrics.FailureReasonServerError
+		default:
+			return backendmetrics.FailureReasonOther
+		}
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(result, &dnsErr) {
+		return backendmetrics.FailureReasonDNS
+	}
+	if isTLSError(result) {
+		return backendmetrics.FailureReasonTLS
+	}
+	if errors.Is(result, syscall.ECONNREFUSED) {
+		return backendmetrics.FailureReasonConnectionRefused
+	}
+	var netErr net.Error
+	if errors.Is(result, context.DeadlineExceeded) || (errors.As(result, &netErr) && netErr.Timeout()) {
+		return backendmetrics.FailureReasonTimeout
+	}
+	return backendmetrics.FailureReasonOther
+}
+
+// isTLSError reports whether the given error is caused by a failed TLS handshake or certificate verification.
+func isTLSError(err error) bool {
+	var (
+		recordHeaderErr     tls.RecordHeaderError
+		alertErr            tls.AlertError
+		verificationErr     *tls.CertificateVerificationError
+		unknownAuthorityErr x509.UnknownAuthorityError
+		hostnameErr         x509.HostnameError
+		invalidErr          x509.CertificateInvalidError
+	)
+	return errors.As(err, &recordHeaderErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &verificationErr) ||
+		errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
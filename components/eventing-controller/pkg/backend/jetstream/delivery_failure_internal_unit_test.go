@@ -0,0 +1,99 @@
+//go:build unit
+
+package jetstream
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	cev2protocol "github.com/cloudevents/sdk-go/v2/protocol"
+	http2 "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+
+	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+)
+
+func Test_DeliveryFailureReason(t *testing.T) {
+	// transportError wraps the given error the same way the CloudEvents HTTP client does for failed requests.
+	transportError := func(err error) error {
+		return cev2protocol.NewReceipt(false, "%w", &url.Error{Op: "Post", URL: "http://sink", Err: err})
+	}
+
+	testCases := []struct {
+		name       string
+		givenError error
+		wantReason string
+	}{
+		{
+			name:       "should classify a 4xx response as client error",
+			givenError: http2.NewResult(http.StatusNotFound, "%w", cev2protocol.ResultNACK),
+			wantReason: backendmetrics.FailureReasonClientError,
+		},
+		{
+			name:       "should classify a 5xx response as server error",
+			givenError: http2.NewResult(http.StatusBadGateway, "%w", cev2protocol.ResultNACK),
+			wantReason: backendmetrics.FailureReasonServerError,
+		},
+		{
+			name:       "should classify a 3xx response as other",
+			givenError: http2.NewResult(http.StatusFound, "%w", cev2protocol.ResultNACK),
+			wantReason: backendmetrics.FailureReasonOther,
+		},
+		{
+			name:       "should classify an exceeded deadline as timeout",
+			givenError: transportError(context.DeadlineExceeded),
+			wantReason: backendmetrics.FailureReasonTimeout,
+		},
+		{
+			name: "should classify a network timeout as timeout",
+			givenError: transportError(&net.OpError{
+				Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded,
+			}),
+			wantReason: backendmetrics.FailureReasonTimeout,
+		},
+		{
+			name: "should classify a refused connection",
+			givenError: transportError(&net.OpError{
+				Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED),
+			}),
+			wantReason: backendmetrics.FailureReasonConnectionRefused,
+		},
+		{
+			name: "should classify an unresolvable host as dns",
+			givenError: transportError(&net.OpError{
+				Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "sink", IsNotFound: true},
+			}),
+			wantReason: backendmetrics.FailureReasonDNS,
+		},
+		{
+			name:       "should classify an unknown certificate authority as tls",
+			givenError: transportError(x509.UnknownAuthorityError{}),
+			wantReason: backendmetrics.FailureReasonTLS,
+		},
+		{
+			name:       "should classify any other error as other",
+			givenError: transportError(errors.New("unexpected EOF")),
+			wantReason: backendmetrics.FailureReasonOther,
+		},
+		{
+			name:       "should classify an error not returned by the HTTP client as other",
+			givenError: fmt.Errorf("failed to send: %w", errors.New("boom")),
+			wantReason: backendmetrics.FailureReasonOther,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.wantReason, DeliveryFailureReason(tc.givenError))
+		})
+	}
+}
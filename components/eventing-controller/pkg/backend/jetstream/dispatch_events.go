@@ -0,0 +1,127 @@
+package jetstream
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/controllers/events"
+)
+
+const (
+	// dispatchEventInterval is the minimum interval between two Kubernetes Events of the same kind on a subscription.
+	dispatchEventInterval = 5 * time.Minute
+
+	dispatchFailedKind           = "failed"
+	dispatchRetriesExhaustedKind = "exhausted"
+)
+
+// dispatchEvents records rate limited Kubernetes Events on the subscriptions for notable dispatch incidents,
+// so that users can see in the subscription description why events are not delivered.
+type dispatchEvents struct {
+	recorder record.EventRecorder
+	interval time.Duration
+	now      func() time.Time
+
+	// subscriptions stores the subscription objects the Events are recorded on.
+	subscriptions sync.Map
+	// failing stores the subscriptions for which the last dispatch failed.
+	failing sync.Map
+
+	mutex        sync.Mutex
+	lastRecorded map[string]time.Time
+}
+
+func newDispatchEvents(recorder record.EventRecorder, interval time.Duration) *dispatchEvents {
+	return &dispatchEvents{
+		recorder:     recorder,
+		interval:     interval,
+		now:          time.Now,
+		lastRecorded: map[string]time.Time{},
+	}
+}
+
+// register stores the object reference of the given subscription to record Events on it.
+func (d *dispatchEvents) register(subscription *eventingv1alpha2.Subscription) {
+	if d == nil {
+		return
+	}
+	d.subscriptions.Store(createKeyPrefix(subscription), &eventingv1alpha2.Subscription{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Subscription",
+			APIVersion: eventingv1alpha2.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      subscription.Name,
+			Namespace: subscription.Namespace,
+			UID:       subscription.UID,
+		},
+	})
+}
+
+// unregister removes all the data stored for the subscription with the given key prefix.
+func (d *dispatchEvents) unregister(subKeyPrefix string) {
+	if d == nil {
+		return
+	}
+	d.subscriptions.Delete(subKeyPrefix)
+	d.failing.Delete(subKeyPrefix)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, kind := range []string{dispatchFailedKind, dispatchRetriesExhaustedKind} {
+		delete(d.lastRecorded, rateLimitKey(subKeyPrefix, kind))
+	}
+}
+
+// dispatchSucceeded resets the failing state of the subscription with the given key prefix.
+func (d *dispatchEvents) dispatchSucceeded(subKeyPrefix string) {
+	if d == nil {
+		return
+	}
+	d.failing.Delete(subKeyPrefix)
+}
+
+// dispatchFailed records an Event for the first failed dispatch after a successful one
+// and for events which are dropped because all delivery attempts are exhausted.
+func (d *dispatchEvents) dispatchFailed(subKeyPrefix, sink, eventID string, status int, numDelivered uint64) {
+	if d == nil {
+		return
+	}
+	subscription, ok := d.subscriptions.Load(subKeyPrefix)
+	if !ok {
+		return
+	}
+	obj, ok := subscription.(*eventingv1alpha2.Subscription)
+	if !ok {
+		return
+	}
+	if _, alreadyFailing := d.failing.LoadOrStore(subKeyPrefix, true); !alreadyFailing &&
+		d.allow(rateLimitKey(subKeyPrefix, dispatchFailedKind)) {
+		events.Warn(d.recorder, obj, events.ReasonDispatchFailed,
+			"Failed to dispatch events to sink %s: %d %s", sink, status, http.StatusText(status))
+	}
+	if numDelivered >= jsConsumerMaxRedeliver && d.allow(rateLimitKey(subKeyPrefix, dispatchRetriesExhaustedKind)) {
+		events.Warn(d.recorder, obj, events.ReasonDispatchRetriesExhausted,
+			"Dropped event %s after %d failed delivery attempts to sink %s", eventID, numDelivered, sink)
+	}
+}
+
+// allow returns true if no Event with the given rate limit key was recorded within the interval.
+func (d *dispatchEvents) allow(key string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	now := d.now()
+	if last, ok := d.lastRecorded[key]; ok && now.Sub(last) < d.interval {
+		return false
+	}
+	d.lastRecorded[key] = now
+	return true
+}
+
+func rateLimitKey(subKeyPrefix, kind string) string {
+	return subKeyPrefix + "/" + kind
+}
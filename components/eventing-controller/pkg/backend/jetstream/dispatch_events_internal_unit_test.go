@@ -0,0 +1,101 @@
+//go:build unit
+
+package jetstream
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+
+	subtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func Test_DispatchEvents(t *testing.T) {
+	// given
+	sub := subtesting.NewSubscription("sub", "ns", subtesting.WithSink("http://sink.ns.svc.cluster.local"))
+	subKeyPrefix := createKeyPrefix(sub)
+	now := time.Now()
+
+	testCases := []struct {
+		name       string
+		givenCalls func(d *dispatchEvents)
+		wantEvents int
+	}{
+		{
+			name: "should record an event for the first failure only",
+			givenCalls: func(d *dispatchEvents) {
+				d.dispatchFailed(subKeyPrefix, sub.Spec.Sink, "id-1", http.StatusBadGateway, 1)
+				d.dispatchFailed(subKeyPrefix, sub.Spec.Sink, "id-2", http.StatusBadGateway, 1)
+			},
+			wantEvents: 1,
+		},
+		{
+			name: "should rate limit the failure events after a recovery",
+			givenCalls: func(d *dispatchEvents) {
+				d.dispatchFailed(subKeyPrefix, sub.Spec.Sink, "id-1", http.StatusBadGateway, 1)
+				d.dispatchSucceeded(subKeyPrefix)
+				d.dispatchFailed(subKeyPrefix, sub.Spec.Sink, "id-2", http.StatusBadGateway, 1)
+			},
+			wantEvents: 1,
+		},
+		{
+			name: "should record a failure event again after the interval",
+			givenCalls: func(d *dispatchEvents) {
+				d.dispatchFailed(subKeyPrefix, sub.Spec.Sink, "id-1", http.StatusBadGateway, 1)
+				d.dispatchSucceeded(subKeyPrefix)
+				d.now = func() time.Time { return now.Add(dispatchEventInterval) }
+				d.dispatchFailed(subKeyPrefix, sub.Spec.Sink, "id-2", http.StatusBadGateway, 1)
+			},
+			wantEvents: 2,
+		},
+		{
+			name: "should record an event if the delivery attempts are exhausted",
+			givenCalls: func(d *dispatchEvents) {
+				d.dispatchFailed(subKeyPrefix, sub.Spec.Sink, "id-1", http.StatusBadGateway, 1)
+				d.dispatchFailed(subKeyPrefix, sub.Spec.Sink, "id-1", http.StatusBadGateway, jsConsumerMaxRedeliver)
+			},
+			wantEvents: 2,
+		},
+		{
+			name: "should not record events for unregistered subscriptions",
+			givenCalls: func(d *dispatchEvents) {
+				d.unregister(subKeyPrefix)
+				d.dispatchFailed(subKeyPrefix, sub.Spec.Sink, "id-1", http.StatusBadGateway, jsConsumerMaxRedeliver)
+			},
+			wantEvents: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			d := newDispatchEvents(recorder, dispatchEventInterval)
+			d.now = func() time.Time { return now }
+			d.register(sub)
+
+			// when
+			tc.givenCalls(d)
+
+			// then
+			require.Len(t, recorder.Events, tc.wantEvents)
+		})
+	}
+}
+
+func Test_DispatchEventsWithoutRecorder(t *testing.T) {
+	// given
+	var d *dispatchEvents
+	sub := subtesting.NewSubscription("sub", "ns")
+
+	// then
+	require.NotPanics(t, func() {
+		d.register(sub)
+		d.dispatchFailed(createKeyPrefix(sub), "", "id", http.StatusBadGateway, jsConsumerMaxRedeliver)
+		d.dispatchSucceeded(createKeyPrefix(sub))
+		d.unregister(createKeyPrefix(sub))
+	})
+}
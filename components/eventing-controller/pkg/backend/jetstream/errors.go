@@ -11,10 +11,17 @@ var (
 	ErrAddConsumer         = errors.New("failed to add a consumer")
 	ErrGetConsumer         = errors.New("failed to get consumer info")
 	ErrUpdateConsumer      = errors.New("failed to update consumer")
+	ErrMaxInFlightLimit    = errors.New("maxInFlightMessages exceeds the JetStream limit")
 	ErrDeleteConsumer      = errors.New("failed to delete consumer")
 	ErrFailedSubscribe     = errors.New("failed to create NATS JetStream subscription")
 	ErrFailedUnsubscribe   = errors.New("failed to unsubscribe from NATS JetStream")
 
+	ErrCABundleLoaderNotSet = errors.New("failed to load the sink CA bundle, no loader is set")
+	ErrLoadCABundle         = errors.New("failed to load the sink CA bundle")
+	ErrTokenProviderNotSet  = errors.New("failed to authenticate at the sink, no ServiceAccount token provider is set")
+
+	ErrInvalidFilterExpression = errors.New("invalid filter expression")
+
 	ErrConnect           = errors.New("failed to connect to NATS JetStream")
 	ErrEmptyStreamName   = errors.New("stream name cannot be empty")
 	ErrStreamNameTooLong = fmt.Errorf("stream name should be max %d characters long", jsMaxStreamNameLength)
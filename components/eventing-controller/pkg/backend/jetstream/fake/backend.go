@@ -0,0 +1,306 @@
+// Package fake provides an in-memory implementation of the JetStream backend for unit tests,
+// which do not need to run a NATS server.
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"k8s.io/apimachinery/pkg/types"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+	backendutilsv2 "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+// compile-time check
+var _ jetstream.Backend = &Backend{}
+
+// Errors are the errors returned by the methods of the fake Backend, nil errors let the methods succeed.
+type Errors struct {
+	Initialize              error
+	SyncSubscription        error
+	DeleteSubscription      error
+	DeleteSubscriptionsOnly error
+	CountMatchingSubjects   error
+	CountPendingMessages    error
+	DeleteInvalidConsumers  error
+}
+
+// Backend is an in-memory JetStream backend. It records the synchronized subscriptions and their consumers,
+// which can be inspected by the tests, and returns the errors injected by SetErrors.
+type Backend struct {
+	mutex             sync.Mutex
+	config            env.NATSConfig
+	cleaner           cleaner.Cleaner
+	initialized       bool
+	connClosedHandler backendutilsv2.ConnClosedHandler
+	// subscriptions are copies of the synchronized subscriptions by their namespaced name.
+	subscriptions map[types.NamespacedName]*eventingv1alpha2.Subscription
+	// consumers are the namespaced names of the subscriptions by the names of their consumers.
+	consumers map[string]types.NamespacedName
+	// matchingSubjects are the numbers of the concrete subjects matched by a subject.
+	matchingSubjects map[string]int
+	// pendingMessages are the numbers of the pending messages by the namespaced name of the subscriptions.
+	pendingMessages map[types.NamespacedName]int
+	errors          Errors
+	calls           map[string]int
+}
+
+// NewBackend returns an empty fake Backend using the given configuration, and the cleaner
+// to build the JetStream subjects of the subscriptions.
+func NewBackend(config env.NATSConfig, cleaner cleaner.Cleaner) *Backend {
+	return &Backend{
+		config:           config,
+		cleaner:          cleaner,
+		subscriptions:    map[types.NamespacedName]*eventingv1alpha2.Subscription{},
+		consumers:        map[string]types.NamespacedName{},
+		matchingSubjects: map[string]int{},
+		pendingMessages:  map[types.NamespacedName]int{},
+		calls:            map[string]int{},
+	}
+}
+
+func (b *Backend) Initialize(connCloseHandler backendutilsv2.ConnClosedHandler) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.calls["Initialize"]++
+	if b.errors.Initialize != nil {
+		return b.errors.Initialize
+	}
+	b.initialized = true
+	b.connClosedHandler = connCloseHandler
+	return nil
+}
+
+func (b *Backend) SyncSubscription(subscription *eventingv1alpha2.Subscription) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.calls["SyncSubscription"]++
+	if b.errors.SyncSubscription != nil {
+		return b.errors.SyncSubscription
+	}
+	consumers, err := b.consumerNames(subscription)
+	if err != nil {
+		return err
+	}
+	key := namespacedName(subscription)
+	b.subscriptions[key] = subscription.DeepCopy()
+	for _, consumer := range consumers {
+		b.consumers[consumer] = key
+	}
+	return nil
+}
+
+func (b *Backend) DeleteSubscription(subscription *eventingv1alpha2.Subscription) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.calls["DeleteSubscription"]++
+	if b.errors.DeleteSubscription != nil {
+		return b.errors.DeleteSubscription
+	}
+	key := namespacedName(subscription)
+	delete(b.subscriptions, key)
+	delete(b.pendingMessages, key)
+	for consumer, owner := range b.consumers {
+		if owner == key {
+			delete(b.consumers, consumer)
+		}
+	}
+	return nil
+}
+
+func (b *Backend) DeleteSubscriptionsOnly(subscription *eventingv1alpha2.Subscription) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.calls["DeleteSubscriptionsOnly"]++
+	if b.errors.DeleteSubscriptionsOnly != nil {
+		return b.errors.DeleteSubscriptionsOnly
+	}
+	// the consumers are kept, as they are bound again by the next synchronization
+	delete(b.subscriptions, namespacedName(subscription))
+	return nil
+}
+
+func (b *Backend) GetJetStreamSubjects(source string, subjects []string,
+	typeMatching eventingv1alpha2.TypeMatching) []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.getJetStreamSubjects(source, subjects, typeMatching)
+}
+
+// getJetStreamSubjects appends the prefix and the cleaned source to the subjects, the same as the JetStream backend.
+func (b *Backend) getJetStreamSubjects(source string, subjects []string,
+	typeMatching eventingv1alpha2.TypeMatching) []string {
+	var result []string
+	for _, subject := range subjects {
+		if typeMatching == eventingv1alpha2.TypeMatchingExact {
+			result = append(result, fmt.Sprintf("%s.%s", b.config.JSSubjectPrefix, subject))
+			continue
+		}
+		cleanSource, _ := b.cleaner.CleanSource(source)
+		result = append(result, fmt.Sprintf("%s.%s.%s", b.config.JSSubjectPrefix, cleanSource, subject))
+	}
+	return result
+}
+
+// consumerNames returns the names of the consumers of the cleaned types of the subscription.
+func (b *Backend) consumerNames(subscription *eventingv1alpha2.Subscription) ([]string, error) {
+	cleanedTypes := jetstream.GetCleanEventTypes(subscription, b.cleaner)
+	subjects := b.getJetStreamSubjects(subscription.Spec.Source,
+		jetstream.GetCleanEventTypesFromEventTypes(cleanedTypes), subscription.Spec.TypeMatching)
+	jsTypes, err := jetstream.GetBackendJetStreamTypes(subscription, subjects)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(jsTypes))
+	for _, jsType := range jsTypes {
+		names = append(names, jsType.ConsumerName)
+	}
+	return names, nil
+}
+
+// CountMatchingSubjects returns the number of concrete subjects set by SetMatchingSubjects, or 0.
+func (b *Backend) CountMatchingSubjects(subject string) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.calls["CountMatchingSubjects"]++
+	if b.errors.CountMatchingSubjects != nil {
+		return 0, b.errors.CountMatchingSubjects
+	}
+	return b.matchingSubjects[subject], nil
+}
+
+// CountPendingMessages returns the number of pending messages set by SetPendingMessages, or 0.
+func (b *Backend) CountPendingMessages(subscription *eventingv1alpha2.Subscription) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.calls["CountPendingMessages"]++
+	if b.errors.CountPendingMessages != nil {
+		return 0, b.errors.CountPendingMessages
+	}
+	return b.pendingMessages[namespacedName(subscription)], nil
+}
+
+// DeleteInvalidConsumers deletes the consumers which do not belong to the types of the given subscriptions.
+func (b *Backend) DeleteInvalidConsumers(subscriptions []eventingv1alpha2.Subscription) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.calls["DeleteInvalidConsumers"]++
+	if b.errors.DeleteInvalidConsumers != nil {
+		return b.errors.DeleteInvalidConsumers
+	}
+	used := map[string]bool{}
+	for i := range subscriptions {
+		consumers, err := b.consumerNames(&subscriptions[i])
+		if err != nil {
+			return err
+		}
+		for _, consumer := range consumers {
+			used[consumer] = true
+		}
+	}
+	for consumer := range b.consumers {
+		if !used[consumer] {
+			delete(b.consumers, consumer)
+		}
+	}
+	return nil
+}
+
+// GetJetStreamContext returns nil, as there is no NATS connection.
+func (b *Backend) GetJetStreamContext() nats.JetStreamContext {
+	return nil
+}
+
+func (b *Backend) GetConfig() env.NATSConfig {
+	return b.config
+}
+
+// SetErrors sets the errors returned by the methods of the Backend from now on.
+func (b *Backend) SetErrors(errors Errors) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.errors = errors
+}
+
+// SetMatchingSubjects sets the number of concrete subjects returned by CountMatchingSubjects for the subject.
+func (b *Backend) SetMatchingSubjects(subject string, count int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.matchingSubjects[subject] = count
+}
+
+// SetPendingMessages sets the number of pending messages returned by CountPendingMessages for the subscription.
+func (b *Backend) SetPendingMessages(subscription types.NamespacedName, count int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.pendingMessages[subscription] = count
+}
+
+// CloseConnection calls the handler passed to Initialize, as if the NATS connection was closed.
+func (b *Backend) CloseConnection() {
+	b.mutex.Lock()
+	handler := b.connClosedHandler
+	b.mutex.Unlock()
+	if handler != nil {
+		handler(nil)
+	}
+}
+
+// Initialized returns true if Initialize succeeded.
+func (b *Backend) Initialized() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.initialized
+}
+
+// Subscription returns a copy of the subscription as it was synchronized last, or false if it is not synchronized.
+func (b *Backend) Subscription(name types.NamespacedName) (*eventingv1alpha2.Subscription, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	subscription, ok := b.subscriptions[name]
+	if !ok {
+		return nil, false
+	}
+	return subscription.DeepCopy(), true
+}
+
+// Subscriptions returns the sorted namespaced names of the synchronized subscriptions.
+func (b *Backend) Subscriptions() []types.NamespacedName {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	names := make([]types.NamespacedName, 0, len(b.subscriptions))
+	for name := range b.subscriptions {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i].String() < names[j].String() })
+	return names
+}
+
+// Consumers returns the sorted names of the consumers.
+func (b *Backend) Consumers() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	consumers := make([]string, 0, len(b.consumers))
+	for consumer := range b.consumers {
+		consumers = append(consumers, consumer)
+	}
+	sort.Strings(consumers)
+	return consumers
+}
+
+// Calls returns how often the method of the given name was called.
+func (b *Backend) Calls(method string) int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.calls[method]
+}
+
+func namespacedName(subscription *eventingv1alpha2.Subscription) types.NamespacedName {
+	return types.NamespacedName{Namespace: subscription.Namespace, Name: subscription.Name}
+}
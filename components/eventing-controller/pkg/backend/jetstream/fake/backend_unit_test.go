@@ -0,0 +1,73 @@
+package fake_test
+
+import (
+	"errors"
+	"testing"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream/fake"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	evtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func Test_Backend(t *testing.T) {
+	// given
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+	backend := fake.NewBackend(env.NATSConfig{JSSubjectPrefix: "kyma"}, cleaner.NewJetStreamCleaner(l))
+	require.NoError(t, backend.Initialize(nil))
+	require.True(t, backend.Initialized())
+
+	sub1 := evtesting.NewSubscription("sub1", "test",
+		evtesting.WithSource(evtesting.EventSourceClean),
+		evtesting.WithEventType(evtesting.OrderCreatedV1Event),
+	)
+	sub2 := evtesting.NewSubscription("sub2", "test",
+		evtesting.WithSource(evtesting.EventSourceClean),
+		evtesting.WithEventType(evtesting.OrderCreatedV1Event),
+	)
+
+	// when
+	require.NoError(t, backend.SyncSubscription(sub1))
+	require.NoError(t, backend.SyncSubscription(sub2))
+
+	// then
+	require.Equal(t, []types.NamespacedName{{Namespace: "test", Name: "sub1"}, {Namespace: "test", Name: "sub2"}},
+		backend.Subscriptions())
+	synced, ok := backend.Subscription(types.NamespacedName{Namespace: "test", Name: "sub1"})
+	require.True(t, ok)
+	require.Equal(t, sub1.Spec, synced.Spec)
+	require.Len(t, backend.Consumers(), 2)
+	require.Equal(t, []string{"kyma." + evtesting.EventSourceClean + "." + evtesting.OrderCreatedV1Event},
+		backend.GetJetStreamSubjects(sub1.Spec.Source, sub1.Spec.Types, eventingv1alpha2.TypeMatchingStandard))
+
+	// when the subscription is deleted without its consumers
+	require.NoError(t, backend.DeleteSubscriptionsOnly(sub2))
+
+	// then
+	require.Equal(t, []types.NamespacedName{{Namespace: "test", Name: "sub1"}}, backend.Subscriptions())
+	require.Len(t, backend.Consumers(), 2)
+
+	// when the invalid consumers are deleted
+	require.NoError(t, backend.DeleteInvalidConsumers([]eventingv1alpha2.Subscription{*sub1}))
+
+	// then
+	require.Len(t, backend.Consumers(), 1)
+
+	// when the errors are injected
+	syncErr := errors.New("sync error")
+	backend.SetErrors(fake.Errors{SyncSubscription: syncErr})
+
+	// then
+	require.ErrorIs(t, backend.SyncSubscription(sub2), syncErr)
+	require.NoError(t, backend.DeleteSubscription(sub1))
+	require.Empty(t, backend.Subscriptions())
+	require.Empty(t, backend.Consumers())
+	require.Equal(t, 3, backend.Calls("SyncSubscription"))
+}
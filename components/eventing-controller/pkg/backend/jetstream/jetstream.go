@@ -3,9 +3,12 @@ package jetstream
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"reflect"
 	"strings"
+	"syscall"
 	"time"
 
 	http2 "github.com/cloudevents/sdk-go/v2/protocol/http"
@@ -13,16 +16,21 @@ import (
 	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
 	pkgerrors "github.com/kyma-project/kyma/components/eventing-controller/pkg/errors"
 
+	cesql "github.com/cloudevents/sdk-go/sql/v2"
 	cev2 "github.com/cloudevents/sdk-go/v2"
 	cev2protocol "github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/nats-io/nats.go"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"k8s.io/client-go/tools/record"
 
 	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/audit"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/filter"
 	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/deadletter"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/tracing"
 	"github.com/kyma-project/kyma/components/eventing-controller/utils"
@@ -32,6 +40,7 @@ var _ Backend = &JetStream{}
 
 const (
 	jsHandlerName          = "jetstream-handler"
+	dispatcherName         = "dispatcher"
 	jsMaxStreamNameLength  = 32
 	idleHeartBeatDuration  = 1 * time.Minute
 	jsConsumerMaxRedeliver = 100
@@ -41,34 +50,61 @@ const (
 )
 
 func NewJetStream(config env.NATSConfig, metricsCollector *backendmetrics.Collector,
-	cleaner cleaner.Cleaner, subsConfig env.DefaultSubscriptionConfig, logger *logger.Logger) *JetStream {
+	cleaner cleaner.Cleaner, subsConfig env.DefaultSubscriptionConfig, log *logger.Logger) *JetStream {
 	return &JetStream{
-		Config:           config,
-		logger:           logger,
-		subscriptions:    make(map[SubscriptionSubjectIdentifier]Subscriber),
-		metricsCollector: metricsCollector,
-		cleaner:          cleaner,
-		subsConfig:       subsConfig,
+		Config:             config,
+		logger:             log,
+		subscriptions:      make(map[SubscriptionSubjectIdentifier]Subscriber),
+		coreSubscriptions:  make(map[SubscriptionSubjectIdentifier]*coreSubscription),
+		metricsCollector:   metricsCollector,
+		cleaner:            cleaner,
+		subsConfig:         subsConfig,
+		dispatchLogSampler: logger.NewSampler(config.DispatchLogSamplesPerSecond),
 	}
 }
 
+// SetAuditor enables the audit records of the consumers created, updated, and deleted by the backend.
+func (js *JetStream) SetAuditor(auditor *audit.Auditor) {
+	js.auditor = auditor
+}
+
+// SetEventRecorder enables recording Kubernetes Events on the subscriptions for dispatch failures.
+func (js *JetStream) SetEventRecorder(recorder record.EventRecorder) {
+	js.dispatchEvents = newDispatchEvents(recorder, dispatchEventInterval)
+}
+
 func (js *JetStream) Initialize(connCloseHandler backendutils.ConnClosedHandler) error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	return js.initialize(connCloseHandler)
+}
+
+// initialize connects to NATS JetStream and ensures the streams exist. The caller must hold the mutex.
+func (js *JetStream) initialize(connCloseHandler backendutils.ConnClosedHandler) error {
 	if err := js.validateConfig(); err != nil {
 		return err
 	}
+	if err := js.initValidator(); err != nil {
+		return err
+	}
 	if err := js.initNATSConn(connCloseHandler); err != nil {
 		return err
 	}
 	if err := js.initJSContext(); err != nil {
 		return err
 	}
-	if err := js.initCloudEventClient(js.Config); err != nil {
+	if err := js.initCloudEventClient(); err != nil {
 		return err
 	}
-	return js.ensureStreamExistsAndIsConfiguredCorrectly()
+	if err := js.ensureStreamExistsAndIsConfiguredCorrectly(); err != nil {
+		return err
+	}
+	return js.ensureDeadLetterStreamExists()
 }
 
 func (js *JetStream) SyncSubscription(subscription *eventingv1alpha2.Subscription) error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
 	subKeyPrefix := createKeyPrefix(subscription)
 	if err := js.checkJetStreamConnection(); err != nil {
 		return err
@@ -83,8 +119,43 @@ func (js *JetStream) SyncSubscription(subscription *eventingv1alpha2.Subscriptio
 		js.sinks.Store(subKeyPrefix, subscription.Spec.Sink)
 	}
 
+	// add/update the attribute filters for callbacks
+	if len(subscription.Spec.Filters) > 0 {
+		js.filters.Store(subKeyPrefix, subscription.Spec.Filters)
+	} else {
+		js.filters.Delete(subKeyPrefix)
+	}
+
+	// add/update the filter expression for callbacks
+	expression, err := eventingv1alpha2.ParseFilterExpression(subscription.Spec.FilterExpression)
+	if err != nil {
+		return pkgerrors.MakeError(ErrInvalidFilterExpression, err)
+	}
+	if expression != nil {
+		js.expressions.Store(subKeyPrefix, expression)
+	} else {
+		js.expressions.Delete(subKeyPrefix)
+	}
+
+	// add/update the dispatch client for sinks with custom TLS settings or a ServiceAccount
+	if err := js.syncSinkClient(subscription); err != nil {
+		return err
+	}
+
+	// add/update the subscription reference for dispatch Events
+	js.dispatchEvents.register(subscription)
+
+	if js.isCoreDeliveryMode(subscription) {
+		callback := js.getCallback(subKeyPrefix, subscription.Namespace, subscription.Name, false)
+		return js.syncCoreSubscriptions(subscription, callback)
+	}
+	// delete the core NATS subscriptions left from the core delivery mode
+	if err := js.deleteCoreSubscriptions(subscription); err != nil {
+		return err
+	}
+
 	// async callback for maxInflight messages
-	callback := js.getCallback(subKeyPrefix, subscription.Name)
+	callback := js.getCallback(subKeyPrefix, subscription.Namespace, subscription.Name, true)
 	asyncCallback := func(m *nats.Msg) {
 		go callback(m)
 	}
@@ -97,6 +168,8 @@ func (js *JetStream) SyncSubscription(subscription *eventingv1alpha2.Subscriptio
 }
 
 func (js *JetStream) DeleteSubscription(subscription *eventingv1alpha2.Subscription) error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
 	// checking the status of the connection is important
 	if err := js.checkJetStreamConnection(); err != nil {
 		return err
@@ -111,24 +184,33 @@ func (js *JetStream) DeleteSubscription(subscription *eventingv1alpha2.Subscript
 			return err
 		}
 	}
+	if err := js.deleteCoreSubscriptions(subscription); err != nil {
+		return err
+	}
 
 	// cleanup consumers on nats-server
 	// in-case data in js.subscriptions[] was lost due to handler restart
 	for _, subject := range subscription.Status.Types {
 		jsSubject := js.GetJetStreamSubject(subscription.Spec.Source, subject.CleanType, subscription.Spec.TypeMatching)
 		jsSubKey := NewSubscriptionSubjectIdentifier(subscription, jsSubject)
-		if err := js.deleteConsumerFromJetStream(jsSubKey.ConsumerName()); err != nil {
+		if err := js.deleteConsumerFromJetStream(jsSubKey.ConsumerName(), createKeyPrefix(subscription)); err != nil {
 			return err
 		}
 	}
 
-	// delete subscription sink info from storage
+	// delete subscription sink and filters info from storage
 	js.sinks.Delete(createKeyPrefix(subscription))
+	js.filters.Delete(createKeyPrefix(subscription))
+	js.expressions.Delete(createKeyPrefix(subscription))
+	js.dispatchEvents.unregister(createKeyPrefix(subscription))
+	js.deleteSinkClient(createKeyPrefix(subscription))
 
 	return nil
 }
 
 func (js *JetStream) DeleteSubscriptionsOnly(subscription *eventingv1alpha2.Subscription) error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
 	js.namedLogger().Infow(
 		"Delete JetStream subscriptions",
 		"namespace", subscription.Namespace,
@@ -148,6 +230,27 @@ func (js *JetStream) DeleteSubscriptionsOnly(subscription *eventingv1alpha2.Subs
 		}
 	}
 
+	return js.deleteCoreSubscriptions(subscription)
+}
+
+// DeleteAllSubscriptionsOnly unsubscribes all the JetStream subscriptions without deleting their consumers,
+// so the events are retained in the stream until the subscriptions are created again.
+// The core NATS subscriptions are unsubscribed as well, their events are not retained.
+func (js *JetStream) DeleteAllSubscriptionsOnly() error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	js.namedLogger().Infow("Delete all JetStream subscriptions", "count", len(js.subscriptions),
+		"coreCount", len(js.coreSubscriptions))
+	for key, jsSub := range js.subscriptions {
+		if err := js.deleteSubscriptionFromJetStreamOnly(jsSub, key); err != nil {
+			return err
+		}
+	}
+	for key := range js.coreSubscriptions {
+		if err := js.deleteCoreSubscription(key); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -163,6 +266,8 @@ func (js *JetStream) GetJetStreamSubjects(source string, subjects []string,
 
 // GetJetStreamContext returns the current JetStreamContext.
 func (js *JetStream) GetJetStreamContext() nats.JetStreamContext {
+	js.connMutex.RLock()
+	defer js.connMutex.RUnlock()
 	return js.jsCtx
 }
 
@@ -175,18 +280,67 @@ func (js *JetStream) GetJetStreamSubject(source, subject string, typeMatching ev
 	return fmt.Sprintf("%s.%s.%s", js.Config.JSSubjectPrefix, cleanSource, subject)
 }
 
+// CountMatchingSubjects returns the number of concrete subjects in the stream matched by the given subject,
+// which can contain wildcards.
+func (js *JetStream) CountMatchingSubjects(subject string) (int, error) {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	if js.Conn == nil || js.Conn.Status() != nats.CONNECTED {
+		return 0, ErrConnect
+	}
+	info, err := js.jsCtx.StreamInfo(js.Config.JSStreamName, &nats.StreamInfoRequest{SubjectsFilter: subject})
+	if err != nil {
+		return 0, err
+	}
+	return len(info.State.Subjects), nil
+}
+
+// CountPendingMessages returns the number of messages of the subscription which are either not delivered
+// or not acknowledged yet by the sink, summed up over all its consumers.
+func (js *JetStream) CountPendingMessages(subscription *eventingv1alpha2.Subscription) (int, error) {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	if js.Conn == nil || js.Conn.Status() != nats.CONNECTED {
+		return 0, ErrConnect
+	}
+	pending := 0
+	for _, t := range subscription.Status.Backend.Types {
+		info, err := js.jsCtx.ConsumerInfo(js.Config.JSStreamName, t.ConsumerName)
+		if errors.Is(err, nats.ErrConsumerNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, pkgerrors.MakeError(ErrGetConsumer, err)
+		}
+		pending += int(info.NumPending) + info.NumAckPending
+	}
+	return pending, nil
+}
+
 // DeleteInvalidConsumers deletes all JetStream consumers having no subscription event types in subscription resources.
 func (js *JetStream) DeleteInvalidConsumers(subscriptions []eventingv1alpha2.Subscription) error {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
 	consumers := js.jsCtx.Consumers(js.Config.JSStreamName)
 	for con := range consumers {
+		if js.isConsumerUsedByKymaSub(con.Name, subscriptions) {
+			continue
+		}
+		// the consumers of the bridge are not owned by any Subscription
+		if _, ok := con.Config.Metadata[ConsumerMetadataBridgeCluster]; ok {
+			continue
+		}
 		// consumer should have no interest and no subscription types to delete it
-		if !con.PushBound && !js.isConsumerUsedByKymaSub(con.Name, subscriptions) {
-			if err := js.deleteConsumerFromJetStream(con.Name); err != nil {
-				return err
-			}
-			js.namedLogger().Infow("Dangling JetStream consumer is deleted", "name", con.Name,
-				"description", con.Config.Description)
+		if con.PushBound {
+			js.namedLogger().Warnw("Dangling JetStream consumer is still bound and not deleted", "name", con.Name,
+				"description", con.Config.Description, "owner", con.Config.Metadata)
+			continue
 		}
+		if err := js.deleteConsumerFromJetStream(con.Name, consumerOwner(con.Config)); err != nil {
+			return err
+		}
+		js.namedLogger().Infow("Dangling JetStream consumer is deleted", "name", con.Name,
+			"description", con.Config.Description, "owner", con.Config.Metadata)
 	}
 	return nil
 }
@@ -196,6 +350,10 @@ func (js *JetStream) isConsumerUsedByKymaSub(consumerName string, subscriptions
 		return false
 	}
 	for ix := range subscriptions {
+		// the consumers of the subscriptions in the core delivery mode are left from the JetStream delivery mode
+		if js.isCoreDeliveryMode(&subscriptions[ix]) {
+			continue
+		}
 		cleanedTypes := GetCleanEventTypes(&subscriptions[ix], js.cleaner)
 		jsSubjects := js.GetJetStreamSubjects(
 			subscriptions[ix].Spec.Source,
@@ -221,6 +379,28 @@ func (js *JetStream) getJetStreamSubject(source, subject string, typeMatching ev
 	return fmt.Sprintf("%s.%s.%s", js.Config.JSSubjectPrefix, cleanSource, subject)
 }
 
+// consumerFilterSubject returns the filter subject of the consumer of the given subject. With the exact type
+// matching, an exact type filter matched by the subject narrows it down to that type, so that the consumer only
+// receives the events of the type instead of receiving all the events of the subject to drop them.
+// With the standard type matching, the subject already consists of the exact source and type.
+func (js *JetStream) consumerFilterSubject(subscription *eventingv1alpha2.Subscription, jsSubject string) string {
+	if subscription.Spec.TypeMatching != eventingv1alpha2.TypeMatchingExact {
+		return jsSubject
+	}
+	for _, f := range subscription.Spec.Filters {
+		eventType, ok := f.Exact[filter.AttributeType]
+		// the NATS wildcards and whitespaces are literal characters of the type, which a subject cannot express
+		if !ok || eventType == "" || strings.ContainsAny(eventType, "*> \t\r\n") {
+			continue
+		}
+		narrowed := js.GetJetStreamSubject(subscription.Spec.Source, eventType, subscription.Spec.TypeMatching)
+		if SubjectMatches(jsSubject, narrowed) {
+			return narrowed
+		}
+	}
+	return jsSubject
+}
+
 func (js *JetStream) validateConfig() error {
 	if js.Config.JSStreamName == "" {
 		return errors.New("Stream name cannot be empty")
@@ -242,31 +422,60 @@ func (js *JetStream) validateConfig() error {
 
 func (js *JetStream) initNATSConn(connCloseHandler backendutils.ConnClosedHandler) error {
 	if js.Conn == nil || js.Conn.Status() != nats.CONNECTED {
-		jsOptions := []nats.Option{
-			nats.RetryOnFailedConnect(true),
-			nats.MaxReconnects(js.Config.MaxReconnects),
-			nats.ReconnectWait(js.Config.ReconnectWait),
-			nats.Name("Kyma Controller"),
-		}
-		conn, err := nats.Connect(js.Config.URL, jsOptions...)
+		conn, err := nats.Connect(js.Config.URL, connectionOptions(js.Config)...)
 		if err != nil || !conn.IsConnected() {
 			return fmt.Errorf("failed to connect to NATS JetStream: %w", err)
 		}
+		js.connMutex.Lock()
 		js.Conn = conn
+		js.connMutex.Unlock()
 		js.connClosedHandler = connCloseHandler
 		if js.connClosedHandler != nil {
 			js.Conn.SetClosedHandler(nats.ConnHandler(js.connClosedHandler))
 		}
 		js.Conn.SetReconnectHandler(js.handleReconnect)
+		js.Conn.SetDisconnectErrHandler(js.handleDisconnect)
+		js.metricsCollector.RecordNATSConnected()
 	}
 	return nil
 }
 
+func (js *JetStream) handleDisconnect(_ *nats.Conn, err error) {
+	js.namedLogger().Infow("Called disconnect handler for JetStream", "error", err)
+	js.metricsCollector.RecordNATSDisconnect(disconnectReason(err))
+}
+
+// disconnectReason classifies the given error of a NATS disconnect into one of the bounded disconnect reasons
+// of the last disconnect reason metric.
+func disconnectReason(err error) string {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return backendmetrics.DisconnectReasonClosed
+	case errors.Is(err, nats.ErrStaleConnection):
+		return backendmetrics.DisconnectReasonStaleConnection
+	case errors.Is(err, nats.ErrAuthorization), errors.Is(err, nats.ErrAuthExpired), errors.Is(err, nats.ErrAuthRevoked):
+		return backendmetrics.DisconnectReasonAuthorization
+	case errors.Is(err, io.EOF), errors.Is(err, syscall.ECONNRESET):
+		return backendmetrics.DisconnectReasonConnectionReset
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return backendmetrics.DisconnectReasonTimeout
+	default:
+		return backendmetrics.DisconnectReasonOther
+	}
+}
+
 func (js *JetStream) handleReconnect(_ *nats.Conn) {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
 	js.namedLogger().Infow("Called reconnect handler for JetStream")
+	js.metricsCollector.RecordNATSReconnect()
 	if err := js.ensureStreamExistsAndIsConfiguredCorrectly(); err != nil {
 		js.namedLogger().Errorw("Failed to ensure the stream exists", "error", err)
 	}
+	if err := js.ensureDeadLetterStreamExists(); err != nil {
+		js.namedLogger().Errorw("Failed to ensure the dead-letter stream exists", "error", err)
+	}
 }
 
 func (js *JetStream) ensureStreamExistsAndIsConfiguredCorrectly() error {
@@ -280,6 +489,7 @@ func (js *JetStream) ensureStreamExistsAndIsConfiguredCorrectly() error {
 		if err != nil {
 			return err
 		}
+		js.syncMaxAckPendingLimit(info)
 		js.namedLogger().Infow("Stream not found, created a new Stream",
 			"stream-info", info)
 		return nil
@@ -293,14 +503,44 @@ func (js *JetStream) ensureStreamExistsAndIsConfiguredCorrectly() error {
 		if err != nil {
 			return err
 		}
+		js.syncMaxAckPendingLimit(newInfo)
 		js.namedLogger().Infow("Updated existing Stream:", "stream-info", newInfo)
 		return nil
 	}
 
+	js.syncMaxAckPendingLimit(info)
 	js.namedLogger().Infow("Reusing existing Stream", "stream-info", info)
 	return nil
 }
 
+// syncMaxAckPendingLimit stores the lowest MaxAckPending limit of the stream consumers and the JetStream account.
+func (js *JetStream) syncMaxAckPendingLimit(streamInfo *nats.StreamInfo) {
+	limit := streamInfo.Config.ConsumerLimits.MaxAckPending
+	accountInfo, err := js.jsCtx.AccountInfo()
+	if err != nil {
+		js.namedLogger().Warnw("Failed to get the JetStream account limits", "error", err)
+	} else if accountLimit := accountInfo.Limits.MaxAckPending; accountLimit > 0 && (limit <= 0 || accountLimit < limit) {
+		limit = accountLimit
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	js.maxAckPendingLimit.Store(int64(limit))
+}
+
+// checkMaxInFlightLimit returns an error if the maxInFlight value of the Subscription exceeds the MaxAckPending
+// limit of the stream consumers or of the JetStream account, since NATS would not apply it to the consumers.
+func (js *JetStream) checkMaxInFlightLimit(subscription *eventingv1alpha2.Subscription) error {
+	limit := int(js.maxAckPendingLimit.Load())
+	maxInFlight := subscription.GetMaxInFlightMessages(&js.subsConfig)
+	if limit > 0 && maxInFlight > limit {
+		return pkgerrors.MakeError(ErrMaxInFlightLimit,
+			fmt.Errorf("maxInFlightMessages %d is greater than the MaxAckPending limit %d of the stream %s",
+				maxInFlight, limit, js.Config.JSStreamName))
+	}
+	return nil
+}
+
 func streamIsConfiguredCorrectly(got nats.StreamConfig, want nats.StreamConfig) bool {
 	// only comparing the fields which we define in stream config.
 	if got.Name != want.Name ||
@@ -320,22 +560,17 @@ func (js *JetStream) initJSContext() error {
 	if err != nil {
 		return fmt.Errorf("failed to create the JetStream context: %w", err)
 	}
+	js.connMutex.Lock()
 	js.jsCtx = jsCtx
+	js.connMutex.Unlock()
 	return nil
 }
 
-func (js *JetStream) initCloudEventClient(config env.NATSConfig) error {
+func (js *JetStream) initCloudEventClient() error {
 	if js.client != nil {
 		return nil
 	}
-	transport := &http.Transport{
-		MaxIdleConns:        config.MaxIdleConns,
-		MaxConnsPerHost:     config.MaxConnsPerHost,
-		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
-		IdleConnTimeout:     config.IdleConnTimeout,
-	}
-
-	client, err := cev2.NewClientHTTP(cev2.WithRoundTripper(transport))
+	client, _, err := js.newCloudEventClient(nil)
 	if err != nil {
 		return err
 	}
@@ -346,7 +581,7 @@ func (js *JetStream) initCloudEventClient(config env.NATSConfig) error {
 // checkJetStreamConnection reconnects to the server if the server is not connected.
 func (js *JetStream) checkJetStreamConnection() error {
 	if js.Conn == nil || js.Conn.Status() != nats.CONNECTED {
-		if err := js.Initialize(js.connClosedHandler); err != nil {
+		if err := js.initialize(js.connClosedHandler); err != nil {
 			return fmt.Errorf("failed to connect to JetStream with status %d: %w", js.Conn.Status(), err)
 		}
 	}
@@ -389,7 +624,7 @@ func (js *JetStream) cleanupUnnecessaryJetStreamSubscribers(
 	if err != nil {
 		if errors.Is(err, nats.ErrConsumerNotFound) {
 			log.Infow("Deleting invalid Consumer!")
-			if err = js.deleteConsumerFromJetStream(key.ConsumerName()); err != nil {
+			if err = js.deleteConsumerFromJetStream(key.ConsumerName(), createKeyPrefix(subscription)); err != nil {
 				return err
 			}
 			delete(js.subscriptions, key)
@@ -423,15 +658,20 @@ func (js *JetStream) runtimeSubscriptionExistsInKymaSub(runtimeSubscriptionKey S
 }
 
 // consumerSubjectExistsInKymaSub checks if the specified consumer is used by the subscription.
+// The filter subject of the consumer is compared with the subjects narrowed by the type filters of the subscription,
+// since the consumer is created with those.
 func (js *JetStream) consumerSubjectExistsInKymaSub(consumer *nats.ConsumerInfo,
 	subscription *eventingv1alpha2.Subscription) bool {
-	return utils.ContainsString(
-		js.GetJetStreamSubjects(
-			subscription.Spec.Source,
-			GetCleanEventTypesFromEventTypes(subscription.Status.Types),
-			subscription.Spec.TypeMatching),
-		consumer.Config.FilterSubject,
-	)
+	jsSubjects := js.GetJetStreamSubjects(
+		subscription.Spec.Source,
+		GetCleanEventTypesFromEventTypes(subscription.Status.Types),
+		subscription.Spec.TypeMatching)
+	for _, jsSubject := range jsSubjects {
+		if js.consumerFilterSubject(subscription, jsSubject) == consumer.Config.FilterSubject {
+			return true
+		}
+	}
+	return false
 }
 
 // deleteSubscriptionFromJetStream deletes subscription from NATS server and from in-memory db.
@@ -444,7 +684,8 @@ func (js *JetStream) deleteSubscriptionFromJetStream(jsSub Subscriber, jsSubKey
 	}
 
 	// delete the consumer manually, since it was created by hand, too
-	if consDelErr := js.deleteConsumerFromJetStream(jsSubKey.ConsumerName()); consDelErr != nil {
+	consDelErr := js.deleteConsumerFromJetStream(jsSubKey.ConsumerName(), jsSubKey.NamespacedName())
+	if consDelErr != nil {
 		return consDelErr
 	}
 
@@ -480,23 +721,42 @@ func (js *JetStream) revertEventTypeToOriginal(event *cev2.Event, sugaredLogger
 	sugaredLogger.Debugw("type reverted to original type by trimming prefixes")
 }
 
-func (js *JetStream) getCallback(subKeyPrefix, subscriptionName string) nats.MsgHandler {
+// getCallback returns the handler dispatching the messages of the subscription to its sink. The messages are
+// acknowledged only if acknowledge is true, because core NATS messages cannot be acknowledged.
+func (js *JetStream) getCallback(subKeyPrefix, subscriptionNamespace, subscriptionName string,
+	acknowledge bool) nats.MsgHandler {
 	return func(msg *nats.Msg) {
+		// the correlation ID is read from the message header until the CloudEvent is converted
+		dispatchLogger := js.namedLogger().Named(dispatcherName)
+		correlationID := msg.Header.Get(tracing.CorrelationIDNATSHeader)
 		// fetch sink info from storage
 		sinkValue, ok := js.sinks.Load(subKeyPrefix)
 		if !ok {
-			js.namedLogger().Errorw("Failed to find sink URL in storage", "keyPrefix", subKeyPrefix)
+			dispatchLogger.Errorw("Failed to find sink URL in storage", "keyPrefix", subKeyPrefix,
+				tracing.CorrelationIDLogKey, correlationID)
 			return
 		}
 		// convert interface type to string
 		sink, ok := sinkValue.(string)
 		if !ok {
-			js.namedLogger().Errorw("Failed to convert sink value to string", "sinkValue", sinkValue)
+			dispatchLogger.Errorw("Failed to convert sink value to string", "sinkValue", sinkValue,
+				tracing.CorrelationIDLogKey, correlationID)
+			return
+		}
+		// skip the events requeued from the dead-letter stream for another subscription
+		if !deadletter.IsRequeuedFor(msg.Header, subscriptionNamespace, subscriptionName) {
+			if acknowledge {
+				if ackErr := msg.Ack(); ackErr != nil {
+					dispatchLogger.Errorw("Failed to ACK an event requeued for another subscription",
+						tracing.CorrelationIDLogKey, correlationID)
+				}
+			}
 			return
 		}
 		ce, err := backendutils.ConvertMsgToCE(msg)
 		if err != nil {
-			js.namedLogger().Errorw("Failed to convert JetStream message to CloudEvent", "error", err)
+			dispatchLogger.Errorw("Failed to convert JetStream message to CloudEvent", "error", err,
+				tracing.CorrelationIDLogKey, correlationID)
 			return
 		}
 
@@ -505,18 +765,47 @@ func (js *JetStream) getCallback(subKeyPrefix, subscriptionName string) nats.Msg
 		defer cancel()
 		ctxWithCE := cev2.ContextWithTarget(ctxWithCancel, sink)
 		traceCtxWithCE := tracing.AddTracingHeadersToContext(ctxWithCE, ce)
+		traceID := tracing.TraceIDFromContext(traceCtxWithCE)
 
 		// decorate the logger with CloudEvent context
-		ceLogger := js.namedLogger().With("id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", sink)
+		ceLogger := js.dispatchLogSampler.Sampled(dispatchLogger, subKeyPrefix).
+			With("id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", sink,
+				tracing.CorrelationIDLogKey, tracing.CorrelationID(ce))
+
+		// skip the dispatching if the event violates the CloudEvents specification
+		if !js.validateEvent(msg, ce, subscriptionNamespace, subscriptionName, sink, acknowledge, ceLogger) {
+			return
+		}
 
 		// revert the event type to original form
 		js.revertEventTypeToOriginal(ce, ceLogger)
 
+		// skip the dispatching if the event does not match the subscription filters
+		if !js.matchesFilters(subKeyPrefix, ce) {
+			if !acknowledge {
+				ceLogger.Debugw("CloudEvent was filtered out by the subscription filters")
+				return
+			}
+			if ackErr := msg.Ack(); ackErr != nil {
+				ceLogger.Errorw("Failed to ACK a filtered event on JetStream")
+			}
+			ceLogger.Debugw("CloudEvent was filtered out by the subscription filters")
+			return
+		}
+
+		var numDelivered uint64
+		if metadata, metadataErr := msg.Metadata(); metadataErr == nil {
+			numDelivered = metadata.NumDelivered
+		}
+		if numDelivered > 1 {
+			ceLogger.Debugw("Redelivering the CloudEvent", "attempt", numDelivered)
+		}
+
 		ceLogger.Debugw("Sending the CloudEvent")
 
 		// dispatch the event to sink
 		start := time.Now()
-		result := js.client.Send(traceCtxWithCE, *ce)
+		result := js.getSinkClient(subKeyPrefix).Send(traceCtxWithCE, *ce)
 		duration := time.Since(start)
 		var res *http2.Result
 		if !cev2protocol.IsACK(result) {
@@ -525,22 +814,30 @@ func (js *JetStream) getCallback(subKeyPrefix, subscriptionName string) nats.Msg
 				status = res.StatusCode
 			}
 
-			js.metricsCollector.RecordDeliveryPerSubscription(subscriptionName, ce.Type(), sink, status)
-			js.metricsCollector.RecordLatencyPerSubscription(duration, subscriptionName, ce.Type(), sink, status)
+			js.metricsCollector.RecordDeliveryPerSubscription(subscriptionNamespace, subscriptionName, ce.Type(), sink,
+				status, traceID)
+			js.metricsCollector.RecordLatencyPerSubscription(duration, subscriptionName, ce.Type(), sink, status, traceID)
+			js.metricsCollector.RecordSinkDelivery(subscriptionNamespace, subscriptionName, sink, duration, status)
+			js.metricsCollector.RecordDeliveryFailure(subscriptionNamespace, subscriptionName, sink,
+				DeliveryFailureReason(result))
 
 			// NAK the msg with a delay so it is redelivered after jsConsumerNakDelay period.
-			if err := msg.NakWithDelay(jsConsumerNakDelay); err != nil {
-				js.namedLogger().Errorw("failed to NAK an event on JetStream")
+			if acknowledge {
+				js.nakOrDeadLetter(msg, subscriptionNamespace, subscriptionName, sink, status, numDelivered, ceLogger)
 			}
 
+			js.dispatchEvents.dispatchFailed(subKeyPrefix, sink, ce.ID(), status, numDelivered)
+
 			ceLogger.Errorw("Failed to dispatch the CloudEvent", "error", result.Error())
 			return
 		}
 
 		// event was successfully dispatched, check if acknowledged by the NATS server
 		// if not, the message is redelivered.
-		if ackErr := msg.Ack(); ackErr != nil {
-			ceLogger.Errorw("Failed to ACK an event on JetStream")
+		if acknowledge {
+			if ackErr := msg.Ack(); ackErr != nil {
+				ceLogger.Errorw("Failed to ACK an event on JetStream")
+			}
 		}
 
 		status := http.StatusOK
@@ -548,27 +845,71 @@ func (js *JetStream) getCallback(subKeyPrefix, subscriptionName string) nats.Msg
 			status = res.StatusCode
 		}
 
-		js.metricsCollector.RecordDeliveryPerSubscription(subscriptionName, ce.Type(), sink, status)
-		js.metricsCollector.RecordLatencyPerSubscription(duration, subscriptionName, ce.Type(), sink, status)
+		js.metricsCollector.RecordDeliveryPerSubscription(subscriptionNamespace, subscriptionName, ce.Type(), sink,
+			status, traceID)
+		js.metricsCollector.RecordLatencyPerSubscription(duration, subscriptionName, ce.Type(), sink, status, traceID)
+		js.metricsCollector.RecordSinkDelivery(subscriptionNamespace, subscriptionName, sink, duration, status)
+		js.dispatchEvents.dispatchSucceeded(subKeyPrefix)
 		ceLogger.Debugw("CloudEvent was dispatched")
 	}
 }
 
-// deleteConsumerFromJS deletes consumer on NATS Server.
-func (js *JetStream) deleteConsumerFromJetStream(name string) error {
-	if err := js.jsCtx.DeleteConsumer(js.Config.JSStreamName, name); err != nil &&
-		!errors.Is(err, nats.ErrConsumerNotFound) {
+// matchesFilters returns true if the event matches the attribute filters and the filter expression
+// of the subscription identified by the given key prefix.
+func (js *JetStream) matchesFilters(subKeyPrefix string, ce *cev2.Event) bool {
+	if value, ok := js.filters.Load(subKeyPrefix); ok {
+		filters, ok := value.([]eventingv1alpha2.SubscriptionFilter)
+		if !ok {
+			js.namedLogger().Errorw("Failed to convert filters value", "filtersValue", value)
+		} else if !filter.Matches(filters, ce) {
+			return false
+		}
+	}
+	if value, ok := js.expressions.Load(subKeyPrefix); ok {
+		expression, ok := value.(cesql.Expression)
+		if !ok {
+			js.namedLogger().Errorw("Failed to convert filter expression value", "expressionValue", value)
+			return true
+		}
+		return filter.MatchesExpression(expression, ce)
+	}
+	return true
+}
+
+// deleteConsumerFromJS deletes consumer on NATS Server. The owner is the namespaced name of the subscription of
+// the consumer, if it is known.
+func (js *JetStream) deleteConsumerFromJetStream(name, owner string) error {
+	if err := js.jsCtx.DeleteConsumer(js.Config.JSStreamName, name); err != nil {
+		if errors.Is(err, nats.ErrConsumerNotFound) {
+			return nil
+		}
 		// if it is not a Not Found error, then return error
 		return utils.MakeConsumerError(ErrDeleteConsumer, err, name)
 	}
+	js.auditConsumer(audit.OperationDelete, name, owner, nil)
 
 	return nil
 }
 
+// auditConsumer records a change of a consumer made by the backend.
+func (js *JetStream) auditConsumer(operation, name, owner string, changes []string) {
+	js.auditor.Write(audit.Record{
+		User:         audit.ControllerUser,
+		Operation:    operation,
+		Kind:         audit.KindJetStreamConsumer,
+		Name:         name,
+		Subscription: owner,
+		Changes:      changes,
+	})
+}
+
 // syncConsumerAndSubscription makes sure there is a consumer and subscription created on the NATS Backend.
 // these also must be bound to each other to ensure that NATS JetStream eventing logic works as expected.
 func (js *JetStream) syncConsumerAndSubscription(subscription *eventingv1alpha2.Subscription,
 	asyncCallback func(m *nats.Msg)) error {
+	if err := js.checkMaxInFlightLimit(subscription); err != nil {
+		return err
+	}
 	for _, eventType := range subscription.Status.Types {
 		jsSubject := js.GetJetStreamSubject(subscription.Spec.Source, eventType.CleanType, subscription.Spec.TypeMatching)
 		jsSubKey := NewSubscriptionSubjectIdentifier(subscription, jsSubject)
@@ -577,6 +918,10 @@ func (js *JetStream) syncConsumerAndSubscription(subscription *eventingv1alpha2.
 		if err != nil {
 			return err
 		}
+		// the filter subject is updated before binding a NATS Subscription, which must subscribe to it
+		if consumerInfo, err = js.syncConsumerFilterSubject(subscription, consumerInfo, jsSubject); err != nil {
+			return err
+		}
 
 		natsSubscription, subExists := js.subscriptions[jsSubKey]
 
@@ -599,6 +944,9 @@ func (js *JetStream) syncConsumerAndSubscription(subscription *eventingv1alpha2.
 		}
 
 		// checks and updates the NATS consumer configs in case they are not up-to-date with the Subscription CR.
+		if consumerInfo, err = js.syncConsumerOwner(subscription, consumerInfo); err != nil {
+			return err
+		}
 		if syncMaxInFlightErr := js.syncConsumerMaxInFlight(subscription, *consumerInfo); syncMaxInFlightErr != nil {
 			return syncMaxInFlightErr
 		}
@@ -617,11 +965,17 @@ func (js *JetStream) getOrCreateConsumer(subscription *eventingv1alpha2.Subscrip
 		if errors.Is(err, nats.ErrConsumerNotFound) {
 			consumerInfo, err = js.jsCtx.AddConsumer(
 				js.Config.JSStreamName,
-				js.getConsumerConfig(jsSubKey, jsSubject, subscription.GetMaxInFlightMessages(&js.subsConfig)),
+				js.getConsumerConfig(jsSubKey, js.consumerFilterSubject(subscription, jsSubject),
+					subscription.GetMaxInFlightMessages(&js.subsConfig), subscription),
 			)
 			if err != nil {
 				return nil, pkgerrors.MakeError(ErrAddConsumer, err)
 			}
+			js.auditConsumer(audit.OperationCreate, consumerInfo.Name, createKeyPrefix(subscription),
+				audit.Diff("", nil, map[string]interface{}{
+					"filterSubject": consumerInfo.Config.FilterSubject,
+					"maxAckPending": consumerInfo.Config.MaxAckPending,
+				}))
 		} else {
 			return nil, pkgerrors.MakeError(ErrGetConsumer, err)
 		}
@@ -636,7 +990,7 @@ func (js *JetStream) createNATSSubscription(subscription *eventingv1alpha2.Subsc
 	jsSubKey := NewSubscriptionSubjectIdentifier(subscription, jsSubject)
 
 	jsSubscription, err := js.jsCtx.Subscribe(
-		jsSubject,
+		js.consumerFilterSubject(subscription, jsSubject),
 		asyncCallback,
 		js.getDefaultSubscriptionOptions(jsSubKey, subscription.GetMaxInFlightMessages(&js.subsConfig))...,
 	)
@@ -662,7 +1016,7 @@ func (js *JetStream) bindInvalidSubscriptions(subscription *eventingv1alpha2.Sub
 	jsSubKey := NewSubscriptionSubjectIdentifier(subscription, jsSubject)
 	// bind the existing consumer to a new subscription on JetStream
 	jsSubscription, err := js.jsCtx.Subscribe(
-		jsSubject,
+		js.consumerFilterSubject(subscription, jsSubject),
 		asyncCallback,
 		nats.Bind(js.Config.JSStreamName, jsSubKey.ConsumerName()),
 	)
@@ -674,6 +1028,48 @@ func (js *JetStream) bindInvalidSubscriptions(subscription *eventingv1alpha2.Sub
 	return nil
 }
 
+// syncConsumerOwner stamps the consumer with the owner metadata of the Subscription. A consumer created before the
+// metadata was introduced, or for a previous Subscription with the same name, is adopted by the Subscription.
+// It returns the updated consumer info.
+func (js *JetStream) syncConsumerOwner(subscription *eventingv1alpha2.Subscription,
+	consumerInfo *nats.ConsumerInfo) (*nats.ConsumerInfo, error) {
+	if isOwnedBy(consumerInfo.Config, subscription) {
+		return consumerInfo, nil
+	}
+
+	js.namedLogger().Infow("Adopting JetStream consumer", "consumer", consumerInfo.Name,
+		"previousOwner", consumerInfo.Config.Metadata, "namespace", subscription.Namespace, "name", subscription.Name)
+	consumerConfig := consumerInfo.Config
+	consumerConfig.Metadata = backendutils.GetOwnerMetadata(subscription)
+	updatedInfo, err := js.jsCtx.UpdateConsumer(js.Config.JSStreamName, &consumerConfig)
+	if err != nil {
+		return nil, pkgerrors.MakeError(ErrUpdateConsumer, err)
+	}
+	js.auditConsumer(audit.OperationUpdate, consumerInfo.Name, createKeyPrefix(subscription),
+		audit.Diff("metadata", consumerInfo.Config.Metadata, consumerConfig.Metadata))
+	return updatedInfo, nil
+}
+
+// syncConsumerFilterSubject checks that the filter subject of the consumer of the given subject is up-to-date
+// with the filters of the Subscription. It returns the updated consumer info.
+func (js *JetStream) syncConsumerFilterSubject(subscription *eventingv1alpha2.Subscription,
+	consumerInfo *nats.ConsumerInfo, jsSubject string) (*nats.ConsumerInfo, error) {
+	filterSubject := js.consumerFilterSubject(subscription, jsSubject)
+	if consumerInfo.Config.FilterSubject == filterSubject {
+		return consumerInfo, nil
+	}
+
+	consumerConfig := consumerInfo.Config
+	consumerConfig.FilterSubject = filterSubject
+	updatedInfo, err := js.jsCtx.UpdateConsumer(js.Config.JSStreamName, &consumerConfig)
+	if err != nil {
+		return nil, pkgerrors.MakeError(ErrUpdateConsumer, err)
+	}
+	js.auditConsumer(audit.OperationUpdate, consumerInfo.Name, createKeyPrefix(subscription),
+		audit.Diff("filterSubject", consumerInfo.Config.FilterSubject, filterSubject))
+	return updatedInfo, nil
+}
+
 // syncConsumerMaxInFlight checks that the latest Subscription's maxInFlight value
 // is propagated to the NATS consumer as MaxAckPending.
 func (js *JetStream) syncConsumerMaxInFlight(subscription *eventingv1alpha2.Subscription,
@@ -692,6 +1088,8 @@ func (js *JetStream) syncConsumerMaxInFlight(subscription *eventingv1alpha2.Subs
 	if _, updateErr := js.jsCtx.UpdateConsumer(js.Config.JSStreamName, &consumerConfig); updateErr != nil {
 		return pkgerrors.MakeError(ErrUpdateConsumer, updateErr)
 	}
+	js.auditConsumer(audit.OperationUpdate, consumerInfo.Name, createKeyPrefix(subscription),
+		audit.Diff("maxAckPending", consumerInfo.Config.MaxAckPending, maxInFlight))
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"testing"
 	"time"
 
@@ -17,6 +18,9 @@ import (
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/cloudevent/validation"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/deadletter"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 	evtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
@@ -120,6 +124,155 @@ func TestJetStreamSubAfterSync_SinkChange(t *testing.T) {
 	require.NoError(t, subscriber2.CheckEvent(evtesting.CloudEventData))
 }
 
+// TestJetStreamSubAfterSync_NarrowedConsumer tests the SyncSubscription method
+// when the consumer subject is narrowed by an exact type filter, then the consumer
+// should not be re-created on resync.
+func TestJetStreamSubAfterSync_NarrowedConsumer(t *testing.T) {
+	// given
+	testEnvironment := setupTestEnvironment(t)
+	jsBackend := testEnvironment.jsBackend
+	defer testEnvironment.natsServer.Shutdown()
+	defer testEnvironment.jsClient.natsConn.Close()
+	initErr := jsBackend.Initialize(nil)
+	require.NoError(t, initErr)
+
+	subscriber := evtesting.NewSubscriber()
+	defer subscriber.Shutdown()
+	require.True(t, subscriber.IsRunning())
+
+	// create a new Subscription with a wildcard type narrowed by an exact type filter
+	sub := evtesting.NewSubscription("sub", "foo",
+		evtesting.WithSourceAndType(evtesting.EventSource, "order.>"),
+		evtesting.WithSinkURL(subscriber.SinkURL),
+		evtesting.WithTypeMatchingExact(),
+		evtesting.WithMaxInFlight(DefaultMaxInFlights),
+		evtesting.WithFilters(eventingv1alpha2.SubscriptionFilter{
+			Exact: map[string]string{"type": evtesting.OrderCreatedV1Event},
+		}),
+	)
+	AddJSCleanEventTypesToStatus(sub, testEnvironment.cleaner)
+	require.NoError(t, jsBackend.SyncSubscription(sub))
+
+	jsSubject := jsBackend.GetJetStreamSubject(sub.Spec.Source, "order.>", sub.Spec.TypeMatching)
+	jsSubKey := NewSubscriptionSubjectIdentifier(sub, jsSubject)
+	consumer, err := jsBackend.jsCtx.ConsumerInfo(jsBackend.Config.JSStreamName, jsSubKey.ConsumerName())
+	require.NoError(t, err)
+	require.Equal(t,
+		jsBackend.GetJetStreamSubject(sub.Spec.Source, evtesting.OrderCreatedV1Event, sub.Spec.TypeMatching),
+		consumer.Config.FilterSubject)
+
+	// when
+	err = jsBackend.SyncSubscription(sub)
+
+	// then
+	require.NoError(t, err)
+	resyncedConsumer, err := jsBackend.jsCtx.ConsumerInfo(jsBackend.Config.JSStreamName, jsSubKey.ConsumerName())
+	require.NoError(t, err)
+	// the consumer is the same, if it was not deleted and created again
+	require.Equal(t, consumer.Created, resyncedConsumer.Created)
+}
+
+// TestJetStream_ReconnectWhileSyncing tests that the reconnects on changed credentials are serialized
+// with the operations of the reconciliations. Run it with -race to detect concurrent access to the connection.
+func TestJetStream_ReconnectWhileSyncing(t *testing.T) {
+	// given
+	testEnvironment := setupTestEnvironment(t)
+	jsBackend := testEnvironment.jsBackend
+	defer testEnvironment.natsServer.Shutdown()
+	defer testEnvironment.jsClient.natsConn.Close()
+	require.NoError(t, jsBackend.Initialize(nil))
+
+	subscriber := evtesting.NewSubscriber()
+	defer subscriber.Shutdown()
+	require.True(t, subscriber.IsRunning())
+
+	sub := evtesting.NewSubscription("sub", "foo",
+		evtesting.WithNotCleanEventSourceAndType(),
+		evtesting.WithSinkURL(subscriber.SinkURL),
+		evtesting.WithTypeMatchingStandard(),
+		evtesting.WithMaxInFlight(DefaultMaxInFlights),
+	)
+	AddJSCleanEventTypesToStatus(sub, testEnvironment.cleaner)
+	require.NoError(t, jsBackend.SyncSubscription(sub))
+
+	// when the connection is re-established while the subscription is synced
+	const reconnects = 5
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < reconnects; i++ {
+			jsBackend.reconnect()
+		}
+	}()
+	for synced := false; !synced; {
+		select {
+		case <-done:
+			synced = true
+		default:
+			// the errors of the closed connection are retried by the reconciliation
+			_ = jsBackend.SyncSubscription(sub)
+			_, _ = jsBackend.CountPendingMessages(sub)
+			require.NotNil(t, jsBackend.GetJetStreamContext())
+		}
+	}
+
+	// then the subscription is bound to the new connection
+	require.NoError(t, jsBackend.SyncSubscription(sub))
+	subject, err := testEnvironment.cleaner.CleanEventType(sub.Spec.Types[0])
+	require.NoError(t, err)
+	require.NoError(t,
+		SendCloudEventToJetStream(jsBackend,
+			jsBackend.GetJetStreamSubject(sub.Spec.Source, subject, sub.Spec.TypeMatching),
+			evtesting.CloudEventData,
+			types.ContentModeBinary),
+	)
+	require.NoError(t, subscriber.CheckEvent(evtesting.CloudEventData))
+}
+
+// TestJetStream_RequeuedForOtherSubscription tests that an event requeued from the dead-letter stream is only
+// dispatched to the Subscription it was requeued for.
+func TestJetStream_RequeuedForOtherSubscription(t *testing.T) {
+	// given
+	testEnvironment := setupTestEnvironment(t)
+	jsBackend := testEnvironment.jsBackend
+	defer testEnvironment.natsServer.Shutdown()
+	defer testEnvironment.jsClient.natsConn.Close()
+	require.NoError(t, jsBackend.Initialize(nil))
+
+	subscriber := evtesting.NewSubscriber()
+	defer subscriber.Shutdown()
+	require.True(t, subscriber.IsRunning())
+
+	sub := evtesting.NewSubscription("sub", "foo",
+		evtesting.WithNotCleanEventSourceAndType(),
+		evtesting.WithSinkURL(subscriber.SinkURL),
+		evtesting.WithTypeMatchingStandard(),
+		evtesting.WithMaxInFlight(DefaultMaxInFlights),
+	)
+	AddJSCleanEventTypesToStatus(sub, testEnvironment.cleaner)
+	require.NoError(t, jsBackend.SyncSubscription(sub))
+	cleanType, err := testEnvironment.cleaner.CleanEventType(sub.Spec.Types[0])
+	require.NoError(t, err)
+	subject := jsBackend.GetJetStreamSubject(sub.Spec.Source, cleanType, sub.Spec.TypeMatching)
+
+	// when the events are requeued for another subscription and for the subscription
+	for i, requeued := range []struct{ subscription, data string }{
+		{subscription: "foo/other", data: evtesting.CloudEventData},
+		{subscription: "foo/sub", data: evtesting.CloudEventData2},
+	} {
+		header := nats.Header{}
+		header.Set(deadletter.HeaderRequeuedFor, requeued.subscription)
+		data := fmt.Sprintf(`{"specversion":"1.0","id":"%d","type":%q,"source":%q,`+
+			`"datacontenttype":"application/json","data":%s}`, i, subject, sub.Spec.Source, requeued.data)
+		_, err = jsBackend.jsCtx.PublishMsg(&nats.Msg{Subject: subject, Header: header, Data: []byte(data)})
+		require.NoError(t, err)
+	}
+
+	// then only the event requeued for the subscription is dispatched
+	require.NoError(t, subscriber.CheckEventCount(evtesting.CloudEventData2, 1, 5*time.Second))
+	require.NoError(t, subscriber.CheckEventCount(evtesting.CloudEventData, 0, time.Second))
+}
+
 // TestMultipleJSSubscriptionsToSameEvent tests the behaviour of JS
 // when multiple subscriptions need to receive the same event.
 func TestMultipleJSSubscriptionsToSameEvent(t *testing.T) {
@@ -161,8 +314,10 @@ func TestMultipleJSSubscriptionsToSameEvent(t *testing.T) {
 			types.ContentModeBinary),
 	)
 	// Check for the 3 events that should be received by the subscriber
-	for i := 0; i < len(subs); i++ {
-		require.NoError(t, subscriber.CheckEvent(evtesting.CloudEventData))
+	events, err := subscriber.WaitForNEvents(len(subs), 10*time.Second)
+	require.NoError(t, err)
+	for _, event := range events {
+		require.Equal(t, evtesting.CloudEventData, event.Data)
 	}
 	// Delete all 3 subscription
 	for i := 0; i < len(subs); i++ {
@@ -178,9 +333,8 @@ func TestMultipleJSSubscriptionsToSameEvent(t *testing.T) {
 			types.ContentModeBinary),
 	)
 	// Check for the event that did not reach the subscriber
-	// Store should never return evtesting.CloudEventData2
-	// hence CheckEvent should fail to match evtesting.CloudEventData2
-	require.Error(t, subscriber.CheckEvent(evtesting.CloudEventData2))
+	require.NoError(t, subscriber.CheckEventCount(evtesting.CloudEventData2, 0, 5*time.Second))
+	require.Len(t, subscriber.ReceivedEvents(), len(subs))
 }
 
 // TestJSSubscriptionRedeliverWithFailedDispatch tests the redelivering
@@ -229,6 +383,15 @@ func TestJSSubscriptionRedeliverWithFailedDispatch(t *testing.T) {
 	// then
 	// it should have failed to dispatch
 	require.Error(t, subscriber.CheckEvent(evtesting.CloudEventData))
+	// and recorded the failure with the reason
+	require.Eventually(t, func() bool {
+		snapshot, snapshotErr := jsBackend.metricsCollector.Snapshot()
+		require.NoError(t, snapshotErr)
+		return snapshot.Sum("eventing_ec_nats_delivery_failures_total", map[string]string{
+			"subscription_name": sub.Name,
+			"reason":            metrics.FailureReasonConnectionRefused,
+		}) > 0
+	}, 10*time.Second, 100*time.Millisecond)
 
 	// when
 	// start a new subscriber
@@ -712,11 +875,304 @@ func getJetStreamClient(t *testing.T, serverURL string) *jetStreamClient {
 	}
 }
 
+// TestJetStreamCompatibility_NATSContainer tests the dispatching of an event with the nats-server release
+// selected by the NATS_IMAGE_TAG environment variable, running in a container.
+func TestJetStreamCompatibility_NATSContainer(t *testing.T) {
+	// given
+	testEnvironment := setupTestEnvironmentWithNATSContainer(t, evtesting.NatsImageTag())
+	defer func() { require.NoError(t, testEnvironment.natsContainer.Shutdown()) }()
+	defer testEnvironment.jsClient.natsConn.Close()
+	jsBackend := testEnvironment.jsBackend
+	require.NoError(t, jsBackend.Initialize(nil))
+
+	subscriber := evtesting.NewSubscriber()
+	defer subscriber.Shutdown()
+	require.True(t, subscriber.IsRunning())
+
+	sub := evtesting.NewSubscription("sub", "foo",
+		evtesting.WithSourceAndType(evtesting.EventSource, evtesting.OrderCreatedCleanEvent),
+		evtesting.WithSinkURL(subscriber.SinkURL),
+		evtesting.WithTypeMatchingExact(),
+		evtesting.WithMaxInFlight(DefaultMaxInFlights),
+	)
+	AddJSCleanEventTypesToStatus(sub, testEnvironment.cleaner)
+	require.NoError(t, jsBackend.SyncSubscription(sub))
+
+	// when
+	require.NoError(t,
+		SendCloudEventToJetStream(jsBackend,
+			jsBackend.GetJetStreamSubject(evtesting.EventSource,
+				evtesting.OrderCreatedCleanEvent,
+				eventingv1alpha2.TypeMatchingExact),
+			evtesting.CloudEventData,
+			types.ContentModeBinary),
+	)
+
+	// then
+	require.NoError(t, subscriber.CheckEvent(evtesting.CloudEventData))
+	require.NoError(t, jsBackend.DeleteSubscription(sub))
+}
+
 // setupTestEnvironment is a TestEnvironment constructor.
+// TestJetStream_CoreDeliveryMode tests that a subscription in the core delivery mode receives the events published
+// to the stream without a JetStream consumer, and that switching the delivery mode replaces the subscriptions.
+func TestJetStream_CoreDeliveryMode(t *testing.T) {
+	// given
+	testEnvironment := setupTestEnvironment(t)
+	jsBackend := testEnvironment.jsBackend
+	defer testEnvironment.natsServer.Shutdown()
+	defer testEnvironment.jsClient.natsConn.Close()
+	require.NoError(t, jsBackend.Initialize(nil))
+
+	subscriber := evtesting.NewSubscriber()
+	defer subscriber.Shutdown()
+	require.True(t, subscriber.IsRunning())
+
+	sub := evtesting.NewSubscription("sub", "foo",
+		evtesting.WithSourceAndType(evtesting.EventSource, evtesting.OrderCreatedCleanEvent),
+		evtesting.WithSinkURL(subscriber.SinkURL),
+		evtesting.WithTypeMatchingExact(),
+		evtesting.WithMaxInFlight(DefaultMaxInFlights),
+		evtesting.WithConfigValue(eventingv1alpha2.DeliveryMode, eventingv1alpha2.DeliveryModeCore),
+	)
+	AddJSCleanEventTypesToStatus(sub, testEnvironment.cleaner)
+	subject := jsBackend.GetJetStreamSubject(evtesting.EventSource, evtesting.OrderCreatedCleanEvent,
+		eventingv1alpha2.TypeMatchingExact)
+	consumerName := NewSubscriptionSubjectIdentifier(sub, subject).ConsumerName()
+
+	// when
+	require.NoError(t, jsBackend.SyncSubscription(sub))
+
+	// then
+	require.Len(t, jsBackend.coreSubscriptions, 1)
+	require.Empty(t, jsBackend.subscriptions)
+	_, err := jsBackend.jsCtx.ConsumerInfo(jsBackend.Config.JSStreamName, consumerName)
+	require.ErrorIs(t, err, nats.ErrConsumerNotFound)
+	require.NoError(t, SendCloudEventToJetStream(jsBackend, subject, evtesting.CloudEventData,
+		types.ContentModeBinary))
+	require.NoError(t, subscriber.CheckEvent(evtesting.CloudEventData))
+
+	// when
+	sub.Spec.Config[eventingv1alpha2.DeliveryMode] = eventingv1alpha2.DeliveryModeJetStream
+	require.NoError(t, jsBackend.SyncSubscription(sub))
+
+	// then
+	require.Empty(t, jsBackend.coreSubscriptions)
+	require.Len(t, jsBackend.subscriptions, 1)
+	_, err = jsBackend.jsCtx.ConsumerInfo(jsBackend.Config.JSStreamName, consumerName)
+	require.NoError(t, err)
+
+	// when
+	sub.Spec.Config[eventingv1alpha2.DeliveryMode] = eventingv1alpha2.DeliveryModeCore
+	require.NoError(t, jsBackend.SyncSubscription(sub))
+
+	// then
+	require.Len(t, jsBackend.coreSubscriptions, 1)
+	require.Empty(t, jsBackend.subscriptions)
+	_, err = jsBackend.jsCtx.ConsumerInfo(jsBackend.Config.JSStreamName, consumerName)
+	require.ErrorIs(t, err, nats.ErrConsumerNotFound)
+
+	// when
+	require.NoError(t, jsBackend.DeleteSubscription(sub))
+
+	// then
+	require.Empty(t, jsBackend.coreSubscriptions)
+}
+
+// TestJetStream_DeadLetter tests that a failed event is moved to the dead-letter stream after its last delivery,
+// and that it can be requeued to its original subject.
+func TestJetStream_DeadLetter(t *testing.T) {
+	// given
+	testEnvironment := setupTestEnvironment(t)
+	jsBackend := testEnvironment.jsBackend
+	defer testEnvironment.natsServer.Shutdown()
+	defer testEnvironment.jsClient.natsConn.Close()
+	jsBackend.Config.JSDeadLetterStreamName = jsBackend.Config.JSStreamName + "_deadletter"
+	jsBackend.Config.JSDeadLetterSubjectPrefix = "deadletter"
+	require.NoError(t, jsBackend.Initialize(nil))
+	deadLetterStream := jsBackend.Config.JSDeadLetterStreamName
+	defer func() { _ = jsBackend.jsCtx.DeleteStream(deadLetterStream) }()
+
+	subject := jsBackend.GetJetStreamSubject(evtesting.EventSource, evtesting.OrderCreatedCleanEvent,
+		eventingv1alpha2.TypeMatchingExact)
+	_, err := jsBackend.jsCtx.AddConsumer(jsBackend.Config.JSStreamName, &nats.ConsumerConfig{
+		Durable:       "failing",
+		FilterSubject: subject,
+		AckPolicy:     nats.AckExplicitPolicy,
+	})
+	require.NoError(t, err)
+	require.NoError(t, SendCloudEventToJetStream(jsBackend, subject, evtesting.CloudEventData,
+		types.ContentModeStructured))
+	pull, err := jsBackend.jsCtx.PullSubscribe(subject, "failing", nats.Bind(jsBackend.Config.JSStreamName, "failing"))
+	require.NoError(t, err)
+	msgs, err := pull.Fetch(1)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	// when the last delivery of the event failed
+	jsBackend.nakOrDeadLetter(msgs[0], "foo", "sub", "http://sink", http.StatusBadGateway, jsConsumerMaxRedeliver,
+		jsBackend.namedLogger())
+
+	// then the event is moved to the dead-letter stream
+	events, err := deadletter.List(jsBackend.jsCtx, deadLetterStream, 1, deadletter.DefaultLimit)
+	require.NoError(t, err)
+	require.Len(t, events.Events, 1)
+	event := events.Events[0]
+	require.Equal(t, subject, event.Subject)
+	require.Equal(t, "foo/sub", event.Subscription)
+	require.Equal(t, "http://sink", event.Sink)
+	require.Equal(t, http.StatusBadGateway, event.Status)
+	require.Equal(t, jsConsumerMaxRedeliver, event.Deliveries)
+	require.NotEmpty(t, event.ID)
+	consumer, err := jsBackend.jsCtx.ConsumerInfo(jsBackend.Config.JSStreamName, "failing")
+	require.NoError(t, err)
+	require.Zero(t, consumer.NumAckPending)
+
+	// when the event is requeued
+	result, err := deadletter.Requeue(jsBackend.jsCtx, deadLetterStream, []uint64{event.Sequence})
+
+	// then it is published to its original subject for the failed subscription without the dead-letter headers
+	require.NoError(t, err)
+	require.Equal(t, []deadletter.Result{{Sequence: event.Sequence}}, result.Results)
+	msgs, err = pull.Fetch(1)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.Equal(t, subject, msgs[0].Subject)
+	require.Empty(t, msgs[0].Header.Get(deadletter.HeaderSubject))
+	require.Equal(t, "foo/sub", msgs[0].Header.Get(deadletter.HeaderRequeuedFor))
+	info, err := jsBackend.jsCtx.StreamInfo(deadLetterStream)
+	require.NoError(t, err)
+	require.Zero(t, info.State.Msgs)
+}
+
+// TestJetStream_DeadLetterSameEvent tests that the same event is moved to the dead-letter stream once per
+// Subscription whose last delivery failed, although the publisher set a message ID.
+func TestJetStream_DeadLetterSameEvent(t *testing.T) {
+	// given
+	testEnvironment := setupTestEnvironment(t)
+	jsBackend := testEnvironment.jsBackend
+	defer testEnvironment.natsServer.Shutdown()
+	defer testEnvironment.jsClient.natsConn.Close()
+	jsBackend.Config.JSDeadLetterStreamName = jsBackend.Config.JSStreamName + "_deadletter"
+	jsBackend.Config.JSDeadLetterSubjectPrefix = "deadletter"
+	require.NoError(t, jsBackend.Initialize(nil))
+	deadLetterStream := jsBackend.Config.JSDeadLetterStreamName
+	defer func() { _ = jsBackend.jsCtx.DeleteStream(deadLetterStream) }()
+
+	subject := jsBackend.GetJetStreamSubject(evtesting.EventSource, evtesting.OrderCreatedCleanEvent,
+		eventingv1alpha2.TypeMatchingExact)
+	names := []string{"first", "second"}
+	for _, name := range names {
+		_, err := jsBackend.jsCtx.AddConsumer(jsBackend.Config.JSStreamName, &nats.ConsumerConfig{
+			Durable:       name,
+			FilterSubject: subject,
+			AckPolicy:     nats.AckExplicitPolicy,
+		})
+		require.NoError(t, err)
+	}
+	header := nats.Header{}
+	header.Set(nats.MsgIdHdr, subject+"/id")
+	_, err := jsBackend.jsCtx.PublishMsg(&nats.Msg{Subject: subject, Header: header,
+		Data: []byte(`{"specversion":"1.0","id":"id","type":"order.created.v1","source":"shop"}`)})
+	require.NoError(t, err)
+
+	// when the last delivery of the event failed for two Subscriptions
+	for _, name := range names {
+		pull, err := jsBackend.jsCtx.PullSubscribe(subject, name, nats.Bind(jsBackend.Config.JSStreamName, name))
+		require.NoError(t, err)
+		msgs, err := pull.Fetch(1)
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+		jsBackend.nakOrDeadLetter(msgs[0], "foo", name, "http://sink", http.StatusBadGateway,
+			jsConsumerMaxRedeliver, jsBackend.namedLogger())
+	}
+
+	// then the event is moved to the dead-letter stream for both of them
+	events, err := deadletter.List(jsBackend.jsCtx, deadLetterStream, 1, deadletter.DefaultLimit)
+	require.NoError(t, err)
+	require.Len(t, events.Events, 2)
+	require.Equal(t, "foo/first", events.Events[0].Subscription)
+	require.Equal(t, "foo/second", events.Events[1].Subscription)
+}
+
+func TestJetStream_ValidateEvent(t *testing.T) {
+	// given
+	testEnvironment := setupTestEnvironment(t)
+	jsBackend := testEnvironment.jsBackend
+	defer testEnvironment.natsServer.Shutdown()
+	defer testEnvironment.jsClient.natsConn.Close()
+	jsBackend.Config.JSDeadLetterStreamName = jsBackend.Config.JSStreamName + "_deadletter"
+	jsBackend.Config.JSDeadLetterSubjectPrefix = "deadletter"
+	jsBackend.Config.CEValidationPolicy = validation.PolicyLenient
+	require.NoError(t, jsBackend.Initialize(nil))
+	deadLetterStream := jsBackend.Config.JSDeadLetterStreamName
+	defer func() { _ = jsBackend.jsCtx.DeleteStream(deadLetterStream) }()
+
+	subject := jsBackend.GetJetStreamSubject(evtesting.EventSource, evtesting.OrderCreatedCleanEvent,
+		eventingv1alpha2.TypeMatchingExact)
+	_, err := jsBackend.jsCtx.AddConsumer(jsBackend.Config.JSStreamName, &nats.ConsumerConfig{
+		Durable:       "invalid",
+		FilterSubject: subject,
+		AckPolicy:     nats.AckExplicitPolicy,
+	})
+	require.NoError(t, err)
+	require.NoError(t, SendCloudEventToJetStream(jsBackend, subject, evtesting.CloudEventData,
+		types.ContentModeStructured))
+	pull, err := jsBackend.jsCtx.PullSubscribe(subject, "invalid", nats.Bind(jsBackend.Config.JSStreamName, "invalid"))
+	require.NoError(t, err)
+	msgs, err := pull.Fetch(1)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	ce, err := backendutils.ConvertMsgToCE(msgs[0])
+	require.NoError(t, err)
+	ce.SetExtension("averyverylongextension", "value")
+
+	// when the event violating a recommended rule is validated with the lenient policy
+	dispatched := jsBackend.validateEvent(msgs[0], ce, "foo", "sub", "http://sink", true, jsBackend.namedLogger())
+
+	// then it is dispatched
+	require.True(t, dispatched)
+
+	// when the event is validated with the strict policy
+	jsBackend.Config.CEValidationPolicy = validation.PolicyStrict
+	require.NoError(t, jsBackend.initValidator())
+	dispatched = jsBackend.validateEvent(msgs[0], ce, "foo", "sub", "http://sink", true, jsBackend.namedLogger())
+
+	// then it is moved to the dead-letter stream without a delivery status
+	require.False(t, dispatched)
+	events, err := deadletter.List(jsBackend.jsCtx, deadLetterStream, 1, deadletter.DefaultLimit)
+	require.NoError(t, err)
+	require.Len(t, events.Events, 1)
+	require.Equal(t, subject, events.Events[0].Subject)
+	require.Zero(t, events.Events[0].Status)
+	consumer, err := jsBackend.jsCtx.ConsumerInfo(jsBackend.Config.JSStreamName, "invalid")
+	require.NoError(t, err)
+	require.Zero(t, consumer.NumAckPending)
+}
+
 func setupTestEnvironment(t *testing.T) *TestEnvironment {
 	natsServer, natsPort, err := StartNATSServer(evtesting.WithJetStreamEnabled())
 	require.NoError(t, err)
-	natsConfig := defaultNATSConfig(natsServer.ClientURL(), natsPort)
+	testEnvironment := newTestEnvironment(t, natsServer.ClientURL(), natsPort)
+	testEnvironment.natsServer = natsServer
+	return testEnvironment
+}
+
+// setupTestEnvironmentWithNATSContainer is a TestEnvironment constructor running the nats-server container
+// of the given tag instead of the embedded server. It skips the test if Docker is not available.
+func setupTestEnvironmentWithNATSContainer(t *testing.T, tag string) *TestEnvironment {
+	natsContainer, err := evtesting.RunNatsContainer(tag)
+	if errors.Is(err, evtesting.ErrDockerNotAvailable) {
+		t.Skipf("Skipping the test against nats-server %s: %v", tag, err)
+	}
+	require.NoError(t, err)
+	testEnvironment := newTestEnvironment(t, natsContainer.ClientURL(), natsContainer.Port())
+	testEnvironment.natsContainer = natsContainer
+	return testEnvironment
+}
+
+func newTestEnvironment(t *testing.T, natsURL string, natsPort int) *TestEnvironment {
+	natsConfig := defaultNATSConfig(natsURL, natsPort)
 	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
 	require.NoError(t, err)
 
@@ -731,7 +1187,6 @@ func setupTestEnvironment(t *testing.T) *TestEnvironment {
 	return &TestEnvironment{
 		jsBackend:  jsBackend,
 		logger:     defaultLogger,
-		natsServer: natsServer,
 		jsClient:   jsClient,
 		natsConfig: natsConfig,
 		cleaner:    jsCleaner,
@@ -3,6 +3,12 @@
 package jetstream
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
@@ -10,6 +16,7 @@ import (
 	cev2 "github.com/cloudevents/sdk-go/v2/event"
 	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/audit"
 	jetstreammocks "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream/mocks"
 	"github.com/nats-io/nats.go"
 
@@ -18,6 +25,7 @@ import (
 	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
 	subtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -208,7 +216,11 @@ func Test_SyncConsumersAndSubscriptions_ForBindInvalidSubscriptions(t *testing.T
 				jsSubject := jsBackend.GetJetStreamSubject(sub.Spec.Source, eventType.CleanType, sub.Spec.TypeMatching)
 				// mock the expected calls
 				jsCtx.On("ConsumerInfo", jsBackend.Config.JSStreamName, jsSubKey.ConsumerName()).
-					Return(&nats.ConsumerInfo{Config: nats.ConsumerConfig{MaxAckPending: DefaultMaxInFlights}}, nil)
+					Return(&nats.ConsumerInfo{Config: nats.ConsumerConfig{
+						MaxAckPending: DefaultMaxInFlights,
+						Metadata:      backendutils.GetOwnerMetadata(sub),
+						FilterSubject: jsSubject,
+					}}, nil)
 				jsCtx.On("Subscribe", jsSubject, mock.AnythingOfType("nats.MsgHandler"), mock.AnythingOfType("nats.subOptFn")).
 					Return(&nats.Subscription{}, nil)
 			},
@@ -224,9 +236,15 @@ func Test_SyncConsumersAndSubscriptions_ForBindInvalidSubscriptions(t *testing.T
 				jsBackend.subscriptions = map[SubscriptionSubjectIdentifier]Subscriber{
 					jsSubKey: validSubscriber,
 				}
+				eventType := sub.Status.Types[0]
+				jsSubject := jsBackend.GetJetStreamSubject(sub.Spec.Source, eventType.CleanType, sub.Spec.TypeMatching)
 				// mock the expected calls
 				jsCtx.On("ConsumerInfo", jsBackend.Config.JSStreamName, jsSubKey.ConsumerName()).
-					Return(&nats.ConsumerInfo{Config: nats.ConsumerConfig{MaxAckPending: DefaultMaxInFlights}}, nil)
+					Return(&nats.ConsumerInfo{Config: nats.ConsumerConfig{
+						MaxAckPending: DefaultMaxInFlights,
+						Metadata:      backendutils.GetOwnerMetadata(sub),
+						FilterSubject: jsSubject,
+					}}, nil)
 			},
 		},
 	}
@@ -260,6 +278,66 @@ func Test_SyncConsumersAndSubscriptions_ForBindInvalidSubscriptions(t *testing.T
 	}
 }
 
+// Test_SyncConsumerOwner tests that the consumers are stamped with the owner metadata of the Subscription.
+func Test_SyncConsumerOwner(t *testing.T) {
+	owner := subtesting.NewSubscription("test", "test")
+	owner.UID = "new-uid"
+
+	testCases := []struct {
+		name             string
+		givenMetadata    map[string]string
+		wantConsumerSync bool
+	}{
+		{
+			name:             "consumer without owner metadata should be adopted",
+			givenMetadata:    nil,
+			wantConsumerSync: true,
+		},
+		{
+			name: "consumer of a previous subscription with the same name should be adopted",
+			givenMetadata: map[string]string{
+				ConsumerMetadataOwnerNamespace: "test",
+				ConsumerMetadataOwnerName:      "test",
+				ConsumerMetadataOwnerUID:       "old-uid",
+			},
+			wantConsumerSync: true,
+		},
+		{
+			name:             "consumer owned by the subscription should not be updated",
+			givenMetadata:    backendutils.GetOwnerMetadata(owner),
+			wantConsumerSync: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+			require.NoError(t, err)
+			jsCtxMock := &jetstreammocks.JetStreamContext{}
+			js := &JetStream{jsCtx: jsCtxMock, logger: defaultLogger}
+			consumer := &nats.ConsumerInfo{Name: "name", Config: nats.ConsumerConfig{
+				MaxAckPending: 10,
+				Metadata:      tc.givenMetadata,
+			}}
+			wantConfig := &nats.ConsumerConfig{MaxAckPending: 10, Metadata: backendutils.GetOwnerMetadata(owner)}
+			if tc.wantConsumerSync {
+				jsCtxMock.On("UpdateConsumer", js.Config.JSStreamName, wantConfig).
+					Return(&nats.ConsumerInfo{Name: "name", Config: *wantConfig}, nil)
+			}
+
+			// when
+			gotConsumer, err := js.syncConsumerOwner(owner, consumer)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, backendutils.GetOwnerMetadata(owner), gotConsumer.Config.Metadata)
+			jsCtxMock.AssertExpectations(t)
+		})
+	}
+}
+
 // Test_SyncConsumersAndSubscriptions_ForSyncConsumerMaxInFlight tests
 // the behaviour of the syncConsumerMaxInFlight function.
 func Test_SyncConsumersAndSubscriptions_ForSyncConsumerMaxInFlight(t *testing.T) {
@@ -272,6 +350,7 @@ func Test_SyncConsumersAndSubscriptions_ForSyncConsumerMaxInFlight(t *testing.T)
 			consumerConfigToUpdate *nats.ConsumerConfig,
 		)
 		wantConfigToUpdate *nats.ConsumerConfig
+		wantAuditChanges   []string
 	}{
 		{
 			name:                       "up-to-date consumer shouldn't be updated",
@@ -297,6 +376,7 @@ func Test_SyncConsumersAndSubscriptions_ForSyncConsumerMaxInFlight(t *testing.T)
 				}, nil)
 			},
 			wantConfigToUpdate: &nats.ConsumerConfig{MaxAckPending: 10},
+			wantAuditChanges:   []string{"maxAckPending: 20 -> 10"},
 		},
 	}
 
@@ -305,8 +385,12 @@ func Test_SyncConsumersAndSubscriptions_ForSyncConsumerMaxInFlight(t *testing.T)
 		t.Run(tc.name, func(t *testing.T) {
 			// given
 			jsCtxMock := &jetstreammocks.JetStreamContext{}
+			auditOutput := filepath.Join(t.TempDir(), "audit.log")
+			auditor, err := audit.New(auditOutput)
+			require.NoError(t, err)
 			js := &JetStream{
-				jsCtx: jsCtxMock,
+				jsCtx:   jsCtxMock,
+				auditor: auditor,
 			}
 			sub := subtesting.NewSubscription("test", "test",
 				subtesting.WithMaxInFlight(tc.givenSubMaxInFlight),
@@ -320,15 +404,110 @@ func Test_SyncConsumersAndSubscriptions_ForSyncConsumerMaxInFlight(t *testing.T)
 			tc.givenjetstreammocks(js, jsCtxMock, tc.wantConfigToUpdate)
 
 			// when
-			err := js.syncConsumerMaxInFlight(sub, consumer)
+			err = js.syncConsumerMaxInFlight(sub, consumer)
 
 			// then
 			assert.NoError(t, err)
 			jsCtxMock.AssertExpectations(t)
+
+			// and the update is audited
+			require.NoError(t, auditor.Sync())
+			output, err := os.ReadFile(auditOutput)
+			require.NoError(t, err)
+			if tc.wantAuditChanges == nil {
+				require.Empty(t, output)
+				return
+			}
+			record := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(output, &record))
+			require.Equal(t, audit.OperationUpdate, record["operation"])
+			require.Equal(t, audit.KindJetStreamConsumer, record["kind"])
+			require.Equal(t, "name", record["name"])
+			require.Equal(t, "test/test", record["subscription"])
+			require.Equal(t, []interface{}{tc.wantAuditChanges[0]}, record["changes"])
 		})
 	}
 }
 
+func Test_consumerFilterSubject(t *testing.T) {
+	typeFilter := func(eventType string) v1alpha2.SubscriptionFilter {
+		return v1alpha2.SubscriptionFilter{Exact: map[string]string{"type": eventType}}
+	}
+	testCases := []struct {
+		name              string
+		givenSubscription *v1alpha2.Subscription
+		givenSubject      string
+		wantSubject       string
+	}{
+		{
+			name: "exact type filter matched by the wildcard subject narrows it",
+			givenSubscription: subtesting.NewSubscription("test", "test", subtesting.WithTypeMatchingExact(),
+				subtesting.WithFilters(v1alpha2.SubscriptionFilter{Prefix: map[string]string{"source": "shop"}},
+					typeFilter("order.created.v1"))),
+			givenSubject: "kyma.order.>",
+			wantSubject:  "kyma.order.created.v1",
+		},
+		{
+			name: "exact type filter not matched by the subject keeps it",
+			givenSubscription: subtesting.NewSubscription("test", "test", subtesting.WithTypeMatchingExact(),
+				subtesting.WithFilters(typeFilter("invoice.created.v1"))),
+			givenSubject: "kyma.order.>",
+			wantSubject:  "kyma.order.>",
+		},
+		{
+			name: "exact type filter with a NATS wildcard keeps the subject",
+			givenSubscription: subtesting.NewSubscription("test", "test", subtesting.WithTypeMatchingExact(),
+				subtesting.WithFilters(typeFilter("order.*.v1"))),
+			givenSubject: "kyma.order.>",
+			wantSubject:  "kyma.order.>",
+		},
+		{
+			name: "standard type matching keeps the subject",
+			givenSubscription: subtesting.NewSubscription("test", "test", subtesting.WithTypeMatchingStandard(),
+				subtesting.WithFilters(typeFilter("order.created.v1"))),
+			givenSubject: "kyma.shop.order.created.v1",
+			wantSubject:  "kyma.shop.order.created.v1",
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			js := &JetStream{Config: env.NATSConfig{JSSubjectPrefix: "kyma"}, cleaner: &cleaner.JetStreamCleaner{}}
+			assert.Equal(t, tc.wantSubject, js.consumerFilterSubject(tc.givenSubscription, tc.givenSubject))
+		})
+	}
+}
+
+func Test_SyncConsumerFilterSubject(t *testing.T) {
+	// given
+	jsCtxMock := &jetstreammocks.JetStreamContext{}
+	js := &JetStream{
+		Config:  env.NATSConfig{JSSubjectPrefix: "kyma", JSStreamName: "kyma"},
+		jsCtx:   jsCtxMock,
+		cleaner: &cleaner.JetStreamCleaner{},
+	}
+	sub := subtesting.NewSubscription("test", "test", subtesting.WithTypeMatchingExact(),
+		subtesting.WithFilters(v1alpha2.SubscriptionFilter{Exact: map[string]string{"type": "order.created.v1"}}))
+	consumer := &nats.ConsumerInfo{Name: "name", Config: nats.ConsumerConfig{FilterSubject: "kyma.order.>"}}
+	wantConfig := &nats.ConsumerConfig{FilterSubject: "kyma.order.created.v1"}
+	jsCtxMock.On("UpdateConsumer", "kyma", wantConfig).Return(&nats.ConsumerInfo{Config: *wantConfig}, nil)
+
+	// when
+	updated, err := js.syncConsumerFilterSubject(sub, consumer, "kyma.order.>")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "kyma.order.created.v1", updated.Config.FilterSubject)
+	jsCtxMock.AssertExpectations(t)
+
+	// when the consumer is up-to-date
+	_, err = js.syncConsumerFilterSubject(sub, updated, "kyma.order.>")
+
+	// then it is not updated again
+	require.NoError(t, err)
+	jsCtxMock.AssertNumberOfCalls(t, "UpdateConsumer", 1)
+}
+
 // Test_SyncConsumersAndSubscriptions_ForErrors test the syncConsumerAndSubscription for right error handling.
 func Test_SyncConsumersAndSubscriptions_ForErrors(t *testing.T) {
 	// pre-requisites
@@ -341,6 +520,7 @@ func Test_SyncConsumersAndSubscriptions_ForErrors(t *testing.T) {
 	)
 	jsSubKey := NewSubscriptionSubjectIdentifier(subWithOneType, jsSubject)
 	invalidSubscriber := &subscriberStub{isValid: false}
+	ownerMetadata := backendutils.GetOwnerMetadata(subWithOneType)
 
 	testCases := []struct {
 		name             string
@@ -354,7 +534,11 @@ func Test_SyncConsumersAndSubscriptions_ForErrors(t *testing.T) {
 				consumerInfoError: nats.ErrConsumerNotFound,
 				consumerInfo:      nil,
 
-				addConsumer: &nats.ConsumerInfo{Config: nats.ConsumerConfig{MaxAckPending: DefaultMaxInFlights}},
+				addConsumer: &nats.ConsumerInfo{Config: nats.ConsumerConfig{
+					MaxAckPending: DefaultMaxInFlights,
+					Metadata:      ownerMetadata,
+					FilterSubject: jsSubject,
+				}},
 
 				subscribe: &nats.Subscription{},
 			},
@@ -385,7 +569,10 @@ func Test_SyncConsumersAndSubscriptions_ForErrors(t *testing.T) {
 				jsSubKey: invalidSubscriber,
 			}},
 			jetStreamContext: &jetStreamContextStub{
-				consumerInfo:      &nats.ConsumerInfo{},
+				consumerInfo: &nats.ConsumerInfo{Config: nats.ConsumerConfig{
+					Metadata:      ownerMetadata,
+					FilterSubject: jsSubject,
+				}},
 				consumerInfoError: nil,
 
 				subscribeError: ErrFailedSubscribe,
@@ -395,7 +582,10 @@ func Test_SyncConsumersAndSubscriptions_ForErrors(t *testing.T) {
 		{
 			name: "Subscribe call on createNATSSubscription error should be propagated",
 			jetStreamContext: &jetStreamContextStub{
-				consumerInfo:      &nats.ConsumerInfo{},
+				consumerInfo: &nats.ConsumerInfo{Config: nats.ConsumerConfig{
+					Metadata:      ownerMetadata,
+					FilterSubject: jsSubject,
+				}},
 				consumerInfoError: nil,
 
 				subscribe:      nil,
@@ -406,7 +596,10 @@ func Test_SyncConsumersAndSubscriptions_ForErrors(t *testing.T) {
 		{
 			name: "UpdateConsumer call error should be propagated",
 			jetStreamContext: &jetStreamContextStub{
-				consumerInfo:      &nats.ConsumerInfo{},
+				consumerInfo: &nats.ConsumerInfo{Config: nats.ConsumerConfig{
+					Metadata:      ownerMetadata,
+					FilterSubject: jsSubject,
+				}},
 				consumerInfoError: nil,
 
 				subscribe:      &nats.Subscription{},
@@ -444,14 +637,14 @@ func Test_SyncConsumersAndSubscriptions_ForErrors(t *testing.T) {
 // then the consumer reports, that it is bound to a NATS subscription even though it is not.
 func Test_SyncConsumersAndSubscriptions_ForBoundConsumerWithoutSubscription(t *testing.T) {
 	// given
-	js := &JetStream{
-		jsCtx: &jetStreamContextStub{
-			consumerInfoError: nil,
-			consumerInfo:      &nats.ConsumerInfo{PushBound: true},
-		},
-		cleaner: &cleaner.JetStreamCleaner{},
-	}
 	subWithType := NewSubscriptionWithOneType()
+	js := &JetStream{cleaner: &cleaner.JetStreamCleaner{}}
+	jsSubject := js.GetJetStreamSubject(subWithType.Spec.Source, subWithType.Status.Types[0].CleanType,
+		subWithType.Spec.TypeMatching)
+	js.jsCtx = &jetStreamContextStub{
+		consumerInfoError: nil,
+		consumerInfo:      &nats.ConsumerInfo{PushBound: true, Config: nats.ConsumerConfig{FilterSubject: jsSubject}},
+	}
 	callback := func(m *nats.Msg) {}
 
 	// when
@@ -554,11 +747,51 @@ func Test_DeleteSubscriptionFromJetStream(t *testing.T) {
 	}
 }
 
+func Test_DeleteAllSubscriptionsOnly(t *testing.T) {
+	// given
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	assert.NoError(t, err)
+	jsBackend := &JetStream{
+		logger:  defaultLogger,
+		cleaner: &cleaner.JetStreamCleaner{},
+		// the consumers must not be deleted
+		jsCtx: &jetStreamContextStub{deleteConsumerErr: nats.ErrJetStreamNotEnabled},
+	}
+	subWithOneType := NewSubscriptionWithOneType()
+	jsSubject := jsBackend.GetJetStreamSubject(subWithOneType.Spec.Source,
+		subWithOneType.Status.Types[0].CleanType,
+		subWithOneType.Spec.TypeMatching,
+	)
+	jsSubKey := NewSubscriptionSubjectIdentifier(subWithOneType, jsSubject)
+	jsBackend.subscriptions = map[SubscriptionSubjectIdentifier]Subscriber{
+		jsSubKey: &subscriberStub{isValid: true},
+	}
+
+	// when
+	err = jsBackend.DeleteAllSubscriptionsOnly()
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, jsBackend.subscriptions)
+
+	// when unsubscribing fails
+	jsBackend.subscriptions = map[SubscriptionSubjectIdentifier]Subscriber{
+		jsSubKey: &subscriberStub{isValid: true, unsubscribeError: nats.ErrConnectionClosed},
+	}
+	err = jsBackend.DeleteAllSubscriptionsOnly()
+
+	// then
+	assert.ErrorIs(t, err, ErrFailedUnsubscribe)
+}
+
 // Test_DeleteInvalidConsumers tests the behaviour of the DeleteInvalidConsumers function.
 func Test_DeleteInvalidConsumers(t *testing.T) {
 	// pre-requisites
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
 	jsBackend := &JetStream{
 		cleaner: &cleaner.JetStreamCleaner{},
+		logger:  defaultLogger,
 	}
 
 	subs := NewSubscriptionsWithMultipleTypes()
@@ -572,6 +805,11 @@ func Test_DeleteInvalidConsumers(t *testing.T) {
 	// add a dangling consumer which should be deleted
 	givenConsumersWithDangling := givenConsumers
 	givenConsumersWithDangling = append(givenConsumersWithDangling, danglingConsumer)
+	bridgeConsumer := &nats.ConsumerInfo{
+		Name:      "bridge-consumer",
+		Config:    nats.ConsumerConfig{Metadata: map[string]string{ConsumerMetadataBridgeCluster: "eu-1"}},
+		PushBound: false,
+	}
 
 	testCases := []struct {
 		name               string
@@ -607,6 +845,15 @@ func Test_DeleteInvalidConsumers(t *testing.T) {
 			},
 			wantError: ErrDeleteConsumer,
 		},
+		{
+			name:               "a consumer of the bridge should not be deleted",
+			givenSubscriptions: []v1alpha2.Subscription{},
+			jetStreamContext: &jetStreamContextStub{
+				consumers: []*nats.ConsumerInfo{bridgeConsumer},
+			},
+			wantConsumers: []*nats.ConsumerInfo{bridgeConsumer},
+			wantError:     nil,
+		},
 		{
 			name:               "all consumers must be deleted if there is no subscription resource",
 			givenSubscriptions: []v1alpha2.Subscription{},
@@ -871,3 +1118,128 @@ func NewConsumers(subs []v1alpha2.Subscription, jsBackend *JetStream) []*nats.Co
 		},
 	}
 }
+
+func Test_CheckMaxInFlightLimit(t *testing.T) {
+	testCases := []struct {
+		name                string
+		givenStreamLimit    int
+		givenAccountLimit   int
+		givenSubMaxInFlight int
+		wantError           bool
+	}{
+		{
+			name:                "no limits should accept any maxInFlight",
+			givenAccountLimit:   -1,
+			givenSubMaxInFlight: 10000,
+			wantError:           false,
+		},
+		{
+			name:                "maxInFlight within the stream limit should be accepted",
+			givenStreamLimit:    100,
+			givenAccountLimit:   -1,
+			givenSubMaxInFlight: 100,
+			wantError:           false,
+		},
+		{
+			name:                "maxInFlight above the stream limit should return error",
+			givenStreamLimit:    100,
+			givenAccountLimit:   -1,
+			givenSubMaxInFlight: 101,
+			wantError:           true,
+		},
+		{
+			name:                "maxInFlight above the lower account limit should return error",
+			givenStreamLimit:    100,
+			givenAccountLimit:   50,
+			givenSubMaxInFlight: 51,
+			wantError:           true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+			require.NoError(t, err)
+			jsCtxMock := &jetstreammocks.JetStreamContext{}
+			jsCtxMock.On("AccountInfo").Return(&nats.AccountInfo{
+				Tier: nats.Tier{Limits: nats.AccountLimits{MaxAckPending: tc.givenAccountLimit}},
+			}, nil)
+			js := &JetStream{jsCtx: jsCtxMock, logger: defaultLogger}
+			js.syncMaxAckPendingLimit(&nats.StreamInfo{Config: nats.StreamConfig{
+				ConsumerLimits: nats.StreamConsumerLimits{MaxAckPending: tc.givenStreamLimit},
+			}})
+			sub := subtesting.NewSubscription("test", "test",
+				subtesting.WithMaxInFlight(tc.givenSubMaxInFlight),
+			)
+
+			// when
+			err = js.checkMaxInFlightLimit(sub)
+
+			// then
+			if tc.wantError {
+				require.ErrorIs(t, err, ErrMaxInFlightLimit)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_disconnectReason(t *testing.T) {
+	testCases := []struct {
+		name       string
+		givenError error
+		wantReason string
+	}{
+		{
+			name:       "should classify a disconnect without error as closed",
+			givenError: nil,
+			wantReason: metrics.DisconnectReasonClosed,
+		},
+		{
+			name:       "should classify a stale connection",
+			givenError: nats.ErrStaleConnection,
+			wantReason: metrics.DisconnectReasonStaleConnection,
+		},
+		{
+			name:       "should classify an expired authorization",
+			givenError: nats.ErrAuthExpired,
+			wantReason: metrics.DisconnectReasonAuthorization,
+		},
+		{
+			name:       "should classify a connection closed by the server as connection reset",
+			givenError: io.EOF,
+			wantReason: metrics.DisconnectReasonConnectionReset,
+		},
+		{
+			name:       "should classify a network timeout",
+			givenError: &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded},
+			wantReason: metrics.DisconnectReasonTimeout,
+		},
+		{
+			name:       "should classify any other error as other",
+			givenError: errors.New("unexpected"),
+			wantReason: metrics.DisconnectReasonOther,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.wantReason, disconnectReason(tc.givenError))
+		})
+	}
+}
+
+func Test_CountMatchingSubjects_ForNoConnection(t *testing.T) {
+	// given
+	js := &JetStream{}
+
+	// when
+	_, err := js.CountMatchingSubjects("kyma.order.*.v1")
+
+	// then
+	require.ErrorIs(t, err, ErrConnect)
+}
@@ -0,0 +1,389 @@
+// Package migration moves the events and consumers of the JetStream stream to a stream with a different name,
+// subject prefix or storage, for example during an upgrade changing the stream configuration.
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+)
+
+const (
+	// DefaultVerifyTimeout is the default time to wait for the target stream to catch up with the source stream.
+	DefaultVerifyTimeout = 5 * time.Minute
+
+	verifyInterval = time.Second
+
+	// temporaryStreamSuffix is appended to the name of the temporary stream used to migrate a stream to itself.
+	temporaryStreamSuffix = "_migration"
+	maxStreamNameLength   = 32
+)
+
+var (
+	ErrNothingToMigrate = errors.New("the source and target stream configurations are the same")
+	ErrInvalidConfig    = errors.New("invalid stream migration configuration")
+	ErrVerify           = errors.New("failed to verify the migrated events")
+)
+
+// StreamConfig identifies the topology of a stream.
+type StreamConfig struct {
+	// Name of the stream.
+	Name string
+	// SubjectPrefix of the subjects of the stream, the stream captures all subjects matching `<prefix>.>`.
+	SubjectPrefix string
+	// Storage type of the stream, either file or memory. The storage of the source stream is kept if empty.
+	Storage string
+}
+
+// Config is the configuration of a stream migration.
+type Config struct {
+	From StreamConfig
+	To   StreamConfig
+	// DeleteSource deletes the source stream with its consumers once its events are migrated.
+	DeleteSource bool
+	// VerifyTimeout is the time to wait for the target stream to catch up with the source stream.
+	VerifyTimeout time.Duration
+}
+
+// Step is a single step of a migration plan.
+type Step struct {
+	Description string
+	run         func(ctx context.Context, js nats.JetStreamContext) error
+}
+
+// Plan is the list of steps migrating a stream.
+type Plan struct {
+	Steps []Step
+}
+
+// NewPlan generates the plan migrating the stream with its consumers as configured.
+// It reads the configuration and the consumers of the source stream, but does not change anything.
+func NewPlan(js nats.JetStreamContext, config Config) (*Plan, error) {
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+	info, err := js.StreamInfo(config.From.Name)
+	resume := false
+	if errors.Is(err, nats.ErrStreamNotFound) && config.From.Name == config.To.Name {
+		// a previous run failed after deleting the stream, so the migration resumes from the temporary stream
+		info, err = js.StreamInfo(temporaryStreamName(config.To.Name))
+		resume = err == nil
+		if resume {
+			config.From.SubjectPrefix = config.To.SubjectPrefix
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the source stream %s: %w", config.From.Name, err)
+	}
+	wantSubjects := []string{config.From.SubjectPrefix + ".>"}
+	if !equalSubjects(info.Config.Subjects, wantSubjects) {
+		return nil, fmt.Errorf("%w: the source stream %s has the subjects %v instead of %v",
+			ErrInvalidConfig, config.From.Name, info.Config.Subjects, wantSubjects)
+	}
+	storage := info.Config.Storage
+	if config.To.Storage != "" {
+		if err = storage.UnmarshalJSON([]byte(`"` + config.To.Storage + `"`)); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+	}
+	if !resume && config.From.Name == config.To.Name && config.From.SubjectPrefix == config.To.SubjectPrefix &&
+		storage == info.Config.Storage {
+		return nil, ErrNothingToMigrate
+	}
+
+	if !config.DeleteSource && config.From.Name != config.To.Name &&
+		subjectsOverlap(config.From.SubjectPrefix, config.To.SubjectPrefix) {
+		return nil, fmt.Errorf("%w: the source stream must be deleted if the subjects of the streams overlap",
+			ErrInvalidConfig)
+	}
+
+	var consumers []nats.ConsumerConfig
+	for consumer := range js.Consumers(info.Config.Name) {
+		consumers = append(consumers, consumer.Config)
+	}
+
+	verifyTimeout := config.VerifyTimeout
+	if verifyTimeout <= 0 {
+		verifyTimeout = DefaultVerifyTimeout
+	}
+	target := info.Config
+	target.Name = config.To.Name
+	target.Subjects = []string{config.To.SubjectPrefix + ".>"}
+	target.Storage = storage
+	target.Sources, target.Mirror = nil, nil
+
+	// the stream names are unique, so a stream is migrated to itself through a temporary stream
+	plan := &Plan{}
+	if resume {
+		plan.addHop(info.Config, target, consumers, true, verifyTimeout)
+		return plan, nil
+	}
+	if config.From.Name == config.To.Name {
+		temporary := target
+		temporary.Name = temporaryStreamName(config.To.Name)
+		temporaryConsumers := plan.addHop(info.Config, temporary, consumers, true, verifyTimeout)
+		plan.addHop(temporary, target, temporaryConsumers, true, verifyTimeout)
+		return plan, nil
+	}
+	plan.addHop(info.Config, target, consumers, config.DeleteSource, verifyTimeout)
+	return plan, nil
+}
+
+// Run runs the steps of the plan in order and stops at the first failing step.
+// The steps can be run again after a failure, the created streams and consumers are reused.
+func (p *Plan) Run(ctx context.Context, js nats.JetStreamContext, log *zap.SugaredLogger) error {
+	for i, step := range p.Steps {
+		log.Infow("Running stream migration step", "step", i+1, "steps", len(p.Steps),
+			"description", step.Description)
+		if err := step.run(ctx, js); err != nil {
+			return fmt.Errorf("step %d %q failed: %w", i+1, step.Description, err)
+		}
+	}
+	return nil
+}
+
+// String returns the numbered steps of the plan.
+func (p *Plan) String() string {
+	var sb strings.Builder
+	for i, step := range p.Steps {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, step.Description)
+	}
+	return sb.String()
+}
+
+// addHop adds the steps migrating the events and consumers of the source stream to the target stream.
+// It returns the configs of the consumers created on the target stream.
+func (p *Plan) addHop(source, target nats.StreamConfig, consumers []nats.ConsumerConfig, deleteSource bool,
+	verifyTimeout time.Duration) []nats.ConsumerConfig {
+	sourcePrefix := subjectPrefix(source)
+	targetPrefix := subjectPrefix(target)
+	// the subjects of two streams must not overlap, so the target stream captures its subjects
+	// only after the source stream is deleted
+	overlapping := subjectsOverlap(sourcePrefix, targetPrefix)
+
+	initial := target
+	if overlapping {
+		// a stream without subjects and sources captures the subject of its name
+		initial.Subjects = nil
+	}
+	p.add(fmt.Sprintf("Create the stream %s with the subjects %v and the %s storage",
+		target.Name, initial.Subjects, target.Storage),
+		func(_ context.Context, js nats.JetStreamContext) error {
+			return createStream(js, initial)
+		})
+
+	// the consumers are created before the events are sourced, so the events are new to them
+	// and delivered with any deliver policy
+	targetConsumers := make([]nats.ConsumerConfig, 0, len(consumers))
+	for _, consumer := range consumers {
+		targetConsumer := moveConsumer(consumer, sourcePrefix, targetPrefix)
+		targetConsumers = append(targetConsumers, targetConsumer)
+		p.add(fmt.Sprintf("Create the consumer %s for the subject %s on the stream %s, replacing the consumer %s",
+			targetConsumer.Durable, targetConsumer.FilterSubject, target.Name, consumer.Durable),
+			func(_ context.Context, js nats.JetStreamContext) error {
+				return createConsumer(js, target.Name, targetConsumer)
+			})
+	}
+
+	streamSource := &nats.StreamSource{Name: source.Name}
+	if sourcePrefix != targetPrefix {
+		streamSource.SubjectTransforms = []nats.SubjectTransformConfig{
+			{Source: sourcePrefix + ".>", Destination: targetPrefix + ".>"},
+		}
+	}
+	p.add(fmt.Sprintf("Source the events of the stream %s into the stream %s", source.Name, target.Name),
+		func(_ context.Context, js nats.JetStreamContext) error {
+			sourcing := initial
+			sourcing.Subjects = nil
+			if !overlapping {
+				sourcing.Subjects = target.Subjects
+			}
+			sourcing.Sources = []*nats.StreamSource{streamSource}
+			_, err := js.UpdateStream(&sourcing)
+			return err
+		})
+
+	p.add(fmt.Sprintf("Verify that the stream %s holds all events of the stream %s", target.Name, source.Name),
+		func(ctx context.Context, js nats.JetStreamContext) error {
+			return verify(ctx, js, source.Name, target.Name, verifyTimeout)
+		})
+
+	deleteStep := func() {
+		p.add(fmt.Sprintf("Delete the stream %s with its consumers", source.Name),
+			func(_ context.Context, js nats.JetStreamContext) error {
+				if err := js.DeleteStream(source.Name); err != nil && !errors.Is(err, nats.ErrStreamNotFound) {
+					return err
+				}
+				return nil
+			})
+	}
+	if overlapping {
+		// the events published between these steps are rejected, since no stream captures their subjects
+		deleteStep()
+	}
+	p.add(fmt.Sprintf("Stop sourcing the stream %s into the stream %s capturing the subjects %v",
+		source.Name, target.Name, target.Subjects),
+		func(_ context.Context, js nats.JetStreamContext) error {
+			_, err := js.UpdateStream(&target)
+			return err
+		})
+	if deleteSource && !overlapping {
+		deleteStep()
+	}
+	return targetConsumers
+}
+
+func (p *Plan) add(description string, run func(ctx context.Context, js nats.JetStreamContext) error) {
+	p.Steps = append(p.Steps, Step{Description: description, run: run})
+}
+
+// createStream creates the stream unless it already exists from a previous run.
+func createStream(js nats.JetStreamContext, config nats.StreamConfig) error {
+	_, err := js.StreamInfo(config.Name)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, nats.ErrStreamNotFound) {
+		return err
+	}
+	_, err = js.AddStream(&config)
+	return err
+}
+
+// createConsumer creates the consumer unless it already exists from a previous run.
+func createConsumer(js nats.JetStreamContext, stream string, config nats.ConsumerConfig) error {
+	_, err := js.ConsumerInfo(stream, config.Durable)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, nats.ErrConsumerNotFound) {
+		return err
+	}
+	_, err = js.AddConsumer(stream, &config)
+	return err
+}
+
+// moveConsumer returns the config of the consumer for the subjects with the target prefix. The consumers of
+// the Subscriptions are renamed to the names computed by the controller for the new subjects.
+func moveConsumer(consumer nats.ConsumerConfig, sourcePrefix, targetPrefix string) nats.ConsumerConfig {
+	moved := consumer
+	moved.FilterSubject = replacePrefix(consumer.FilterSubject, sourcePrefix, targetPrefix)
+	moved.FilterSubjects = nil
+	for _, subject := range consumer.FilterSubjects {
+		moved.FilterSubjects = append(moved.FilterSubjects, replacePrefix(subject, sourcePrefix, targetPrefix))
+	}
+	namespace := consumer.Metadata[jetstream.ConsumerMetadataOwnerNamespace]
+	name := consumer.Metadata[jetstream.ConsumerMetadataOwnerName]
+	if namespace != "" && name != "" {
+		moved.Durable = jetstream.ConsumerName(namespace, name, moved.FilterSubject)
+		moved.Description = namespace + "/" + name + "/" + moved.FilterSubject
+	}
+	moved.Name = moved.Durable
+	if consumer.DeliverSubject != "" {
+		// the deliver subject of the source consumer is still used by its subscription
+		moved.DeliverSubject = nats.NewInbox()
+	}
+	return moved
+}
+
+// verify waits until the target stream sourced all events of the source stream. The consumers of the source
+// stream may still remove acknowledged events, so the target stream can hold more events than the source stream.
+func verify(ctx context.Context, js nats.JetStreamContext, source, target string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(verifyInterval)
+	defer ticker.Stop()
+	for {
+		sourceInfo, err := js.StreamInfo(source)
+		if err != nil {
+			return err
+		}
+		targetInfo, err := js.StreamInfo(target)
+		if err != nil {
+			return err
+		}
+		lag, sourcing := sourceLag(targetInfo, source)
+		if sourcing && lag == 0 && targetInfo.State.Msgs >= sourceInfo.State.Msgs {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: the stream %s holds %d events with a lag of %d, the stream %s holds %d events",
+				ErrVerify, target, targetInfo.State.Msgs, lag, source, sourceInfo.State.Msgs)
+		case <-ticker.C:
+		}
+	}
+}
+
+// sourceLag returns the number of events of the source stream not sourced by the target stream yet,
+// and whether the target stream is sourcing the source stream.
+func sourceLag(target *nats.StreamInfo, source string) (uint64, bool) {
+	for _, info := range target.Sources {
+		if info.Name == source && info.Error == nil {
+			return info.Lag, true
+		}
+	}
+	return 0, false
+}
+
+func validate(config Config) error {
+	for _, c := range []StreamConfig{config.From, config.To} {
+		if c.Name == "" || c.SubjectPrefix == "" {
+			return fmt.Errorf("%w: the stream name and subject prefix must not be empty", ErrInvalidConfig)
+		}
+		if strings.ContainsAny(c.Name, ".*> ") {
+			return fmt.Errorf("%w: the stream name %q must not contain '.', '*', '>' or spaces", ErrInvalidConfig, c.Name)
+		}
+	}
+	if len(config.To.Name) > maxStreamNameLength {
+		return fmt.Errorf("%w: the stream name %q must be at most %d characters long",
+			ErrInvalidConfig, config.To.Name, maxStreamNameLength)
+	}
+	return nil
+}
+
+// temporaryStreamName returns the name of the temporary stream, shortened to the maximum stream name length.
+func temporaryStreamName(name string) string {
+	if maxLength := maxStreamNameLength - len(temporaryStreamSuffix); len(name) > maxLength {
+		name = name[:maxLength]
+	}
+	return name + temporaryStreamSuffix
+}
+
+func subjectPrefix(config nats.StreamConfig) string {
+	if len(config.Subjects) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(config.Subjects[0], ".>")
+}
+
+// subjectsOverlap returns true if the subjects with the given prefixes overlap.
+func subjectsOverlap(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+func replacePrefix(subject, from, to string) string {
+	if rest, ok := strings.CutPrefix(subject, from+"."); ok {
+		return to + "." + rest
+	}
+	return subject
+}
+
+func equalSubjects(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+	evtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+const (
+	testSubject = "order.created.v1"
+	testEvents  = 10
+)
+
+// setupStream starts a NATS server with a file stream of the given name and prefix holding the test events,
+// and a push consumer of a Subscription for them.
+func setupStream(t *testing.T, name, prefix string) nats.JetStreamContext {
+	t.Helper()
+	natsServer, _, err := jetstream.StartNATSServer(evtesting.WithJetStreamEnabled(),
+		evtesting.WithStoreDir(t.TempDir()))
+	require.NoError(t, err)
+	t.Cleanup(natsServer.Shutdown)
+	conn, err := nats.Connect(natsServer.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+	js, err := conn.JetStream()
+	require.NoError(t, err)
+
+	_, err = js.AddStream(&nats.StreamConfig{
+		Name:      name,
+		Subjects:  []string{prefix + ".>"},
+		Storage:   nats.FileStorage,
+		Retention: nats.InterestPolicy,
+	})
+	require.NoError(t, err)
+	subject := prefix + "." + testSubject
+	_, err = js.AddConsumer(name, &nats.ConsumerConfig{
+		Durable:        jetstream.ConsumerName("test", "sub", subject),
+		Description:    "test/sub/" + subject,
+		DeliverSubject: nats.NewInbox(),
+		DeliverPolicy:  nats.DeliverNewPolicy,
+		AckPolicy:      nats.AckExplicitPolicy,
+		FilterSubject:  subject,
+		Metadata: map[string]string{
+			jetstream.ConsumerMetadataOwnerNamespace: "test",
+			jetstream.ConsumerMetadataOwnerName:      "sub",
+		},
+	})
+	require.NoError(t, err)
+	for i := 0; i < testEvents; i++ {
+		_, err = js.Publish(subject, []byte("event"))
+		require.NoError(t, err)
+	}
+	return js
+}
+
+func TestPlan_Run(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       Config
+		wantSteps    int
+		wantDeleted  bool
+		wantStorage  nats.StorageType
+		wantSubjects []string
+	}{
+		{
+			name: "should migrate the stream to a stream with another name and subject prefix",
+			config: Config{
+				From:          StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+				To:            StreamConfig{Name: "eventing", SubjectPrefix: "events"},
+				DeleteSource:  true,
+				VerifyTimeout: 10 * time.Second,
+			},
+			wantSteps:    6,
+			wantDeleted:  true,
+			wantStorage:  nats.FileStorage,
+			wantSubjects: []string{"events.>"},
+		},
+		{
+			name: "should migrate the stream to a stream with another name and the same subject prefix",
+			config: Config{
+				From:          StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+				To:            StreamConfig{Name: "eventing", SubjectPrefix: "kyma"},
+				DeleteSource:  true,
+				VerifyTimeout: 10 * time.Second,
+			},
+			wantSteps:    6,
+			wantDeleted:  true,
+			wantStorage:  nats.FileStorage,
+			wantSubjects: []string{"kyma.>"},
+		},
+		{
+			name: "should keep the source stream if it is not deleted",
+			config: Config{
+				From:          StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+				To:            StreamConfig{Name: "eventing", SubjectPrefix: "events", Storage: "memory"},
+				VerifyTimeout: 10 * time.Second,
+			},
+			wantSteps:    5,
+			wantStorage:  nats.MemoryStorage,
+			wantSubjects: []string{"events.>"},
+		},
+		{
+			name: "should migrate the stream to itself with another storage",
+			config: Config{
+				From:          StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+				To:            StreamConfig{Name: "sap", SubjectPrefix: "kyma", Storage: "memory"},
+				VerifyTimeout: 10 * time.Second,
+			},
+			wantSteps:    12,
+			wantDeleted:  true,
+			wantStorage:  nats.MemoryStorage,
+			wantSubjects: []string{"kyma.>"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			js := setupStream(t, tc.config.From.Name, tc.config.From.SubjectPrefix)
+			plan, err := NewPlan(js, tc.config)
+			require.NoError(t, err)
+			require.Len(t, plan.Steps, tc.wantSteps)
+
+			// when
+			err = plan.Run(context.Background(), js, zap.NewNop().Sugar())
+
+			// then
+			require.NoError(t, err)
+			info, err := js.StreamInfo(tc.config.To.Name)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantSubjects, info.Config.Subjects)
+			require.Equal(t, tc.wantStorage, info.Config.Storage)
+			require.Empty(t, info.Config.Sources)
+			require.Equal(t, uint64(testEvents), info.State.Msgs)
+
+			// the consumer of the Subscription for the new subject gets all events
+			subject := tc.config.To.SubjectPrefix + "." + testSubject
+			consumer, err := js.ConsumerInfo(tc.config.To.Name, jetstream.ConsumerName("test", "sub", subject))
+			require.NoError(t, err)
+			require.Equal(t, subject, consumer.Config.FilterSubject)
+			require.Equal(t, uint64(testEvents), consumer.NumPending)
+
+			if tc.config.From.Name != tc.config.To.Name {
+				_, err = js.StreamInfo(tc.config.From.Name)
+				if tc.wantDeleted {
+					require.ErrorIs(t, err, nats.ErrStreamNotFound)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+			_, err = js.StreamInfo(temporaryStreamName(tc.config.To.Name))
+			require.ErrorIs(t, err, nats.ErrStreamNotFound)
+		})
+	}
+}
+
+func TestNewPlan_Resume(t *testing.T) {
+	// given a previous run which failed after moving the stream into the temporary stream
+	js := setupStream(t, temporaryStreamName("sap"), "kyma")
+	config := Config{
+		From:          StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+		To:            StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+		VerifyTimeout: 10 * time.Second,
+	}
+
+	// when
+	plan, err := NewPlan(js, config)
+	require.NoError(t, err)
+	err = plan.Run(context.Background(), js, zap.NewNop().Sugar())
+
+	// then the events are moved from the temporary stream
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 6)
+	info, err := js.StreamInfo("sap")
+	require.NoError(t, err)
+	require.Equal(t, uint64(testEvents), info.State.Msgs)
+	_, err = js.StreamInfo(temporaryStreamName("sap"))
+	require.ErrorIs(t, err, nats.ErrStreamNotFound)
+}
+
+func TestNewPlan_Errors(t *testing.T) {
+	js := setupStream(t, "sap", "kyma")
+	testCases := []struct {
+		name    string
+		config  Config
+		wantErr error
+	}{
+		{
+			name: "should fail if the topology is unchanged",
+			config: Config{
+				From: StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+				To:   StreamConfig{Name: "sap", SubjectPrefix: "kyma", Storage: "file"},
+			},
+			wantErr: ErrNothingToMigrate,
+		},
+		{
+			name: "should fail if the source stream does not exist",
+			config: Config{
+				From: StreamConfig{Name: "missing", SubjectPrefix: "kyma"},
+				To:   StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+			},
+			wantErr: nats.ErrStreamNotFound,
+		},
+		{
+			name: "should fail if the source stream has other subjects",
+			config: Config{
+				From: StreamConfig{Name: "sap", SubjectPrefix: "other"},
+				To:   StreamConfig{Name: "eventing", SubjectPrefix: "kyma"},
+			},
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "should fail if the subjects overlap and the source stream is kept",
+			config: Config{
+				From: StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+				To:   StreamConfig{Name: "eventing", SubjectPrefix: "kyma.events"},
+			},
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "should fail if the storage is invalid",
+			config: Config{
+				From: StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+				To:   StreamConfig{Name: "eventing", SubjectPrefix: "kyma", Storage: "disk"},
+			},
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "should fail if the stream name is invalid",
+			config: Config{
+				From: StreamConfig{Name: "sap", SubjectPrefix: "kyma"},
+				To:   StreamConfig{Name: "event.ing", SubjectPrefix: "kyma"},
+			},
+			wantErr: ErrInvalidConfig,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewPlan(js, tc.config)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestTemporaryStreamName(t *testing.T) {
+	require.Equal(t, "sap_migration", temporaryStreamName("sap"))
+	require.Len(t, temporaryStreamName("a-stream-name-of-32-characters-x"), maxStreamNameLength)
+}
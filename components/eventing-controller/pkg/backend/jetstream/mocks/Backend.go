@@ -19,6 +19,54 @@ type Backend struct {
 	mock.Mock
 }
 
+// CountMatchingSubjects provides a mock function with given fields: subject
+func (_m *Backend) CountMatchingSubjects(subject string) (int, error) {
+	ret := _m.Called(subject)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int, error)); ok {
+		return rf(subject)
+	}
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(subject)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountPendingMessages provides a mock function with given fields: subscription
+func (_m *Backend) CountPendingMessages(subscription *v1alpha2.Subscription) (int, error) {
+	ret := _m.Called(subscription)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*v1alpha2.Subscription) (int, error)); ok {
+		return rf(subscription)
+	}
+	if rf, ok := ret.Get(0).(func(*v1alpha2.Subscription) int); ok {
+		r0 = rf(subscription)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(*v1alpha2.Subscription) error); ok {
+		r1 = rf(subscription)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteInvalidConsumers provides a mock function with given fields: subscriptions
 func (_m *Backend) DeleteInvalidConsumers(subscriptions []v1alpha2.Subscription) error {
 	ret := _m.Called(subscriptions)
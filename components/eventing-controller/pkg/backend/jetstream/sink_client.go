@@ -0,0 +1,133 @@
+package jetstream
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
+	pkgerrors "github.com/kyma-project/kyma/components/eventing-controller/pkg/errors"
+)
+
+// sinkClient is a CloudEvents client dedicated to the sink of a subscription with custom TLS settings
+// or a ServiceAccount to authenticate at the sink.
+type sinkClient struct {
+	client    cev2.Client
+	transport *http.Transport
+	// fingerprint identifies the TLS and authentication settings the client was created with.
+	fingerprint string
+}
+
+// SetCABundleLoader sets the loader for the CA bundles referenced by the sink TLS settings of the subscriptions.
+func (js *JetStream) SetCABundleLoader(loader sink.CABundleLoader) {
+	js.caBundleLoader = loader
+}
+
+// SetTokenProvider sets the provider for the ServiceAccount tokens sent to the sinks of the subscriptions.
+func (js *JetStream) SetTokenProvider(provider sink.TokenProvider) {
+	js.tokenProvider = provider
+}
+
+// syncSinkClient creates a dedicated CloudEvents client for the subscription if it has custom sink TLS settings
+// or a ServiceAccount, otherwise the subscription uses the shared client.
+func (js *JetStream) syncSinkClient(subscription *eventingv1alpha2.Subscription) error {
+	subKeyPrefix := createKeyPrefix(subscription)
+	sinkTLS := subscription.Spec.SinkTLS
+	hasTLS := sinkTLS != nil && (sinkTLS.CABundleRef != nil || sinkTLS.InsecureSkipVerify)
+	serviceAccountName := subscription.Spec.ServiceAccountName
+	if !hasTLS && serviceAccountName == "" {
+		js.deleteSinkClient(subKeyPrefix)
+		return nil
+	}
+	if serviceAccountName != "" && js.tokenProvider == nil {
+		return ErrTokenProviderNotSet
+	}
+
+	var caBundle []byte
+	if hasTLS && sinkTLS.CABundleRef != nil {
+		if js.caBundleLoader == nil {
+			return ErrCABundleLoaderNotSet
+		}
+		var err error
+		if caBundle, err = js.caBundleLoader.Load(context.Background(), subscription); err != nil {
+			return pkgerrors.MakeError(ErrLoadCABundle, err)
+		}
+	}
+
+	// the sink is part of the fingerprint, because it is the audience of the ServiceAccount tokens
+	fingerprint := fmt.Sprintf("%t/%x/%s/%s", hasTLS && sinkTLS.InsecureSkipVerify, sha256.Sum256(caBundle),
+		serviceAccountName, subscription.Spec.Sink)
+	if value, ok := js.sinkClients.Load(subKeyPrefix); ok {
+		if current, ok := value.(*sinkClient); ok && current.fingerprint == fingerprint {
+			return nil
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if hasTLS {
+		var err error
+		if tlsConfig, err = sink.NewTLSConfig(sinkTLS, caBundle); err != nil {
+			return pkgerrors.MakeError(ErrLoadCABundle, err)
+		}
+	}
+	transport := js.newTransport(tlsConfig)
+	var roundTripper http.RoundTripper = transport
+	if serviceAccountName != "" {
+		roundTripper = sink.NewTokenRoundTripper(transport, js.tokenProvider,
+			subscription.Namespace, serviceAccountName, subscription.Spec.Sink)
+	}
+	client, err := cev2.NewClientHTTP(cev2.WithRoundTripper(roundTripper))
+	if err != nil {
+		return err
+	}
+	js.deleteSinkClient(subKeyPrefix)
+	js.sinkClients.Store(subKeyPrefix, &sinkClient{client: client, transport: transport, fingerprint: fingerprint})
+	return nil
+}
+
+// deleteSinkClient removes the dedicated CloudEvents client of the subscription and closes its idle connections.
+func (js *JetStream) deleteSinkClient(subKeyPrefix string) {
+	value, ok := js.sinkClients.LoadAndDelete(subKeyPrefix)
+	if !ok {
+		return
+	}
+	if current, ok := value.(*sinkClient); ok {
+		current.transport.CloseIdleConnections()
+	}
+}
+
+// getSinkClient returns the CloudEvents client used to dispatch events to the sink of the subscription.
+func (js *JetStream) getSinkClient(subKeyPrefix string) cev2.Client {
+	if value, ok := js.sinkClients.Load(subKeyPrefix); ok {
+		if current, ok := value.(*sinkClient); ok {
+			return current.client
+		}
+	}
+	return js.client
+}
+
+// newCloudEventClient creates a CloudEvents HTTP client with the configured transport settings.
+func (js *JetStream) newCloudEventClient(tlsConfig *tls.Config) (cev2.Client, *http.Transport, error) {
+	transport := js.newTransport(tlsConfig)
+	client, err := cev2.NewClientHTTP(cev2.WithRoundTripper(transport))
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, transport, nil
+}
+
+// newTransport creates an HTTP transport with the configured connection settings.
+func (js *JetStream) newTransport(tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		MaxIdleConns:        js.Config.MaxIdleConns,
+		MaxConnsPerHost:     js.Config.MaxConnsPerHost,
+		MaxIdleConnsPerHost: js.Config.MaxIdleConnsPerHost,
+		IdleConnTimeout:     js.Config.IdleConnTimeout,
+		TLSClientConfig:     tlsConfig,
+	}
+}
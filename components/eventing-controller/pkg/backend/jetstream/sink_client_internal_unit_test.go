@@ -0,0 +1,173 @@
+//go:build unit
+
+package jetstream
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
+	subtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+type caBundleLoaderFunc func(ctx context.Context, subscription *v1alpha2.Subscription) ([]byte, error)
+
+func (f caBundleLoaderFunc) Load(ctx context.Context, subscription *v1alpha2.Subscription) ([]byte, error) {
+	return f(ctx, subscription)
+}
+
+type tokenProviderFunc func(ctx context.Context, namespace, serviceAccountName, audience string) (string, error)
+
+func (f tokenProviderFunc) Token(ctx context.Context, namespace, serviceAccountName, audience string) (string, error) {
+	return f(ctx, namespace, serviceAccountName, audience)
+}
+
+// Test_SyncSinkClient tests that only the subscriptions with custom sink TLS settings get a dedicated client.
+func Test_SyncSinkClient(t *testing.T) {
+	// given
+	js := &JetStream{}
+	require.NoError(t, js.initCloudEventClient())
+	sub := subtesting.NewSubscription("sub", "ns", subtesting.WithSink("https://sink.ns.svc.cluster.local"))
+	subKeyPrefix := createKeyPrefix(sub)
+
+	// when
+	require.NoError(t, js.syncSinkClient(sub))
+
+	// then
+	require.Equal(t, js.client, js.getSinkClient(subKeyPrefix))
+
+	// when
+	sub.Spec.SinkTLS = &v1alpha2.SinkTLSConfig{InsecureSkipVerify: true}
+	require.NoError(t, js.syncSinkClient(sub))
+	dedicatedClient := js.getSinkClient(subKeyPrefix)
+
+	// then
+	require.NotEqual(t, js.client, dedicatedClient)
+
+	// when the settings did not change
+	require.NoError(t, js.syncSinkClient(sub))
+
+	// then the client is reused
+	require.Equal(t, dedicatedClient, js.getSinkClient(subKeyPrefix))
+
+	// when
+	sub.Spec.SinkTLS = nil
+	require.NoError(t, js.syncSinkClient(sub))
+
+	// then
+	require.Equal(t, js.client, js.getSinkClient(subKeyPrefix))
+}
+
+func Test_SyncSinkClientWithCABundleReference(t *testing.T) {
+	// given
+	loadErr := errors.New("not found")
+	sub := subtesting.NewSubscription("sub", "ns",
+		subtesting.WithSink("https://sink.ns.svc.cluster.local"),
+		subtesting.WithSinkTLS(&v1alpha2.SinkTLSConfig{
+			CABundleRef: &v1alpha2.CABundleReference{Kind: v1alpha2.CABundleKindSecret, Name: "ca"},
+		}),
+	)
+
+	testCases := []struct {
+		name        string
+		givenLoader sink.CABundleLoader
+		wantErr     error
+	}{
+		{
+			name:        "should fail if no loader is set",
+			givenLoader: nil,
+			wantErr:     ErrCABundleLoaderNotSet,
+		},
+		{
+			name: "should fail if the CA bundle cannot be loaded",
+			givenLoader: caBundleLoaderFunc(func(context.Context, *v1alpha2.Subscription) ([]byte, error) {
+				return nil, loadErr
+			}),
+			wantErr: ErrLoadCABundle,
+		},
+		{
+			name: "should fail if the CA bundle is invalid",
+			givenLoader: caBundleLoaderFunc(func(context.Context, *v1alpha2.Subscription) ([]byte, error) {
+				return []byte("invalid"), nil
+			}),
+			wantErr: ErrLoadCABundle,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			js := &JetStream{}
+			js.SetCABundleLoader(tc.givenLoader)
+
+			// when
+			err := js.syncSinkClient(sub)
+
+			// then
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+// Test_SyncSinkClientWithServiceAccount tests that the events are dispatched with a token of the ServiceAccount
+// bound to the sink.
+func Test_SyncSinkClientWithServiceAccount(t *testing.T) {
+	// given
+	var gotAuthorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	js := &JetStream{}
+	require.NoError(t, js.initCloudEventClient())
+	sub := subtesting.NewSubscription("sub", "ns",
+		subtesting.WithSink(server.URL),
+		subtesting.WithServiceAccountName("dispatcher"),
+	)
+	subKeyPrefix := createKeyPrefix(sub)
+
+	// when no token provider is set
+	err := js.syncSinkClient(sub)
+
+	// then
+	require.ErrorIs(t, err, ErrTokenProviderNotSet)
+
+	// when
+	js.SetTokenProvider(tokenProviderFunc(func(_ context.Context, namespace, name, audience string) (string, error) {
+		return namespace + "/" + name + "/" + audience, nil
+	}))
+	require.NoError(t, js.syncSinkClient(sub))
+	dedicatedClient := js.getSinkClient(subKeyPrefix)
+	event := cev2.NewEvent()
+	event.SetID("id")
+	event.SetSource("source")
+	event.SetType("type")
+	result := dedicatedClient.Send(cev2.ContextWithTarget(context.Background(), server.URL), event)
+
+	// then
+	require.True(t, cev2.IsACK(result))
+	require.Equal(t, "Bearer ns/dispatcher/"+server.URL, gotAuthorization)
+
+	// when the sink changed
+	sub.Spec.Sink = server.URL + "/events"
+	require.NoError(t, js.syncSinkClient(sub))
+
+	// then a new client is created for the new audience
+	require.NotEqual(t, dedicatedClient, js.getSinkClient(subKeyPrefix))
+
+	// when
+	sub.Spec.ServiceAccountName = ""
+	require.NoError(t, js.syncSinkClient(sub))
+
+	// then
+	require.Equal(t, js.client, js.getSinkClient(subKeyPrefix))
+}
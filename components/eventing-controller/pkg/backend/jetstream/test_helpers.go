@@ -1,10 +1,8 @@
 package jetstream
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"net/http"
 	"time"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
@@ -17,7 +15,6 @@ import (
 	nats2 "github.com/cloudevents/sdk-go/protocol/nats/v2"
 	v2 "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
-	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
 	evtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
@@ -35,10 +32,12 @@ type TestEnvironment struct {
 	jsBackend  *JetStream
 	logger     *logger.Logger
 	natsServer *server.Server
-	jsClient   *jetStreamClient
-	natsConfig env.NATSConfig
-	cleaner    cleaner.Cleaner
-	natsPort   int
+	// natsContainer is the nats-server container used instead of the embedded natsServer, if it is set.
+	natsContainer *evtesting.NatsContainer
+	jsClient      *jetStreamClient
+	natsConfig    env.NATSConfig
+	cleaner       cleaner.Cleaner
+	natsPort      int
 }
 
 func StartNATSServer(serverOpts ...evtesting.NatsServerOpt) (*server.Server, int, error) {
@@ -71,53 +70,34 @@ func SendEventToJetStream(jsClient *JetStream, data string) error {
 }
 
 func SendCloudEventToJetStream(jetStreamClient *JetStream, subject, eventData, cetype string) error {
-	// create a CE http request
-	var headers http.Header
-	if cetype == types.ContentModeBinary {
-		headers = evtesting.GetBinaryMessageHeaders()
-	} else {
-		headers = evtesting.GetStructuredMessageHeaders()
-	}
-	req, err := http.NewRequest(http.MethodPost, "dummy", bytes.NewBuffer([]byte(eventData)))
-	if err != nil {
-		return err
-	}
-	for k, v := range headers {
-		req.Header[k] = v
-	}
-	// convert  to the CE Event
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	message := cev2http.NewMessageFromHttpRequest(req)
-	defer func() { _ = message.Finish(nil) }()
-	event, err := binding.ToEvent(ctx, message)
-	if err != nil {
-		return err
-	}
-	if validateErr := event.Validate(); validateErr != nil {
-		return validateErr
-	}
+	return PublishCloudEventToJetStream(jetStreamClient, subject,
+		evtesting.NewCloudEventBuilder().WithData(eventData), cetype)
+}
+
+// PublishCloudEventToJetStream sends the built CloudEvent to the subject in the given content mode, without
+// validating it.
+func PublishCloudEventToJetStream(jetStreamClient *JetStream, subject string, builder *evtesting.CloudEventBuilder,
+	contentMode string) error {
 	// get a CE sender for the embedded NATS using CE-SDK
 	natsOpts := nats2.NatsOptions()
 	url := jetStreamClient.Config.URL
 	sender, err := nats2.NewSender(url, subject, natsOpts)
 	if err != nil {
-		return nil
+		return err
 	}
 	client, err := v2.NewClient(sender)
 	if err != nil {
 		return err
 	}
-	// force binary binding and send the event to NATS using CE-SDK
-	if cetype == types.ContentModeBinary {
+	// force the content mode and send the event to NATS using CE-SDK
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if contentMode == types.ContentModeBinary {
 		ctx = binding.WithForceBinary(ctx)
 	} else {
 		ctx = binding.WithForceStructured(ctx)
 	}
-	if err := client.Send(ctx, *event); err != nil {
-		return err
-	}
-	return nil
+	return client.Send(ctx, builder.Build())
 }
 
 func AddJSCleanEventTypesToStatus(sub *v1alpha2.Subscription, cleaner cleaner.Cleaner) {
@@ -4,6 +4,7 @@ package jetstream
 
 import (
 	"sync"
+	"sync/atomic"
 
 	backendutilsv2 "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
 
@@ -12,8 +13,11 @@ import (
 
 	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/audit"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
 	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/cloudevent/validation"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
 )
 
@@ -39,6 +43,12 @@ type Backend interface {
 	// GetJetStreamSubjects returns a list of subjects appended with stream name and source as prefix if needed
 	GetJetStreamSubjects(source string, subjects []string, typeMatching eventingv1alpha2.TypeMatching) []string
 
+	// CountMatchingSubjects returns the number of concrete subjects in the stream matched by the given subject
+	CountMatchingSubjects(subject string) (int, error)
+
+	// CountPendingMessages returns the number of messages of the subscription which are not acknowledged yet
+	CountPendingMessages(subscription *eventingv1alpha2.Subscription) (int, error)
+
 	// DeleteInvalidConsumers deletes all JetStream consumers having no subscription types in subscription resources
 	DeleteInvalidConsumers(subscriptions []eventingv1alpha2.Subscription) error
 
@@ -50,18 +60,49 @@ type Backend interface {
 }
 
 type JetStream struct {
-	Config        env.NATSConfig
+	Config env.NATSConfig
+	// mutex serializes the operations of the reconciliations with each other and with the reconnects
+	// on changed credentials, which replace the connection and the JetStream context.
+	mutex sync.Mutex
+	// connMutex guards the connection and the JetStream context read outside the serialized operations,
+	// such as by the dispatch callbacks.
+	connMutex     sync.RWMutex
 	Conn          *nats.Conn
 	jsCtx         nats.JetStreamContext
 	client        cev2.Client
 	subscriptions map[SubscriptionSubjectIdentifier]Subscriber
-	sinks         sync.Map
+	// coreSubscriptions stores the core NATS subscriptions of the subscriptions in the core delivery mode.
+	coreSubscriptions map[SubscriptionSubjectIdentifier]*coreSubscription
+	sinks             sync.Map
+	// filters stores the attribute filters of each subscription, used by the dispatch callbacks.
+	filters sync.Map
+	// expressions stores the parsed filter expression of each subscription, used by the dispatch callbacks.
+	expressions sync.Map
 	// connClosedHandler gets called by the NATS server when Conn is closed and retry attempts are exhausted.
 	connClosedHandler backendutilsv2.ConnClosedHandler
 	logger            *logger.Logger
 	metricsCollector  *backendmetrics.Collector
 	cleaner           cleaner.Cleaner
 	subsConfig        env.DefaultSubscriptionConfig
+	// maxAckPendingLimit is the lowest MaxAckPending limit of the stream consumers and the JetStream account,
+	// it is 0 if there is no limit.
+	maxAckPendingLimit atomic.Int64
+	// caBundleLoader loads the CA bundles referenced by the sink TLS settings of the subscriptions.
+	caBundleLoader sink.CABundleLoader
+	// tokenProvider provides the ServiceAccount tokens sent to the sinks of the subscriptions.
+	tokenProvider sink.TokenProvider
+	// sinkClients stores the dedicated CloudEvents clients of the subscriptions with custom sink TLS settings
+	// or a ServiceAccount.
+	sinkClients sync.Map
+	// dispatchEvents records Kubernetes Events for dispatch failures, it is nil if no recorder is set.
+	dispatchEvents *dispatchEvents
+	// dispatchLogSampler samples the debug and info lines logged for each dispatched event by subscription,
+	// it is nil if all lines are logged.
+	dispatchLogSampler *logger.Sampler
+	// validator checks the dispatched events against the CloudEvents specification, nil if they are not checked.
+	validator *validation.Validator
+	// auditor records the changes of the consumers, it is nil if they are not audited.
+	auditor *audit.Auditor
 }
 
 func (js *JetStream) GetConfig() env.NATSConfig {
@@ -17,6 +17,7 @@ import (
 
 	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
 )
 
 const (
@@ -33,6 +34,14 @@ const (
 	ConsumerDeliverPolicyLast           = "last"
 	ConsumerDeliverPolicyLastPerSubject = "last_per_subject"
 	ConsumerDeliverPolicyNew            = "new"
+
+	// The consumer metadata keys identifying the Subscription which owns the consumer.
+	ConsumerMetadataOwnerNamespace = backendutils.OwnerMetadataNamespace
+	ConsumerMetadataOwnerName      = backendutils.OwnerMetadataName
+	ConsumerMetadataOwnerUID       = backendutils.OwnerMetadataUID
+	// ConsumerMetadataBridgeCluster is the consumer metadata key marking the consumers of the bridge, which forwards
+	// the events to another cluster. These consumers are not owned by any Subscription.
+	ConsumerMetadataBridgeCluster = "eventing.kyma-project.io/bridge-cluster"
 )
 
 // getDefaultSubscriptionOptions builds the default nats.SubOpts by using the subscription/consumer configuration.
@@ -168,8 +177,9 @@ func getStreamConfig(natsConfig env.NATSConfig) (*nats.StreamConfig, error) {
 
 // getConsumerConfig return the consumerConfig according to the default configuration.
 func (js *JetStream) getConsumerConfig(jsSubKey SubscriptionSubjectIdentifier,
-	jsSubject string, maxInFlight int) *nats.ConsumerConfig {
+	jsSubject string, maxInFlight int, owner *eventingv1alpha2.Subscription) *nats.ConsumerConfig {
 	return &nats.ConsumerConfig{
+		Metadata:       backendutils.GetOwnerMetadata(owner),
 		Durable:        jsSubKey.ConsumerName(),
 		Description:    jsSubKey.namespacedSubjectName,
 		DeliverPolicy:  toJetStreamConsumerDeliverPolicy(js.Config.JSConsumerDeliverPolicy),
@@ -185,6 +195,23 @@ func (js *JetStream) getConsumerConfig(jsSubKey SubscriptionSubjectIdentifier,
 	}
 }
 
+// isOwnedBy returns true if the consumer metadata identifies the given Subscription as the owner of the consumer.
+func isOwnedBy(consumerConfig nats.ConsumerConfig, owner *eventingv1alpha2.Subscription) bool {
+	return consumerConfig.Metadata[ConsumerMetadataOwnerNamespace] == owner.Namespace &&
+		consumerConfig.Metadata[ConsumerMetadataOwnerName] == owner.Name &&
+		consumerConfig.Metadata[ConsumerMetadataOwnerUID] == string(owner.UID)
+}
+
+// consumerOwner returns the namespaced name of the Subscription identified by the consumer metadata as the owner of
+// the consumer, or an empty string if the consumer has no owner metadata.
+func consumerOwner(consumerConfig nats.ConsumerConfig) string {
+	namespace, ok := consumerConfig.Metadata[ConsumerMetadataOwnerNamespace]
+	if !ok {
+		return ""
+	}
+	return namespace + separator + consumerConfig.Metadata[ConsumerMetadataOwnerName]
+}
+
 func createKeyPrefix(sub *eventingv1alpha2.Subscription) string {
 	namespacedName := types.NamespacedName{
 		Namespace: sub.Namespace,
@@ -284,7 +311,13 @@ func NewSubscriptionSubjectIdentifier(subscription *eventingv1alpha2.Subscriptio
 // It uses the crypto/md5 lib to return a string of 32 characters as recommended by the JetStream
 // documentation https://docs.nats.io/running-a-nats-service/nats_admin/jetstream_admin/naming.
 func computeConsumerName(subscription *eventingv1alpha2.Subscription, subject string) string {
-	cn := subscription.Namespace + separator + subscription.Name + separator + subject
+	return ConsumerName(subscription.Namespace, subscription.Name, subject)
+}
+
+// ConsumerName returns the JetStream consumer name of the subscription with the given namespace and name
+// for the given subject.
+func ConsumerName(namespace, name, subject string) string {
+	cn := namespace + separator + name + separator + subject
 	h := md5.Sum([]byte(cn)) // #nosec
 	return hex.EncodeToString(h[:])
 }
@@ -293,3 +326,18 @@ func computeConsumerName(subscription *eventingv1alpha2.Subscription, subject st
 func computeNamespacedSubjectName(subscription *eventingv1alpha2.Subscription, subject string) string {
 	return subscription.Namespace + separator + subscription.Name + separator + subject
 }
+
+// SubjectMatches returns true if the subject matches the filter with the NATS wildcards `*` and `>`.
+func SubjectMatches(filter, subject string) bool {
+	filterTokens := strings.Split(filter, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for idx, token := range filterTokens {
+		if token == ">" {
+			return len(subjectTokens) > idx
+		}
+		if idx >= len(subjectTokens) || (token != "*" && token != subjectTokens[idx]) {
+			return false
+		}
+	}
+	return len(filterTokens) == len(subjectTokens)
+}
@@ -0,0 +1,69 @@
+package jetstream
+
+import (
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/cloudevent/validation"
+)
+
+// initValidator creates the validator of the dispatched events, unless their validation is disabled.
+func (js *JetStream) initValidator() error {
+	js.validator = nil
+	if js.Config.CEValidationPolicy == "" {
+		return nil
+	}
+	validator, err := validation.NewValidator(js.Config.CEValidationPolicy, js.Config.CEMaxEventSize)
+	if err != nil {
+		return err
+	}
+	js.validator = validator
+	return nil
+}
+
+// validateEvent checks the event against the CloudEvents specification and returns true if it is dispatched.
+// Since the delivery of an event rejected by the validation policy would fail again, it is moved to the dead-letter
+// stream if it is enabled, and terminated otherwise.
+func (js *JetStream) validateEvent(msg *nats.Msg, ce *cev2event.Event, subscriptionNamespace, subscriptionName,
+	sink string, acknowledge bool, ceLogger *zap.SugaredLogger) bool {
+	if js.validator == nil {
+		return true
+	}
+	violations, err := js.validator.Validate(ce)
+	if err == nil {
+		if len(violations) > 0 {
+			ceLogger.Debugw("Dispatching a CloudEvent violating the CloudEvents specification",
+				"violations", (&validation.Error{Violations: violations}).Error())
+		}
+		return true
+	}
+	if !acknowledge {
+		ceLogger.Warnw("Dropped an invalid CloudEvent", "error", err)
+		return false
+	}
+	if js.isDeadLetterEnabled() {
+		var numDelivered uint64
+		if metadata, metadataErr := msg.Metadata(); metadataErr == nil {
+			numDelivered = metadata.NumDelivered
+		}
+		// the status is 0, since the event is not delivered to the sink
+		dlErr := js.deadLetter(msg, subscriptionNamespace, subscriptionName, sink, 0, numDelivered)
+		if dlErr == nil {
+			ceLogger.Warnw("Moved an invalid CloudEvent to the dead-letter stream",
+				"stream", js.Config.JSDeadLetterStreamName, "error", err)
+			return false
+		}
+		// the event is redelivered to move it to the dead-letter stream again
+		ceLogger.Errorw("Failed to move an invalid CloudEvent to the dead-letter stream", "error", dlErr)
+		if nakErr := msg.NakWithDelay(jsConsumerNakDelay); nakErr != nil {
+			ceLogger.Errorw("Failed to NAK an event on JetStream", "error", nakErr)
+		}
+		return false
+	}
+	if termErr := msg.Term(); termErr != nil {
+		ceLogger.Errorw("Failed to terminate an event on JetStream", "error", termErr)
+	}
+	ceLogger.Warnw("Dropped an invalid CloudEvent", "error", err)
+	return false
+}
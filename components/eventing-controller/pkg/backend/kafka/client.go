@@ -0,0 +1,57 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+// adminClient is the part of the Kafka admin API used by the backend, it is implemented by *kadm.Client.
+type adminClient interface {
+	CreateTopics(ctx context.Context, partitions int32, replicationFactor int16, configs map[string]*string,
+		topics ...string) (kadm.CreateTopicResponses, error)
+	ListGroups(ctx context.Context, filterStates ...string) (kadm.ListedGroups, error)
+	DeleteGroups(ctx context.Context, groups ...string) (kadm.DeleteGroupResponses, error)
+	Close()
+}
+
+// consumerClient is the part of the Kafka consumer API used by the backend, it is implemented by *kgo.Client.
+type consumerClient interface {
+	PollFetches(ctx context.Context) kgo.Fetches
+	CommitRecords(ctx context.Context, rs ...*kgo.Record) error
+	Close()
+}
+
+// Perform a compile-time check.
+var (
+	_ adminClient    = &kadm.Client{}
+	_ consumerClient = &kgo.Client{}
+)
+
+func newAdminClient(config env.KafkaConfig) (adminClient, error) {
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(config.Brokers...),
+		kgo.DialTimeout(config.DialTimeout),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return kadm.NewClient(client), nil
+}
+
+// newConsumerClient returns a member of the given consumer group consuming the given topics. The offsets are only
+// committed after the records are dispatched, so that a restarted member continues with the undelivered records.
+// A new group starts at the end of the topics.
+func newConsumerClient(config env.KafkaConfig, group string, topics []string) (consumerClient, error) {
+	return kgo.NewClient(
+		kgo.SeedBrokers(config.Brokers...),
+		kgo.DialTimeout(config.DialTimeout),
+		kgo.ConsumerGroup(group),
+		kgo.ConsumeTopics(topics...),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtEnd()),
+		kgo.DisableAutoCommit(),
+	)
+}
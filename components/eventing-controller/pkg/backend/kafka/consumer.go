@@ -0,0 +1,237 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	cev2protocol "github.com/cloudevents/sdk-go/v2/protocol"
+	http2 "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/filter"
+	backendjetstream "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	pkgerrors "github.com/kyma-project/kyma/components/eventing-controller/pkg/errors"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/tracing"
+)
+
+const (
+	// initialRetryBackoff is the delay before the first redelivery of a failed event, it doubles with every retry
+	// up to the DispatcherRetryPeriod.
+	initialRetryBackoff = time.Second
+	// pollErrorBackoff is the delay before polling again after fetching failed.
+	pollErrorBackoff = time.Second
+)
+
+func newTransport(config env.KafkaConfig, tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		MaxIdleConns:        config.MaxIdleConns,
+		MaxConnsPerHost:     config.MaxConnsPerHost,
+		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
+		IdleConnTimeout:     config.IdleConnTimeout,
+		TLSClientConfig:     tlsConfig,
+	}
+}
+
+// startConsumer joins the consumer group of the subscription and dispatches the consumed records in the background.
+// The records are dispatched with a dedicated client if the subscription has sink settings.
+func (k *Kafka) startConsumer(subscription *eventingv1alpha2.Subscription, spec consumerSpec,
+	settings *sinkSettings) (*consumer, error) {
+	expression, err := eventingv1alpha2.ParseFilterExpression(spec.filterExpression)
+	if err != nil {
+		return nil, pkgerrors.MakeError(ErrInvalidFilterExpression, err)
+	}
+	var sinkClient cev2.Client
+	var sinkTransport *http.Transport
+	if settings != nil {
+		if sinkClient, sinkTransport, err = k.newSinkClient(subscription, settings); err != nil {
+			return nil, err
+		}
+	}
+	client, err := k.newConsumerClient(k.Config, spec.group, spec.topics)
+	if err != nil {
+		return nil, pkgerrors.MakeError(ErrStartConsumer, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &consumer{spec: spec, expression: expression, client: client, cancel: cancel,
+		sinkClient: sinkClient, sinkTransport: sinkTransport, done: make(chan struct{})}
+	namespace, name := subscription.Namespace, subscription.Name
+	go k.consume(ctx, c, namespace, name)
+	k.namedLogger().Infow("Started the consumer of the subscription", "namespace", namespace, "name", name,
+		"group", spec.group, "topics", spec.topics)
+	return c, nil
+}
+
+// stop cancels the dispatching, waits for the consume loop to return and leaves the consumer group.
+func (c *consumer) stop() {
+	c.cancel()
+	<-c.done
+	c.client.Close()
+	if c.sinkTransport != nil {
+		c.sinkTransport.CloseIdleConnections()
+	}
+}
+
+// consume polls the records of the subscription topics and dispatches them to the sink. The offsets of a poll are
+// only committed once all of its records are dispatched or their retries are exhausted. If the consumer is stopped
+// while dispatching, nothing is committed and the records are redelivered from the last committed offsets.
+func (k *Kafka) consume(ctx context.Context, c *consumer, namespace, name string) {
+	defer close(c.done)
+	dispatchLogger := k.namedLogger().Named(dispatcherName).With("namespace", namespace, "name", name,
+		"group", c.spec.group)
+	for {
+		fetches := c.client.PollFetches(ctx)
+		if ctx.Err() != nil || fetches.IsClientClosed() {
+			return
+		}
+		fetchFailed := false
+		fetches.EachError(func(topic string, partition int32, err error) {
+			fetchFailed = true
+			dispatchLogger.Errorw("Failed to fetch records", "topic", topic, "partition", partition, "error", err)
+		})
+		records := fetches.Records()
+		if len(records) == 0 {
+			if fetchFailed {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(pollErrorBackoff):
+				}
+			}
+			continue
+		}
+		if !k.dispatchRecords(ctx, c, records, namespace, name, dispatchLogger) {
+			return
+		}
+		if err := c.client.CommitRecords(ctx, records...); err != nil {
+			dispatchLogger.Errorw("Failed to commit the offsets of the dispatched records", "error", err)
+		}
+	}
+}
+
+// dispatchRecords dispatches the records with at most maxInFlight concurrent requests to the sink.
+// It returns false if the consumer was stopped before all records were handled.
+func (k *Kafka) dispatchRecords(ctx context.Context, c *consumer, records []*kgo.Record, namespace, name string,
+	dispatchLogger *zap.SugaredLogger) bool {
+	maxInFlight := c.spec.maxInFlight
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+	inFlight := make(chan struct{}, maxInFlight)
+	var (
+		wg          sync.WaitGroup
+		interrupted atomic.Bool
+	)
+	for _, record := range records {
+		select {
+		case <-ctx.Done():
+			interrupted.Store(true)
+		case inFlight <- struct{}{}:
+			wg.Add(1)
+			go func(record *kgo.Record) {
+				defer func() {
+					<-inFlight
+					wg.Done()
+				}()
+				if !k.dispatch(ctx, c, record, namespace, name, dispatchLogger) {
+					interrupted.Store(true)
+				}
+			}(record)
+		}
+		if interrupted.Load() {
+			break
+		}
+	}
+	wg.Wait()
+	return !interrupted.Load()
+}
+
+// dispatch sends the record to the sink and retries failed deliveries with an exponential backoff until the
+// DispatcherMaxRetries are exhausted. It returns false if the consumer was stopped before the record was handled.
+func (k *Kafka) dispatch(ctx context.Context, c *consumer, record *kgo.Record, namespace, name string,
+	dispatchLogger *zap.SugaredLogger) bool {
+	ce, err := ToCloudEvent(record)
+	if err != nil {
+		dispatchLogger.Errorw("Failed to convert Kafka record to CloudEvent, skipping it", "topic", record.Topic,
+			"partition", record.Partition, "offset", record.Offset, "error", err)
+		return true
+	}
+	ceLogger := dispatchLogger.With("id", ce.ID(), "source", ce.Source(), "type", ce.Type(), "sink", c.spec.sink,
+		tracing.CorrelationIDLogKey, tracing.CorrelationID(ce))
+
+	// skip the dispatching if the event does not match the subscription filters
+	if !filter.Matches(c.spec.filters, ce) || !filter.MatchesExpression(c.expression, ce) {
+		ceLogger.Debugw("CloudEvent was filtered out by the subscription filters")
+		return true
+	}
+
+	backoff := min(initialRetryBackoff, k.subsConfig.DispatcherRetryPeriod)
+	for attempt := 0; ; attempt++ {
+		if k.send(ctx, c, ce, namespace, name, ceLogger) {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		if attempt >= k.subsConfig.DispatcherMaxRetries {
+			ceLogger.Errorw("Retries exhausted, dropping the CloudEvent", "attempts", attempt+1)
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > k.subsConfig.DispatcherRetryPeriod {
+			backoff = k.subsConfig.DispatcherRetryPeriod
+		}
+		ceLogger.Debugw("Redelivering the CloudEvent", "attempt", attempt+2)
+	}
+}
+
+// send sends the CloudEvent once to the sink and returns true if the sink acknowledged it.
+func (k *Kafka) send(ctx context.Context, c *consumer, ce *cev2.Event, namespace, name string,
+	ceLogger *zap.SugaredLogger) bool {
+	ctxWithCE := cev2.ContextWithTarget(ctx, c.spec.sink)
+	traceCtxWithCE := tracing.AddTracingHeadersToContext(ctxWithCE, ce)
+	traceID := tracing.TraceIDFromContext(traceCtxWithCE)
+
+	ceLogger.Debugw("Sending the CloudEvent")
+	start := time.Now()
+	client := k.client
+	if c.sinkClient != nil {
+		client = c.sinkClient
+	}
+	result := client.Send(traceCtxWithCE, *ce)
+	duration := time.Since(start)
+
+	var res *http2.Result
+	status := http.StatusOK
+	if !cev2protocol.IsACK(result) {
+		status = http.StatusInternalServerError
+	}
+	if cev2.ResultAs(result, &res) {
+		status = res.StatusCode
+	}
+	k.metricsCollector.RecordDeliveryPerSubscription(namespace, name, ce.Type(), c.spec.sink, status, traceID)
+	k.metricsCollector.RecordLatencyPerSubscription(duration, name, ce.Type(), c.spec.sink, status, traceID)
+	if !cev2protocol.IsACK(result) {
+		if ctx.Err() == nil {
+			k.metricsCollector.RecordSinkDelivery(namespace, name, c.spec.sink, duration, status)
+			k.metricsCollector.RecordDeliveryFailure(namespace, name, c.spec.sink,
+				backendjetstream.DeliveryFailureReason(result))
+			ceLogger.Errorw("Failed to dispatch the CloudEvent", "error", result.Error())
+		}
+		return false
+	}
+	k.metricsCollector.RecordSinkDelivery(namespace, name, c.spec.sink, duration, status)
+	ceLogger.Debugw("CloudEvent was dispatched")
+	return true
+}
@@ -0,0 +1,25 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNoBrokers           = errors.New("no Kafka brokers configured")
+	ErrConnect             = errors.New("failed to connect to Kafka")
+	ErrNotInitialized      = errors.New("the Kafka backend is not initialized")
+	ErrWildcardType        = errors.New("wildcard event types are not supported by the Kafka backend")
+	ErrTopicNameTooLong    = fmt.Errorf("topic name should be max %d characters long", maxTopicNameLength)
+	ErrCreateTopic         = errors.New("failed to create topic")
+	ErrStartConsumer       = errors.New("failed to start consumer")
+	ErrListConsumerGroups  = errors.New("failed to list consumer groups")
+	ErrDeleteConsumerGroup = errors.New("failed to delete consumer group")
+
+	ErrInvalidFilterExpression = errors.New("invalid filter expression")
+
+	ErrCABundleLoaderNotSet = errors.New("failed to load the sink CA bundle, no loader is set")
+	ErrLoadCABundle         = errors.New("failed to load the sink CA bundle")
+	ErrTokenProviderNotSet  = errors.New("failed to authenticate at the sink, no ServiceAccount token provider is set")
+)
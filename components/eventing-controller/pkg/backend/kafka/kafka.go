@@ -0,0 +1,279 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	pkgerrors "github.com/kyma-project/kyma/components/eventing-controller/pkg/errors"
+)
+
+var _ Backend = &Kafka{}
+
+const (
+	kafkaHandlerName    = "kafka-handler"
+	dispatcherName      = "dispatcher"
+	maxTopicNameLength  = 249
+	adminRequestTimeout = 30 * time.Second
+)
+
+func NewKafka(config env.KafkaConfig, metricsCollector *backendmetrics.Collector,
+	cleaner cleaner.Cleaner, subsConfig env.DefaultSubscriptionConfig, log *logger.Logger) *Kafka {
+	return &Kafka{
+		Config:            config,
+		logger:            log,
+		metricsCollector:  metricsCollector,
+		cleaner:           cleaner,
+		subsConfig:        subsConfig,
+		consumers:         make(map[string]*consumer),
+		newAdminClient:    newAdminClient,
+		newConsumerClient: newConsumerClient,
+	}
+}
+
+func (k *Kafka) Initialize() error {
+	if len(k.Config.Brokers) == 0 {
+		return ErrNoBrokers
+	}
+	if k.admin == nil {
+		admin, err := k.newAdminClient(k.Config)
+		if err != nil {
+			return pkgerrors.MakeError(ErrConnect, err)
+		}
+		k.admin = admin
+	}
+	if k.client == nil {
+		client, err := k.newCloudEventClient()
+		if err != nil {
+			return err
+		}
+		k.client = client
+	}
+	return nil
+}
+
+func (k *Kafka) SyncSubscription(subscription *eventingv1alpha2.Subscription) error {
+	if k.admin == nil {
+		return ErrNotInitialized
+	}
+	kafkaTypes, err := k.GetKafkaTypes(subscription)
+	if err != nil {
+		return err
+	}
+	topics := uniqueTopics(kafkaTypes)
+	if err := k.createTopics(topics); err != nil {
+		return err
+	}
+	settings, err := k.loadSinkSettings(subscription)
+	if err != nil {
+		return err
+	}
+
+	spec := consumerSpec{
+		group:            k.ConsumerGroup(subscription),
+		topics:           topics,
+		sink:             subscription.Spec.Sink,
+		filters:          subscription.Spec.Filters,
+		filterExpression: subscription.Spec.FilterExpression,
+		maxInFlight:      subscription.GetMaxInFlightMessages(&k.subsConfig),
+	}
+	if settings != nil {
+		spec.sinkFingerprint = settings.fingerprint
+	}
+	key := createKeyPrefix(subscription)
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if c, ok := k.consumers[key]; ok {
+		if reflect.DeepEqual(c.spec, spec) {
+			return nil
+		}
+		k.namedLogger().Infow("Restarting the consumer of the changed subscription",
+			"namespace", subscription.Namespace, "name", subscription.Name, "group", spec.group)
+		c.stop()
+		delete(k.consumers, key)
+	}
+	c, err := k.startConsumer(subscription, spec, settings)
+	if err != nil {
+		return err
+	}
+	k.consumers[key] = c
+	return nil
+}
+
+func (k *Kafka) DeleteSubscription(subscription *eventingv1alpha2.Subscription) error {
+	key := createKeyPrefix(subscription)
+	k.mutex.Lock()
+	if c, ok := k.consumers[key]; ok {
+		c.stop()
+		delete(k.consumers, key)
+	}
+	k.mutex.Unlock()
+
+	if k.admin == nil {
+		return ErrNotInitialized
+	}
+	return k.deleteConsumerGroup(k.ConsumerGroup(subscription))
+}
+
+// GetKafkaTypes returns the topics of the event types of the subscription, they are named
+// <prefix>.<source>.<event type> after cleaning for the standard type matching and <prefix>.<event type> for the
+// exact type matching.
+func (k *Kafka) GetKafkaTypes(subscription *eventingv1alpha2.Subscription) ([]eventingv1alpha2.KafkaTypes, error) {
+	kafkaTypes := make([]eventingv1alpha2.KafkaTypes, 0, len(subscription.Spec.Types))
+	for _, eventType := range subscription.Spec.Types {
+		topic, err := k.topicName(subscription.Spec.Source, eventType, subscription.Spec.TypeMatching)
+		if err != nil {
+			return nil, err
+		}
+		kafkaTypes = append(kafkaTypes, eventingv1alpha2.KafkaTypes{OriginalType: eventType, Topic: topic})
+	}
+	return kafkaTypes, nil
+}
+
+// ConsumerGroup returns the name of the consumer group of the subscription, named <prefix>.<namespace>.<name>.
+func (k *Kafka) ConsumerGroup(subscription *eventingv1alpha2.Subscription) string {
+	return fmt.Sprintf("%s.%s.%s", k.Config.ConsumerGroupPrefix, subscription.Namespace, subscription.Name)
+}
+
+func (k *Kafka) DeleteInvalidConsumerGroups(subscriptions []eventingv1alpha2.Subscription) error {
+	if k.admin == nil {
+		return ErrNotInitialized
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), adminRequestTimeout)
+	defer cancel()
+	groups, err := k.admin.ListGroups(ctx)
+	if err != nil {
+		return pkgerrors.MakeError(ErrListConsumerGroups, err)
+	}
+
+	used := make(map[string]bool, len(subscriptions))
+	for i := range subscriptions {
+		used[k.ConsumerGroup(&subscriptions[i])] = true
+	}
+	for _, group := range groups.Groups() {
+		if used[group] || !strings.HasPrefix(group, k.Config.ConsumerGroupPrefix+".") {
+			continue
+		}
+		if err := k.deleteConsumerGroup(group); err != nil {
+			if errors.Is(err, kerr.NonEmptyGroup) {
+				k.namedLogger().Warnw("Dangling Kafka consumer group still has members and is not deleted",
+					"group", group)
+				continue
+			}
+			return err
+		}
+		k.namedLogger().Infow("Dangling Kafka consumer group is deleted", "group", group)
+	}
+	return nil
+}
+
+func (k *Kafka) Shutdown() {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	for key, c := range k.consumers {
+		c.stop()
+		delete(k.consumers, key)
+	}
+	if k.admin != nil {
+		k.admin.Close()
+		k.admin = nil
+	}
+}
+
+func (k *Kafka) topicName(source, eventType string, typeMatching eventingv1alpha2.TypeMatching) (string, error) {
+	if eventingv1alpha2.IsWildcardType(eventType) {
+		return "", pkgerrors.MakeError(ErrWildcardType, errors.New(eventType))
+	}
+	cleanType, err := k.cleaner.CleanEventType(eventType)
+	if err != nil {
+		return "", err
+	}
+	topic := fmt.Sprintf("%s.%s", k.Config.TopicPrefix, cleanType)
+	if typeMatching != eventingv1alpha2.TypeMatchingExact {
+		cleanSource, err := k.cleaner.CleanSource(source)
+		if err != nil {
+			return "", err
+		}
+		topic = fmt.Sprintf("%s.%s.%s", k.Config.TopicPrefix, cleanSource, cleanType)
+	}
+	if len(topic) > maxTopicNameLength {
+		return "", pkgerrors.MakeError(ErrTopicNameTooLong, errors.New(topic))
+	}
+	return topic, nil
+}
+
+// createTopics creates the missing topics with the configured partitions and replication factor.
+func (k *Kafka) createTopics(topics []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), adminRequestTimeout)
+	defer cancel()
+	responses, err := k.admin.CreateTopics(ctx, int32(k.Config.TopicPartitions),
+		int16(k.Config.TopicReplicationFactor), nil, topics...)
+	if err != nil {
+		return pkgerrors.MakeError(ErrCreateTopic, err)
+	}
+	for _, response := range responses.Sorted() {
+		if response.Err != nil && !errors.Is(response.Err, kerr.TopicAlreadyExists) {
+			return pkgerrors.MakeError(ErrCreateTopic, fmt.Errorf("%s: %w", response.Topic, response.Err))
+		}
+	}
+	return nil
+}
+
+func (k *Kafka) deleteConsumerGroup(group string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), adminRequestTimeout)
+	defer cancel()
+	responses, err := k.admin.DeleteGroups(ctx, group)
+	if err != nil {
+		return pkgerrors.MakeError(ErrDeleteConsumerGroup, err)
+	}
+	if err := responses.Error(); err != nil && !errors.Is(err, kerr.GroupIDNotFound) {
+		return fmt.Errorf("%w %s: %w", ErrDeleteConsumerGroup, group, err)
+	}
+	return nil
+}
+
+func (k *Kafka) newCloudEventClient() (cev2.Client, error) {
+	transport := newTransport(k.Config, nil)
+	return cev2.NewClientHTTP(cev2.WithRoundTripper(transport))
+}
+
+func (k *Kafka) namedLogger() *zap.SugaredLogger {
+	return k.logger.WithContext().Named(kafkaHandlerName)
+}
+
+func createKeyPrefix(sub *eventingv1alpha2.Subscription) string {
+	namespacedName := types.NamespacedName{
+		Namespace: sub.Namespace,
+		Name:      sub.Name,
+	}
+	return namespacedName.String()
+}
+
+// uniqueTopics returns the sorted topics of the given types without duplicates.
+func uniqueTopics(kafkaTypes []eventingv1alpha2.KafkaTypes) []string {
+	seen := make(map[string]bool, len(kafkaTypes))
+	topics := make([]string, 0, len(kafkaTypes))
+	for _, t := range kafkaTypes {
+		if !seen[t.Topic] {
+			seen[t.Topic] = true
+			topics = append(topics, t.Topic)
+		}
+	}
+	sort.Strings(topics)
+	return topics
+}
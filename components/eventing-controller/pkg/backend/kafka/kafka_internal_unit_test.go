@@ -0,0 +1,531 @@
+//go:build unit
+
+package kafka
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	evtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func Test_GetKafkaTypes(t *testing.T) {
+	k := newTestKafka(t, nil, nil)
+
+	testCases := []struct {
+		name      string
+		givenSub  *eventingv1alpha2.Subscription
+		wantTypes []eventingv1alpha2.KafkaTypes
+		wantError error
+	}{
+		{
+			name: "should prefix the topics with the cleaned source for the standard type matching",
+			givenSub: evtesting.NewSubscription("sub", "ns",
+				evtesting.WithSource("my/app"),
+				evtesting.WithTypes([]string{"order.created.v1", "order$deleted.v1"}),
+			),
+			wantTypes: []eventingv1alpha2.KafkaTypes{
+				{OriginalType: "order.created.v1", Topic: "kyma.myapp.order.created.v1"},
+				{OriginalType: "order$deleted.v1", Topic: "kyma.myapp.orderdeleted.v1"},
+			},
+		},
+		{
+			name: "should not prefix the topics with the source for the exact type matching",
+			givenSub: evtesting.NewSubscription("sub", "ns",
+				evtesting.WithExactTypeMatching(),
+				evtesting.WithSource("my/app"),
+				evtesting.WithTypes([]string{"sap.kyma.custom.order.created.v1"}),
+			),
+			wantTypes: []eventingv1alpha2.KafkaTypes{
+				{OriginalType: "sap.kyma.custom.order.created.v1", Topic: "kyma.sap.kyma.custom.order.created.v1"},
+			},
+		},
+		{
+			name: "should reject wildcard types",
+			givenSub: evtesting.NewSubscription("sub", "ns",
+				evtesting.WithExactTypeMatching(),
+				evtesting.WithTypes([]string{"order.>"}),
+			),
+			wantError: ErrWildcardType,
+		},
+		{
+			name: "should reject topic names exceeding the Kafka limit",
+			givenSub: evtesting.NewSubscription("sub", "ns",
+				evtesting.WithExactTypeMatching(),
+				evtesting.WithTypes([]string{strings.Repeat("a", maxTopicNameLength)}),
+			),
+			wantError: ErrTopicNameTooLong,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			kafkaTypes, err := k.GetKafkaTypes(tc.givenSub)
+
+			// then
+			require.ErrorIs(t, err, tc.wantError)
+			require.Equal(t, tc.wantTypes, kafkaTypes)
+		})
+	}
+}
+
+func Test_SyncSubscription(t *testing.T) {
+	// given a sink failing the first delivery
+	sink := newTestSink(http.StatusServiceUnavailable)
+	defer sink.Close()
+	admin := &fakeAdminClient{existingTopics: map[string]bool{"kyma.myapp.order.created.v1": true}}
+	consumers := &fakeConsumerClients{}
+	k := newTestKafka(t, admin, consumers)
+	require.NoError(t, k.Initialize())
+	defer k.Shutdown()
+
+	sub := evtesting.NewSubscription("sub", "ns",
+		evtesting.WithSource("myapp"),
+		evtesting.WithTypes([]string{"order.created.v1", "order.deleted.v1"}),
+		evtesting.WithSink(sink.URL),
+	)
+	sub.Spec.Filters = []eventingv1alpha2.SubscriptionFilter{{Exact: map[string]string{"tenant": "a"}}}
+
+	// when
+	require.NoError(t, k.SyncSubscription(sub))
+
+	// then the missing topic is created and a member of the consumer group is started
+	require.Equal(t, []string{"kyma.myapp.order.deleted.v1"}, admin.createdTopics)
+	consumer := consumers.last(t)
+	require.Equal(t, "kyma-eventing.ns.sub", consumer.group)
+	require.Equal(t, []string{"kyma.myapp.order.created.v1", "kyma.myapp.order.deleted.v1"}, consumer.topics)
+
+	// when the consumer polls a matching and a filtered event
+	consumer.records <- []*kgo.Record{
+		newTestRecord(t, "kyma.myapp.order.created.v1", 0, "a"),
+		newTestRecord(t, "kyma.myapp.order.created.v1", 1, "b"),
+	}
+
+	// then the matching event is redelivered until the sink accepts it and both offsets are committed
+	require.Eventually(t, func() bool { return len(consumer.committed()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"id-0", "id-0"}, sink.receivedIDs())
+
+	// when the subscription is synced again without changes
+	require.NoError(t, k.SyncSubscription(sub))
+
+	// then the consumer is kept
+	require.Len(t, consumers.clients, 1)
+
+	// when the sink changes
+	sub.Spec.Sink = sink.URL + "/changed"
+	require.NoError(t, k.SyncSubscription(sub))
+
+	// then the consumer is restarted
+	require.Len(t, consumers.clients, 2)
+	require.True(t, consumer.isClosed())
+
+	// when the subscription is deleted
+	require.NoError(t, k.DeleteSubscription(sub))
+
+	// then its consumer is stopped and its consumer group is deleted
+	require.True(t, consumers.last(t).isClosed())
+	require.Equal(t, []string{"kyma-eventing.ns.sub"}, admin.deletedGroups)
+}
+
+func Test_SyncSubscription_FilterExpression(t *testing.T) {
+	// given
+	sink := newTestSink()
+	defer sink.Close()
+	admin := &fakeAdminClient{existingTopics: map[string]bool{"kyma.myapp.order.created.v1": true}}
+	consumers := &fakeConsumerClients{}
+	k := newTestKafka(t, admin, consumers)
+	require.NoError(t, k.Initialize())
+	defer k.Shutdown()
+
+	sub := evtesting.NewSubscription("sub", "ns",
+		evtesting.WithSource("myapp"),
+		evtesting.WithTypes([]string{"order.created.v1"}),
+		evtesting.WithSink(sink.URL),
+		evtesting.WithFilterExpression("tenant IN ('b', 'c')"),
+	)
+
+	// when
+	require.NoError(t, k.SyncSubscription(sub))
+	consumer := consumers.last(t)
+	consumer.records <- []*kgo.Record{
+		newTestRecord(t, "kyma.myapp.order.created.v1", 0, "a"),
+		newTestRecord(t, "kyma.myapp.order.created.v1", 1, "b"),
+	}
+
+	// then only the event matching the expression is dispatched, and both offsets are committed
+	require.Eventually(t, func() bool { return len(consumer.committed()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"id-1"}, sink.receivedIDs())
+
+	// when the expression is invalid
+	sub.Spec.FilterExpression = "tenant IN"
+	err := k.SyncSubscription(sub)
+
+	// then the consumer is stopped and not restarted
+	require.ErrorIs(t, err, ErrInvalidFilterExpression)
+	require.True(t, consumer.isClosed())
+	require.Len(t, consumers.clients, 1)
+}
+
+func Test_Dispatch_RetriesExhausted(t *testing.T) {
+	// given a sink failing all deliveries
+	sink := newTestSink(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	defer sink.Close()
+	consumers := &fakeConsumerClients{}
+	k := newTestKafka(t, &fakeAdminClient{}, consumers)
+	k.subsConfig.DispatcherMaxRetries = 2
+	require.NoError(t, k.Initialize())
+	defer k.Shutdown()
+	sub := evtesting.NewSubscription("sub", "ns",
+		evtesting.WithSource("myapp"),
+		evtesting.WithTypes([]string{"order.created.v1"}),
+		evtesting.WithSink(sink.URL),
+	)
+	require.NoError(t, k.SyncSubscription(sub))
+	consumer := consumers.last(t)
+
+	// when
+	consumer.records <- []*kgo.Record{newTestRecord(t, "kyma.myapp.order.created.v1", 0, "a")}
+
+	// then the event is dropped after the retries and its offset is committed
+	require.Eventually(t, func() bool { return len(consumer.committed()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, sink.receivedIDs(), 3)
+}
+
+func Test_Dispatch_StoppedWhileRetrying(t *testing.T) {
+	// given a sink failing all deliveries and a long retry period
+	sink := newTestSink(http.StatusInternalServerError)
+	defer sink.Close()
+	consumers := &fakeConsumerClients{}
+	k := newTestKafka(t, &fakeAdminClient{}, consumers)
+	k.subsConfig.DispatcherRetryPeriod = time.Hour
+	require.NoError(t, k.Initialize())
+	sub := evtesting.NewSubscription("sub", "ns",
+		evtesting.WithSource("myapp"),
+		evtesting.WithTypes([]string{"order.created.v1"}),
+		evtesting.WithSink(sink.URL),
+	)
+	require.NoError(t, k.SyncSubscription(sub))
+	consumer := consumers.last(t)
+	consumer.records <- []*kgo.Record{newTestRecord(t, "kyma.myapp.order.created.v1", 0, "a")}
+	require.Eventually(t, func() bool { return len(sink.receivedIDs()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// when
+	k.Shutdown()
+
+	// then the offset is not committed, so the event is redelivered to the next member of the group
+	require.True(t, consumer.isClosed())
+	require.Empty(t, consumer.committed())
+}
+
+// Test_Dispatch_SinkSettings tests that the events are dispatched with the sink TLS settings and a token of the
+// ServiceAccount of the subscription.
+func Test_Dispatch_SinkSettings(t *testing.T) {
+	// given a TLS sink with a self-signed certificate
+	authorizations := make(chan string, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations <- r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	consumers := &fakeConsumerClients{}
+	k := newTestKafka(t, &fakeAdminClient{}, consumers)
+	require.NoError(t, k.Initialize())
+	defer k.Shutdown()
+	sub := evtesting.NewSubscription("sub", "ns",
+		evtesting.WithSource("myapp"),
+		evtesting.WithTypes([]string{"order.created.v1"}),
+		evtesting.WithSink(server.URL),
+		evtesting.WithSinkTLS(&eventingv1alpha2.SinkTLSConfig{InsecureSkipVerify: true}),
+		evtesting.WithServiceAccountName("dispatcher"),
+	)
+
+	// when no token provider is set
+	err := k.SyncSubscription(sub)
+
+	// then
+	require.ErrorIs(t, err, ErrTokenProviderNotSet)
+
+	// when
+	k.SetTokenProvider(tokenProviderFunc(func(_ context.Context, namespace, name, audience string) (string, error) {
+		return namespace + "/" + name + "/" + audience, nil
+	}))
+	require.NoError(t, k.SyncSubscription(sub))
+	consumer := consumers.last(t)
+	consumer.records <- []*kgo.Record{newTestRecord(t, "kyma.myapp.order.created.v1", 0, "a")}
+
+	// then
+	require.Equal(t, "Bearer ns/dispatcher/"+server.URL, <-authorizations)
+	require.Eventually(t, func() bool { return len(consumer.committed()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// when the sink settings change
+	sub.Spec.ServiceAccountName = ""
+	require.NoError(t, k.SyncSubscription(sub))
+
+	// then the consumer is restarted with the new settings
+	require.True(t, consumer.isClosed())
+	require.Len(t, consumers.clients, 2)
+}
+
+func Test_DeleteInvalidConsumerGroups(t *testing.T) {
+	// given
+	admin := &fakeAdminClient{groups: []string{"kyma-eventing.ns.valid", "kyma-eventing.ns.dangling", "other.ns.sub"}}
+	k := newTestKafka(t, admin, nil)
+	require.NoError(t, k.Initialize())
+
+	// when
+	err := k.DeleteInvalidConsumerGroups([]eventingv1alpha2.Subscription{*evtesting.NewSubscription("valid", "ns")})
+
+	// then only the dangling group with the prefix is deleted
+	require.NoError(t, err)
+	require.Equal(t, []string{"kyma-eventing.ns.dangling"}, admin.deletedGroups)
+}
+
+func Test_ToCloudEvent(t *testing.T) {
+	// given
+	event := cev2.NewEvent()
+	event.SetID("id")
+	event.SetSource("myapp")
+	event.SetType("order.created.v1")
+	event.SetTime(time.Date(2023, 10, 17, 8, 0, 0, 0, time.UTC))
+	event.SetExtension("tenant", "a")
+	require.NoError(t, event.SetData(cev2.ApplicationJSON, map[string]string{"key": "value"}))
+	structured, err := event.MarshalJSON()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		givenRecord *kgo.Record
+	}{
+		{
+			name:        "should convert a record in the binary content mode",
+			givenRecord: NewRecord("topic", event),
+		},
+		{
+			name: "should convert a record in the structured content mode",
+			givenRecord: &kgo.Record{Topic: "topic", Value: structured, Headers: []kgo.RecordHeader{
+				{Key: contentTypeHeader, Value: []byte("application/cloudevents+json; charset=UTF-8")},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			ce, err := ToCloudEvent(tc.givenRecord)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, event.ID(), ce.ID())
+			require.Equal(t, event.Source(), ce.Source())
+			require.Equal(t, event.Type(), ce.Type())
+			require.Equal(t, event.Time(), ce.Time())
+			require.Equal(t, "a", ce.Extensions()["tenant"])
+			require.Equal(t, cev2.ApplicationJSON, ce.DataContentType())
+			require.JSONEq(t, `{"key":"value"}`, string(ce.Data()))
+		})
+	}
+
+	// when the record has no CloudEvent headers
+	_, err = ToCloudEvent(&kgo.Record{Topic: "topic", Value: []byte("{}")})
+
+	// then
+	require.Error(t, err)
+}
+
+func newTestKafka(t *testing.T, admin adminClient, consumers *fakeConsumerClients) *Kafka {
+	t.Helper()
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+	config := env.KafkaConfig{
+		Brokers:             []string{"localhost:9092"},
+		TopicPrefix:         "kyma",
+		TopicPartitions:     1,
+		ConsumerGroupPrefix: "kyma-eventing",
+		MaxIdleConns:        1,
+		MaxConnsPerHost:     1,
+		MaxIdleConnsPerHost: 1,
+		IdleConnTimeout:     time.Second,
+	}
+	subsConfig := env.DefaultSubscriptionConfig{
+		MaxInFlightMessages:   1,
+		DispatcherRetryPeriod: 10 * time.Millisecond,
+		DispatcherMaxRetries:  10,
+	}
+	k := NewKafka(config, backendmetrics.NewCollector(), cleaner.NewKafkaCleaner(defaultLogger), subsConfig,
+		defaultLogger)
+	k.newAdminClient = func(env.KafkaConfig) (adminClient, error) { return admin, nil }
+	k.newConsumerClient = consumers.new
+	return k
+}
+
+func newTestRecord(t *testing.T, topic string, offset int64, tenant string) *kgo.Record {
+	t.Helper()
+	event := cev2.NewEvent()
+	event.SetID("id-" + string(rune('0'+offset)))
+	event.SetSource("myapp")
+	event.SetType("order.created.v1")
+	event.SetExtension("tenant", tenant)
+	require.NoError(t, event.SetData(cev2.ApplicationJSON, map[string]string{"key": "value"}))
+	record := NewRecord(topic, event)
+	record.Offset = offset
+	return record
+}
+
+// testSink records the IDs of the received events and responds with the given status codes,
+// and with 204 once they are used up.
+type testSink struct {
+	*httptest.Server
+	mutex       sync.Mutex
+	statusCodes []int
+	ids         []string
+}
+
+func newTestSink(statusCodes ...int) *testSink {
+	s := &testSink{statusCodes: statusCodes}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event, err := cev2http.NewEventFromHTTPRequest(r)
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if err == nil {
+			s.ids = append(s.ids, event.ID())
+		}
+		status := http.StatusNoContent
+		if len(s.statusCodes) > 0 {
+			status, s.statusCodes = s.statusCodes[0], s.statusCodes[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	return s
+}
+
+func (s *testSink) receivedIDs() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.ids...)
+}
+
+type tokenProviderFunc func(ctx context.Context, namespace, serviceAccountName, audience string) (string, error)
+
+func (f tokenProviderFunc) Token(ctx context.Context, namespace, serviceAccountName, audience string) (string, error) {
+	return f(ctx, namespace, serviceAccountName, audience)
+}
+
+type fakeAdminClient struct {
+	existingTopics map[string]bool
+	createdTopics  []string
+	groups         []string
+	deletedGroups  []string
+}
+
+func (f *fakeAdminClient) CreateTopics(_ context.Context, _ int32, _ int16, _ map[string]*string,
+	topics ...string) (kadm.CreateTopicResponses, error) {
+	responses := kadm.CreateTopicResponses{}
+	for _, topic := range topics {
+		if f.existingTopics[topic] {
+			responses[topic] = kadm.CreateTopicResponse{Topic: topic, Err: kerr.TopicAlreadyExists}
+			continue
+		}
+		f.createdTopics = append(f.createdTopics, topic)
+		responses[topic] = kadm.CreateTopicResponse{Topic: topic}
+	}
+	return responses, nil
+}
+
+func (f *fakeAdminClient) ListGroups(context.Context, ...string) (kadm.ListedGroups, error) {
+	groups := kadm.ListedGroups{}
+	for _, group := range f.groups {
+		groups[group] = kadm.ListedGroup{Group: group}
+	}
+	return groups, nil
+}
+
+func (f *fakeAdminClient) DeleteGroups(_ context.Context, groups ...string) (kadm.DeleteGroupResponses, error) {
+	responses := kadm.DeleteGroupResponses{}
+	for _, group := range groups {
+		f.deletedGroups = append(f.deletedGroups, group)
+		responses[group] = kadm.DeleteGroupResponse{Group: group}
+	}
+	return responses, nil
+}
+
+func (f *fakeAdminClient) Close() {}
+
+type fakeConsumerClients struct {
+	clients []*fakeConsumerClient
+}
+
+func (f *fakeConsumerClients) new(_ env.KafkaConfig, group string, topics []string) (consumerClient, error) {
+	client := &fakeConsumerClient{group: group, topics: topics, records: make(chan []*kgo.Record, 1)}
+	f.clients = append(f.clients, client)
+	return client, nil
+}
+
+func (f *fakeConsumerClients) last(t *testing.T) *fakeConsumerClient {
+	t.Helper()
+	require.NotEmpty(t, f.clients)
+	return f.clients[len(f.clients)-1]
+}
+
+// fakeConsumerClient returns the records sent to its channel from PollFetches.
+type fakeConsumerClient struct {
+	group   string
+	topics  []string
+	records chan []*kgo.Record
+	mutex   sync.Mutex
+	commits []*kgo.Record
+	closed  bool
+}
+
+func (f *fakeConsumerClient) PollFetches(ctx context.Context) kgo.Fetches {
+	select {
+	case <-ctx.Done():
+		return kgo.Fetches{{Topics: []kgo.FetchTopic{{Partitions: []kgo.FetchPartition{{Err: ctx.Err()}}}}}}
+	case records := <-f.records:
+		return kgo.Fetches{{Topics: []kgo.FetchTopic{{Topic: records[0].Topic,
+			Partitions: []kgo.FetchPartition{{Records: records}}}}}}
+	}
+}
+
+func (f *fakeConsumerClient) CommitRecords(_ context.Context, rs ...*kgo.Record) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.commits = append(f.commits, rs...)
+	return nil
+}
+
+func (f *fakeConsumerClient) Close() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.closed = true
+}
+
+func (f *fakeConsumerClient) committed() []*kgo.Record {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]*kgo.Record{}, f.commits...)
+}
+
+func (f *fakeConsumerClient) isClosed() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.closed
+}
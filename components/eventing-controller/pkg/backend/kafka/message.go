@@ -0,0 +1,102 @@
+package kafka
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// The headers of the CloudEvents Kafka protocol binding.
+const (
+	ceHeaderPrefix           = "ce_"
+	contentTypeHeader        = "content-type"
+	structuredContentTypePfx = "application/cloudevents"
+)
+
+// NewRecord returns a record of the given topic carrying the CloudEvent in the binary content mode
+// of the CloudEvents Kafka protocol binding, with the context attributes as ce_ headers and the data as value.
+// The record key is the event source, so that the events of a source keep their order.
+func NewRecord(topic string, event cev2.Event) *kgo.Record {
+	headers := []kgo.RecordHeader{
+		{Key: ceHeaderPrefix + "specversion", Value: []byte(event.SpecVersion())},
+		{Key: ceHeaderPrefix + "id", Value: []byte(event.ID())},
+		{Key: ceHeaderPrefix + "source", Value: []byte(event.Source())},
+		{Key: ceHeaderPrefix + "type", Value: []byte(event.Type())},
+	}
+	if subject := event.Subject(); subject != "" {
+		headers = append(headers, kgo.RecordHeader{Key: ceHeaderPrefix + "subject", Value: []byte(subject)})
+	}
+	if !event.Time().IsZero() {
+		headers = append(headers, kgo.RecordHeader{Key: ceHeaderPrefix + "time",
+			Value: []byte(event.Time().UTC().Format(time.RFC3339Nano))})
+	}
+	if schema := event.DataSchema(); schema != "" {
+		headers = append(headers, kgo.RecordHeader{Key: ceHeaderPrefix + "dataschema", Value: []byte(schema)})
+	}
+	for name, value := range event.Extensions() {
+		headers = append(headers, kgo.RecordHeader{Key: ceHeaderPrefix + name, Value: []byte(fmt.Sprintf("%v", value))})
+	}
+	if contentType := event.DataContentType(); contentType != "" {
+		headers = append(headers, kgo.RecordHeader{Key: contentTypeHeader, Value: []byte(contentType)})
+	}
+	return &kgo.Record{Topic: topic, Key: []byte(event.Source()), Value: event.Data(), Headers: headers}
+}
+
+// ToCloudEvent converts a record in either the binary or the structured content mode
+// of the CloudEvents Kafka protocol binding to a CloudEvent.
+func ToCloudEvent(record *kgo.Record) (*cev2.Event, error) {
+	headers := make(map[string]string, len(record.Headers))
+	for _, h := range record.Headers {
+		headers[strings.ToLower(h.Key)] = string(h.Value)
+	}
+
+	event := cev2.NewEvent()
+	if strings.HasPrefix(headers[contentTypeHeader], structuredContentTypePfx) {
+		if err := event.UnmarshalJSON(record.Value); err != nil {
+			return nil, err
+		}
+		return &event, nil
+	}
+
+	for key, value := range headers {
+		name, ok := strings.CutPrefix(key, ceHeaderPrefix)
+		if !ok {
+			continue
+		}
+		switch name {
+		case "specversion":
+			event.SetSpecVersion(value)
+		case "id":
+			event.SetID(value)
+		case "source":
+			event.SetSource(value)
+		case "type":
+			event.SetType(value)
+		case "subject":
+			event.SetSubject(value)
+		case "dataschema":
+			event.SetDataSchema(value)
+		case "time":
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %stime header: %w", ceHeaderPrefix, err)
+			}
+			event.SetTime(t)
+		default:
+			event.SetExtension(name, value)
+		}
+	}
+	if contentType, ok := headers[contentTypeHeader]; ok {
+		event.SetDataContentType(contentType)
+	}
+	if len(record.Value) > 0 {
+		event.DataEncoded = record.Value
+	}
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
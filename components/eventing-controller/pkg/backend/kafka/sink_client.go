@@ -0,0 +1,86 @@
+package kafka
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
+	pkgerrors "github.com/kyma-project/kyma/components/eventing-controller/pkg/errors"
+)
+
+// sinkSettings are the TLS settings and the ServiceAccount a subscription uses to reach its sink.
+type sinkSettings struct {
+	tlsConfig          *tls.Config
+	serviceAccountName string
+	// fingerprint identifies the settings, it is part of the consumer spec to restart the consumer on changes.
+	fingerprint string
+}
+
+// SetCABundleLoader sets the loader for the CA bundles referenced by the sink TLS settings of the subscriptions.
+func (k *Kafka) SetCABundleLoader(loader sink.CABundleLoader) {
+	k.caBundleLoader = loader
+}
+
+// SetTokenProvider sets the provider for the ServiceAccount tokens sent to the sinks of the subscriptions.
+func (k *Kafka) SetTokenProvider(provider sink.TokenProvider) {
+	k.tokenProvider = provider
+}
+
+// loadSinkSettings returns the sink settings of the subscription, or nil if it has no custom sink TLS settings
+// and no ServiceAccount, so that it uses the shared client.
+func (k *Kafka) loadSinkSettings(subscription *eventingv1alpha2.Subscription) (*sinkSettings, error) {
+	sinkTLS := subscription.Spec.SinkTLS
+	hasTLS := sinkTLS != nil && (sinkTLS.CABundleRef != nil || sinkTLS.InsecureSkipVerify)
+	serviceAccountName := subscription.Spec.ServiceAccountName
+	if !hasTLS && serviceAccountName == "" {
+		return nil, nil
+	}
+	if serviceAccountName != "" && k.tokenProvider == nil {
+		return nil, ErrTokenProviderNotSet
+	}
+
+	var caBundle []byte
+	if hasTLS && sinkTLS.CABundleRef != nil {
+		if k.caBundleLoader == nil {
+			return nil, ErrCABundleLoaderNotSet
+		}
+		var err error
+		if caBundle, err = k.caBundleLoader.Load(context.Background(), subscription); err != nil {
+			return nil, pkgerrors.MakeError(ErrLoadCABundle, err)
+		}
+	}
+	settings := &sinkSettings{
+		serviceAccountName: serviceAccountName,
+		fingerprint: fmt.Sprintf("%t/%x/%s", hasTLS && sinkTLS.InsecureSkipVerify, sha256.Sum256(caBundle),
+			serviceAccountName),
+	}
+	if hasTLS {
+		var err error
+		if settings.tlsConfig, err = sink.NewTLSConfig(sinkTLS, caBundle); err != nil {
+			return nil, pkgerrors.MakeError(ErrLoadCABundle, err)
+		}
+	}
+	return settings, nil
+}
+
+// newSinkClient creates a CloudEvents client dedicated to the sink of the subscription with the given settings.
+func (k *Kafka) newSinkClient(subscription *eventingv1alpha2.Subscription,
+	settings *sinkSettings) (cev2.Client, *http.Transport, error) {
+	transport := newTransport(k.Config, settings.tlsConfig)
+	var roundTripper http.RoundTripper = transport
+	if settings.serviceAccountName != "" {
+		roundTripper = sink.NewTokenRoundTripper(transport, k.tokenProvider,
+			subscription.Namespace, settings.serviceAccountName, subscription.Spec.Sink)
+	}
+	client, err := cev2.NewClientHTTP(cev2.WithRoundTripper(roundTripper))
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, transport, nil
+}
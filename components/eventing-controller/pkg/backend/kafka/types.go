@@ -0,0 +1,99 @@
+package kafka
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	cesql "github.com/cloudevents/sdk-go/sql/v2"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+type Backend interface {
+	// Initialize connects to the Kafka brokers.
+	Initialize() error
+
+	// SyncSubscription creates the topics of the Kyma eventing subscription and (re)starts its consumer group
+	// member dispatching the events to the sink.
+	SyncSubscription(subscription *eventingv1alpha2.Subscription) error
+
+	// DeleteSubscription stops the consumer of the subscription and deletes its consumer group.
+	// The topics are kept, as they can be shared with other subscriptions and producers.
+	DeleteSubscription(subscription *eventingv1alpha2.Subscription) error
+
+	// GetKafkaTypes returns the topics of the event types of the subscription.
+	GetKafkaTypes(subscription *eventingv1alpha2.Subscription) ([]eventingv1alpha2.KafkaTypes, error)
+
+	// ConsumerGroup returns the name of the consumer group of the subscription.
+	ConsumerGroup(subscription *eventingv1alpha2.Subscription) string
+
+	// DeleteInvalidConsumerGroups deletes the consumer groups with the configured prefix
+	// which do not belong to any of the given subscriptions.
+	DeleteInvalidConsumerGroups(subscriptions []eventingv1alpha2.Subscription) error
+
+	// Shutdown stops all consumers and closes the connections to the brokers.
+	Shutdown()
+
+	// GetConfig returns the backends Configuration
+	GetConfig() env.KafkaConfig
+}
+
+type Kafka struct {
+	Config           env.KafkaConfig
+	admin            adminClient
+	client           cev2.Client
+	logger           *logger.Logger
+	metricsCollector *backendmetrics.Collector
+	cleaner          cleaner.Cleaner
+	subsConfig       env.DefaultSubscriptionConfig
+	// caBundleLoader loads the CA bundles referenced by the sink TLS settings of the subscriptions.
+	caBundleLoader sink.CABundleLoader
+	// tokenProvider provides the ServiceAccount tokens sent to the sinks of the subscriptions.
+	tokenProvider sink.TokenProvider
+	// consumers stores the running consumer of each subscription by its namespaced name.
+	consumers map[string]*consumer
+	mutex     sync.Mutex
+	// newAdminClient and newConsumerClient connect to the brokers, they are replaced by fakes in tests.
+	newAdminClient    func(config env.KafkaConfig) (adminClient, error)
+	newConsumerClient func(config env.KafkaConfig, group string, topics []string) (consumerClient, error)
+}
+
+func (k *Kafka) GetConfig() env.KafkaConfig {
+	return k.Config
+}
+
+// consumerSpec is the part of a subscription a running consumer depends on,
+// the consumer is restarted if it changes.
+type consumerSpec struct {
+	group   string
+	topics  []string
+	sink    string
+	filters []eventingv1alpha2.SubscriptionFilter
+	// filterExpression is kept unparsed, so that the specs can be compared.
+	filterExpression string
+	maxInFlight      int
+	// sinkFingerprint identifies the sink TLS settings and ServiceAccount, it is empty for the shared client.
+	sinkFingerprint string
+}
+
+// consumer is a member of the consumer group of a subscription.
+type consumer struct {
+	spec   consumerSpec
+	client consumerClient
+	cancel context.CancelFunc
+	// expression is the parsed filterExpression of the spec.
+	expression cesql.Expression
+	// sinkClient is the client dedicated to the sink of the subscription, or nil to use the shared client.
+	sinkClient cev2.Client
+	// sinkTransport is the transport of the sinkClient, its idle connections are closed when the consumer stops.
+	sinkTransport *http.Transport
+	// done is closed once the consume loop returned.
+	done chan struct{}
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// OverflowLabelValue is the value of the sink and event type labels of the delivery metrics
+// aggregating the deliveries beyond the cardinality limit.
+const OverflowLabelValue = "other"
+
+// sinkEventType is a distinct combination of the sink and event type labels of the delivery metrics.
+type sinkEventType struct {
+	sink, eventType string
+}
+
+// subscriptionRef identifies a subscription which records deliveries.
+type subscriptionRef struct {
+	namespace, name string
+}
+
+// cardinalityGuard caps the number of distinct sink and event type combinations of the delivery metrics.
+// A combination keeps its slot as long as a subscription which recorded deliveries with it exists.
+type cardinalityGuard struct {
+	limit  int
+	logger *zap.SugaredLogger
+
+	mu sync.Mutex
+	// admitted stores the subscriptions which recorded deliveries with each admitted combination.
+	admitted map[sinkEventType]map[subscriptionRef]struct{}
+	// sinks stores the number of admitted combinations of each sink.
+	sinks  map[string]int
+	warned bool
+}
+
+func newCardinalityGuard(limit int, logger *zap.SugaredLogger) *cardinalityGuard {
+	return &cardinalityGuard{
+		limit:    limit,
+		logger:   logger,
+		admitted: map[sinkEventType]map[subscriptionRef]struct{}{},
+		sinks:    map[string]int{},
+	}
+}
+
+// admit returns the sink and event type label values to record a delivery of the given subscription with.
+// Combinations beyond the limit are aggregated into the OverflowLabelValue.
+func (g *cardinalityGuard) admit(namespace, name, sink, eventType string) (string, string) {
+	if g == nil {
+		return sink, eventType
+	}
+	key := sinkEventType{sink: sink, eventType: eventType}
+	ref := subscriptionRef{namespace: namespace, name: name}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if subscriptions, ok := g.admitted[key]; ok {
+		subscriptions[ref] = struct{}{}
+		return sink, eventType
+	}
+	if len(g.admitted) < g.limit {
+		g.admitted[key] = map[subscriptionRef]struct{}{ref: {}}
+		g.sinks[sink]++
+		return sink, eventType
+	}
+	if !g.warned {
+		g.warned = true
+		if g.logger != nil {
+			g.logger.Warnw("Delivery metrics reached the cardinality limit, aggregating further sinks and event types",
+				"limit", g.limit, "label", OverflowLabelValue)
+		}
+	}
+	return OverflowLabelValue, OverflowLabelValue
+}
+
+// labels returns the sink and event type label values to record a delivery with, without admitting
+// the combination. Combinations which are not admitted are aggregated into the OverflowLabelValue.
+func (g *cardinalityGuard) labels(sink, eventType string) (string, string) {
+	if g == nil {
+		return sink, eventType
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.admitted[sinkEventType{sink: sink, eventType: eventType}]; ok {
+		return sink, eventType
+	}
+	return OverflowLabelValue, OverflowLabelValue
+}
+
+// release removes the given subscription from the admitted combinations, and frees the slots of the combinations
+// which are not used by any other subscription. It returns the combinations the subscription recorded deliveries with.
+func (g *cardinalityGuard) release(namespace, name string) []sinkEventType {
+	if g == nil {
+		return nil
+	}
+	ref := subscriptionRef{namespace: namespace, name: name}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var released []sinkEventType
+	for key, subscriptions := range g.admitted {
+		if _, ok := subscriptions[ref]; !ok {
+			continue
+		}
+		released = append(released, key)
+		delete(subscriptions, ref)
+		if len(subscriptions) > 0 {
+			continue
+		}
+		delete(g.admitted, key)
+		if g.sinks[key.sink]--; g.sinks[key.sink] == 0 {
+			delete(g.sinks, key.sink)
+		}
+		// warn again when the limit is reached with the freed slot
+		g.warned = false
+	}
+	return released
+}
+
+// sink returns the sink label value to record a delivery with.
+// Sinks without any combination within the limit are aggregated into the OverflowLabelValue.
+func (g *cardinalityGuard) sink(sink string) string {
+	if g == nil {
+		return sink
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.sinks[sink]; ok {
+		return sink
+	}
+	return OverflowLabelValue
+}
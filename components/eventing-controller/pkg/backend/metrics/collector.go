@@ -2,9 +2,14 @@ package metrics
 
 import (
 	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
@@ -25,16 +30,62 @@ const (
 	// deliveryMetricHelp help text for the delivery per subscription metric.
 	deliveryMetricHelp = "The total number of dispatched events per subscription"
 
+	// deliveryFailuresMetricKey name of the delivery failures per subscription metric.
+	deliveryFailuresMetricKey = "eventing_ec_nats_delivery_failures_total"
+	// deliveryFailuresMetricHelp help text for the delivery failures per subscription metric.
+	deliveryFailuresMetricHelp = "The total number of failed event deliveries per subscription by failure reason"
+
+	// deliveryPerNamespaceMetricKey name of the delivery per namespace metric.
+	deliveryPerNamespaceMetricKey = "eventing_ec_nats_delivery_per_namespace_total"
+	// deliveryPerNamespaceMetricHelp help text for the delivery per namespace metric.
+	deliveryPerNamespaceMetricHelp = "The total number of dispatched events per subscription namespace"
+
+	// deliveryFailuresPerNamespaceMetricKey name of the delivery failures per namespace metric.
+	deliveryFailuresPerNamespaceMetricKey = "eventing_ec_nats_delivery_failures_per_namespace_total"
+	// deliveryFailuresPerNamespaceMetricHelp help text for the delivery failures per namespace metric.
+	deliveryFailuresPerNamespaceMetricHelp = "The total number of failed event deliveries per namespace by failure reason"
+
 	// eventTypeSubscribedMetricKey name of the eventType subscribed metric.
 	eventTypeSubscribedMetricKey = "eventing_ec_event_type_subscribed_total"
 	// eventTypeSubscribedMetricHelp help text for the eventType subscribed metric.
 	eventTypeSubscribedMetricHelp = "The total number of eventTypes subscribed using the Subscription CRD"
 
+	// natsConnectionStatusMetricKey name of the NATS connection status metric.
+	natsConnectionStatusMetricKey = "eventing_ec_nats_connection_status"
+	// natsConnectionStatusMetricHelp help text for the NATS connection status metric.
+	natsConnectionStatusMetricHelp = "The status of the NATS connection of the JetStream backend. `1` indicates connected"
+
+	// natsReconnectsMetricKey name of the NATS reconnects metric.
+	natsReconnectsMetricKey = "eventing_ec_nats_reconnects_total"
+	// natsReconnectsMetricHelp help text for the NATS reconnects metric.
+	natsReconnectsMetricHelp = "The total number of reconnects of the NATS connection of the JetStream backend"
+
+	// natsLastDisconnectMetricKey name of the NATS last disconnect reason metric.
+	natsLastDisconnectMetricKey = "eventing_ec_nats_last_disconnect_reason"
+	//nolint:lll // help text for metrics
+	// natsLastDisconnectMetricHelp help text for the NATS last disconnect reason metric.
+	natsLastDisconnectMetricHelp = "The reason of the last disconnect of the NATS connection of the JetStream backend. `1` indicates the last reason"
+
 	// subscriptionStatus name of the subscription status metric.
 	subscriptionStatusMetricKey = "eventing_ec_subscription_status"
 	// subscriptionStatusMetricHelp help text for the subscription status metric.
 	subscriptionStatusMetricHelp = "The status of a subscription. `1` indicates the subscription is marked as ready"
 
+	// reconcilePhaseDurationMetricKey name of the reconcile phase duration metric.
+	reconcilePhaseDurationMetricKey = "eventing_ec_subscription_reconcile_phase_duration_seconds"
+	// reconcilePhaseDurationMetricHelp help text for the reconcile phase duration metric.
+	reconcilePhaseDurationMetricHelp = "The duration of a phase of the subscription reconciliation"
+
+	// subscriptionsByReadyMetricKey name of the subscriptions by ready state metric.
+	subscriptionsByReadyMetricKey = "eventing_ec_subscriptions"
+	// subscriptionsByReadyMetricHelp help text for the subscriptions by ready state metric.
+	subscriptionsByReadyMetricHelp = "The number of reconciled subscriptions by ready state"
+
+	// featureFlagMetricKey name of the feature flag info metric.
+	featureFlagMetricKey = "eventing_ec_feature_flag_info"
+	// featureFlagMetricHelp help text for the feature flag info metric.
+	featureFlagMetricHelp = "The state of an experimental feature flag. `1` indicates the current state"
+
 	subscriptionNameLabel      = "subscription_name"
 	eventTypeLabel             = "event_type"
 	sinkLabel                  = "sink"
@@ -43,15 +94,84 @@ const (
 	consumerNameLabel          = "consumer_name"
 	backendTypeLabel           = "eventing_backend"
 	streamNameLabel            = "stream_name"
+	phaseLabel                 = "phase"
+	readyLabel                 = "ready"
+	reasonLabel                = "reason"
+	flagLabel                  = "flag"
+	enabledLabel               = "enabled"
+
+	// traceIDExemplarLabel is the exemplar label linking the delivery metrics to the trace of an example event.
+	traceIDExemplarLabel = "trace_id"
+
+	// OpenMetricsEndpoint is the path of the metrics endpoint serving the OpenMetrics format, which includes exemplars.
+	OpenMetricsEndpoint = "/openmetrics"
+
+	// PhaseStatusUpdate is the reconcile phase updating the subscription status in Kubernetes.
+	PhaseStatusUpdate = "status_update"
+	// PhaseBackendSync is the reconcile phase synchronizing the subscription with the backend.
+	PhaseBackendSync = "backend_sync"
+	// PhaseEventMeshAPI is the part of the backend sync phase calling the EventMesh API.
+	PhaseEventMeshAPI = "eventmesh_api"
+
+	// FailureReasonTimeout is the delivery failure reason of a sink not responding in time.
+	FailureReasonTimeout = "timeout"
+	// FailureReasonClientError is the delivery failure reason of a sink responding with a 4xx status code.
+	FailureReasonClientError = "client_error"
+	// FailureReasonServerError is the delivery failure reason of a sink responding with a 5xx status code.
+	FailureReasonServerError = "server_error"
+	// FailureReasonConnectionRefused is the delivery failure reason of a sink refusing the connection.
+	FailureReasonConnectionRefused = "connection_refused"
+	// FailureReasonTLS is the delivery failure reason of a failed TLS handshake with a sink.
+	FailureReasonTLS = "tls"
+	// FailureReasonDNS is the delivery failure reason of a sink host name which cannot be resolved.
+	FailureReasonDNS = "dns"
+	// FailureReasonOther is the delivery failure reason of any other failure.
+	FailureReasonOther = "other"
+
+	// DisconnectReasonClosed is the disconnect reason of a NATS connection closed by the client.
+	DisconnectReasonClosed = "closed"
+	// DisconnectReasonConnectionReset is the disconnect reason of a NATS connection reset or closed by the server.
+	DisconnectReasonConnectionReset = "connection_reset"
+	// DisconnectReasonStaleConnection is the disconnect reason of a NATS connection not answering the pings.
+	DisconnectReasonStaleConnection = "stale_connection"
+	// DisconnectReasonAuthorization is the disconnect reason of a NATS connection whose authorization failed.
+	DisconnectReasonAuthorization = "authorization"
+	// DisconnectReasonTimeout is the disconnect reason of a NATS connection timing out.
+	DisconnectReasonTimeout = "timeout"
+	// DisconnectReasonOther is the disconnect reason of any other failure.
+	DisconnectReasonOther = "other"
 )
 
+// subscriptionKey identifies a subscription reconciled by a backend.
+type subscriptionKey struct {
+	backendType, namespace, name string
+}
+
 // Collector implements the prometheus.Collector interface.
 type Collector struct {
 	deliveryPerSubscription *prometheus.CounterVec
+	deliveryFailures        *prometheus.CounterVec
 	eventTypes              *prometheus.CounterVec
 	latencyPerSubscriber    *prometheus.HistogramVec
 	health                  *prometheus.GaugeVec
 	subscriptionStatus      *prometheus.GaugeVec
+	reconcilePhaseDuration  *prometheus.HistogramVec
+	subscriptionsByReady    *prometheus.GaugeVec
+	natsConnectionStatus    *prometheus.GaugeVec
+	natsReconnects          *prometheus.CounterVec
+	natsLastDisconnect      *prometheus.GaugeVec
+	featureFlags            *prometheus.GaugeVec
+	// deliveryPerNamespace and deliveryFailuresPerNamespace aggregate the deliveries of all subscriptions
+	// of a namespace, for dashboards on clusters with too many subscriptions to query the per subscription series.
+	deliveryPerNamespace         *prometheus.CounterVec
+	deliveryFailuresPerNamespace *prometheus.CounterVec
+	// readyStates stores the last recorded ready state of each subscription to maintain subscriptionsByReady.
+	readyStates   map[subscriptionKey]bool
+	readyStatesMu sync.Mutex
+	// cardinality caps the distinct sink and event type labels of the delivery metrics, unlimited if nil.
+	cardinality *cardinalityGuard
+	// sinkHealth tracks the deliveries per sink host, disabled if nil.
+	sinkHealth *sinkHealthTracker
 }
 
 // NewCollector a new instance of Collector.
@@ -64,6 +184,27 @@ func NewCollector() *Collector {
 			},
 			[]string{subscriptionNameLabel, eventTypeLabel, sinkLabel, responseCodeLabel},
 		),
+		deliveryFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: deliveryFailuresMetricKey,
+				Help: deliveryFailuresMetricHelp,
+			},
+			[]string{subscriptionNameLabel, sinkLabel, reasonLabel},
+		),
+		deliveryPerNamespace: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: deliveryPerNamespaceMetricKey,
+				Help: deliveryPerNamespaceMetricHelp,
+			},
+			[]string{subscriptionNamespaceLabel, responseCodeLabel},
+		),
+		deliveryFailuresPerNamespace: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: deliveryFailuresPerNamespaceMetricKey,
+				Help: deliveryFailuresPerNamespaceMetricHelp,
+			},
+			[]string{subscriptionNamespaceLabel, reasonLabel},
+		),
 		latencyPerSubscriber: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
 				Name:    latencyMetricKey,
@@ -93,58 +234,209 @@ func NewCollector() *Collector {
 			},
 			[]string{subscriptionNameLabel, subscriptionNamespaceLabel, consumerNameLabel, backendTypeLabel, streamNameLabel},
 		),
+		reconcilePhaseDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    reconcilePhaseDurationMetricKey,
+				Help:    reconcilePhaseDurationMetricHelp,
+				Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+			},
+			[]string{backendTypeLabel, phaseLabel},
+		),
+		subscriptionsByReady: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: subscriptionsByReadyMetricKey,
+				Help: subscriptionsByReadyMetricHelp,
+			},
+			[]string{backendTypeLabel, readyLabel},
+		),
+		natsConnectionStatus: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: natsConnectionStatusMetricKey,
+				Help: natsConnectionStatusMetricHelp,
+			},
+			nil,
+		),
+		natsReconnects: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: natsReconnectsMetricKey,
+				Help: natsReconnectsMetricHelp,
+			},
+			nil,
+		),
+		natsLastDisconnect: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: natsLastDisconnectMetricKey,
+				Help: natsLastDisconnectMetricHelp,
+			},
+			[]string{reasonLabel},
+		),
+		featureFlags: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: featureFlagMetricKey,
+				Help: featureFlagMetricHelp,
+			},
+			[]string{flagLabel, enabledLabel},
+		),
+		readyStates: map[subscriptionKey]bool{},
 	}
 }
 
 // Describe implements the prometheus.Collector interface Describe method.
 func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	c.deliveryPerSubscription.Describe(ch)
+	c.deliveryFailures.Describe(ch)
+	c.deliveryPerNamespace.Describe(ch)
+	c.deliveryFailuresPerNamespace.Describe(ch)
 	c.eventTypes.Describe(ch)
 	c.latencyPerSubscriber.Describe(ch)
 	c.health.Describe(ch)
 	c.subscriptionStatus.Describe(ch)
+	c.reconcilePhaseDuration.Describe(ch)
+	c.subscriptionsByReady.Describe(ch)
+	c.natsConnectionStatus.Describe(ch)
+	c.natsReconnects.Describe(ch)
+	c.natsLastDisconnect.Describe(ch)
+	c.featureFlags.Describe(ch)
+	c.sinkHealth.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface Collect method.
 func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	c.deliveryPerSubscription.Collect(ch)
+	c.deliveryFailures.Collect(ch)
+	c.deliveryPerNamespace.Collect(ch)
+	c.deliveryFailuresPerNamespace.Collect(ch)
 	c.eventTypes.Collect(ch)
 	c.latencyPerSubscriber.Collect(ch)
 	c.health.Collect(ch)
 	c.subscriptionStatus.Collect(ch)
+	c.reconcilePhaseDuration.Collect(ch)
+	c.subscriptionsByReady.Collect(ch)
+	c.natsConnectionStatus.Collect(ch)
+	c.natsReconnects.Collect(ch)
+	c.natsLastDisconnect.Collect(ch)
+	c.featureFlags.Collect(ch)
+	c.sinkHealth.Collect(ch)
 }
 
 // RegisterMetrics registers the metrics.
 func (c *Collector) RegisterMetrics() {
 	metrics.Registry.MustRegister(c.deliveryPerSubscription)
+	metrics.Registry.MustRegister(c.deliveryFailures)
+	metrics.Registry.MustRegister(c.deliveryPerNamespace)
+	metrics.Registry.MustRegister(c.deliveryFailuresPerNamespace)
 	metrics.Registry.MustRegister(c.eventTypes)
 	metrics.Registry.MustRegister(c.latencyPerSubscriber)
 	metrics.Registry.MustRegister(c.health)
 	metrics.Registry.MustRegister(c.subscriptionStatus)
+	metrics.Registry.MustRegister(c.reconcilePhaseDuration)
+	metrics.Registry.MustRegister(c.subscriptionsByReady)
+	metrics.Registry.MustRegister(c.natsConnectionStatus)
+	metrics.Registry.MustRegister(c.natsReconnects)
+	metrics.Registry.MustRegister(c.natsLastDisconnect)
+	metrics.Registry.MustRegister(c.featureFlags)
+	if c.sinkHealth != nil {
+		metrics.Registry.MustRegister(c.sinkHealth)
+	}
 
 	// set health metric to 1. With future updates this can be tied to other health indicators.
 	c.health.WithLabelValues().Set(1)
 }
 
-// RecordDeliveryPerSubscription records a eventing_ec_nats_delivery_per_subscription_total metric.
-func (c *Collector) RecordDeliveryPerSubscription(subscriptionName, eventType, sink string, statusCode int) {
-	c.deliveryPerSubscription.WithLabelValues(
+// SetCardinalityLimit caps the number of distinct sink and event type combinations of the delivery metrics
+// to the given limit. The deliveries beyond the limit are recorded with the OverflowLabelValue,
+// and a warning is logged once the limit is reached. A limit of zero disables the cap.
+// It must be called before any delivery is recorded.
+func (c *Collector) SetCardinalityLimit(limit int, logger *zap.SugaredLogger) {
+	if limit <= 0 {
+		c.cardinality = nil
+		return
+	}
+	c.cardinality = newCardinalityGuard(limit, logger)
+}
+
+// SetSinkHealthPolicy enables tracking the health of the sink hosts with the given policy,
+// or disables it if the window of the policy is not positive.
+// It must be called before the metrics are registered and any delivery is recorded.
+func (c *Collector) SetSinkHealthPolicy(policy SinkHealthPolicy) {
+	if policy.Window <= 0 {
+		c.sinkHealth = nil
+		return
+	}
+	c.sinkHealth = newSinkHealthTracker(policy)
+}
+
+// RecordSinkDelivery counts a delivery of the given subscription towards the health of the host of the given sink.
+func (c *Collector) RecordSinkDelivery(subscriptionNamespace, subscriptionName, sink string,
+	duration time.Duration, statusCode int) {
+	c.sinkHealth.record(subscriptionNamespace, subscriptionName, sink, duration, !isDeliveryFailure(statusCode))
+}
+
+// SinkHealth returns the health of the host of the given sink, and false if the sink health is not tracked
+// or there was no delivery to the host within the rolling window.
+func (c *Collector) SinkHealth(sink string) (SinkHealth, bool) {
+	return c.sinkHealth.health(SinkHost(sink))
+}
+
+// RecordDeliveryPerSubscription records a eventing_ec_nats_delivery_per_subscription_total metric,
+// and aggregates it into the eventing_ec_nats_delivery_per_namespace_total metric.
+// Failed deliveries of traced events record the given trace ID as exemplar, if it is not empty.
+func (c *Collector) RecordDeliveryPerSubscription(subscriptionNamespace, subscriptionName, eventType, sink string,
+	statusCode int, traceID string) {
+	c.deliveryPerNamespace.WithLabelValues(subscriptionNamespace, fmt.Sprintf("%v", statusCode)).Inc()
+	sink, eventType = c.cardinality.admit(subscriptionNamespace, subscriptionName, sink, eventType)
+	counter := c.deliveryPerSubscription.WithLabelValues(
 		subscriptionName,
 		eventType,
 		fmt.Sprintf("%v", sink),
-		fmt.Sprintf("%v", statusCode)).Inc()
+		fmt.Sprintf("%v", statusCode))
+	if adder, ok := counter.(prometheus.ExemplarAdder); ok && traceID != "" && isDeliveryFailure(statusCode) {
+		adder.AddWithExemplar(1, prometheus.Labels{traceIDExemplarLabel: traceID})
+		return
+	}
+	counter.Inc()
+}
+
+// RecordDeliveryFailure records a eventing_ec_nats_delivery_failures_total metric,
+// and aggregates it into the eventing_ec_nats_delivery_failures_per_namespace_total metric.
+// The given reason is expected to be one of the FailureReason constants to keep the label bounded.
+func (c *Collector) RecordDeliveryFailure(subscriptionNamespace, subscriptionName, sink, reason string) {
+	c.deliveryFailuresPerNamespace.WithLabelValues(subscriptionNamespace, reason).Inc()
+	c.deliveryFailures.WithLabelValues(subscriptionName, c.cardinality.sink(sink), reason).Inc()
 }
 
 // RecordLatencyPerSubscription records a eventing_ec_nats_subscriber_dispatch_duration_seconds.
+// It is expected to be recorded after the delivery, which admits the sink and event type labels.
+// Traced events record the given trace ID as exemplar, if it is not empty.
 func (c *Collector) RecordLatencyPerSubscription(
 	duration time.Duration,
 	subscriptionName, eventType, sink string,
-	statusCode int) {
-	c.latencyPerSubscriber.WithLabelValues(
+	statusCode int, traceID string) {
+	sink, eventType = c.cardinality.labels(sink, eventType)
+	histogram := c.latencyPerSubscriber.WithLabelValues(
 		subscriptionName,
 		eventType,
 		fmt.Sprintf("%v", sink),
-		fmt.Sprintf("%v", statusCode)).Observe(duration.Seconds())
+		fmt.Sprintf("%v", statusCode))
+	if observer, ok := histogram.(prometheus.ExemplarObserver); ok && traceID != "" {
+		observer.ObserveWithExemplar(duration.Seconds(), prometheus.Labels{traceIDExemplarLabel: traceID})
+		return
+	}
+	histogram.Observe(duration.Seconds())
+}
+
+// isDeliveryFailure reports whether the given status code of a sink indicates a failed delivery.
+func isDeliveryFailure(statusCode int) bool {
+	return statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices
+}
+
+// OpenMetricsHandler returns a http.Handler serving the registered metrics in the OpenMetrics format,
+// which unlike the Prometheus text format includes the exemplars.
+func OpenMetricsHandler() http.Handler {
+	return promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
+		ErrorHandling:     promhttp.HTTPErrorOnError,
+		EnableOpenMetrics: true,
+	})
 }
 
 // RecordEventTypes records a eventing_ec_event_type_subscribed_total metric.
@@ -182,4 +474,91 @@ func (c *Collector) RemoveSubscriptionStatus(subscriptionName, subscriptionNames
 
 func (c *Collector) ResetSubscriptionStatus() {
 	c.subscriptionStatus.Reset()
+
+	c.readyStatesMu.Lock()
+	defer c.readyStatesMu.Unlock()
+	c.readyStates = map[subscriptionKey]bool{}
+	c.subscriptionsByReady.Reset()
+}
+
+// RecordReconcilePhaseDuration records an eventing_ec_subscription_reconcile_phase_duration_seconds metric.
+func (c *Collector) RecordReconcilePhaseDuration(backendType, phase string, duration time.Duration) {
+	c.reconcilePhaseDuration.WithLabelValues(backendType, phase).Observe(duration.Seconds())
+}
+
+// RecordSubscriptionReady records the ready state of a subscription in the eventing_ec_subscriptions metric.
+func (c *Collector) RecordSubscriptionReady(backendType, subscriptionNamespace, subscriptionName string, ready bool) {
+	key := subscriptionKey{backendType: backendType, namespace: subscriptionNamespace, name: subscriptionName}
+
+	c.readyStatesMu.Lock()
+	defer c.readyStatesMu.Unlock()
+	if previous, ok := c.readyStates[key]; ok {
+		if previous == ready {
+			return
+		}
+		c.subscriptionsByReady.WithLabelValues(backendType, strconv.FormatBool(previous)).Dec()
+	}
+	c.readyStates[key] = ready
+	c.subscriptionsByReady.WithLabelValues(backendType, strconv.FormatBool(ready)).Inc()
+}
+
+// RemoveSubscriptionReady removes a subscription from the eventing_ec_subscriptions metric.
+// If the cardinality of the delivery metrics is capped, it also releases the sink and event type combinations
+// of the subscription, and removes its delivery metrics recorded with them.
+func (c *Collector) RemoveSubscriptionReady(backendType, subscriptionNamespace, subscriptionName string) {
+	for _, released := range c.cardinality.release(subscriptionNamespace, subscriptionName) {
+		labels := prometheus.Labels{
+			subscriptionNameLabel: subscriptionName,
+			eventTypeLabel:        released.eventType,
+			sinkLabel:             released.sink,
+		}
+		c.deliveryPerSubscription.DeletePartialMatch(labels)
+		c.latencyPerSubscriber.DeletePartialMatch(labels)
+		c.deliveryFailures.DeletePartialMatch(prometheus.Labels{
+			subscriptionNameLabel: subscriptionName,
+			sinkLabel:             released.sink,
+		})
+	}
+
+	key := subscriptionKey{backendType: backendType, namespace: subscriptionNamespace, name: subscriptionName}
+
+	c.readyStatesMu.Lock()
+	defer c.readyStatesMu.Unlock()
+	previous, ok := c.readyStates[key]
+	if !ok {
+		return
+	}
+	delete(c.readyStates, key)
+	c.subscriptionsByReady.WithLabelValues(backendType, strconv.FormatBool(previous)).Dec()
+}
+
+// RecordNATSConnected records the NATS connection of the JetStream backend as connected
+// in the eventing_ec_nats_connection_status metric.
+func (c *Collector) RecordNATSConnected() {
+	c.natsConnectionStatus.WithLabelValues().Set(1)
+}
+
+// RecordNATSReconnect records a reconnect of the NATS connection of the JetStream backend
+// in the eventing_ec_nats_reconnects_total and eventing_ec_nats_connection_status metrics.
+func (c *Collector) RecordNATSReconnect() {
+	c.natsReconnects.WithLabelValues().Inc()
+	c.natsConnectionStatus.WithLabelValues().Set(1)
+}
+
+// RecordNATSDisconnect records a disconnect of the NATS connection of the JetStream backend with the given reason
+// in the eventing_ec_nats_last_disconnect_reason and eventing_ec_nats_connection_status metrics.
+// The given reason is expected to be one of the DisconnectReason constants to keep the label bounded.
+func (c *Collector) RecordNATSDisconnect(reason string) {
+	c.natsConnectionStatus.WithLabelValues().Set(0)
+	c.natsLastDisconnect.Reset()
+	c.natsLastDisconnect.WithLabelValues(reason).Set(1)
+}
+
+// RecordFeatureFlags records the given states of the experimental feature flags
+// in the eventing_ec_feature_flag_info metric, replacing the previously recorded states.
+func (c *Collector) RecordFeatureFlags(states map[string]bool) {
+	c.featureFlags.Reset()
+	for flag, enabled := range states {
+		c.featureFlags.WithLabelValues(flag, strconv.FormatBool(enabled)).Set(1)
+	}
 }
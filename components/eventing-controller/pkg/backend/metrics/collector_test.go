@@ -0,0 +1,245 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCollector_SubscriptionsByReady(t *testing.T) {
+	// given
+	collector := NewCollector()
+	ready := func() float64 {
+		return testutil.ToFloat64(collector.subscriptionsByReady.WithLabelValues("nats", "true"))
+	}
+	notReady := func() float64 {
+		return testutil.ToFloat64(collector.subscriptionsByReady.WithLabelValues("nats", "false"))
+	}
+
+	// when
+	collector.RecordSubscriptionReady("nats", "ns", "sub1", false)
+	collector.RecordSubscriptionReady("nats", "ns", "sub2", true)
+	collector.RecordSubscriptionReady("nats", "ns", "sub2", true)
+
+	// then
+	require.Equal(t, float64(1), ready())
+	require.Equal(t, float64(1), notReady())
+
+	// when
+	collector.RecordSubscriptionReady("nats", "ns", "sub1", true)
+
+	// then
+	require.Equal(t, float64(2), ready())
+	require.Equal(t, float64(0), notReady())
+
+	// when
+	collector.RemoveSubscriptionReady("nats", "ns", "sub1")
+	collector.RemoveSubscriptionReady("nats", "ns", "unknown")
+
+	// then
+	require.Equal(t, float64(1), ready())
+	require.Equal(t, float64(0), notReady())
+}
+
+func TestCollector_Exemplars(t *testing.T) {
+	// given
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	collector := NewCollector()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	// when
+	collector.RecordDeliveryPerSubscription("ns", "sub", "type", "sink", http.StatusOK, traceID)
+	collector.RecordDeliveryPerSubscription("ns", "sub", "type", "sink", http.StatusBadGateway, traceID)
+	collector.RecordLatencyPerSubscription(time.Millisecond, "sub", "type", "sink", http.StatusOK, traceID)
+	collector.RecordLatencyPerSubscription(time.Millisecond, "sub", "type", "sink", http.StatusAccepted, "")
+
+	// then only the failed deliveries have exemplars
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	exemplars := map[string]string{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			code := ""
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == responseCodeLabel {
+					code = label.GetValue()
+				}
+			}
+			exemplar := metric.GetCounter().GetExemplar()
+			for _, bucket := range metric.GetHistogram().GetBucket() {
+				if bucket.GetExemplar() != nil {
+					exemplar = bucket.GetExemplar()
+				}
+			}
+			if exemplar != nil {
+				require.Len(t, exemplar.GetLabel(), 1)
+				exemplars[family.GetName()+"/"+code] = exemplar.GetLabel()[0].GetValue()
+			}
+		}
+	}
+	require.Equal(t, map[string]string{
+		deliveryMetricKey + "/502": traceID,
+		latencyMetricKey + "/200":  traceID,
+	}, exemplars)
+}
+
+func TestCollector_DeliveryFailures(t *testing.T) {
+	// given
+	collector := NewCollector()
+	failures := func(reason string) float64 {
+		return testutil.ToFloat64(collector.deliveryFailures.WithLabelValues("sub", "sink", reason))
+	}
+
+	// when
+	collector.RecordDeliveryFailure("ns", "sub", "sink", FailureReasonTimeout)
+	collector.RecordDeliveryFailure("ns", "sub", "sink", FailureReasonTimeout)
+	collector.RecordDeliveryFailure("ns", "sub", "sink", FailureReasonServerError)
+
+	// then
+	require.Equal(t, float64(2), failures(FailureReasonTimeout))
+	require.Equal(t, float64(1), failures(FailureReasonServerError))
+	require.Equal(t, float64(0), failures(FailureReasonDNS))
+}
+
+func TestCollector_CardinalityLimit(t *testing.T) {
+	// given
+	core, logs := observer.New(zap.WarnLevel)
+	collector := NewCollector()
+	collector.SetCardinalityLimit(2, zap.New(core).Sugar())
+	deliveries := func(sink, eventType string) float64 {
+		return testutil.ToFloat64(collector.deliveryPerSubscription.WithLabelValues("sub", eventType, sink, "200"))
+	}
+
+	// when
+	collector.RecordDeliveryPerSubscription("ns", "sub", "type1", "sink1", http.StatusOK, "")
+	collector.RecordDeliveryPerSubscription("ns", "sub", "type2", "sink1", http.StatusOK, "")
+	collector.RecordDeliveryPerSubscription("ns", "sub", "type3", "sink2", http.StatusOK, "")
+	collector.RecordDeliveryPerSubscription("ns", "sub", "type4", "sink3", http.StatusOK, "")
+	collector.RecordDeliveryPerSubscription("ns", "sub", "type1", "sink1", http.StatusOK, "")
+	collector.RecordDeliveryFailure("ns", "sub", "sink1", FailureReasonTimeout)
+	collector.RecordDeliveryFailure("ns", "sub", "sink2", FailureReasonTimeout)
+
+	// then
+	require.Equal(t, float64(2), deliveries("sink1", "type1"))
+	require.Equal(t, float64(1), deliveries("sink1", "type2"))
+	require.Equal(t, float64(2), deliveries(OverflowLabelValue, OverflowLabelValue))
+	require.Equal(t, 3, testutil.CollectAndCount(collector.deliveryPerSubscription))
+	require.Equal(t, float64(1),
+		testutil.ToFloat64(collector.deliveryFailures.WithLabelValues("sub", "sink1", FailureReasonTimeout)))
+	require.Equal(t, float64(1),
+		testutil.ToFloat64(collector.deliveryFailures.WithLabelValues("sub", OverflowLabelValue, FailureReasonTimeout)))
+	require.Equal(t, 1, logs.Len())
+}
+
+func TestCollector_CardinalityLimitRelease(t *testing.T) {
+	// given
+	collector := NewCollector()
+	collector.SetCardinalityLimit(2, zap.NewNop().Sugar())
+	deliveries := func(sub, sink, eventType string) float64 {
+		return testutil.ToFloat64(collector.deliveryPerSubscription.WithLabelValues(sub, eventType, sink, "200"))
+	}
+	collector.RecordSubscriptionReady("nats", "ns", "sub1", true)
+	collector.RecordSubscriptionReady("nats", "ns", "sub2", true)
+	collector.RecordDeliveryPerSubscription("ns", "sub1", "type1", "sink1", http.StatusOK, "")
+	collector.RecordLatencyPerSubscription(time.Millisecond, "sub1", "type1", "sink1", http.StatusOK, "")
+	collector.RecordDeliveryFailure("ns", "sub1", "sink1", FailureReasonTimeout)
+	collector.RecordDeliveryPerSubscription("ns", "sub2", "type2", "sink2", http.StatusOK, "")
+	collector.RecordDeliveryPerSubscription("ns", "sub2", "type1", "sink1", http.StatusOK, "")
+
+	// when the limit is reached
+	collector.RecordDeliveryPerSubscription("ns", "sub3", "type3", "sink3", http.StatusOK, "")
+
+	// then the delivery is aggregated
+	require.Equal(t, float64(1), deliveries("sub3", OverflowLabelValue, OverflowLabelValue))
+
+	// when a subscription sharing its combination with another one is deleted
+	collector.RemoveSubscriptionReady("nats", "ns", "sub1")
+	collector.RecordDeliveryPerSubscription("ns", "sub3", "type3", "sink3", http.StatusOK, "")
+
+	// then its metrics are removed, but the combination keeps its slot
+	require.Equal(t, float64(2), deliveries("sub3", OverflowLabelValue, OverflowLabelValue))
+	require.Equal(t, 0, testutil.CollectAndCount(collector.latencyPerSubscriber))
+	require.Equal(t, 0, testutil.CollectAndCount(collector.deliveryFailures))
+
+	// when the last subscription of the combinations is deleted
+	collector.RemoveSubscriptionReady("nats", "ns", "sub2")
+	collector.RecordDeliveryPerSubscription("ns", "sub3", "type3", "sink3", http.StatusOK, "")
+
+	// then the freed slots admit new combinations
+	require.Equal(t, float64(1), deliveries("sub3", "sink3", "type3"))
+	require.Equal(t, 2, testutil.CollectAndCount(collector.deliveryPerSubscription))
+}
+
+func TestCollector_NATSConnection(t *testing.T) {
+	// given
+	collector := NewCollector()
+	status := func() float64 {
+		return testutil.ToFloat64(collector.natsConnectionStatus.WithLabelValues())
+	}
+
+	// when
+	collector.RecordNATSConnected()
+
+	// then
+	require.Equal(t, float64(1), status())
+
+	// when
+	collector.RecordNATSDisconnect(DisconnectReasonStaleConnection)
+	collector.RecordNATSReconnect()
+	collector.RecordNATSDisconnect(DisconnectReasonConnectionReset)
+
+	// then
+	require.Equal(t, float64(0), status())
+	require.Equal(t, float64(1), testutil.ToFloat64(collector.natsReconnects.WithLabelValues()))
+	require.Equal(t, 1, testutil.CollectAndCount(collector.natsLastDisconnect))
+	require.Equal(t, float64(1),
+		testutil.ToFloat64(collector.natsLastDisconnect.WithLabelValues(DisconnectReasonConnectionReset)))
+
+	// when
+	collector.RecordNATSReconnect()
+
+	// then
+	require.Equal(t, float64(1), status())
+	require.Equal(t, float64(2), testutil.ToFloat64(collector.natsReconnects.WithLabelValues()))
+}
+
+func TestCollector_PerNamespace(t *testing.T) {
+	// given
+	collector := NewCollector()
+
+	// when
+	collector.RecordDeliveryPerSubscription("ns1", "sub1", "type", "sink1", http.StatusOK, "")
+	collector.RecordDeliveryPerSubscription("ns1", "sub2", "type", "sink2", http.StatusOK, "")
+	collector.RecordDeliveryPerSubscription("ns2", "sub1", "type", "sink3", http.StatusBadGateway, "")
+	collector.RecordDeliveryFailure("ns2", "sub1", "sink3", FailureReasonServerError)
+
+	// then
+	require.Equal(t, float64(2),
+		testutil.ToFloat64(collector.deliveryPerNamespace.WithLabelValues("ns1", "200")))
+	require.Equal(t, float64(1),
+		testutil.ToFloat64(collector.deliveryPerNamespace.WithLabelValues("ns2", "502")))
+	require.Equal(t, 2, testutil.CollectAndCount(collector.deliveryPerNamespace))
+	require.Equal(t, float64(1),
+		testutil.ToFloat64(collector.deliveryFailuresPerNamespace.WithLabelValues("ns2", FailureReasonServerError)))
+}
+
+func TestCollector_FeatureFlags(t *testing.T) {
+	// given
+	collector := NewCollector()
+	collector.RecordFeatureFlags(map[string]bool{"pull-consumers": false, "batch-dispatch": false})
+
+	// when
+	collector.RecordFeatureFlags(map[string]bool{"pull-consumers": true, "batch-dispatch": false})
+
+	// then the previous states are replaced
+	require.Equal(t, 2, testutil.CollectAndCount(collector.featureFlags))
+	require.Equal(t, float64(1), testutil.ToFloat64(collector.featureFlags.WithLabelValues("pull-consumers", "true")))
+	require.Equal(t, float64(1), testutil.ToFloat64(collector.featureFlags.WithLabelValues("batch-dispatch", "false")))
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// descRegexp matches the string representation of a prometheus.Desc, which does not expose its fields otherwise.
+//
+//nolint:gochecknoglobals // compiled once
+var descRegexp = regexp.MustCompile(
+	`^Desc\{fqName: ("(?:[^"\\]|\\.)*"), help: ("(?:[^"\\]|\\.)*"), constLabels: \{.*\}, variableLabels: \{(.*)\}\}$`)
+
+// Descriptor describes a metric exposed by the eventing-controller.
+type Descriptor struct {
+	Name string
+	Help string
+	// Type is either TypeCounter, TypeGauge or TypeHistogram.
+	Type   string
+	Labels []string
+}
+
+// HasLabel returns true if the metric has the given label.
+func (d Descriptor) HasLabel(label string) bool {
+	for _, l := range d.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// Descriptors returns the descriptors of the metrics of the Collector and the JetStreamCollector sorted by name,
+// for example to generate the dashboards from them.
+func Descriptors() ([]Descriptor, error) {
+	c := NewCollector()
+	typed := []struct {
+		metricType string
+		collector  prometheus.Collector
+	}{
+		{TypeCounter, c.deliveryPerSubscription},
+		{TypeCounter, c.deliveryFailures},
+		{TypeCounter, c.deliveryPerNamespace},
+		{TypeCounter, c.deliveryFailuresPerNamespace},
+		{TypeCounter, c.eventTypes},
+		{TypeHistogram, c.latencyPerSubscriber},
+		{TypeGauge, c.health},
+		{TypeGauge, c.subscriptionStatus},
+		{TypeHistogram, c.reconcilePhaseDuration},
+		{TypeGauge, c.subscriptionsByReady},
+		{TypeGauge, c.natsConnectionStatus},
+		{TypeCounter, c.natsReconnects},
+		{TypeGauge, c.natsLastDisconnect},
+		{TypeGauge, c.featureFlags},
+		// the JetStreamCollector only collects gauges
+		{TypeGauge, NewJetStreamCollector(nil, nil)},
+	}
+
+	var descriptors []Descriptor
+	for _, t := range typed {
+		ch := make(chan *prometheus.Desc, 16)
+		t.collector.Describe(ch)
+		close(ch)
+		for desc := range ch {
+			descriptor, err := parseDesc(desc)
+			if err != nil {
+				return nil, err
+			}
+			descriptor.Type = t.metricType
+			descriptors = append(descriptors, descriptor)
+		}
+	}
+	sort.Slice(descriptors, func(i, j int) bool { return descriptors[i].Name < descriptors[j].Name })
+	return descriptors, nil
+}
+
+func parseDesc(desc *prometheus.Desc) (Descriptor, error) {
+	matches := descRegexp.FindStringSubmatch(desc.String())
+	if matches == nil {
+		return Descriptor{}, fmt.Errorf("failed to parse the metric descriptor %s", desc)
+	}
+	name, err := strconv.Unquote(matches[1])
+	if err != nil {
+		return Descriptor{}, err
+	}
+	help, err := strconv.Unquote(matches[2])
+	if err != nil {
+		return Descriptor{}, err
+	}
+	var labels []string
+	for _, label := range strings.Split(matches[3], ",") {
+		// constrained labels are shown as c(<label>)
+		label = strings.TrimSuffix(strings.TrimPrefix(label, "c("), ")")
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return Descriptor{Name: name, Help: help, Labels: labels}, nil
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptors(t *testing.T) {
+	// when
+	descriptors, err := Descriptors()
+
+	// then all the described metrics are returned with their type and labels
+	require.NoError(t, err)
+	ch := make(chan *prometheus.Desc, 64)
+	NewCollector().Describe(ch)
+	NewJetStreamCollector(nil, nil).Describe(ch)
+	close(ch)
+	require.Len(t, descriptors, len(ch))
+
+	byName := map[string]Descriptor{}
+	for _, d := range descriptors {
+		byName[d.Name] = d
+	}
+	require.Equal(t, deliveryFailuresMetricHelp, byName[deliveryFailuresMetricKey].Help)
+	require.Equal(t, TypeCounter, byName[deliveryFailuresMetricKey].Type)
+	require.True(t, byName[deliveryFailuresMetricKey].HasLabel(reasonLabel))
+	require.Equal(t, TypeHistogram, byName[latencyMetricKey].Type)
+	require.Equal(t, TypeGauge, byName[natsConnectionStatusMetricKey].Type)
+	require.Equal(t, TypeGauge, byName[pendingPerNamespaceMetricKey].Type)
+	require.Equal(t, []string{streamNameLabel, subscriptionNamespaceLabel}, byName[pendingPerNamespaceMetricKey].Labels)
+	require.Empty(t, byName[natsRTTMetricKey].Labels)
+}
@@ -0,0 +1,182 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// jetStreamCollectTimeout is the maximum duration of querying the stream and consumer infos on a scrape.
+	jetStreamCollectTimeout = 5 * time.Second
+
+	// streamMessagesMetricKey name of the stream messages metric.
+	streamMessagesMetricKey = "eventing_ec_jetstream_stream_messages"
+	// streamMessagesMetricHelp help text for the stream messages metric.
+	streamMessagesMetricHelp = "The number of messages stored in the JetStream stream"
+
+	// streamBytesMetricKey name of the stream bytes metric.
+	streamBytesMetricKey = "eventing_ec_jetstream_stream_bytes"
+	// streamBytesMetricHelp help text for the stream bytes metric.
+	streamBytesMetricHelp = "The number of bytes stored in the JetStream stream"
+
+	// streamMaxMessagesMetricKey name of the stream max messages metric.
+	streamMaxMessagesMetricKey = "eventing_ec_jetstream_stream_max_messages"
+	// streamMaxMessagesMetricHelp help text for the stream max messages metric.
+	streamMaxMessagesMetricHelp = "The maximum number of messages of the JetStream stream. `-1` indicates no limit"
+
+	// streamMaxBytesMetricKey name of the stream max bytes metric.
+	streamMaxBytesMetricKey = "eventing_ec_jetstream_stream_max_bytes"
+	// streamMaxBytesMetricHelp help text for the stream max bytes metric.
+	streamMaxBytesMetricHelp = "The maximum number of bytes of the JetStream stream. `-1` indicates no limit"
+
+	// consumerPendingMetricKey name of the consumer pending messages metric.
+	consumerPendingMetricKey = "eventing_ec_jetstream_consumer_pending_messages"
+	// consumerPendingMetricHelp help text for the consumer pending messages metric.
+	consumerPendingMetricHelp = "The number of messages of the JetStream consumer not delivered yet"
+
+	// consumerAckPendingMetricKey name of the consumer ack pending messages metric.
+	consumerAckPendingMetricKey = "eventing_ec_jetstream_consumer_ack_pending_messages"
+	// consumerAckPendingMetricHelp help text for the consumer ack pending messages metric.
+	consumerAckPendingMetricHelp = "The number of messages of the JetStream consumer delivered but not acknowledged yet"
+
+	// consumerRedeliveredMetricKey name of the consumer redelivered messages metric.
+	consumerRedeliveredMetricKey = "eventing_ec_jetstream_consumer_redelivered_messages"
+	// consumerRedeliveredMetricHelp help text for the consumer redelivered messages metric.
+	consumerRedeliveredMetricHelp = "The number of messages of the JetStream consumer redelivered and not acknowledged yet"
+
+	// pendingPerNamespaceMetricKey name of the pending messages per namespace metric.
+	pendingPerNamespaceMetricKey = "eventing_ec_jetstream_pending_messages_per_namespace"
+	// pendingPerNamespaceMetricHelp help text for the pending messages per namespace metric.
+	pendingPerNamespaceMetricHelp = "The number of messages not delivered or not acknowledged yet per namespace"
+
+	// consumerNamespaceMetadataKey is the consumer metadata key of the namespace of the subscription owning
+	// the consumer, as set by the JetStream backend.
+	consumerNamespaceMetadataKey = "eventing.kyma-project.io/subscription-namespace"
+
+	// natsRTTMetricKey name of the NATS round trip time metric.
+	natsRTTMetricKey = "eventing_ec_nats_rtt_seconds"
+	// natsRTTMetricHelp help text for the NATS round trip time metric.
+	natsRTTMetricHelp = "The round trip time of the NATS connection of the JetStream backend"
+)
+
+// JetStreamSource provides the JetStream context and the name of the stream the JetStreamCollector collects from.
+type JetStreamSource interface {
+	// JetStreamStream returns the current JetStream context and stream name,
+	// or a nil context if the JetStream backend is not running.
+	JetStreamStream() (nats.JetStreamContext, string)
+	// NATSConnection returns the current NATS connection, or nil if the JetStream backend is not running.
+	NATSConnection() *nats.Conn
+}
+
+// JetStreamCollector implements the prometheus.Collector interface exposing the state of the JetStream stream
+// and its consumers, which are queried on every scrape.
+type JetStreamCollector struct {
+	source JetStreamSource
+	logger *zap.SugaredLogger
+
+	streamMessages      *prometheus.Desc
+	streamBytes         *prometheus.Desc
+	streamMaxMessages   *prometheus.Desc
+	streamMaxBytes      *prometheus.Desc
+	consumerPending     *prometheus.Desc
+	consumerAckPending  *prometheus.Desc
+	consumerRedelivered *prometheus.Desc
+	pendingPerNamespace *prometheus.Desc
+	natsRTT             *prometheus.Desc
+}
+
+// NewJetStreamCollector returns a new instance of JetStreamCollector collecting from the given source.
+func NewJetStreamCollector(source JetStreamSource, logger *zap.SugaredLogger) *JetStreamCollector {
+	streamLabels := []string{streamNameLabel}
+	consumerLabels := []string{streamNameLabel, consumerNameLabel}
+	return &JetStreamCollector{
+		source: source,
+		logger: logger,
+
+		streamMessages:    prometheus.NewDesc(streamMessagesMetricKey, streamMessagesMetricHelp, streamLabels, nil),
+		streamBytes:       prometheus.NewDesc(streamBytesMetricKey, streamBytesMetricHelp, streamLabels, nil),
+		streamMaxMessages: prometheus.NewDesc(streamMaxMessagesMetricKey, streamMaxMessagesMetricHelp, streamLabels, nil),
+		streamMaxBytes:    prometheus.NewDesc(streamMaxBytesMetricKey, streamMaxBytesMetricHelp, streamLabels, nil),
+		consumerPending:   prometheus.NewDesc(consumerPendingMetricKey, consumerPendingMetricHelp, consumerLabels, nil),
+		consumerAckPending: prometheus.NewDesc(consumerAckPendingMetricKey, consumerAckPendingMetricHelp,
+			consumerLabels, nil),
+		consumerRedelivered: prometheus.NewDesc(consumerRedeliveredMetricKey, consumerRedeliveredMetricHelp,
+			consumerLabels, nil),
+		pendingPerNamespace: prometheus.NewDesc(pendingPerNamespaceMetricKey, pendingPerNamespaceMetricHelp,
+			[]string{streamNameLabel, subscriptionNamespaceLabel}, nil),
+		natsRTT: prometheus.NewDesc(natsRTTMetricKey, natsRTTMetricHelp, nil, nil),
+	}
+}
+
+// Describe implements the prometheus.Collector interface Describe method.
+func (c *JetStreamCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.streamMessages
+	ch <- c.streamBytes
+	ch <- c.streamMaxMessages
+	ch <- c.streamMaxBytes
+	ch <- c.consumerPending
+	ch <- c.consumerAckPending
+	ch <- c.consumerRedelivered
+	ch <- c.pendingPerNamespace
+	ch <- c.natsRTT
+}
+
+// Collect implements the prometheus.Collector interface Collect method.
+// It collects nothing if the JetStream backend is not running or the stream cannot be queried.
+func (c *JetStreamCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectRTT(ch)
+
+	jsCtx, streamName := c.source.JetStreamStream()
+	if jsCtx == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), jetStreamCollectTimeout)
+	defer cancel()
+
+	info, err := jsCtx.StreamInfo(streamName, nats.Context(ctx))
+	if err != nil {
+		c.logger.Warnw("Failed to collect the JetStream stream metrics", "stream", streamName, "error", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.streamMessages, prometheus.GaugeValue, float64(info.State.Msgs), streamName)
+	ch <- prometheus.MustNewConstMetric(c.streamBytes, prometheus.GaugeValue, float64(info.State.Bytes), streamName)
+	ch <- prometheus.MustNewConstMetric(c.streamMaxMessages, prometheus.GaugeValue,
+		float64(info.Config.MaxMsgs), streamName)
+	ch <- prometheus.MustNewConstMetric(c.streamMaxBytes, prometheus.GaugeValue, float64(info.Config.MaxBytes), streamName)
+
+	// the pending messages of the consumers are aggregated per namespace of the subscriptions owning them
+	pendingPerNamespace := map[string]uint64{}
+	for consumer := range jsCtx.Consumers(streamName, nats.Context(ctx)) {
+		if namespace, ok := consumer.Config.Metadata[consumerNamespaceMetadataKey]; ok {
+			pendingPerNamespace[namespace] += consumer.NumPending + uint64(consumer.NumAckPending)
+		}
+		ch <- prometheus.MustNewConstMetric(c.consumerPending, prometheus.GaugeValue,
+			float64(consumer.NumPending), streamName, consumer.Name)
+		ch <- prometheus.MustNewConstMetric(c.consumerAckPending, prometheus.GaugeValue,
+			float64(consumer.NumAckPending), streamName, consumer.Name)
+		ch <- prometheus.MustNewConstMetric(c.consumerRedelivered, prometheus.GaugeValue,
+			float64(consumer.NumRedelivered), streamName, consumer.Name)
+	}
+	for namespace, pending := range pendingPerNamespace {
+		ch <- prometheus.MustNewConstMetric(c.pendingPerNamespace, prometheus.GaugeValue,
+			float64(pending), streamName, namespace)
+	}
+}
+
+// collectRTT collects the round trip time of the NATS connection, if it is connected.
+func (c *JetStreamCollector) collectRTT(ch chan<- prometheus.Metric) {
+	conn := c.source.NATSConnection()
+	if conn == nil || !conn.IsConnected() {
+		return
+	}
+	rtt, err := conn.RTT()
+	if err != nil {
+		c.logger.Warnw("Failed to collect the NATS round trip time", "error", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.natsRTT, prometheus.GaugeValue, rtt.Seconds())
+}
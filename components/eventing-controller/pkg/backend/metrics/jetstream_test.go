@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	jetstreammocks "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream/mocks"
+	evtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+type jetStreamSourceStub struct {
+	jsCtx nats.JetStreamContext
+	conn  *nats.Conn
+}
+
+func (s jetStreamSourceStub) JetStreamStream() (nats.JetStreamContext, string) {
+	return s.jsCtx, "kyma"
+}
+
+func (s jetStreamSourceStub) NATSConnection() *nats.Conn {
+	return s.conn
+}
+
+func TestJetStreamCollector(t *testing.T) {
+	// given
+	jsCtx := &jetstreammocks.JetStreamContext{}
+	jsCtx.On("StreamInfo", "kyma", mock.Anything).Return(&nats.StreamInfo{
+		Config: nats.StreamConfig{MaxMsgs: -1, MaxBytes: 1024},
+		State:  nats.StreamState{Msgs: 10, Bytes: 512},
+	}, nil)
+	consumers := make(chan *nats.ConsumerInfo, 1)
+	consumers <- &nats.ConsumerInfo{
+		Name:           "consumer1",
+		Config:         nats.ConsumerConfig{Metadata: map[string]string{consumerNamespaceMetadataKey: "ns"}},
+		NumPending:     3,
+		NumAckPending:  2,
+		NumRedelivered: 1,
+	}
+	close(consumers)
+	jsCtx.On("Consumers", "kyma", mock.Anything).Return((<-chan *nats.ConsumerInfo)(consumers))
+	collector := NewJetStreamCollector(jetStreamSourceStub{jsCtx: jsCtx}, zap.NewNop().Sugar())
+
+	// when
+	err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP eventing_ec_jetstream_consumer_ack_pending_messages The number of messages of the JetStream consumer delivered but not acknowledged yet
+# TYPE eventing_ec_jetstream_consumer_ack_pending_messages gauge
+eventing_ec_jetstream_consumer_ack_pending_messages{consumer_name="consumer1",stream_name="kyma"} 2
+# HELP eventing_ec_jetstream_consumer_pending_messages The number of messages of the JetStream consumer not delivered yet
+# TYPE eventing_ec_jetstream_consumer_pending_messages gauge
+eventing_ec_jetstream_consumer_pending_messages{consumer_name="consumer1",stream_name="kyma"} 3
+# HELP eventing_ec_jetstream_consumer_redelivered_messages The number of messages of the JetStream consumer redelivered and not acknowledged yet
+# TYPE eventing_ec_jetstream_consumer_redelivered_messages gauge
+eventing_ec_jetstream_consumer_redelivered_messages{consumer_name="consumer1",stream_name="kyma"} 1
+# HELP eventing_ec_jetstream_pending_messages_per_namespace The number of messages not delivered or not acknowledged yet per namespace
+# TYPE eventing_ec_jetstream_pending_messages_per_namespace gauge
+eventing_ec_jetstream_pending_messages_per_namespace{stream_name="kyma",subscription_namespace="ns"} 5
+# HELP eventing_ec_jetstream_stream_bytes The number of bytes stored in the JetStream stream
+# TYPE eventing_ec_jetstream_stream_bytes gauge
+eventing_ec_jetstream_stream_bytes{stream_name="kyma"} 512
+# HELP eventing_ec_jetstream_stream_max_bytes The maximum number of bytes of the JetStream stream. `+"`-1`"+` indicates no limit
+# TYPE eventing_ec_jetstream_stream_max_bytes gauge
+eventing_ec_jetstream_stream_max_bytes{stream_name="kyma"} 1024
+# HELP eventing_ec_jetstream_stream_max_messages The maximum number of messages of the JetStream stream. `+"`-1`"+` indicates no limit
+# TYPE eventing_ec_jetstream_stream_max_messages gauge
+eventing_ec_jetstream_stream_max_messages{stream_name="kyma"} -1
+# HELP eventing_ec_jetstream_stream_messages The number of messages stored in the JetStream stream
+# TYPE eventing_ec_jetstream_stream_messages gauge
+eventing_ec_jetstream_stream_messages{stream_name="kyma"} 10
+`))
+
+	// then
+	require.NoError(t, err)
+}
+
+func TestJetStreamCollector_NothingToCollect(t *testing.T) {
+	// given
+	failingJSCtx := &jetstreammocks.JetStreamContext{}
+	failingJSCtx.On("StreamInfo", "kyma", mock.Anything).Return(nil, errors.New("connection closed"))
+
+	testCases := []struct {
+		name        string
+		givenSource JetStreamSource
+	}{
+		{
+			name:        "should collect nothing if the JetStream backend is not running",
+			givenSource: jetStreamSourceStub{},
+		},
+		{
+			name:        "should collect nothing if the stream cannot be queried",
+			givenSource: jetStreamSourceStub{jsCtx: failingJSCtx},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			collector := NewJetStreamCollector(tc.givenSource, zap.NewNop().Sugar())
+			require.Equal(t, 0, testutil.CollectAndCount(collector))
+		})
+	}
+}
+
+func TestJetStreamCollector_RTT(t *testing.T) {
+	// given
+	port, err := evtesting.GetFreePort()
+	require.NoError(t, err)
+	natsServer := evtesting.RunNatsServerOnPort(evtesting.WithPort(port))
+	defer evtesting.ShutDownNATSServer(natsServer)
+	conn, err := nats.Connect(natsServer.ClientURL())
+	require.NoError(t, err)
+	defer conn.Close()
+	collector := NewJetStreamCollector(jetStreamSourceStub{conn: conn}, zap.NewNop().Sugar())
+
+	// when
+	count := testutil.CollectAndCount(collector, natsRTTMetricKey)
+
+	// then
+	require.Equal(t, 1, count)
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	otelprometheus "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// StartOTLPExport pushes the metrics of the Collector to the OTLP HTTP endpoint at the given URL every interval,
+// in addition to exposing them to Prometheus. The standard OTEL_EXPORTER_OTLP_* environment variables configure
+// the export further, such as the headers or the TLS certificates.
+// It returns a function which pushes the pending metrics and stops the export.
+func (c *Collector) StartOTLPExport(ctx context.Context, endpointURL string,
+	interval time.Duration) (func(context.Context) error, error) {
+	exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(endpointURL))
+	if err != nil {
+		return nil, err
+	}
+	registry, err := c.NewRegistry()
+	if err != nil {
+		return nil, err
+	}
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(interval),
+		sdkmetric.WithProducer(otelprometheus.NewMetricProducer(otelprometheus.WithGatherer(registry))),
+	)
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector_StartOTLPExport(t *testing.T) {
+	// given
+	bodies := make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	collector := NewCollector()
+	collector.RecordReconcilePhaseDuration("nats", PhaseBackendSync, time.Second)
+
+	// when
+	stop, err := collector.StartOTLPExport(context.Background(), server.URL+"/v1/metrics", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, stop(context.Background()))
+
+	// then the pending metrics are pushed on stop
+	require.Len(t, bodies, 1)
+	assert.Contains(t, string(<-bodies), reconcilePhaseDurationMetricKey)
+}
@@ -0,0 +1,245 @@
+package metrics
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// sinkHealthBuckets is the number of buckets the rolling window of the sink health is divided into.
+	sinkHealthBuckets = 10
+
+	// sinkSuccessRatioMetricKey name of the sink success ratio metric.
+	sinkSuccessRatioMetricKey = "eventing_ec_sink_success_ratio"
+	// sinkSuccessRatioMetricHelp help text for the sink success ratio metric.
+	sinkSuccessRatioMetricHelp = "The ratio of the successful deliveries to the sink host within the rolling window"
+
+	// sinkLatencyMetricKey name of the sink average latency metric.
+	sinkLatencyMetricKey = "eventing_ec_sink_latency_average_seconds"
+	// sinkLatencyMetricHelp help text for the sink average latency metric.
+	sinkLatencyMetricHelp = "The average duration of the deliveries to the sink host within the rolling window"
+
+	// sinkDeliveriesMetricKey name of the sink deliveries metric.
+	sinkDeliveriesMetricKey = "eventing_ec_sink_deliveries"
+	// sinkDeliveriesMetricHelp help text for the sink deliveries metric.
+	sinkDeliveriesMetricHelp = "The number of deliveries to the sink host within the rolling window"
+
+	// sinkSubscriptionsMetricKey name of the sink subscriptions metric.
+	sinkSubscriptionsMetricKey = "eventing_ec_sink_subscriptions"
+	// sinkSubscriptionsMetricHelp help text for the sink subscriptions metric.
+	sinkSubscriptionsMetricHelp = "The number of subscriptions which delivered to the sink host within the rolling window"
+
+	// sinkHealthyMetricKey name of the sink healthy metric.
+	sinkHealthyMetricKey = "eventing_ec_sink_healthy"
+	// sinkHealthyMetricHelp help text for the sink healthy metric.
+	sinkHealthyMetricHelp = "The health of the sink host. `1` indicates a healthy sink host"
+
+	sinkHostLabel = "sink_host"
+)
+
+// SinkHealth is the health of a sink host aggregated over the deliveries of all subscriptions
+// within the rolling window.
+type SinkHealth struct {
+	// Host is the host of the sink URLs, including the port if any.
+	Host string
+	// Deliveries is the number of deliveries to the host.
+	Deliveries int
+	// SuccessRatio is the ratio of the successful deliveries, between 0 and 1.
+	SuccessRatio float64
+	// AverageLatency is the average duration of the deliveries.
+	AverageLatency time.Duration
+	// Subscriptions is the number of subscriptions which delivered to the host.
+	Subscriptions int
+	// Healthy is false if there were enough deliveries to score the host and too few of them succeeded.
+	Healthy bool
+}
+
+// SinkHealthPolicy determines the rolling window of the sink health and when a sink host is unhealthy.
+type SinkHealthPolicy struct {
+	// Window is the duration of the rolling window. The sink health is not tracked if it is not positive.
+	Window time.Duration
+	// MinDeliveries is the minimal number of deliveries within the window to consider a host unhealthy.
+	MinDeliveries int
+	// MinSuccessRatio is the success ratio below which a host is unhealthy.
+	MinSuccessRatio float64
+}
+
+// sinkHealthBucket counts the deliveries to a sink host within a part of the rolling window.
+type sinkHealthBucket struct {
+	start         time.Time
+	successes     int
+	failures      int
+	latency       time.Duration
+	subscriptions map[string]struct{}
+}
+
+// sinkHealthTracker tracks the deliveries per sink host within a rolling window
+// and implements the prometheus.Collector interface exposing their health.
+type sinkHealthTracker struct {
+	policy         SinkHealthPolicy
+	bucketDuration time.Duration
+	now            func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*[sinkHealthBuckets]sinkHealthBucket
+
+	successRatio  *prometheus.Desc
+	latency       *prometheus.Desc
+	deliveries    *prometheus.Desc
+	subscriptions *prometheus.Desc
+	healthy       *prometheus.Desc
+}
+
+func newSinkHealthTracker(policy SinkHealthPolicy) *sinkHealthTracker {
+	labels := []string{sinkHostLabel}
+	return &sinkHealthTracker{
+		policy:         policy,
+		bucketDuration: policy.Window / sinkHealthBuckets,
+		now:            time.Now,
+		hosts:          map[string]*[sinkHealthBuckets]sinkHealthBucket{},
+		successRatio:   prometheus.NewDesc(sinkSuccessRatioMetricKey, sinkSuccessRatioMetricHelp, labels, nil),
+		latency:        prometheus.NewDesc(sinkLatencyMetricKey, sinkLatencyMetricHelp, labels, nil),
+		deliveries:     prometheus.NewDesc(sinkDeliveriesMetricKey, sinkDeliveriesMetricHelp, labels, nil),
+		subscriptions:  prometheus.NewDesc(sinkSubscriptionsMetricKey, sinkSubscriptionsMetricHelp, labels, nil),
+		healthy:        prometheus.NewDesc(sinkHealthyMetricKey, sinkHealthyMetricHelp, labels, nil),
+	}
+}
+
+// record counts a delivery of the given subscription to the host of the given sink.
+func (t *sinkHealthTracker) record(subscriptionNamespace, subscriptionName, sink string,
+	duration time.Duration, success bool) {
+	if t == nil {
+		return
+	}
+	host := SinkHost(sink)
+	start := t.now().Truncate(t.bucketDuration)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	buckets, ok := t.hosts[host]
+	if !ok {
+		buckets = &[sinkHealthBuckets]sinkHealthBucket{}
+		t.hosts[host] = buckets
+	}
+	bucket := &buckets[(start.UnixNano()/int64(t.bucketDuration))%sinkHealthBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = sinkHealthBucket{start: start, subscriptions: map[string]struct{}{}}
+	}
+	if success {
+		bucket.successes++
+	} else {
+		bucket.failures++
+	}
+	bucket.latency += duration
+	bucket.subscriptions[subscriptionNamespace+"/"+subscriptionName] = struct{}{}
+}
+
+// health returns the health of the given sink host, and false if there was no delivery to it within the window.
+func (t *sinkHealthTracker) health(host string) (SinkHealth, bool) {
+	if t == nil {
+		return SinkHealth{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	buckets, ok := t.hosts[host]
+	if !ok {
+		return SinkHealth{}, false
+	}
+	return t.aggregate(host, buckets)
+}
+
+// all returns the health of all sink hosts with deliveries within the window,
+// and forgets the hosts without any.
+func (t *sinkHealthTracker) all() []SinkHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	healths := make([]SinkHealth, 0, len(t.hosts))
+	for host, buckets := range t.hosts {
+		health, ok := t.aggregate(host, buckets)
+		if !ok {
+			delete(t.hosts, host)
+			continue
+		}
+		healths = append(healths, health)
+	}
+	return healths
+}
+
+// aggregate sums up the buckets within the window. The caller must hold the lock.
+func (t *sinkHealthTracker) aggregate(host string, buckets *[sinkHealthBuckets]sinkHealthBucket) (SinkHealth, bool) {
+	oldest := t.now().Truncate(t.bucketDuration).Add(-t.bucketDuration * (sinkHealthBuckets - 1))
+	var successes, failures int
+	var latency time.Duration
+	subscriptions := map[string]struct{}{}
+	for i := range buckets {
+		if buckets[i].start.Before(oldest) {
+			continue
+		}
+		successes += buckets[i].successes
+		failures += buckets[i].failures
+		latency += buckets[i].latency
+		for subscription := range buckets[i].subscriptions {
+			subscriptions[subscription] = struct{}{}
+		}
+	}
+	deliveries := successes + failures
+	if deliveries == 0 {
+		return SinkHealth{}, false
+	}
+	successRatio := float64(successes) / float64(deliveries)
+	return SinkHealth{
+		Host:           host,
+		Deliveries:     deliveries,
+		SuccessRatio:   successRatio,
+		AverageLatency: latency / time.Duration(deliveries),
+		Subscriptions:  len(subscriptions),
+		Healthy:        deliveries < t.policy.MinDeliveries || successRatio >= t.policy.MinSuccessRatio,
+	}, true
+}
+
+// Describe implements the prometheus.Collector interface Describe method.
+func (t *sinkHealthTracker) Describe(ch chan<- *prometheus.Desc) {
+	if t == nil {
+		return
+	}
+	ch <- t.successRatio
+	ch <- t.latency
+	ch <- t.deliveries
+	ch <- t.subscriptions
+	ch <- t.healthy
+}
+
+// Collect implements the prometheus.Collector interface Collect method.
+// The metrics are computed from the rolling window on every scrape.
+func (t *sinkHealthTracker) Collect(ch chan<- prometheus.Metric) {
+	if t == nil {
+		return
+	}
+	for _, health := range t.all() {
+		healthy := 0.0
+		if health.Healthy {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(t.successRatio, prometheus.GaugeValue, health.SuccessRatio, health.Host)
+		ch <- prometheus.MustNewConstMetric(t.latency, prometheus.GaugeValue,
+			health.AverageLatency.Seconds(), health.Host)
+		ch <- prometheus.MustNewConstMetric(t.deliveries, prometheus.GaugeValue,
+			float64(health.Deliveries), health.Host)
+		ch <- prometheus.MustNewConstMetric(t.subscriptions, prometheus.GaugeValue,
+			float64(health.Subscriptions), health.Host)
+		ch <- prometheus.MustNewConstMetric(t.healthy, prometheus.GaugeValue, healthy, health.Host)
+	}
+}
+
+// SinkHost returns the host of the given sink URL, including the port if any,
+// or the sink itself if it has no host.
+func SinkHost(sink string) string {
+	u, err := url.Parse(sink)
+	if err != nil || u.Host == "" {
+		return sink
+	}
+	return u.Host
+}
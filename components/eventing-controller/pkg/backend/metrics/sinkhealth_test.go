@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector_SinkHealth(t *testing.T) {
+	// given
+	collector := NewCollector()
+	collector.SetSinkHealthPolicy(SinkHealthPolicy{Window: time.Minute, MinDeliveries: 4, MinSuccessRatio: 0.5})
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	collector.sinkHealth.now = func() time.Time { return now }
+	const failing = "http://failing.shop.svc.cluster.local:8080"
+
+	// when deliveries of several subscriptions to the same host mostly fail
+	collector.RecordSinkDelivery("shop", "orders", failing+"/orders", 100*time.Millisecond, http.StatusOK)
+	collector.RecordSinkDelivery("shop", "orders", failing+"/orders", 300*time.Millisecond, http.StatusBadGateway)
+	collector.RecordSinkDelivery("shop", "payments", failing+"/payments", 300*time.Millisecond, http.StatusBadGateway)
+	collector.RecordSinkDelivery("shop", "invoices", failing, 100*time.Millisecond, http.StatusInternalServerError)
+	collector.RecordSinkDelivery("shop", "orders", "http://healthy.shop", time.Millisecond, http.StatusNoContent)
+
+	// then the host is unhealthy once for all of them
+	health, ok := collector.SinkHealth(failing + "/anything")
+	require.True(t, ok)
+	require.Equal(t, SinkHealth{
+		Host:           "failing.shop.svc.cluster.local:8080",
+		Deliveries:     4,
+		SuccessRatio:   0.25,
+		AverageLatency: 200 * time.Millisecond,
+		Subscriptions:  3,
+		Healthy:        false,
+	}, health)
+	health, ok = collector.SinkHealth("http://healthy.shop/other")
+	require.True(t, ok)
+	require.True(t, health.Healthy)
+	_, ok = collector.SinkHealth("http://unknown.shop")
+	require.False(t, ok)
+
+	expected := `
+# HELP eventing_ec_sink_healthy The health of the sink host. ` + "`1`" + ` indicates a healthy sink host
+# TYPE eventing_ec_sink_healthy gauge
+eventing_ec_sink_healthy{sink_host="failing.shop.svc.cluster.local:8080"} 0
+eventing_ec_sink_healthy{sink_host="healthy.shop"} 1
+# HELP eventing_ec_sink_subscriptions The number of subscriptions which delivered to the sink host within the rolling window
+# TYPE eventing_ec_sink_subscriptions gauge
+eventing_ec_sink_subscriptions{sink_host="failing.shop.svc.cluster.local:8080"} 3
+eventing_ec_sink_subscriptions{sink_host="healthy.shop"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector.sinkHealth, strings.NewReader(expected),
+		sinkHealthyMetricKey, sinkSubscriptionsMetricKey))
+
+	// when the deliveries leave the rolling window
+	now = now.Add(55 * time.Second)
+	collector.RecordSinkDelivery("shop", "orders", failing, 100*time.Millisecond, http.StatusOK)
+
+	// then only the recent deliveries are scored
+	health, ok = collector.SinkHealth(failing)
+	require.True(t, ok)
+	require.Equal(t, 5, health.Deliveries)
+	now = now.Add(10 * time.Second)
+	health, ok = collector.SinkHealth(failing)
+	require.True(t, ok)
+	require.Equal(t, 1, health.Deliveries)
+	require.Equal(t, 1, health.Subscriptions)
+	require.True(t, health.Healthy)
+
+	// and the hosts without deliveries are not exposed anymore
+	require.Equal(t, 5, testutil.CollectAndCount(collector.sinkHealth))
+}
+
+func TestCollector_SinkHealthDisabled(t *testing.T) {
+	// given
+	collector := NewCollector()
+	collector.SetSinkHealthPolicy(SinkHealthPolicy{})
+
+	// when
+	collector.RecordSinkDelivery("shop", "orders", "http://sink.shop", time.Millisecond, http.StatusBadGateway)
+
+	// then
+	_, ok := collector.SinkHealth("http://sink.shop")
+	require.False(t, ok)
+	require.Nil(t, collector.sinkHealth)
+}
+
+func TestSinkHost(t *testing.T) {
+	require.Equal(t, "sink.shop:8080", SinkHost("https://sink.shop:8080/path?query"))
+	require.Equal(t, "sink.shop", SinkHost("http://sink.shop"))
+	require.Equal(t, "not a url", SinkHost("not a url"))
+}
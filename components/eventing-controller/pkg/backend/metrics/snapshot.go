@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Sample is the value of a single metric series at the time of a Snapshot.
+type Sample struct {
+	// Name is the name of the metric.
+	Name string
+	// Labels are the label values of the series.
+	Labels map[string]string
+	// Value is the value of a counter or gauge, or the sum of the observations of a histogram.
+	Value float64
+	// Count is the number of observations of a histogram.
+	Count uint64
+}
+
+// Snapshot is the list of samples of all metric series of a Collector.
+type Snapshot []Sample
+
+// NewRegistry returns a new registry with only the metrics of the Collector registered, isolated from the
+// controller-runtime registry the metrics are exposed with by RegisterMetrics.
+func (c *Collector) NewRegistry() (*prometheus.Registry, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(c); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// Snapshot returns the current values of all metric series of the Collector.
+func (c *Collector) Snapshot() (Snapshot, error) {
+	registry, err := c.NewRegistry()
+	if err != nil {
+		return nil, err
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			sample := Sample{Name: family.GetName(), Labels: map[string]string{}}
+			for _, label := range metric.GetLabel() {
+				sample.Labels[label.GetName()] = label.GetValue()
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				sample.Value = metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				sample.Value = metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				sample.Value = metric.GetHistogram().GetSampleSum()
+				sample.Count = metric.GetHistogram().GetSampleCount()
+			}
+			snapshot = append(snapshot, sample)
+		}
+	}
+	return snapshot, nil
+}
+
+// Find returns the samples of the metric with the given name having all the given label values.
+func (s Snapshot) Find(name string, labels map[string]string) Snapshot {
+	var found Snapshot
+	for _, sample := range s {
+		if sample.Name == name && sample.hasLabels(labels) {
+			found = append(found, sample)
+		}
+	}
+	return found
+}
+
+// Sum returns the sum of the values of the samples of the metric with the given name having all the given
+// label values, which for histograms is the sum of their observations.
+func (s Snapshot) Sum(name string, labels map[string]string) float64 {
+	var sum float64
+	for _, sample := range s.Find(name, labels) {
+		sum += sample.Value
+	}
+	return sum
+}
+
+func (s Sample) hasLabels(labels map[string]string) bool {
+	for name, value := range labels {
+		if s.Labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
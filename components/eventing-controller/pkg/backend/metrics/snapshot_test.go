@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector_Snapshot(t *testing.T) {
+	// given
+	collector := NewCollector()
+	otherCollector := NewCollector()
+
+	// when
+	collector.RecordDeliveryPerSubscription("ns", "sub1", "type", "sink", http.StatusOK, "")
+	collector.RecordDeliveryPerSubscription("ns", "sub1", "type", "sink", http.StatusOK, "")
+	collector.RecordDeliveryPerSubscription("ns", "sub2", "type", "sink", http.StatusBadGateway, "")
+	collector.RecordLatencyPerSubscription(2*time.Second, "sub1", "type", "sink", http.StatusOK, "")
+	collector.RecordSubscriptionReady("nats", "ns", "sub1", true)
+	otherCollector.RecordDeliveryPerSubscription("ns", "sub1", "type", "sink", http.StatusOK, "")
+	snapshot, err := collector.Snapshot()
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, snapshot.Find(deliveryMetricKey, nil), 2)
+	require.Equal(t, float64(3), snapshot.Sum(deliveryMetricKey, nil))
+	require.Equal(t, float64(2), snapshot.Sum(deliveryMetricKey, map[string]string{
+		subscriptionNameLabel: "sub1",
+		responseCodeLabel:     "200",
+	}))
+	require.Empty(t, snapshot.Find(deliveryMetricKey, map[string]string{subscriptionNameLabel: "sub3"}))
+
+	latency := snapshot.Find(latencyMetricKey, map[string]string{subscriptionNameLabel: "sub1"})
+	require.Len(t, latency, 1)
+	require.Equal(t, uint64(1), latency[0].Count)
+	require.Equal(t, float64(2), latency[0].Value)
+
+	require.Equal(t, float64(1), snapshot.Sum(subscriptionsByReadyMetricKey, map[string]string{readyLabel: "true"}))
+}
+
+func TestCollector_NewRegistry(t *testing.T) {
+	// given
+	collector := NewCollector()
+
+	// when
+	first, err := collector.NewRegistry()
+	require.NoError(t, err)
+	second, err := collector.NewRegistry()
+	require.NoError(t, err)
+
+	// then the same collector can be registered in isolated registries
+	collector.RecordNATSConnected()
+	for _, registry := range []*prometheus.Registry{first, second} {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		require.NotEmpty(t, families)
+	}
+}
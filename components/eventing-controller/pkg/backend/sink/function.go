@@ -0,0 +1,96 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	// FunctionNameLabel is the label of the Services of the serverless Functions, set to the name of the Function.
+	FunctionNameLabel = "serverless.kyma-project.io/function-name"
+
+	// functionConditionRunning is the condition of a Function which is true once its Pods serve requests.
+	functionConditionRunning = "Running"
+)
+
+var (
+	// FunctionGroupVersionKind is the kind of the serverless Functions. The Functions are read as unstructured
+	// objects, since only the conditions of their status are used.
+	FunctionGroupVersionKind = schema.GroupVersionKind{
+		Group:   "serverless.kyma-project.io",
+		Version: "v1alpha2",
+		Kind:    "Function",
+	}
+
+	ErrFunctionNotRunning = errors.New("sink function is not running")
+)
+
+// CheckFunction returns ErrFunctionNotRunning if the Function with the given name is not running yet, so that no
+// events are dispatched to it before it can accept them. It returns an error if the Function does not exist.
+func CheckFunction(ctx context.Context, reader client.Reader, namespace, name string) error {
+	function := &unstructured.Unstructured{}
+	function.SetGroupVersionKind(FunctionGroupVersionKind)
+	if err := reader.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: name}, function); err != nil {
+		return fmt.Errorf("failed to get sink function %s/%s: %w", namespace, name, err)
+	}
+	conditions, _, err := unstructured.NestedSlice(function.Object, "status", "conditions")
+	if err != nil {
+		return xerrors.Errorf("failed to read the conditions of sink function %s/%s: %v", namespace, name, err)
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != functionConditionRunning {
+			continue
+		}
+		if condition["status"] == string(corev1.ConditionTrue) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s/%s: %v", ErrFunctionNotRunning, namespace, name, condition["message"])
+	}
+	return fmt.Errorf("%w: %s/%s", ErrFunctionNotRunning, namespace, name)
+}
+
+// checkServiceFunction checks the Function of the given Service, if the Service belongs to a Function.
+// Clusters without serverless have no Functions to check.
+func checkServiceFunction(ctx context.Context, reader client.Reader, svc *corev1.Service) error {
+	name, ok := svc.Labels[FunctionNameLabel]
+	if !ok {
+		return nil
+	}
+	err := CheckFunction(ctx, reader, svc.Namespace, name)
+	if meta.IsNoMatchError(err) || k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// WatchFunctions watches the serverless Functions with the given controller, so that the Subscriptions with a
+// Function sink are reconciled when the Function starts running. The Functions are not watched if serverless is not
+// installed in the cluster. The given onSinkChange is optional.
+func WatchFunctions(mgr ctrl.Manager, ctru controller.Controller, reader client.Reader,
+	onSinkChange SinkChangeFunc) error {
+	if _, err := mgr.GetRESTMapper().RESTMapping(FunctionGroupVersionKind.GroupKind(),
+		FunctionGroupVersionKind.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	function := &unstructured.Unstructured{}
+	function.SetGroupVersionKind(FunctionGroupVersionKind)
+	// the Functions are served by the Service of their name, so their Subscriptions are found as for the Services
+	return ctru.Watch(source.Kind(mgr.GetCache(), function), NewServiceEventHandler(reader, onSinkChange))
+}
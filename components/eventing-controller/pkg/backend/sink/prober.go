@@ -0,0 +1,103 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// ProbeMethodTCP probes the sink by opening a TCP connection to it.
+	ProbeMethodTCP = "tcp"
+	// ProbeMethodHTTP probes the sink by sending an HTTP HEAD request to it.
+	ProbeMethodHTTP = "http"
+
+	defaultHTTPPort  = "80"
+	defaultHTTPSPort = "443"
+)
+
+// Prober checks whether a subscription sink is reachable.
+type Prober interface {
+	Probe(ctx context.Context, sinkURL string) error
+}
+
+// ProberFunc implements the Prober interface.
+type ProberFunc func(ctx context.Context, sinkURL string) error
+
+func (pf ProberFunc) Probe(ctx context.Context, sinkURL string) error {
+	return pf(ctx, sinkURL)
+}
+
+type tcpProber struct {
+	timeout time.Duration
+}
+
+type httpProber struct {
+	client *http.Client
+}
+
+// Perform a compile-time check.
+var _ Prober = &tcpProber{}
+var _ Prober = &httpProber{}
+
+// NewProber returns a Prober for the given probe method. It returns an error if the method is not supported.
+func NewProber(method string, timeout time.Duration) (Prober, error) {
+	switch method {
+	case ProbeMethodTCP, "":
+		return &tcpProber{timeout: timeout}, nil
+	case ProbeMethodHTTP:
+		return &httpProber{client: &http.Client{Timeout: timeout}}, nil
+	}
+	return nil, xerrors.Errorf("invalid sink probe method: %q", method)
+}
+
+// Probe opens a TCP connection to the sink host and closes it right away.
+func (p *tcpProber) Probe(ctx context.Context, sinkURL string) error {
+	address, err := hostPort(sinkURL)
+	if err != nil {
+		return err
+	}
+	dialer := net.Dialer{Timeout: p.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return xerrors.Errorf("failed to connect to sink %s: %v", address, err)
+	}
+	return conn.Close()
+}
+
+// Probe sends an HTTP HEAD request to the sink. Any HTTP response means the sink is reachable.
+func (p *httpProber) Probe(ctx context.Context, sinkURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, sinkURL, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to create probe request for sink %s: %v", sinkURL, err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("failed to send probe request to sink %s: %v", sinkURL, err)
+	}
+	return resp.Body.Close()
+}
+
+// hostPort returns the host:port address of the given sink URL using the scheme default port if none is set.
+func hostPort(sinkURL string) (string, error) {
+	u, err := url.ParseRequestURI(sinkURL)
+	if err != nil {
+		return "", xerrors.Errorf("failed to parse sink URL %s: %v", sinkURL, err)
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultHTTPPort
+		if u.Scheme == "https" {
+			port = defaultHTTPSPort
+		}
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("sink URL %s has no host", sinkURL)
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
@@ -0,0 +1,78 @@
+package sink
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProber(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	closedServer := httptest.NewServer(http.NotFoundHandler())
+	closedServer.Close()
+	defer server.Close()
+
+	testCases := []struct {
+		name      string
+		method    string
+		sinkURL   string
+		wantError bool
+	}{
+		{
+			name:      "TCP probe of a running sink",
+			method:    ProbeMethodTCP,
+			sinkURL:   server.URL,
+			wantError: false,
+		},
+		{
+			name:      "TCP probe of a stopped sink",
+			method:    ProbeMethodTCP,
+			sinkURL:   closedServer.URL,
+			wantError: true,
+		},
+		{
+			name:      "HTTP probe of a running sink returning an error status",
+			method:    ProbeMethodHTTP,
+			sinkURL:   server.URL,
+			wantError: false,
+		},
+		{
+			name:      "HTTP probe of a stopped sink",
+			method:    ProbeMethodHTTP,
+			sinkURL:   closedServer.URL,
+			wantError: true,
+		},
+		{
+			name:      "TCP probe of an invalid sink URL",
+			method:    ProbeMethodTCP,
+			sinkURL:   "invalid sink",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		testCase := tc
+		t.Run(testCase.name, func(t *testing.T) {
+			prober, err := NewProber(testCase.method, time.Second)
+			require.NoError(t, err)
+
+			// when
+			err = prober.Probe(context.Background(), testCase.sinkURL)
+
+			// then
+			require.Equal(t, testCase.wantError, err != nil, "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestNewProberWithInvalidMethod(t *testing.T) {
+	_, err := NewProber("udp", time.Second)
+	require.Error(t, err)
+}
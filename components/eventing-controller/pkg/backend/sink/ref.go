@@ -0,0 +1,102 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/utils"
+)
+
+// SinkChangeFunc is called for each Subscription whose sink Service changed, before the Subscription is enqueued.
+type SinkChangeFunc func(key types.NamespacedName)
+
+// NewServiceEventHandler returns an event handler which enqueues the Subscriptions whose sink is the changed
+// Service, so that their sink is resolved and validated again. The given onSinkChange is optional.
+func NewServiceEventHandler(reader client.Reader, onSinkChange SinkChangeFunc) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, svc client.Object) []reconcile.Request {
+		return serviceSinkRequests(ctx, reader, svc.GetNamespace(), svc.GetName(), onSinkChange)
+	})
+}
+
+// NewEndpointSliceEventHandler returns an event handler which enqueues the Subscriptions whose sink is the Service
+// of the changed EndpointSlice, so that the sink reachability is updated when the Service endpoints change.
+// The given onSinkChange is optional.
+func NewEndpointSliceEventHandler(reader client.Reader, onSinkChange SinkChangeFunc) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, slice client.Object) []reconcile.Request {
+		name, ok := slice.GetLabels()[discoveryv1.LabelServiceName]
+		if !ok {
+			return nil
+		}
+		return serviceSinkRequests(ctx, reader, slice.GetNamespace(), name, onSinkChange)
+	})
+}
+
+// NewEndpointSliceMetadata returns the object to watch the EndpointSlices with. Only their metadata is cached,
+// since the Service of an EndpointSlice is read from its labels.
+func NewEndpointSliceMetadata() *metav1.PartialObjectMetadata {
+	slice := &metav1.PartialObjectMetadata{}
+	slice.SetGroupVersionKind(discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"))
+	return slice
+}
+
+// EndpointSliceSelector selects the EndpointSlices which belong to a Service.
+func EndpointSliceSelector() labels.Selector {
+	requirement, _ := labels.NewRequirement(discoveryv1.LabelServiceName, selection.Exists, nil)
+	return labels.NewSelector().Add(*requirement)
+}
+
+// serviceSinkRequests returns the requests of the Subscriptions whose sink is the given Service.
+func serviceSinkRequests(ctx context.Context, reader client.Reader, namespace, name string,
+	onSinkChange SinkChangeFunc) []reconcile.Request {
+	subs := &v1alpha2.SubscriptionList{}
+	if err := reader.List(ctx, subs, client.InNamespace(namespace)); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, sub := range subs.Items {
+		if IsServiceSink(&sub, namespace, name) {
+			key := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}
+			if onSinkChange != nil {
+				onSinkChange(key)
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		}
+	}
+	return requests
+}
+
+// IsServiceReferenced returns true if the sinkRef of the Subscription references the given Service.
+func IsServiceReferenced(sub *v1alpha2.Subscription, namespace, name string) bool {
+	ref := sub.Spec.SinkRef
+	if ref == nil || ref.Name != name {
+		return false
+	}
+	refNamespace := ref.Namespace
+	if refNamespace == "" {
+		refNamespace = sub.Namespace
+	}
+	return refNamespace == namespace
+}
+
+// IsServiceSink returns true if the given Service is the sink of the Subscription,
+// either referenced by the sinkRef or addressed by the cluster-local sink URL.
+func IsServiceSink(sub *v1alpha2.Subscription, namespace, name string) bool {
+	if sub.Spec.SinkRef != nil {
+		return IsServiceReferenced(sub, namespace, name)
+	}
+	host, _, err := utils.GetSinkData(sub.Spec.Sink)
+	if err != nil {
+		return false
+	}
+	return host == fmt.Sprintf("%s.%s.%s", name, namespace, v1alpha2.ClusterLocalURLSuffix)
+}
@@ -0,0 +1,170 @@
+package sink
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+)
+
+func TestIsServiceReferenced(t *testing.T) {
+	newSub := func(ref *v1alpha2.SinkReference) *v1alpha2.Subscription {
+		sub := &v1alpha2.Subscription{}
+		sub.Namespace = "shop"
+		sub.Spec.SinkRef = ref
+		return sub
+	}
+
+	testCases := []struct {
+		name     string
+		givenSub *v1alpha2.Subscription
+		want     bool
+	}{
+		{
+			name:     "subscription without sinkRef",
+			givenSub: newSub(nil),
+			want:     false,
+		},
+		{
+			name:     "sinkRef to the service in the subscription namespace",
+			givenSub: newSub(&v1alpha2.SinkReference{Name: "orders"}),
+			want:     true,
+		},
+		{
+			name:     "sinkRef to the service with an explicit namespace",
+			givenSub: newSub(&v1alpha2.SinkReference{Name: "orders", Namespace: "shop"}),
+			want:     true,
+		},
+		{
+			name:     "sinkRef to another service",
+			givenSub: newSub(&v1alpha2.SinkReference{Name: "payments"}),
+			want:     false,
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.want, IsServiceReferenced(testCase.givenSub, "shop", "orders"))
+		})
+	}
+}
+
+func TestIsServiceSink(t *testing.T) {
+	newSub := func(sink string, ref *v1alpha2.SinkReference) *v1alpha2.Subscription {
+		sub := &v1alpha2.Subscription{}
+		sub.Namespace = "shop"
+		sub.Spec.Sink = sink
+		sub.Spec.SinkRef = ref
+		return sub
+	}
+
+	testCases := []struct {
+		name     string
+		givenSub *v1alpha2.Subscription
+		want     bool
+	}{
+		{
+			name:     "sinkRef to the service",
+			givenSub: newSub("", &v1alpha2.SinkReference{Name: "orders"}),
+			want:     true,
+		},
+		{
+			name:     "sink URL of the service",
+			givenSub: newSub("http://orders.shop.svc.cluster.local:8080/events", nil),
+			want:     true,
+		},
+		{
+			name:     "sink URL of another service",
+			givenSub: newSub("http://payments.shop.svc.cluster.local", nil),
+			want:     false,
+		},
+		{
+			name:     "sink URL of the service in another namespace",
+			givenSub: newSub("http://orders.other.svc.cluster.local", nil),
+			want:     false,
+		},
+		{
+			name:     "invalid sink URL",
+			givenSub: newSub("://orders", nil),
+			want:     false,
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.want, IsServiceSink(testCase.givenSub, "shop", "orders"))
+		})
+	}
+}
+
+func TestEndpointSliceEventHandler(t *testing.T) {
+	// given
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha2.AddToScheme(scheme))
+	newSub := func(name, sink string) *v1alpha2.Subscription {
+		sub := &v1alpha2.Subscription{}
+		sub.Namespace = "shop"
+		sub.Name = name
+		sub.Spec.Sink = sink
+		return sub
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newSub("orders-sub", "http://orders.shop.svc.cluster.local/events"),
+		newSub("payments-sub", "http://payments.shop.svc.cluster.local/events"),
+	).Build()
+
+	testCases := []struct {
+		name        string
+		givenLabels map[string]string
+		wantKeys    []types.NamespacedName
+	}{
+		{
+			name:        "endpointslice of the sink service",
+			givenLabels: map[string]string{discoveryv1.LabelServiceName: "orders"},
+			wantKeys:    []types.NamespacedName{{Namespace: "shop", Name: "orders-sub"}},
+		},
+		{
+			name:        "endpointslice of another service",
+			givenLabels: map[string]string{discoveryv1.LabelServiceName: "inventory"},
+		},
+		{
+			name: "endpointslice without a service",
+		},
+	}
+	for _, tc := range testCases {
+		testCase := tc
+		t.Run(testCase.name, func(t *testing.T) {
+			var changed []types.NamespacedName
+			eventHandler := NewEndpointSliceEventHandler(fakeClient, func(key types.NamespacedName) {
+				changed = append(changed, key)
+			})
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer queue.ShutDown()
+			slice := NewEndpointSliceMetadata()
+			slice.Namespace = "shop"
+			slice.Name = "orders-abcde"
+			slice.Labels = testCase.givenLabels
+
+			// when
+			eventHandler.Create(context.Background(), event.CreateEvent{Object: slice}, queue)
+
+			// then
+			require.Equal(t, testCase.wantKeys, changed)
+			require.Equal(t, len(testCase.wantKeys), queue.Len())
+			for range testCase.wantKeys {
+				item, _ := queue.Get()
+				require.Contains(t, testCase.wantKeys, item.(reconcile.Request).NamespacedName)
+				queue.Done(item)
+			}
+		})
+	}
+}
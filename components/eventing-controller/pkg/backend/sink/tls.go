@@ -0,0 +1,90 @@
+package sink
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+)
+
+// CABundleLoader loads the PEM-encoded CA bundle referenced by the sink TLS config of a subscription.
+type CABundleLoader interface {
+	Load(ctx context.Context, subscription *v1alpha2.Subscription) ([]byte, error)
+}
+
+type defaultCABundleLoader struct {
+	reader client.Reader
+}
+
+// Perform a compile-time check.
+var _ CABundleLoader = &defaultCABundleLoader{}
+
+// NewCABundleLoader returns a CABundleLoader which reads the referenced ConfigMaps and Secrets using the given reader.
+func NewCABundleLoader(reader client.Reader) CABundleLoader {
+	return &defaultCABundleLoader{reader: reader}
+}
+
+// Load returns the CA bundle referenced by the subscription, or nil if the subscription does not reference one.
+func (l *defaultCABundleLoader) Load(ctx context.Context, subscription *v1alpha2.Subscription) ([]byte, error) {
+	if subscription.Spec.SinkTLS == nil || subscription.Spec.SinkTLS.CABundleRef == nil {
+		return nil, nil
+	}
+	ref := subscription.Spec.SinkTLS.CABundleRef
+	key := ref.Key
+	if key == "" {
+		key = v1alpha2.DefaultCABundleKey
+	}
+	lookupKey := k8stypes.NamespacedName{Name: ref.Name, Namespace: subscription.Namespace}
+
+	var data []byte
+	var found bool
+	switch ref.Kind {
+	case v1alpha2.CABundleKindConfigMap:
+		cm := &corev1.ConfigMap{}
+		if err := l.reader.Get(ctx, lookupKey, cm); err != nil {
+			return nil, xerrors.Errorf("failed to get CA bundle ConfigMap %s: %v", lookupKey, err)
+		}
+		var value string
+		value, found = cm.Data[key]
+		data = []byte(value)
+	case v1alpha2.CABundleKindSecret:
+		secret := &corev1.Secret{}
+		if err := l.reader.Get(ctx, lookupKey, secret); err != nil {
+			return nil, xerrors.Errorf("failed to get CA bundle Secret %s: %v", lookupKey, err)
+		}
+		data, found = secret.Data[key]
+	default:
+		return nil, xerrors.Errorf("invalid CA bundle reference kind: %q", ref.Kind)
+	}
+	if !found {
+		return nil, xerrors.Errorf("CA bundle %s %s has no key %q", ref.Kind, lookupKey, key)
+	}
+	return data, nil
+}
+
+// NewTLSConfig returns the TLS config used to dispatch events to a sink with the given sink TLS settings.
+// The CA bundle is trusted in addition to the system trust store.
+func NewTLSConfig(sinkTLS *v1alpha2.SinkTLSConfig, caBundle []byte) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: sinkTLS != nil && sinkTLS.InsecureSkipVerify, //nolint:gosec // explicitly requested by the user
+	}
+	if len(caBundle) == 0 {
+		return tlsConfig, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(caBundle) {
+		return nil, xerrors.New("failed to parse any certificate from the CA bundle")
+	}
+	tlsConfig.RootCAs = pool
+	return tlsConfig, nil
+}
@@ -0,0 +1,158 @@
+package sink
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	controllertesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func TestCABundleLoader(t *testing.T) {
+	// given
+	namespace := "test"
+	caBundle := "-----BEGIN CERTIFICATE-----"
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{Name: "ca-cm", Namespace: namespace},
+			Data:       map[string]string{eventingv1alpha2.DefaultCABundleKey: caBundle},
+		},
+		&corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "ca-secret", Namespace: namespace},
+			Data:       map[string][]byte{"bundle.pem": []byte(caBundle)},
+		},
+	).Build()
+	loader := NewCABundleLoader(fakeClient)
+
+	testCases := []struct {
+		name          string
+		givenSinkTLS  *eventingv1alpha2.SinkTLSConfig
+		wantCABundle  []byte
+		wantErrString string
+	}{
+		{
+			name:         "Without sink TLS settings",
+			givenSinkTLS: nil,
+			wantCABundle: nil,
+		},
+		{
+			name: "With the default key of a ConfigMap",
+			givenSinkTLS: &eventingv1alpha2.SinkTLSConfig{
+				CABundleRef: &eventingv1alpha2.CABundleReference{Kind: eventingv1alpha2.CABundleKindConfigMap, Name: "ca-cm"},
+			},
+			wantCABundle: []byte(caBundle),
+		},
+		{
+			name: "With a custom key of a Secret",
+			givenSinkTLS: &eventingv1alpha2.SinkTLSConfig{
+				CABundleRef: &eventingv1alpha2.CABundleReference{
+					Kind: eventingv1alpha2.CABundleKindSecret, Name: "ca-secret", Key: "bundle.pem",
+				},
+			},
+			wantCABundle: []byte(caBundle),
+		},
+		{
+			name: "With a missing key",
+			givenSinkTLS: &eventingv1alpha2.SinkTLSConfig{
+				CABundleRef: &eventingv1alpha2.CABundleReference{Kind: eventingv1alpha2.CABundleKindSecret, Name: "ca-secret"},
+			},
+			wantErrString: "has no key",
+		},
+		{
+			name: "With a missing ConfigMap",
+			givenSinkTLS: &eventingv1alpha2.SinkTLSConfig{
+				CABundleRef: &eventingv1alpha2.CABundleReference{Kind: eventingv1alpha2.CABundleKindConfigMap, Name: "missing"},
+			},
+			wantErrString: "failed to get CA bundle ConfigMap",
+		},
+	}
+
+	for _, tC := range testCases {
+		testCase := tC
+		t.Run(testCase.name, func(t *testing.T) {
+			sub := controllertesting.NewSubscription("sub", namespace)
+			sub.Spec.SinkTLS = testCase.givenSinkTLS
+
+			// when
+			caBundle, err := loader.Load(context.Background(), sub)
+
+			// then
+			if testCase.wantErrString != "" {
+				require.ErrorContains(t, err, testCase.wantErrString)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, testCase.wantCABundle, caBundle)
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	// given
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	testCases := []struct {
+		name          string
+		givenSinkTLS  *eventingv1alpha2.SinkTLSConfig
+		givenCABundle []byte
+		wantErr       bool
+		wantReachable bool
+	}{
+		{
+			name:          "Without CA bundle the server certificate is not trusted",
+			givenSinkTLS:  &eventingv1alpha2.SinkTLSConfig{},
+			wantReachable: false,
+		},
+		{
+			name:          "With the CA bundle the server certificate is trusted",
+			givenSinkTLS:  &eventingv1alpha2.SinkTLSConfig{},
+			givenCABundle: caBundle,
+			wantReachable: true,
+		},
+		{
+			name:          "With insecure flag the server certificate is not verified",
+			givenSinkTLS:  &eventingv1alpha2.SinkTLSConfig{InsecureSkipVerify: true},
+			wantReachable: true,
+		},
+		{
+			name:          "With an invalid CA bundle",
+			givenSinkTLS:  &eventingv1alpha2.SinkTLSConfig{},
+			givenCABundle: []byte("invalid"),
+			wantErr:       true,
+		},
+	}
+
+	for _, tC := range testCases {
+		testCase := tC
+		t.Run(testCase.name, func(t *testing.T) {
+			// when
+			tlsConfig, err := NewTLSConfig(testCase.givenSinkTLS, testCase.givenCABundle)
+
+			// then
+			if testCase.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+			resp, err := client.Get(server.URL)
+			if resp != nil {
+				require.NoError(t, resp.Body.Close())
+			}
+			require.Equal(t, testCase.wantReachable, err == nil, "unexpected error: %v", err)
+		})
+	}
+}
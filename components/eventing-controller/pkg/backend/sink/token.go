@@ -0,0 +1,143 @@
+package sink
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultTokenExpiration is the requested lifetime of the ServiceAccount tokens sent to the sinks.
+	DefaultTokenExpiration = time.Hour
+
+	// tokenRefreshRatio is the share of the token lifetime after which a cached token is refreshed.
+	tokenRefreshRatio = 0.8
+)
+
+// TokenProvider returns ServiceAccount tokens bound to the given audience.
+type TokenProvider interface {
+	Token(ctx context.Context, namespace, serviceAccountName, audience string) (string, error)
+}
+
+type tokenKey struct {
+	namespace          string
+	serviceAccountName string
+	audience           string
+}
+
+type cachedToken struct {
+	token     string
+	refreshAt time.Time
+}
+
+type serviceAccountTokenProvider struct {
+	client     client.SubResourceClientConstructor
+	expiration time.Duration
+	now        func() time.Time
+
+	mutex  sync.Mutex
+	tokens map[tokenKey]cachedToken
+}
+
+// Perform a compile-time check.
+var _ TokenProvider = &serviceAccountTokenProvider{}
+
+// NewTokenProvider returns a TokenProvider which requests the tokens with the TokenRequest API
+// and caches them until most of their lifetime is elapsed.
+func NewTokenProvider(c client.SubResourceClientConstructor) TokenProvider {
+	return &serviceAccountTokenProvider{
+		client:     c,
+		expiration: DefaultTokenExpiration,
+		now:        time.Now,
+		tokens:     map[tokenKey]cachedToken{},
+	}
+}
+
+// Token returns a cached token of the ServiceAccount for the audience, or requests a new one.
+func (p *serviceAccountTokenProvider) Token(ctx context.Context,
+	namespace, serviceAccountName, audience string) (string, error) {
+	key := tokenKey{namespace: namespace, serviceAccountName: serviceAccountName, audience: audience}
+	p.mutex.Lock()
+	cached, ok := p.tokens[key]
+	p.mutex.Unlock()
+	if ok && p.now().Before(cached.refreshAt) {
+		return cached.token, nil
+	}
+
+	// the lock is not held while requesting the token, so that the dispatch of events to other sinks is not blocked
+	expirationSeconds := int64(p.expiration.Seconds())
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName, Namespace: namespace},
+	}
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         []string{audience},
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+	issuedAt := p.now()
+	if err := p.client.SubResource("token").Create(ctx, serviceAccount, tokenRequest); err != nil {
+		return "", xerrors.Errorf("failed to request a token of ServiceAccount %s/%s: %v",
+			namespace, serviceAccountName, err)
+	}
+	if tokenRequest.Status.Token == "" {
+		return "", xerrors.Errorf("empty token returned for ServiceAccount %s/%s", namespace, serviceAccountName)
+	}
+
+	// the API server may issue tokens with a different lifetime than the requested one
+	lifetime := p.expiration
+	if !tokenRequest.Status.ExpirationTimestamp.IsZero() {
+		lifetime = tokenRequest.Status.ExpirationTimestamp.Sub(issuedAt)
+	}
+	cached = cachedToken{
+		token:     tokenRequest.Status.Token,
+		refreshAt: issuedAt.Add(time.Duration(float64(lifetime) * tokenRefreshRatio)),
+	}
+	p.mutex.Lock()
+	p.tokens[key] = cached
+	p.mutex.Unlock()
+	return cached.token, nil
+}
+
+// tokenRoundTripper sets a ServiceAccount token as bearer token in the Authorization header of the requests.
+type tokenRoundTripper struct {
+	next               http.RoundTripper
+	provider           TokenProvider
+	namespace          string
+	serviceAccountName string
+	audience           string
+}
+
+// Perform a compile-time check.
+var _ http.RoundTripper = &tokenRoundTripper{}
+
+// NewTokenRoundTripper returns a RoundTripper which authenticates the requests with a token
+// of the ServiceAccount bound to the audience, before passing them to the next RoundTripper.
+func NewTokenRoundTripper(next http.RoundTripper, provider TokenProvider,
+	namespace, serviceAccountName, audience string) http.RoundTripper {
+	return &tokenRoundTripper{
+		next:               next,
+		provider:           provider,
+		namespace:          namespace,
+		serviceAccountName: serviceAccountName,
+		audience:           audience,
+	}
+}
+
+func (t *tokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.provider.Token(req.Context(), t.namespace, t.serviceAccountName, t.audience)
+	if err != nil {
+		return nil, err
+	}
+	// a RoundTripper must not modify the given request
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.next.RoundTrip(req)
+}
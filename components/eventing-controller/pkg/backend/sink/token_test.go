@@ -0,0 +1,122 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newFakeTokenClient returns a client which issues tokens named after the ServiceAccount, the audience and
+// the number of the request, with the given lifetime. It returns the given error instead if it is not nil.
+func newFakeTokenClient(lifetime time.Duration, now func() time.Time, requests *int, err error) client.Client {
+	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+		SubResourceCreate: func(_ context.Context, _ client.Client, subResourceName string,
+			obj client.Object, subResource client.Object, _ ...client.SubResourceCreateOption) error {
+			if err != nil {
+				return err
+			}
+			tokenRequest, ok := subResource.(*authenticationv1.TokenRequest)
+			if subResourceName != "token" || !ok {
+				return errors.New("unexpected sub resource")
+			}
+			*requests++
+			tokenRequest.Status.Token = obj.GetNamespace() + "/" + obj.GetName() + "/" +
+				tokenRequest.Spec.Audiences[0] + "/" + strconv.Itoa(*requests)
+			tokenRequest.Status.ExpirationTimestamp = metav1.NewTime(now().Add(lifetime))
+			return nil
+		},
+	}).Build()
+}
+
+func TestTokenProvider(t *testing.T) {
+	// given
+	now := time.Now()
+	clock := func() time.Time { return now }
+	requests := 0
+	provider := NewTokenProvider(newFakeTokenClient(time.Hour, clock, &requests, nil))
+	provider.(*serviceAccountTokenProvider).now = clock
+	ctx := context.Background()
+
+	// when
+	token, err := provider.Token(ctx, "test", "dispatcher", "https://sink")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "test/dispatcher/https://sink/1", token)
+
+	// when the token is requested again before the refresh
+	now = now.Add(47 * time.Minute)
+	token, err = provider.Token(ctx, "test", "dispatcher", "https://sink")
+
+	// then the cached token is returned
+	require.NoError(t, err)
+	require.Equal(t, "test/dispatcher/https://sink/1", token)
+	require.Equal(t, 1, requests)
+
+	// when the token is requested for another audience
+	token, err = provider.Token(ctx, "test", "dispatcher", "https://other-sink")
+
+	// then a new token is requested
+	require.NoError(t, err)
+	require.Equal(t, "test/dispatcher/https://other-sink/2", token)
+
+	// when most of the token lifetime is elapsed
+	now = now.Add(2 * time.Minute)
+	token, err = provider.Token(ctx, "test", "dispatcher", "https://sink")
+
+	// then the token is refreshed
+	require.NoError(t, err)
+	require.Equal(t, "test/dispatcher/https://sink/3", token)
+	require.Equal(t, 3, requests)
+}
+
+func TestTokenProvider_Error(t *testing.T) {
+	// given
+	requests := 0
+	provider := NewTokenProvider(newFakeTokenClient(time.Hour, time.Now, &requests, errors.New("forbidden")))
+
+	// when
+	_, err := provider.Token(context.Background(), "test", "dispatcher", "https://sink")
+
+	// then
+	require.ErrorContains(t, err, "failed to request a token of ServiceAccount test/dispatcher: forbidden")
+}
+
+func TestTokenRoundTripper(t *testing.T) {
+	// given
+	var gotAuthorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	requests := 0
+	provider := NewTokenProvider(newFakeTokenClient(time.Hour, time.Now, &requests, nil))
+	httpClient := &http.Client{
+		Transport: NewTokenRoundTripper(http.DefaultTransport, provider, "test", "dispatcher", server.URL),
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, nil)
+	require.NoError(t, err)
+
+	// when
+	resp, err := httpClient.Do(req)
+
+	// then
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "Bearer test/dispatcher/"+server.URL+"/1", gotAuthorization)
+	require.Empty(t, req.Header.Get("Authorization"))
+}
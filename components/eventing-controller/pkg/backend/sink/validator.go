@@ -2,6 +2,7 @@ package sink
 
 import (
 	"context"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
@@ -41,15 +42,32 @@ func NewValidator(ctx context.Context, client client.Client, recorder record.Eve
 }
 
 func (s defaultSinkValidator) Validate(subscription *v1alpha2.Subscription) error {
-	_, subDomains, err := utils.GetSinkData(subscription.Spec.Sink)
+	trimmedHost, subDomains, err := utils.GetSinkData(subscription.Spec.Sink)
 	if err != nil {
 		return err
 	}
+
+	// External sinks have no cluster-local svc, they were already permitted by the webhook
+	if !strings.HasSuffix(trimmedHost, v1alpha2.ClusterLocalURLSuffix) && subscription.IsExternalSinkAllowed() {
+		return nil
+	}
+
 	svcNs := subDomains[1]
 	svcName := subDomains[0]
 
+	// A Function is checked before its Service, which is only created once the Function is built.
+	// The events are held back until the Function is running, so that they are not lost to a Function not ready yet.
+	isFunctionRef := subscription.Spec.SinkRef != nil && subscription.Spec.SinkRef.IsFunction()
+	if isFunctionRef {
+		if err := CheckFunction(s.ctx, s.client, svcNs, svcName); err != nil {
+			events.Warn(s.recorder, subscription, events.ReasonSinkNotReady, "Sink function is not ready: %v", err)
+			return err
+		}
+	}
+
 	// Validate svc is a cluster-local one
-	if _, err := GetClusterLocalService(s.ctx, s.client, svcNs, svcName); err != nil {
+	svc, err := GetClusterLocalService(s.ctx, s.client, svcNs, svcName)
+	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			events.Warn(s.recorder, subscription, events.ReasonValidationFailed, "Sink does not correspond to a valid cluster local svc")
 			return xerrors.Errorf("failed to validate subscription sink URL. It is not a valid cluster local svc: %v", err)
@@ -59,6 +77,14 @@ func (s defaultSinkValidator) Validate(subscription *v1alpha2.Subscription) erro
 		return xerrors.Errorf("failed to fetch cluster-local svc for namespace '%s' and name '%s': %v", svcNs, svcName, err)
 	}
 
+	// The Function of a Service addressed by the sink URL or sinkRef is recognized by the label of the Service.
+	if !isFunctionRef {
+		if err := checkServiceFunction(s.ctx, s.client, svc); err != nil {
+			events.Warn(s.recorder, subscription, events.ReasonSinkNotReady, "Sink function is not ready: %v", err)
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -70,3 +96,16 @@ func GetClusterLocalService(ctx context.Context, client client.Client, svcNs, sv
 	}
 	return svc, nil
 }
+
+// NewServiceLookup returns a lookup for the webhook sink validation which checks the existence of the sink svc
+// using the given reader.
+func NewServiceLookup(ctx context.Context, reader client.Reader) func(namespace, name string) (bool, error) {
+	return func(namespace, name string) (bool, error) {
+		svc := &corev1.Service{}
+		err := reader.Get(ctx, k8stypes.NamespacedName{Name: name, Namespace: namespace}, svc)
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+}
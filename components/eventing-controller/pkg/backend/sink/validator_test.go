@@ -2,12 +2,14 @@ package sink
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -93,3 +95,95 @@ func TestSinkValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestSinkValidator_Function(t *testing.T) {
+	// given
+	const namespaceName = "test"
+	newFunction := func(name, running, message string) *unstructured.Unstructured {
+		function := &unstructured.Unstructured{}
+		function.SetGroupVersionKind(FunctionGroupVersionKind)
+		function.SetName(name)
+		function.SetNamespace(namespaceName)
+		require.NoError(t, unstructured.SetNestedSlice(function.Object, []interface{}{
+			map[string]interface{}{"type": "ConfigurationReady", "status": "True"},
+			map[string]interface{}{"type": functionConditionRunning, "status": running, "message": message},
+		}, "status", "conditions"))
+		return function
+	}
+	newService := func(name, functionName string) *corev1.Service {
+		svc := &corev1.Service{ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespaceName}}
+		if functionName != "" {
+			svc.Labels = map[string]string{FunctionNameLabel: functionName}
+		}
+		return svc
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		newFunction("running", "True", ""),
+		newService("running", "running"),
+		newFunction("deploying", "False", "Deployment is not ready"),
+		newService("deploying", "deploying"),
+		newFunction("unbuilt", "Unknown", ""),
+		newService("orphan", "deleted"),
+	).Build()
+	sinkValidator := NewValidator(context.Background(), fakeClient, &record.FakeRecorder{})
+
+	testCases := []struct {
+		name           string
+		givenSinkOpt   controllertesting.SubscriptionOpt
+		wantNotRunning bool
+		wantErrString  string
+	}{
+		{
+			name:         "sinkRef to a running function",
+			givenSinkOpt: controllertesting.WithFunctionSinkRef("running"),
+		},
+		{
+			name:           "sinkRef to a deploying function",
+			givenSinkOpt:   controllertesting.WithFunctionSinkRef("deploying"),
+			wantNotRunning: true,
+			wantErrString:  "Deployment is not ready",
+		},
+		{
+			name:           "sinkRef to a function without service",
+			givenSinkOpt:   controllertesting.WithFunctionSinkRef("unbuilt"),
+			wantNotRunning: true,
+		},
+		{
+			name:          "sinkRef to a missing function",
+			givenSinkOpt:  controllertesting.WithFunctionSinkRef("missing"),
+			wantErrString: "failed to get sink function test/missing",
+		},
+		{
+			name:           "sink URL of the service of a deploying function",
+			givenSinkOpt:   controllertesting.WithSink("http://deploying.test.svc.cluster.local"),
+			wantNotRunning: true,
+		},
+		{
+			name:         "sink URL of the service of a running function",
+			givenSinkOpt: controllertesting.WithSink("http://running.test.svc.cluster.local"),
+		},
+		{
+			name:         "sink URL of the service of a deleted function",
+			givenSinkOpt: controllertesting.WithSink("http://orphan.test.svc.cluster.local"),
+		},
+	}
+	for _, tC := range testCases {
+		testCase := tC
+		t.Run(testCase.name, func(t *testing.T) {
+			sub := controllertesting.NewSubscription("foo", namespaceName, testCase.givenSinkOpt)
+			sub.ResolveSink()
+
+			// when
+			err := sinkValidator.Validate(sub)
+
+			// then
+			if !testCase.wantNotRunning && testCase.wantErrString == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, testCase.wantNotRunning, errors.Is(err, ErrFunctionNotRunning))
+			require.Contains(t, err.Error(), testCase.wantErrString)
+		})
+	}
+}
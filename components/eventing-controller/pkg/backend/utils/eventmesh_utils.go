@@ -115,6 +115,9 @@ func ConvertKymaSubToEventMeshSub(subscription *eventingv1alpha2.Subscription, t
 	// set Name of EventMesh subscription
 	eventMeshSubscription.Name = nameMapper.MapSubscriptionName(subscription.Name, subscription.Namespace)
 
+	// set the owner of EventMesh subscription
+	eventMeshSubscription.Labels = GetOwnerMetadata(subscription)
+
 	// Applying protocol settings if provided in subscription CR
 	if setErr := setEventMeshProtocolSettings(subscription, eventMeshSubscription); setErr != nil {
 		return nil, setErr
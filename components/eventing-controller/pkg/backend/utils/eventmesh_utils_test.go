@@ -94,13 +94,15 @@ func TestConvertKymaSubToEventMeshSub(t *testing.T) {
 					TokenURL:     subscription.Spec.Config[eventingv1alpha2.WebhookAuthTokenURL],
 				}
 
-				return eventingtesting.NewEventMeshSubscription(
+				eventMeshSubscription := eventingtesting.NewEventMeshSubscription(
 					defaultNameMapper.MapSubscriptionName(subscription.Name, subscription.Namespace),
 					subscription.Spec.Config[eventingv1alpha2.ProtocolSettingsContentMode],
 					expectedWebhookURL,
 					bebSubEvents,
 					expectedWebhookAuth,
 				)
+				eventMeshSubscription.Labels = GetOwnerMetadata(subscription)
+				return eventMeshSubscription
 			},
 		},
 		{
@@ -116,13 +118,15 @@ func TestConvertKymaSubToEventMeshSub(t *testing.T) {
 				)
 			},
 			wantEventMeshSubscriptionFunc: func(subscription *eventingv1alpha2.Subscription) *types.Subscription {
-				return eventingtesting.NewEventMeshSubscription(
+				eventMeshSubscription := eventingtesting.NewEventMeshSubscription(
 					defaultNameMapper.MapSubscriptionName(subscription.Name, subscription.Namespace),
 					*defaultProtocolSettings.ContentMode,
 					expectedWebhookURL,
 					bebSubEvents,
 					defaultWebhookAuth, // WebhookAuth should retain defaults
 				)
+				eventMeshSubscription.Labels = GetOwnerMetadata(subscription)
+				return eventMeshSubscription
 			},
 		},
 	}
@@ -480,6 +484,12 @@ func TestGetHash(t *testing.T) {
 	hash, err := GetHash(&eventMeshSubscription)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(hash).To(BeNumerically(">", 0))
+
+	// the owner labels are not part of the hash
+	eventMeshSubscription.Labels = map[string]string{OwnerMetadataName: "name"}
+	labelledHash, err := GetHash(&eventMeshSubscription)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(labelledHash).To(Equal(hash))
 }
 
 func TestGetWebhookAuthHash(t *testing.T) {
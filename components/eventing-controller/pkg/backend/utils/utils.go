@@ -19,6 +19,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+const (
+	// The metadata keys stamped on the backend resources to identify the Subscription which owns them.
+	OwnerMetadataNamespace = "eventing.kyma-project.io/subscription-namespace"
+	OwnerMetadataName      = "eventing.kyma-project.io/subscription-name"
+	OwnerMetadataUID       = "eventing.kyma-project.io/subscription-uid"
+)
+
 type EventTypeInfo struct {
 	OriginalType  string
 	CleanType     string
@@ -32,6 +39,15 @@ type NameMapper interface {
 	MapSubscriptionName(subscriptionName, subscriptionNamespace string) string
 }
 
+// GetOwnerMetadata returns the metadata identifying the given Subscription as the owner of a backend resource.
+func GetOwnerMetadata(owner *eventingv1alpha2.Subscription) map[string]string {
+	return map[string]string{
+		OwnerMetadataNamespace: owner.Namespace,
+		OwnerMetadataName:      owner.Name,
+		OwnerMetadataUID:       string(owner.UID),
+	}
+}
+
 func APIRuleGroupVersionResource() schema.GroupVersionResource {
 	return schema.GroupVersionResource{
 		Version:  apigatewayv1beta1.GroupVersion.Version,
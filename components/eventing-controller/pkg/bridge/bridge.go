@@ -0,0 +1,201 @@
+// Package bridge forwards selected event types of the local JetStream stream to another cluster, either to the
+// publish endpoint of its event publisher proxy or to its NATS server, so that Subscriptions in the other cluster
+// receive the events published in this cluster.
+package bridge
+
+import (
+	"context"
+	"crypto/md5" // #nosec
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	backendjetstream "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+const (
+	bridgeName = "bridge"
+
+	// OriginExtension is the CloudEvents extension carrying the name of the cluster an event was bridged from.
+	// The bridge does not forward the events carrying it, so that bridges in both directions do not loop.
+	OriginExtension = "kymaorigin"
+
+	// fetchWait is the maximum time a fetch waits for new events, it bounds the time to notice a shutdown.
+	fetchWait = time.Second
+)
+
+// Forwarder publishes an event to the other cluster.
+type Forwarder interface {
+	// Forward publishes the event, which was stored under the given subject of the local stream.
+	Forward(ctx context.Context, subject string, event *cev2event.Event) error
+}
+
+// Bridge consumes the selected event types of the local stream with durable consumers and forwards them.
+// An event is acknowledged once it is forwarded, or redelivered after the RetryDelay if forwarding failed.
+type Bridge struct {
+	config     env.BridgeConfig
+	natsConfig env.NATSConfig
+	jsCtx      nats.JetStreamContext
+	forwarder  Forwarder
+	logger     *logger.Logger
+}
+
+// New returns a bridge consuming the local stream of the NATS configuration with the given JetStream context.
+func New(config env.BridgeConfig, natsConfig env.NATSConfig, jsCtx nats.JetStreamContext, forwarder Forwarder,
+	logger *logger.Logger) *Bridge {
+	return &Bridge{
+		config:     config,
+		natsConfig: natsConfig,
+		jsCtx:      jsCtx,
+		forwarder:  forwarder,
+		logger:     logger,
+	}
+}
+
+// Run forwards the events until the context is cancelled. The consumers are created again after the RetryDelay
+// as long as the stream is not available, for example while another backend is active.
+func (b *Bridge) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, eventType := range b.config.EventTypes {
+		subject := fmt.Sprintf("%s.%s", b.natsConfig.JSSubjectPrefix, eventType)
+		wg.Add(1)
+		go func(subject string) {
+			defer wg.Done()
+			b.runSubject(ctx, subject)
+		}(subject)
+	}
+	b.namedLogger().Infow("Started the bridge", "cluster", b.config.ClusterName, "eventTypes", b.config.EventTypes)
+	wg.Wait()
+	b.namedLogger().Info("Stopped the bridge")
+	return nil
+}
+
+// runSubject forwards the events of a subject until the context is cancelled.
+func (b *Bridge) runSubject(ctx context.Context, subject string) {
+	log := b.namedLogger().With("subject", subject)
+	var sub *nats.Subscription
+	for sub == nil {
+		var err error
+		if sub, err = b.subscribe(subject); err != nil {
+			log.Errorw("Failed to create the consumer of the bridge, retrying", "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(b.config.RetryDelay):
+			}
+		}
+	}
+	defer func() {
+		// the consumer is kept, so that the events published until the restart are forwarded afterwards
+		if err := sub.Drain(); err != nil {
+			log.Warnw("Failed to drain the subscription of the bridge", "error", err)
+		}
+	}()
+
+	for ctx.Err() == nil {
+		fetchCtx, cancel := context.WithTimeout(ctx, fetchWait)
+		msgs, err := sub.Fetch(b.config.MaxInFlight, nats.Context(fetchCtx))
+		cancel()
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, nats.ErrTimeout) {
+			log.Errorw("Failed to fetch the events", "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(b.config.RetryDelay):
+			}
+			continue
+		}
+		var wg sync.WaitGroup
+		for _, msg := range msgs {
+			wg.Add(1)
+			go func(msg *nats.Msg) {
+				defer wg.Done()
+				b.handle(ctx, msg, log)
+			}(msg)
+		}
+		wg.Wait()
+	}
+}
+
+// subscribe binds to the durable consumer of the subject, which is created if it does not exist yet.
+// A new consumer only forwards the events published after its creation.
+func (b *Bridge) subscribe(subject string) (*nats.Subscription, error) {
+	name := consumerName(b.config.ClusterName, subject)
+	if _, err := b.jsCtx.ConsumerInfo(b.natsConfig.JSStreamName, name); err != nil {
+		if !errors.Is(err, nats.ErrConsumerNotFound) {
+			return nil, err
+		}
+		if _, err := b.jsCtx.AddConsumer(b.natsConfig.JSStreamName, &nats.ConsumerConfig{
+			Durable:       name,
+			Description:   fmt.Sprintf("Bridge of %s forwarding %s", b.config.ClusterName, subject),
+			FilterSubject: subject,
+			DeliverPolicy: nats.DeliverNewPolicy,
+			AckPolicy:     nats.AckExplicitPolicy,
+			MaxAckPending: b.config.MaxInFlight,
+			Metadata:      map[string]string{backendjetstream.ConsumerMetadataBridgeCluster: b.config.ClusterName},
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return b.jsCtx.PullSubscribe(subject, name, nats.Bind(b.natsConfig.JSStreamName, name))
+}
+
+// handle forwards the event of the message and acknowledges it. The events bridged from another cluster
+// and the messages which are no CloudEvents are acknowledged without forwarding them.
+func (b *Bridge) handle(ctx context.Context, msg *nats.Msg, log *zap.SugaredLogger) {
+	event, err := backendutils.ConvertMsgToCE(msg)
+	if err != nil {
+		log.Errorw("Failed to convert the message to a CloudEvent, dropping it", "error", err)
+		b.ack(msg, log)
+		return
+	}
+	ceLog := log.With("id", event.ID(), "source", event.Source(), "type", event.Type())
+	if origin, ok := event.Extensions()[OriginExtension]; ok {
+		ceLog.Debugw("CloudEvent was bridged from another cluster, not forwarding it", "origin", origin)
+		b.ack(msg, log)
+		return
+	}
+	event.SetExtension(OriginExtension, b.config.ClusterName)
+
+	forwardCtx, cancel := context.WithTimeout(ctx, b.config.RequestTimeout)
+	defer cancel()
+	if err := b.forwarder.Forward(forwardCtx, msg.Subject, event); err != nil {
+		if ctx.Err() != nil {
+			// the event is redelivered to the next bridge after the AckWait
+			return
+		}
+		ceLog.Errorw("Failed to forward the CloudEvent, retrying", "error", err)
+		if nakErr := msg.NakWithDelay(b.config.RetryDelay); nakErr != nil {
+			ceLog.Errorw("Failed to nack the CloudEvent", "error", nakErr)
+		}
+		return
+	}
+	ceLog.Debugw("CloudEvent was forwarded")
+	b.ack(msg, log)
+}
+
+func (b *Bridge) ack(msg *nats.Msg, log *zap.SugaredLogger) {
+	if err := msg.Ack(); err != nil {
+		log.Errorw("Failed to ack the message", "error", err)
+	}
+}
+
+// consumerName returns the name of the durable consumer of the bridge of the given cluster and subject.
+// It uses the crypto/md5 lib to return a string of 32 characters like the consumers of the Subscriptions.
+func consumerName(clusterName, subject string) string {
+	h := md5.Sum([]byte("bridge/" + clusterName + "/" + subject)) // #nosec
+	return hex.EncodeToString(h[:])
+}
+
+func (b *Bridge) namedLogger() *zap.SugaredLogger {
+	return b.logger.WithContext().Named(bridgeName)
+}
@@ -0,0 +1,177 @@
+package bridge_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/bridge"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	testingutils "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+const (
+	streamName    = "bridge"
+	subjectPrefix = "bridge"
+)
+
+// fakeForwarder records the forwarded events and fails forwarding the events with the given ids once.
+type fakeForwarder struct {
+	mutex     sync.Mutex
+	failOnce  map[string]bool
+	forwarded []cev2event.Event
+}
+
+func (f *fakeForwarder) Forward(_ context.Context, _ string, event *cev2event.Event) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.failOnce[event.ID()] {
+		f.failOnce[event.ID()] = false
+		return errors.New("connection refused")
+	}
+	f.forwarded = append(f.forwarded, *event)
+	return nil
+}
+
+func (f *fakeForwarder) forwardedIDs() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	ids := make([]string, 0, len(f.forwarded))
+	for _, event := range f.forwarded {
+		ids = append(ids, event.ID())
+	}
+	return ids
+}
+
+func newEvent(id, eventType string, extensions map[string]string) []byte {
+	event := cev2event.New()
+	event.SetID(id)
+	event.SetSource("shop")
+	event.SetType(eventType)
+	for name, value := range extensions {
+		event.SetExtension(name, value)
+	}
+	data, _ := json.Marshal(event)
+	return data
+}
+
+func Test_Bridge_Run(t *testing.T) {
+	// given a JetStream stream and a bridge forwarding the events of the shop source
+	port, err := testingutils.GetFreePort()
+	require.NoError(t, err)
+	natsServer := testingutils.RunNatsServerOnPort(testingutils.WithPort(port), testingutils.WithJetStreamEnabled(),
+		testingutils.WithStoreDir(t.TempDir()))
+	defer testingutils.ShutDownNATSServer(natsServer)
+	conn, err := nats.Connect(natsServer.ClientURL())
+	require.NoError(t, err)
+	defer conn.Close()
+	jsCtx, err := conn.JetStream()
+	require.NoError(t, err)
+	_, err = jsCtx.AddStream(&nats.StreamConfig{Name: streamName, Subjects: []string{subjectPrefix + ".>"}})
+	require.NoError(t, err)
+
+	l, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+	forwarder := &fakeForwarder{failOnce: map[string]bool{"retried": true}}
+	b := bridge.New(env.BridgeConfig{
+		ClusterName:    "eu-1",
+		EventTypes:     []string{"shop.>"},
+		MaxInFlight:    10,
+		RequestTimeout: time.Second,
+		RetryDelay:     100 * time.Millisecond,
+	}, env.NATSConfig{JSStreamName: streamName, JSSubjectPrefix: subjectPrefix}, jsCtx, forwarder, l)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- b.Run(ctx) }()
+	require.Eventually(t, func() bool {
+		info, infoErr := jsCtx.StreamInfo(streamName)
+		return infoErr == nil && info.State.Consumers == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// when
+	for _, msg := range []struct {
+		subject string
+		data    []byte
+	}{
+		{subject: "bridge.shop.order.created.v1", data: newEvent("forwarded", "order.created.v1", nil)},
+		{subject: "bridge.shop.order.created.v1", data: newEvent("retried", "order.created.v1", nil)},
+		{subject: "bridge.shop.order.created.v1", data: newEvent("bridged", "order.created.v1",
+			map[string]string{bridge.OriginExtension: "eu-2"})},
+		{subject: "bridge.billing.invoice.created.v1", data: newEvent("not-selected", "invoice.created.v1", nil)},
+	} {
+		_, err = jsCtx.Publish(msg.subject, msg.data)
+		require.NoError(t, err)
+	}
+
+	// then the selected events are forwarded with the origin, and the failed one is retried
+	require.Eventually(t, func() bool {
+		return len(forwarder.forwardedIDs()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.ElementsMatch(t, []string{"forwarded", "retried"}, forwarder.forwardedIDs())
+	for _, event := range forwarder.forwarded {
+		require.Equal(t, "eu-1", event.Extensions()[bridge.OriginExtension])
+	}
+
+	// when the bridge is stopped
+	cancel()
+
+	// then the consumer is kept and has nothing pending
+	require.NoError(t, <-done)
+	info, err := jsCtx.StreamInfo(streamName)
+	require.NoError(t, err)
+	require.Equal(t, 1, info.State.Consumers)
+	for consumer := range jsCtx.Consumers(streamName) {
+		require.Zero(t, consumer.NumPending)
+		require.Zero(t, consumer.NumAckPending)
+	}
+	require.ElementsMatch(t, []string{"forwarded", "retried"}, forwarder.forwardedIDs())
+}
+
+func Test_HTTPForwarder_Forward(t *testing.T) {
+	// given
+	var gotHeaders http.Header
+	statusCode := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+	forwarder := bridge.NewHTTPForwarder(server.URL, server.Client())
+
+	event := cev2event.New()
+	event.SetID("1")
+	event.SetSource("shop")
+	event.SetType("sap.kyma.custom.shop.order.created.v1")
+	event.SetExtension("originaltype", "order.created.v1")
+	event.SetExtension(bridge.OriginExtension, "eu-1")
+
+	// when
+	err := forwarder.Forward(context.Background(), "kyma.shop.order.created.v1", &event)
+
+	// then the event is published with its original type
+	require.NoError(t, err)
+	require.Equal(t, "order.created.v1", gotHeaders.Get("Ce-Type"))
+	require.Empty(t, gotHeaders.Get("Ce-Originaltype"))
+	require.Equal(t, "eu-1", gotHeaders.Get("Ce-Kymaorigin"))
+	require.Equal(t, "sap.kyma.custom.shop.order.created.v1", event.Type())
+
+	// when the publisher rejects the event
+	statusCode = http.StatusServiceUnavailable
+	err = forwarder.Forward(context.Background(), "kyma.shop.order.created.v1", &event)
+
+	// then
+	require.EqualError(t, err, "publishing failed with the status code 503")
+}
@@ -0,0 +1,85 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/nats-io/nats.go"
+)
+
+// originalTypeExtension is the CloudEvents extension in which the event publisher proxy keeps the event type
+// as it was published, before adding the prefix and the source.
+const originalTypeExtension = "originaltype"
+
+var (
+	_ Forwarder = &HTTPForwarder{}
+	_ Forwarder = &JetStreamForwarder{}
+)
+
+// HTTPForwarder publishes the events to the publish endpoint of the event publisher proxy of the other cluster.
+type HTTPForwarder struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPForwarder returns a forwarder sending the events in the binary content mode to the URL.
+func NewHTTPForwarder(url string, client *http.Client) *HTTPForwarder {
+	return &HTTPForwarder{url: url, client: client}
+}
+
+// Forward publishes the event with the type as it was published in this cluster, so that the event publisher
+// proxy of the other cluster builds the event type of its stream again.
+func (f *HTTPForwarder) Forward(ctx context.Context, _ string, event *cev2event.Event) error {
+	published := event.Clone()
+	if originalType, ok := published.Extensions()[originalTypeExtension]; ok {
+		published.SetType(fmt.Sprintf("%v", originalType))
+		published.SetExtension(originalTypeExtension, nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, nil)
+	if err != nil {
+		return err
+	}
+	if err := cev2http.WriteRequest(binding.WithForceBinary(ctx), binding.ToMessage(&published), req); err != nil {
+		return err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("publishing failed with the status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// JetStreamForwarder publishes the events to the stream of the other cluster, either through a connection to its
+// NATS server or through a leafnode connected to it.
+type JetStreamForwarder struct {
+	jsCtx nats.JetStreamContext
+}
+
+// NewJetStreamForwarder returns a forwarder publishing the structured events with the JetStream context
+// of the other cluster.
+func NewJetStreamForwarder(jsCtx nats.JetStreamContext) *JetStreamForwarder {
+	return &JetStreamForwarder{jsCtx: jsCtx}
+}
+
+// Forward publishes the event under the same subject as in the local stream, so both streams must use the same
+// subject prefix.
+func (f *JetStreamForwarder) Forward(ctx context.Context, subject string, event *cev2event.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = f.jsCtx.Publish(subject, data, nats.Context(ctx))
+	return err
+}
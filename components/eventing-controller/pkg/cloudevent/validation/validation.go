@@ -0,0 +1,189 @@
+// Package validation checks CloudEvents against the CloudEvents v1.0 specification. It enforces the rules the
+// CloudEvents SDK leaves out, such as the extension naming rules and the event size, and reports every violation
+// of an event instead of only the first one. The event-publisher-proxy enforces the same rules on publishing.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+)
+
+const (
+	// PolicyStrict rejects the events violating any rule of the specification.
+	PolicyStrict = "strict"
+	// PolicyLenient rejects the events violating the rules the specification requires, and accepts the events
+	// violating the rules it only recommends.
+	PolicyLenient = "lenient"
+
+	// DefaultMaxEventSize is the size in bytes up to which the specification requires intermediaries to forward
+	// events, and which producers should not exceed.
+	DefaultMaxEventSize = 64 * 1024
+	// MaxExtensionNameLength is the length the specification recommends extension attribute names not to exceed.
+	MaxExtensionNameLength = 20
+
+	// The rules of the violations.
+	RuleRequiredAttribute = "required_attribute"
+	RuleExtensionName     = "extension_name"
+	RuleEventSize         = "event_size"
+)
+
+var (
+	ErrInvalidPolicy = errors.New("invalid validation policy")
+
+	// reservedExtensionNames are the names extension attributes must not use.
+	reservedExtensionNames = map[string]bool{"data": true}
+)
+
+// Violation is a violation of a rule of the CloudEvents specification.
+type Violation struct {
+	// Rule is the rule which is violated, such as RuleExtensionName.
+	Rule string
+	// Attribute is the name of the attribute violating the rule, empty for the rules of the whole event.
+	Attribute string
+	// Message describes the violation.
+	Message string
+	// Required is true if the specification requires the rule with MUST, and false if it only recommends it.
+	Required bool
+}
+
+func (v Violation) String() string {
+	if v.Attribute == "" {
+		return v.Message
+	}
+	return fmt.Sprintf("%s: %s", v.Attribute, v.Message)
+}
+
+// Error is the error of an event rejected by the validation policy, listing all its violations.
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.String())
+	}
+	return "invalid CloudEvent: " + strings.Join(messages, "; ")
+}
+
+// Validator checks the events against the specification and rejects them according to its policy.
+type Validator struct {
+	policy       string
+	maxEventSize int
+}
+
+// NewValidator returns a Validator with the given policy, which rejects the events larger than the given size
+// in bytes with the strict policy. The size is not checked if it is 0.
+func NewValidator(policy string, maxEventSize int) (*Validator, error) {
+	if policy != PolicyStrict && policy != PolicyLenient {
+		return nil, fmt.Errorf("%w: %q, must be either %s or %s", ErrInvalidPolicy, policy, PolicyStrict,
+			PolicyLenient)
+	}
+	if maxEventSize < 0 {
+		return nil, fmt.Errorf("invalid maximum event size: %d, must not be negative", maxEventSize)
+	}
+	return &Validator{policy: policy, maxEventSize: maxEventSize}, nil
+}
+
+// Validate returns the violations of the event, and an *Error listing them if the policy rejects the event.
+func (v *Validator) Validate(event *cev2event.Event) ([]Violation, error) {
+	violations := Violations(event, v.maxEventSize)
+	for _, violation := range violations {
+		if violation.Required || v.policy == PolicyStrict {
+			return violations, &Error{Violations: violations}
+		}
+	}
+	return violations, nil
+}
+
+// Violations returns the violations of the specification by the event. The size is not checked if it is 0.
+func Violations(event *cev2event.Event, maxEventSize int) []Violation {
+	var violations []Violation
+	if version := event.SpecVersion(); version != cev2event.CloudEventsVersionV1 {
+		violations = append(violations, Violation{
+			Rule:      RuleRequiredAttribute,
+			Attribute: "specversion",
+			Message:   fmt.Sprintf("MUST be %s, got %q", cev2event.CloudEventsVersionV1, version),
+			Required:  true,
+		})
+	}
+	for name, value := range map[string]string{"id": event.ID(), "source": event.Source(), "type": event.Type()} {
+		if strings.TrimSpace(value) == "" {
+			violations = append(violations, Violation{
+				Rule:      RuleRequiredAttribute,
+				Attribute: name,
+				Message:   "MUST be a non-empty string",
+				Required:  true,
+			})
+		}
+	}
+	for name := range event.Extensions() {
+		violations = append(violations, extensionNameViolations(name)...)
+	}
+	if size := Size(event); maxEventSize > 0 && size > maxEventSize {
+		violations = append(violations, Violation{
+			Rule:    RuleEventSize,
+			Message: fmt.Sprintf("event size of %d bytes SHOULD NOT exceed %d bytes", size, maxEventSize),
+		})
+	}
+	// the violations are sorted by their attributes, so that the errors are stable
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Attribute < violations[j].Attribute })
+	return violations
+}
+
+// extensionNameViolations returns the violations of the naming rules by the extension attribute name. The SDK
+// already restricts the names to lower-case letters and digits.
+func extensionNameViolations(name string) []Violation {
+	var violations []Violation
+	if reservedExtensionNames[name] {
+		violations = append(violations, Violation{
+			Rule:      RuleExtensionName,
+			Attribute: name,
+			Message:   "extension attribute names MUST NOT be reserved",
+			Required:  true,
+		})
+	}
+	if len(name) > MaxExtensionNameLength {
+		violations = append(violations, Violation{
+			Rule:      RuleExtensionName,
+			Attribute: name,
+			Message:   fmt.Sprintf("extension attribute names SHOULD NOT exceed %d characters", MaxExtensionNameLength),
+		})
+	}
+	return violations
+}
+
+// Size returns the size of the event in bytes as the size of its data and of the names and values of its attributes.
+func Size(event *cev2event.Event) int {
+	size := len(event.Data())
+	attributes := map[string]string{
+		"specversion": event.SpecVersion(),
+		"id":          event.ID(),
+		"source":      event.Source(),
+		"type":        event.Type(),
+	}
+	if value := event.DataContentType(); value != "" {
+		attributes["datacontenttype"] = value
+	}
+	if value := event.DataSchema(); value != "" {
+		attributes["dataschema"] = value
+	}
+	if value := event.Subject(); value != "" {
+		attributes["subject"] = value
+	}
+	if value := event.Time(); !value.IsZero() {
+		attributes["time"] = value.Format(time.RFC3339Nano)
+	}
+	for name, value := range event.Extensions() {
+		attributes[name] = fmt.Sprint(value)
+	}
+	for name, value := range attributes {
+		size += len(name) + len(value)
+	}
+	return size
+}
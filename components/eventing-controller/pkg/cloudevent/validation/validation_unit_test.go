@@ -0,0 +1,129 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+)
+
+func newEvent(t *testing.T, data string, extensions map[string]string) *cev2event.Event {
+	t.Helper()
+	event := cev2event.New(cev2event.CloudEventsVersionV1)
+	event.SetID("00000000-0000-0000-0000-000000000000")
+	event.SetSource("/default/sap.kyma/shop")
+	event.SetType("order.created.v1")
+	require.NoError(t, event.SetData(cev2event.ApplicationJSON, []byte(data)))
+	for name, value := range extensions {
+		require.NoError(t, event.Context.SetExtension(name, value))
+	}
+	return &event
+}
+
+func Test_Validate(t *testing.T) {
+	largeData := `"` + strings.Repeat("a", DefaultMaxEventSize) + `"`
+	testCases := []struct {
+		name             string
+		event            func(t *testing.T) *cev2event.Event
+		wantViolations   []string
+		wantLenientError bool
+		wantStrictError  bool
+	}{
+		{
+			name: "should accept a valid event",
+			event: func(t *testing.T) *cev2event.Event {
+				return newEvent(t, `{"key":"value"}`, map[string]string{"correlationid": "123"})
+			},
+		},
+		{
+			name: "should report all missing required attributes",
+			event: func(t *testing.T) *cev2event.Event {
+				event := newEvent(t, `{}`, nil)
+				// the SDK setters refuse empty values
+				event.Context.(*cev2event.EventContextV1).ID = " "
+				event.Context.(*cev2event.EventContextV1).Type = ""
+				return event
+			},
+			wantViolations:   []string{"id: MUST be a non-empty string", "type: MUST be a non-empty string"},
+			wantLenientError: true,
+			wantStrictError:  true,
+		},
+		{
+			name: "should reject an event of another specification version",
+			event: func(t *testing.T) *cev2event.Event {
+				event := newEvent(t, `{}`, nil)
+				event.SetSpecVersion(cev2event.CloudEventsVersionV03)
+				return event
+			},
+			wantViolations:   []string{`specversion: MUST be 1.0, got "0.3"`},
+			wantLenientError: true,
+			wantStrictError:  true,
+		},
+		{
+			name: "should reject reserved extension names",
+			event: func(t *testing.T) *cev2event.Event {
+				return newEvent(t, `{}`, map[string]string{"data": "value"})
+			},
+			wantViolations:   []string{"data: extension attribute names MUST NOT be reserved"},
+			wantLenientError: true,
+			wantStrictError:  true,
+		},
+		{
+			name: "should reject long extension names only with the strict policy",
+			event: func(t *testing.T) *cev2event.Event {
+				return newEvent(t, `{}`, map[string]string{"averyverylongextension": "value"})
+			},
+			wantViolations: []string{
+				"averyverylongextension: extension attribute names SHOULD NOT exceed 20 characters",
+			},
+			wantStrictError: true,
+		},
+		{
+			name: "should reject large events only with the strict policy",
+			event: func(t *testing.T) *cev2event.Event {
+				return newEvent(t, largeData, nil)
+			},
+			wantViolations:  []string{"event size of 65669 bytes SHOULD NOT exceed 65536 bytes"},
+			wantStrictError: true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			for policy, wantErr := range map[string]bool{
+				PolicyLenient: tc.wantLenientError,
+				PolicyStrict:  tc.wantStrictError,
+			} {
+				// given
+				validator, err := NewValidator(policy, DefaultMaxEventSize)
+				require.NoError(t, err)
+
+				// when
+				violations, err := validator.Validate(tc.event(t))
+
+				// then
+				var messages []string
+				for _, violation := range violations {
+					messages = append(messages, violation.String())
+				}
+				require.Equal(t, tc.wantViolations, messages, policy)
+				if !wantErr {
+					require.NoError(t, err, policy)
+					continue
+				}
+				var validationErr *Error
+				require.True(t, errors.As(err, &validationErr), policy)
+				require.Equal(t, "invalid CloudEvent: "+strings.Join(tc.wantViolations, "; "), err.Error())
+			}
+		})
+	}
+}
+
+func Test_NewValidator(t *testing.T) {
+	_, err := NewValidator("loose", DefaultMaxEventSize)
+	require.ErrorIs(t, err, ErrInvalidPolicy)
+	_, err = NewValidator(PolicyStrict, -1)
+	require.Error(t, err)
+}
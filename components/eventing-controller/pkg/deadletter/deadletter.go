@@ -0,0 +1,383 @@
+// Package deadletter serves the admin endpoints to list, requeue and purge the events of the dead-letter stream,
+// to which the JetStream backend moves the events after their last failed delivery.
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/tokenauth"
+)
+
+const (
+	// Endpoint is the path of the endpoint listing the events of the dead-letter stream.
+	Endpoint = "/admin/deadletters"
+	// RequeueEndpoint is the path of the endpoint publishing events of the dead-letter stream to their original
+	// subjects again, and removing them from the dead-letter stream.
+	RequeueEndpoint = Endpoint + "/requeue"
+	// PurgeEndpoint is the path of the endpoint removing events from the dead-letter stream.
+	PurgeEndpoint = Endpoint + "/purge"
+
+	// HeaderPrefix is the prefix of the headers added to the original headers of a dead-lettered event.
+	HeaderPrefix = "Kyma-Dead-Letter-"
+	// HeaderSubject is the header of a dead-lettered event with its original subject.
+	HeaderSubject = HeaderPrefix + "Subject"
+	// HeaderSubscription is the header of a dead-lettered event with the namespaced name of the Subscription
+	// it failed to be delivered to.
+	HeaderSubscription = HeaderPrefix + "Subscription"
+	// HeaderSink is the header of a dead-lettered event with the sink it failed to be delivered to.
+	HeaderSink = HeaderPrefix + "Sink"
+	// HeaderStatus is the header of a dead-lettered event with the HTTP status of its last delivery.
+	HeaderStatus = HeaderPrefix + "Status"
+	// HeaderDeliveries is the header of a dead-lettered event with the number of its deliveries.
+	HeaderDeliveries = HeaderPrefix + "Deliveries"
+	// HeaderRequeuedFor is the header of a requeued event with the namespaced name of the Subscription it failed to
+	// be delivered to. The other Subscriptions of its subject skip the requeued event.
+	HeaderRequeuedFor = "Kyma-Requeued-For"
+
+	// DefaultLimit is the default number of events listed per page.
+	DefaultLimit = 50
+	// MaxLimit is the maximum number of events listed per page.
+	MaxLimit = 1000
+
+	// fromQueryParameter is the sequence the events are listed from.
+	fromQueryParameter = "from"
+	// limitQueryParameter is the maximum number of listed events.
+	limitQueryParameter = "limit"
+
+	// readIdleTimeout is the time to wait for the next event of the dead-letter stream while listing them.
+	readIdleTimeout = 2 * time.Second
+
+	handlerName = "dead-letter"
+)
+
+var (
+	ErrDisabled    = errors.New("the dead-letter stream is disabled or the JetStream backend is not running")
+	ErrNoSequences = errors.New("no sequences given")
+	ErrNoSubject   = errors.New("the event has no original subject")
+	ErrRequeueAll  = errors.New("requeueing all events is not supported, list and requeue them by sequence")
+	ErrDuplicate   = errors.New("the requeued event was dropped by JetStream as a duplicate")
+)
+
+// Source provides the JetStream context and the name of the dead-letter stream.
+type Source interface {
+	// DeadLetterStream returns the current JetStream context and the name of the dead-letter stream, or a nil context
+	// if the JetStream backend is not running or the dead-letter stream is disabled.
+	DeadLetterStream() (nats.JetStreamContext, string)
+}
+
+// Event is an event of the dead-letter stream.
+type Event struct {
+	Sequence uint64 `json:"sequence"`
+	// DeadLettered is the time the event was moved to the dead-letter stream.
+	DeadLettered time.Time `json:"deadLettered"`
+	// Subject is the original subject of the event.
+	Subject      string `json:"subject"`
+	Subscription string `json:"subscription,omitempty"`
+	Sink         string `json:"sink,omitempty"`
+	Status       int    `json:"status,omitempty"`
+	Deliveries   int    `json:"deliveries,omitempty"`
+	ID           string `json:"id,omitempty"`
+	Type         string `json:"type,omitempty"`
+	Source       string `json:"source,omitempty"`
+}
+
+// ListResponse is the response of the Endpoint.
+type ListResponse struct {
+	Events []Event `json:"events"`
+	// Next is the sequence to list the next page from, or 0 if there are no more events.
+	Next uint64 `json:"next,omitempty"`
+}
+
+// Request is the request of the RequeueEndpoint and the PurgeEndpoint.
+type Request struct {
+	// Sequences are the sequences of the events in the dead-letter stream.
+	Sequences []uint64 `json:"sequences,omitempty"`
+	// All purges all events of the dead-letter stream instead of the given sequences. It is not supported by the
+	// RequeueEndpoint, since the events would be dead-lettered again if the sink is not fixed.
+	All bool `json:"all,omitempty"`
+}
+
+// Response is the response of the RequeueEndpoint and the PurgeEndpoint.
+type Response struct {
+	// Results are the results per requested sequence.
+	Results []Result `json:"results,omitempty"`
+	// Purged is the number of events purged with Request.All.
+	Purged uint64 `json:"purged,omitempty"`
+}
+
+// Result is the result of requeueing or purging a single event.
+type Result struct {
+	Sequence uint64 `json:"sequence"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Handlers returns the handlers of the Endpoint, RequeueEndpoint and PurgeEndpoint by path. All of them require
+// the bearer token stored in the given token file, which is read on every request to follow its rotation.
+func Handlers(source Source, tokenFile string, logger *logger.Logger) map[string]http.Handler {
+	namedLogger := logger.WithContext().Named(handlerName)
+	h := &handler{source: source, logger: namedLogger}
+	return map[string]http.Handler{
+		Endpoint:        tokenauth.RequireToken(handlerName, tokenFile, http.HandlerFunc(h.list), namedLogger),
+		RequeueEndpoint: tokenauth.RequireToken(handlerName, tokenFile, http.HandlerFunc(h.requeue), namedLogger),
+		PurgeEndpoint:   tokenauth.RequireToken(handlerName, tokenFile, http.HandlerFunc(h.purge), namedLogger),
+	}
+}
+
+type handler struct {
+	source Source
+	logger *zap.SugaredLogger
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	from, err := queryUint(r, fromQueryParameter, 1)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, err := queryUint(r, limitQueryParameter, DefaultLimit)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		http.Error(w, fmt.Sprintf("%s must be between 1 and %d", limitQueryParameter, MaxLimit),
+			http.StatusBadRequest)
+		return
+	}
+	jsCtx, stream := h.source.DeadLetterStream()
+	if jsCtx == nil {
+		http.Error(w, ErrDisabled.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	response, err := List(jsCtx, stream, from, int(limit))
+	if err != nil {
+		h.logger.Errorw("Failed to list the dead-lettered events", "stream", stream, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, response)
+}
+
+func (h *handler) requeue(w http.ResponseWriter, r *http.Request) {
+	h.serveRequest(w, r, func(jsCtx nats.JetStreamContext, stream string, request Request) (Response, error) {
+		if request.All {
+			return Response{}, ErrRequeueAll
+		}
+		return Requeue(jsCtx, stream, request.Sequences)
+	})
+}
+
+func (h *handler) purge(w http.ResponseWriter, r *http.Request) {
+	h.serveRequest(w, r, func(jsCtx nats.JetStreamContext, stream string, request Request) (Response, error) {
+		if request.All {
+			return PurgeAll(jsCtx, stream)
+		}
+		return Purge(jsCtx, stream, request.Sequences)
+	})
+}
+
+// serveRequest decodes the request of the RequeueEndpoint or the PurgeEndpoint and runs it.
+func (h *handler) serveRequest(w http.ResponseWriter, r *http.Request,
+	run func(jsCtx nats.JetStreamContext, stream string, request Request) (Response, error)) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var request Request
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	jsCtx, stream := h.source.DeadLetterStream()
+	if jsCtx == nil {
+		http.Error(w, ErrDisabled.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	response, err := run(jsCtx, stream, request)
+	if errors.Is(err, ErrNoSequences) || errors.Is(err, ErrRequeueAll) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		h.logger.Errorw("Failed to process the dead-lettered events", "path", r.URL.Path, "stream", stream,
+			"error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, result := range response.Results {
+		if result.Error != "" {
+			h.logger.Warnw("Failed to process a dead-lettered event", "path", r.URL.Path, "stream", stream,
+				"sequence", result.Sequence, "error", result.Error)
+		}
+	}
+	h.logger.Infow("Processed dead-lettered events", "path", r.URL.Path, "stream", stream,
+		"events", len(response.Results), "purged", response.Purged)
+	writeJSON(w, response)
+}
+
+// List returns at most limit events of the dead-letter stream, starting from the given sequence.
+func List(jsCtx nats.JetStreamContext, stream string, from uint64, limit int) (ListResponse, error) {
+	response := ListResponse{Events: []Event{}}
+	info, err := jsCtx.StreamInfo(stream)
+	if err != nil {
+		return response, err
+	}
+	if info.State.Msgs == 0 || info.State.LastSeq < from {
+		return response, nil
+	}
+
+	// an ordered consumer reads the stream without storing any state on the server
+	sub, err := jsCtx.SubscribeSync("", nats.BindStream(stream), nats.OrderedConsumer(),
+		nats.StartSequence(from))
+	if err != nil {
+		return response, err
+	}
+	defer func() { _ = sub.Unsubscribe() }()
+	for len(response.Events) < limit {
+		msg, err := sub.NextMsg(readIdleTimeout)
+		if errors.Is(err, nats.ErrTimeout) {
+			break
+		} else if err != nil {
+			return response, err
+		}
+		metadata, err := msg.Metadata()
+		if err != nil {
+			return response, err
+		}
+		response.Events = append(response.Events, toEvent(metadata.Sequence.Stream, metadata.Timestamp,
+			msg.Header, msg.Data))
+		if metadata.NumPending == 0 || metadata.Sequence.Stream >= info.State.LastSeq {
+			response.Next = 0
+			return response, nil
+		}
+		response.Next = metadata.Sequence.Stream + 1
+	}
+	return response, nil
+}
+
+// Requeue publishes the events of the given sequences to their original subjects with their original headers except
+// the message ID, and deletes them from the dead-letter stream. A requeued event is only delivered to the
+// Subscription it failed to be delivered to, see IsRequeuedFor.
+func Requeue(jsCtx nats.JetStreamContext, stream string, sequences []uint64) (Response, error) {
+	return forEach(sequences, func(sequence uint64) error {
+		msg, err := jsCtx.GetMsg(stream, sequence)
+		if err != nil {
+			return err
+		}
+		subject := msg.Header.Get(HeaderSubject)
+		if subject == "" {
+			return ErrNoSubject
+		}
+		header := nats.Header{}
+		for key, values := range msg.Header {
+			if !strings.HasPrefix(key, HeaderPrefix) {
+				header[key] = values
+			}
+		}
+		// the original message ID would deduplicate the event requeued within the duplicate window of the stream
+		header.Del(nats.MsgIdHdr)
+		header.Del(HeaderRequeuedFor)
+		if subscription := msg.Header.Get(HeaderSubscription); subscription != "" {
+			header.Set(HeaderRequeuedFor, subscription)
+		}
+		ack, err := jsCtx.PublishMsg(&nats.Msg{Subject: subject, Header: header, Data: msg.Data})
+		if err != nil {
+			return err
+		}
+		if ack.Duplicate {
+			return ErrDuplicate
+		}
+		return jsCtx.DeleteMsg(stream, sequence)
+	})
+}
+
+// IsRequeuedFor returns true if the event with the given headers is delivered to the given Subscription, which is
+// false only for an event requeued for another Subscription.
+func IsRequeuedFor(header nats.Header, namespace, name string) bool {
+	requeuedFor := header.Get(HeaderRequeuedFor)
+	return requeuedFor == "" || requeuedFor == namespace+"/"+name
+}
+
+// Purge deletes the events of the given sequences from the dead-letter stream.
+func Purge(jsCtx nats.JetStreamContext, stream string, sequences []uint64) (Response, error) {
+	return forEach(sequences, func(sequence uint64) error {
+		return jsCtx.DeleteMsg(stream, sequence)
+	})
+}
+
+// PurgeAll deletes all events from the dead-letter stream.
+func PurgeAll(jsCtx nats.JetStreamContext, stream string) (Response, error) {
+	info, err := jsCtx.StreamInfo(stream)
+	if err != nil {
+		return Response{}, err
+	}
+	if err = jsCtx.PurgeStream(stream); err != nil {
+		return Response{}, err
+	}
+	return Response{Purged: info.State.Msgs}, nil
+}
+
+// forEach runs the given function for each sequence and collects the results.
+func forEach(sequences []uint64, run func(sequence uint64) error) (Response, error) {
+	if len(sequences) == 0 {
+		return Response{}, ErrNoSequences
+	}
+	response := Response{Results: make([]Result, 0, len(sequences))}
+	for _, sequence := range sequences {
+		result := Result{Sequence: sequence}
+		if err := run(sequence); err != nil {
+			result.Error = err.Error()
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response, nil
+}
+
+// toEvent returns the Event of a message of the dead-letter stream.
+func toEvent(sequence uint64, deadLettered time.Time, header nats.Header, data []byte) Event {
+	event := Event{
+		Sequence:     sequence,
+		DeadLettered: deadLettered,
+		Subject:      header.Get(HeaderSubject),
+		Subscription: header.Get(HeaderSubscription),
+		Sink:         header.Get(HeaderSink),
+	}
+	event.Status, _ = strconv.Atoi(header.Get(HeaderStatus))
+	event.Deliveries, _ = strconv.Atoi(header.Get(HeaderDeliveries))
+	// the events are stored in the structured mode with the attributes in the JSON data
+	var attributes struct {
+		ID     string `json:"id"`
+		Type   string `json:"type"`
+		Source string `json:"source"`
+	}
+	if err := json.Unmarshal(data, &attributes); err == nil {
+		event.ID, event.Type, event.Source = attributes.ID, attributes.Type, attributes.Source
+	}
+	return event
+}
+
+func queryUint(r *http.Request, name string, defaultValue uint64) (uint64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a positive number, got %q", name, value)
+	}
+	return parsed, nil
+}
+
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
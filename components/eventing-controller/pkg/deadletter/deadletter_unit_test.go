@@ -0,0 +1,308 @@
+//go:build unit
+
+package deadletter //nolint:testpackage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	testingutils "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+const (
+	token      = "secret-token"
+	streamName = "deadletter"
+	events     = 5
+)
+
+type sourceFunc func() (nats.JetStreamContext, string)
+
+func (f sourceFunc) DeadLetterStream() (nats.JetStreamContext, string) {
+	return f()
+}
+
+// setupDeadLetterStream starts a NATS server with a stream of original events and a dead-letter stream holding
+// the given number of dead-lettered events, and returns the JetStream context.
+func setupDeadLetterStream(t *testing.T) nats.JetStreamContext {
+	t.Helper()
+	port, err := testingutils.GetFreePort()
+	require.NoError(t, err)
+	natsServer := testingutils.RunNatsServerOnPort(testingutils.WithPort(port), testingutils.WithJetStreamEnabled(),
+		testingutils.WithStoreDir(t.TempDir()))
+	t.Cleanup(func() { testingutils.ShutDownNATSServer(natsServer) })
+	conn, err := nats.Connect(natsServer.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+	jsCtx, err := conn.JetStream()
+	require.NoError(t, err)
+
+	_, err = jsCtx.AddStream(&nats.StreamConfig{Name: "kyma", Subjects: []string{"kyma.>"}})
+	require.NoError(t, err)
+	_, err = jsCtx.AddStream(&nats.StreamConfig{Name: streamName, Subjects: []string{"deadletter.>"}})
+	require.NoError(t, err)
+	for i := 1; i <= events; i++ {
+		header := nats.Header{}
+		header.Set("Correlation-Id", strconv.Itoa(i))
+		header.Set(HeaderSubject, "kyma.order.created.v1")
+		header.Set(HeaderSubscription, "shop/orders")
+		header.Set(HeaderSink, "http://orders.shop")
+		header.Set(HeaderStatus, "502")
+		header.Set(HeaderDeliveries, "100")
+		header.Set(nats.MsgIdHdr, "kyma.order.created.v1/"+strconv.Itoa(i))
+		_, err = jsCtx.PublishMsg(&nats.Msg{
+			Subject: "deadletter.kyma.order.created.v1",
+			Header:  header,
+			Data:    []byte(`{"id":"` + strconv.Itoa(i) + `","type":"order.created.v1","source":"shop"}`),
+		})
+		require.NoError(t, err)
+	}
+	return jsCtx
+}
+
+func newHandlers(t *testing.T, jsCtx nats.JetStreamContext) *http.ServeMux {
+	t.Helper()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte(token+"\n"), 0o600))
+	defaultLogger, err := logger.New("json", "info")
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	source := sourceFunc(func() (nats.JetStreamContext, string) { return jsCtx, streamName })
+	for path, handler := range Handlers(source, tokenFile, defaultLogger) {
+		mux.Handle(path, handler)
+	}
+	return mux
+}
+
+func serve(mux *http.ServeMux, method, target, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer "+token)
+	mux.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func Test_List(t *testing.T) {
+	// given
+	mux := newHandlers(t, setupDeadLetterStream(t))
+
+	// when the first page is listed
+	recorder := serve(mux, http.MethodGet, Endpoint+"?limit=3", "")
+
+	// then
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var page ListResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&page))
+	require.Len(t, page.Events, 3)
+	require.Equal(t, uint64(4), page.Next)
+	event := page.Events[0]
+	require.False(t, event.DeadLettered.IsZero())
+	event.DeadLettered = page.Events[0].DeadLettered.UTC().Truncate(0)
+	require.Equal(t, Event{
+		Sequence:     1,
+		DeadLettered: event.DeadLettered,
+		Subject:      "kyma.order.created.v1",
+		Subscription: "shop/orders",
+		Sink:         "http://orders.shop",
+		Status:       http.StatusBadGateway,
+		Deliveries:   100,
+		ID:           "1",
+		Type:         "order.created.v1",
+		Source:       "shop",
+	}, event)
+
+	// when the next page is listed
+	recorder = serve(mux, http.MethodGet, Endpoint+"?limit=3&from="+strconv.FormatUint(page.Next, 10), "")
+
+	// then it holds the remaining events
+	require.Equal(t, http.StatusOK, recorder.Code)
+	page = ListResponse{}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&page))
+	require.Len(t, page.Events, 2)
+	require.Equal(t, uint64(4), page.Events[0].Sequence)
+	require.Zero(t, page.Next)
+}
+
+func Test_RequeueAndPurge(t *testing.T) {
+	// given
+	jsCtx := setupDeadLetterStream(t)
+	mux := newHandlers(t, jsCtx)
+
+	// when two events are requeued, one of them twice
+	recorder := serve(mux, http.MethodPost, RequeueEndpoint, `{"sequences":[1,2,2]}`)
+
+	// then they are published to the original subject with the original headers
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var response Response
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.Len(t, response.Results, 3)
+	require.Empty(t, response.Results[0].Error)
+	require.Empty(t, response.Results[1].Error)
+	require.NotEmpty(t, response.Results[2].Error)
+	msg, err := jsCtx.GetMsg("kyma", 1)
+	require.NoError(t, err)
+	require.Equal(t, "kyma.order.created.v1", msg.Subject)
+	require.Equal(t, "1", msg.Header.Get("Correlation-Id"))
+	require.Empty(t, msg.Header.Get(HeaderSubject))
+	require.Empty(t, msg.Header.Get(nats.MsgIdHdr))
+	require.Equal(t, "shop/orders", msg.Header.Get(HeaderRequeuedFor))
+	requireEvents(t, jsCtx, events-2)
+
+	// when an event is requeued within the duplicate window of its original message ID
+	header := nats.Header{}
+	header.Set(nats.MsgIdHdr, "kyma.order.created.v1/4")
+	_, err = jsCtx.PublishMsg(&nats.Msg{Subject: "kyma.order.created.v1", Header: header})
+	require.NoError(t, err)
+	recorder = serve(mux, http.MethodPost, RequeueEndpoint, `{"sequences":[4]}`)
+
+	// then it is not deduplicated
+	require.Equal(t, http.StatusOK, recorder.Code)
+	response = Response{}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.Len(t, response.Results, 1)
+	require.Empty(t, response.Results[0].Error)
+	info, err := jsCtx.StreamInfo("kyma")
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), info.State.Msgs)
+	requireEvents(t, jsCtx, events-3)
+
+	// when an event is purged
+	recorder = serve(mux, http.MethodPost, PurgeEndpoint, `{"sequences":[3]}`)
+
+	// then
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireEvents(t, jsCtx, events-4)
+
+	// when all events are purged
+	recorder = serve(mux, http.MethodPost, PurgeEndpoint, `{"all":true}`)
+
+	// then
+	require.Equal(t, http.StatusOK, recorder.Code)
+	response = Response{}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.Equal(t, uint64(events-4), response.Purged)
+	requireEvents(t, jsCtx, 0)
+}
+
+func Test_Handlers_Errors(t *testing.T) {
+	mux := newHandlers(t, setupDeadLetterStream(t))
+	disabled := newHandlers(t, nil)
+
+	testCases := []struct {
+		name       string
+		mux        *http.ServeMux
+		method     string
+		target     string
+		body       string
+		noToken    bool
+		wantStatus int
+	}{
+		{
+			name:       "should fail without the token",
+			mux:        mux,
+			method:     http.MethodGet,
+			target:     Endpoint,
+			noToken:    true,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "should fail with an invalid limit",
+			mux:        mux,
+			method:     http.MethodGet,
+			target:     Endpoint + "?limit=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should fail to requeue all events",
+			mux:        mux,
+			method:     http.MethodPost,
+			target:     RequeueEndpoint,
+			body:       `{"all":true}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should fail to purge without sequences",
+			mux:        mux,
+			method:     http.MethodPost,
+			target:     PurgeEndpoint,
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should fail to purge with GET",
+			mux:        mux,
+			method:     http.MethodGet,
+			target:     PurgeEndpoint,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "should fail if the dead-letter stream is disabled",
+			mux:        disabled,
+			method:     http.MethodGet,
+			target:     Endpoint,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if !tc.noToken {
+				request.Header.Set("Authorization", "Bearer "+token)
+			}
+			tc.mux.ServeHTTP(recorder, request)
+			require.Equal(t, tc.wantStatus, recorder.Code)
+		})
+	}
+}
+
+func Test_IsRequeuedFor(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenRequeuedFor string
+		want             bool
+	}{
+		{
+			name:             "event which is not requeued should be delivered",
+			givenRequeuedFor: "",
+			want:             true,
+		},
+		{
+			name:             "event requeued for the subscription should be delivered",
+			givenRequeuedFor: "shop/orders",
+			want:             true,
+		},
+		{
+			name:             "event requeued for another subscription should not be delivered",
+			givenRequeuedFor: "shop/invoices",
+			want:             false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			header := nats.Header{}
+			if tc.givenRequeuedFor != "" {
+				header.Set(HeaderRequeuedFor, tc.givenRequeuedFor)
+			}
+			require.Equal(t, tc.want, IsRequeuedFor(header, "shop", "orders"))
+		})
+	}
+}
+
+func requireEvents(t *testing.T, jsCtx nats.JetStreamContext, want uint64) {
+	t.Helper()
+	info, err := jsCtx.StreamInfo(streamName)
+	require.NoError(t, err)
+	require.Equal(t, want, info.State.Msgs)
+}
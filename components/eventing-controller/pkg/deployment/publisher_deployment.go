@@ -357,6 +357,12 @@ func getNATSEnvVars(natsConfig env.NATSConfig, publisherConfig env.PublisherConf
 		},
 		// JetStream-specific config
 		{Name: "JS_STREAM_NAME", Value: natsConfig.JSStreamName},
+		// the events must be published to the subjects cleaned like the ones of the consumers
+		{Name: "NATS_CLEANER_STRATEGY", Value: natsConfig.CleanerStrategy},
+		{Name: "NATS_CLEANER_ALLOWED_CHARACTERS", Value: natsConfig.CleanerAllowedCharacters},
+		{Name: "NATS_CLEANER_REPLACEMENT", Value: natsConfig.CleanerReplacement},
+		{Name: "NATS_CLEANER_HASH_COLLISIONS", Value: strconv.FormatBool(natsConfig.CleanerHashCollisions)},
+		{Name: "NATS_CLEANER_MAX_SEGMENT_LENGTH", Value: strconv.Itoa(natsConfig.CleanerMaxSegmentLength)},
 	}
 }
 
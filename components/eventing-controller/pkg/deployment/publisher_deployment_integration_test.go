@@ -121,11 +121,21 @@ func Test_GetNATSEnvVars(t *testing.T) {
 				"PUBLISHER_REQUEST_TIMEOUT": "10s",
 			},
 			givenNATSConfig: env.NATSConfig{
-				JSStreamName: "kyma",
+				JSStreamName:             "kyma",
+				CleanerStrategy:          "custom",
+				CleanerAllowedCharacters: "a-z",
+				CleanerReplacement:       "_",
+				CleanerHashCollisions:    true,
+				CleanerMaxSegmentLength:  32,
 			},
 			wantEnvs: map[string]string{
-				"REQUEST_TIMEOUT": "10s",
-				"JS_STREAM_NAME":  "kyma",
+				"REQUEST_TIMEOUT":                 "10s",
+				"JS_STREAM_NAME":                  "kyma",
+				"NATS_CLEANER_STRATEGY":           "custom",
+				"NATS_CLEANER_ALLOWED_CHARACTERS": "a-z",
+				"NATS_CLEANER_REPLACEMENT":        "_",
+				"NATS_CLEANER_HASH_COLLISIONS":    "true",
+				"NATS_CLEANER_MAX_SEGMENT_LENGTH": "32",
 			},
 		},
 	}
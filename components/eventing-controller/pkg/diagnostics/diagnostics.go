@@ -0,0 +1,192 @@
+// Package diagnostics maps the Subscriptions to their backend resources and reports their delivery state,
+// so that operators do not need to query NATS or EventMesh directly to find out why events are not delivered.
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/nats-io/nats.go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
+)
+
+// SubscriptionReport is the delivery state of a Subscription and its backend resources.
+type SubscriptionReport struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Ready     bool   `json:"ready"`
+	Sink      string `json:"sink,omitempty"`
+	// LastError is the latest failure reported for the Subscription, if it is not ready.
+	LastError string `json:"lastError,omitempty"`
+	// Consumers are the JetStream consumers of the Subscription, one per event type.
+	Consumers []ConsumerReport `json:"consumers,omitempty"`
+	// EventMesh is the EventMesh subscription of the Subscription.
+	EventMesh *EventMeshReport `json:"eventMesh,omitempty"`
+}
+
+// ConsumerReport is the state of a JetStream consumer.
+type ConsumerReport struct {
+	EventType string `json:"eventType"`
+	Name      string `json:"name"`
+	// Pending is the number of messages not delivered yet.
+	Pending uint64 `json:"pending"`
+	// AckPending is the number of messages delivered but not acknowledged yet.
+	AckPending int `json:"ackPending"`
+	// Redelivered is the number of messages redelivered and not acknowledged yet.
+	Redelivered int `json:"redelivered"`
+	// Bound is true if the controller subscribed to the consumer.
+	Bound bool `json:"bound"`
+	// Error is set if the consumer state could not be queried.
+	Error string `json:"error,omitempty"`
+}
+
+// EventMeshReport is the state of an EventMesh subscription as reported in the Subscription status.
+type EventMeshReport struct {
+	Name                     string `json:"name,omitempty"`
+	Status                   string `json:"status,omitempty"`
+	StatusReason             string `json:"statusReason,omitempty"`
+	LastSuccessfulDelivery   string `json:"lastSuccessfulDelivery,omitempty"`
+	LastFailedDelivery       string `json:"lastFailedDelivery,omitempty"`
+	LastFailedDeliveryReason string `json:"lastFailedDeliveryReason,omitempty"`
+}
+
+// Inspector collects the SubscriptionReports.
+type Inspector struct {
+	client     client.Reader
+	jsCtx      nats.JetStreamContext
+	streamName string
+	nameMapper backendutils.NameMapper
+}
+
+// NewInspector returns an Inspector reading the Subscriptions with the given client. The consumer states are only
+// queried if a JetStream context is given, and the EventMesh subscription names are only reported if a name mapper
+// is given.
+func NewInspector(client client.Reader, jsCtx nats.JetStreamContext, streamName string,
+	nameMapper backendutils.NameMapper) *Inspector {
+	return &Inspector{
+		client:     client,
+		jsCtx:      jsCtx,
+		streamName: streamName,
+		nameMapper: nameMapper,
+	}
+}
+
+// Subscriptions returns the reports of the Subscriptions in the given namespace, or in all namespaces if the
+// namespace is empty, sorted by namespace and name.
+func (i *Inspector) Subscriptions(ctx context.Context, namespace string) ([]SubscriptionReport, error) {
+	subs := &eventingv1alpha2.SubscriptionList{}
+	if err := i.client.List(ctx, subs, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	sort.Slice(subs.Items, func(a, b int) bool {
+		if subs.Items[a].Namespace != subs.Items[b].Namespace {
+			return subs.Items[a].Namespace < subs.Items[b].Namespace
+		}
+		return subs.Items[a].Name < subs.Items[b].Name
+	})
+
+	reports := make([]SubscriptionReport, 0, len(subs.Items))
+	for idx := range subs.Items {
+		reports = append(reports, i.report(ctx, &subs.Items[idx]))
+	}
+	return reports, nil
+}
+
+// Subscription returns the report of the Subscription with the given name.
+func (i *Inspector) Subscription(ctx context.Context, name types.NamespacedName) (SubscriptionReport, error) {
+	sub := &eventingv1alpha2.Subscription{}
+	if err := i.client.Get(ctx, name, sub); err != nil {
+		return SubscriptionReport{}, err
+	}
+	return i.report(ctx, sub), nil
+}
+
+func (i *Inspector) report(ctx context.Context, sub *eventingv1alpha2.Subscription) SubscriptionReport {
+	report := SubscriptionReport{
+		Namespace: sub.Namespace,
+		Name:      sub.Name,
+		Ready:     sub.Status.Ready,
+		Sink:      SinkURL(sub),
+		LastError: lastError(sub),
+	}
+	for _, t := range sub.Status.Backend.Types {
+		report.Consumers = append(report.Consumers, i.consumerReport(ctx, t))
+	}
+	if backend := sub.Status.Backend; backend.EventMeshSubscriptionStatus != nil || len(backend.EmsTypes) > 0 {
+		report.EventMesh = &EventMeshReport{}
+		if i.nameMapper != nil {
+			report.EventMesh.Name = i.nameMapper.MapSubscriptionName(sub.Name, sub.Namespace)
+		}
+		if status := backend.EventMeshSubscriptionStatus; status != nil {
+			report.EventMesh.Status = status.Status
+			report.EventMesh.StatusReason = status.StatusReason
+			report.EventMesh.LastSuccessfulDelivery = status.LastSuccessfulDelivery
+			report.EventMesh.LastFailedDelivery = status.LastFailedDelivery
+			report.EventMesh.LastFailedDeliveryReason = status.LastFailedDeliveryReason
+		}
+	}
+	return report
+}
+
+func (i *Inspector) consumerReport(ctx context.Context, t eventingv1alpha2.JetStreamTypes) ConsumerReport {
+	report := ConsumerReport{EventType: t.OriginalType, Name: t.ConsumerName}
+	if i.jsCtx == nil || t.ConsumerName == "" {
+		return report
+	}
+	info, err := i.jsCtx.ConsumerInfo(i.streamName, t.ConsumerName, nats.Context(ctx))
+	if errors.Is(err, nats.ErrConsumerNotFound) {
+		report.Error = "consumer not found"
+		return report
+	} else if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Pending = info.NumPending
+	report.AckPending = info.NumAckPending
+	report.Redelivered = info.NumRedelivered
+	report.Bound = info.PushBound
+	return report
+}
+
+// SinkURL returns the resolved sink URL of the Subscription.
+func SinkURL(sub *eventingv1alpha2.Subscription) string {
+	if sub.Status.SinkURI != "" {
+		return sub.Status.SinkURI
+	}
+	return sub.Spec.Sink
+}
+
+// lastError returns the message of the latest failing condition of the Subscription, or the EventMesh failure
+// if no condition reports one. It returns an empty string if the Subscription is ready.
+func lastError(sub *eventingv1alpha2.Subscription) string {
+	if sub.Status.Ready {
+		return ""
+	}
+	var latest *eventingv1alpha2.Condition
+	for idx := range sub.Status.Conditions {
+		c := &sub.Status.Conditions[idx]
+		if c.Status == corev1.ConditionTrue || c.Message == "" {
+			continue
+		}
+		if latest == nil || latest.LastTransitionTime.Before(&c.LastTransitionTime) {
+			latest = c
+		}
+	}
+	if latest != nil {
+		return latest.Message
+	}
+	backend := sub.Status.Backend
+	if backend.FailedActivation != "" {
+		return backend.FailedActivation
+	}
+	if status := backend.EventMeshSubscriptionStatus; status != nil {
+		return status.LastFailedDeliveryReason
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package diagnostics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/diagnostics"
+	testingutils "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+const streamName = "kyma"
+
+func Test_Inspector_Subscriptions(t *testing.T) {
+	// given a JetStream stream with two messages for the consumer of a Subscription
+	port, err := testingutils.GetFreePort()
+	require.NoError(t, err)
+	natsServer := testingutils.RunNatsServerOnPort(testingutils.WithPort(port), testingutils.WithJetStreamEnabled(),
+		testingutils.WithStoreDir(t.TempDir()))
+	defer testingutils.ShutDownNATSServer(natsServer)
+	conn, err := nats.Connect(natsServer.ClientURL())
+	require.NoError(t, err)
+	defer conn.Close()
+	jsCtx, err := conn.JetStream()
+	require.NoError(t, err)
+	_, err = jsCtx.AddStream(&nats.StreamConfig{Name: streamName, Subjects: []string{"kyma.>"}})
+	require.NoError(t, err)
+	_, err = jsCtx.AddConsumer(streamName, &nats.ConsumerConfig{
+		Durable:       "consumer-a",
+		FilterSubject: "kyma.order.created.v1",
+		AckPolicy:     nats.AckExplicitPolicy,
+	})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = jsCtx.Publish("kyma.order.created.v1", []byte("{}"))
+		require.NoError(t, err)
+	}
+
+	now := time.Now()
+	ready := &eventingv1alpha2.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "orders"},
+		Spec:       eventingv1alpha2.SubscriptionSpec{Sink: "http://orders.shop.svc.cluster.local"},
+		Status: eventingv1alpha2.SubscriptionStatus{
+			Ready: true,
+			Backend: eventingv1alpha2.Backend{Types: []eventingv1alpha2.JetStreamTypes{
+				{OriginalType: "order.created.v1", ConsumerName: "consumer-a"},
+				{OriginalType: "order.deleted.v1", ConsumerName: "consumer-b"},
+			}},
+		},
+	}
+	notReady := &eventingv1alpha2.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "billing", Name: "invoices"},
+		Status: eventingv1alpha2.SubscriptionStatus{
+			SinkURI: "http://invoices.billing.svc.cluster.local",
+			Conditions: []eventingv1alpha2.Condition{
+				{Status: corev1.ConditionFalse, Message: "old failure",
+					LastTransitionTime: metav1.NewTime(now.Add(-time.Hour))},
+				{Status: corev1.ConditionFalse, Message: "dial tcp: connection refused",
+					LastTransitionTime: metav1.NewTime(now)},
+				{Status: corev1.ConditionTrue, Message: "ok", LastTransitionTime: metav1.NewTime(now)},
+			},
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, eventingv1alpha2.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ready, notReady).Build()
+	inspector := diagnostics.NewInspector(k8sClient, jsCtx, streamName, nil)
+
+	// when
+	reports, err := inspector.Subscriptions(context.Background(), "")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, []diagnostics.SubscriptionReport{
+		{
+			Namespace: "billing",
+			Name:      "invoices",
+			Sink:      "http://invoices.billing.svc.cluster.local",
+			LastError: "dial tcp: connection refused",
+		},
+		{
+			Namespace: "shop",
+			Name:      "orders",
+			Ready:     true,
+			Sink:      "http://orders.shop.svc.cluster.local",
+			Consumers: []diagnostics.ConsumerReport{
+				{EventType: "order.created.v1", Name: "consumer-a", Pending: 2},
+				{EventType: "order.deleted.v1", Name: "consumer-b", Error: "consumer not found"},
+			},
+		},
+	}, reports)
+
+	// when
+	report, err := inspector.Subscription(context.Background(), types.NamespacedName{Namespace: "shop", Name: "orders"})
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, reports[1], report)
+}
+
+func Test_Inspector_EventMesh(t *testing.T) {
+	// given
+	sub := &eventingv1alpha2.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "orders"},
+		Status: eventingv1alpha2.SubscriptionStatus{
+			Backend: eventingv1alpha2.Backend{
+				EventMeshSubscriptionStatus: &eventingv1alpha2.EventMeshSubscriptionStatus{
+					Status:                   "Active",
+					LastFailedDelivery:       "2023-10-17T08:00:00Z",
+					LastFailedDeliveryReason: "Webhook endpoint response code: 503",
+				},
+			},
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, eventingv1alpha2.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sub).Build()
+	inspector := diagnostics.NewInspector(k8sClient, nil, streamName, nil)
+
+	// when
+	reports, err := inspector.Subscriptions(context.Background(), "shop")
+
+	// then the EventMesh delivery failure is reported as the last error
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	require.Equal(t, "Webhook endpoint response code: 503", reports[0].LastError)
+	require.Equal(t, &diagnostics.EventMeshReport{
+		Status:                   "Active",
+		LastFailedDelivery:       "2023-10-17T08:00:00Z",
+		LastFailedDeliveryReason: "Webhook endpoint response code: 503",
+	}, reports[0].EventMesh)
+}
@@ -0,0 +1,200 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+)
+
+// The delivery states of an event for a consumer.
+const (
+	// EventStatePending is the state of an event not delivered to the consumer yet.
+	EventStatePending = "pending"
+	// EventStateAckPending is the state of an event delivered to the consumer, which may not be acknowledged yet.
+	// JetStream only reports the acknowledgements up to the oldest unacknowledged event of a consumer, so the events
+	// acknowledged after it are reported as ack pending as well.
+	EventStateAckPending = "ackPending"
+	// EventStateAcknowledged is the state of an event acknowledged by the consumer.
+	EventStateAcknowledged = "acknowledged"
+
+	ceIDHeader   = "ce-id"
+	ceTypeHeader = "ce-type"
+
+	// eventSearchIdleTimeout is the time to wait for the next event of the stream, before the search is stopped.
+	eventSearchIdleTimeout = 2 * time.Second
+)
+
+var ErrNoJetStream = errors.New("no JetStream context given")
+
+// EventReport is the delivery state of an event found in the stream.
+type EventReport struct {
+	ID       string `json:"id"`
+	Type     string `json:"type,omitempty"`
+	Subject  string `json:"subject"`
+	Sequence uint64 `json:"sequence"`
+	// Published is the time the event was stored in the stream.
+	Published time.Time `json:"published"`
+	// Consumers are the consumers whose filter subjects match the subject of the event.
+	Consumers []EventConsumerReport `json:"consumers,omitempty"`
+}
+
+// EventConsumerReport is the delivery state of an event for a JetStream consumer.
+type EventConsumerReport struct {
+	Name string `json:"name"`
+	// Subscription is the namespaced name of the Subscription owning the consumer.
+	Subscription string `json:"subscription,omitempty"`
+	State        string `json:"state"`
+	// Deliveries is the number of deliveries of the event known to JetStream, which is 0 for a pending event
+	// and at least 1 otherwise. JetStream does not report the redeliveries of single events.
+	Deliveries int `json:"deliveries"`
+	// MaxDeliver is the maximum number of deliveries of an event to the consumer, or -1 if it is unlimited.
+	MaxDeliver int `json:"maxDeliver"`
+	// Redelivered is the number of events of the consumer which are redelivered and not acknowledged yet.
+	Redelivered int `json:"redelivered"`
+}
+
+// FindEvents searches the stream for the events with the given CloudEvent id, which were published in the given
+// time window, and returns their delivery states. Since the stream removes the events acknowledged by all consumers
+// with the interest retention policy, an event which is not found may have been delivered.
+func (i *Inspector) FindEvents(ctx context.Context, id string, since, until time.Time) ([]EventReport, error) {
+	if i.jsCtx == nil {
+		return nil, ErrNoJetStream
+	}
+	info, err := i.jsCtx.StreamInfo(i.streamName, nats.Context(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the stream %s: %w", i.streamName, err)
+	}
+	if info.State.Msgs == 0 || info.State.LastTime.Before(since) {
+		return nil, nil
+	}
+
+	reports, err := i.scanEvents(ctx, id, since, until, info.State.LastSeq)
+	if err != nil || len(reports) == 0 {
+		return nil, err
+	}
+
+	var consumers []*nats.ConsumerInfo
+	for consumer := range i.jsCtx.Consumers(i.streamName, nats.Context(ctx)) {
+		consumers = append(consumers, consumer)
+	}
+	for idx := range reports {
+		reports[idx].Consumers = eventConsumerReports(reports[idx], consumers)
+	}
+	return reports, nil
+}
+
+// scanEvents reads the events of the stream published in the time window up to the last sequence,
+// and returns the events with the given CloudEvent id without their consumers.
+func (i *Inspector) scanEvents(ctx context.Context, id string, since, until time.Time,
+	lastSequence uint64) ([]EventReport, error) {
+	// an ordered consumer reads the stream from the start of the window without affecting the Subscriptions
+	sub, err := i.jsCtx.SubscribeSync("", nats.BindStream(i.streamName), nats.OrderedConsumer(),
+		nats.StartTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the stream %s: %w", i.streamName, err)
+	}
+	defer func() { _ = sub.Unsubscribe() }()
+
+	var reports []EventReport
+	for ctx.Err() == nil {
+		msg, err := sub.NextMsg(eventSearchIdleTimeout)
+		if errors.Is(err, nats.ErrTimeout) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read the stream %s: %w", i.streamName, err)
+		}
+		metadata, err := msg.Metadata()
+		if err != nil {
+			return nil, err
+		}
+		if metadata.Timestamp.After(until) {
+			break
+		}
+		if eventID, eventType := cloudEventIDAndType(msg); eventID == id {
+			reports = append(reports, EventReport{
+				ID:        eventID,
+				Type:      eventType,
+				Subject:   msg.Subject,
+				Sequence:  metadata.Sequence.Stream,
+				Published: metadata.Timestamp,
+			})
+		}
+		if metadata.NumPending == 0 || metadata.Sequence.Stream >= lastSequence {
+			break
+		}
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// eventConsumerReports returns the delivery states of the event for the consumers of its subject.
+func eventConsumerReports(event EventReport, consumers []*nats.ConsumerInfo) []EventConsumerReport {
+	var reports []EventConsumerReport
+	for _, consumer := range consumers {
+		if !consumerFilters(consumer.Config, event.Subject) {
+			continue
+		}
+		report := EventConsumerReport{
+			Name:        consumer.Name,
+			MaxDeliver:  consumer.Config.MaxDeliver,
+			Redelivered: consumer.NumRedelivered,
+		}
+		namespace := consumer.Config.Metadata[jetstream.ConsumerMetadataOwnerNamespace]
+		name := consumer.Config.Metadata[jetstream.ConsumerMetadataOwnerName]
+		if namespace != "" && name != "" {
+			report.Subscription = namespace + "/" + name
+		}
+		switch {
+		case event.Sequence > consumer.Delivered.Stream:
+			report.State = EventStatePending
+		case event.Sequence <= consumer.AckFloor.Stream || consumer.Config.AckPolicy == nats.AckNonePolicy:
+			report.State = EventStateAcknowledged
+			report.Deliveries = 1
+		default:
+			report.State = EventStateAckPending
+			report.Deliveries = 1
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// cloudEventIDAndType returns the id and type of the CloudEvent in the message, which is either in binary mode
+// with the attributes in the headers, or in structured mode with the attributes in the JSON data.
+func cloudEventIDAndType(msg *nats.Msg) (string, string) {
+	if id := msg.Header.Get(ceIDHeader); id != "" {
+		return id, msg.Header.Get(ceTypeHeader)
+	}
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		return "", ""
+	}
+	return event.ID, event.Type
+}
+
+// consumerFilters returns true if the consumer receives the events of the subject.
+func consumerFilters(config nats.ConsumerConfig, subject string) bool {
+	if config.FilterSubject == "" && len(config.FilterSubjects) == 0 {
+		return true
+	}
+	if config.FilterSubject != "" && jetstream.SubjectMatches(config.FilterSubject, subject) {
+		return true
+	}
+	for _, filter := range config.FilterSubjects {
+		if jetstream.SubjectMatches(filter, subject) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package diagnostics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/diagnostics"
+	testingutils "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func Test_Inspector_FindEvents(t *testing.T) {
+	// given a stream with three events, of which the first is acknowledged and the second is delivered
+	// to the consumer of a Subscription
+	port, err := testingutils.GetFreePort()
+	require.NoError(t, err)
+	natsServer := testingutils.RunNatsServerOnPort(testingutils.WithPort(port), testingutils.WithJetStreamEnabled(),
+		testingutils.WithStoreDir(t.TempDir()))
+	defer testingutils.ShutDownNATSServer(natsServer)
+	conn, err := nats.Connect(natsServer.ClientURL())
+	require.NoError(t, err)
+	defer conn.Close()
+	jsCtx, err := conn.JetStream()
+	require.NoError(t, err)
+	_, err = jsCtx.AddStream(&nats.StreamConfig{Name: streamName, Subjects: []string{"kyma.>"}})
+	require.NoError(t, err)
+	_, err = jsCtx.AddConsumer(streamName, &nats.ConsumerConfig{
+		Durable:       "consumer-a",
+		FilterSubject: "kyma.order.created.v1",
+		AckPolicy:     nats.AckExplicitPolicy,
+		MaxDeliver:    10,
+		Metadata: map[string]string{
+			jetstream.ConsumerMetadataOwnerNamespace: "shop",
+			jetstream.ConsumerMetadataOwnerName:      "orders",
+		},
+	})
+	require.NoError(t, err)
+	_, err = jsCtx.AddConsumer(streamName, &nats.ConsumerConfig{
+		Durable:       "consumer-b",
+		FilterSubject: "kyma.order.*.v1",
+		AckPolicy:     nats.AckExplicitPolicy,
+	})
+	require.NoError(t, err)
+	_, err = jsCtx.AddConsumer(streamName, &nats.ConsumerConfig{
+		Durable:       "consumer-c",
+		FilterSubject: "kyma.customer.created.v1",
+		AckPolicy:     nats.AckExplicitPolicy,
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	for _, id := range []string{"1", "2"} {
+		_, err = jsCtx.Publish("kyma.order.created.v1", []byte(`{"id":"`+id+`","type":"order.created.v1"}`))
+		require.NoError(t, err)
+	}
+	// an event in binary mode with the attributes in the headers
+	_, err = jsCtx.PublishMsg(&nats.Msg{
+		Subject: "kyma.order.created.v1",
+		Header:  nats.Header{"ce-id": []string{"3"}, "ce-type": []string{"order.created.v1"}},
+		Data:    []byte("{}"),
+	})
+	require.NoError(t, err)
+
+	pull, err := jsCtx.PullSubscribe("kyma.order.created.v1", "consumer-a", nats.Bind(streamName, "consumer-a"))
+	require.NoError(t, err)
+	msgs, err := pull.Fetch(2)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	require.NoError(t, msgs[0].AckSync())
+
+	inspector := diagnostics.NewInspector(nil, jsCtx, streamName, nil)
+	pendingB := diagnostics.EventConsumerReport{Name: "consumer-b", State: diagnostics.EventStatePending}
+
+	testCases := []struct {
+		name        string
+		id          string
+		since       time.Time
+		wantReports []diagnostics.EventReport
+	}{
+		{
+			name:  "should find the acknowledged event",
+			id:    "1",
+			since: start,
+			wantReports: []diagnostics.EventReport{{
+				ID: "1", Type: "order.created.v1", Subject: "kyma.order.created.v1", Sequence: 1,
+				Consumers: []diagnostics.EventConsumerReport{
+					{Name: "consumer-a", Subscription: "shop/orders", State: diagnostics.EventStateAcknowledged,
+						Deliveries: 1, MaxDeliver: 10},
+					pendingB,
+				},
+			}},
+		},
+		{
+			name:  "should find the delivered event",
+			id:    "2",
+			since: start,
+			wantReports: []diagnostics.EventReport{{
+				ID: "2", Type: "order.created.v1", Subject: "kyma.order.created.v1", Sequence: 2,
+				Consumers: []diagnostics.EventConsumerReport{
+					{Name: "consumer-a", Subscription: "shop/orders", State: diagnostics.EventStateAckPending,
+						Deliveries: 1, MaxDeliver: 10},
+					pendingB,
+				},
+			}},
+		},
+		{
+			name:  "should find the pending event in binary mode",
+			id:    "3",
+			since: start,
+			wantReports: []diagnostics.EventReport{{
+				ID: "3", Type: "order.created.v1", Subject: "kyma.order.created.v1", Sequence: 3,
+				Consumers: []diagnostics.EventConsumerReport{
+					{Name: "consumer-a", Subscription: "shop/orders", State: diagnostics.EventStatePending,
+						MaxDeliver: 10},
+					pendingB,
+				},
+			}},
+		},
+		{
+			name:  "should not find an unknown event",
+			id:    "4",
+			since: start,
+		},
+		{
+			name:  "should not find an event published before the time window",
+			id:    "1",
+			since: time.Now().Add(time.Minute),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			reports, err := inspector.FindEvents(context.Background(), tc.id, tc.since, time.Now().Add(time.Minute))
+
+			// then
+			require.NoError(t, err)
+			for idx := range reports {
+				require.False(t, reports[idx].Published.IsZero())
+				reports[idx].Published = time.Time{}
+				// the default maximum deliveries of the consumer depend on the server
+				for c := range reports[idx].Consumers {
+					if reports[idx].Consumers[c].Name == "consumer-b" {
+						reports[idx].Consumers[c].MaxDeliver = 0
+					}
+				}
+			}
+			require.Equal(t, tc.wantReports, reports)
+		})
+	}
+}
+
+func Test_Inspector_FindEvents_NoJetStream(t *testing.T) {
+	inspector := diagnostics.NewInspector(nil, nil, streamName, nil)
+	_, err := inspector.FindEvents(context.Background(), "1", time.Now(), time.Now())
+	require.ErrorIs(t, err, diagnostics.ErrNoJetStream)
+}
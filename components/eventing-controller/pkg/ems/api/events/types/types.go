@@ -21,6 +21,9 @@ type Subscription struct {
 	LastSuccessfulDelivery   string             `json:"lastSuccessfulDelivery,omitempty"`
 	LastFailedDelivery       string             `json:"lastFailedDelivery,omitempty"`
 	LastFailedDeliveryReason string             `json:"lastFailedDeliveryReason,omitempty"`
+	// Labels identify the Subscription which owns the EventMesh subscription. They are not part of the hashes,
+	// so that stamping them does not recreate the existing EventMesh subscriptions.
+	Labels map[string]string `json:"labels,omitempty" hash:"ignore"`
 }
 
 type Subscriptions []Subscription
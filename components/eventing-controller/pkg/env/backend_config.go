@@ -21,6 +21,12 @@ type BackendConfig struct {
 
 	DefaultSubscriptionConfig DefaultSubscriptionConfig
 
+	SubscriptionQuota SubscriptionQuota
+
+	// DeletionProtectionPendingMessages is the number of pending messages of a Subscription above which
+	// the webhook rejects its deletion. The deletion protection is disabled if it is 0.
+	DeletionProtectionPendingMessages int `envconfig:"DELETION_PROTECTION_PENDING_MESSAGES" default:"0"`
+
 	//nolint:lll
 	EventingWebhookAuthSecretName string `envconfig:"EVENTING_WEBHOOK_AUTH_SECRET_NAME" required:"true" default:"eventing-webhook-auth"`
 	//nolint:lll
@@ -49,12 +55,41 @@ type DefaultSubscriptionConfig struct {
 	MaxInFlightMessages   int           `envconfig:"DEFAULT_MAX_IN_FLIGHT_MESSAGES" default:"10"`
 	DispatcherRetryPeriod time.Duration `envconfig:"DEFAULT_DISPATCHER_RETRY_PERIOD" default:"5m"`
 	DispatcherMaxRetries  int           `envconfig:"DEFAULT_DISPATCHER_MAX_RETRIES" default:"10"`
+	// MaxInFlightMessagesLimit is the cluster-wide upper bound of the maxInFlightMessages of a Subscription.
+	// The bound is not enforced if it is 0.
+	MaxInFlightMessagesLimit int `envconfig:"MAX_IN_FLIGHT_MESSAGES_LIMIT" default:"0"`
+	// DeliveryMode is the NATS delivery mode of the Subscriptions without a deliveryMode config,
+	// either jetstream or core.
+	DeliveryMode string `envconfig:"DEFAULT_DELIVERY_MODE" default:"jetstream"`
+	// Source is set by the defaulting webhook for Subscriptions with the standard type matching and no source.
+	Source string `envconfig:"DEFAULT_SUBSCRIPTION_SOURCE" default:""`
+}
+
+// SubscriptionQuota limits the Subscriptions of a single namespace. A limit is not enforced if it is 0.
+type SubscriptionQuota struct {
+	MaxSubscriptions    int `envconfig:"NAMESPACE_MAX_SUBSCRIPTIONS" default:"0"`
+	MaxEventTypes       int `envconfig:"NAMESPACE_MAX_EVENT_TYPES" default:"0"`
+	MaxInFlightMessages int `envconfig:"NAMESPACE_MAX_IN_FLIGHT_MESSAGES" default:"0"`
+}
+
+// IsEnabled returns true if any limit of the quota is enforced.
+func (q SubscriptionQuota) IsEnabled() bool {
+	return q.MaxSubscriptions > 0 || q.MaxEventTypes > 0 || q.MaxInFlightMessages > 0
 }
 
 func GetBackendConfig() BackendConfig {
-	cfg := BackendConfig{}
-	if err := envconfig.Process("", &cfg); err != nil {
+	cfg, err := LoadBackendConfig()
+	if err != nil {
 		log.Fatalf("Invalid configuration: %v", err)
 	}
 	return cfg
 }
+
+// LoadBackendConfig returns the BackendConfig from the environment, or an error if it is invalid.
+func LoadBackendConfig() (BackendConfig, error) {
+	cfg := BackendConfig{}
+	if err := envconfig.Process("", &cfg); err != nil {
+		return BackendConfig{}, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,45 @@
+package env
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// BridgeConfig represents the environment config of the bridge forwarding events of the local JetStream stream
+// to another cluster.
+type BridgeConfig struct {
+	// ClusterName identifies this cluster in the origin extension of the forwarded events.
+	ClusterName string `envconfig:"BRIDGE_CLUSTER_NAME" default:""`
+	// EventTypes are the forwarded event types as <source>.<event type> after cleaning, which are the subjects
+	// of the stream without the subject prefix. The NATS wildcards * and > are supported.
+	EventTypes []string `envconfig:"BRIDGE_EVENT_TYPES" default:""`
+
+	// TargetPublishURL is the publish endpoint of the event publisher proxy of the other cluster.
+	TargetPublishURL string `envconfig:"BRIDGE_TARGET_PUBLISH_URL" default:""`
+	// TargetNATSURL is the URL of the NATS server of the other cluster, or of a leafnode connected to it,
+	// publishing the events to the stream of the other cluster directly.
+	TargetNATSURL string `envconfig:"BRIDGE_TARGET_NATS_URL" default:""`
+	// TargetNATSCredentialsFile is the credentials file of the target NATS connection. Not used if empty.
+	TargetNATSCredentialsFile string `envconfig:"BRIDGE_TARGET_NATS_CREDENTIALS_FILE" default:""`
+
+	// MaxInFlight is the maximum number of events of an event type forwarded at the same time.
+	MaxInFlight int `envconfig:"BRIDGE_MAX_IN_FLIGHT" default:"10"`
+	// RequestTimeout is the timeout of forwarding a single event.
+	RequestTimeout time.Duration `envconfig:"BRIDGE_REQUEST_TIMEOUT" default:"10s"`
+	// RetryDelay is the delay before an event is forwarded again after forwarding failed.
+	RetryDelay time.Duration `envconfig:"BRIDGE_RETRY_DELAY" default:"5s"`
+}
+
+// Enabled returns true if a target of the bridge is configured.
+func (c BridgeConfig) Enabled() bool {
+	return c.TargetPublishURL != "" || c.TargetNATSURL != ""
+}
+
+func GetBridgeConfig() (BridgeConfig, error) {
+	cfg := BridgeConfig{}
+	if err := envconfig.Process("", &cfg); err != nil {
+		return BridgeConfig{}, err
+	}
+	return cfg, nil
+}
@@ -64,6 +64,52 @@ type Config struct {
 
 	// NATSProvisioningEnabled enable/disable the NATS resources provisioning feature flag.
 	NATSProvisioningEnabled bool `envconfig:"NATS_PROVISIONING_ENABLED" required:"false" default:"true"`
+
+	// ExternalSinkPolicy defines whether Subscriptions may use sinks outside the cluster.
+	// Supported values are "deny", "annotated" and "allow".
+	ExternalSinkPolicy string `envconfig:"EXTERNAL_SINK_POLICY" required:"false" default:"deny"`
+
+	// CleanerStrategy is the name of the registered strategy cleaning the event types and sources of the Subscriptions
+	// for the EventMesh backend.
+	CleanerStrategy string `envconfig:"EVENTMESH_CLEANER_STRATEGY" required:"false" default:"eventmesh"`
+	// CleanerAllowedCharacters is the regular expression character class of the characters kept by the cleaner,
+	// restricting the characters supported by EventMesh further. All supported characters are kept if empty.
+	CleanerAllowedCharacters string `envconfig:"EVENTMESH_CLEANER_ALLOWED_CHARACTERS" required:"false" default:""`
+	// CleanerReplacement substitutes the characters removed by the cleaner. They are stripped if empty.
+	CleanerReplacement string `envconfig:"EVENTMESH_CLEANER_REPLACEMENT" required:"false" default:""`
+
+	// ShardCount is the number of eventing-controller replicas the Subscriptions are distributed over.
+	ShardCount int `envconfig:"SHARD_COUNT" required:"false" default:"1"`
+
+	// ShardIndex is the zero-based index of the Subscription shard reconciled by this replica.
+	ShardIndex int `envconfig:"SHARD_INDEX" required:"false" default:"0"`
+
+	// Profile is the name of the deployment profile presetting the tuning values, none if empty.
+	Profile string `envconfig:"DEPLOYMENT_PROFILE" required:"false" default:""`
+
+	// FeatureFlagsConfigMapName is the name of the ConfigMap enabling the experimental capabilities per cluster.
+	// All experimental capabilities are disabled if it is empty.
+	//nolint:lll
+	FeatureFlagsConfigMapName string `envconfig:"FEATURE_FLAGS_CONFIGMAP_NAME" required:"false" default:"eventing-feature-flags"`
+	// FeatureFlagsConfigMapNamespace is the Namespace of the feature flags ConfigMap.
+	//nolint:lll
+	FeatureFlagsConfigMapNamespace string `envconfig:"FEATURE_FLAGS_CONFIGMAP_NAMESPACE" required:"false" default:"kyma-system"`
+
+	// ApplicationEventTypesNamespaces are the Namespaces the event types declared by the Application CRs are
+	// registered in. The Application CRs are not watched if it is empty.
+	//nolint:lll
+	ApplicationEventTypesNamespaces []string `envconfig:"APPLICATION_EVENT_TYPES_NAMESPACES" required:"false" default:""`
+	// ApplicationAllowlistConfigMapName is the name of the ConfigMap allowing the publisher proxy to accept the event
+	// types declared by the Application CRs. No allowlist is maintained if it is empty.
+	//nolint:lll
+	ApplicationAllowlistConfigMapName string `envconfig:"APPLICATION_ALLOWLIST_CONFIGMAP_NAME" required:"false" default:"eventing-application-allowlist"`
+	// ApplicationAllowlistConfigMapNamespace is the Namespace of the allowlist ConfigMap.
+	//nolint:lll
+	ApplicationAllowlistConfigMapNamespace string `envconfig:"APPLICATION_ALLOWLIST_CONFIGMAP_NAMESPACE" required:"false" default:"kyma-system"`
+
+	// AuditLogOutput is where the audit records of the changes to the Subscriptions and their consumers are written,
+	// either "stdout", "stderr", or the path of a file. The changes are not audited if it is empty.
+	AuditLogOutput string `envconfig:"AUDIT_LOG_OUTPUT" required:"false" default:""`
 }
 
 func GetConfig() Config {
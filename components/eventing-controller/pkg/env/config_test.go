@@ -42,4 +42,6 @@ func Test_GetConfig(t *testing.T) {
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(config.WebhookActivationTimeout).To(Equal(webhookActivationTimeout))
 	g.Expect(config.NATSProvisioningEnabled).To(Equal(true))
+	g.Expect(config.ShardCount).To(Equal(1))
+	g.Expect(config.ShardIndex).To(Equal(0))
 }
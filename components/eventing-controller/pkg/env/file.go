@@ -0,0 +1,132 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	envconfigTag = "envconfig"
+	redacted     = "<redacted>"
+)
+
+// sensitiveEnvs are the environment variables whose values are redacted from the effective configuration.
+//
+//nolint:gochecknoglobals // constant lookup table
+var sensitiveEnvs = map[string]bool{
+	"CLIENT_SECRET": true,
+}
+
+// ConfigFile is the schema of the configuration file. Each section holds the values of the environment variables
+// of a configuration struct by their name, for example:
+//
+//	nats:
+//	  NATS_URL: nats://eventing-nats.kyma-system.svc.cluster.local:4222
+//	  JS_STREAM_REPLICAS: 3
+//	backend:
+//	  APP_LOG_LEVEL: debug
+//
+// The environment variables of the container take precedence over the values of the file.
+type ConfigFile struct {
+	// Eventing holds the environment variables of Config, such as BEB_API_URL and SHARD_COUNT.
+	Eventing map[string]string `yaml:"eventing,omitempty"`
+	// NATS holds the environment variables of NATSConfig, such as NATS_URL and JS_STREAM_NAME.
+	NATS map[string]string `yaml:"nats,omitempty"`
+	// Backend holds the environment variables of BackendConfig, such as APP_LOG_LEVEL and PUBLISHER_IMAGE.
+	Backend map[string]string `yaml:"backend,omitempty"`
+}
+
+// LoadConfigFile reads the configuration file of the given path and sets the environment variables
+// which are not set yet to its values. It returns an error if the file names unknown environment variables.
+func LoadConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the configuration file %s: %w", path, err)
+	}
+	file := ConfigFile{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	// an empty file is a valid configuration file without any values
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse the configuration file %s: %w", path, err)
+	}
+	if err := file.validate(); err != nil {
+		return fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	for _, section := range []map[string]string{file.Eventing, file.NATS, file.Backend} {
+		for name, value := range section {
+			if _, ok := os.LookupEnv(name); ok {
+				continue
+			}
+			if err := os.Setenv(name, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validate returns all the environment variables of the sections which are unknown to their configuration struct.
+func (f ConfigFile) validate() error {
+	v := &validation{}
+	v.knownEnvs("eventing", f.Eventing, Config{})
+	v.knownEnvs("nats", f.NATS, NATSConfig{})
+	v.knownEnvs("backend", f.Backend, BackendConfig{})
+	return v.err()
+}
+
+// knownEnvs records an error for each environment variable of the given section
+// which is not one of the given configuration struct.
+func (v *validation) knownEnvs(section string, values map[string]string, config interface{}) {
+	known := envValues(config)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, ok := known[name]
+		v.check(ok, "%s is no environment variable of the %s section", name, section)
+	}
+}
+
+// EffectiveConfigFile returns the configuration file with the values of all the environment variables
+// of the given configurations, with the sensitive values redacted.
+func EffectiveConfigFile(eventing Config, nats NATSConfig, backend BackendConfig) ConfigFile {
+	return ConfigFile{
+		Eventing: envValues(eventing),
+		NATS:     envValues(nats),
+		Backend:  envValues(backend),
+	}
+}
+
+// envValues returns the values of the fields of the given configuration struct by their environment variable,
+// including the fields of its nested structs.
+func envValues(config interface{}) map[string]string {
+	values := map[string]string{}
+	collectEnvValues(reflect.ValueOf(config), values)
+	return values
+}
+
+func collectEnvValues(value reflect.Value, values map[string]string) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, ok := field.Tag.Lookup(envconfigTag)
+		switch {
+		case ok && sensitiveEnvs[name]:
+			values[name] = redacted
+		case ok:
+			values[name] = fmt.Sprint(value.Field(i).Interface())
+		case field.Type.Kind() == reflect.Struct && field.IsExported():
+			collectEnvValues(value.Field(i), values)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadConfigFile(t *testing.T) {
+	// given
+	t.Setenv("JS_STREAM_NAME", "container")
+	// registers the variables to be unset again by the cleanup
+	for _, name := range []string{"JS_STREAM_REPLICAS", "SHARD_COUNT", "APP_LOG_LEVEL"} {
+		t.Setenv(name, "")
+		require.NoError(t, os.Unsetenv(name))
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+eventing:
+  SHARD_COUNT: 2
+nats:
+  JS_STREAM_NAME: file
+  JS_STREAM_REPLICAS: 3
+backend:
+  APP_LOG_LEVEL: debug
+`), 0o600))
+
+	// when
+	err := LoadConfigFile(path)
+
+	// then the environment variables of the container take precedence
+	require.NoError(t, err)
+	require.Equal(t, "container", os.Getenv("JS_STREAM_NAME"))
+	require.Equal(t, "3", os.Getenv("JS_STREAM_REPLICAS"))
+	require.Equal(t, "2", os.Getenv("SHARD_COUNT"))
+	require.Equal(t, "debug", os.Getenv("APP_LOG_LEVEL"))
+}
+
+func Test_LoadConfigFile_Invalid(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenContent     string
+		wantErrorContain string
+	}{
+		{
+			name:             "unknown section",
+			givenContent:     "jetstream:\n  JS_STREAM_NAME: sap\n",
+			wantErrorContain: "field jetstream not found",
+		},
+		{
+			name:             "unknown environment variable",
+			givenContent:     "nats:\n  JS_STREAM_NAME: sap\n  SHARD_COUNT: 2\n",
+			wantErrorContain: "SHARD_COUNT is no environment variable of the nats section",
+		},
+		{
+			name:             "no map",
+			givenContent:     "nats: sap\n",
+			wantErrorContain: "failed to parse the configuration file",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.givenContent), 0o600))
+
+			// when
+			err := LoadConfigFile(path)
+
+			// then
+			require.ErrorContains(t, err, tc.wantErrorContain)
+		})
+	}
+}
+
+func Test_EffectiveConfigFile(t *testing.T) {
+	// given
+	backendConfig, err := LoadBackendConfig()
+	require.NoError(t, err)
+	envConfig := Config{ClientSecret: "secret", ShardCount: 2}
+
+	// when
+	file := EffectiveConfigFile(envConfig, validNATSConfig(), backendConfig)
+
+	// then
+	require.Equal(t, redacted, file.Eventing["CLIENT_SECRET"])
+	require.Equal(t, "2", file.Eventing["SHARD_COUNT"])
+	require.Equal(t, "nats://eventing-nats.kyma-system.svc.cluster.local:4222", file.NATS["NATS_URL"])
+	// the nested structs are included
+	require.Equal(t, "5m0s", file.Backend["DEFAULT_DISPATCHER_RETRY_PERIOD"])
+	require.Equal(t, "json", file.Backend["APP_LOG_FORMAT"])
+	// the fields without environment variable are excluded
+	require.NotContains(t, file.NATS, "MaxReconnects")
+	require.NoError(t, file.validate())
+}
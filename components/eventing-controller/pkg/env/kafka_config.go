@@ -0,0 +1,47 @@
+package env
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// KafkaConfig represents the environment config for the Eventing Controller with Kafka.
+type KafkaConfig struct {
+	// Brokers are the addresses of the Kafka bootstrap brokers. The brokers of the EventingBackend spec take
+	// precedence over them.
+	Brokers []string `envconfig:"KAFKA_BROKERS" default:""`
+
+	// EventTypePrefix prefix for the EventType
+	// note: eventType format is <prefix>.<application>.<event>.<version>
+	EventTypePrefix string `envconfig:"EVENT_TYPE_PREFIX" required:"true"`
+
+	// TopicPrefix is the prefix of the topics, which are named <prefix>.<source>.<event type> after cleaning.
+	TopicPrefix string `envconfig:"KAFKA_TOPIC_PREFIX" default:"kyma"`
+	// TopicPartitions is the number of partitions of the topics created by the controller.
+	TopicPartitions int `envconfig:"KAFKA_TOPIC_PARTITIONS" default:"1"`
+	// TopicReplicationFactor is the replication factor of the topics created by the controller.
+	TopicReplicationFactor int `envconfig:"KAFKA_TOPIC_REPLICATION_FACTOR" default:"1"`
+	// ConsumerGroupPrefix is the prefix of the consumer groups, which are named <prefix>.<namespace>.<name>
+	// after the Subscription consuming the topics.
+	ConsumerGroupPrefix string `envconfig:"KAFKA_CONSUMER_GROUP_PREFIX" default:"kyma-eventing"`
+	// DialTimeout is the timeout of connecting to a broker.
+	DialTimeout time.Duration `envconfig:"KAFKA_DIAL_TIMEOUT" default:"10s"`
+
+	// CleanerStrategy is the name of the registered strategy cleaning the event types and sources of the Subscriptions.
+	CleanerStrategy string `envconfig:"KAFKA_CLEANER_STRATEGY" default:"kafka"`
+
+	// HTTP Transport config for the message dispatcher
+	MaxIdleConns        int           `envconfig:"MAX_IDLE_CONNS" default:"50"`
+	MaxConnsPerHost     int           `envconfig:"MAX_CONNS_PER_HOST" default:"50"`
+	MaxIdleConnsPerHost int           `envconfig:"MAX_IDLE_CONNS_PER_HOST" default:"50"`
+	IdleConnTimeout     time.Duration `envconfig:"IDLE_CONN_TIMEOUT" default:"10s"`
+}
+
+func GetKafkaConfig() (KafkaConfig, error) {
+	cfg := KafkaConfig{}
+	if err := envconfig.Process("", &cfg); err != nil {
+		return KafkaConfig{}, err
+	}
+	return cfg, nil
+}
@@ -13,6 +13,13 @@ type NATSConfig struct {
 	MaxReconnects int
 	ReconnectWait time.Duration
 
+	// Credentials and TLS material of the NATS connection, read from files such as the keys of a mounted Secret.
+	// The connection is re-established when the files change. They are not used if empty.
+	CredentialsFile string `envconfig:"NATS_CREDENTIALS_FILE" default:""`
+	TLSCertFile     string `envconfig:"NATS_TLS_CERT_FILE" default:""`
+	TLSKeyFile      string `envconfig:"NATS_TLS_KEY_FILE" default:""`
+	TLSCAFile       string `envconfig:"NATS_TLS_CA_FILE" default:""`
+
 	// EventTypePrefix prefix for the EventType
 	// note: eventType format is <prefix>.<application>.<event>.<version>
 	EventTypePrefix string `envconfig:"EVENT_TYPE_PREFIX" required:"true"`
@@ -53,6 +60,53 @@ type NATSConfig struct {
 	// - new: When first consuming messages, the consumer starts receiving messages that were created
 	//   after the consumer was created.
 	JSConsumerDeliverPolicy string `envconfig:"JS_CONSUMER_DELIVER_POLICY" default:"new"`
+
+	// Dead-letter configs
+	// JSDeadLetterStreamName is the name of the stream the events are moved to after their last failed delivery.
+	// The events are dropped after their last failed delivery if it is empty.
+	JSDeadLetterStreamName string `envconfig:"JS_DEAD_LETTER_STREAM_NAME" default:""`
+	// JSDeadLetterSubjectPrefix is the prefix of the subjects of the dead-letter stream, which are the original
+	// subjects of the events prepended with it. It must not overlap with the subject prefix of the stream.
+	JSDeadLetterSubjectPrefix string `envconfig:"JS_DEAD_LETTER_SUBJECT_PREFIX" default:"deadletter"`
+
+	// CleanerStrategy is the name of the registered strategy cleaning the event types and sources of the Subscriptions.
+	CleanerStrategy string `envconfig:"NATS_CLEANER_STRATEGY" default:"jetstream"`
+	// CleanerAllowedCharacters is the regular expression character class of the characters kept by the cleaner,
+	// restricting the characters supported by NATS further. All supported characters are kept if empty.
+	CleanerAllowedCharacters string `envconfig:"NATS_CLEANER_ALLOWED_CHARACTERS" default:""`
+	// CleanerReplacement substitutes the characters removed by the cleaner. They are stripped if empty.
+	CleanerReplacement string `envconfig:"NATS_CLEANER_REPLACEMENT" default:""`
+	// CleanerMappingBucket is the name of the JetStream key-value bucket persisting the original event types
+	// of the cleaned event types. They are only kept in memory if empty.
+	CleanerMappingBucket string `envconfig:"NATS_CLEANER_MAPPING_BUCKET" default:""`
+	// CleanerHashCollisions enables appending a hash suffix to the event types changed by the cleaner,
+	// so that they cannot collide with the cleaned event type of a different original event type.
+	CleanerHashCollisions bool `envconfig:"NATS_CLEANER_HASH_COLLISIONS" default:"false"`
+	// CleanerMaxSegmentLength is the maximum length of the segments of the cleaned event types. The longer segments
+	// are shortened and get a hash suffix. The length is not limited if it is 0.
+	CleanerMaxSegmentLength int `envconfig:"NATS_CLEANER_MAX_SEGMENT_LENGTH" default:"0"`
+
+	// Sink probing configs
+	// Interval between two reachability probes of a subscription sink. Probing is disabled if it is zero.
+	SinkProbeInterval time.Duration `envconfig:"SINK_PROBE_INTERVAL" default:"0s"`
+	// Timeout of a single reachability probe.
+	SinkProbeTimeout time.Duration `envconfig:"SINK_PROBE_TIMEOUT" default:"5s"`
+	// Method used to probe the sinks, tcp or http.
+	SinkProbeMethod string `envconfig:"SINK_PROBE_METHOD" default:"tcp"`
+	// Interval between two refreshes of the SinkHealthy condition from the sink health metrics.
+	// The condition is disabled if it is zero.
+	SinkHealthConditionInterval time.Duration `envconfig:"SINK_HEALTH_CONDITION_INTERVAL" default:"0s"`
+
+	// CEValidationPolicy rejects the dispatched events violating any rule of the CloudEvents specification if "strict",
+	// or only the events violating its required rules if "lenient". The events are not validated if it is empty.
+	CEValidationPolicy string `envconfig:"CE_VALIDATION_POLICY" default:"lenient"`
+	// CEMaxEventSize is the size in bytes the events should not exceed, checked only by the strict validation policy.
+	// The size is not checked if it is zero.
+	CEMaxEventSize int `envconfig:"CE_MAX_EVENT_SIZE" default:"65536"`
+
+	// DispatchLogSamplesPerSecond is the maximum number of events per second and subscription whose dispatch and
+	// redelivery lines are logged at debug and info level. The lines of all events are logged if it is zero.
+	DispatchLogSamplesPerSecond int `envconfig:"DISPATCH_LOG_SAMPLES_PER_SECOND" default:"0"`
 }
 
 func GetNATSConfig(maxReconnects int, reconnectWait time.Duration) (NATSConfig, error) {
@@ -37,23 +37,26 @@ func Test_GetNATSConfig(t *testing.T) {
 				reconnectWait: 1 * time.Second,
 			},
 			want: NATSConfig{
-				URL:                     "natsurl",
-				MaxReconnects:           1,
-				ReconnectWait:           1 * time.Second,
-				EventTypePrefix:         "etp",
-				MaxIdleConns:            50,
-				MaxConnsPerHost:         50,
-				MaxIdleConnsPerHost:     50,
-				IdleConnTimeout:         10 * time.Second,
-				JSStreamName:            "jsn",
-				JSSubjectPrefix:         "kma",
-				JSStreamStorageType:     "memory",
-				JSStreamReplicas:        1,
-				JSStreamRetentionPolicy: "interest",
-				JSStreamMaxMessages:     -1,
-				JSStreamMaxBytes:        "-1",
-				JSConsumerDeliverPolicy: "new",
-				JSStreamDiscardPolicy:   "new",
+				URL:                       "natsurl",
+				MaxReconnects:             1,
+				ReconnectWait:             1 * time.Second,
+				EventTypePrefix:           "etp",
+				MaxIdleConns:              50,
+				MaxConnsPerHost:           50,
+				MaxIdleConnsPerHost:       50,
+				IdleConnTimeout:           10 * time.Second,
+				JSStreamName:              "jsn",
+				JSSubjectPrefix:           "kma",
+				JSStreamStorageType:       "memory",
+				JSStreamReplicas:          1,
+				JSStreamRetentionPolicy:   "interest",
+				JSStreamMaxMessages:       -1,
+				JSStreamMaxBytes:          "-1",
+				JSConsumerDeliverPolicy:   "new",
+				JSStreamDiscardPolicy:     "new",
+				JSDeadLetterSubjectPrefix: "deadletter",
+				CEValidationPolicy:        "lenient",
+				CEMaxEventSize:            65536,
 			},
 			wantErr: false,
 		},
@@ -80,23 +83,26 @@ func Test_GetNATSConfig(t *testing.T) {
 				reconnectWait: 1 * time.Second,
 			},
 			want: NATSConfig{
-				URL:                     "natsurl",
-				MaxReconnects:           1,
-				ReconnectWait:           1 * time.Second,
-				EventTypePrefix:         "etp",
-				MaxIdleConns:            1,
-				MaxConnsPerHost:         2,
-				MaxIdleConnsPerHost:     3,
-				IdleConnTimeout:         1 * time.Second,
-				JSStreamName:            "jsn",
-				JSSubjectPrefix:         "testjsn",
-				JSStreamStorageType:     "jsst",
-				JSStreamReplicas:        4,
-				JSStreamRetentionPolicy: "jsrp",
-				JSStreamMaxMessages:     5,
-				JSStreamMaxBytes:        "6",
-				JSConsumerDeliverPolicy: "jcdp",
-				JSStreamDiscardPolicy:   "jsdp",
+				URL:                       "natsurl",
+				MaxReconnects:             1,
+				ReconnectWait:             1 * time.Second,
+				EventTypePrefix:           "etp",
+				MaxIdleConns:              1,
+				MaxConnsPerHost:           2,
+				MaxIdleConnsPerHost:       3,
+				IdleConnTimeout:           1 * time.Second,
+				JSStreamName:              "jsn",
+				JSSubjectPrefix:           "testjsn",
+				JSStreamStorageType:       "jsst",
+				JSStreamReplicas:          4,
+				JSStreamRetentionPolicy:   "jsrp",
+				JSStreamMaxMessages:       5,
+				JSStreamMaxBytes:          "6",
+				JSConsumerDeliverPolicy:   "jcdp",
+				JSStreamDiscardPolicy:     "jsdp",
+				JSDeadLetterSubjectPrefix: "deadletter",
+				CEValidationPolicy:        "lenient",
+				CEMaxEventSize:            65536,
 			},
 			wantErr: false,
 		},
@@ -0,0 +1,121 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+const (
+	// ProfileEnv is the environment variable selecting the deployment profile.
+	ProfileEnv = "DEPLOYMENT_PROFILE"
+
+	ProfileEvaluation      = "evaluation"
+	ProfileProductionSmall = "production-small"
+	ProfileProductionLarge = "production-large"
+)
+
+// Profile presets the tuning values of a deployment, so that they do not have to be copied between clusters.
+// The values of a profile are defaults only, the environment variables and command line arguments which are set
+// explicitly take precedence over them.
+type Profile struct {
+	// Envs are the values of the environment variables by their name.
+	Envs map[string]string
+	// Args are the values of the command line arguments by their name.
+	Args map[string]string
+}
+
+// profiles are the supported deployment profiles by their name.
+//
+//nolint:gochecknoglobals // constant lookup table
+var profiles = map[string]Profile{
+	// evaluation keeps the events in memory with a single replica, for trial and development clusters.
+	ProfileEvaluation: {
+		Envs: map[string]string{
+			"JS_STREAM_STORAGE_TYPE":         "memory",
+			"JS_STREAM_REPLICAS":             "1",
+			"JS_STREAM_MAX_BYTES":            "100Mi",
+			"DEFAULT_MAX_IN_FLIGHT_MESSAGES": "10",
+			"PUBLISHER_REPLICAS":             "1",
+			"PUBLISHER_REQUEST_TIMEOUT":      "5s",
+		},
+		Args: map[string]string{
+			"max-concurrent-reconciles": "1",
+			"reconnect-wait":            "3s",
+		},
+	},
+	// production-small persists the events with three replicas, for clusters with up to a few hundred Subscriptions.
+	ProfileProductionSmall: {
+		Envs: map[string]string{
+			"JS_STREAM_STORAGE_TYPE":         "file",
+			"JS_STREAM_REPLICAS":             "3",
+			"JS_STREAM_MAX_BYTES":            "700Mi",
+			"DEFAULT_MAX_IN_FLIGHT_MESSAGES": "10",
+			"PUBLISHER_REPLICAS":             "2",
+			"PUBLISHER_REQUEST_TIMEOUT":      "10s",
+			"IDLE_CONN_TIMEOUT":              "30s",
+		},
+		Args: map[string]string{
+			"max-concurrent-reconciles": "2",
+			"reconnect-wait":            "3s",
+		},
+	},
+	// production-large persists more events and dispatches and reconciles with a higher concurrency,
+	// for clusters with thousands of Subscriptions.
+	ProfileProductionLarge: {
+		Envs: map[string]string{
+			"JS_STREAM_STORAGE_TYPE":         "file",
+			"JS_STREAM_REPLICAS":             "3",
+			"JS_STREAM_MAX_BYTES":            "5Gi",
+			"DEFAULT_MAX_IN_FLIGHT_MESSAGES": "20",
+			"MAX_IDLE_CONNS":                 "200",
+			"MAX_CONNS_PER_HOST":             "200",
+			"MAX_IDLE_CONNS_PER_HOST":        "200",
+			"IDLE_CONN_TIMEOUT":              "30s",
+			"PUBLISHER_REPLICAS":             "3",
+			"PUBLISHER_REQUESTS_CPU":         "100m",
+			"PUBLISHER_REQUESTS_MEMORY":      "128Mi",
+			"PUBLISHER_LIMITS_CPU":           "500m",
+			"PUBLISHER_LIMITS_MEMORY":        "512Mi",
+			"PUBLISHER_REQUEST_TIMEOUT":      "10s",
+		},
+		Args: map[string]string{
+			"max-concurrent-reconciles": "8",
+			"rate-limiter-qps":          "50",
+			"rate-limiter-burst":        "500",
+			"reconnect-wait":            "3s",
+		},
+	},
+}
+
+// ProfileNames returns the sorted names of the supported deployment profiles.
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupProfile returns the deployment profile of the given name, or an error if there is no such profile.
+func LookupProfile(name string) (Profile, error) {
+	profile, ok := profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%s must be one of %q, got %q", ProfileEnv, ProfileNames(), name)
+	}
+	return profile, nil
+}
+
+// ApplyEnvs sets the environment variables of the profile which are not set yet.
+func (p Profile) ApplyEnvs() error {
+	for name, value := range p.Envs {
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package env
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Profiles(t *testing.T) {
+	known := envValues(Config{})
+	for name, value := range envValues(NATSConfig{}) {
+		known[name] = value
+	}
+	for name, value := range envValues(BackendConfig{}) {
+		known[name] = value
+	}
+
+	t.Setenv("NATS_URL", "nats://eventing-nats.kyma-system.svc.cluster.local:4222")
+	t.Setenv("EVENT_TYPE_PREFIX", "sap.kyma.custom")
+	t.Setenv("JS_STREAM_NAME", "sap")
+	t.Setenv("JS_STREAM_SUBJECT_PREFIX", "kyma")
+
+	for _, name := range ProfileNames() {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			// given
+			profile, err := LookupProfile(name)
+			require.NoError(t, err)
+			for env := range profile.Envs {
+				require.Contains(t, known, env)
+				// registers the variable to be unset again by the cleanup
+				t.Setenv(env, "")
+				require.NoError(t, os.Unsetenv(env))
+			}
+
+			// when
+			require.NoError(t, profile.ApplyEnvs())
+
+			// then the preset configuration is valid
+			backendConfig, err := LoadBackendConfig()
+			require.NoError(t, err)
+			require.NoError(t, backendConfig.Validate())
+			natsConfig, err := GetNATSConfig(10, time.Second)
+			require.NoError(t, err)
+			require.NoError(t, natsConfig.Validate())
+		})
+	}
+}
+
+func Test_Profile_ApplyEnvs(t *testing.T) {
+	// given
+	t.Setenv("JS_STREAM_REPLICAS", "5")
+	t.Setenv("JS_STREAM_STORAGE_TYPE", "")
+	require.NoError(t, os.Unsetenv("JS_STREAM_STORAGE_TYPE"))
+	profile, err := LookupProfile(ProfileProductionSmall)
+	require.NoError(t, err)
+
+	// when
+	err = profile.ApplyEnvs()
+
+	// then the explicit values take precedence
+	require.NoError(t, err)
+	require.Equal(t, "5", os.Getenv("JS_STREAM_REPLICAS"))
+	require.Equal(t, "file", os.Getenv("JS_STREAM_STORAGE_TYPE"))
+}
+
+func Test_LookupProfile_Unknown(t *testing.T) {
+	_, err := LookupProfile("production-huge")
+	require.EqualError(t, err, `DEPLOYMENT_PROFILE must be one of ["evaluation" "production-large" "production-small"], `+
+		`got "production-huge"`)
+}
@@ -0,0 +1,283 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
+)
+
+const (
+	// maxStreamReplicas is the maximum number of replicas of a JetStream stream.
+	maxStreamReplicas = 5
+	maxPort           = 65535
+	// invalidNATSNameCharacters are the characters not allowed in the names of the JetStream streams.
+	invalidNATSNameCharacters = " \t\r\n.*>/\\"
+	// invalidNATSSubjectCharacters are the characters not allowed in the subject prefix.
+	invalidNATSSubjectCharacters = " \t\r\n*>"
+	// maxKafkaTopicPrefixLength leaves room for the source and event type in the topic names of at most 249 bytes.
+	maxKafkaTopicPrefixLength = 100
+)
+
+// kafkaTopicPrefix matches the topic prefixes consisting of the characters allowed in the Kafka topic names.
+var kafkaTopicPrefix = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// validation collects the errors of a configuration, so that all of them are reported at once.
+type validation struct {
+	errs []error
+}
+
+// check records an error with the given message if the given condition is false.
+func (v *validation) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf(format, args...))
+	}
+}
+
+// oneOf records an error if the given value of the environment variable is none of the allowed values.
+func (v *validation) oneOf(name, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.errs = append(v.errs, fmt.Errorf("%s must be one of %q, got %q", name, allowed, value))
+}
+
+// url records an error if the given value of the environment variable is not an absolute URL
+// with one of the allowed schemes.
+func (v *validation) url(name, value string, schemes ...string) {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		v.errs = append(v.errs, fmt.Errorf("%s must be an absolute URL such as %s://host:port, got %q",
+			name, schemes[0], value))
+		return
+	}
+	v.oneOf(name+" scheme", u.Scheme, schemes...)
+}
+
+// quantity records an error if the given value of the environment variable is no resource quantity.
+func (v *validation) quantity(name, value string) {
+	if _, err := resource.ParseQuantity(value); err != nil {
+		v.errs = append(v.errs, fmt.Errorf("%s must be a quantity such as 128Mi, got %q", name, value))
+	}
+}
+
+// subjectPrefixesOverlap returns true if the subjects with the given prefixes overlap.
+func subjectPrefixesOverlap(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+func (v *validation) err() error {
+	return errors.Join(v.errs...)
+}
+
+// Validate returns all the errors of the NATS configuration joined, or nil if it is valid.
+func (c NATSConfig) Validate() error {
+	v := &validation{}
+	for _, natsURL := range strings.Split(c.URL, ",") {
+		v.url("NATS_URL", strings.TrimSpace(natsURL), "nats", "tls", "ws", "wss")
+	}
+	v.check(c.MaxReconnects >= -1, "--max-reconnects must be at least -1 for unlimited reconnects, got %d",
+		c.MaxReconnects)
+	v.check(c.ReconnectWait > 0, "--reconnect-wait must be positive, got %s", c.ReconnectWait)
+	v.check((c.TLSCertFile == "") == (c.TLSKeyFile == ""),
+		"NATS_TLS_CERT_FILE and NATS_TLS_KEY_FILE must be set together, got %q and %q", c.TLSCertFile, c.TLSKeyFile)
+	v.check(c.MaxIdleConns >= 0, "MAX_IDLE_CONNS must not be negative, got %d", c.MaxIdleConns)
+	v.check(c.MaxConnsPerHost >= 0, "MAX_CONNS_PER_HOST must not be negative, got %d", c.MaxConnsPerHost)
+	v.check(c.MaxIdleConnsPerHost >= 0, "MAX_IDLE_CONNS_PER_HOST must not be negative, got %d", c.MaxIdleConnsPerHost)
+	v.check(c.IdleConnTimeout >= 0, "IDLE_CONN_TIMEOUT must not be negative, got %s", c.IdleConnTimeout)
+
+	v.check(c.JSStreamName != "" && !strings.ContainsAny(c.JSStreamName, invalidNATSNameCharacters),
+		"JS_STREAM_NAME must not be empty or contain whitespaces or any of %q, got %q",
+		strings.TrimSpace(invalidNATSNameCharacters), c.JSStreamName)
+	v.check(c.JSSubjectPrefix != "" && !strings.ContainsAny(c.JSSubjectPrefix, invalidNATSSubjectCharacters),
+		"JS_STREAM_SUBJECT_PREFIX must not be empty or contain whitespaces or wildcards, got %q", c.JSSubjectPrefix)
+	v.oneOf("JS_STREAM_STORAGE_TYPE", c.JSStreamStorageType, "memory", "file")
+	v.check(c.JSStreamReplicas >= 1 && c.JSStreamReplicas <= maxStreamReplicas,
+		"JS_STREAM_REPLICAS must be between 1 and %d, got %d", maxStreamReplicas, c.JSStreamReplicas)
+	v.oneOf("JS_STREAM_RETENTION_POLICY", c.JSStreamRetentionPolicy, "interest", "limits")
+	v.oneOf("JS_STREAM_DISCARD_POLICY", c.JSStreamDiscardPolicy, "new", "old")
+	v.oneOf("JS_CONSUMER_DELIVER_POLICY", c.JSConsumerDeliverPolicy, "all", "last", "last_per_subject", "new")
+	v.check(c.JSStreamMaxMessages >= -1, "JS_STREAM_MAX_MSGS must be at least -1 for no limit, got %d",
+		c.JSStreamMaxMessages)
+	v.check(c.JSStreamMaxMsgsPerTopic >= -1, "JS_STREAM_MAX_MSGS_PER_TOPIC must be at least -1 for no limit, got %d",
+		c.JSStreamMaxMsgsPerTopic)
+	v.check(c.JSStreamMaxMessages <= 0 || c.JSStreamMaxMsgsPerTopic <= c.JSStreamMaxMessages,
+		"JS_STREAM_MAX_MSGS_PER_TOPIC must not exceed JS_STREAM_MAX_MSGS %d, got %d",
+		c.JSStreamMaxMessages, c.JSStreamMaxMsgsPerTopic)
+	if c.JSStreamMaxBytes != "" && c.JSStreamMaxBytes != "-1" {
+		v.quantity("JS_STREAM_MAX_BYTES", c.JSStreamMaxBytes)
+	}
+
+	if c.JSDeadLetterStreamName != "" {
+		v.check(!strings.ContainsAny(c.JSDeadLetterStreamName, invalidNATSNameCharacters) &&
+			c.JSDeadLetterStreamName != c.JSStreamName,
+			"JS_DEAD_LETTER_STREAM_NAME must differ from JS_STREAM_NAME and not contain whitespaces or any of %q, got %q",
+			strings.TrimSpace(invalidNATSNameCharacters), c.JSDeadLetterStreamName)
+		v.check(c.JSDeadLetterSubjectPrefix != "" &&
+			!strings.ContainsAny(c.JSDeadLetterSubjectPrefix, invalidNATSSubjectCharacters) &&
+			!subjectPrefixesOverlap(c.JSDeadLetterSubjectPrefix, c.JSSubjectPrefix),
+			"JS_DEAD_LETTER_SUBJECT_PREFIX must not be empty, contain whitespaces or wildcards, "+
+				"or overlap with JS_STREAM_SUBJECT_PREFIX %q, got %q", c.JSSubjectPrefix, c.JSDeadLetterSubjectPrefix)
+	}
+
+	v.check(c.CleanerMaxSegmentLength >= 0, "NATS_CLEANER_MAX_SEGMENT_LENGTH must not be negative, got %d",
+		c.CleanerMaxSegmentLength)
+
+	v.check(c.SinkProbeInterval >= 0, "SINK_PROBE_INTERVAL must not be negative, got %s", c.SinkProbeInterval)
+	v.check(c.SinkHealthConditionInterval >= 0, "SINK_HEALTH_CONDITION_INTERVAL must not be negative, got %s",
+		c.SinkHealthConditionInterval)
+	if c.SinkProbeInterval > 0 {
+		v.check(c.SinkProbeTimeout > 0 && c.SinkProbeTimeout <= c.SinkProbeInterval,
+			"SINK_PROBE_TIMEOUT must be positive and not exceed SINK_PROBE_INTERVAL %s, got %s",
+			c.SinkProbeInterval, c.SinkProbeTimeout)
+		v.oneOf("SINK_PROBE_METHOD", c.SinkProbeMethod, "tcp", "http")
+	}
+
+	if c.CEValidationPolicy != "" {
+		v.oneOf("CE_VALIDATION_POLICY", c.CEValidationPolicy, "strict", "lenient")
+	}
+	v.check(c.CEMaxEventSize >= 0, "CE_MAX_EVENT_SIZE must not be negative, got %d", c.CEMaxEventSize)
+
+	v.check(c.DispatchLogSamplesPerSecond >= 0, "DISPATCH_LOG_SAMPLES_PER_SECOND must not be negative, got %d",
+		c.DispatchLogSamplesPerSecond)
+	return v.err()
+}
+
+// Validate returns all the errors of the Kafka configuration joined, or nil if it is valid.
+func (c KafkaConfig) Validate() error {
+	v := &validation{}
+	for _, broker := range c.Brokers {
+		_, port, err := net.SplitHostPort(broker)
+		v.check(err == nil && port != "", "KAFKA_BROKERS must be host:port addresses, got %q", broker)
+	}
+	v.check(len(c.TopicPrefix) <= maxKafkaTopicPrefixLength && kafkaTopicPrefix.MatchString(c.TopicPrefix),
+		"KAFKA_TOPIC_PREFIX must consist of at most %d letters, digits, '.', '_' or '-', got %q",
+		maxKafkaTopicPrefixLength, c.TopicPrefix)
+	v.check(c.TopicPartitions >= 1, "KAFKA_TOPIC_PARTITIONS must be at least 1, got %d", c.TopicPartitions)
+	v.check(c.TopicReplicationFactor >= 1, "KAFKA_TOPIC_REPLICATION_FACTOR must be at least 1, got %d",
+		c.TopicReplicationFactor)
+	v.check(c.ConsumerGroupPrefix != "", "KAFKA_CONSUMER_GROUP_PREFIX must not be empty")
+	v.check(c.DialTimeout > 0, "KAFKA_DIAL_TIMEOUT must be positive, got %s", c.DialTimeout)
+	v.check(c.MaxIdleConns >= 0, "MAX_IDLE_CONNS must not be negative, got %d", c.MaxIdleConns)
+	v.check(c.MaxConnsPerHost >= 0, "MAX_CONNS_PER_HOST must not be negative, got %d", c.MaxConnsPerHost)
+	v.check(c.MaxIdleConnsPerHost >= 0, "MAX_IDLE_CONNS_PER_HOST must not be negative, got %d", c.MaxIdleConnsPerHost)
+	v.check(c.IdleConnTimeout >= 0, "IDLE_CONN_TIMEOUT must not be negative, got %s", c.IdleConnTimeout)
+	return v.err()
+}
+
+// Validate returns all the errors of the bridge configuration joined, or nil if it is valid or the bridge is disabled.
+func (c BridgeConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	v := &validation{}
+	v.check(c.TargetPublishURL == "" || c.TargetNATSURL == "",
+		"either BRIDGE_TARGET_PUBLISH_URL or BRIDGE_TARGET_NATS_URL must be set, not both")
+	if c.TargetPublishURL != "" {
+		v.url("BRIDGE_TARGET_PUBLISH_URL", c.TargetPublishURL, "https", "http")
+	}
+	if c.TargetNATSURL != "" {
+		v.url("BRIDGE_TARGET_NATS_URL", c.TargetNATSURL, "nats", "tls", "ws", "wss")
+	}
+	v.check(c.ClusterName != "" && !strings.ContainsAny(c.ClusterName, " \t\r\n"),
+		"BRIDGE_CLUSTER_NAME must not be empty or contain whitespaces, got %q", c.ClusterName)
+	v.check(len(c.EventTypes) > 0, "BRIDGE_EVENT_TYPES must not be empty")
+	for _, eventType := range c.EventTypes {
+		v.check(eventType != "" && !strings.ContainsAny(eventType, " \t\r\n") &&
+			!strings.HasPrefix(eventType, ".") && !strings.HasSuffix(eventType, ".") &&
+			!strings.Contains(eventType, ".."),
+			"BRIDGE_EVENT_TYPES must be NATS subjects without the subject prefix, got %q", eventType)
+	}
+	v.check(c.MaxInFlight >= 1, "BRIDGE_MAX_IN_FLIGHT must be at least 1, got %d", c.MaxInFlight)
+	v.check(c.RequestTimeout > 0, "BRIDGE_REQUEST_TIMEOUT must be positive, got %s", c.RequestTimeout)
+	v.check(c.RetryDelay > 0, "BRIDGE_RETRY_DELAY must be positive, got %s", c.RetryDelay)
+	return v.err()
+}
+
+// Validate returns all the errors of the webhook certificate configuration joined, or nil if it is valid.
+func (c WebhookCertConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	v := &validation{}
+	v.check(c.SecretName != "", "WEBHOOK_SECRET_NAME must not be empty")
+	v.check(c.Namespace != "", "WEBHOOK_NAMESPACE must not be empty")
+	v.check(c.ServiceName != "", "WEBHOOK_SERVICE_NAME must not be empty")
+	v.check(c.RenewBefore > 0 && c.RenewBefore < c.Validity,
+		"WEBHOOK_CERT_RENEW_BEFORE must be positive and less than WEBHOOK_CERT_VALIDITY %s, got %s",
+		c.Validity, c.RenewBefore)
+	// the replicas pick up a rotated certificate within the check interval, while the previous one stays trusted
+	v.check(c.CheckInterval > 0 && c.CheckInterval < c.RenewBefore,
+		"WEBHOOK_CERT_CHECK_INTERVAL must be positive and less than WEBHOOK_CERT_RENEW_BEFORE %s, got %s",
+		c.RenewBefore, c.CheckInterval)
+	return v.err()
+}
+
+// Validate returns all the errors of the configuration joined, or nil if it is valid.
+func (c Config) Validate() error {
+	v := &validation{}
+	v.url("BEB_API_URL", c.BEBAPIURL, "https", "http")
+	v.check(c.WebhookActivationTimeout > 0, "WEBHOOK_ACTIVATION_TIMEOUT must be positive, got %s",
+		c.WebhookActivationTimeout)
+	v.oneOf("QOS", c.Qos, string(types.QosAtLeastOnce), string(types.QosAtMostOnce))
+	v.oneOf("CONTENT_MODE", c.ContentMode, "", types.ContentModeBinary, types.ContentModeStructured)
+	v.oneOf("EXTERNAL_SINK_POLICY", c.ExternalSinkPolicy, "deny", "annotated", "allow")
+	if c.Profile != "" {
+		_, err := LookupProfile(c.Profile)
+		v.check(err == nil, "%v", err)
+	}
+	v.check(c.ShardCount >= 1, "SHARD_COUNT must be at least 1, got %d", c.ShardCount)
+	v.check(c.ShardIndex >= 0 && c.ShardIndex < c.ShardCount,
+		"SHARD_INDEX must be at least 0 and lower than SHARD_COUNT %d, got %d", c.ShardCount, c.ShardIndex)
+	return v.err()
+}
+
+// Validate returns all the errors of the backend configuration joined, or nil if it is valid.
+func (c BackendConfig) Validate() error {
+	v := &validation{}
+	p := c.PublisherConfig
+	v.check(p.Replicas >= 1, "PUBLISHER_REPLICAS must be at least 1, got %d", p.Replicas)
+	v.check(p.PortNum >= 1 && p.PortNum <= maxPort, "PUBLISHER_PORT_NUM must be a port number, got %d", p.PortNum)
+	v.check(p.MetricsPortNum >= 1 && p.MetricsPortNum <= maxPort,
+		"PUBLISHER_METRICS_PORT_NUM must be a port number, got %d", p.MetricsPortNum)
+	v.quantity("PUBLISHER_REQUESTS_CPU", p.RequestsCPU)
+	v.quantity("PUBLISHER_REQUESTS_MEMORY", p.RequestsMemory)
+	v.quantity("PUBLISHER_LIMITS_CPU", p.LimitsCPU)
+	v.quantity("PUBLISHER_LIMITS_MEMORY", p.LimitsMemory)
+	if timeout, err := time.ParseDuration(p.RequestTimeout); err != nil || timeout <= 0 {
+		v.check(false, "PUBLISHER_REQUEST_TIMEOUT must be a positive duration such as 5s, got %q", p.RequestTimeout)
+	}
+
+	d := c.DefaultSubscriptionConfig
+	v.check(d.MaxInFlightMessages >= 1, "DEFAULT_MAX_IN_FLIGHT_MESSAGES must be at least 1, got %d",
+		d.MaxInFlightMessages)
+	v.check(d.DispatcherRetryPeriod > 0, "DEFAULT_DISPATCHER_RETRY_PERIOD must be positive, got %s",
+		d.DispatcherRetryPeriod)
+	v.check(d.DispatcherMaxRetries >= 0, "DEFAULT_DISPATCHER_MAX_RETRIES must not be negative, got %d",
+		d.DispatcherMaxRetries)
+	v.oneOf("DEFAULT_DELIVERY_MODE", d.DeliveryMode, "jetstream", "core")
+	v.check(d.MaxInFlightMessagesLimit >= 0, "MAX_IN_FLIGHT_MESSAGES_LIMIT must not be negative, got %d",
+		d.MaxInFlightMessagesLimit)
+	v.check(d.MaxInFlightMessagesLimit == 0 || d.MaxInFlightMessages <= d.MaxInFlightMessagesLimit,
+		"DEFAULT_MAX_IN_FLIGHT_MESSAGES must not exceed MAX_IN_FLIGHT_MESSAGES_LIMIT %d, got %d",
+		d.MaxInFlightMessagesLimit, d.MaxInFlightMessages)
+
+	q := c.SubscriptionQuota
+	v.check(q.MaxSubscriptions >= 0, "NAMESPACE_MAX_SUBSCRIPTIONS must not be negative, got %d", q.MaxSubscriptions)
+	v.check(q.MaxEventTypes >= 0, "NAMESPACE_MAX_EVENT_TYPES must not be negative, got %d", q.MaxEventTypes)
+	v.check(q.MaxInFlightMessages == 0 || q.MaxInFlightMessages >= d.MaxInFlightMessages,
+		"NAMESPACE_MAX_IN_FLIGHT_MESSAGES must be 0 or at least DEFAULT_MAX_IN_FLIGHT_MESSAGES %d, got %d",
+		d.MaxInFlightMessages, q.MaxInFlightMessages)
+	v.check(c.DeletionProtectionPendingMessages >= 0,
+		"DELETION_PROTECTION_PENDING_MESSAGES must not be negative, got %d", c.DeletionProtectionPendingMessages)
+	return v.err()
+}
@@ -0,0 +1,434 @@
+package env
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validNATSConfig() NATSConfig {
+	return NATSConfig{
+		URL:                     "nats://eventing-nats.kyma-system.svc.cluster.local:4222",
+		MaxReconnects:           10,
+		ReconnectWait:           3 * time.Second,
+		JSStreamName:            "sap",
+		JSSubjectPrefix:         "kyma",
+		JSStreamStorageType:     "file",
+		JSStreamReplicas:        3,
+		JSStreamRetentionPolicy: "interest",
+		JSStreamMaxMessages:     -1,
+		JSStreamMaxBytes:        "700Mi",
+		JSStreamMaxMsgsPerTopic: -1,
+		JSStreamDiscardPolicy:   "new",
+		JSConsumerDeliverPolicy: "new",
+		SinkProbeTimeout:        5 * time.Second,
+		SinkProbeMethod:         "tcp",
+	}
+}
+
+func validConfig() Config {
+	return Config{
+		BEBAPIURL:                "https://enterprise-messaging-pubsub.cfapps.sap.hana.ondemand.com/sap/ems/v1",
+		WebhookActivationTimeout: time.Minute,
+		Qos:                      "AT_LEAST_ONCE",
+		ExternalSinkPolicy:       "deny",
+		ShardCount:               1,
+	}
+}
+
+func validKafkaConfig() KafkaConfig {
+	return KafkaConfig{
+		Brokers:                []string{"kafka-0.kafka:9092", "kafka-1.kafka:9092"},
+		TopicPrefix:            "kyma",
+		TopicPartitions:        3,
+		TopicReplicationFactor: 3,
+		ConsumerGroupPrefix:    "kyma-eventing",
+		DialTimeout:            10 * time.Second,
+	}
+}
+
+func validBridgeConfig() BridgeConfig {
+	return BridgeConfig{
+		ClusterName:      "eu-1",
+		EventTypes:       []string{"shop.order.created.v1", "billing.>"},
+		TargetPublishURL: "https://eventing-publisher.eu-2.example.com/publish",
+		MaxInFlight:      10,
+		RequestTimeout:   10 * time.Second,
+		RetryDelay:       5 * time.Second,
+	}
+}
+
+func Test_NATSConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		givenConfig  func(*NATSConfig)
+		wantErrorMsg []string
+	}{
+		{
+			name:        "valid configuration",
+			givenConfig: func(*NATSConfig) {},
+		},
+		{
+			name: "valid configuration with multiple URLs and probing",
+			givenConfig: func(c *NATSConfig) {
+				c.URL = "nats://nats-0:4222, tls://nats-1:4222"
+				c.SinkProbeInterval = time.Minute
+			},
+		},
+		{
+			name: "invalid URL",
+			givenConfig: func(c *NATSConfig) {
+				c.URL = "nats://nats-0:4222,nats-1"
+			},
+			wantErrorMsg: []string{`NATS_URL must be an absolute URL such as nats://host:port, got "nats-1"`},
+		},
+		{
+			name: "TLS certificate without key",
+			givenConfig: func(c *NATSConfig) {
+				c.TLSCertFile = "/etc/nats/tls.crt"
+			},
+			wantErrorMsg: []string{
+				`NATS_TLS_CERT_FILE and NATS_TLS_KEY_FILE must be set together, got "/etc/nats/tls.crt" and ""`,
+			},
+		},
+		{
+			name: "all errors are reported",
+			givenConfig: func(c *NATSConfig) {
+				c.JSStreamName = "sap.stream"
+				c.JSStreamReplicas = 7
+				c.JSStreamStorageType = "disk"
+				c.JSStreamMaxBytes = "lots"
+			},
+			wantErrorMsg: []string{
+				`JS_STREAM_NAME must not be empty or contain whitespaces or any of ".*>/\\", got "sap.stream"`,
+				`JS_STREAM_STORAGE_TYPE must be one of ["memory" "file"], got "disk"`,
+				"JS_STREAM_REPLICAS must be between 1 and 5, got 7",
+				`JS_STREAM_MAX_BYTES must be a quantity such as 128Mi, got "lots"`,
+			},
+		},
+		{
+			name: "inconsistent stream limits",
+			givenConfig: func(c *NATSConfig) {
+				c.JSStreamMaxMessages = 100
+				c.JSStreamMaxMsgsPerTopic = 1000
+			},
+			wantErrorMsg: []string{"JS_STREAM_MAX_MSGS_PER_TOPIC must not exceed JS_STREAM_MAX_MSGS 100, got 1000"},
+		},
+		{
+			name: "probe timeout exceeding the probe interval",
+			givenConfig: func(c *NATSConfig) {
+				c.SinkProbeInterval = time.Second
+			},
+			wantErrorMsg: []string{"SINK_PROBE_TIMEOUT must be positive and not exceed SINK_PROBE_INTERVAL 1s, got 5s"},
+		},
+		{
+			name: "valid dead-letter stream",
+			givenConfig: func(c *NATSConfig) {
+				c.JSDeadLetterStreamName = "sap_deadletter"
+				c.JSDeadLetterSubjectPrefix = "deadletter"
+			},
+		},
+		{
+			name: "dead-letter subjects overlapping with the stream subjects",
+			givenConfig: func(c *NATSConfig) {
+				c.JSDeadLetterStreamName = "sap"
+				c.JSDeadLetterSubjectPrefix = "kyma.deadletter"
+			},
+			wantErrorMsg: []string{
+				`JS_DEAD_LETTER_STREAM_NAME must differ from JS_STREAM_NAME and not contain whitespaces ` +
+					`or any of ".*>/\\", got "sap"`,
+				`JS_DEAD_LETTER_SUBJECT_PREFIX must not be empty, contain whitespaces or wildcards, ` +
+					`or overlap with JS_STREAM_SUBJECT_PREFIX "kyma", got "kyma.deadletter"`,
+			},
+		},
+		{
+			name: "negative log sampling",
+			givenConfig: func(c *NATSConfig) {
+				c.DispatchLogSamplesPerSecond = -1
+			},
+			wantErrorMsg: []string{"DISPATCH_LOG_SAMPLES_PER_SECOND must not be negative, got -1"},
+		},
+		{
+			name: "should fail if the CloudEvents validation is invalid",
+			givenConfig: func(c *NATSConfig) {
+				c.CEValidationPolicy = "loose"
+				c.CEMaxEventSize = -1
+			},
+			wantErrorMsg: []string{
+				`CE_VALIDATION_POLICY must be one of ["strict" "lenient"], got "loose"`,
+				"CE_MAX_EVENT_SIZE must not be negative, got -1",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			config := validNATSConfig()
+			tc.givenConfig(&config)
+
+			// when
+			err := config.Validate()
+
+			// then
+			requireErrorMessages(t, err, tc.wantErrorMsg)
+		})
+	}
+}
+
+func Test_Config_Validate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		givenConfig  func(*Config)
+		wantErrorMsg []string
+	}{
+		{
+			name:        "valid configuration",
+			givenConfig: func(*Config) {},
+		},
+		{
+			name: "invalid values",
+			givenConfig: func(c *Config) {
+				c.Qos = "EXACTLY_ONCE"
+				c.ContentMode = "binary"
+				c.ShardCount = 2
+				c.ShardIndex = 2
+			},
+			wantErrorMsg: []string{
+				`QOS must be one of ["AT_LEAST_ONCE" "AT_MOST_ONCE"], got "EXACTLY_ONCE"`,
+				`CONTENT_MODE must be one of ["" "BINARY" "STRUCTURED"], got "binary"`,
+				"SHARD_INDEX must be at least 0 and lower than SHARD_COUNT 2, got 2",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			config := validConfig()
+			tc.givenConfig(&config)
+
+			// when
+			err := config.Validate()
+
+			// then
+			requireErrorMessages(t, err, tc.wantErrorMsg)
+		})
+	}
+}
+
+func Test_KafkaConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		givenConfig  func(*KafkaConfig)
+		wantErrorMsg []string
+	}{
+		{
+			name:        "valid configuration",
+			givenConfig: func(*KafkaConfig) {},
+		},
+		{
+			name: "invalid values",
+			givenConfig: func(c *KafkaConfig) {
+				c.Brokers = []string{"kafka-0.kafka"}
+				c.TopicPrefix = "kyma/events"
+				c.TopicPartitions = 0
+				c.ConsumerGroupPrefix = ""
+			},
+			wantErrorMsg: []string{
+				`KAFKA_BROKERS must be host:port addresses, got "kafka-0.kafka"`,
+				`KAFKA_TOPIC_PREFIX must consist of at most 100 letters, digits, '.', '_' or '-', got "kyma/events"`,
+				"KAFKA_TOPIC_PARTITIONS must be at least 1, got 0",
+				"KAFKA_CONSUMER_GROUP_PREFIX must not be empty",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			config := validKafkaConfig()
+			tc.givenConfig(&config)
+
+			// when
+			err := config.Validate()
+
+			// then
+			requireErrorMessages(t, err, tc.wantErrorMsg)
+		})
+	}
+}
+
+func Test_BridgeConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		givenConfig  func(*BridgeConfig)
+		wantErrorMsg []string
+	}{
+		{
+			name:        "valid configuration",
+			givenConfig: func(*BridgeConfig) {},
+		},
+		{
+			name: "a disabled bridge is not validated",
+			givenConfig: func(c *BridgeConfig) {
+				c.TargetPublishURL = ""
+				c.ClusterName = ""
+			},
+		},
+		{
+			name: "invalid values",
+			givenConfig: func(c *BridgeConfig) {
+				c.TargetNATSURL = "nats://nats.eu-2.example.com:7422"
+				c.ClusterName = ""
+				c.EventTypes = []string{"shop..created"}
+				c.MaxInFlight = 0
+			},
+			wantErrorMsg: []string{
+				"either BRIDGE_TARGET_PUBLISH_URL or BRIDGE_TARGET_NATS_URL must be set, not both",
+				`BRIDGE_CLUSTER_NAME must not be empty or contain whitespaces, got ""`,
+				`BRIDGE_EVENT_TYPES must be NATS subjects without the subject prefix, got "shop..created"`,
+				"BRIDGE_MAX_IN_FLIGHT must be at least 1, got 0",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			config := validBridgeConfig()
+			tc.givenConfig(&config)
+
+			// when
+			err := config.Validate()
+
+			// then
+			requireErrorMessages(t, err, tc.wantErrorMsg)
+		})
+	}
+}
+
+func Test_WebhookCertConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		givenConfig  func(*WebhookCertConfig)
+		wantErrorMsg []string
+	}{
+		{
+			name:        "default configuration",
+			givenConfig: func(*WebhookCertConfig) {},
+		},
+		{
+			name: "a disabled certificate management is not validated",
+			givenConfig: func(c *WebhookCertConfig) {
+				c.Enabled = false
+				c.ServiceName = ""
+			},
+		},
+		{
+			name: "invalid values",
+			givenConfig: func(c *WebhookCertConfig) {
+				c.ServiceName = ""
+				c.RenewBefore = 2 * c.Validity
+				c.CheckInterval = 0
+			},
+			wantErrorMsg: []string{
+				"WEBHOOK_SERVICE_NAME must not be empty",
+				"WEBHOOK_CERT_RENEW_BEFORE must be positive and less than WEBHOOK_CERT_VALIDITY 8760h0m0s, got 17520h0m0s",
+				"WEBHOOK_CERT_CHECK_INTERVAL must be positive and less than WEBHOOK_CERT_RENEW_BEFORE 17520h0m0s, got 0s",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			config, err := GetWebhookCertConfig()
+			require.NoError(t, err)
+			tc.givenConfig(&config)
+
+			// when
+			err = config.Validate()
+
+			// then
+			requireErrorMessages(t, err, tc.wantErrorMsg)
+		})
+	}
+}
+
+func Test_BackendConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		givenConfig  func(*BackendConfig)
+		wantErrorMsg []string
+	}{
+		{
+			name:        "default configuration",
+			givenConfig: func(*BackendConfig) {},
+		},
+		{
+			name: "inconsistent in-flight messages",
+			givenConfig: func(c *BackendConfig) {
+				c.DefaultSubscriptionConfig.MaxInFlightMessagesLimit = 5
+				c.SubscriptionQuota.MaxInFlightMessages = 8
+			},
+			wantErrorMsg: []string{
+				"DEFAULT_MAX_IN_FLIGHT_MESSAGES must not exceed MAX_IN_FLIGHT_MESSAGES_LIMIT 5, got 10",
+				"NAMESPACE_MAX_IN_FLIGHT_MESSAGES must be 0 or at least DEFAULT_MAX_IN_FLIGHT_MESSAGES 10, got 8",
+			},
+		},
+		{
+			name: "invalid delivery mode",
+			givenConfig: func(c *BackendConfig) {
+				c.DefaultSubscriptionConfig.DeliveryMode = "stream"
+			},
+			wantErrorMsg: []string{
+				`DEFAULT_DELIVERY_MODE must be one of ["jetstream" "core"], got "stream"`,
+			},
+		},
+		{
+			name: "invalid publisher configuration",
+			givenConfig: func(c *BackendConfig) {
+				c.PublisherConfig.PortNum = 0
+				c.PublisherConfig.RequestTimeout = "5"
+			},
+			wantErrorMsg: []string{
+				"PUBLISHER_PORT_NUM must be a port number, got 0",
+				`PUBLISHER_REQUEST_TIMEOUT must be a positive duration such as 5s, got "5"`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			config, err := LoadBackendConfig()
+			require.NoError(t, err)
+			tc.givenConfig(&config)
+
+			// when
+			err = config.Validate()
+
+			// then
+			requireErrorMessages(t, err, tc.wantErrorMsg)
+		})
+	}
+}
+
+// requireErrorMessages requires the given joined error to consist of exactly the given messages.
+func requireErrorMessages(t *testing.T, err error, wantErrorMsg []string) {
+	t.Helper()
+	if len(wantErrorMsg) == 0 {
+		require.NoError(t, err)
+		return
+	}
+	require.Error(t, err)
+	joined, ok := err.(interface{ Unwrap() []error })
+	require.True(t, ok)
+	var gotErrorMsg []string
+	for _, e := range joined.Unwrap() {
+		gotErrorMsg = append(gotErrorMsg, e.Error())
+	}
+	require.Equal(t, wantErrorMsg, gotErrorMsg)
+}
@@ -0,0 +1,209 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// ConfigWatcher loads the configuration files of a directory into the environment and reloads them on changes.
+// Each file is named by an environment variable and contains its value, like the keys of a ConfigMap or Secret
+// mounted as a volume. The values of the files override the environment variables of the container.
+type ConfigWatcher struct {
+	dir string
+
+	mutex sync.Mutex
+	// originals are the values of the environment variables before they were overridden by a file,
+	// or nil if they were not set, to restore them when the file is removed.
+	originals map[string]*string
+	// reloadable tells whether an environment variable may be changed at runtime, or is nil if all of them may.
+	reloadable func(name string) bool
+	// pending are the values of the files of the environment variables which are not reloadable and were changed,
+	// or nil if the file was removed, to report each change only once.
+	pending map[string]*string
+}
+
+// NewConfigWatcher returns a new ConfigWatcher of the configuration files of the given directory.
+func NewConfigWatcher(dir string) *ConfigWatcher {
+	return &ConfigWatcher{dir: dir, originals: map[string]*string{}, pending: map[string]*string{}}
+}
+
+// SetReloadable sets the function telling which environment variables the later loads may change.
+// The changes of the other environment variables are still returned by Load, but they are not applied
+// until the process is restarted.
+func (w *ConfigWatcher) SetReloadable(reloadable func(name string) bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.reloadable = reloadable
+}
+
+// Load sets the environment variables to the values of the configuration files and restores the ones whose file
+// was removed. It returns the sorted names of the environment variables whose value changed.
+// The environment variables which are not reloadable are left unchanged; their changes are returned once.
+func (w *ConfigWatcher) Load() ([]string, error) {
+	values, err := w.readFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	var changed []string
+	for name, value := range values {
+		current, ok := os.LookupEnv(name)
+		if _, overridden := w.originals[name]; !overridden {
+			if ok {
+				w.originals[name] = &current
+			} else {
+				w.originals[name] = nil
+			}
+		}
+		if ok && current == value {
+			delete(w.pending, name)
+			continue
+		}
+		if !w.isReloadable(name) {
+			if w.markPending(name, &value) {
+				changed = append(changed, name)
+			}
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return nil, err
+		}
+		changed = append(changed, name)
+	}
+	for name, original := range w.originals {
+		if _, ok := values[name]; ok {
+			continue
+		}
+		if !w.isReloadable(name) {
+			if current, ok := os.LookupEnv(name); !equalValues(original, lookup(current, ok)) {
+				if w.markPending(name, nil) {
+					changed = append(changed, name)
+				}
+				continue
+			}
+			// the file had the original value, so there is nothing to restore
+			delete(w.originals, name)
+			delete(w.pending, name)
+			continue
+		}
+		delete(w.originals, name)
+		delete(w.pending, name)
+		if original == nil {
+			err = os.Unsetenv(name)
+		} else {
+			err = os.Setenv(name, *original)
+		}
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// isReloadable tells whether the given environment variable may be changed. The caller must hold the lock.
+func (w *ConfigWatcher) isReloadable(name string) bool {
+	return w.reloadable == nil || w.reloadable(name)
+}
+
+// markPending records the changed value of the file of an environment variable which is not reloadable,
+// and returns false if the change was already recorded. The caller must hold the lock.
+func (w *ConfigWatcher) markPending(name string, value *string) bool {
+	if pending, ok := w.pending[name]; ok && equalValues(pending, value) {
+		return false
+	}
+	w.pending[name] = value
+	return true
+}
+
+// equalValues tells whether both values are unset or equal.
+func equalValues(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// lookup returns the given value of an environment variable, or nil if it is not set.
+func lookup(value string, ok bool) *string {
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+// readFiles returns the values of the configuration files by their name.
+// The hidden files are skipped, which includes the internal files of the mounted volumes, such as `..data`.
+func (w *ConfigWatcher) readFiles() (map[string]string, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the configuration directory %s: %w", w.dir, err)
+	}
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		// the files of the mounted volumes are symbolic links, so the entry type cannot tell about directories
+		path := filepath.Join(w.dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the configuration file %s: %w", path, err)
+		}
+		if info.IsDir() {
+			continue
+		}
+		value, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the configuration file %s: %w", path, err)
+		}
+		values[entry.Name()] = strings.TrimSpace(string(value))
+	}
+	return values, nil
+}
+
+// Watch loads the configuration files whenever the directory changes, and calls the given function with the names
+// of the environment variables whose value changed. It blocks until the given context is done.
+func (w *ConfigWatcher) Watch(ctx context.Context, logger *zap.SugaredLogger, onChange func(changed []string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := watcher.Close(); err != nil {
+			logger.Warnw("Failed to close the configuration watcher", "error", err)
+		}
+	}()
+	if err := watcher.Add(w.dir); err != nil {
+		return fmt.Errorf("failed to watch the configuration directory %s: %w", w.dir, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcher.Errors:
+			logger.Warnw("Failed to watch the configuration directory", "directory", w.dir, "error", err)
+		case <-watcher.Events:
+			changed, err := w.Load()
+			if err != nil {
+				logger.Errorw("Failed to reload the configuration", "directory", w.dir, "error", err)
+				continue
+			}
+			if len(changed) > 0 {
+				onChange(changed)
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package env
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func Test_ConfigWatcher_Load(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	t.Setenv("TEST_CONFIG_OVERRIDDEN", "container")
+	// registers the variable to be unset again by the cleanup
+	t.Setenv("TEST_CONFIG_ADDED", "")
+	require.NoError(t, os.Unsetenv("TEST_CONFIG_ADDED"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TEST_CONFIG_OVERRIDDEN"), []byte("file\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TEST_CONFIG_ADDED"), []byte("added"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "..data"), []byte("internal"), 0o600))
+	watcher := NewConfigWatcher(dir)
+
+	// when
+	changed, err := watcher.Load()
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, []string{"TEST_CONFIG_ADDED", "TEST_CONFIG_OVERRIDDEN"}, changed)
+	require.Equal(t, "file", os.Getenv("TEST_CONFIG_OVERRIDDEN"))
+	require.Equal(t, "added", os.Getenv("TEST_CONFIG_ADDED"))
+
+	// when nothing changed
+	changed, err = watcher.Load()
+
+	// then
+	require.NoError(t, err)
+	require.Empty(t, changed)
+
+	// when the files are changed and removed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TEST_CONFIG_ADDED"), []byte("changed"), 0o600))
+	require.NoError(t, os.Remove(filepath.Join(dir, "TEST_CONFIG_OVERRIDDEN")))
+	changed, err = watcher.Load()
+
+	// then the removed variables are restored
+	require.NoError(t, err)
+	require.Equal(t, []string{"TEST_CONFIG_ADDED", "TEST_CONFIG_OVERRIDDEN"}, changed)
+	require.Equal(t, "container", os.Getenv("TEST_CONFIG_OVERRIDDEN"))
+	require.Equal(t, "changed", os.Getenv("TEST_CONFIG_ADDED"))
+
+	// when the last file is removed
+	require.NoError(t, os.Remove(filepath.Join(dir, "TEST_CONFIG_ADDED")))
+	changed, err = watcher.Load()
+
+	// then the variables not set before are unset
+	require.NoError(t, err)
+	require.Equal(t, []string{"TEST_CONFIG_ADDED"}, changed)
+	_, ok := os.LookupEnv("TEST_CONFIG_ADDED")
+	require.False(t, ok)
+}
+
+func Test_ConfigWatcher_LoadNotReloadable(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	t.Setenv("TEST_CONFIG_RELOADABLE", "container")
+	t.Setenv("TEST_CONFIG_RESTART", "container")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TEST_CONFIG_RESTART"), []byte("file"), 0o600))
+	watcher := NewConfigWatcher(dir)
+	changed, err := watcher.Load()
+	require.NoError(t, err)
+	require.Equal(t, []string{"TEST_CONFIG_RESTART"}, changed)
+	watcher.SetReloadable(func(name string) bool { return name == "TEST_CONFIG_RELOADABLE" })
+
+	// when both files are changed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TEST_CONFIG_RELOADABLE"), []byte("changed"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TEST_CONFIG_RESTART"), []byte("changed"), 0o600))
+	changed, err = watcher.Load()
+
+	// then both changes are reported, but only the reloadable variable is changed
+	require.NoError(t, err)
+	require.Equal(t, []string{"TEST_CONFIG_RELOADABLE", "TEST_CONFIG_RESTART"}, changed)
+	require.Equal(t, "changed", os.Getenv("TEST_CONFIG_RELOADABLE"))
+	require.Equal(t, "file", os.Getenv("TEST_CONFIG_RESTART"))
+
+	// when nothing changed
+	changed, err = watcher.Load()
+
+	// then the pending change is not reported again
+	require.NoError(t, err)
+	require.Empty(t, changed)
+
+	// when the file of the variable which is not reloadable is removed
+	require.NoError(t, os.Remove(filepath.Join(dir, "TEST_CONFIG_RESTART")))
+	changed, err = watcher.Load()
+
+	// then the removal is reported, but the variable is not restored
+	require.NoError(t, err)
+	require.Equal(t, []string{"TEST_CONFIG_RESTART"}, changed)
+	require.Equal(t, "file", os.Getenv("TEST_CONFIG_RESTART"))
+
+	// when the file is written with the current value again
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "TEST_CONFIG_RESTART"), []byte("file"), 0o600))
+	changed, err = watcher.Load()
+
+	// then there is no change
+	require.NoError(t, err)
+	require.Empty(t, changed)
+}
+
+func Test_ConfigWatcher_Watch(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	t.Setenv("TEST_CONFIG_WATCHED", "container")
+	watcher := NewConfigWatcher(dir)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan []string, 10)
+	done := make(chan error)
+	go func() {
+		done <- watcher.Watch(ctx, zap.NewNop().Sugar(), func(changed []string) { changes <- changed })
+	}()
+
+	// when
+	require.Eventually(t, func() bool {
+		// the file is written until the watcher is started and notices it
+		err := os.WriteFile(filepath.Join(dir, "TEST_CONFIG_WATCHED"), []byte("file"), 0o600)
+		// the truncated file might be loaded before it is written, so the watcher is waited for to load the content
+		return err == nil && len(changes) > 0 && os.Getenv("TEST_CONFIG_WATCHED") == "file"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// then
+	require.Equal(t, []string{"TEST_CONFIG_WATCHED"}, <-changes)
+	cancel()
+	require.NoError(t, <-done)
+}
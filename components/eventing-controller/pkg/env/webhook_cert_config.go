@@ -0,0 +1,43 @@
+package env
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// WebhookCertConfig represents the environment config of the built-in generation and rotation of the serving
+// certificate of the Subscription webhooks.
+type WebhookCertConfig struct {
+	// Enabled generates and rotates the certificate in the controller. Disable it if the Secret and the CA bundles
+	// are managed externally, such as by cert-manager.
+	Enabled bool `envconfig:"WEBHOOK_CERT_MANAGEMENT_ENABLED" default:"true"`
+
+	// SecretName is the name of the Secret the certificate is stored in, shared by all replicas.
+	SecretName string `envconfig:"WEBHOOK_SECRET_NAME" default:"eventing-webhook-server-cert"`
+	// Namespace is the namespace of the Secret and the webhook Service.
+	Namespace string `envconfig:"WEBHOOK_NAMESPACE" default:"kyma-system"`
+	// ServiceName is the name of the webhook Service the certificate is issued for.
+	ServiceName string `envconfig:"WEBHOOK_SERVICE_NAME" default:"eventing-controller-webhook-service"`
+
+	// The webhook configurations and the CRDs with a conversion webhook the CA bundle is patched into.
+	MutatingWebhookName string `envconfig:"MUTATING_WEBHOOK_NAME" default:"subscription-mutating-webhook-configuration"`
+	//nolint:lll
+	ValidatingWebhookName string   `envconfig:"VALIDATING_WEBHOOK_NAME" default:"subscription-validating-webhook-configuration"`
+	ConversionCRDNames    []string `envconfig:"WEBHOOK_CONVERSION_CRD_NAMES" default:"subscriptions.eventing.kyma-project.io"`
+
+	// Validity is the validity of a generated certificate.
+	Validity time.Duration `envconfig:"WEBHOOK_CERT_VALIDITY" default:"8760h"`
+	// RenewBefore is the remaining validity at which the certificate is rotated.
+	RenewBefore time.Duration `envconfig:"WEBHOOK_CERT_RENEW_BEFORE" default:"720h"`
+	// CheckInterval is the interval between the checks of the certificate and the CA bundles.
+	CheckInterval time.Duration `envconfig:"WEBHOOK_CERT_CHECK_INTERVAL" default:"10m"`
+}
+
+func GetWebhookCertConfig() (WebhookCertConfig, error) {
+	cfg := WebhookCertConfig{}
+	if err := envconfig.Process("", &cfg); err != nil {
+		return WebhookCertConfig{}, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,253 @@
+// Package migration exports the Subscriptions of a cluster to a portable manifest and imports them into another
+// cluster or backend, so that the Subscriptions survive cluster rebuilds and blue/green migrations.
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+// ExportedAnnotations are the annotations kept by the export, all others are specific to the exported cluster.
+//
+//nolint:gochecknoglobals // constant lookup table
+var ExportedAnnotations = map[string]bool{
+	eventingv1alpha2.BackendAnnotation: true,
+	eventingv1alpha2.ExplainAnnotation: true,
+}
+
+// Export returns the Subscriptions of the namespace, or of all namespaces if it is empty, without their status
+// and cluster-specific metadata. The omitted fields are set to the effective defaults, so that the Subscriptions
+// behave the same in a cluster with different defaults. They are sorted by namespace and name.
+func Export(ctx context.Context, reader client.Reader, namespace string,
+	defaults env.DefaultSubscriptionConfig) ([]eventingv1alpha2.Subscription, error) {
+	var subs eventingv1alpha2.SubscriptionList
+	if err := reader.List(ctx, &subs, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	exported := make([]eventingv1alpha2.Subscription, 0, len(subs.Items))
+	for i := range subs.Items {
+		exported = append(exported, exportSubscription(&subs.Items[i], defaults))
+	}
+	sort.Slice(exported, func(i, j int) bool {
+		if exported[i].Namespace != exported[j].Namespace {
+			return exported[i].Namespace < exported[j].Namespace
+		}
+		return exported[i].Name < exported[j].Name
+	})
+	return exported, nil
+}
+
+func exportSubscription(sub *eventingv1alpha2.Subscription,
+	defaults env.DefaultSubscriptionConfig) eventingv1alpha2.Subscription {
+	exported := eventingv1alpha2.Subscription{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: eventingv1alpha2.GroupVersion.String(),
+			Kind:       "Subscription",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: sub.Namespace,
+			Name:      sub.Name,
+			Labels:    sub.Labels,
+		},
+		Spec: *sub.Spec.DeepCopy(),
+	}
+	for key, value := range sub.Annotations {
+		if ExportedAnnotations[key] {
+			if exported.Annotations == nil {
+				exported.Annotations = map[string]string{}
+			}
+			exported.Annotations[key] = value
+		}
+	}
+
+	// the ID is assigned by the controller of the exported cluster
+	exported.Spec.ID = ""
+	if exported.Spec.TypeMatching == "" {
+		exported.Spec.TypeMatching = eventingv1alpha2.TypeMatchingStandard
+	}
+	if exported.Spec.Source == "" && exported.Spec.TypeMatching == eventingv1alpha2.TypeMatchingStandard {
+		exported.Spec.Source = defaults.Source
+	}
+	if exported.Spec.Config == nil {
+		exported.Spec.Config = map[string]string{}
+	}
+	exported.Spec.Config[eventingv1alpha2.MaxInFlightMessages] = strconv.Itoa(sub.GetMaxInFlightMessages(&defaults))
+	// the expiry is kept as the absolute date, the TTL would restart with the creation in the other cluster
+	if exported.Spec.TTL != nil {
+		expiryDate := metav1.NewTime(sub.CreationTimestamp.Add(exported.Spec.TTL.Duration))
+		exported.Spec.ExpiryDate = &expiryDate
+		exported.Spec.TTL = nil
+	}
+	return exported
+}
+
+// manifest is the YAML List of the exported Subscriptions as written by WriteManifest.
+type manifest struct {
+	APIVersion string                   `json:"apiVersion"`
+	Kind       string                   `json:"kind"`
+	Items      []map[string]interface{} `json:"items"`
+}
+
+// subscriptionList is the YAML List of the exported Subscriptions as read by ReadManifest.
+type subscriptionList struct {
+	Kind  string                          `json:"kind"`
+	Items []eventingv1alpha2.Subscription `json:"items"`
+}
+
+// WriteManifest writes the Subscriptions without their status as a YAML List, which can also be applied
+// with kubectl.
+func WriteManifest(w io.Writer, subs []eventingv1alpha2.Subscription) error {
+	list := manifest{APIVersion: "v1", Kind: "List", Items: []map[string]interface{}{}}
+	for i := range subs {
+		data, err := json.Marshal(&subs[i])
+		if err != nil {
+			return err
+		}
+		var item map[string]interface{}
+		if err := json.Unmarshal(data, &item); err != nil {
+			return err
+		}
+		delete(item, "status")
+		if metadata, ok := item["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "creationTimestamp")
+		}
+		list.Items = append(list.Items, item)
+	}
+	data, err := yaml.Marshal(&list)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// ReadManifest reads the Subscriptions of a YAML List written by WriteManifest.
+func ReadManifest(r io.Reader) ([]eventingv1alpha2.Subscription, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var list subscriptionList
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if list.Kind != "List" {
+		return nil, fmt.Errorf("invalid manifest: kind must be List, got %q", list.Kind)
+	}
+	for i, sub := range list.Items {
+		if sub.Kind != "Subscription" || sub.Name == "" || sub.Namespace == "" {
+			return nil, fmt.Errorf("invalid manifest: item %d must be a Subscription with a namespace and name", i)
+		}
+	}
+	return list.Items, nil
+}
+
+// SinkRule replaces the prefix From of the sinks with To.
+type SinkRule struct {
+	From string
+	To   string
+}
+
+// ParseSinkRule parses a rule of the form <from>=<to>.
+func ParseSinkRule(s string) (SinkRule, error) {
+	from, to, ok := strings.Cut(s, "=")
+	if !ok || from == "" {
+		return SinkRule{}, fmt.Errorf("invalid sink rule %q, must be <from>=<to>", s)
+	}
+	return SinkRule{From: from, To: to}, nil
+}
+
+// Options are the changes applied to the Subscriptions when they are imported.
+type Options struct {
+	// SinkRules remap the sinks of the Subscriptions, the first rule matching a sink is applied.
+	// The sinkRefs are not remapped, as they reference a Service in the namespace of the Subscription.
+	SinkRules []SinkRule
+	// Backend selects the backend of the Subscriptions with the BackendAnnotation if it is not empty.
+	Backend string
+}
+
+// Prepare returns the Subscriptions with the options applied.
+func Prepare(subs []eventingv1alpha2.Subscription, opts Options) []eventingv1alpha2.Subscription {
+	prepared := make([]eventingv1alpha2.Subscription, 0, len(subs))
+	for i := range subs {
+		sub := subs[i].DeepCopy()
+		for _, rule := range opts.SinkRules {
+			if strings.HasPrefix(sub.Spec.Sink, rule.From) {
+				sub.Spec.Sink = rule.To + strings.TrimPrefix(sub.Spec.Sink, rule.From)
+				break
+			}
+		}
+		if opts.Backend != "" {
+			if sub.Annotations == nil {
+				sub.Annotations = map[string]string{}
+			}
+			sub.Annotations[eventingv1alpha2.BackendAnnotation] = opts.Backend
+		}
+		prepared = append(prepared, *sub)
+	}
+	return prepared
+}
+
+// Result is the outcome of importing a Subscription.
+type Result struct {
+	Namespace string
+	Name      string
+	// Action is either created, updated or failed.
+	Action string
+	Error  error
+}
+
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionFailed  = "failed"
+)
+
+// Import creates the Subscriptions, or updates the spec, labels and annotations of the existing ones.
+// It continues after a Subscription failed, for example because the webhook rejected it, and reports the outcome
+// of each Subscription.
+func Import(ctx context.Context, c client.Client, subs []eventingv1alpha2.Subscription) []Result {
+	results := make([]Result, 0, len(subs))
+	for i := range subs {
+		action, err := importSubscription(ctx, c, subs[i].DeepCopy())
+		if err != nil {
+			action = ActionFailed
+		}
+		results = append(results, Result{Namespace: subs[i].Namespace, Name: subs[i].Name, Action: action, Error: err})
+	}
+	return results
+}
+
+func importSubscription(ctx context.Context, c client.Client, sub *eventingv1alpha2.Subscription) (string, error) {
+	existing := &eventingv1alpha2.Subscription{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}, existing)
+	if k8serrors.IsNotFound(err) {
+		return ActionCreated, c.Create(ctx, sub)
+	}
+	if err != nil {
+		return "", err
+	}
+	existing.Spec = sub.Spec
+	existing.Labels = sub.Labels
+	for key, value := range sub.Annotations {
+		if existing.Annotations == nil {
+			existing.Annotations = map[string]string{}
+		}
+		existing.Annotations[key] = value
+	}
+	return ActionUpdated, c.Update(ctx, existing)
+}
@@ -0,0 +1,200 @@
+package migration_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/migration"
+)
+
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, eventingv1alpha2.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func newSubscription(namespace, name, sink string) *eventingv1alpha2.Subscription {
+	return &eventingv1alpha2.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: eventingv1alpha2.SubscriptionSpec{
+			Sink:   sink,
+			Source: "shop",
+			Types:  []string{"order.created.v1"},
+		},
+	}
+}
+
+func Test_Export(t *testing.T) {
+	// given
+	created := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	withDefaults := newSubscription("shop", "orders", "http://orders.shop.svc.cluster.local")
+	withDefaults.Spec.Source = ""
+	withDefaults.Spec.ID = "id-of-the-old-cluster"
+	withDefaults.CreationTimestamp = created
+	withDefaults.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+	withDefaults.Annotations = map[string]string{
+		eventingv1alpha2.BackendAnnotation:                 "nats",
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+	}
+	withDefaults.Status.Ready = true
+	withConfig := newSubscription("billing", "invoices", "http://invoices.billing.svc.cluster.local")
+	withConfig.Spec.TypeMatching = eventingv1alpha2.TypeMatchingExact
+	withConfig.Spec.Config = map[string]string{eventingv1alpha2.MaxInFlightMessages: "3"}
+	k8sClient := newFakeClient(t, withDefaults, withConfig)
+
+	// when
+	subs, err := migration.Export(context.Background(), k8sClient, "",
+		env.DefaultSubscriptionConfig{MaxInFlightMessages: 10, Source: "kyma"})
+
+	// then the Subscriptions are sorted, and the defaults are resolved
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	require.Equal(t, "billing", subs[0].Namespace)
+	require.Equal(t, eventingv1alpha2.TypeMatchingExact, subs[0].Spec.TypeMatching)
+	require.Equal(t, "shop", subs[0].Spec.Source)
+	require.Equal(t, "3", subs[0].Spec.Config[eventingv1alpha2.MaxInFlightMessages])
+
+	require.Equal(t, "shop", subs[1].Namespace)
+	require.Equal(t, "Subscription", subs[1].Kind)
+	require.Empty(t, subs[1].Spec.ID)
+	require.Equal(t, eventingv1alpha2.TypeMatchingStandard, subs[1].Spec.TypeMatching)
+	require.Equal(t, "kyma", subs[1].Spec.Source)
+	require.Equal(t, "10", subs[1].Spec.Config[eventingv1alpha2.MaxInFlightMessages])
+	require.Nil(t, subs[1].Spec.TTL)
+	require.True(t, created.Add(time.Hour).Equal(subs[1].Spec.ExpiryDate.Time))
+	require.Equal(t, map[string]string{eventingv1alpha2.BackendAnnotation: "nats"}, subs[1].Annotations)
+	require.False(t, subs[1].Status.Ready)
+}
+
+func Test_WriteManifest_ReadManifest(t *testing.T) {
+	// given
+	sub := newSubscription("shop", "orders", "http://orders.shop.svc.cluster.local")
+	sub.TypeMeta = metav1.TypeMeta{APIVersion: eventingv1alpha2.GroupVersion.String(), Kind: "Subscription"}
+	var buf bytes.Buffer
+
+	// when
+	require.NoError(t, migration.WriteManifest(&buf, []eventingv1alpha2.Subscription{*sub}))
+	manifest := buf.String()
+	subs, err := migration.ReadManifest(&buf)
+
+	// then
+	require.NoError(t, err)
+	require.Contains(t, manifest, "kind: List")
+	require.NotContains(t, manifest, "status:")
+	require.NotContains(t, manifest, "creationTimestamp")
+	require.Len(t, subs, 1)
+	require.Equal(t, sub.Name, subs[0].Name)
+	require.Equal(t, sub.Spec, subs[0].Spec)
+}
+
+func Test_ReadManifest_Invalid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		manifest  string
+		wantError string
+	}{
+		{
+			name:      "not a List",
+			manifest:  "apiVersion: v1\nkind: ConfigMap\n",
+			wantError: `invalid manifest: kind must be List, got "ConfigMap"`,
+		},
+		{
+			name:      "item without namespace",
+			manifest:  "apiVersion: v1\nkind: List\nitems:\n- kind: Subscription\n  metadata:\n    name: orders\n",
+			wantError: "invalid manifest: item 0 must be a Subscription with a namespace and name",
+		},
+		{
+			name:      "item of another kind",
+			manifest:  "kind: List\nitems:\n- kind: Secret\n  metadata:\n    name: orders\n    namespace: shop\n",
+			wantError: "invalid manifest: item 0 must be a Subscription with a namespace and name",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := migration.ReadManifest(strings.NewReader(tc.manifest))
+			require.EqualError(t, err, tc.wantError)
+		})
+	}
+}
+
+func Test_ParseSinkRule(t *testing.T) {
+	rule, err := migration.ParseSinkRule("http://orders.shop.svc.cluster.local=https://orders.example.com")
+	require.NoError(t, err)
+	require.Equal(t, migration.SinkRule{
+		From: "http://orders.shop.svc.cluster.local",
+		To:   "https://orders.example.com",
+	}, rule)
+
+	_, err = migration.ParseSinkRule("http://orders.shop.svc.cluster.local")
+	require.Error(t, err)
+	_, err = migration.ParseSinkRule("=https://orders.example.com")
+	require.Error(t, err)
+}
+
+func Test_Prepare(t *testing.T) {
+	// given
+	subs := []eventingv1alpha2.Subscription{
+		*newSubscription("shop", "orders", "http://orders.shop.svc.cluster.local/events"),
+		*newSubscription("billing", "invoices", "http://invoices.billing.svc.cluster.local"),
+	}
+
+	// when
+	prepared := migration.Prepare(subs, migration.Options{
+		SinkRules: []migration.SinkRule{
+			{From: "http://orders.shop", To: "http://orders-v2.shop"},
+			{From: "http://orders", To: "http://not-applied"},
+		},
+		Backend: "nats",
+	})
+
+	// then only the first matching rule is applied, and the input is not changed
+	require.Equal(t, "http://orders-v2.shop.svc.cluster.local/events", prepared[0].Spec.Sink)
+	require.Equal(t, "http://invoices.billing.svc.cluster.local", prepared[1].Spec.Sink)
+	for _, sub := range prepared {
+		require.Equal(t, "nats", sub.Annotations[eventingv1alpha2.BackendAnnotation])
+	}
+	require.Equal(t, "http://orders.shop.svc.cluster.local/events", subs[0].Spec.Sink)
+	require.Empty(t, subs[0].Annotations)
+}
+
+func Test_Import(t *testing.T) {
+	// given an existing Subscription with a finalizer and an annotation of the cluster
+	existing := newSubscription("shop", "orders", "http://old.shop.svc.cluster.local")
+	existing.Finalizers = []string{"eventing.kyma-project.io"}
+	existing.Annotations = map[string]string{"team": "shop"}
+	k8sClient := newFakeClient(t, existing)
+	updated := newSubscription("shop", "orders", "http://orders.shop.svc.cluster.local")
+	updated.Annotations = map[string]string{eventingv1alpha2.BackendAnnotation: "nats"}
+	created := newSubscription("billing", "invoices", "http://invoices.billing.svc.cluster.local")
+
+	// when
+	results := migration.Import(context.Background(), k8sClient,
+		[]eventingv1alpha2.Subscription{*updated, *created})
+
+	// then
+	require.Equal(t, []migration.Result{
+		{Namespace: "shop", Name: "orders", Action: migration.ActionUpdated},
+		{Namespace: "billing", Name: "invoices", Action: migration.ActionCreated},
+	}, results)
+
+	got := &eventingv1alpha2.Subscription{}
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "shop", Name: "orders"}, got))
+	require.Equal(t, updated.Spec.Sink, got.Spec.Sink)
+	require.Equal(t, existing.Finalizers, got.Finalizers)
+	require.Equal(t, map[string]string{"team": "shop", eventingv1alpha2.BackendAnnotation: "nats"}, got.Annotations)
+	require.NoError(t, k8sClient.Get(context.Background(),
+		types.NamespacedName{Namespace: "billing", Name: "invoices"}, &eventingv1alpha2.Subscription{}))
+}
@@ -0,0 +1,160 @@
+// Package profiling serves the pprof, expvar and snapshot endpoints used to profile the eventing-controller,
+// such as the memory growth during large resyncs, in production clusters.
+package profiling
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/tokenauth"
+)
+
+const (
+	// PprofEndpoint is the path prefix of the pprof endpoints.
+	PprofEndpoint = "/debug/pprof/"
+
+	// VarsEndpoint is the path of the expvar endpoint, which includes the runtime memory statistics.
+	VarsEndpoint = "/debug/vars"
+
+	// SnapshotEndpoint is the path of the endpoint writing a heap and a goroutine snapshot to the snapshot directory.
+	SnapshotEndpoint = "/debug/snapshot"
+
+	// MaxSnapshots is the number of snapshots kept in the snapshot directory, older snapshots are removed.
+	MaxSnapshots = 5
+
+	// gcQueryParameter runs a garbage collection before the heap snapshot if set to true.
+	gcQueryParameter = "gc"
+
+	heapSnapshotPrefix      = "heap-"
+	goroutineSnapshotPrefix = "goroutine-"
+	snapshotTimeFormat      = "20060102T150405.000000000Z"
+
+	handlerName = "profiling"
+)
+
+// SnapshotResponse is the response of the SnapshotEndpoint.
+type SnapshotResponse struct {
+	Heap      string `json:"heap"`
+	Goroutine string `json:"goroutine"`
+}
+
+// Handlers returns the handlers of the PprofEndpoint, VarsEndpoint and SnapshotEndpoint by path. All of them require
+// the bearer token stored in the given token file, which is read on every request to follow its rotation.
+// The snapshots are written to the given directory.
+func Handlers(tokenFile, snapshotDir string, logger *logger.Logger) map[string]http.Handler {
+	namedLogger := logger.WithContext().Named(handlerName)
+
+	pprofMux := http.NewServeMux()
+	pprofMux.HandleFunc(PprofEndpoint, pprof.Index)
+	pprofMux.HandleFunc(PprofEndpoint+"cmdline", pprof.Cmdline)
+	pprofMux.HandleFunc(PprofEndpoint+"profile", pprof.Profile)
+	pprofMux.HandleFunc(PprofEndpoint+"symbol", pprof.Symbol)
+	pprofMux.HandleFunc(PprofEndpoint+"trace", pprof.Trace)
+
+	snapshots := &snapshotter{dir: snapshotDir, logger: namedLogger}
+	return map[string]http.Handler{
+		PprofEndpoint:    tokenauth.RequireToken(handlerName, tokenFile, pprofMux, namedLogger),
+		VarsEndpoint:     tokenauth.RequireToken(handlerName, tokenFile, expvar.Handler(), namedLogger),
+		SnapshotEndpoint: tokenauth.RequireToken(handlerName, tokenFile, snapshots, namedLogger),
+	}
+}
+
+// snapshotter writes the heap and goroutine snapshots to a directory, so that they can be copied from the pod
+// after the fact, for example after a resync which took too long to profile interactively.
+type snapshotter struct {
+	dir    string
+	logger *zap.SugaredLogger
+
+	// mutex serializes the snapshots, so that their file names are unique and the cleanup does not race.
+	mutex sync.Mutex
+}
+
+func (s *snapshotter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	response, err := s.snapshot(r.URL.Query().Get(gcQueryParameter) == "true")
+	if err != nil {
+		s.logger.Errorw("Failed to write the snapshot", "dir", s.dir, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.logger.Infow("Wrote the snapshot", "heap", response.Heap, "goroutine", response.Goroutine)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// snapshot writes the heap profile in the pprof format and the stacks of all goroutines as text, and removes
+// the snapshots exceeding MaxSnapshots.
+func (s *snapshotter) snapshot(gc bool) (SnapshotResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return SnapshotResponse{}, err
+	}
+	if gc {
+		runtime.GC()
+	}
+	timestamp := time.Now().UTC().Format(snapshotTimeFormat)
+	response := SnapshotResponse{
+		Heap:      filepath.Join(s.dir, heapSnapshotPrefix+timestamp+".pb.gz"),
+		Goroutine: filepath.Join(s.dir, goroutineSnapshotPrefix+timestamp+".txt"),
+	}
+	if err := writeProfile("heap", 0, response.Heap); err != nil {
+		return SnapshotResponse{}, err
+	}
+	// debug level 2 prints the stacks of the goroutines like an unrecovered panic, including their wait times
+	if err := writeProfile("goroutine", 2, response.Goroutine); err != nil {
+		return SnapshotResponse{}, err
+	}
+	for _, prefix := range []string{heapSnapshotPrefix, goroutineSnapshotPrefix} {
+		if err := s.removeOldSnapshots(prefix); err != nil {
+			return SnapshotResponse{}, err
+		}
+	}
+	return response, nil
+}
+
+func writeProfile(name string, debug int, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := rpprof.Lookup(name).WriteTo(file, debug); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write the %s profile: %w", name, err)
+	}
+	return file.Close()
+}
+
+// removeOldSnapshots removes the oldest snapshots with the given prefix exceeding MaxSnapshots. The file names
+// sort by their timestamps.
+func (s *snapshotter) removeOldSnapshots(prefix string) error {
+	paths, err := filepath.Glob(filepath.Join(s.dir, prefix+"*"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+	for len(paths) > MaxSnapshots {
+		if err := os.Remove(paths[0]); err != nil {
+			return err
+		}
+		paths = paths[1:]
+	}
+	return nil
+}
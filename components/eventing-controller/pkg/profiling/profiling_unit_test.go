@@ -0,0 +1,132 @@
+//go:build unit
+
+package profiling //nolint:testpackage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+)
+
+const token = "secret-token"
+
+func newHandlers(t *testing.T) (map[string]http.Handler, string, string) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte(token+"\n"), 0o600))
+	snapshotDir := filepath.Join(dir, "snapshots")
+	defaultLogger, err := logger.New("json", "info")
+	require.NoError(t, err)
+	return Handlers(tokenFile, snapshotDir, defaultLogger), tokenFile, snapshotDir
+}
+
+func Test_Handlers_Authorization(t *testing.T) {
+	handlers, tokenFile, _ := newHandlers(t)
+	mux := http.NewServeMux()
+	for path, handler := range handlers {
+		mux.Handle(path, handler)
+	}
+
+	testCases := []struct {
+		name            string
+		givenPath       string
+		givenAuthHeader string
+		givenNoToken    bool
+		wantStatus      int
+	}{
+		{
+			name:            "should serve the pprof index with the token",
+			givenPath:       PprofEndpoint,
+			givenAuthHeader: "Bearer " + token,
+			wantStatus:      http.StatusOK,
+		},
+		{
+			name:            "should serve a named pprof profile with the token",
+			givenPath:       PprofEndpoint + "heap",
+			givenAuthHeader: "Bearer " + token,
+			wantStatus:      http.StatusOK,
+		},
+		{
+			name:            "should serve the expvar variables with the token",
+			givenPath:       VarsEndpoint,
+			givenAuthHeader: "Bearer " + token,
+			wantStatus:      http.StatusOK,
+		},
+		{
+			name:       "should fail without the token",
+			givenPath:  PprofEndpoint + "heap",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:            "should fail with a wrong token",
+			givenPath:       VarsEndpoint,
+			givenAuthHeader: "Bearer wrong-token",
+			wantStatus:      http.StatusUnauthorized,
+		},
+		{
+			name:            "should fail if the token file is missing",
+			givenPath:       VarsEndpoint,
+			givenAuthHeader: "Bearer " + token,
+			givenNoToken:    true,
+			wantStatus:      http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.givenNoToken {
+				require.NoError(t, os.Rename(tokenFile, tokenFile+".old"))
+				defer func() { require.NoError(t, os.Rename(tokenFile+".old", tokenFile)) }()
+			}
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, tc.givenPath, nil)
+			if tc.givenAuthHeader != "" {
+				request.Header.Set("Authorization", tc.givenAuthHeader)
+			}
+			mux.ServeHTTP(recorder, request)
+			require.Equal(t, tc.wantStatus, recorder.Code)
+		})
+	}
+}
+
+func Test_Snapshot(t *testing.T) {
+	// given
+	handlers, _, snapshotDir := newHandlers(t)
+	handler := handlers[SnapshotEndpoint]
+
+	// when more snapshots are written than kept
+	var response SnapshotResponse
+	for i := 0; i < MaxSnapshots+2; i++ {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, SnapshotEndpoint+"?gc=true", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	}
+
+	// then only the latest snapshots are kept
+	require.FileExists(t, response.Heap)
+	require.FileExists(t, response.Goroutine)
+	goroutines, err := os.ReadFile(response.Goroutine)
+	require.NoError(t, err)
+	require.Contains(t, string(goroutines), "goroutine ")
+	files, err := os.ReadDir(snapshotDir)
+	require.NoError(t, err)
+	require.Len(t, files, 2*MaxSnapshots)
+
+	// and the snapshots are only written on POST
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, SnapshotEndpoint, nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
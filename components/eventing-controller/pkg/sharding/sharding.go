@@ -0,0 +1,65 @@
+package sharding
+
+import (
+	"hash/fnv"
+
+	"golang.org/x/xerrors"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+)
+
+// Shard identifies the subset of Subscriptions reconciled by one eventing-controller replica.
+// Subscriptions are assigned to shards by the hash of their namespace/name key.
+// The zero value owns all Subscriptions.
+type Shard struct {
+	Index int
+	Count int
+}
+
+// New returns the Shard with the given index out of count shards.
+// It returns an error if the index is not within the range [0, count).
+func New(index, count int) (Shard, error) {
+	if count < 1 {
+		return Shard{}, xerrors.Errorf("invalid shard count %d: must be at least 1", count)
+	}
+	if index < 0 || index >= count {
+		return Shard{}, xerrors.Errorf("invalid shard index %d: must be within [0, %d)", index, count)
+	}
+	return Shard{Index: index, Count: count}, nil
+}
+
+// IsEnabled returns true if Subscriptions are distributed over more than one shard.
+func (s Shard) IsEnabled() bool {
+	return s.Count > 1
+}
+
+// IsPrimary returns true if the shard is responsible for the work that is not tied to a single
+// Subscription, e.g. cleaning up dangling backend resources. The first shard is the primary one.
+func (s Shard) IsPrimary() bool {
+	return s.Index == 0
+}
+
+// Owns returns true if the Subscription with the given namespace and name belongs to the shard.
+func (s Shard) Owns(namespace, name string) bool {
+	if !s.IsEnabled() {
+		return true
+	}
+	h := fnv.New32a()
+	// writing to a hash never returns an error
+	_, _ = h.Write([]byte(namespace + "/" + name))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index
+}
+
+// Filter returns the Subscriptions which belong to the shard.
+func (s Shard) Filter(subscriptions []eventingv1alpha2.Subscription) []eventingv1alpha2.Subscription {
+	if !s.IsEnabled() {
+		return subscriptions
+	}
+	owned := make([]eventingv1alpha2.Subscription, 0, len(subscriptions)/s.Count+1)
+	for i := range subscriptions {
+		if s.Owns(subscriptions[i].Namespace, subscriptions[i].Name) {
+			owned = append(owned, subscriptions[i])
+		}
+	}
+	return owned
+}
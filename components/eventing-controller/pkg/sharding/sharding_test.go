@@ -0,0 +1,74 @@
+package sharding_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name       string
+		givenIndex int
+		givenCount int
+		wantError  bool
+	}{
+		{name: "single shard", givenIndex: 0, givenCount: 1, wantError: false},
+		{name: "last shard", givenIndex: 2, givenCount: 3, wantError: false},
+		{name: "zero shards", givenIndex: 0, givenCount: 0, wantError: true},
+		{name: "index out of range", givenIndex: 3, givenCount: 3, wantError: true},
+		{name: "negative index", givenIndex: -1, givenCount: 3, wantError: true},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := sharding.New(tc.givenIndex, tc.givenCount)
+			require.Equal(t, tc.wantError, err != nil)
+		})
+	}
+}
+
+func TestShardOwns(t *testing.T) {
+	// given
+	const shardCount = 3
+	shards := make([]sharding.Shard, 0, shardCount)
+	for i := 0; i < shardCount; i++ {
+		shard, err := sharding.New(i, shardCount)
+		require.NoError(t, err)
+		shards = append(shards, shard)
+	}
+
+	subs := make([]eventingv1alpha2.Subscription, 0, 100)
+	for i := 0; i < 100; i++ {
+		sub := eventingv1alpha2.Subscription{}
+		sub.Namespace = "ns"
+		sub.Name = fmt.Sprintf("sub-%d", i)
+		subs = append(subs, sub)
+	}
+
+	// when
+	owned := 0
+	for _, shard := range shards {
+		owned += len(shard.Filter(subs))
+	}
+
+	// then
+	// every subscription is owned by exactly one shard
+	require.Equal(t, len(subs), owned)
+	for _, sub := range subs {
+		owners := 0
+		for _, shard := range shards {
+			if shard.Owns(sub.Namespace, sub.Name) {
+				owners++
+			}
+		}
+		require.Equal(t, 1, owners, "subscription %s/%s", sub.Namespace, sub.Name)
+	}
+
+	// the zero value owns everything
+	require.Len(t, sharding.Shard{}.Filter(subs), len(subs))
+}
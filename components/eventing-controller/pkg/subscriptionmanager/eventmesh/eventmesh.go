@@ -22,6 +22,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
@@ -32,6 +33,7 @@ import (
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
 	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/subscriptionmanager"
 )
 
@@ -63,15 +65,18 @@ func AddV1Alpha2ToScheme(scheme *runtime.Scheme) error {
 
 // SubscriptionManager implements the subscriptionmanager.Manager interface.
 type SubscriptionManager struct {
-	cancel           context.CancelFunc
-	envCfg           env.Config
-	restCfg          *rest.Config
-	metricsAddr      string
-	resyncPeriod     time.Duration
-	mgr              manager.Manager
-	eventMeshBackend backendeventmesh.Backend
-	logger           *logger.Logger
-	collector        *metrics.Collector
+	cancel            context.CancelFunc
+	envCfg            env.Config
+	restCfg           *rest.Config
+	metricsAddr       string
+	resyncPeriod      time.Duration
+	mgr               manager.Manager
+	eventMeshBackend  backendeventmesh.Backend
+	logger            *logger.Logger
+	collector         *metrics.Collector
+	shard             sharding.Shard
+	controllerOptions controller.Options
+	cleanerMapping    *cleaner.Mapping
 }
 
 // NewSubscriptionManager creates the SubscriptionManager for BEB and initializes it as far as it
@@ -88,6 +93,21 @@ func NewSubscriptionManager(restCfg *rest.Config, metricsAddr string, resyncPeri
 	}
 }
 
+// SetControllerOptions sets the workqueue options of the subscription controller.
+func (c *SubscriptionManager) SetControllerOptions(options controller.Options) {
+	c.controllerOptions = options
+}
+
+// SetShard restricts the subscription manager to the Subscriptions which belong to the given shard.
+func (c *SubscriptionManager) SetShard(shard sharding.Shard) {
+	c.shard = shard
+}
+
+// SetCleanerMapping records the event types cleaned by the subscription manager in the given mapping.
+func (c *SubscriptionManager) SetCleanerMapping(mapping *cleaner.Mapping) {
+	c.cleanerMapping = mapping
+}
+
 // Init implements the subscriptionmanager.Manager interface.
 func (c *SubscriptionManager) Init(mgr manager.Manager) error {
 	if len(c.envCfg.Domain) == 0 {
@@ -125,7 +145,26 @@ func (c *SubscriptionManager) Start(_ env.DefaultSubscriptionConfig, params subs
 
 	// Initialize v1alpha2 handler for EventMesh
 	eventMeshHandler := backendeventmesh.NewEventMesh(oauth2credential, nameMapper, c.logger)
-	eventMeshcleaner := cleaner.NewEventMeshCleaner(c.logger)
+	eventMeshcleaner, err := cleaner.New(c.envCfg.CleanerStrategy, c.logger, cleaner.CharacterPolicy{
+		AllowedCharacters: c.envCfg.CleanerAllowedCharacters,
+		Replacement:       c.envCfg.CleanerReplacement,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create event type cleaner failed")
+	}
+	validationCleaner := eventMeshcleaner
+	// EventMesh only runs as the secondary backend next to NATS
+	if secondary := subscriptionmanager.GetSecondaryBackend(params); secondary == eventingv1alpha2.BackendEventMesh {
+		eventingv1alpha2.InitializeBackends(eventingv1alpha2.BackendNATS, secondary)
+	} else {
+		eventingv1alpha2.InitializeBackends(eventingv1alpha2.BackendEventMesh, secondary)
+	}
+	eventingv1alpha2.InitializeCleanedTypesValidation(func(eventTypes []string) error {
+		return cleaner.ValidateEventTypes(validationCleaner, eventTypes)
+	})
+	if c.cleanerMapping != nil {
+		eventMeshcleaner = cleaner.NewMappingCleaner(eventMeshcleaner, c.cleanerMapping)
+	}
 	eventMeshReconciler := eventmesh.NewReconciler(
 		ctx,
 		client,
@@ -140,6 +179,9 @@ func (c *SubscriptionManager) Start(_ env.DefaultSubscriptionConfig, params subs
 		c.collector,
 	)
 	c.eventMeshBackend = eventMeshReconciler.Backend
+	eventMeshReconciler.SetShard(c.shard)
+	eventMeshReconciler.SetSecondaryBackend(subscriptionmanager.GetSecondaryBackend(params))
+	eventMeshReconciler.SetControllerOptions(c.controllerOptions)
 	if err := eventMeshReconciler.SetupUnmanaged(c.mgr); err != nil {
 		return xerrors.Errorf("setup EventMesh subscription controller failed: %v", err)
 	}
@@ -163,13 +205,14 @@ func (c *SubscriptionManager) Stop(runCleanup bool) error {
 func (c *SubscriptionManager) stopEventMeshBackend(runCleanup bool) error {
 	dynamicClient := dynamic.NewForConfigOrDie(c.restCfg)
 	if !runCleanup {
-		return markAllV1Alpha2SubscriptionsAsNotReady(dynamicClient, c.namedLogger())
+		return markAllV1Alpha2SubscriptionsAsNotReady(dynamicClient, c.shard, c.namedLogger())
 	}
 
-	return cleanupEventMesh(c.eventMeshBackend, dynamicClient, c.namedLogger())
+	return cleanupEventMesh(c.eventMeshBackend, dynamicClient, c.shard, c.namedLogger())
 }
 
-func markAllV1Alpha2SubscriptionsAsNotReady(dynamicClient dynamic.Interface, logger *zap.SugaredLogger) error {
+func markAllV1Alpha2SubscriptionsAsNotReady(dynamicClient dynamic.Interface, shard sharding.Shard,
+	logger *zap.SugaredLogger) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	// Fetch all subscriptions.
@@ -181,8 +224,8 @@ func markAllV1Alpha2SubscriptionsAsNotReady(dynamicClient dynamic.Interface, log
 	if err != nil {
 		return errors.Wrapf(err, "convert subscriptionList from unstructured list failed")
 	}
-	// Mark all as not ready
-	for _, sub := range subs.Items {
+	// Mark all subscriptions owned by this shard as not ready
+	for _, sub := range shard.Filter(subs.Items) {
 		if !sub.Status.Ready {
 			continue
 		}
@@ -198,7 +241,8 @@ func markAllV1Alpha2SubscriptionsAsNotReady(dynamicClient dynamic.Interface, log
 }
 
 // cleanupEventMesh removes all created EventMesh artifacts (based on Subscription v1alpha2).
-func cleanupEventMesh(backend backendeventmesh.Backend, dynamicClient dynamic.Interface, logger *zap.SugaredLogger) error {
+func cleanupEventMesh(backend backendeventmesh.Backend, dynamicClient dynamic.Interface, shard sharding.Shard,
+	logger *zap.SugaredLogger) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -218,9 +262,9 @@ func cleanupEventMesh(backend backendeventmesh.Backend, dynamicClient dynamic.In
 		return errors.Wrapf(err, "convert subscriptionList from unstructured list failed")
 	}
 
-	// Clean APIRules.
+	// Clean APIRules of the subscriptions owned by this shard.
 	isCleanupSuccessful := true
-	for _, v := range subs.Items {
+	for _, v := range shard.Filter(subs.Items) {
 		sub := v
 		if apiRule := sub.Status.Backend.APIRuleName; apiRule != "" {
 			if err := dynamicClient.Resource(backendutils.APIRuleGroupVersionResource()).Namespace(sub.Namespace).
@@ -25,6 +25,7 @@ import (
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/client"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
 	controllertesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
 )
 
@@ -140,7 +141,7 @@ func Test_cleanupEventMesh(t *testing.T) {
 	require.NotNil(t, unstructuredAPIRuleBeforeCleanup)
 
 	// when
-	err = cleanupEventMesh(bebSubMgr.eventMeshBackend, bebSubMgr.Client, defaultLogger.WithContext())
+	err = cleanupEventMesh(bebSubMgr.eventMeshBackend, bebSubMgr.Client, sharding.Shard{}, defaultLogger.WithContext())
 	require.NoError(t, err)
 
 	// then
@@ -206,7 +207,7 @@ func Test_markAllV1Alpha2SubscriptionsAsNotReady(t *testing.T) {
 	require.Equal(t, true, gotSub.Status.Ready)
 
 	// when
-	err = markAllV1Alpha2SubscriptionsAsNotReady(fakeClient, defaultLogger.WithContext())
+	err = markAllV1Alpha2SubscriptionsAsNotReady(fakeClient, sharding.Shard{}, defaultLogger.WithContext())
 	require.NoError(t, err)
 
 	// then
@@ -3,6 +3,7 @@ package jetstream
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
 	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
@@ -11,6 +12,7 @@ import (
 
 	"golang.org/x/xerrors"
 
+	"github.com/nats-io/nats.go"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -21,16 +23,19 @@ import (
 
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
 	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
 	"github.com/kyma-project/kyma/components/eventing-controller/controllers/subscription/jetstream"
 	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/audit"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/eventtype"
 	backendjetstream "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
 	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/subscriptionmanager"
 )
 
@@ -58,14 +63,21 @@ func AddV1Alpha2ToScheme(scheme *runtime.Scheme) error {
 }
 
 type SubscriptionManager struct {
-	cancel           context.CancelFunc
-	envCfg           env.NATSConfig
-	restCfg          *rest.Config
-	metricsAddr      string
-	metricsCollector *backendmetrics.Collector
-	mgr              manager.Manager
-	backendv2        backendjetstream.Backend
-	logger           *logger.Logger
+	cancel            context.CancelFunc
+	envCfg            env.NATSConfig
+	restCfg           *rest.Config
+	metricsAddr       string
+	metricsCollector  *backendmetrics.Collector
+	mgr               manager.Manager
+	backendv2         backendjetstream.Backend
+	logger            *logger.Logger
+	shard             sharding.Shard
+	controllerOptions controller.Options
+	cleanerMapping    *cleaner.Mapping
+	auditor           *audit.Auditor
+
+	// backendMutex guards the backend, which is replaced on every start and read by the webhook.
+	backendMutex sync.RWMutex
 }
 
 // NewSubscriptionManager creates the subscription manager for JetStream.
@@ -80,6 +92,31 @@ func NewSubscriptionManager(restCfg *rest.Config, natsConfig env.NATSConfig, met
 	}
 }
 
+// SetControllerOptions sets the workqueue options of the subscription controller.
+func (sm *SubscriptionManager) SetControllerOptions(options controller.Options) {
+	sm.controllerOptions = options
+}
+
+// SetNATSConfig replaces the NATS configuration used by the next start of the subscription manager.
+func (sm *SubscriptionManager) SetNATSConfig(natsConfig env.NATSConfig) {
+	sm.envCfg = natsConfig
+}
+
+// SetShard restricts the subscription manager to the Subscriptions which belong to the given shard.
+func (sm *SubscriptionManager) SetShard(shard sharding.Shard) {
+	sm.shard = shard
+}
+
+// SetCleanerMapping records the event types cleaned by the subscription manager in the given mapping.
+func (sm *SubscriptionManager) SetCleanerMapping(mapping *cleaner.Mapping) {
+	sm.cleanerMapping = mapping
+}
+
+// SetAuditor records the changes of the consumers made by the subscription manager with the given auditor.
+func (sm *SubscriptionManager) SetAuditor(auditor *audit.Auditor) {
+	sm.auditor = auditor
+}
+
 // Init initialize the JetStream subscription manager.
 func (sm *SubscriptionManager) Init(mgr manager.Manager) error {
 	if len(sm.envCfg.URL) == 0 {
@@ -90,7 +127,7 @@ func (sm *SubscriptionManager) Init(mgr manager.Manager) error {
 	return nil
 }
 
-func (sm *SubscriptionManager) Start(defaultSubsConfig env.DefaultSubscriptionConfig, _ subscriptionmanager.Params) error {
+func (sm *SubscriptionManager) Start(defaultSubsConfig env.DefaultSubscriptionConfig, params subscriptionmanager.Params) error {
 	sm.metricsCollector.ResetSubscriptionStatus()
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -104,9 +141,34 @@ func (sm *SubscriptionManager) Start(defaultSubsConfig env.DefaultSubscriptionCo
 	eventingv1alpha1.InitializeEventTypeCleaner(simpleCleaner)
 
 	// Initialize v1alpha2 event type cleaner
-	jsCleaner := cleaner.NewJetStreamCleaner(sm.logger)
+	jsCleaner, err := cleaner.New(sm.envCfg.CleanerStrategy, sm.logger, cleaner.CharacterPolicy{
+		AllowedCharacters: sm.envCfg.CleanerAllowedCharacters,
+		Replacement:       sm.envCfg.CleanerReplacement,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the event type cleaner: %w", err)
+	}
+	validationCleaner := jsCleaner
+	eventingv1alpha2.InitializeBackends(eventingv1alpha2.BackendNATS, subscriptionmanager.GetSecondaryBackend(params))
+	eventingv1alpha2.InitializeCleanedTypesValidation(func(eventTypes []string) error {
+		return cleaner.ValidateEventTypes(validationCleaner, eventTypes)
+	})
+	collisionPolicy := cleaner.CollisionPolicy{
+		HashCollisions:   sm.envCfg.CleanerHashCollisions,
+		MaxSegmentLength: sm.envCfg.CleanerMaxSegmentLength,
+	}
+	if collisionPolicy.Enabled() {
+		jsCleaner = cleaner.NewCollisionProofCleaner(jsCleaner, collisionPolicy, sm.logger)
+	}
+	if sm.cleanerMapping != nil {
+		jsCleaner = cleaner.NewMappingCleaner(jsCleaner, sm.cleanerMapping)
+	}
 	jetStreamHandler := backendjetstream.NewJetStream(sm.envCfg,
 		sm.metricsCollector, jsCleaner, defaultSubsConfig, sm.logger)
+	jetStreamHandler.SetEventRecorder(recorder)
+	jetStreamHandler.SetAuditor(sm.auditor)
+	jetStreamHandler.SetCABundleLoader(sink.NewCABundleLoader(sm.mgr.GetAPIReader()))
+	jetStreamHandler.SetTokenProvider(sink.NewTokenProvider(sm.mgr.GetClient()))
 	jetStreamReconciler := jetstream.NewReconciler(
 		ctx,
 		client,
@@ -117,19 +179,49 @@ func (sm *SubscriptionManager) Start(defaultSubsConfig env.DefaultSubscriptionCo
 		sink.NewValidator(ctx, client, recorder),
 		sm.metricsCollector,
 	)
+	sm.backendMutex.Lock()
 	sm.backendv2 = jetStreamReconciler.Backend
+	sm.backendMutex.Unlock()
+	jetStreamReconciler.SetShard(sm.shard)
+	jetStreamReconciler.SetSecondaryBackend(subscriptionmanager.GetSecondaryBackend(params))
+	jetStreamReconciler.SetControllerOptions(sm.controllerOptions)
+
+	if sm.envCfg.SinkProbeInterval > 0 {
+		prober, err := sink.NewProber(sm.envCfg.SinkProbeMethod, sm.envCfg.SinkProbeTimeout)
+		if err != nil {
+			return xerrors.Errorf("failed to create the sink prober: %v", err)
+		}
+		jetStreamReconciler.SetSinkProber(prober, sm.envCfg.SinkProbeInterval)
+	}
+	jetStreamReconciler.SetSinkHealthInterval(sm.envCfg.SinkHealthConditionInterval)
 
 	if err := jetStreamHandler.Initialize(jetStreamReconciler.HandleNatsConnClose); err != nil {
 		return fmt.Errorf("failed to initialise jetstream reconciler: %w", err)
 	}
-
-	// delete dangling invalid consumers here
-	var subs eventingv1alpha2.SubscriptionList
-	if err := client.List(context.Background(), &subs); err != nil {
-		return fmt.Errorf("failed to get all subscription resources: %w", err)
+	go func() {
+		if err := jetStreamHandler.WatchCredentials(ctx); err != nil {
+			sm.namedLogger().Errorw("Failed to watch the NATS credentials", "error", err)
+		}
+	}()
+	if sm.cleanerMapping != nil && sm.envCfg.CleanerMappingBucket != "" {
+		store, err := cleaner.NewKeyValueStoreForBucket(jetStreamHandler.GetJetStreamContext(),
+			sm.envCfg.CleanerMappingBucket)
+		if err != nil {
+			return fmt.Errorf("failed to persist the event type cleaner mapping: %w", err)
+		}
+		sm.cleanerMapping.SetStore(store)
 	}
-	if err := jetStreamHandler.DeleteInvalidConsumers(subs.Items); err != nil {
-		return err
+
+	// delete dangling invalid consumers here, the consumers are shared by all shards,
+	// so only the primary shard deletes them based on all subscriptions
+	if sm.shard.IsPrimary() {
+		var subs eventingv1alpha2.SubscriptionList
+		if err := client.List(context.Background(), &subs); err != nil {
+			return fmt.Errorf("failed to get all subscription resources: %w", err)
+		}
+		if err := jetStreamHandler.DeleteInvalidConsumers(subs.Items); err != nil {
+			return err
+		}
 	}
 
 	// start the subscription controller
@@ -141,18 +233,101 @@ func (sm *SubscriptionManager) Start(defaultSubsConfig env.DefaultSubscriptionCo
 	return nil
 }
 
+// CountPendingMessages returns the number of messages of the subscription which are not acknowledged yet.
+// It returns 0 if the JetStream subscription manager was never started.
+func (sm *SubscriptionManager) CountPendingMessages(subscription *eventingv1alpha2.Subscription) (int, error) {
+	sm.backendMutex.RLock()
+	defer sm.backendMutex.RUnlock()
+	if sm.backendv2 == nil {
+		return 0, nil
+	}
+	return sm.backendv2.CountPendingMessages(subscription)
+}
+
+// StreamUsage returns the usage of the JetStream stream.
+// It returns nil if the JetStream subscription manager was never started.
+func (sm *SubscriptionManager) StreamUsage() (*eventingv1alpha1.StreamUsage, error) {
+	sm.backendMutex.RLock()
+	defer sm.backendMutex.RUnlock()
+	if sm.backendv2 == nil || sm.backendv2.GetJetStreamContext() == nil {
+		return nil, nil
+	}
+	streamName := sm.backendv2.GetConfig().JSStreamName
+	info, err := sm.backendv2.GetJetStreamContext().StreamInfo(streamName)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get info of stream %s: %v", streamName, err)
+	}
+	return &eventingv1alpha1.StreamUsage{
+		Name:      streamName,
+		Messages:  int64(info.State.Msgs),
+		Bytes:     int64(info.State.Bytes),
+		Consumers: info.State.Consumers,
+	}, nil
+}
+
+// JetStreamStream returns the current JetStream context and stream name.
+// It returns a nil context if the JetStream subscription manager was never started.
+func (sm *SubscriptionManager) JetStreamStream() (nats.JetStreamContext, string) {
+	sm.backendMutex.RLock()
+	defer sm.backendMutex.RUnlock()
+	if sm.backendv2 == nil {
+		return nil, ""
+	}
+	return sm.backendv2.GetJetStreamContext(), sm.backendv2.GetConfig().JSStreamName
+}
+
+// DeadLetterStream returns the current JetStream context and dead-letter stream name.
+// It returns a nil context if the JetStream subscription manager was never started or dead-lettering is disabled.
+func (sm *SubscriptionManager) DeadLetterStream() (nats.JetStreamContext, string) {
+	sm.backendMutex.RLock()
+	defer sm.backendMutex.RUnlock()
+	if sm.backendv2 == nil || sm.backendv2.GetConfig().JSDeadLetterStreamName == "" {
+		return nil, ""
+	}
+	return sm.backendv2.GetJetStreamContext(), sm.backendv2.GetConfig().JSDeadLetterStreamName
+}
+
+// NATSConnection returns the current NATS connection.
+// It returns nil if the JetStream subscription manager was never started.
+func (sm *SubscriptionManager) NATSConnection() *nats.Conn {
+	sm.backendMutex.RLock()
+	defer sm.backendMutex.RUnlock()
+	jsBackend, ok := sm.backendv2.(*backendjetstream.JetStream)
+	if !ok {
+		return nil
+	}
+	return jsBackend.Conn
+}
+
+// Pause stops the subscription controller and unsubscribes all the JetStream subscriptions without deleting their
+// consumers, so no events are dispatched and the events are retained in the stream. Start resumes the dispatch.
+func (sm *SubscriptionManager) Pause() error {
+	sm.cancel()
+	sm.backendMutex.RLock()
+	defer sm.backendMutex.RUnlock()
+	jsBackend, ok := sm.backendv2.(*backendjetstream.JetStream)
+	if !ok {
+		return errors.New("converting backend to JetStream v2 backend failed")
+	}
+	return jsBackend.DeleteAllSubscriptionsOnly()
+}
+
 func (sm *SubscriptionManager) Stop(runCleanup bool) error {
 	sm.cancel()
+	if sm.cleanerMapping != nil {
+		sm.cleanerMapping.SetStore(nil)
+	}
 	if !runCleanup {
 		return nil
 	}
 	dynamicClient := dynamic.NewForConfigOrDie(sm.restCfg)
 
-	return cleanupv2(sm.backendv2, dynamicClient, sm.namedLogger())
+	return cleanupv2(sm.backendv2, dynamicClient, sm.shard, sm.namedLogger())
 }
 
 // clean removes all JetStream artifacts.
-func cleanupv2(backend backendjetstream.Backend, dynamicClient dynamic.Interface, logger *zap.SugaredLogger) error {
+func cleanupv2(backend backendjetstream.Backend, dynamicClient dynamic.Interface, shard sharding.Shard,
+	logger *zap.SugaredLogger) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -175,9 +350,9 @@ func cleanupv2(backend backendjetstream.Backend, dynamicClient dynamic.Interface
 		return errors.Wrapf(err, "convert subscriptionList from unstructured list failed")
 	}
 
-	// clean all status.
+	// clean all status of the subscriptions owned by this shard.
 	isCleanupSuccessful := true
-	for _, v := range subs.Items {
+	for _, v := range shard.Filter(subs.Items) {
 		sub := v
 		subKey := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}
 		log := logger.With("key", subKey.String())
@@ -19,6 +19,7 @@ import (
 	backendnats "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
 	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
 	controllertesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
 )
 
@@ -36,7 +37,7 @@ func TestCleanup(t *testing.T) {
 	testEnv.consumersEquals(t, 1)
 
 	// when
-	err := cleanupv2(testEnv.jsBackend, testEnv.dynamicClient, testEnv.defaultLogger.WithContext())
+	err := cleanupv2(testEnv.jsBackend, testEnv.dynamicClient, sharding.Shard{}, testEnv.defaultLogger.WithContext())
 
 	// then
 	require.NoError(t, err)
@@ -0,0 +1,202 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/controllers/subscription/kafka"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	backendkafka "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/kafka"
+	backendmetrics "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/sink"
+	backendutils "github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/utils"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/sharding"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/subscriptionmanager"
+)
+
+const (
+	subscriptionManagerName = "kafka-subscription-manager"
+)
+
+type SubscriptionManager struct {
+	cancel            context.CancelFunc
+	envCfg            env.KafkaConfig
+	restCfg           *rest.Config
+	metricsCollector  *backendmetrics.Collector
+	mgr               manager.Manager
+	backend           backendkafka.Backend
+	logger            *logger.Logger
+	shard             sharding.Shard
+	controllerOptions controller.Options
+}
+
+// NewSubscriptionManager creates the subscription manager for Kafka.
+func NewSubscriptionManager(restCfg *rest.Config, kafkaConfig env.KafkaConfig,
+	metricsCollector *backendmetrics.Collector, logger *logger.Logger) *SubscriptionManager {
+	return &SubscriptionManager{
+		envCfg:           kafkaConfig,
+		restCfg:          restCfg,
+		metricsCollector: metricsCollector,
+		logger:           logger,
+	}
+}
+
+// SetControllerOptions sets the workqueue options of the subscription controller.
+func (sm *SubscriptionManager) SetControllerOptions(options controller.Options) {
+	sm.controllerOptions = options
+}
+
+// SetKafkaConfig replaces the Kafka configuration used by the next start of the subscription manager.
+func (sm *SubscriptionManager) SetKafkaConfig(kafkaConfig env.KafkaConfig) {
+	sm.envCfg = kafkaConfig
+}
+
+// GetKafkaConfig returns the Kafka configuration used by the next start of the subscription manager.
+func (sm *SubscriptionManager) GetKafkaConfig() env.KafkaConfig {
+	return sm.envCfg
+}
+
+// SetShard restricts the subscription manager to the Subscriptions which belong to the given shard.
+func (sm *SubscriptionManager) SetShard(shard sharding.Shard) {
+	sm.shard = shard
+}
+
+// Init initialize the Kafka subscription manager.
+func (sm *SubscriptionManager) Init(mgr manager.Manager) error {
+	sm.mgr = mgr
+	sm.namedLogger().Info("initialized Kafka subscription manager")
+	return nil
+}
+
+func (sm *SubscriptionManager) Start(defaultSubsConfig env.DefaultSubscriptionConfig, _ subscriptionmanager.Params) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	sm.cancel = cancel
+
+	client := sm.mgr.GetClient()
+	recorder := sm.mgr.GetEventRecorderFor("eventing-controller-kafka")
+
+	kafkaCleaner, err := cleaner.New(sm.envCfg.CleanerStrategy, sm.logger, cleaner.CharacterPolicy{})
+	if err != nil {
+		return fmt.Errorf("failed to create the event type cleaner: %w", err)
+	}
+	// Kafka supports all the Subscription fields and does not run next to a secondary backend
+	eventingv1alpha2.InitializeBackends("", "")
+	eventingv1alpha2.InitializeCleanedTypesValidation(func(eventTypes []string) error {
+		return cleaner.ValidateEventTypes(kafkaCleaner, eventTypes)
+	})
+
+	kafkaHandler := backendkafka.NewKafka(sm.envCfg, sm.metricsCollector, kafkaCleaner, defaultSubsConfig, sm.logger)
+	kafkaHandler.SetCABundleLoader(sink.NewCABundleLoader(sm.mgr.GetAPIReader()))
+	kafkaHandler.SetTokenProvider(sink.NewTokenProvider(sm.mgr.GetClient()))
+	kafkaReconciler := kafka.NewReconciler(
+		ctx,
+		client,
+		kafkaHandler,
+		sm.logger,
+		recorder,
+		kafkaCleaner,
+		sink.NewValidator(ctx, client, recorder),
+		sm.metricsCollector,
+	)
+	sm.backend = kafkaHandler
+	kafkaReconciler.SetShard(sm.shard)
+	kafkaReconciler.SetControllerOptions(sm.controllerOptions)
+
+	if err := kafkaHandler.Initialize(); err != nil {
+		return fmt.Errorf("failed to initialise kafka reconciler: %w", err)
+	}
+
+	// delete dangling consumer groups here, the groups are shared by all shards,
+	// so only the primary shard deletes them based on all subscriptions
+	if sm.shard.IsPrimary() {
+		var subs eventingv1alpha2.SubscriptionList
+		if err := client.List(context.Background(), &subs); err != nil {
+			return fmt.Errorf("failed to get all subscription resources: %w", err)
+		}
+		if err := kafkaHandler.DeleteInvalidConsumerGroups(subs.Items); err != nil {
+			return err
+		}
+	}
+
+	// start the subscription controller
+	if err := kafkaReconciler.SetupUnmanaged(sm.mgr); err != nil {
+		return fmt.Errorf("unable to setup the Kafka subscription controller: %w", err)
+	}
+	sm.namedLogger().Info("Started v1alpha2 Kafka subscription manager")
+
+	return nil
+}
+
+func (sm *SubscriptionManager) Stop(runCleanup bool) error {
+	if sm.cancel != nil {
+		sm.cancel()
+	}
+	if sm.backend == nil {
+		return nil
+	}
+	defer sm.backend.Shutdown()
+	if !runCleanup {
+		return nil
+	}
+	dynamicClient := dynamic.NewForConfigOrDie(sm.restCfg)
+
+	return cleanup(sm.backend, dynamicClient, sm.shard, sm.namedLogger())
+}
+
+// cleanup resets the status of the Subscriptions and deletes their consumer groups. The topics are kept.
+func cleanup(backend backendkafka.Backend, dynamicClient dynamic.Interface, shard sharding.Shard,
+	logger *zap.SugaredLogger) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// fetch all subscriptions.
+	subscriptionsUnstructured, err := dynamicClient.Resource(
+		eventingv1alpha2.SubscriptionGroupVersionResource()).Namespace(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "list subscriptions failed")
+	}
+
+	subs, err := eventingv1alpha2.ConvertUnstructListToSubList(subscriptionsUnstructured)
+	if err != nil {
+		return errors.Wrapf(err, "convert subscriptionList from unstructured list failed")
+	}
+
+	// clean all status of the subscriptions owned by this shard.
+	isCleanupSuccessful := true
+	for _, v := range shard.Filter(subs.Items) {
+		sub := v
+		subKey := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}
+		log := logger.With("key", subKey.String())
+
+		desiredSub := sub.DuplicateWithStatusDefaults()
+		if updateErr := backendutils.UpdateSubscriptionStatus(ctx, dynamicClient, desiredSub); updateErr != nil {
+			isCleanupSuccessful = false
+			log.Errorw("Failed to update Kafka subscription status", "error", updateErr)
+		}
+
+		if delErr := backend.DeleteSubscription(&sub); delErr != nil {
+			isCleanupSuccessful = false
+			log.Errorw("Failed to delete Kafka consumer group", "error", delErr)
+		}
+	}
+
+	logger.Debugw("Finished cleanup process", "success", isCleanupSuccessful)
+	return nil
+}
+
+func (sm *SubscriptionManager) namedLogger() *zap.SugaredLogger {
+	return sm.logger.WithContext().Named(subscriptionManagerName)
+}
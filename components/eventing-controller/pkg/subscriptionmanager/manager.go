@@ -11,6 +11,10 @@ const (
 	ParamNameClientSecret = "client_secret"
 	ParamNameTokenURL     = "token_url"
 	ParamNameCertsURL     = "certs_url"
+
+	// ParamNameSecondaryBackend names the backend which runs next to the primary one, if any.
+	// Only the Subscriptions selecting it by the backend annotation are reconciled by the secondary backend.
+	ParamNameSecondaryBackend = "secondary_backend"
 )
 
 type Params map[string]interface{}
@@ -26,3 +30,9 @@ type Manager interface {
 	// Stop tells the subscription manager instance to shut down and clean-up.
 	Stop(runCleanup bool) error
 }
+
+// GetSecondaryBackend returns the secondary backend of the given params or an empty string if none is set.
+func GetSecondaryBackend(params Params) string {
+	secondaryBackend, _ := params[ParamNameSecondaryBackend].(string)
+	return secondaryBackend
+}
@@ -0,0 +1,34 @@
+// Package tokenauth guards the administrative HTTP endpoints of the eventing-controller with a bearer token
+// stored in a file, such as a mounted Secret.
+package tokenauth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// RequireToken returns a http.Handler calling the next handler only for the requests authorized by the bearer token
+// stored in the given token file, which is read on every request to follow its rotation. It responds with 401 to the
+// other requests, and with 503 if the token is unavailable. The name describes the token in the errors.
+func RequireToken(name, tokenFile string, next http.Handler, logger *zap.SugaredLogger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, err := os.ReadFile(tokenFile)
+		token := strings.TrimSpace(string(content))
+		if err != nil || token == "" {
+			logger.Errorw("Failed to read the "+name+" token", "file", tokenFile, "error", err)
+			http.Error(w, name+" token unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	cev2protocolhttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 
@@ -25,6 +27,13 @@ const (
 	b3SpanIDKey       = "X-B3-SpanId"
 	b3SampledKey      = "X-B3-Sampled"
 	b3FlagsKey        = "X-B3-Flags"
+
+	// correlationIDCEExtensionsKey is the extension of the correlation ID chosen by the publisher.
+	correlationIDCEExtensionsKey = "correlationid"
+	// CorrelationIDLogKey is the key of the correlation ID in the log lines of an event.
+	CorrelationIDLogKey = "correlationid"
+	// CorrelationIDNATSHeader is the header of the correlation ID in the NATS messages published by the publisher proxy.
+	CorrelationIDNATSHeader = "ce-correlationid"
 )
 
 func AddTracingHeadersToContext(ctx context.Context, ce *cev2.Event) context.Context {
@@ -66,6 +75,37 @@ func AddTracingHeadersToContext(ctx context.Context, ce *cev2.Event) context.Con
 	return ctx
 }
 
+// TraceIDFromContext returns the trace ID of the tracing headers added to the given context by
+// AddTracingHeadersToContext, or an empty string if there are none or the trace is not sampled.
+func TraceIDFromContext(ctx context.Context) string {
+	header := cev2protocolhttp.HeaderFrom(ctx)
+	if traceParent := header.Get(traceParentKey); traceParent != "" {
+		// the traceparent header is formatted as <version>-<trace-id>-<parent-id>-<trace-flags>
+		parts := strings.Split(traceParent, "-")
+		if len(parts) != 4 || len(parts[1]) != 32 {
+			return ""
+		}
+		flags, err := strconv.ParseUint(parts[3], 16, 8)
+		if err != nil || flags&1 == 0 {
+			return ""
+		}
+		return parts[1]
+	}
+	if header.Get(b3SampledKey) == "1" || header.Get(b3FlagsKey) == "1" {
+		return header.Get(b3TraceIDKey)
+	}
+	return ""
+}
+
+// CorrelationID returns the correlation ID of the event set by the publisher proxy,
+// which is the CloudEvent ID unless the publisher has chosen a different one.
+func CorrelationID(ce *cev2.Event) string {
+	if correlationID, ok := ce.Extensions()[correlationIDCEExtensionsKey].(string); ok && correlationID != "" {
+		return correlationID
+	}
+	return ce.ID()
+}
+
 func removeCEExtension(e *cev2.Event, key string) {
 	v1Context := e.Context.AsV1()
 	delete(v1Context.Extensions, key)
@@ -2,6 +2,7 @@ package tracing
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -68,6 +69,75 @@ func TestAddTracingHeadersToContext(t *testing.T) {
 	}
 }
 
+func TestTraceIDFromContext(t *testing.T) {
+	g := NewGomegaWithT(t)
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testCases := []struct {
+		name            string
+		event           *cev2event.Event
+		expectedTraceID string
+	}{
+		{
+			name: "sampled w3c trace",
+			event: NewEventWithExtensions(map[string]string{
+				traceParentCEExtensionsKey: "00-" + traceID + "-00f067aa0ba902b7-01",
+			}),
+			expectedTraceID: traceID,
+		}, {
+			name: "not sampled w3c trace",
+			event: NewEventWithExtensions(map[string]string{
+				traceParentCEExtensionsKey: "00-" + traceID + "-00f067aa0ba902b7-00",
+			}),
+			expectedTraceID: "",
+		}, {
+			name: "invalid w3c trace",
+			event: NewEventWithExtensions(map[string]string{
+				traceParentCEExtensionsKey: "foo",
+			}),
+			expectedTraceID: "",
+		}, {
+			name: "sampled b3 trace",
+			event: NewEventWithExtensions(map[string]string{
+				b3TraceIDCEExtensionsKey: traceID,
+				b3SampledCEExtensionsKey: "1",
+			}),
+			expectedTraceID: traceID,
+		}, {
+			name: "not sampled b3 trace",
+			event: NewEventWithExtensions(map[string]string{
+				b3TraceIDCEExtensionsKey: traceID,
+				b3SampledCEExtensionsKey: "0",
+			}),
+			expectedTraceID: "",
+		}, {
+			name:            "no trace",
+			event:           NewEventWithExtensions(map[string]string{}),
+			expectedTraceID: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := AddTracingHeadersToContext(context.Background(), tc.event)
+			g.Expect(TraceIDFromContext(ctx)).To(Equal(tc.expectedTraceID))
+		})
+	}
+}
+
+func TestCorrelationID(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the CloudEvent ID is the default correlation ID
+	event := cev2event.New()
+	event.SetID("id")
+	g.Expect(CorrelationID(&event)).To(Equal("id"))
+
+	// the correlation ID is read from the events published by the publisher proxy
+	g.Expect(json.Unmarshal([]byte(`{"specversion":"1.0","id":"id","source":"source","type":"type",`+
+		`"correlationid":"order-42"}`), &event)).To(Succeed())
+	g.Expect(CorrelationID(&event)).To(Equal("order-42"))
+}
+
 func getTracingExtensions(event *cev2event.Event) map[string]string {
 	traceExtensions := make(map[string]string)
 	for k, v := range event.Extensions() {
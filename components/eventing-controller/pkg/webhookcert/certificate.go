@@ -0,0 +1,105 @@
+package webhookcert
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	certificatePEMType = "CERTIFICATE"
+	privateKeyPEMType  = "EC PRIVATE KEY"
+
+	// clockSkew backdates the certificates, so that they are valid on API servers with a clock running behind.
+	clockSkew = time.Hour
+
+	serialNumberBits = 128
+)
+
+// dnsNames returns the DNS names the webhook Service is reached by from the API server.
+func dnsNames(serviceName, namespace string) []string {
+	return []string{
+		serviceName,
+		serviceName + "." + namespace,
+		serviceName + "." + namespace + ".svc",
+		serviceName + "." + namespace + ".svc.cluster.local",
+	}
+}
+
+// generateCertificate returns a new self-signed serving certificate of the webhook Service and its private key,
+// both PEM encoded. The certificate is its own CA, so that it is trusted by the CA bundles containing it.
+func generateCertificate(serviceName, namespace string, validity time.Duration, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate the private key: %w", err)
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberBits))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate the serial number: %w", err)
+	}
+	names := dnsNames(serviceName, namespace)
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: names[len(names)-2]},
+		DNSNames:              names,
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(validity),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create the certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode the private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: certificatePEMType, Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMType, Bytes: keyDER}), nil
+}
+
+// parseCertificate returns the first certificate of the given PEM data.
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != certificatePEMType {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// caBundle returns the PEM encoded CA bundle of the given certificate followed by the certificates of the given
+// previous CA bundle which are still valid. The previous certificates stay trusted after a rotation, so that the
+// replicas still serving them keep working until they load the new certificate.
+func caBundle(certPEM, previousBundle []byte, now time.Time) []byte {
+	bundle := bytes.NewBuffer(append([]byte{}, certPEM...))
+	for rest := previousBundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != certificatePEMType {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || !now.Before(cert.NotAfter) {
+			continue
+		}
+		encoded := pem.EncodeToMemory(block)
+		if !bytes.Contains(bundle.Bytes(), encoded) {
+			bundle.Write(encoded)
+		}
+	}
+	return bundle.Bytes()
+}
@@ -0,0 +1,261 @@
+// Package webhookcert generates and rotates the serving certificate of the Subscription webhooks, and patches
+// its CA bundle into the webhook configurations and the conversion webhooks of the CRDs.
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+const (
+	// CABundleKey is the key of the CA bundle in the Secret, which includes the previous certificates still valid.
+	CABundleKey = "ca.crt"
+
+	managerName = "webhook-cert-manager"
+
+	// retryInterval is the interval between the attempts of a failed check.
+	retryInterval = 10 * time.Second
+)
+
+var errNoCertificate = errors.New("the webhook serving certificate is not loaded yet")
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;update
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;update
+
+// Manager keeps the serving certificate of the webhooks in the Secret valid, and serves it to the webhook server.
+// It runs on all replicas, which share the certificate of the Secret. The replica which rotates the certificate first
+// wins, the other ones load its certificate with their next check.
+type Manager struct {
+	client client.Client
+	config env.WebhookCertConfig
+	logger *logger.Logger
+	now    func() time.Time
+
+	mutex       sync.RWMutex
+	certificate *tls.Certificate
+}
+
+// NewManager returns a new Manager. The client should not be cached, so that neither all Secrets nor all CRDs
+// of the cluster are watched.
+func NewManager(client client.Client, config env.WebhookCertConfig, logger *logger.Logger) *Manager {
+	return &Manager{client: client, config: config, logger: logger, now: time.Now}
+}
+
+// Start checks the certificate and the CA bundles every CheckInterval until the given context is done.
+// It implements the manager.Runnable interface.
+func (m *Manager) Start(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			interval := m.config.CheckInterval
+			if err := m.Sync(ctx); err != nil {
+				m.namedLogger().Errorw("Failed to sync the webhook certificate", "error", err)
+				interval = retryInterval
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+// NeedLeaderElection returns false, since all replicas serve the webhooks.
+func (m *Manager) NeedLeaderElection() bool {
+	return false
+}
+
+// GetCertificate returns the current serving certificate. It is set as tls.Config.GetCertificate of the webhook
+// server, so that a rotated certificate is served without a restart.
+func (m *Manager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.certificate == nil {
+		return nil, errNoCertificate
+	}
+	return m.certificate, nil
+}
+
+// Sync creates or rotates the certificate in the Secret if required, loads it, and patches the CA bundle into
+// the webhook configurations and the conversion webhooks.
+func (m *Manager) Sync(ctx context.Context) error {
+	secret, err := m.ensureSecret(ctx)
+	if err != nil {
+		return err
+	}
+	certificate, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("failed to load the certificate of the Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	m.mutex.Lock()
+	m.certificate = &certificate
+	m.mutex.Unlock()
+
+	bundle := secret.Data[CABundleKey]
+	return errors.Join(
+		m.patchMutatingWebhook(ctx, bundle),
+		m.patchValidatingWebhook(ctx, bundle),
+		m.patchConversionWebhooks(ctx, bundle),
+	)
+}
+
+// ensureSecret returns the Secret with a valid certificate, after creating or rotating it if required.
+func (m *Manager) ensureSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: m.config.Namespace, Name: m.config.SecretName}
+	err := m.client.Get(ctx, key, secret)
+	if k8serrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: m.config.Namespace, Name: m.config.SecretName},
+			Type:       corev1.SecretTypeTLS,
+		}
+		if err = m.issue(secret); err != nil {
+			return nil, err
+		}
+		if err = m.client.Create(ctx, secret); err != nil {
+			// another replica created the Secret first, its certificate is loaded with the next check
+			return nil, fmt.Errorf("failed to create the Secret %s: %w", key, err)
+		}
+		m.namedLogger().Infow("Created the webhook certificate", "secret", key.String())
+		return secret, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the Secret %s: %w", key, err)
+	}
+
+	if reason := m.rotationReason(secret); reason != "" {
+		if err = m.issue(secret); err != nil {
+			return nil, err
+		}
+		if err = m.client.Update(ctx, secret); err != nil {
+			return nil, fmt.Errorf("failed to update the Secret %s: %w", key, err)
+		}
+		m.namedLogger().Infow("Rotated the webhook certificate", "secret", key.String(), "reason", reason)
+	}
+	return secret, nil
+}
+
+// rotationReason returns why the certificate of the Secret must be rotated, or an empty string if it is valid.
+func (m *Manager) rotationReason(secret *corev1.Secret) string {
+	cert, err := parseCertificate(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return "invalid certificate"
+	}
+	if _, err = tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]); err != nil {
+		return "invalid private key"
+	}
+	if err = cert.VerifyHostname(dnsNames(m.config.ServiceName, m.config.Namespace)[2]); err != nil {
+		return "certificate of another Service"
+	}
+	if m.now().Add(m.config.RenewBefore).After(cert.NotAfter) {
+		return "certificate expires soon"
+	}
+	if !bytes.Contains(secret.Data[CABundleKey], secret.Data[corev1.TLSCertKey]) {
+		return "certificate missing in the CA bundle"
+	}
+	return ""
+}
+
+// issue sets a new certificate in the given Secret, and adds it to the CA bundle of the Secret.
+func (m *Manager) issue(secret *corev1.Secret) error {
+	now := m.now()
+	certPEM, keyPEM, err := generateCertificate(m.config.ServiceName, m.config.Namespace, m.config.Validity, now)
+	if err != nil {
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	// the certificate of the Secret is added as well, in case it was managed externally without a CA bundle
+	previous := append(append([]byte{}, secret.Data[CABundleKey]...), secret.Data[corev1.TLSCertKey]...)
+	secret.Data[CABundleKey] = caBundle(certPEM, previous, now)
+	secret.Data[corev1.TLSCertKey] = certPEM
+	secret.Data[corev1.TLSPrivateKeyKey] = keyPEM
+	return nil
+}
+
+func (m *Manager) patchMutatingWebhook(ctx context.Context, bundle []byte) error {
+	config := &admissionv1.MutatingWebhookConfiguration{}
+	if err := m.client.Get(ctx, client.ObjectKey{Name: m.config.MutatingWebhookName}, config); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	changed := false
+	for i := range config.Webhooks {
+		changed = setCABundle(&config.Webhooks[i].ClientConfig.CABundle, bundle) || changed
+	}
+	return m.update(ctx, config, changed)
+}
+
+func (m *Manager) patchValidatingWebhook(ctx context.Context, bundle []byte) error {
+	config := &admissionv1.ValidatingWebhookConfiguration{}
+	if err := m.client.Get(ctx, client.ObjectKey{Name: m.config.ValidatingWebhookName}, config); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	changed := false
+	for i := range config.Webhooks {
+		changed = setCABundle(&config.Webhooks[i].ClientConfig.CABundle, bundle) || changed
+	}
+	return m.update(ctx, config, changed)
+}
+
+// patchConversionWebhooks patches the CA bundle into the CRDs converting their versions with a webhook.
+func (m *Manager) patchConversionWebhooks(ctx context.Context, bundle []byte) error {
+	var errs []error
+	for _, name := range m.config.ConversionCRDNames {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := m.client.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+			errs = append(errs, client.IgnoreNotFound(err))
+			continue
+		}
+		conversion := crd.Spec.Conversion
+		if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter ||
+			conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+			continue
+		}
+		changed := setCABundle(&conversion.Webhook.ClientConfig.CABundle, bundle)
+		errs = append(errs, m.update(ctx, crd, changed))
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) update(ctx context.Context, obj client.Object, changed bool) error {
+	if !changed {
+		return nil
+	}
+	if err := m.client.Update(ctx, obj); err != nil {
+		return fmt.Errorf("failed to update the CA bundle of %T %s: %w", obj, obj.GetName(), err)
+	}
+	m.namedLogger().Infow("Updated the CA bundle", "kind", fmt.Sprintf("%T", obj), "name", obj.GetName())
+	return nil
+}
+
+// setCABundle sets the given CA bundle, and returns true if it was changed.
+func setCABundle(caBundle *[]byte, bundle []byte) bool {
+	if bytes.Equal(*caBundle, bundle) {
+		return false
+	}
+	*caBundle = bundle
+	return true
+}
+
+func (m *Manager) namedLogger() *zap.SugaredLogger {
+	return m.logger.WithContext().Named(managerName)
+}
@@ -0,0 +1,191 @@
+package webhookcert //nolint:testpackage
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+)
+
+func newTestManager(t *testing.T, objs ...client.Object) (*Manager, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, apiextensionsv1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	config, err := env.GetWebhookCertConfig()
+	require.NoError(t, err)
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.INFO))
+	require.NoError(t, err)
+	return NewManager(fakeClient, config, defaultLogger), fakeClient
+}
+
+func newWebhookConfigurations(config env.WebhookCertConfig) []client.Object {
+	return []client.Object{
+		&admissionv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: config.MutatingWebhookName},
+			Webhooks:   []admissionv1.MutatingWebhook{{Name: "msubscription.kb.io"}},
+		},
+		&admissionv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: config.ValidatingWebhookName},
+			Webhooks:   []admissionv1.ValidatingWebhook{{Name: "vsubscription.kb.io"}},
+		},
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: config.ConversionCRDNames[0]},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Conversion: &apiextensionsv1.CustomResourceConversion{
+					Strategy: apiextensionsv1.WebhookConverter,
+					Webhook: &apiextensionsv1.WebhookConversion{
+						ClientConfig:             &apiextensionsv1.WebhookClientConfig{},
+						ConversionReviewVersions: []string{"v1"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getSecret(t *testing.T, c client.Client, config env.WebhookCertConfig) *corev1.Secret {
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(),
+		client.ObjectKey{Namespace: config.Namespace, Name: config.SecretName}, secret))
+	return secret
+}
+
+// requireTrusted requires the given certificate to be trusted by the given CA bundle for the webhook Service.
+func requireTrusted(t *testing.T, bundle, certPEM []byte, config env.WebhookCertConfig, now time.Time) {
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(bundle))
+	cert, err := parseCertificate(certPEM)
+	require.NoError(t, err)
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:     config.ServiceName + "." + config.Namespace + ".svc",
+		Roots:       roots,
+		CurrentTime: now,
+	})
+	require.NoError(t, err)
+}
+
+func Test_Sync_CreatesCertificate(t *testing.T) {
+	// given
+	config, err := env.GetWebhookCertConfig()
+	require.NoError(t, err)
+	m, _ := newTestManager(t, newWebhookConfigurations(config)...)
+	_, err = m.GetCertificate(nil)
+	require.ErrorIs(t, err, errNoCertificate)
+
+	// when
+	require.NoError(t, m.Sync(context.Background()))
+
+	// then the certificate is served and trusted by all the CA bundles
+	certificate, err := m.GetCertificate(nil)
+	require.NoError(t, err)
+	require.NotNil(t, certificate)
+	secret := getSecret(t, m.client, m.config)
+	require.Equal(t, corev1.SecretTypeTLS, secret.Type)
+	bundle := secret.Data[CABundleKey]
+	requireTrusted(t, bundle, secret.Data[corev1.TLSCertKey], m.config, time.Now())
+
+	mutating := &admissionv1.MutatingWebhookConfiguration{}
+	require.NoError(t, m.client.Get(context.Background(), client.ObjectKey{Name: m.config.MutatingWebhookName}, mutating))
+	require.Equal(t, bundle, mutating.Webhooks[0].ClientConfig.CABundle)
+	validating := &admissionv1.ValidatingWebhookConfiguration{}
+	require.NoError(t, m.client.Get(context.Background(),
+		client.ObjectKey{Name: m.config.ValidatingWebhookName}, validating))
+	require.Equal(t, bundle, validating.Webhooks[0].ClientConfig.CABundle)
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	require.NoError(t, m.client.Get(context.Background(), client.ObjectKey{Name: m.config.ConversionCRDNames[0]}, crd))
+	require.Equal(t, bundle, crd.Spec.Conversion.Webhook.ClientConfig.CABundle)
+}
+
+func Test_Sync_Rotation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		givenLater     time.Duration
+		givenService   string
+		wantRotation   bool
+		wantOldTrusted bool
+	}{
+		{
+			name:       "should keep a valid certificate",
+			givenLater: time.Hour,
+		},
+		{
+			name:           "should rotate a certificate expiring soon and keep trusting it",
+			givenLater:     8760*time.Hour - 719*time.Hour,
+			wantRotation:   true,
+			wantOldTrusted: true,
+		},
+		{
+			name:         "should rotate the certificate of another Service",
+			givenLater:   time.Hour,
+			givenService: "other-webhook-service",
+			wantRotation: true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given a Secret created by the manager
+			m, c := newTestManager(t)
+			start := time.Now()
+			m.now = func() time.Time { return start }
+			if tc.givenService != "" {
+				m.config.ServiceName = tc.givenService
+			}
+			require.NoError(t, m.Sync(context.Background()))
+			old := getSecret(t, c, m.config)
+
+			// when
+			later := start.Add(tc.givenLater)
+			m.now = func() time.Time { return later }
+			m.config.ServiceName = "eventing-controller-webhook-service"
+			require.NoError(t, m.Sync(context.Background()))
+
+			// then
+			secret := getSecret(t, c, m.config)
+			if !tc.wantRotation {
+				require.Equal(t, old.Data, secret.Data)
+				return
+			}
+			require.NotEqual(t, old.Data[corev1.TLSCertKey], secret.Data[corev1.TLSCertKey])
+			requireTrusted(t, secret.Data[CABundleKey], secret.Data[corev1.TLSCertKey], m.config, later)
+			if tc.wantOldTrusted {
+				requireTrusted(t, secret.Data[CABundleKey], old.Data[corev1.TLSCertKey], m.config, later)
+			}
+		})
+	}
+}
+
+func Test_caBundle(t *testing.T) {
+	// given
+	now := time.Now()
+	expired, _, err := generateCertificate("service", "namespace", time.Hour, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	valid, _, err := generateCertificate("service", "namespace", time.Hour, now)
+	require.NoError(t, err)
+	current, _, err := generateCertificate("service", "namespace", time.Hour, now)
+	require.NoError(t, err)
+	previous := append(append(append([]byte{}, expired...), valid...), valid...)
+
+	// when
+	bundle := caBundle(current, previous, now)
+
+	// then the expired and duplicated certificates are dropped
+	require.Equal(t, append(append([]byte{}, current...), valid...), bundle)
+}
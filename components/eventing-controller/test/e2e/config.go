@@ -0,0 +1,42 @@
+package e2e
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// Config represents the environment config of the end-to-end tests, which run against the cluster of the current
+// kubeconfig context with Eventing installed.
+type Config struct {
+	// PublisherURL is the base URL of the Event Publisher Proxy reachable from the tests, such as a port-forward.
+	PublisherURL string `envconfig:"E2E_PUBLISHER_URL" default:"http://localhost:38081"`
+	// SinkImage is the image of the sink deployed by DeploySink, built from the test/e2e/sink package.
+	SinkImage string `envconfig:"E2E_SINK_IMAGE" required:"true"`
+
+	// NamespacePrefix is the prefix of the namespace generated for each Framework.
+	NamespacePrefix string `envconfig:"E2E_NAMESPACE_PREFIX" default:"eventing-e2e-"`
+	// KeepNamespace keeps the generated namespaces after the tests to investigate failures.
+	KeepNamespace bool `envconfig:"E2E_KEEP_NAMESPACE" default:"false"`
+
+	// EventSource is the source of the published events and the Subscriptions. EventMesh requires the namespace
+	// of its Secret.
+	EventSource string `envconfig:"E2E_EVENT_SOURCE" default:"e2e"`
+
+	// The EventingBackend the active backend is read from.
+	BackendCRNamespace string `envconfig:"BACKEND_CR_NAMESPACE" default:"kyma-system"`
+	BackendCRName      string `envconfig:"BACKEND_CR_NAME" default:"eventing-backend"`
+
+	// Timeout is the timeout of waiting for a Subscription, a sink or a delivery.
+	Timeout time.Duration `envconfig:"E2E_TIMEOUT" default:"2m"`
+	// PollInterval is the interval between the checks while waiting.
+	PollInterval time.Duration `envconfig:"E2E_POLL_INTERVAL" default:"2s"`
+}
+
+func GetConfig() (Config, error) {
+	cfg := Config{}
+	if err := envconfig.Process("", &cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,71 @@
+//go:build e2e
+
+package e2e_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
+	"github.com/kyma-project/kyma/components/eventing-controller/test/e2e"
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+const (
+	orderCreatedType = "order.created.v1"
+	orderUpdatedType = "order.updated.v1"
+)
+
+func Test_Delivery(t *testing.T) {
+	// given
+	f := e2e.New(t)
+	sink := f.DeploySink("sink")
+	f.CreateSubscription("orders", sink, []string{orderCreatedType, orderUpdatedType})
+
+	for _, contentMode := range []string{types.ContentModeBinary, types.ContentModeStructured} {
+		contentMode := contentMode
+		t.Run(contentMode, func(t *testing.T) {
+			// when
+			id := f.Publish(f.NewEvent(orderCreatedType), contentMode)
+
+			// then
+			event := sink.RequireEvent(id)
+			require.Equal(t, orderCreatedType, event.Type())
+			require.Equal(t, f.Config.EventSource, event.Source())
+		})
+	}
+}
+
+func Test_Delivery_ExactTypeMatching(t *testing.T) {
+	// given
+	f := e2e.New(t)
+	sink := f.DeploySink("sink")
+	f.CreateSubscription("orders", sink, []string{orderCreatedType}, eventingtesting.WithTypeMatchingExact())
+
+	// when
+	unsubscribed := f.Publish(f.NewEvent(orderUpdatedType), types.ContentModeBinary)
+	subscribed := f.Publish(f.NewEvent(orderCreatedType), types.ContentModeBinary)
+
+	// then
+	sink.RequireEvent(subscribed)
+	sink.RequireNoEvent(unsubscribed)
+}
+
+func Test_Delivery_DeletedSubscription(t *testing.T) {
+	// given the sink is subscribed by a second Subscription, which tells when the events were dispatched
+	f := e2e.New(t)
+	sink := f.DeploySink("sink")
+	deleted := f.CreateSubscription("orders-created", sink, []string{orderCreatedType})
+	f.CreateSubscription("orders-updated", sink, []string{orderUpdatedType})
+	sink.RequireEvent(f.Publish(f.NewEvent(orderCreatedType), types.ContentModeBinary))
+
+	// when
+	f.DeleteSubscription(deleted)
+	unsubscribed := f.Publish(f.NewEvent(orderCreatedType), types.ContentModeBinary)
+	subscribed := f.Publish(f.NewEvent(orderUpdatedType), types.ContentModeBinary)
+
+	// then
+	sink.RequireEvent(subscribed)
+	sink.RequireNoEvent(unsubscribed)
+}
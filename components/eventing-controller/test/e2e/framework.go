@@ -0,0 +1,117 @@
+// Package e2e is the framework of the end-to-end tests of Eventing. It deploys sinks, creates Subscriptions,
+// publishes events through the Event Publisher Proxy and asserts their delivery on a cluster with any backend,
+// so that the suites do not set up the integration test ensembles of the single backends.
+//
+// The suites are built with the e2e tag:
+//
+//	E2E_SINK_IMAGE=<image> go test -tags e2e ./test/e2e/...
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eventingv1alpha1 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha1"
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+)
+
+// Framework runs the fixtures and assertions of a test in its own namespace, which is deleted after the test.
+type Framework struct {
+	Config    Config
+	Client    client.Client
+	Namespace string
+
+	t         *testing.T
+	ctx       context.Context
+	clientset kubernetes.Interface
+}
+
+// New returns a new Framework for the given test, connected to the cluster of the current kubeconfig context.
+func New(t *testing.T) *Framework {
+	t.Helper()
+	cfg, err := GetConfig()
+	require.NoError(t, err)
+
+	restCfg, err := ctrl.GetConfig()
+	require.NoError(t, err)
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, eventingv1alpha1.AddToScheme(scheme))
+	require.NoError(t, eventingv1alpha2.AddToScheme(scheme))
+	k8sClient, err := client.New(restCfg, client.Options{Scheme: scheme})
+	require.NoError(t, err)
+	clientset, err := kubernetes.NewForConfig(restCfg)
+	require.NoError(t, err)
+
+	f := &Framework{Config: cfg, Client: k8sClient, t: t, ctx: context.Background(), clientset: clientset}
+	f.createNamespace()
+	return f
+}
+
+func (f *Framework) createNamespace() {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: f.Config.NamespacePrefix}}
+	require.NoError(f.t, f.Client.Create(f.ctx, namespace))
+	f.Namespace = namespace.Name
+	f.t.Logf("Created the namespace %s", f.Namespace)
+
+	f.t.Cleanup(func() {
+		if f.Config.KeepNamespace {
+			f.t.Logf("Kept the namespace %s", f.Namespace)
+			return
+		}
+		// the Subscriptions are deleted first, so that the controller can remove their finalizers
+		if err := f.Client.DeleteAllOf(f.ctx, &eventingv1alpha2.Subscription{}, client.InNamespace(f.Namespace)); err != nil {
+			f.t.Logf("Failed to delete the Subscriptions of the namespace %s: %v", f.Namespace, err)
+		}
+		if err := f.Client.Delete(f.ctx, namespace); err != nil {
+			f.t.Logf("Failed to delete the namespace %s: %v", f.Namespace, err)
+		}
+	})
+}
+
+// Backend returns the active backend of the EventingBackend.
+func (f *Framework) Backend() eventingv1alpha1.BackendType {
+	f.t.Helper()
+	backend := &eventingv1alpha1.EventingBackend{}
+	key := client.ObjectKey{Namespace: f.Config.BackendCRNamespace, Name: f.Config.BackendCRName}
+	require.NoError(f.t, f.Client.Get(f.ctx, key, backend))
+	return backend.Status.Backend
+}
+
+// RequireBackend skips the test unless one of the given backends is active, for the features which are not
+// supported by all backends.
+func (f *Framework) RequireBackend(backends ...eventingv1alpha1.BackendType) {
+	f.t.Helper()
+	active := f.Backend()
+	for _, backend := range backends {
+		if backend == active {
+			return
+		}
+	}
+	f.t.Skipf("the test requires one of the backends %v, the active backend is %s", backends, active)
+}
+
+// Eventually waits until the given condition returns nil, and fails the test with its last error after the timeout.
+func (f *Framework) Eventually(description string, condition func() error) {
+	f.t.Helper()
+	var lastErr error
+	err := wait.PollUntilContextTimeout(f.ctx, f.Config.PollInterval, f.Config.Timeout, true,
+		func(context.Context) (bool, error) {
+			lastErr = condition()
+			return lastErr == nil, nil
+		})
+	if err != nil {
+		require.FailNow(f.t, fmt.Sprintf("%s: %v", description, lastErr))
+	}
+}
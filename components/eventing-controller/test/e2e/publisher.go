@@ -0,0 +1,46 @@
+package e2e
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+const (
+	// publishEndpoint is the CloudEvents endpoint of the Event Publisher Proxy.
+	publishEndpoint = "/publish"
+
+	publishTimeout = 10 * time.Second
+)
+
+// NewEvent returns a builder of an event of the given type from the configured EventSource, with a unique ID.
+func (f *Framework) NewEvent(eventType string) *eventingtesting.CloudEventBuilder {
+	return eventingtesting.NewCloudEventBuilder().
+		WithID(uuid.NewString()).
+		WithSource(f.Config.EventSource).
+		WithType(eventType).
+		WithData(fmt.Sprintf(`{"test":%q}`, f.t.Name()))
+}
+
+// Publish publishes the event of the given builder through the Event Publisher Proxy in the given content mode,
+// either types.ContentModeBinary or types.ContentModeStructured, and returns its ID.
+func (f *Framework) Publish(builder *eventingtesting.CloudEventBuilder, contentMode string) string {
+	f.t.Helper()
+	req, err := builder.BuildRequest(f.Config.PublisherURL+publishEndpoint, contentMode)
+	require.NoError(f.t, err)
+	resp, err := (&http.Client{Timeout: publishTimeout}).Do(req.WithContext(f.ctx))
+	require.NoError(f.t, err)
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	require.Less(f.t, resp.StatusCode, http.StatusMultipleChoices,
+		"failed to publish the event: %d %s", resp.StatusCode, body)
+	require.GreaterOrEqual(f.t, resp.StatusCode, http.StatusOK)
+	event := builder.Build()
+	return event.ID()
+}
@@ -0,0 +1,181 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	cev2binding "github.com/cloudevents/sdk-go/v2/binding"
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/utils"
+)
+
+const (
+	// SinkPort is the port the sink listens on.
+	SinkPort = 8080
+	// EventsEndpoint is the path of the sink endpoint listing the received events as JSON.
+	EventsEndpoint = "/events"
+	// HealthEndpoint is the path of the readiness probe of the sink.
+	HealthEndpoint = "/healthz"
+
+	// maxSinkEvents is the number of received events kept by the sink, the oldest ones are dropped.
+	maxSinkEvents = 1000
+
+	sinkServicePort = 80
+)
+
+// Sink is a sink deployed to the namespace of a Framework, recording the events delivered to it.
+type Sink struct {
+	f    *Framework
+	Name string
+}
+
+// DeploySink deploys a sink with the given name, and waits until it is available.
+func (f *Framework) DeploySink(name string) *Sink {
+	f.t.Helper()
+	labels := map[string]string{"app": name}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: f.Namespace, Name: name, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: utils.Int32Ptr(1),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					// the sink is reached through the API server proxy, which is not part of the mesh
+					Annotations: map[string]string{"sidecar.istio.io/inject": "false"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "sink",
+						Image: f.Config.SinkImage,
+						Ports: []corev1.ContainerPort{{ContainerPort: SinkPort}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+								Path: HealthEndpoint, Port: intstr.FromInt(SinkPort),
+							}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: f.Namespace, Name: name, Labels: labels},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{{
+				Name: "http", Port: sinkServicePort, TargetPort: intstr.FromInt(SinkPort),
+			}},
+		},
+	}
+	require.NoError(f.t, f.Client.Create(f.ctx, deployment))
+	require.NoError(f.t, f.Client.Create(f.ctx, service))
+
+	f.Eventually(fmt.Sprintf("sink %s is not available", name), func() error {
+		if err := f.Client.Get(f.ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+			return err
+		}
+		if deployment.Status.AvailableReplicas < 1 {
+			return fmt.Errorf("%d available replicas", deployment.Status.AvailableReplicas)
+		}
+		return nil
+	})
+	return &Sink{f: f, Name: name}
+}
+
+// URL returns the in-cluster URL of the sink, to be used as the sink of Subscriptions.
+func (s *Sink) URL() string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local", s.Name, s.f.Namespace)
+}
+
+// Events returns the events received by the sink, in the order of their arrival.
+func (s *Sink) Events() ([]cev2event.Event, error) {
+	body, err := s.f.clientset.CoreV1().Services(s.f.Namespace).
+		ProxyGet("http", s.Name, fmt.Sprint(sinkServicePort), EventsEndpoint, nil).DoRaw(s.f.ctx)
+	if err != nil {
+		return nil, err
+	}
+	var events []cev2event.Event
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// RequireEvent waits until the sink received the event with the given ID, and returns it.
+func (s *Sink) RequireEvent(id string) cev2event.Event {
+	s.f.t.Helper()
+	var received cev2event.Event
+	s.f.Eventually(fmt.Sprintf("sink %s did not receive the event %s", s.Name, id), func() error {
+		events, err := s.Events()
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if event.ID() == id {
+				received = event
+				return nil
+			}
+		}
+		return fmt.Errorf("%d other events received", len(events))
+	})
+	return received
+}
+
+// RequireNoEvent requires that the sink did not receive the event with the given ID. Call it after an event
+// published later was received, to not depend on a fixed delay.
+func (s *Sink) RequireNoEvent(id string) {
+	s.f.t.Helper()
+	events, err := s.Events()
+	require.NoError(s.f.t, err)
+	for _, event := range events {
+		require.NotEqual(s.f.t, id, event.ID(), "sink %s received the event %s", s.Name, id)
+	}
+}
+
+// SinkHandler returns the http.Handler of the sink deployed by DeploySink. It records the CloudEvents posted to
+// any path, and lists them on the EventsEndpoint.
+func SinkHandler() http.Handler {
+	var mutex sync.Mutex
+	events := make([]cev2event.Event, 0, maxSinkEvents)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc(EventsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(events)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		event, err := cev2binding.ToEvent(r.Context(), cev2http.NewMessageFromHttpRequest(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		if len(events) == maxSinkEvents {
+			events = append(events[:0], events[1:]...)
+		}
+		events = append(events, *event)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
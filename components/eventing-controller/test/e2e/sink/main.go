@@ -0,0 +1,26 @@
+// The sink command is the sink deployed by the end-to-end tests. It records the received CloudEvents and lists
+// them on the /events endpoint. Build its image with ko, for example:
+//
+//	KO_DOCKER_REPO=<registry> ko build ./test/e2e/sink
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/test/e2e"
+)
+
+const readHeaderTimeout = 10 * time.Second
+
+func main() {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", e2e.SinkPort),
+		Handler:           e2e.SinkHandler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	log.Printf("Listening on %s", server.Addr)
+	log.Fatal(server.ListenAndServe())
+}
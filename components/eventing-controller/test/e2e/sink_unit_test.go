@@ -0,0 +1,75 @@
+//go:build unit
+
+package e2e_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
+	"github.com/kyma-project/kyma/components/eventing-controller/test/e2e"
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func Test_SinkHandler(t *testing.T) {
+	// given
+	server := httptest.NewServer(e2e.SinkHandler())
+	defer server.Close()
+
+	// when events are posted in both content modes
+	var ids []string
+	for _, contentMode := range []string{types.ContentModeBinary, types.ContentModeStructured} {
+		builder := eventingtesting.NewCloudEventBuilder().WithID(contentMode)
+		req, err := builder.BuildRequest(server.URL+"/any/path", contentMode)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		ids = append(ids, contentMode)
+	}
+
+	// then they are listed in the order of their arrival
+	resp, err := http.Get(server.URL + e2e.EventsEndpoint)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	var events []cev2event.Event
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&events))
+	require.Len(t, events, len(ids))
+	for i, event := range events {
+		require.Equal(t, ids[i], event.ID())
+	}
+}
+
+func Test_SinkHandler_RejectsInvalidRequests(t *testing.T) {
+	server := httptest.NewServer(e2e.SinkHandler())
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{name: "should reject a GET request", method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed},
+		{name: "should reject a request without an event", method: http.MethodPost, body: "{}",
+			wantStatus: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, server.URL+"/", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package e2e
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	eventingtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+// WithBackend selects the backend of the Subscription if several backends run.
+func WithBackend(backend string) eventingtesting.SubscriptionOpt {
+	return func(sub *eventingv1alpha2.Subscription) {
+		if sub.Annotations == nil {
+			sub.Annotations = map[string]string{}
+		}
+		sub.Annotations[eventingv1alpha2.BackendAnnotation] = backend
+	}
+}
+
+// CreateSubscription creates a Subscription of the given event types of the configured EventSource to the given
+// sink with the standard type matching, and waits until it is ready. The options change the Subscription before
+// it is created.
+func (f *Framework) CreateSubscription(name string, sink *Sink, eventTypes []string,
+	opts ...eventingtesting.SubscriptionOpt) *eventingv1alpha2.Subscription {
+	f.t.Helper()
+	opts = append([]eventingtesting.SubscriptionOpt{
+		eventingtesting.WithSource(f.Config.EventSource),
+		eventingtesting.WithTypes(eventTypes),
+		eventingtesting.WithSink(sink.URL()),
+		eventingtesting.WithTypeMatchingStandard(),
+	}, opts...)
+	subscription := eventingtesting.NewSubscription(name, f.Namespace, opts...)
+	require.NoError(f.t, f.Client.Create(f.ctx, subscription))
+	f.RequireSubscriptionReady(subscription)
+	return subscription
+}
+
+// RequireSubscriptionReady waits until the given Subscription is ready, and updates it.
+func (f *Framework) RequireSubscriptionReady(subscription *eventingv1alpha2.Subscription) {
+	f.t.Helper()
+	f.Eventually(fmt.Sprintf("Subscription %s is not ready", subscription.Name), func() error {
+		if err := f.Client.Get(f.ctx, client.ObjectKeyFromObject(subscription), subscription); err != nil {
+			return err
+		}
+		if !subscription.Status.Ready {
+			return fmt.Errorf("conditions %+v", subscription.Status.Conditions)
+		}
+		return nil
+	})
+}
+
+// DeleteSubscription deletes the given Subscription, and waits until it is gone.
+func (f *Framework) DeleteSubscription(subscription *eventingv1alpha2.Subscription) {
+	f.t.Helper()
+	require.NoError(f.t, f.Client.Delete(f.ctx, subscription))
+	f.Eventually(fmt.Sprintf("Subscription %s is not deleted", subscription.Name), func() error {
+		err := f.Client.Get(f.ctx, client.ObjectKeyFromObject(subscription), &eventingv1alpha2.Subscription{})
+		if err == nil {
+			return fmt.Errorf("the Subscription %s still exists", subscription.Name)
+		}
+		return client.IgnoreNotFound(err)
+	})
+}
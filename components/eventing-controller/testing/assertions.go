@@ -0,0 +1,105 @@
+package testing
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/stretchr/testify/require"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+)
+
+// The checks of the Subscription status are shared by the gomega matchers and the testify assertions,
+// they return an error describing the mismatch.
+
+// checkCondition returns an error if the subscription has no condition of the same type, status, reason and message.
+func checkCondition(s *eventingv1alpha2.Subscription, condition eventingv1alpha2.Condition) error {
+	for _, c := range s.Status.Conditions {
+		if c.Type == condition.Type && c.Status == condition.Status && c.Reason == condition.Reason &&
+			c.Message == condition.Message {
+			return nil
+		}
+	}
+	return fmt.Errorf("subscription %s/%s has no condition %s=%s with reason %q and message %q, conditions: %s",
+		s.Namespace, s.Name, condition.Type, condition.Status, condition.Reason, condition.Message,
+		formatConditions(s.Status.Conditions))
+}
+
+// checkReady returns an error if the subscription is not ready.
+func checkReady(s *eventingv1alpha2.Subscription) error {
+	if s.Status.Ready {
+		return nil
+	}
+	return fmt.Errorf("subscription %s/%s is not ready, conditions: %s", s.Namespace, s.Name,
+		formatConditions(s.Status.Conditions))
+}
+
+// checkCleanTypes returns an error if the clean types in the subscription status differ from the given ones.
+func checkCleanTypes(s *eventingv1alpha2.Subscription, cleanTypes ...string) error {
+	actual := make([]string, 0, len(s.Status.Types))
+	for _, t := range s.Status.Types {
+		actual = append(actual, t.CleanType)
+	}
+	if len(actual) == len(cleanTypes) && (len(actual) == 0 || reflect.DeepEqual(actual, cleanTypes)) {
+		return nil
+	}
+	return fmt.Errorf("subscription %s/%s has the clean types %q, expected %q", s.Namespace, s.Name, actual,
+		cleanTypes)
+}
+
+// checkBackendConsumer returns an error if the backend status of the subscription has no consumer
+// for the original event type.
+func checkBackendConsumer(s *eventingv1alpha2.Subscription, originalType string) error {
+	for _, t := range s.Status.Backend.Types {
+		if t.OriginalType == originalType && t.ConsumerName != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("subscription %s/%s has no backend consumer for the type %q, backend types: %+v",
+		s.Namespace, s.Name, originalType, s.Status.Backend.Types)
+}
+
+func formatConditions(conditions []eventingv1alpha2.Condition) string {
+	formatted := make([]string, 0, len(conditions))
+	for _, c := range conditions {
+		formatted = append(formatted, fmt.Sprintf("%s=%s (%s: %s)", c.Type, c.Status, c.Reason, c.Message))
+	}
+	return fmt.Sprintf("%q", formatted)
+}
+
+//
+// testify assertions
+//
+
+// RequireCondition fails the test if the subscription has no condition of the same type, status, reason and message.
+func RequireCondition(t require.TestingT, s *eventingv1alpha2.Subscription, condition eventingv1alpha2.Condition) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	require.NoError(t, checkCondition(s, condition))
+}
+
+// RequireReady fails the test if the subscription is not ready.
+func RequireReady(t require.TestingT, s *eventingv1alpha2.Subscription) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	require.NoError(t, checkReady(s))
+}
+
+// RequireCleanTypes fails the test if the clean types in the subscription status differ from the given ones.
+func RequireCleanTypes(t require.TestingT, s *eventingv1alpha2.Subscription, cleanTypes ...string) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	require.NoError(t, checkCleanTypes(s, cleanTypes...))
+}
+
+// RequireBackendConsumer fails the test if the backend status of the subscription has no consumer
+// for the original event type.
+func RequireBackendConsumer(t require.TestingT, s *eventingv1alpha2.Subscription, originalType string) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	require.NoError(t, checkBackendConsumer(s, originalType))
+}
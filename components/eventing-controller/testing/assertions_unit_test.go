@@ -0,0 +1,58 @@
+package testing_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	testingutils "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func Test_SubscriptionStatusMatchers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	activeCondition := testingutils.DefaultReadyCondition()
+	sub := testingutils.NewSubscription("sub", "test",
+		testingutils.WithEventType(testingutils.OrderCreatedUncleanEvent),
+		testingutils.WithStatus(true),
+		testingutils.WithConditions([]eventingv1alpha2.Condition{activeCondition}),
+		testingutils.WithStatusTypes([]eventingv1alpha2.EventType{{
+			OriginalType: testingutils.OrderCreatedUncleanEvent,
+			CleanType:    testingutils.OrderCreatedCleanEvent,
+		}}),
+		testingutils.WithStatusJSBackendTypes([]eventingv1alpha2.JetStreamTypes{{
+			OriginalType: testingutils.OrderCreatedUncleanEvent,
+			ConsumerName: "a59e97ceb4883938c193bc0abf6e8bca",
+		}}),
+	)
+	inactiveCondition := eventingv1alpha2.MakeCondition(eventingv1alpha2.ConditionSubscriptionActive,
+		eventingv1alpha2.ConditionReasonNATSSubscriptionNotActive, corev1.ConditionFalse, "")
+
+	// gomega matchers
+	g.Expect(sub).To(testingutils.BeReady())
+	g.Expect(sub).To(testingutils.HaveCondition(activeCondition))
+	g.Expect(sub).NotTo(testingutils.HaveCondition(inactiveCondition))
+	g.Expect(sub).To(testingutils.HaveCleanTypes(testingutils.OrderCreatedCleanEvent))
+	g.Expect(sub).NotTo(testingutils.HaveCleanTypes(testingutils.OrderCreatedUncleanEvent))
+	g.Expect(sub).To(testingutils.HaveBackendConsumer(testingutils.OrderCreatedUncleanEvent))
+	g.Expect(sub).NotTo(testingutils.HaveBackendConsumer(testingutils.OrderCreatedCleanEvent))
+
+	// testify assertions
+	testingutils.RequireReady(t, sub)
+	testingutils.RequireCondition(t, sub, activeCondition)
+	testingutils.RequireCleanTypes(t, sub, testingutils.OrderCreatedCleanEvent)
+	testingutils.RequireBackendConsumer(t, sub, testingutils.OrderCreatedUncleanEvent)
+
+	// failure messages
+	notReady := testingutils.NewSubscription("sub", "test",
+		testingutils.WithConditions([]eventingv1alpha2.Condition{inactiveCondition}))
+	beReady := testingutils.BeReady()
+	success, err := beReady.Match(notReady)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(success).To(BeFalse())
+	g.Expect(beReady.FailureMessage(notReady)).To(ContainSubstring(
+		`subscription test/sub is not ready, conditions: ["Subscription active=False (NATS Subscription not active: )"]`))
+	g.Expect(notReady).To(testingutils.HaveCleanTypes())
+}
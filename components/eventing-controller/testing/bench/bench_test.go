@@ -0,0 +1,129 @@
+package bench_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	kymalogger "github.com/kyma-project/kyma/common/logging/logger"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/testing/bench"
+)
+
+func newTestEnvironment(tb testing.TB) *bench.Environment {
+	tb.Helper()
+	defaultLogger, err := logger.New(string(kymalogger.JSON), string(kymalogger.WARN))
+	require.NoError(tb, err)
+	environment, err := bench.NewEnvironment("", defaultLogger)
+	require.NoError(tb, err)
+	tb.Cleanup(environment.Close)
+	return environment
+}
+
+func Test_RunSync(t *testing.T) {
+	// given
+	environment := newTestEnvironment(t)
+
+	// when
+	report, err := environment.RunSync(bench.SyncConfig{Subscriptions: 20, TypesPerSubscription: 2})
+
+	// then
+	require.NoError(t, err)
+	for _, phase := range []bench.SyncPhase{report.Create, report.Resync, report.Delete} {
+		require.Positive(t, phase.Throughput)
+		require.Positive(t, phase.Latency.Max)
+	}
+	// and the consumers were deleted
+	streamInfo, err := environment.Backend.GetJetStreamContext().StreamInfo(environment.NATSConfig.JSStreamName)
+	require.NoError(t, err)
+	require.Zero(t, streamInfo.State.Consumers)
+}
+
+func Test_RunDispatch(t *testing.T) {
+	// given
+	environment := newTestEnvironment(t)
+
+	// when
+	report, err := environment.RunDispatch(context.Background(), bench.DispatchConfig{
+		Subscriptions: 3,
+		SinkLatency:   bench.Duration(5 * time.Millisecond),
+		Rate:          100,
+		Duration:      bench.Duration(time.Second),
+		DrainTimeout:  bench.Duration(10 * time.Second),
+	})
+
+	// then all the published events were delivered
+	require.NoError(t, err)
+	require.Positive(t, report.Published)
+	require.Equal(t, report.Published, report.Delivered)
+	require.Positive(t, report.Throughput)
+	require.GreaterOrEqual(t, time.Duration(report.Latency.P50), 5*time.Millisecond)
+}
+
+func Test_Report_JSON(t *testing.T) {
+	// given
+	report := bench.NewReport()
+	report.Sync = &bench.SyncReport{
+		Create: bench.SyncPhase{Duration: bench.Duration(1500 * time.Millisecond), Throughput: 10},
+	}
+
+	// when
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded bench.Report
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	// then the durations are readable and decoded again
+	require.Contains(t, string(data), `"duration":"1.5s"`)
+	require.Equal(t, report.Sync, decoded.Sync)
+	require.Nil(t, decoded.Dispatch)
+}
+
+// BenchmarkSyncSubscription measures the sync of Subscriptions with the given numbers of existing Subscriptions.
+func BenchmarkSyncSubscription(b *testing.B) {
+	for _, subscriptions := range []int{100, 1000} {
+		subscriptions := subscriptions
+		b.Run(fmt.Sprintf("subscriptions=%d", subscriptions), func(b *testing.B) {
+			environment := newTestEnvironment(b)
+			b.ResetTimer()
+			var report bench.SyncReport
+			for i := 0; i < b.N; i++ {
+				var err error
+				report, err = environment.RunSync(bench.SyncConfig{Subscriptions: subscriptions})
+				require.NoError(b, err)
+			}
+			b.ReportMetric(report.Create.Throughput, "creates/s")
+			b.ReportMetric(report.Resync.Throughput, "resyncs/s")
+			b.ReportMetric(float64(time.Duration(report.Resync.Latency.P99).Microseconds()), "resync-p99-us")
+		})
+	}
+}
+
+// BenchmarkDispatch measures the dispatch of events to sinks with the given latencies.
+func BenchmarkDispatch(b *testing.B) {
+	for _, sinkLatency := range []time.Duration{0, 10 * time.Millisecond} {
+		sinkLatency := sinkLatency
+		b.Run(fmt.Sprintf("sink-latency=%s", sinkLatency), func(b *testing.B) {
+			environment := newTestEnvironment(b)
+			b.ResetTimer()
+			var report bench.DispatchReport
+			for i := 0; i < b.N; i++ {
+				var err error
+				report, err = environment.RunDispatch(context.Background(), bench.DispatchConfig{
+					Subscriptions: 10,
+					SinkLatency:   bench.Duration(sinkLatency),
+					Rate:          1000,
+					Duration:      bench.Duration(time.Second),
+				})
+				require.NoError(b, err)
+			}
+			b.ReportMetric(report.Throughput, "events/s")
+			b.ReportMetric(float64(time.Duration(report.Latency.P99).Microseconds()), "p99-us")
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	cev2binding "github.com/cloudevents/sdk-go/v2/binding"
+	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	evtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+	"github.com/kyma-project/kyma/components/eventing-controller/testing/loadgen"
+)
+
+const (
+	defaultMaxInFlight  = 10
+	defaultDrainTimeout = time.Minute
+
+	// drainPollInterval is the interval of checking whether all the published events were delivered.
+	drainPollInterval = 10 * time.Millisecond
+)
+
+var ErrNotDelivered = errors.New("not all the published events were delivered before the drain timeout")
+
+// DispatchConfig configures the dispatch benchmark.
+type DispatchConfig struct {
+	// Subscriptions is the number of Subscriptions, each of an event type of its own. The events are published
+	// to the types in turns.
+	Subscriptions int `json:"subscriptions"`
+	// SinkLatency is how long the sink takes to respond to each event.
+	SinkLatency Duration `json:"sinkLatency"`
+	// Rate is the number of events published per second.
+	Rate int `json:"rate"`
+	// Duration is how long the events are published.
+	Duration Duration `json:"duration"`
+	// EventSize is the approximate size of the JSON data of each event in bytes, it defaults to 64.
+	EventSize int `json:"eventSize"`
+	// MaxInFlight is the maxInFlightMessages of each Subscription, it defaults to 10.
+	MaxInFlight int `json:"maxInFlight"`
+	// DrainTimeout is how long to wait for the delivery of the published events, it defaults to 1m.
+	DrainTimeout Duration `json:"drainTimeout"`
+}
+
+func (c *DispatchConfig) validate() error {
+	if c.Subscriptions <= 0 {
+		return ErrInvalidSubscriptions
+	}
+	if c.MaxInFlight <= 0 {
+		c.MaxInFlight = defaultMaxInFlight
+	}
+	if c.DrainTimeout <= 0 {
+		c.DrainTimeout = Duration(defaultDrainTimeout)
+	}
+	return nil
+}
+
+// DispatchReport is the result of the dispatch benchmark.
+type DispatchReport struct {
+	Config DispatchConfig `json:"config"`
+	// Published is the number of events published successfully to JetStream.
+	Published int `json:"published"`
+	// PublishFailed is the number of events whose publishing failed.
+	PublishFailed int `json:"publishFailed"`
+	// PublishSkipped is the number of events not published in time, because all the publishers were busy.
+	PublishSkipped int `json:"publishSkipped"`
+	// Delivered is the number of distinct events delivered to the sink.
+	Delivered int `json:"delivered"`
+	// Duration is the time from the first publishing until the last delivery.
+	Duration Duration `json:"duration"`
+	// Throughput is the number of events delivered per second.
+	Throughput float64 `json:"throughput"`
+	// Latency is the time from the creation of the events until the sink responded to them.
+	Latency Latencies `json:"latency"`
+}
+
+func (r DispatchReport) String() string {
+	return fmt.Sprintf("dispatch to %d subscriptions with a sink latency of %s:\n"+
+		"published: %d, failed: %d, skipped: %d, delivered: %d, duration: %s, throughput: %.1f events/s\n"+
+		"latency %s", r.Config.Subscriptions, r.Config.SinkLatency, r.Published, r.PublishFailed, r.PublishSkipped,
+		r.Delivered, r.Duration, r.Throughput, r.Latency)
+}
+
+// RunDispatch creates the configured Subscriptions to a sink, publishes events to JetStream at the configured rate
+// until all of them are delivered, deletes the Subscriptions, and reports the delivery throughput and latency.
+func (e *Environment) RunDispatch(ctx context.Context, config DispatchConfig) (DispatchReport, error) {
+	if err := config.validate(); err != nil {
+		return DispatchReport{}, err
+	}
+	sink := newLatencySink(time.Duration(config.SinkLatency))
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	var subscriptions []*eventingv1alpha2.Subscription
+	defer func() {
+		for _, subscription := range subscriptions {
+			_ = e.Backend.DeleteSubscription(subscription)
+		}
+	}()
+	eventTypes := make([]string, 0, config.Subscriptions)
+	for i := 0; i < config.Subscriptions; i++ {
+		eventType := fmt.Sprintf("dispatch.order%d.created.v1", i)
+		subscription := e.newSubscription(fmt.Sprintf("dispatch-%d", i), server.URL, []string{eventType},
+			evtesting.WithMaxInFlight(config.MaxInFlight))
+		subscriptions = append(subscriptions, subscription)
+		if err := e.Backend.SyncSubscription(subscription); err != nil {
+			return DispatchReport{}, fmt.Errorf("failed to create the Subscription %s: %w", subscription.Name, err)
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	start := time.Now()
+	publisher := loadgen.NewJetStreamPublisher(e.Backend.GetJetStreamContext(), e.NATSConfig.JSSubjectPrefix)
+	published, err := loadgen.Run(ctx, publisher, loadgen.Config{
+		Rate:       config.Rate,
+		Duration:   time.Duration(config.Duration),
+		EventSize:  config.EventSize,
+		EventTypes: eventTypes,
+		Source:     source,
+	})
+	if err != nil {
+		return DispatchReport{}, err
+	}
+	drainErr := sink.waitFor(ctx, published.Sent, time.Duration(config.DrainTimeout))
+
+	delivered, lastDelivery, latencies := sink.results()
+	report := DispatchReport{
+		Config:         config,
+		Published:      published.Sent,
+		PublishFailed:  published.Failed,
+		PublishSkipped: published.Skipped,
+		Delivered:      delivered,
+		Latency:        newLatencies(latencies),
+	}
+	if delivered > 0 {
+		report.Duration = Duration(lastDelivery.Sub(start))
+		report.Throughput = float64(delivered) / lastDelivery.Sub(start).Seconds()
+	}
+	return report, drainErr
+}
+
+// latencySink is a sink responding to each event after a fixed latency, which records the distinct delivered events
+// and their latencies.
+type latencySink struct {
+	latency time.Duration
+
+	mutex        sync.Mutex
+	delivered    map[string]struct{}
+	latencies    []time.Duration
+	lastDelivery time.Time
+}
+
+func newLatencySink(latency time.Duration) *latencySink {
+	return &latencySink{latency: latency, delivered: map[string]struct{}{}}
+}
+
+func (s *latencySink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	event, err := cev2binding.ToEvent(r.Context(), cev2http.NewMessageFromHttpRequest(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	time.Sleep(s.latency)
+	now := time.Now()
+
+	s.mutex.Lock()
+	// the redeliveries are not counted, their latency includes the NAK delay of the failed delivery
+	if _, ok := s.delivered[event.ID()]; !ok {
+		s.delivered[event.ID()] = struct{}{}
+		s.latencies = append(s.latencies, now.Sub(event.Time()))
+		s.lastDelivery = now
+	}
+	s.mutex.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// waitFor waits until the given number of events was delivered, and returns ErrNotDelivered after the timeout.
+func (s *latencySink) waitFor(ctx context.Context, events int, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		s.mutex.Lock()
+		delivered := len(s.delivered)
+		s.mutex.Unlock()
+		if delivered >= events {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %d of %d delivered", ErrNotDelivered, delivered, events)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *latencySink) results() (int, time.Time, []time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.delivered), s.lastDelivery, append([]time.Duration{}, s.latencies...)
+}
@@ -0,0 +1,101 @@
+// Package bench measures the throughput of the JetStream backend, for the benchmarks and the bench command which
+// track its performance regressions. It syncs Subscriptions and dispatches events to sinks with a configurable
+// latency, and reports the results in a machine-readable form.
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+	"github.com/kyma-project/kyma/components/eventing-controller/logger"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/cleaner"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/jetstream"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/backend/metrics"
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/env"
+	evtesting "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+const (
+	// namespace is the namespace of the Subscriptions created by the benchmarks.
+	namespace = "bench"
+	// source is the source of the Subscriptions and the events of the benchmarks.
+	source = "bench"
+
+	maxReconnects = 10
+	reconnectWait = 3 * time.Second
+)
+
+// Environment is a JetStream backend connected to NATS, with a stream of its own.
+type Environment struct {
+	Backend    *jetstream.JetStream
+	NATSConfig env.NATSConfig
+
+	cleaner    cleaner.Cleaner
+	natsServer *server.Server
+}
+
+// NewEnvironment returns an initialized JetStream backend connected to the NATS server of the given URL. If the
+// URL is empty, an embedded NATS server is started, which is shut down by Close.
+func NewEnvironment(natsURL string, logger *logger.Logger) (*Environment, error) {
+	environment := &Environment{cleaner: cleaner.NewJetStreamCleaner(logger)}
+	if natsURL == "" {
+		port, err := evtesting.GetFreePort()
+		if err != nil {
+			return nil, err
+		}
+		environment.natsServer = evtesting.RunNatsServerOnPort(evtesting.WithPort(port),
+			evtesting.WithJetStreamEnabled())
+		natsURL = environment.natsServer.ClientURL()
+	}
+
+	// the stream is unique, so that the benchmarks do not interfere with the streams of the NATS server in use
+	streamName := fmt.Sprintf("bench%d", time.Now().UnixNano())
+	environment.NATSConfig = env.NATSConfig{
+		URL:                     natsURL,
+		MaxReconnects:           maxReconnects,
+		ReconnectWait:           reconnectWait,
+		JSStreamName:            streamName,
+		JSSubjectPrefix:         streamName,
+		JSStreamStorageType:     jetstream.StorageTypeMemory,
+		JSStreamRetentionPolicy: jetstream.RetentionPolicyInterest,
+		JSStreamDiscardPolicy:   jetstream.DiscardPolicyNew,
+	}
+	environment.Backend = jetstream.NewJetStream(environment.NATSConfig, metrics.NewCollector(), environment.cleaner,
+		env.DefaultSubscriptionConfig{MaxInFlightMessages: 1}, logger)
+	if err := environment.Backend.Initialize(nil); err != nil {
+		environment.Close()
+		return nil, fmt.Errorf("failed to initialize the JetStream backend: %w", err)
+	}
+	return environment, nil
+}
+
+// Close deletes the stream, and shuts down the embedded NATS server if it was started.
+func (e *Environment) Close() {
+	if e.Backend.Conn != nil {
+		if jsCtx := e.Backend.GetJetStreamContext(); jsCtx != nil {
+			_ = jsCtx.DeleteStream(e.NATSConfig.JSStreamName)
+		}
+		e.Backend.Conn.Close()
+	}
+	if e.natsServer != nil {
+		evtesting.ShutDownNATSServer(e.natsServer)
+	}
+}
+
+// newSubscription returns a Subscription of the given event types with the exact type matching, so that the
+// events are published to the subjects of their types, with its clean types in its status.
+func (e *Environment) newSubscription(name, sink string, eventTypes []string,
+	opts ...evtesting.SubscriptionOpt) *eventingv1alpha2.Subscription {
+	opts = append([]evtesting.SubscriptionOpt{
+		evtesting.WithSource(source),
+		evtesting.WithTypes(eventTypes),
+		evtesting.WithExactTypeMatching(),
+		evtesting.WithSinkURL(sink),
+	}, opts...)
+	subscription := evtesting.NewSubscription(name, namespace, opts...)
+	jetstream.AddJSCleanEventTypesToStatus(subscription, e.cleaner)
+	return subscription
+}
@@ -0,0 +1,108 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/testing/loadgen"
+)
+
+// Report is the machine-readable report of a bench run, so that the results of the runs can be compared to track
+// the performance regressions.
+type Report struct {
+	Timestamp time.Time       `json:"timestamp"`
+	GoVersion string          `json:"goVersion"`
+	GOOS      string          `json:"goos"`
+	GOARCH    string          `json:"goarch"`
+	NumCPU    int             `json:"numCPU"`
+	Sync      *SyncReport     `json:"sync,omitempty"`
+	Dispatch  *DispatchReport `json:"dispatch,omitempty"`
+}
+
+// NewReport returns a Report of the current platform without results.
+func NewReport() Report {
+	return Report{
+		Timestamp: time.Now().UTC(),
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+	}
+}
+
+// WriteFile writes the Report as indented JSON to the file of the given path, or to stdout if the path is empty.
+func (r Report) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func (r Report) String() string {
+	var results []string
+	if r.Sync != nil {
+		results = append(results, r.Sync.String())
+	}
+	if r.Dispatch != nil {
+		results = append(results, r.Dispatch.String())
+	}
+	return strings.Join(results, "\n")
+}
+
+// Duration is a time.Duration encoded as a string like "1.5ms", which stays readable in the reports.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Latencies are the percentiles of measured latencies.
+type Latencies struct {
+	P50 Duration `json:"p50"`
+	P95 Duration `json:"p95"`
+	P99 Duration `json:"p99"`
+	Max Duration `json:"max"`
+}
+
+// newLatencies returns the percentiles of the given latencies, which are sorted in place.
+func newLatencies(latencies []time.Duration) Latencies {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return Latencies{
+		P50: Duration(loadgen.Percentile(latencies, 50)),
+		P95: Duration(loadgen.Percentile(latencies, 95)),
+		P99: Duration(loadgen.Percentile(latencies, 99)),
+		Max: Duration(loadgen.Percentile(latencies, 100)),
+	}
+}
+
+func (l Latencies) String() string {
+	return fmt.Sprintf("p50: %s, p95: %s, p99: %s, max: %s", l.P50, l.P95, l.P99, l.Max)
+}
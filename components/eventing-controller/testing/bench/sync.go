@@ -0,0 +1,122 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	eventingv1alpha2 "github.com/kyma-project/kyma/components/eventing-controller/api/v1alpha2"
+)
+
+const (
+	defaultTypesPerSubscription = 1
+
+	// syncSink is the sink of the synced Subscriptions, no events are dispatched to it.
+	syncSink = "http://localhost:8080"
+)
+
+var ErrInvalidSubscriptions = errors.New("the number of Subscriptions must be positive")
+
+// SyncConfig configures the sync benchmark.
+type SyncConfig struct {
+	// Subscriptions is the number of synced Subscriptions.
+	Subscriptions int `json:"subscriptions"`
+	// TypesPerSubscription is the number of event types of each Subscription, it defaults to 1.
+	TypesPerSubscription int `json:"typesPerSubscription"`
+}
+
+func (c *SyncConfig) validate() error {
+	if c.Subscriptions <= 0 {
+		return ErrInvalidSubscriptions
+	}
+	if c.TypesPerSubscription <= 0 {
+		c.TypesPerSubscription = defaultTypesPerSubscription
+	}
+	return nil
+}
+
+// SyncReport is the result of the sync benchmark.
+type SyncReport struct {
+	Config SyncConfig `json:"config"`
+	// Create is the first sync of the new Subscriptions, creating their consumers.
+	Create SyncPhase `json:"create"`
+	// Resync is the sync of the unchanged Subscriptions, as done by every reconciliation.
+	Resync SyncPhase `json:"resync"`
+	// Delete is the deletion of the Subscriptions and their consumers.
+	Delete SyncPhase `json:"delete"`
+}
+
+// SyncPhase is the result of syncing or deleting all the Subscriptions once.
+type SyncPhase struct {
+	Duration Duration `json:"duration"`
+	// Throughput is the number of Subscriptions synced or deleted per second.
+	Throughput float64   `json:"throughput"`
+	Latency    Latencies `json:"latency"`
+}
+
+func newSyncPhase(latencies []time.Duration, duration time.Duration) SyncPhase {
+	return SyncPhase{
+		Duration:   Duration(duration),
+		Throughput: float64(len(latencies)) / duration.Seconds(),
+		Latency:    newLatencies(latencies),
+	}
+}
+
+func (r SyncReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sync of %d subscriptions with %d types each:", r.Config.Subscriptions,
+		r.Config.TypesPerSubscription)
+	for _, phase := range []struct {
+		name string
+		SyncPhase
+	}{{"create", r.Create}, {"resync", r.Resync}, {"delete", r.Delete}} {
+		fmt.Fprintf(&b, "\n%s: duration: %s, throughput: %.1f subscriptions/s, latency %s", phase.name,
+			phase.Duration, phase.Throughput, phase.Latency)
+	}
+	return b.String()
+}
+
+// RunSync syncs the configured number of new Subscriptions with the backend, syncs them again unchanged, deletes
+// them, and reports the throughput and latency of each phase.
+func (e *Environment) RunSync(config SyncConfig) (SyncReport, error) {
+	if err := config.validate(); err != nil {
+		return SyncReport{}, err
+	}
+	subscriptions := make([]*eventingv1alpha2.Subscription, 0, config.Subscriptions)
+	for i := 0; i < config.Subscriptions; i++ {
+		eventTypes := make([]string, 0, config.TypesPerSubscription)
+		for j := 0; j < config.TypesPerSubscription; j++ {
+			eventTypes = append(eventTypes, fmt.Sprintf("sync.order%d.created%d.v1", i, j))
+		}
+		subscriptions = append(subscriptions, e.newSubscription(fmt.Sprintf("sync-%d", i), syncSink, eventTypes))
+	}
+
+	report := SyncReport{Config: config}
+	var err error
+	if report.Create, err = measure(subscriptions, e.Backend.SyncSubscription); err != nil {
+		return SyncReport{}, fmt.Errorf("failed to create the Subscriptions: %w", err)
+	}
+	if report.Resync, err = measure(subscriptions, e.Backend.SyncSubscription); err != nil {
+		return SyncReport{}, fmt.Errorf("failed to resync the Subscriptions: %w", err)
+	}
+	if report.Delete, err = measure(subscriptions, e.Backend.DeleteSubscription); err != nil {
+		return SyncReport{}, fmt.Errorf("failed to delete the Subscriptions: %w", err)
+	}
+	return report, nil
+}
+
+// measure calls the given function with each Subscription, and returns its throughput and latency.
+func measure(subscriptions []*eventingv1alpha2.Subscription,
+	f func(*eventingv1alpha2.Subscription) error) (SyncPhase, error) {
+	latencies := make([]time.Duration, 0, len(subscriptions))
+	start := time.Now()
+	for _, subscription := range subscriptions {
+		callStart := time.Now()
+		if err := f(subscription); err != nil {
+			return SyncPhase{}, fmt.Errorf("%s/%s: %w", subscription.Namespace, subscription.Name, err)
+		}
+		latencies = append(latencies, time.Since(callStart))
+	}
+	return newSyncPhase(latencies, time.Since(start)), nil
+}
@@ -0,0 +1,145 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
+)
+
+// BatchedCloudEventsContentType is the content type of the HTTP requests with a batch of structured CloudEvents.
+const BatchedCloudEventsContentType = "application/cloudevents-batch+json"
+
+// CloudEventBuilder builds CloudEvents for the tests. It starts with a valid event of the CloudEventType
+// from the CloudEventSource with the CloudEventData, which can be changed or made invalid by the builder methods.
+type CloudEventBuilder struct {
+	event cev2event.Event
+}
+
+// NewCloudEventBuilder returns a builder of a valid CloudEvent.
+func NewCloudEventBuilder() *CloudEventBuilder {
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(EventID)
+	event.SetType(CloudEventType)
+	event.SetSource(CloudEventSource)
+	builder := &CloudEventBuilder{event: event}
+	return builder.WithData(CloudEventData)
+}
+
+func (b *CloudEventBuilder) WithID(id string) *CloudEventBuilder {
+	b.event.SetID(id)
+	return b
+}
+
+func (b *CloudEventBuilder) WithType(eventType string) *CloudEventBuilder {
+	b.event.SetType(eventType)
+	return b
+}
+
+func (b *CloudEventBuilder) WithSource(source string) *CloudEventBuilder {
+	b.event.SetSource(source)
+	return b
+}
+
+func (b *CloudEventBuilder) WithSubject(subject string) *CloudEventBuilder {
+	b.event.SetSubject(subject)
+	return b
+}
+
+func (b *CloudEventBuilder) WithTime(t time.Time) *CloudEventBuilder {
+	b.event.SetTime(t)
+	return b
+}
+
+// WithData sets the JSON data of the event.
+func (b *CloudEventBuilder) WithData(data string) *CloudEventBuilder {
+	return b.WithRawData(cev2.ApplicationJSON, []byte(data))
+}
+
+// WithRawData sets the data of the event as is, even if it does not match the content type.
+// The data is base64-encoded in the structured content mode unless the content type is JSON.
+func (b *CloudEventBuilder) WithRawData(contentType string, data []byte) *CloudEventBuilder {
+	b.event.SetDataContentType(contentType)
+	b.event.DataEncoded = data
+	b.event.DataBase64 = !strings.Contains(contentType, "json")
+	return b
+}
+
+// WithExtension sets the extension attribute of the event. Invalid extension names make the event invalid.
+func (b *CloudEventBuilder) WithExtension(name string, value interface{}) *CloudEventBuilder {
+	b.event.SetExtension(name, value)
+	return b
+}
+
+// WithoutID makes the event invalid by removing its required id.
+func (b *CloudEventBuilder) WithoutID() *CloudEventBuilder {
+	b.event.SetID("")
+	return b
+}
+
+// WithoutType makes the event invalid by removing its required type.
+func (b *CloudEventBuilder) WithoutType() *CloudEventBuilder {
+	b.event.SetType("")
+	return b
+}
+
+// WithoutSource makes the event invalid by removing its required source.
+func (b *CloudEventBuilder) WithoutSource() *CloudEventBuilder {
+	b.event.SetSource("")
+	return b
+}
+
+// Build returns a copy of the event, it is not validated to allow building invalid events.
+func (b *CloudEventBuilder) Build() cev2event.Event {
+	return b.event.Clone()
+}
+
+// BuildStructured returns the event encoded in the structured content mode.
+func (b *CloudEventBuilder) BuildStructured() ([]byte, error) {
+	return json.Marshal(b.Build())
+}
+
+// BuildRequest returns an HTTP POST request to the URL with the event encoded in the given content mode,
+// either types.ContentModeBinary or types.ContentModeStructured.
+func (b *CloudEventBuilder) BuildRequest(url, contentMode string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	ctx := binding.WithForceStructured(context.Background())
+	if contentMode == types.ContentModeBinary {
+		ctx = binding.WithForceBinary(context.Background())
+	}
+	event := b.Build()
+	if err := cev2http.WriteRequest(ctx, binding.ToMessage(&event), req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// NewBatchedRequest returns an HTTP POST request to the URL with the events encoded in the batched content mode.
+func NewBatchedRequest(url string, builders ...*CloudEventBuilder) (*http.Request, error) {
+	events := make([]cev2event.Event, 0, len(builders))
+	for _, builder := range builders {
+		events = append(events, builder.Build())
+	}
+	body, err := json.Marshal(events)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", BatchedCloudEventsContentType)
+	return req, nil
+}
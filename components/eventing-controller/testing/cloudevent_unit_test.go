@@ -0,0 +1,78 @@
+package testing_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
+	testingutils "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func Test_CloudEventBuilder(t *testing.T) {
+	// given
+	builder := testingutils.NewCloudEventBuilder().
+		WithData(testingutils.CloudEventData2).
+		WithExtension("correlationid", "correlation-1")
+
+	for _, contentMode := range []string{types.ContentModeBinary, types.ContentModeStructured} {
+		contentMode := contentMode
+		t.Run(contentMode, func(t *testing.T) {
+			// when
+			req, err := builder.BuildRequest("http://localhost/store", contentMode)
+			require.NoError(t, err)
+
+			// then the request decodes to the built event
+			event, err := binding.ToEvent(context.Background(), cev2http.NewMessageFromHttpRequest(req))
+			require.NoError(t, err)
+			require.NoError(t, event.Validate())
+			require.Equal(t, testingutils.EventID, event.ID())
+			require.Equal(t, testingutils.CloudEventType, event.Type())
+			require.Equal(t, "correlation-1", event.Extensions()["correlationid"])
+			require.JSONEq(t, testingutils.CloudEventData2, string(event.Data()))
+		})
+	}
+}
+
+func Test_CloudEventBuilder_InvalidEvents(t *testing.T) {
+	// when
+	event := testingutils.NewCloudEventBuilder().WithoutID().Build()
+	req, err := testingutils.NewCloudEventBuilder().WithoutSource().BuildRequest("http://localhost/store",
+		types.ContentModeBinary)
+
+	// then the invalid events are built anyway
+	require.Error(t, event.Validate())
+	require.NoError(t, err)
+	require.Empty(t, req.Header.Get("ce-source"))
+	require.Equal(t, testingutils.EventID, req.Header.Get("ce-id"))
+
+	// when
+	structured, err := testingutils.NewCloudEventBuilder().WithoutType().BuildStructured()
+
+	// then
+	require.NoError(t, err)
+	require.Contains(t, string(structured), `"type":""`)
+}
+
+func Test_NewBatchedRequest(t *testing.T) {
+	// when
+	req, err := testingutils.NewBatchedRequest("http://localhost/store",
+		testingutils.NewCloudEventBuilder().WithID("1"),
+		testingutils.NewCloudEventBuilder().WithID("2"))
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, testingutils.BatchedCloudEventsContentType, req.Header.Get("Content-Type"))
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	var events []map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &events))
+	require.Len(t, events, 2)
+	require.Equal(t, "1", events[0]["id"])
+	require.Equal(t, "2", events[1]["id"])
+}
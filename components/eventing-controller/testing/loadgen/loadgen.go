@@ -0,0 +1,210 @@
+// Package loadgen publishes events at a configurable rate and reports the achieved throughput and latency,
+// for the benchmarks and soak tests of the eventing backends and the event-publisher-proxy.
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+)
+
+const (
+	defaultSource      = "loadgen"
+	defaultConcurrency = 10
+	defaultEventSize   = 64
+)
+
+var (
+	ErrInvalidRate       = errors.New("the rate must be positive")
+	ErrInvalidDuration   = errors.New("the duration must be positive")
+	ErrMissingEventTypes = errors.New("at least one event type is required")
+)
+
+// Publisher publishes a single event to the system under test.
+type Publisher interface {
+	Publish(ctx context.Context, event cev2event.Event) error
+}
+
+// Config configures the generated load.
+type Config struct {
+	// Rate is the number of events published per second.
+	Rate int
+	// Duration is how long the events are published.
+	Duration time.Duration
+	// EventSize is the approximate size of the JSON data of each event in bytes, it defaults to 64.
+	EventSize int
+	// EventTypes are the types of the events, which are published in turns.
+	EventTypes []string
+	// Source is the source of the events, it defaults to "loadgen".
+	Source string
+	// Concurrency is the maximum number of events published at the same time, it defaults to 10.
+	Concurrency int
+}
+
+func (c *Config) validate() error {
+	switch {
+	case c.Rate <= 0:
+		return ErrInvalidRate
+	case c.Duration <= 0:
+		return ErrInvalidDuration
+	case len(c.EventTypes) == 0:
+		return ErrMissingEventTypes
+	}
+	if c.EventSize <= 0 {
+		c.EventSize = defaultEventSize
+	}
+	if c.Source == "" {
+		c.Source = defaultSource
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = defaultConcurrency
+	}
+	return nil
+}
+
+// Report is the result of a load run.
+type Report struct {
+	// Sent is the number of events published successfully.
+	Sent int
+	// Failed is the number of events whose publishing failed.
+	Failed int
+	// Skipped is the number of events not published in time, because all the publishers were busy.
+	Skipped int
+	// Duration is the time from the first publishing until the last one completed.
+	Duration time.Duration
+	// Throughput is the number of events published successfully per second.
+	Throughput float64
+	// The latencies of the successful publishing.
+	LatencyP50 time.Duration
+	LatencyP95 time.Duration
+	LatencyP99 time.Duration
+	LatencyMax time.Duration
+	// FirstError is the first publishing error, if any.
+	FirstError error
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sent: %d, failed: %d, skipped: %d, duration: %s, throughput: %.1f events/s\n",
+		r.Sent, r.Failed, r.Skipped, r.Duration.Round(time.Millisecond), r.Throughput)
+	fmt.Fprintf(&b, "latency p50: %s, p95: %s, p99: %s, max: %s", r.LatencyP50, r.LatencyP95, r.LatencyP99,
+		r.LatencyMax)
+	if r.FirstError != nil {
+		fmt.Fprintf(&b, "\nfirst error: %v", r.FirstError)
+	}
+	return b.String()
+}
+
+// Run publishes the events with the publisher as configured until the duration elapsed or the context is done,
+// waits for the pending publishing and reports the results.
+func Run(ctx context.Context, publisher Publisher, config Config) (Report, error) {
+	if err := config.validate(); err != nil {
+		return Report{}, err
+	}
+	data := newEventData(config.EventSize)
+
+	var (
+		mutex     sync.Mutex
+		report    Report
+		latencies []time.Duration
+		wg        sync.WaitGroup
+	)
+	events := make(chan cev2event.Event, config.Concurrency)
+	for i := 0; i < config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for event := range events {
+				start := time.Now()
+				err := publisher.Publish(ctx, event)
+				latency := time.Since(start)
+
+				mutex.Lock()
+				if err != nil {
+					report.Failed++
+					if report.FirstError == nil {
+						report.FirstError = err
+					}
+				} else {
+					report.Sent++
+					latencies = append(latencies, latency)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(time.Second / time.Duration(config.Rate))
+	defer ticker.Stop()
+	deadline := time.After(config.Duration)
+	for i := 0; ; i++ {
+		// the IDs are unique across the runs, so that the events are not dropped as duplicates
+		id := fmt.Sprintf("%d-%d", start.UnixNano(), i)
+		event := newEvent(id, config.Source, config.EventTypes[i%len(config.EventTypes)], data)
+		select {
+		case events <- event:
+		default:
+			mutex.Lock()
+			report.Skipped++
+			mutex.Unlock()
+		}
+		select {
+		case <-ticker.C:
+			continue
+		case <-deadline:
+		case <-ctx.Done():
+		}
+		break
+	}
+	close(events)
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	report.Throughput = float64(report.Sent) / report.Duration.Seconds()
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	report.LatencyP50 = Percentile(latencies, 50)
+	report.LatencyP95 = Percentile(latencies, 95)
+	report.LatencyP99 = Percentile(latencies, 99)
+	report.LatencyMax = Percentile(latencies, 100)
+	return report, nil
+}
+
+// Percentile returns the p-th percentile of the sorted latencies, or 0 if there are none.
+func Percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := (len(sorted)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+// newEventData returns JSON data of about the given size.
+func newEventData(size int) []byte {
+	const overhead = len(`{"data":""}`)
+	padding := size - overhead
+	if padding < 0 {
+		padding = 0
+	}
+	return []byte(`{"data":"` + strings.Repeat("x", padding) + `"}`)
+}
+
+func newEvent(id, source, eventType string, data []byte) cev2event.Event {
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(id)
+	event.SetSource(source)
+	event.SetType(eventType)
+	event.SetTime(time.Now())
+	_ = event.SetData(cev2.ApplicationJSON, data)
+	return event
+}
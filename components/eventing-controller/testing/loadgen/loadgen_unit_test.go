@@ -0,0 +1,53 @@
+package loadgen_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
+	"github.com/kyma-project/kyma/components/eventing-controller/testing/loadgen"
+)
+
+func Test_Run(t *testing.T) {
+	// given
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if received.Add(1)%4 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	publisher := loadgen.NewHTTPPublisher(server.URL, server.Client(), types.ContentModeBinary)
+
+	// when
+	report, err := loadgen.Run(context.Background(), publisher, loadgen.Config{
+		Rate:       100,
+		Duration:   500 * time.Millisecond,
+		EventSize:  1024,
+		EventTypes: []string{"order.created.v1", "order.updated.v1"},
+	})
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, int(received.Load()), report.Sent+report.Failed)
+	require.InDelta(t, 50, report.Sent+report.Failed+report.Skipped, 10)
+	require.Equal(t, int(received.Load())/4, report.Failed)
+	require.EqualError(t, report.FirstError, "publishing failed with the status code 500")
+	require.Greater(t, report.Throughput, 0.0)
+	require.Greater(t, report.LatencyP50, time.Duration(0))
+	require.LessOrEqual(t, report.LatencyP50, report.LatencyP99)
+	require.LessOrEqual(t, report.LatencyP99, report.LatencyMax)
+}
+
+func Test_Run_InvalidConfig(t *testing.T) {
+	_, err := loadgen.Run(context.Background(), nil, loadgen.Config{Rate: 10, Duration: time.Second})
+	require.ErrorIs(t, err, loadgen.ErrMissingEventTypes)
+}
@@ -0,0 +1,74 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cev2event "github.com/cloudevents/sdk-go/v2/event"
+	cev2http "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/nats-io/nats.go"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
+)
+
+// HTTPPublisher publishes the events to an HTTP endpoint like the one of the event-publisher-proxy.
+type HTTPPublisher struct {
+	url         string
+	client      *http.Client
+	contentMode string
+}
+
+// NewHTTPPublisher returns a publisher sending the events to the URL in the content mode, either
+// types.ContentModeBinary or types.ContentModeStructured.
+func NewHTTPPublisher(url string, client *http.Client, contentMode string) *HTTPPublisher {
+	return &HTTPPublisher{url: url, client: client, contentMode: contentMode}
+}
+
+func (p *HTTPPublisher) Publish(ctx context.Context, event cev2event.Event) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, nil)
+	if err != nil {
+		return err
+	}
+	encodingCtx := binding.WithForceStructured(ctx)
+	if p.contentMode == types.ContentModeBinary {
+		encodingCtx = binding.WithForceBinary(ctx)
+	}
+	if err := cev2http.WriteRequest(encodingCtx, binding.ToMessage(&event), req); err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("publishing failed with the status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// JetStreamPublisher publishes the events directly to the JetStream backend, bypassing the event-publisher-proxy.
+type JetStreamPublisher struct {
+	jsCtx         nats.JetStreamContext
+	subjectPrefix string
+}
+
+// NewJetStreamPublisher returns a publisher sending the structured events to the subjects made of the prefix
+// and the event type, which is expected to be clean already.
+func NewJetStreamPublisher(jsCtx nats.JetStreamContext, subjectPrefix string) *JetStreamPublisher {
+	return &JetStreamPublisher{jsCtx: jsCtx, subjectPrefix: subjectPrefix}
+}
+
+func (p *JetStreamPublisher) Publish(ctx context.Context, event cev2event.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = p.jsCtx.Publish(fmt.Sprintf("%s.%s", p.subjectPrefix, event.Type()), data, nats.Context(ctx))
+	return err
+}
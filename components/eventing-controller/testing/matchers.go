@@ -244,17 +244,30 @@ func HaveSubscriptionNotReady() gomegatypes.GomegaMatcher {
 	}, BeFalse())
 }
 
+// HaveCondition matches the subscriptions having a condition of the same type, status, reason and message.
 func HaveCondition(condition eventingv1alpha2.Condition) gomegatypes.GomegaMatcher {
-	return WithTransform(
-		func(s *eventingv1alpha2.Subscription) []eventingv1alpha2.Condition {
-			return s.Status.Conditions
-		},
-		ContainElement(MatchFields(IgnoreExtras|IgnoreMissing, Fields{
-			"Type":    Equal(condition.Type),
-			"Reason":  Equal(condition.Reason),
-			"Message": Equal(condition.Message),
-			"Status":  Equal(condition.Status),
-		})))
+	return WithTransform(func(s *eventingv1alpha2.Subscription) error {
+		return checkCondition(s, condition)
+	}, Succeed())
+}
+
+// BeReady matches the ready subscriptions, the failure message lists their conditions.
+func BeReady() gomegatypes.GomegaMatcher {
+	return WithTransform(checkReady, Succeed())
+}
+
+// HaveCleanTypes matches the subscriptions having the given clean types in their status, in the same order.
+func HaveCleanTypes(cleanTypes ...string) gomegatypes.GomegaMatcher {
+	return WithTransform(func(s *eventingv1alpha2.Subscription) error {
+		return checkCleanTypes(s, cleanTypes...)
+	}, Succeed())
+}
+
+// HaveBackendConsumer matches the subscriptions having a backend consumer for the original event type.
+func HaveBackendConsumer(originalType string) gomegatypes.GomegaMatcher {
+	return WithTransform(func(s *eventingv1alpha2.Subscription) error {
+		return checkBackendConsumer(s, originalType)
+	}, Succeed())
 }
 
 func HaveSubscriptionActiveCondition() gomegatypes.GomegaMatcher {
@@ -1,10 +1,21 @@
 package testing
 
 import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/nats-io/nats-server/v2/server"
 	natstestserver "github.com/nats-io/nats-server/v2/test"
 )
 
+const (
+	natsClusterName = "eventing-test"
+	natsClusterSize = 3
+	natsReadyWait   = 10 * time.Second
+)
+
 type NatsServerOpt func(opts *server.Options)
 
 func WithJetStreamEnabled() NatsServerOpt {
@@ -19,13 +30,21 @@ func WithPort(port int) NatsServerOpt {
 	}
 }
 
+// WithStoreDir sets the JetStream storage directory, so that the streams are not shared with other servers.
+func WithStoreDir(dir string) NatsServerOpt {
+	return func(opts *server.Options) {
+		opts.StoreDir = dir
+	}
+}
+
 // RunNatsServerOnPort will run a server with the given server options.
 func RunNatsServerOnPort(opts ...NatsServerOpt) *server.Server {
-	serverOpts := &natstestserver.DefaultTestOptions
+	// copy the default options, so that the overrides do not leak into the servers started later
+	serverOpts := natstestserver.DefaultTestOptions
 	for _, opt := range opts {
-		opt(serverOpts)
+		opt(&serverOpts)
 	}
-	return natstestserver.RunServer(serverOpts)
+	return natstestserver.RunServer(&serverOpts)
 }
 
 // StartDefaultJetStreamServer will run a server on the given port.
@@ -42,3 +61,140 @@ func ShutDownNATSServer(natsServer *server.Server) {
 	natsServer.Shutdown()
 	natsServer.WaitForShutdown()
 }
+
+// NatsCluster is a cluster of embedded NATS servers with JetStream enabled, whose nodes can be shut down and
+// restarted individually to test the replication and the failover.
+type NatsCluster struct {
+	servers  []*server.Server
+	options  []*server.Options
+	storeDir string
+}
+
+// StartJetStreamCluster runs a cluster of three NATS servers with JetStream enabled on free ports,
+// and waits until the cluster elected its JetStream meta leader.
+func StartJetStreamCluster() (*NatsCluster, error) {
+	storeDir, err := os.MkdirTemp("", "nats-cluster-")
+	if err != nil {
+		return nil, err
+	}
+	cluster := &NatsCluster{
+		servers:  make([]*server.Server, natsClusterSize),
+		options:  make([]*server.Options, natsClusterSize),
+		storeDir: storeDir,
+	}
+
+	routes := make([]string, natsClusterSize)
+	for i := range cluster.options {
+		clientPort, err := GetFreePort()
+		if err != nil {
+			cluster.Shutdown()
+			return nil, err
+		}
+		clusterPort, err := GetFreePort()
+		if err != nil {
+			cluster.Shutdown()
+			return nil, err
+		}
+		opts := natstestserver.DefaultTestOptions
+		opts.ServerName = fmt.Sprintf("nats-%d", i)
+		opts.Port = clientPort
+		opts.JetStream = true
+		opts.StoreDir = fmt.Sprintf("%s/%s", storeDir, opts.ServerName)
+		opts.Cluster = server.ClusterOpts{Name: natsClusterName, Host: opts.Host, Port: clusterPort}
+		cluster.options[i] = &opts
+		routes[i] = fmt.Sprintf("nats://%s:%d", opts.Host, clusterPort)
+	}
+	for i, opts := range cluster.options {
+		opts.Routes = server.RoutesFromStr(strings.Join(routes, ","))
+		if err := cluster.RestartNode(i); err != nil {
+			cluster.Shutdown()
+			return nil, err
+		}
+	}
+	if err := cluster.WaitForMetaLeader(natsReadyWait); err != nil {
+		cluster.Shutdown()
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// ClientURL returns the client URLs of all nodes, separated by commas as accepted by nats.Connect.
+func (c *NatsCluster) ClientURL() string {
+	urls := make([]string, len(c.options))
+	for i, opts := range c.options {
+		urls[i] = fmt.Sprintf("nats://%s:%d", opts.Host, opts.Port)
+	}
+	return strings.Join(urls, ",")
+}
+
+// Node returns the server of the i-th node, or nil if the node is shut down.
+func (c *NatsCluster) Node(i int) *server.Server {
+	return c.servers[i]
+}
+
+// MetaLeader returns the index of the node which is the JetStream meta leader, or -1 if there is none.
+func (c *NatsCluster) MetaLeader() int {
+	for i, natsServer := range c.servers {
+		if natsServer != nil && natsServer.JetStreamIsLeader() {
+			return i
+		}
+	}
+	return -1
+}
+
+// WaitForMetaLeader waits until the running nodes elected a JetStream meta leader and are current with it.
+func (c *NatsCluster) WaitForMetaLeader(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if c.MetaLeader() >= 0 && c.isCurrent() {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fmt.Errorf("no JetStream meta leader elected within %s", timeout)
+}
+
+func (c *NatsCluster) isCurrent() bool {
+	for _, natsServer := range c.servers {
+		if natsServer != nil && !natsServer.JetStreamIsCurrent() {
+			return false
+		}
+	}
+	return true
+}
+
+// ShutdownNode shuts down the i-th node and waits until the shutdown is complete. Its JetStream data is kept,
+// so that it is recovered by RestartNode.
+func (c *NatsCluster) ShutdownNode(i int) {
+	if c.servers[i] == nil {
+		return
+	}
+	ShutDownNATSServer(c.servers[i])
+	c.servers[i] = nil
+}
+
+// RestartNode starts the i-th node again with the same ports and JetStream data, if it is shut down.
+func (c *NatsCluster) RestartNode(i int) error {
+	if c.servers[i] != nil {
+		return nil
+	}
+	natsServer, err := server.NewServer(c.options[i])
+	if err != nil {
+		return err
+	}
+	go natsServer.Start()
+	if !natsServer.ReadyForConnections(natsReadyWait) {
+		natsServer.Shutdown()
+		return fmt.Errorf("NATS server %s not ready for connections within %s", c.options[i].ServerName, natsReadyWait)
+	}
+	c.servers[i] = natsServer
+	return nil
+}
+
+// Shutdown shuts down all nodes and deletes their JetStream data.
+func (c *NatsCluster) Shutdown() {
+	for i := range c.servers {
+		c.ShutdownNode(i)
+	}
+	_ = os.RemoveAll(c.storeDir)
+}
@@ -0,0 +1,50 @@
+package testing_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	testingutils "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func Test_JetStreamCluster_Failover(t *testing.T) {
+	// given
+	cluster, err := testingutils.StartJetStreamCluster()
+	require.NoError(t, err)
+	defer cluster.Shutdown()
+
+	conn, err := nats.Connect(cluster.ClientURL())
+	require.NoError(t, err)
+	defer conn.Close()
+	jsCtx, err := conn.JetStream()
+	require.NoError(t, err)
+	_, err = jsCtx.AddStream(&nats.StreamConfig{Name: "test", Subjects: []string{"test.>"}, Replicas: 3})
+	require.NoError(t, err)
+	_, err = jsCtx.Publish("test.before", []byte("before"))
+	require.NoError(t, err)
+
+	// when the meta leader is shut down
+	leader := cluster.MetaLeader()
+	cluster.ShutdownNode(leader)
+
+	// then the other nodes elect a new meta leader and keep the replicated stream available
+	require.NoError(t, cluster.WaitForMetaLeader(10*time.Second))
+	require.NotEqual(t, leader, cluster.MetaLeader())
+	require.Eventually(t, func() bool {
+		// the message ID deduplicates the retried publishing
+		_, err := jsCtx.Publish("test.after", []byte("after"), nats.MsgId("after"))
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	// when the node is restarted
+	require.NoError(t, cluster.RestartNode(leader))
+
+	// then it catches up with the cluster
+	require.NoError(t, cluster.WaitForMetaLeader(10*time.Second))
+	info, err := jsCtx.StreamInfo("test")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), info.State.Msgs)
+}
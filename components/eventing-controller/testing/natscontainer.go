@@ -0,0 +1,103 @@
+package testing
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+const (
+	// NatsImageTagEnv is the environment variable overriding the default tag of the nats-server image.
+	NatsImageTagEnv = "NATS_IMAGE_TAG"
+	// DefaultNatsImageTag is the tag of the nats-server image, matching the version of the embedded server.
+	DefaultNatsImageTag = "2.10.3"
+
+	natsImageRepository = "nats"
+	natsClientPort      = "4222/tcp"
+	natsContainerWait   = time.Minute
+	// natsContainerTTL is the number of seconds after which the container is removed even if the test did not
+	// shut it down, e.g. because it was interrupted.
+	natsContainerTTL = 600
+)
+
+// ErrDockerNotAvailable is returned by RunNatsContainer if there is no Docker daemon to run the container,
+// the tests can be skipped then.
+var ErrDockerNotAvailable = errors.New("docker is not available")
+
+// NatsImageTag returns the tag of the nats-server image set by the NATS_IMAGE_TAG environment variable,
+// or the DefaultNatsImageTag.
+func NatsImageTag() string {
+	if tag := os.Getenv(NatsImageTagEnv); tag != "" {
+		return tag
+	}
+	return DefaultNatsImageTag
+}
+
+// NatsContainer is a nats-server container with JetStream enabled, to verify the compatibility with the specific
+// NATS releases which cannot be embedded.
+type NatsContainer struct {
+	pool     *dockertest.Pool
+	resource *dockertest.Resource
+}
+
+// RunNatsContainer runs the nats-server image of the given tag with JetStream enabled, and waits until it accepts
+// connections. It returns an error if Docker is not available.
+func RunNatsContainer(tag string) (*NatsContainer, error) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDockerNotAvailable, err)
+	}
+	if err := pool.Client.Ping(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDockerNotAvailable, err)
+	}
+	pool.MaxWait = natsContainerWait
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: natsImageRepository,
+		Tag:        tag,
+		Cmd:        []string{"-js"},
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		return nil, err
+	}
+	_ = resource.Expire(natsContainerTTL)
+
+	container := &NatsContainer{pool: pool, resource: resource}
+	if err := pool.Retry(func() error {
+		conn, err := nats.Connect(container.ClientURL())
+		if err != nil {
+			return err
+		}
+		conn.Close()
+		return nil
+	}); err != nil {
+		_ = container.Shutdown()
+		return nil, fmt.Errorf("nats-server %s not ready for connections: %w", tag, err)
+	}
+	return container, nil
+}
+
+// ClientURL returns the URL of the client port published on the host.
+func (c *NatsContainer) ClientURL() string {
+	return fmt.Sprintf("nats://%s", c.resource.GetHostPort(natsClientPort))
+}
+
+// Port returns the client port published on the host.
+func (c *NatsContainer) Port() int {
+	port, _ := strconv.Atoi(c.resource.GetPort(natsClientPort))
+	return port
+}
+
+// Shutdown removes the container.
+func (c *NatsContainer) Shutdown() error {
+	return c.pool.Purge(c.resource)
+}
@@ -0,0 +1,190 @@
+package testing
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// UpdateGoldenFilesEnv is the environment variable which makes CompareWithGolden overwrite the golden recordings
+// with the current ones instead of comparing them, if it is "true".
+const UpdateGoldenFilesEnv = "UPDATE_GOLDEN_FILES"
+
+// RecordedRequest is a request received by the "/store" or "/return500" Endpoint of a recording Subscriber.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// recorder records the requests in memory and appends them to a recording file as JSON lines.
+type recorder struct {
+	mutex    sync.Mutex
+	path     string
+	requests []RecordedRequest
+}
+
+// handle records the requests to the sink Endpoints before passing them to the next handler.
+func (r *recorder) handle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == storeEndpoint || req.URL.Path == internalErrorEndpoint {
+			if err := r.record(req); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+func (r *recorder) record(req *http.Request) error {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	recorded := RecordedRequest{Method: req.Method, Path: req.URL.Path, Header: req.Header.Clone(), Body: string(body)}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.requests = append(r.requests, recorded)
+	line, err := json.Marshal(recorded)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// WithRecording will make the Subscriber record all the requests to its "/store" and "/return500" Endpoints,
+// including the ones failed by the injected faults, and append them to the recording file at the given path.
+func WithRecording(path string) SubscriberOption {
+	return func(subscriber *Subscriber) {
+		subscriber.recorder = &recorder{path: path}
+	}
+}
+
+// Recording returns the requests recorded by the Subscriber so far, in the order of their arrival.
+func (s *Subscriber) Recording() []RecordedRequest {
+	if s.recorder == nil {
+		return nil
+	}
+	s.recorder.mutex.Lock()
+	defer s.recorder.mutex.Unlock()
+	requests := make([]RecordedRequest, len(s.recorder.requests))
+	copy(requests, s.recorder.requests)
+	return requests
+}
+
+// CompareWithGolden returns an error if the requests recorded by the Subscriber differ from the ones
+// in the golden recording file, ignoring the given headers. If UPDATE_GOLDEN_FILES is "true", the golden recording
+// is overwritten with the current one instead.
+func (s *Subscriber) CompareWithGolden(goldenPath string, ignoredHeaders ...string) error {
+	requests := s.Recording()
+	if os.Getenv(UpdateGoldenFilesEnv) == "true" {
+		return WriteRecording(goldenPath, requests)
+	}
+	golden, err := ReadRecording(goldenPath)
+	if err != nil {
+		return err
+	}
+	if len(golden) != len(requests) {
+		return fmt.Errorf("recorded %d requests, the golden recording %s has %d", len(requests), goldenPath,
+			len(golden))
+	}
+	for i := range golden {
+		expected := withoutHeaders(golden[i], ignoredHeaders)
+		actual := withoutHeaders(requests[i], ignoredHeaders)
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("request %d differs from the golden recording %s:\nexpected: %+v\nactual:   %+v", i,
+				goldenPath, expected, actual)
+		}
+	}
+	return nil
+}
+
+// withoutHeaders returns a copy of the request without the given headers.
+func withoutHeaders(request RecordedRequest, headers []string) RecordedRequest {
+	request.Header = request.Header.Clone()
+	if request.Header == nil {
+		request.Header = http.Header{}
+	}
+	for _, header := range headers {
+		request.Header.Del(header)
+	}
+	return request
+}
+
+// ReadRecording reads the requests from the recording file.
+func ReadRecording(path string) ([]RecordedRequest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var requests []RecordedRequest
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var request RecordedRequest
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			return nil, fmt.Errorf("invalid recording %s: %w", path, err)
+		}
+		requests = append(requests, request)
+	}
+	return requests, scanner.Err()
+}
+
+// WriteRecording overwrites the recording file with the requests.
+func WriteRecording(path string, requests []RecordedRequest) error {
+	var b bytes.Buffer
+	for _, request := range requests {
+		line, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		b.Write(append(line, '\n'))
+	}
+	return os.WriteFile(path, b.Bytes(), 0o600)
+}
+
+// ReplayRecording sends the requests of the recording file to the same paths at the given base URL, e.g. the one
+// of another Subscriber, and returns an error if any of them fails.
+func ReplayRecording(path, baseURL string, client *http.Client) error {
+	requests, err := ReadRecording(path)
+	if err != nil {
+		return err
+	}
+	for i, request := range requests {
+		req, err := http.NewRequest(request.Method, strings.TrimSuffix(baseURL, "/")+request.Path,
+			strings.NewReader(request.Body))
+		if err != nil {
+			return err
+		}
+		req.Header = request.Header.Clone()
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("replaying request %d failed: %w", i, err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+	return nil
+}
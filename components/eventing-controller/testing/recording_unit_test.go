@@ -0,0 +1,67 @@
+package testing_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/kyma/components/eventing-controller/pkg/ems/api/events/types"
+	testingutils "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+const goldenRecording = "testdata/subscriber_recording.jsonl"
+
+//nolint:gochecknoglobals // test fixture
+var volatileHeaders = []string{"User-Agent", "Accept-Encoding"}
+
+func Test_Subscriber_WithRecording(t *testing.T) {
+	// given
+	recordingPath := filepath.Join(t.TempDir(), "recording.jsonl")
+	subscriber := testingutils.NewSubscriber(testingutils.WithRecording(recordingPath))
+	defer subscriber.Shutdown()
+	builder := testingutils.NewCloudEventBuilder().
+		WithTime(time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)).
+		WithExtension("correlationid", "correlation-1")
+
+	// when
+	for _, contentMode := range []string{types.ContentModeBinary, types.ContentModeStructured} {
+		req, err := builder.BuildRequest(subscriber.SinkURL, contentMode)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	// then the recording matches the golden one
+	require.Len(t, subscriber.Recording(), 2)
+	require.NoError(t, subscriber.CompareWithGolden(goldenRecording, volatileHeaders...))
+	recording, err := testingutils.ReadRecording(recordingPath)
+	require.NoError(t, err)
+	require.Equal(t, subscriber.Recording(), recording)
+
+	// when the recording is replayed to another Subscriber
+	replayed := testingutils.NewSubscriber(testingutils.WithRecording(filepath.Join(t.TempDir(), "replayed.jsonl")))
+	defer replayed.Shutdown()
+	require.NoError(t, testingutils.ReplayRecording(recordingPath, replayed.URL(), http.DefaultClient))
+
+	// then it receives the same requests
+	require.Equal(t, subscriber.Recording(), replayed.Recording())
+	events, err := replayed.WaitForNEvents(2, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, testingutils.CloudEventData, events[0].Data)
+
+	// when another request is received
+	req, err := builder.WithID("other").BuildRequest(replayed.SinkURL, types.ContentModeBinary)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	// then the recording differs from the golden one
+	t.Setenv(testingutils.UpdateGoldenFilesEnv, "false")
+	require.EqualError(t, replayed.CompareWithGolden(goldenRecording, volatileHeaders...),
+		"recorded 3 requests, the golden recording "+goldenRecording+" has 2")
+}
@@ -1,6 +1,8 @@
 package testing
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"log"
@@ -8,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"sync"
 	"time"
 
 	"go.uber.org/atomic"
@@ -33,6 +36,94 @@ type Subscriber struct {
 	checkURL         string
 	InternalErrorURL string
 	checkRetriesURL  string
+	// received records all the events received by the Subscriber, in the order of their arrival.
+	received *receivedEvents
+	// faults are injected into the responses of the "/store" Endpoint.
+	faults *faults
+	// recorder records the requests to the sink Endpoints, it is nil if they are not recorded.
+	recorder *recorder
+
+	// mux, listener and tls configure the server started by NewSubscriber.
+	mux      *http.ServeMux
+	listener net.Listener
+	tls      bool
+}
+
+// ReceivedEvent is an event received by the Subscriber.
+type ReceivedEvent struct {
+	// Data is the stored data of the event, as returned by the "/check" Endpoint.
+	Data string
+	// ReceivedAt is the time the Subscriber received the event.
+	ReceivedAt time.Time
+}
+
+// receivedEvents records the received events and notifies the waiting assertions of new ones.
+type receivedEvents struct {
+	mutex  sync.Mutex
+	events []ReceivedEvent
+	// changed is closed and replaced whenever an event is received.
+	changed chan struct{}
+}
+
+func newReceivedEvents() *receivedEvents {
+	return &receivedEvents{changed: make(chan struct{})}
+}
+
+func (r *receivedEvents) add(data string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, ReceivedEvent{Data: data, ReceivedAt: time.Now()})
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// get returns a copy of the received events and a channel which is closed when the next event is received.
+func (r *receivedEvents) get() ([]ReceivedEvent, <-chan struct{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	events := make([]ReceivedEvent, len(r.events))
+	copy(events, r.events)
+	return events, r.changed
+}
+
+// faults configures the faults injected into the responses of the "/store" Endpoint.
+type faults struct {
+	delay        time.Duration
+	statusCodes  []int
+	dropEveryNth int
+	attempts     atomic.Int32
+}
+
+// handle wraps the handler of the "/store" Endpoint with the configured faults. The events of the attempts
+// which are dropped or answered with a status code outside the 2xx-range are not stored.
+func (f *faults) handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		attempt := int(f.attempts.Inc())
+		if f.delay > 0 {
+			select {
+			case <-time.After(f.delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if f.dropEveryNth > 0 && attempt%f.dropEveryNth == 0 {
+			// aborts the response and closes the connection without logging it
+			panic(http.ErrAbortHandler)
+		}
+		statusCode := http.StatusOK
+		if attempt <= len(f.statusCodes) {
+			statusCode = f.statusCodes[attempt-1]
+		}
+		if !is2XXStatusCode(statusCode) {
+			_, _ = io.Copy(io.Discard, r.Body)
+			w.WriteHeader(statusCode)
+			return
+		}
+		next(w, r)
+		if statusCode != http.StatusOK {
+			w.WriteHeader(statusCode)
+		}
+	}
 }
 
 type SubscriberOption func(*Subscriber)
@@ -40,14 +131,27 @@ type SubscriberOption func(*Subscriber)
 // NewSubscriber creates a simple test Subscriber with http Endpoints to received, store end answer
 // event data. NewSubscriber accepts SubscriberOpts for customization.
 func NewSubscriber(opts ...SubscriberOption) *Subscriber {
-	subscriber := &Subscriber{}
+	subscriber := &Subscriber{received: newReceivedEvents(), faults: &faults{}}
 	for _, opt := range opts {
 		opt(subscriber)
 	}
 
-	if subscriber.server == nil {
-		mux := getDataServeMux()
-		subscriber.server = httptest.NewServer(mux)
+	if subscriber.mux == nil {
+		subscriber.mux = getDataServeMux(subscriber.received, subscriber.faults)
+	}
+	var handler http.Handler = subscriber.mux
+	if subscriber.recorder != nil {
+		handler = subscriber.recorder.handle(handler)
+	}
+	subscriber.server = httptest.NewUnstartedServer(handler)
+	if subscriber.listener != nil {
+		subscriber.server.Listener.Close()
+		subscriber.server.Listener = subscriber.listener
+	}
+	if subscriber.tls {
+		subscriber.server.StartTLS()
+	} else {
+		subscriber.server.Start()
 	}
 	subscriber.SinkURL = fmt.Sprintf("%s%s", subscriber.server.URL, storeEndpoint)
 	subscriber.checkURL = fmt.Sprintf("%s%s", subscriber.server.URL, checkEndpoint)
@@ -60,38 +164,66 @@ func NewSubscriber(opts ...SubscriberOption) *Subscriber {
 // "/store" e Endpoint instead of its default behaviour of only storing the http.Request.Body data.
 func WithCloudEventServeMux() SubscriberOption {
 	return func(subscriber *Subscriber) {
-		mux := getCloudEventServeMux()
-		subscriber.server = httptest.NewServer(mux)
+		subscriber.mux = getCloudEventServeMux(subscriber.received, subscriber.faults)
 	}
 }
 
 func WithListener(listener net.Listener) SubscriberOption {
 	return func(subscriber *Subscriber) {
-		mux := getDataServeMux()
-		subscriber.server = httptest.NewUnstartedServer(mux)
-		subscriber.server.Listener.Close()
-		subscriber.server.Listener = listener
-		subscriber.server.Start()
+		subscriber.listener = listener
+	}
+}
+
+// WithTLS will make the Subscriber serve HTTPS with a generated certificate for 127.0.0.1 instead of HTTP.
+// Use the CABundle of the Subscriber to trust it in the clients.
+func WithTLS() SubscriberOption {
+	return func(subscriber *Subscriber) {
+		subscriber.tls = true
+	}
+}
+
+// WithResponseDelay will make the Subscriber delay each response of its "/store" Endpoint by the given duration.
+func WithResponseDelay(delay time.Duration) SubscriberOption {
+	return func(subscriber *Subscriber) {
+		subscriber.faults.delay = delay
+	}
+}
+
+// WithStatusCodes will make the Subscriber answer the n-th attempt at its "/store" Endpoint with the n-th status code,
+// and the attempts after the given status codes with "OK" (code: 200). The events of the attempts which are answered
+// with a status code outside the 2xx-range are not stored.
+func WithStatusCodes(statusCodes ...int) SubscriberOption {
+	return func(subscriber *Subscriber) {
+		subscriber.faults.statusCodes = statusCodes
+	}
+}
+
+// WithDropEveryNth will make the Subscriber close the connection of every n-th attempt at its "/store" Endpoint
+// without a response and without storing the event.
+func WithDropEveryNth(n int) SubscriberOption {
+	return func(subscriber *Subscriber) {
+		subscriber.faults.dropEveryNth = n
 	}
 }
 
 // getCloudEventServeMux sets the Subscriber up to handle all CloudEvent related data (headers etc.) to test
 // against CloudEvents. Use the WithCloudEventServeMux opt to set the Subscriber with this ServeMux.
-func getCloudEventServeMux() *http.ServeMux {
+func getCloudEventServeMux(received *receivedEvents, faults *faults) *http.ServeMux {
 	store := make(chan string, maxNoOfData)
 	retries := atomic.Int32{}
 	mux := http.NewServeMux()
 
 	// this Endpoint stores the CloudEvent as a string.
-	mux.HandleFunc(storeEndpoint, func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc(storeEndpoint, faults.handle(func(w http.ResponseWriter, r *http.Request) {
 		eventString, err := cehelper.RequestToEventString(r)
 		if err != nil {
 			log.Printf("read data failed: %v", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		received.add(eventString)
 		store <- eventString
-	})
+	}))
 	// this Endpoint returns the last stored string. If, after a time out of 0.5 sec, nothing was found in the store
 	// it will return an empty string.
 	mux.HandleFunc(checkEndpoint, func(w http.ResponseWriter, r *http.Request) {
@@ -114,6 +246,7 @@ func getCloudEventServeMux() *http.ServeMux {
 		if eventString, err := cehelper.RequestToEventString(r); err != nil {
 			log.Printf("read data failed: %v", err)
 		} else {
+			received.add(eventString)
 			store <- eventString
 		}
 		retries.Inc()
@@ -133,21 +266,22 @@ func getCloudEventServeMux() *http.ServeMux {
 
 // getDataServeMux sets the Subscriber up to handle the request body data for tests. This is the default ServeMux for
 // the Subscriber. Use this to test against only the event data without any metadata.
-func getDataServeMux() *http.ServeMux {
+func getDataServeMux(received *receivedEvents, faults *faults) *http.ServeMux {
 	store := make(chan string, maxNoOfData)
 	retries := atomic.Int32{}
 	mux := http.NewServeMux()
 
 	// this Endpoint stores the data of the request body.
-	mux.HandleFunc(storeEndpoint, func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc(storeEndpoint, faults.handle(func(w http.ResponseWriter, r *http.Request) {
 		data, err := io.ReadAll(r.Body)
 		if err != nil {
 			log.Printf("read data failed: %v", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		received.add(string(data))
 		store <- string(data)
-	})
+	}))
 	// this Endpoint returns the last stored string. If, after a time out of 0.5 sec, nothing was found in the store
 	// it will return an empty string.
 	mux.HandleFunc(checkEndpoint, func(w http.ResponseWriter, r *http.Request) {
@@ -170,6 +304,7 @@ func getDataServeMux() *http.ServeMux {
 		if data, err := io.ReadAll(r.Body); err != nil {
 			log.Printf("read data failed: %v", err)
 		} else {
+			received.add(string(data))
 			store <- string(data)
 		}
 		retries.Inc()
@@ -191,10 +326,30 @@ func (s *Subscriber) Shutdown() {
 	s.server.Close()
 }
 
+// URL returns the base URL of the Subscriber, without an Endpoint.
+func (s *Subscriber) URL() string {
+	return s.server.URL
+}
+
 func (s *Subscriber) GetSubscriberListener() net.Listener {
 	return s.server.Listener
 }
 
+// Certificate returns the certificate of the Subscriber serving HTTPS, or nil if it serves HTTP.
+func (s *Subscriber) Certificate() *x509.Certificate {
+	return s.server.Certificate()
+}
+
+// CABundle returns the PEM-encoded certificate of the Subscriber serving HTTPS, to be trusted as CA bundle by the
+// clients, or nil if it serves HTTP.
+func (s *Subscriber) CABundle() []byte {
+	certificate := s.Certificate()
+	if certificate == nil {
+		return nil
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})
+}
+
 func (s *Subscriber) IsRunning() bool {
 	return s.CheckEvent("") == nil
 }
@@ -206,7 +361,7 @@ func (s Subscriber) CheckEvent(expectedData string) error {
 	err := retry.Do(
 		func() error {
 			// check if a response was received and that it's code is in 2xx-range
-			resp, err := http.Get(s.checkURL)
+			resp, err := s.server.Client().Get(s.checkURL)
 			if err != nil {
 				return pkgerrors.Wrapf(err, "get HTTP request failed")
 			}
@@ -247,7 +402,7 @@ func (s Subscriber) CheckRetries(expectedNoOfRetries int, expectedData string) e
 	delay := time.Second
 	err := retry.Do(
 		func() error {
-			resp, err := http.Get(s.checkRetriesURL)
+			resp, err := s.server.Client().Get(s.checkRetriesURL)
 			if err != nil {
 				return pkgerrors.Wrapf(err, "get HTTP request failed")
 			}
@@ -286,6 +441,79 @@ func (s Subscriber) CheckRetries(expectedNoOfRetries int, expectedData string) e
 	return nil
 }
 
+// Attempts returns the number of requests the "/store" Endpoint of the Subscriber received so far, including the ones
+// failed by the injected faults.
+func (s *Subscriber) Attempts() int {
+	return int(s.faults.attempts.Load())
+}
+
+// ReceivedEvents returns all the events received by the Subscriber so far, in the order of their arrival.
+// Unlike CheckEvent, it does not consume the events.
+func (s *Subscriber) ReceivedEvents() []ReceivedEvent {
+	events, _ := s.received.get()
+	return events
+}
+
+// WaitForNEvents waits until the Subscriber received at least n events and returns all the received events.
+// It returns an error with the events received so far if they are less than n after the timeout.
+func (s *Subscriber) WaitForNEvents(n int, timeout time.Duration) ([]ReceivedEvent, error) {
+	deadline := time.After(timeout)
+	for {
+		events, changed := s.received.get()
+		if len(events) >= n {
+			return events, nil
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			return events, fmt.Errorf("received %d of %d events within %s", len(events), n, timeout)
+		}
+	}
+}
+
+// CheckEventCount waits until the Subscriber received the expected data n times and returns an error
+// if it did not receive it exactly n times after the timeout.
+func (s *Subscriber) CheckEventCount(expectedData string, n int, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	for {
+		events, changed := s.received.get()
+		count := 0
+		for _, event := range events {
+			if event.Data == expectedData {
+				count++
+			}
+		}
+		if count > n {
+			return fmt.Errorf("received the event %d times, expected %d times", count, n)
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			if count != n {
+				return fmt.Errorf("received the event %d times within %s, expected %d times", count, timeout, n)
+			}
+			return nil
+		}
+	}
+}
+
+// CheckEventsInOrder returns an error if the Subscriber did not receive the expected data in the given order.
+// Other events received in between are ignored.
+func (s *Subscriber) CheckEventsInOrder(expectedData ...string) error {
+	events := s.ReceivedEvents()
+	next := 0
+	for _, event := range events {
+		if next < len(expectedData) && event.Data == expectedData[next] {
+			next++
+		}
+	}
+	if next < len(expectedData) {
+		return fmt.Errorf("received %d of %d events in order, missing event %q", next, len(expectedData),
+			expectedData[next])
+	}
+	return nil
+}
+
 func is2XXStatusCode(statusCode int) bool {
 	return statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
 }
@@ -0,0 +1,149 @@
+package testing_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	testingutils "github.com/kyma-project/kyma/components/eventing-controller/testing"
+)
+
+func Test_Subscriber_ReceivedEvents(t *testing.T) {
+	// given
+	subscriber := testingutils.NewSubscriber()
+	defer subscriber.Shutdown()
+	start := time.Now()
+
+	// when
+	go func() {
+		for _, data := range []string{"first", "second", "third"} {
+			resp, err := http.Post(subscriber.SinkURL, "text/plain", strings.NewReader(data))
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}
+	}()
+
+	// then
+	events, err := subscriber.WaitForNEvents(3, 5*time.Second)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.False(t, events[0].ReceivedAt.Before(start))
+	require.False(t, events[2].ReceivedAt.Before(events[0].ReceivedAt))
+	require.NoError(t, subscriber.CheckEventsInOrder("first", "third"))
+	require.Error(t, subscriber.CheckEventsInOrder("third", "first"))
+	require.NoError(t, subscriber.CheckEventCount("second", 1, 100*time.Millisecond))
+	require.Error(t, subscriber.CheckEventCount("second", 2, 100*time.Millisecond))
+
+	_, err = subscriber.WaitForNEvents(4, 100*time.Millisecond)
+	require.EqualError(t, err, "received 3 of 4 events within 100ms")
+}
+
+func Test_Subscriber_WithTLS(t *testing.T) {
+	// given
+	subscriber := testingutils.NewSubscriber(testingutils.WithTLS())
+	defer subscriber.Shutdown()
+	require.True(t, strings.HasPrefix(subscriber.SinkURL, "https://"))
+	require.True(t, subscriber.IsRunning())
+
+	// when the client does not trust the Subscriber
+	_, err := http.Post(subscriber.SinkURL, "text/plain", strings.NewReader("untrusted"))
+
+	// then
+	require.Error(t, err)
+
+	// when the client trusts the CA bundle of the Subscriber
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(subscriber.CABundle()))
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}}}
+	resp, err := client.Post(subscriber.SinkURL, "text/plain", strings.NewReader("trusted"))
+
+	// then
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.NoError(t, subscriber.CheckEvent("trusted"))
+}
+
+func Test_Subscriber_WithoutTLS(t *testing.T) {
+	subscriber := testingutils.NewSubscriber()
+	defer subscriber.Shutdown()
+
+	require.True(t, strings.HasPrefix(subscriber.SinkURL, "http://"))
+	require.Nil(t, subscriber.Certificate())
+	require.Nil(t, subscriber.CABundle())
+}
+
+func Test_Subscriber_WithStatusCodes(t *testing.T) {
+	// given
+	subscriber := testingutils.NewSubscriber(
+		testingutils.WithStatusCodes(http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent))
+	defer subscriber.Shutdown()
+
+	// when
+	var statusCodes []int
+	for i := 0; i < 4; i++ {
+		resp, err := http.Post(subscriber.SinkURL, "text/plain", strings.NewReader("data"))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		statusCodes = append(statusCodes, resp.StatusCode)
+	}
+
+	// then only the successful attempts are stored
+	require.Equal(t, []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent,
+		http.StatusOK}, statusCodes)
+	require.Equal(t, 4, subscriber.Attempts())
+	require.NoError(t, subscriber.CheckEventCount("data", 2, 100*time.Millisecond))
+}
+
+func Test_Subscriber_WithDropEveryNth(t *testing.T) {
+	// given
+	subscriber := testingutils.NewSubscriber(testingutils.WithDropEveryNth(2))
+	defer subscriber.Shutdown()
+
+	// when
+	var failed []bool
+	for _, data := range []string{"first", "second", "third", "fourth"} {
+		resp, err := http.Post(subscriber.SinkURL, "text/plain", strings.NewReader(data))
+		if err == nil {
+			require.NoError(t, resp.Body.Close())
+		}
+		failed = append(failed, err != nil)
+	}
+
+	// then
+	require.Equal(t, []bool{false, true, false, true}, failed)
+	require.Equal(t, 4, subscriber.Attempts())
+	require.NoError(t, subscriber.CheckEventsInOrder("first", "third"))
+	require.NoError(t, subscriber.CheckEventCount("second", 0, 100*time.Millisecond))
+}
+
+func Test_Subscriber_WithResponseDelay(t *testing.T) {
+	// given
+	subscriber := testingutils.NewSubscriber(testingutils.WithResponseDelay(200 * time.Millisecond))
+	defer subscriber.Shutdown()
+
+	// when the client times out before the delayed response
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	_, err := client.Post(subscriber.SinkURL, "text/plain", strings.NewReader("timeout"))
+
+	// then
+	require.Error(t, err)
+
+	// when the client waits for the delayed response
+	start := time.Now()
+	resp, err := http.Post(subscriber.SinkURL, "text/plain", strings.NewReader("delayed"))
+
+	// then
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	require.NoError(t, subscriber.CheckEventCount("delayed", 1, 100*time.Millisecond))
+}
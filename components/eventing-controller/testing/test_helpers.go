@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/dynamic"
@@ -867,3 +868,71 @@ func WithBackend(backend eventingv1alpha2.Backend) SubscriptionOpt {
 		sub.Status.Backend = backend
 	}
 }
+
+// WithSinkTLS is a SubscriptionOpt for creating a Subscription with the given sink TLS settings.
+func WithSinkTLS(sinkTLS *eventingv1alpha2.SinkTLSConfig) SubscriptionOpt {
+	return func(sub *eventingv1alpha2.Subscription) {
+		sub.Spec.SinkTLS = sinkTLS
+	}
+}
+
+// WithServiceAccountName is a SubscriptionOpt for creating a Subscription which authenticates at the sink
+// with a token of the given ServiceAccount.
+func WithServiceAccountName(name string) SubscriptionOpt {
+	return func(sub *eventingv1alpha2.Subscription) {
+		sub.Spec.ServiceAccountName = name
+	}
+}
+
+// WithSinkRef is a SubscriptionOpt for creating a Subscription with a sinkRef to the given Service instead of a sink URL.
+func WithSinkRef(svcName string, port int32) SubscriptionOpt {
+	return func(sub *eventingv1alpha2.Subscription) {
+		sub.Spec.Sink = ""
+		sub.Spec.SinkRef = &eventingv1alpha2.SinkReference{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Name:       svcName,
+			Port:       port,
+		}
+	}
+}
+
+// WithFunctionSinkRef is a SubscriptionOpt for creating a Subscription with a sinkRef to a serverless Function.
+func WithFunctionSinkRef(functionName string) SubscriptionOpt {
+	return func(sub *eventingv1alpha2.Subscription) {
+		sub.Spec.Sink = ""
+		sub.Spec.SinkRef = &eventingv1alpha2.SinkReference{
+			APIVersion: eventingv1alpha2.FunctionAPIVersion,
+			Kind:       eventingv1alpha2.SinkRefKindFunction,
+			Name:       functionName,
+		}
+	}
+}
+
+// WithTTL is a SubscriptionOpt for creating a Subscription which expires after the given time to live.
+func WithTTL(ttl time.Duration) SubscriptionOpt {
+	return func(sub *eventingv1alpha2.Subscription) {
+		sub.Spec.TTL = &metav1.Duration{Duration: ttl}
+	}
+}
+
+// WithExpiryDate is a SubscriptionOpt for creating a Subscription which expires at the given date.
+func WithExpiryDate(expiryDate time.Time) SubscriptionOpt {
+	return func(sub *eventingv1alpha2.Subscription) {
+		sub.Spec.ExpiryDate = &metav1.Time{Time: expiryDate}
+	}
+}
+
+// WithFilters is a SubscriptionOpt that sets the spec with the given attribute filters.
+func WithFilters(filters ...eventingv1alpha2.SubscriptionFilter) SubscriptionOpt {
+	return func(sub *eventingv1alpha2.Subscription) {
+		sub.Spec.Filters = filters
+	}
+}
+
+// WithFilterExpression is a SubscriptionOpt that sets the spec with the given CESQL filter expression.
+func WithFilterExpression(expression string) SubscriptionOpt {
+	return func(sub *eventingv1alpha2.Subscription) {
+		sub.Spec.FilterExpression = expression
+	}
+}
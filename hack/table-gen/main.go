@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
 
 	"sigs.k8s.io/yaml"
 )
@@ -34,31 +37,61 @@ const (
 
 **Spec:**
 
-| Parameter | Type | Description |
-| ---- | ----------- | ---- |
+| Parameter | Type | Description |{{ if $version.SpecValidation }} Validation |{{ end }}
+| ---- | ----------- | ---- |{{ if $version.SpecValidation }} ---- |{{ end }}
 {{- range $prop := $version.Spec }}
-| **{{range $i, $v := $prop.Path}}{{if $i}}.&#x200b;{{end}}{{$v}}{{end}}** {{ if $prop.Required}}(required){{ end }} | {{ markdownEscape $prop.ElemType }} | {{ $prop.Description }} |
+| **{{range $i, $v := $prop.Path}}{{if $i}}.&#x200b;{{end}}{{$v}}{{end}}** {{ if $prop.Required}}(required){{ end }} | {{ markdownEscape $prop.ElemType }}{{ if $prop.Format }} ({{ markdownEscape $prop.Format }}){{ end }}{{ if $prop.Nullable }} (nullable){{ end }} | {{ $prop.Description }} |{{ if $version.SpecValidation }} {{ $prop.Validation }} |{{ end }}
 {{- end }}
 {{- end }}
 {{ if $version.Status }}
 **Status:**
 
-| Parameter | Type | Description |
-| ---- | ----------- | ---- |
+| Parameter | Type | Description |{{ if $version.StatusValidation }} Validation |{{ end }}
+| ---- | ----------- | ---- |{{ if $version.StatusValidation }} ---- |{{ end }}
 {{- range $prop := $version.Status }}
-| **{{range $i, $v := $prop.Path}}{{if $i}}.&#x200b;{{end}}{{$v}}{{end}}** {{ if $prop.Required}}(required){{ end }} | {{ markdownEscape $prop.ElemType }} | {{ $prop.Description }} |
+| **{{range $i, $v := $prop.Path}}{{if $i}}.&#x200b;{{end}}{{$v}}{{end}}** {{ if $prop.Required}}(required){{ end }} | {{ markdownEscape $prop.ElemType }}{{ if $prop.Format }} ({{ markdownEscape $prop.Format }}){{ end }}{{ if $prop.Nullable }} (nullable){{ end }} | {{ $prop.Description }} |{{ if $version.StatusValidation }} {{ $prop.Validation }} |{{ end }}
 {{- end }}
 {{- end }}
 
 {{ end -}}`
+
+	// intOrStringType is the type of the properties with the x-kubernetes-int-or-string extension.
+	intOrStringType = "int-or-string"
+	// rawObjectType is the type of the objects with the x-kubernetes-preserve-unknown-fields extension,
+	// which accept any fields.
+	rawObjectType = "object (raw)"
+
+	// template to be used for rendering several CRDs into one page. It lists the kinds with links to their
+	// sections, and renders each CRD with the documentationTemplate below a heading of its kind.
+	combinedTemplate = `
+**Index:**
+{{ range $crd := . }}
+- [{{ $crd.Kind }}](#{{ $crd.Anchor }})
+{{- end }}
+{{ range $crd := . }}
+## {{ $crd.Kind }}
+
+{{ $crd.Versions }}
+{{ end }}
+`
 )
 
 var (
-	CRDFilename string
-	MDFilename  string
-	APIVersion  string
-	CRDKind     string
-	CRDGroup    string
+	MDFilename       string
+	MDDir            string
+	CRDDir           string
+	TemplateFilename string
+	APIVersion       string
+	CRDKind          string
+	CRDGroup         string
+
+	// validationKeywords are the schema keywords of the value constraints rendered in the Validation column,
+	// in the order they are rendered.
+	validationKeywords = []string{"minimum", "maximum", "minLength", "maxLength", "pattern"}
+
+	// versionsTemplate is the template the versions of a CRD are rendered with,
+	// the documentationTemplate unless it is overridden with the template flag.
+	versionsTemplate = documentationTemplate
 )
 
 // element contains one tree element. can be a simple type (string,
@@ -66,7 +99,10 @@ type element struct {
 	name        string
 	description string
 	elemtype    string
+	format      string
 	required    bool
+	nullable    bool
+	validation  string
 	items       *element
 	properties  []*element
 }
@@ -75,18 +111,31 @@ type flatElement struct {
 	Path        []string
 	Description string
 	ElemType    string
+	Format      string // OpenAPI format of the type, such as "date-time"
 	Required    bool
+	Nullable    bool
+	Validation  string // value constraints of the schema, such as "minimum: 1"
+}
+
+// crdDoc is the documentation of one CRD of a combined page.
+type crdDoc struct {
+	Kind     string
+	Anchor   string // anchor of the heading of the kind, linked from the index
+	Versions string // documentation of the versions rendered with the documentationTemplate
 }
 
 type crdVersion struct {
 	GKV                        string // API-GroupKindVersion
 	Spec, Status               []flatElement
+	SpecValidation             bool // whether any Spec element has value constraints
+	StatusValidation           bool // whether any Status element has value constraints
 	Stored, Served, Deprecated bool
 	DeprecationWarning         string
 }
 
 func (e *element) String() string {
-	s := fmt.Sprintf("-----\nname:%v\ndesc:%v\ntype:%v\nreq:%v", e.name, e.description, e.elemtype, e.required)
+	s := fmt.Sprintf("-----\nname:%v\ndesc:%v\ntype:%v\nformat:%v\nreq:%v",
+		e.name, e.description, e.elemtype, e.format, e.required)
 	s = fmt.Sprintf("%v\nitems: %v", s, e.items)
 	for _, p := range e.properties {
 		s = fmt.Sprintf("%v \n - %v", s, p)
@@ -105,30 +154,124 @@ func (af *arrayFlags) Set(value string) error {
 	return nil
 }
 
-var ignoreSpec, ignoreStatus arrayFlags
+var crdFilenames, ignoreSpec, ignoreStatus arrayFlags
 
 func main() {
-	flag.StringVar(&CRDFilename, "crd-filename", "", "Full or relative Path to the .yaml file containing crd")
+	flag.Var(&crdFilenames, "crd-filename", "Full or relative Path to the .yaml file containing crd. Can appear multiple times to render all CRDs into one page with an index.")
+	flag.StringVar(&CRDDir, "crd-dir", "", "Full or relative Path to a directory of .yaml files containing crds. All CRDs of the directory are rendered, in addition to the crd-filename ones.")
 	flag.StringVar(&MDFilename, "md-filename", "", "Full or relative Path to the .md file containing the file where we should insert table rows")
+	flag.StringVar(&MDDir, "md-dir", "", "Full or relative Path to a directory with one .md file per CRD, instead of md-filename. The file of a CRD is named after its kind, optionally with a prefix, such as 'evnt-01-subscription.md', and created if it does not exist.")
+	flag.StringVar(&TemplateFilename, "template", "", "Full or relative Path to a Go template file replacing the built-in template of the versions of a CRD. It is executed with the sorted versions and can use the markdownEscape function.")
 	flag.Var(&ignoreSpec, "ignore-spec", "Spec property path to ignore during table generation. Can appear multiple times. Eg. `-ignore-spec 'foo.bar' -ignore-spec 'foo.baz'")
 	flag.Var(&ignoreStatus, "ignore-status", "Status property path to ignore during table generation. Can appear multiple times. Eg. `-ignore-status 'foo.bar' -ignore-status 'foo.baz'")
 	flag.Parse()
 
-	if CRDFilename == "" {
-		panic(fmt.Errorf("crd-filename cannot be empty. Please enter the correct filename"))
+	if TemplateFilename != "" {
+		versionsTemplate = readTemplate(TemplateFilename)
+	}
+
+	if CRDDir != "" {
+		crdFilenames = append(crdFilenames, findCRDFiles(CRDDir)...)
+	}
+
+	if len(crdFilenames) == 0 {
+		panic(fmt.Errorf("crd-filename and crd-dir cannot be empty. Please enter the correct filename or a directory with CRDs"))
+	}
+
+	if (MDFilename == "") == (MDDir == "") {
+		panic(fmt.Errorf("either md-filename or md-dir must be set. Please enter the correct filename or directory"))
+	}
+
+	if MDDir != "" {
+		for _, crdFilename := range crdFilenames {
+			kind, _ := readCRDVersions(crdFilename)
+			replaceDocInMD(findOrCreateMDFile(MDDir, kind), generateDocFromCRD(crdFilename))
+		}
+		return
+	}
+
+	var doc string
+	if len(crdFilenames) == 1 {
+		doc = generateDocFromCRD(crdFilenames[0])
+	} else {
+		doc = generateCombinedDoc(crdFilenames)
 	}
+	replaceDocInMD(MDFilename, doc)
+}
 
-	if MDFilename == "" {
-		panic(fmt.Errorf("md-filename cannot be empty. Please enter the correct filename"))
+// readTemplate returns the content of the given template file, after checking that it parses.
+func readTemplate(templateFilename string) string {
+	content, err := os.ReadFile(templateFilename)
+	if err != nil {
+		panic(err)
+	}
+	if _, err := newVersionsTemplate(string(content)); err != nil {
+		panic(fmt.Errorf("invalid template %v: %w", templateFilename, err))
+	}
+	return string(content)
+}
+
+// findCRDFiles returns the .yaml and .yml files of the given directory which contain a CRD, sorted by name.
+// Other files, such as kustomizations, are skipped.
+func findCRDFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		panic(err)
+	}
+	var crdFiles []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		filename := filepath.Join(dir, entry.Name())
+		input, err := os.ReadFile(filename)
+		if err != nil {
+			panic(err)
+		}
+		var obj struct {
+			Kind string `json:"kind"`
+		}
+		if err := yaml.Unmarshal(input, &obj); err != nil || obj.Kind != "CustomResourceDefinition" {
+			log.Printf("Skipping %v, it does not contain a CRD", filename)
+			continue
+		}
+		crdFiles = append(crdFiles, filename)
 	}
+	return crdFiles
+}
 
-	doc := generateDocFromCRD()
-	replaceDocInMD(doc)
+// findOrCreateMDFile returns the .md file of the given directory documenting the given kind. The file name is the
+// lower case kind, optionally with a prefix separated by a dash. If there is no such file, it is created with a
+// heading and the TABLE-START and TABLE-END tags.
+func findOrCreateMDFile(dir, kind string) string {
+	name := strings.ToLower(kind) + ".md"
+	matches, err := filepath.Glob(filepath.Join(dir, "*-"+name))
+	if err != nil {
+		panic(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+		matches = append(matches, filepath.Join(dir, name))
+	}
+	switch len(matches) {
+	case 0:
+		mdFilename := filepath.Join(dir, name)
+		content := fmt.Sprintf("# %v\n\n<!-- TABLE-START -->\n<!-- TABLE-END -->\n", kind)
+		if err := os.WriteFile(mdFilename, []byte(content), 0644); err != nil {
+			panic(err)
+		}
+		return mdFilename
+	case 1:
+		return matches[0]
+	default:
+		panic(fmt.Errorf("several files document the kind %v: %v", kind, strings.Join(matches, ", ")))
+	}
 }
 
-// replaceDocInMD replaces the content between TABLE-START and TABLE-END tags with the newly generated content in doc.
-func replaceDocInMD(doc string) {
-	inDoc, err := os.ReadFile(MDFilename)
+// replaceDocInMD replaces the content between TABLE-START and TABLE-END tags of the given file
+// with the newly generated content in doc.
+func replaceDocInMD(mdFilename, doc string) {
+	inDoc, err := os.ReadFile(mdFilename)
 	if err != nil {
 		panic(err)
 	}
@@ -140,7 +283,7 @@ func replaceDocInMD(doc string) {
 	re := regexp.MustCompile(REPattern)
 	outDoc := re.ReplaceAll(inDoc, []byte(newContent))
 
-	outFile, err := os.OpenFile(MDFilename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	outFile, err := os.OpenFile(mdFilename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
 	if err != nil {
 		panic(err)
 	}
@@ -149,9 +292,61 @@ func replaceDocInMD(doc string) {
 }
 
 // generateDocFromCRD generates table of content out of CRD.
-// elementsToSkip are the elements to skip generated by getElementsToSkip function.
-func generateDocFromCRD() string {
-	input, err := os.ReadFile(CRDFilename)
+func generateDocFromCRD(crdFilename string) string {
+	_, crdVersions := readCRDVersions(crdFilename)
+	return generateSnippet(crdVersions)
+}
+
+// generateCombinedDoc generates one page out of several CRDs, with an index of their kinds.
+// The CRDs are rendered in the given order.
+func generateCombinedDoc(crdFilenames []string) string {
+	var docs []crdDoc
+	anchors := map[string]int{}
+	for _, crdFilename := range crdFilenames {
+		kind, crdVersions := readCRDVersions(crdFilename)
+		docs = append(docs, crdDoc{
+			Kind:     kind,
+			Anchor:   headingAnchor(kind, anchors),
+			Versions: strings.TrimRight(generateSnippet(crdVersions), "\n"),
+		})
+	}
+
+	tmpl, err := template.New("").Parse(combinedTemplate)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var b strings.Builder
+	if err = tmpl.Execute(&b, docs); err != nil {
+		log.Fatal(err)
+	}
+	return b.String()
+}
+
+// headingAnchor returns the anchor of a Markdown heading with the given text, the way GitHub generates it:
+// lower case, without punctuation, and with dashes for spaces. Repeated headings get a numbered suffix,
+// counted in anchors.
+func headingAnchor(heading string, anchors map[string]int) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		}
+	}
+	anchor := b.String()
+	count := anchors[anchor]
+	anchors[anchor]++
+	if count > 0 {
+		anchor = fmt.Sprintf("%v-%v", anchor, count)
+	}
+	return anchor
+}
+
+// readCRDVersions reads the kind and the sorted versions of the CRD in the given file.
+func readCRDVersions(crdFilename string) (string, []crdVersion) {
+	input, err := os.ReadFile(crdFilename)
 	if err != nil {
 		panic(err)
 	}
@@ -184,6 +379,8 @@ func generateDocFromCRD() string {
 			crd.GKV = fmt.Sprintf("%v.%v/%v", CRDKind, CRDGroup, APIVersion)
 			crd.Spec = filterIgnored(pathList(version, "spec"), ignoreSpec)
 			crd.Status = filterIgnored(pathList(version, "status"), ignoreStatus)
+			crd.SpecValidation = hasValidation(crd.Spec)
+			crd.StatusValidation = hasValidation(crd.Status)
 			crdVersions = append(crdVersions, crd)
 		}
 	}
@@ -202,11 +399,16 @@ func generateDocFromCRD() string {
 		}
 		return false
 	})
-	return generateSnippet(crdVersions)
+	return CRDKind, crdVersions
+}
+
+// newVersionsTemplate parses the given template of the versions of a CRD with the markdownEscape function.
+func newVersionsTemplate(text string) (*template.Template, error) {
+	return template.New("").Funcs(template.FuncMap{"markdownEscape": markdownEscape}).Parse(text)
 }
 
 func generateSnippet(versions []crdVersion) string {
-	tmpl, err := template.New("").Funcs(template.FuncMap{"markdownEscape": markdownEscape}).Parse(documentationTemplate)
+	tmpl, err := newVersionsTemplate(versionsTemplate)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -267,7 +469,10 @@ func flatten(e *element) []flatElement {
 		Path:        []string{e.name},
 		Description: e.description,
 		ElemType:    e.elemtype,
+		Format:      e.format,
 		Required:    e.required,
+		Nullable:    e.nullable,
+		Validation:  e.validation,
 	}
 
 	// recurse into child properties
@@ -315,6 +520,13 @@ func flattenArray(from *element, to *flatElement, flatElems []flatElement) []fla
 	} else { // handle array of simple type
 		for _, item := range items {
 			to.ElemType = fmt.Sprintf("[]%v", item.ElemType)
+			if to.Format == "" {
+				to.Format = item.Format
+			}
+			// the constraints of the items are shown on the list, as the items are not listed themselves
+			if item.Validation != "" && to.Validation == "" {
+				to.Validation = fmt.Sprintf("items %v", item.Validation)
+			}
 		}
 	}
 	return flatElems
@@ -336,6 +548,7 @@ func convertUnstructuredToElementTree(obj interface{}, name string, required boo
 	if !ok {
 		return &e
 	}
+	m = mergeAllOf(m)
 
 	e.name = name
 	e.required = required
@@ -344,8 +557,15 @@ func convertUnstructuredToElementTree(obj interface{}, name string, required boo
 	}
 
 	e.elemtype = getType(m)
+	e.validation = getValidation(m)
+	if format, ok := m["format"].(string); ok {
+		e.format = format
+	}
+	if nullable, ok := m["nullable"].(bool); ok {
+		e.nullable = nullable
+	}
 
-	if e.elemtype == "object" {
+	if e.elemtype == "object" || e.elemtype == rawObjectType {
 		handleObjectType(&e, m)
 	}
 
@@ -355,9 +575,31 @@ func convertUnstructuredToElementTree(obj interface{}, name string, required boo
 			e.items = convertUnstructuredToElementTree(p, "items", false)
 		}
 	}
+
+	if e.elemtype != "object" && e.elemtype != rawObjectType && e.elemtype != "array" {
+		e.elemtype = withEnum(e.elemtype, m)
+	}
 	return &e
 }
 
+// withEnum appends the allowed values of the given schema to the given type, such as "string (Allowed: Memory, File)",
+// or returns the type as it is if the schema has no enum.
+func withEnum(elemtype string, p map[string]interface{}) string {
+	enum, ok := p["enum"].([]interface{})
+	if !ok || len(enum) == 0 {
+		return elemtype
+	}
+	values := make([]string, 0, len(enum))
+	for _, v := range enum {
+		if f, ok := v.(float64); ok {
+			values = append(values, strconv.FormatFloat(f, 'f', -1, 64))
+			continue
+		}
+		values = append(values, fmt.Sprintf("%v", v))
+	}
+	return fmt.Sprintf("%v (Allowed: %v)", elemtype, strings.Join(values, ", "))
+}
+
 func handleObjectType(e *element, m map[string]interface{}) {
 	e.properties = []*element{}
 
@@ -383,26 +625,135 @@ func handleObjectType(e *element, m map[string]interface{}) {
 }
 
 func getType(p map[string]interface{}) string {
+	// the Kubernetes extensions take precedence, since they come with an anyOf or an object type
+	if intOrString, ok := p["x-kubernetes-int-or-string"].(bool); ok && intOrString {
+		return intOrStringType
+	}
+	if preserve, ok := p["x-kubernetes-preserve-unknown-fields"].(bool); ok && preserve {
+		if typeVal, ok := p["type"].(string); !ok || typeVal == "object" {
+			return rawObjectType
+		}
+	}
 	if typeVal, ok := p["type"].(string); ok {
 		return typeVal
 	}
-	if anyOfVal, ok := p["anyOf"].([]interface{}); ok {
-		var anyOfStringVal []string
-		for _, v := range anyOfVal {
-			var typeValue = "UNKNOWN TYPE"
-			castedValue, ok := v.(map[string]interface{})
-			if ok {
-				typeValue = getType(castedValue)
+	if _, ok := p["allOf"].([]interface{}); ok {
+		if merged := mergeAllOf(p); merged["type"] != nil {
+			return getType(merged)
+		}
+	}
+	for _, composition := range []string{"anyOf", "oneOf"} {
+		if alternatives, ok := p[composition].([]interface{}); ok {
+			if types := alternativeTypes(alternatives); len(types) > 0 {
+				return fmt.Sprintf("{%s}", strings.Join(types, " or "))
 			}
-
-			anyOfStringVal = append(anyOfStringVal, typeValue)
 		}
-		return fmt.Sprintf("{%s}", strings.Join(anyOfStringVal, " or "))
 	}
 
 	return "UNKNOWN TYPE"
 }
 
+// alternativeTypes returns the distinct types of the given anyOf or oneOf sub-schemas, in their order.
+// The sub-schemas without a type, which only add constraints such as required properties, are skipped.
+func alternativeTypes(alternatives []interface{}) []string {
+	var types []string
+	seen := map[string]bool{}
+	for _, v := range alternatives {
+		alternative, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		typeValue := getType(alternative)
+		if typeValue == "UNKNOWN TYPE" || seen[typeValue] {
+			continue
+		}
+		seen[typeValue] = true
+		types = append(types, typeValue)
+	}
+	return types
+}
+
+// mergeAllOf returns the given schema with its allOf sub-schemas merged into it, since a value has to match all of
+// them. The properties and required properties are combined, and the type, description, items, and enum are taken
+// from the first schema which has them. The schema is returned as it is if it has no allOf.
+func mergeAllOf(p map[string]interface{}) map[string]interface{} {
+	subSchemas, ok := p["allOf"].([]interface{})
+	if !ok {
+		return p
+	}
+	merged := map[string]interface{}{}
+	for k, v := range p {
+		if k != "allOf" {
+			merged[k] = v
+		}
+	}
+	for _, v := range subSchemas {
+		subSchema, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		subSchema = mergeAllOf(subSchema)
+		for k, v := range subSchema {
+			switch k {
+			case "properties":
+				properties, _ := merged[k].(map[string]interface{})
+				combined := map[string]interface{}{}
+				for name, property := range properties {
+					combined[name] = property
+				}
+				additional, _ := v.(map[string]interface{})
+				for name, property := range additional {
+					if _, exists := combined[name]; !exists {
+						combined[name] = property
+					}
+				}
+				merged[k] = combined
+			case "required":
+				required, _ := merged[k].([]interface{})
+				additional, _ := v.([]interface{})
+				merged[k] = append(append([]interface{}{}, required...), additional...)
+			default:
+				if _, exists := merged[k]; !exists {
+					merged[k] = v
+				}
+			}
+		}
+	}
+	return merged
+}
+
+// getValidation returns the value constraints of the given schema, such as "minimum: 1, maxLength: 63",
+// or an empty string if it has none.
+func getValidation(p map[string]interface{}) string {
+	var constraints []string
+	for _, keyword := range validationKeywords {
+		value, ok := p[keyword]
+		if !ok {
+			continue
+		}
+		switch v := value.(type) {
+		case float64:
+			constraints = append(constraints, fmt.Sprintf("%v: %v", keyword, strconv.FormatFloat(v, 'f', -1, 64)))
+		case string:
+			// the pipes are escaped, they would end the table cell otherwise
+			constraints = append(constraints, fmt.Sprintf("%v: `%v`", keyword, strings.ReplaceAll(v, "|", `\|`)))
+		default:
+			constraints = append(constraints, fmt.Sprintf("%v: %v", keyword, v))
+		}
+	}
+	return strings.Join(constraints, ", ")
+}
+
+// hasValidation returns true if any of the given elements has value constraints.
+func hasValidation(elements []flatElement) bool {
+	for _, elem := range elements {
+		if elem.Validation != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func contains(list []interface{}, value string) bool {
 	for _, i := range list {
 		if i.(string) == value {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -203,3 +205,522 @@ func TestFilterIgnored(t *testing.T) {
 		})
 	}
 }
+
+func TestHeadingAnchor(t *testing.T) {
+	anchors := map[string]int{}
+	for _, tt := range []struct {
+		heading string
+		want    string
+	}{
+		{heading: "EventType", want: "eventtype"},
+		{heading: "Subscription Template", want: "subscription-template"},
+		{heading: "EventType.eventing.kyma-project.io/v1alpha1", want: "eventtypeeventingkyma-projectiov1alpha1"},
+		{heading: "EventType", want: "eventtype-1"},
+		{heading: "EventType", want: "eventtype-2"},
+	} {
+		if got := headingAnchor(tt.heading, anchors); got != tt.want {
+			t.Errorf("headingAnchor(%q) = %v, want %v", tt.heading, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateCombinedDoc(t *testing.T) {
+	dir := t.TempDir()
+	var crdFilenames []string
+	for _, kind := range []string{"Foo", "Bar"} {
+		crd := `spec:
+  group: example.com
+  names:
+    kind: ` + kind + `
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        properties:
+          spec:
+            properties:
+              name:
+                description: Name of the ` + kind + `.
+                type: string
+            type: object
+        type: object
+`
+		crdFilename := filepath.Join(dir, strings.ToLower(kind)+".yaml")
+		if err := os.WriteFile(crdFilename, []byte(crd), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		crdFilenames = append(crdFilenames, crdFilename)
+	}
+
+	got := generateCombinedDoc(crdFilenames)
+
+	for _, want := range []string{
+		"- [Foo](#foo)\n- [Bar](#bar)\n",
+		"## Foo\n\n### Foo.example.com/v1\n",
+		"## Bar\n\n### Bar.example.com/v1\n",
+		"| **name**  | string | Name of the Bar. |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generateCombinedDoc() = %v, want it to contain %v", got, want)
+		}
+	}
+	if strings.Index(got, "## Foo") > strings.Index(got, "## Bar") {
+		t.Errorf("generateCombinedDoc() = %v, want the CRDs in the given order", got)
+	}
+}
+
+func TestFindCRDFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"b.crd.yaml":         "kind: CustomResourceDefinition\n",
+		"a.crd.yml":          "---\nkind: CustomResourceDefinition\n",
+		"kustomization.yaml": "kind: Kustomization\n",
+		"README.md":          "kind: CustomResourceDefinition\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested.yaml"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	got := findCRDFiles(dir)
+
+	want := []string{filepath.Join(dir, "a.crd.yml"), filepath.Join(dir, "b.crd.yaml")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findCRDFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestFindOrCreateMDFile(t *testing.T) {
+	dir := t.TempDir()
+	prefixed := filepath.Join(dir, "evnt-01-subscription.md")
+	if err := os.WriteFile(prefixed, []byte("<!-- TABLE-START -->\n<!-- TABLE-END -->\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := findOrCreateMDFile(dir, "Subscription"); got != prefixed {
+		t.Errorf("findOrCreateMDFile() = %v, want the existing file %v", got, prefixed)
+	}
+
+	created := findOrCreateMDFile(dir, "EventType")
+	if want := filepath.Join(dir, "eventtype.md"); created != want {
+		t.Errorf("findOrCreateMDFile() = %v, want %v", created, want)
+	}
+	content, err := os.ReadFile(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# EventType\n\n<!-- TABLE-START -->\n<!-- TABLE-END -->\n"; string(content) != want {
+		t.Errorf("findOrCreateMDFile() created %q, want %q", content, want)
+	}
+	replaceDocInMD(created, "### EventType.example.com/v1\n")
+	if content, _ = os.ReadFile(created); !strings.Contains(string(content), "### EventType.example.com/v1\n") {
+		t.Errorf("replaceDocInMD() = %q, want it to contain the doc", content)
+	}
+}
+
+func TestReadTemplate(t *testing.T) {
+	dir := t.TempDir()
+	templateFilename := filepath.Join(dir, "custom.tmpl")
+	custom := `{{ range . }}#### {{ .GKV }}
+{{ range .Spec }}- {{ index .Path 0 }}: {{ markdownEscape .ElemType }}
+{{ end }}{{ end }}`
+	if err := os.WriteFile(templateFilename, []byte(custom), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { versionsTemplate = documentationTemplate }()
+
+	versionsTemplate = readTemplate(templateFilename)
+	got := generateSnippet([]crdVersion{{
+		GKV:  "Foo.example.com/v1",
+		Spec: []flatElement{{Path: []string{"names"}, ElemType: "[]string"}},
+	}})
+
+	if want := "#### Foo.example.com/v1\n- names: \\[\\]string\n"; got != want {
+		t.Errorf("generateSnippet() = %q, want %q", got, want)
+	}
+}
+
+func TestReadTemplate_Invalid(t *testing.T) {
+	templateFilename := filepath.Join(t.TempDir(), "invalid.tmpl")
+	if err := os.WriteFile(templateFilename, []byte("{{ range . }}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("readTemplate() did not panic on an invalid template")
+		}
+	}()
+
+	readTemplate(templateFilename)
+}
+
+func TestGetValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema map[string]interface{}
+		want   string
+	}{
+		{
+			name:   "no constraints",
+			schema: map[string]interface{}{"type": "string"},
+			want:   "",
+		},
+		{
+			name:   "numeric constraints",
+			schema: map[string]interface{}{"type": "number", "maximum": 2.5, "minimum": float64(1000000)},
+			want:   "minimum: 1000000, maximum: 2.5",
+		},
+		{
+			name: "string constraints",
+			schema: map[string]interface{}{
+				"type": "string", "minLength": float64(1), "maxLength": float64(63), "pattern": "^(a|b)$",
+			},
+			want: "minLength: 1, maxLength: 63, pattern: `^(a\\|b)$`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getValidation(tt.schema); got != tt.want {
+				t.Errorf("getValidation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateDocFromCRD_Validation(t *testing.T) {
+	crd := `spec:
+  group: example.com
+  names:
+    kind: Foo
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        properties:
+          spec:
+            properties:
+              name:
+                description: Name of the Foo.
+                maxLength: 63
+                type: string
+              replicas:
+                minimum: 1
+                type: integer
+              types:
+                items:
+                  minLength: 1
+                  type: string
+                type: array
+            type: object
+          status:
+            properties:
+              ready:
+                type: boolean
+            type: object
+        type: object
+`
+	crdFilename := filepath.Join(t.TempDir(), "foo.yaml")
+	if err := os.WriteFile(crdFilename, []byte(crd), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := generateDocFromCRD(crdFilename)
+
+	for _, want := range []string{
+		"| Parameter | Type | Description | Validation |\n| ---- | ----------- | ---- | ---- |\n",
+		"| **name**  | string | Name of the Foo. | maxLength: 63 |",
+		"| **replicas**  | integer |  | minimum: 1 |",
+		"| **types**  | \\[\\]string |  | items minLength: 1 |",
+		"**Status:**\n\n| Parameter | Type | Description |\n| ---- | ----------- | ---- |\n| **ready**  | boolean |  |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generateDocFromCRD() = %v, want it to contain %v", got, want)
+		}
+	}
+}
+
+func TestConvertUnstructuredToElementTree_Enum(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"storage":  map[string]interface{}{"type": "string", "enum": []interface{}{"Memory", "File"}},
+			"replicas": map[string]interface{}{"type": "integer", "enum": []interface{}{float64(1), float64(3)}},
+			"modes": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string", "enum": []interface{}{"BINARY", "STRUCTURED"}},
+			},
+			"name": map[string]interface{}{"type": "string"},
+		},
+	}
+
+	got := map[string]string{}
+	for _, elem := range flatten(convertUnstructuredToElementTree(schema, "spec", true)) {
+		got[strings.Join(elem.Path, ".")] = elem.ElemType
+	}
+
+	want := map[string]string{
+		"spec":          "object",
+		"spec.storage":  "string (Allowed: Memory, File)",
+		"spec.replicas": "integer (Allowed: 1, 3)",
+		"spec.modes":    "[]string (Allowed: BINARY, STRUCTURED)",
+		"spec.name":     "string",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertUnstructuredToElementTree() types = %v, want %v", got, want)
+	}
+}
+
+func TestGetType_KubernetesExtensions(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema map[string]interface{}
+		want   string
+	}{
+		{
+			name: "int-or-string with anyOf",
+			schema: map[string]interface{}{
+				"anyOf": []interface{}{
+					map[string]interface{}{"type": "integer"},
+					map[string]interface{}{"type": "string"},
+				},
+				"x-kubernetes-int-or-string": true,
+			},
+			want: "int-or-string",
+		},
+		{
+			name:   "preserve-unknown-fields without type",
+			schema: map[string]interface{}{"x-kubernetes-preserve-unknown-fields": true},
+			want:   "object (raw)",
+		},
+		{
+			name:   "preserve-unknown-fields object",
+			schema: map[string]interface{}{"type": "object", "x-kubernetes-preserve-unknown-fields": true},
+			want:   "object (raw)",
+		},
+		{
+			name:   "disabled extension",
+			schema: map[string]interface{}{"type": "object", "x-kubernetes-preserve-unknown-fields": false},
+			want:   "object",
+		},
+		{
+			name:   "no type",
+			schema: map[string]interface{}{},
+			want:   "UNKNOWN TYPE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getType(tt.schema); got != tt.want {
+				t.Errorf("getType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertUnstructuredToElementTree_RawObject(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"data": map[string]interface{}{"type": "object", "x-kubernetes-preserve-unknown-fields": true},
+			"port": map[string]interface{}{"x-kubernetes-int-or-string": true, "enum": []interface{}{"http", float64(80)}},
+		},
+	}
+
+	got := map[string]string{}
+	for _, elem := range flatten(convertUnstructuredToElementTree(schema, "spec", true)) {
+		got[strings.Join(elem.Path, ".")] = elem.ElemType
+	}
+
+	want := map[string]string{
+		"spec":      "object",
+		"spec.data": "object (raw)",
+		"spec.port": "int-or-string (Allowed: http, 80)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertUnstructuredToElementTree() types = %v, want %v", got, want)
+	}
+}
+
+func TestGetType_Compositions(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema map[string]interface{}
+		want   string
+	}{
+		{
+			name: "oneOf with types",
+			schema: map[string]interface{}{"oneOf": []interface{}{
+				map[string]interface{}{"type": "string"},
+				map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			}},
+			want: "{string or array}",
+		},
+		{
+			name: "oneOf with constraints only",
+			schema: map[string]interface{}{"oneOf": []interface{}{
+				map[string]interface{}{"required": []interface{}{"a"}},
+				map[string]interface{}{"required": []interface{}{"b"}},
+			}},
+			want: "UNKNOWN TYPE",
+		},
+		{
+			name: "anyOf with duplicated types",
+			schema: map[string]interface{}{"anyOf": []interface{}{
+				map[string]interface{}{"type": "string", "format": "date-time"},
+				map[string]interface{}{"type": "string", "format": "date"},
+			}},
+			want: "{string}",
+		},
+		{
+			name: "allOf",
+			schema: map[string]interface{}{"allOf": []interface{}{
+				map[string]interface{}{"required": []interface{}{"a"}},
+				map[string]interface{}{"type": "object"},
+			}},
+			want: "object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getType(tt.schema); got != tt.want {
+				t.Errorf("getType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertUnstructuredToElementTree_AllOf(t *testing.T) {
+	schema := map[string]interface{}{
+		"description": "The spec.",
+		"allOf": []interface{}{
+			map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+				"required":   []interface{}{"name"},
+			},
+			map[string]interface{}{
+				"description": "Ignored, the schema has its own description.",
+				"properties":  map[string]interface{}{"size": map[string]interface{}{"type": "integer"}},
+			},
+		},
+	}
+
+	got := flatten(convertUnstructuredToElementTree(schema, "spec", true))
+
+	want := []flatElement{
+		{Path: []string{"spec"}, Description: "The spec.", ElemType: "object", Required: true},
+		{Path: []string{"spec", "name"}, ElemType: "string", Required: true},
+		{Path: []string{"spec", "size"}, ElemType: "integer"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertUnstructuredToElementTree() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateDocFromCRD_Nullable(t *testing.T) {
+	crd := `spec:
+  group: example.com
+  names:
+    kind: Foo
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        properties:
+          spec:
+            properties:
+              expiry:
+                description: Expiry of the Foo.
+                nullable: true
+                type: string
+              labels:
+                items:
+                  type: string
+                nullable: true
+                type: array
+              name:
+                nullable: false
+                type: string
+            type: object
+        type: object
+`
+	crdFilename := filepath.Join(t.TempDir(), "foo.yaml")
+	if err := os.WriteFile(crdFilename, []byte(crd), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := generateDocFromCRD(crdFilename)
+
+	for _, want := range []string{
+		"| **expiry**  | string (nullable) | Expiry of the Foo. |",
+		"| **labels**  | \\[\\]string (nullable) |  |",
+		"| **name**  | string |  |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generateDocFromCRD() = %v, want it to contain %v", got, want)
+		}
+	}
+}
+
+func TestGenerateDocFromCRD_Format(t *testing.T) {
+	crd := `spec:
+  group: example.com
+  names:
+    kind: Foo
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        properties:
+          spec:
+            properties:
+              sink:
+                format: uri
+                type: string
+              size:
+                format: int64
+                type: integer
+              certificates:
+                items:
+                  format: byte
+                  type: string
+                type: array
+            type: object
+          status:
+            properties:
+              lastUpdateTime:
+                format: date-time
+                nullable: true
+                type: string
+            type: object
+        type: object
+`
+	crdFilename := filepath.Join(t.TempDir(), "foo.yaml")
+	if err := os.WriteFile(crdFilename, []byte(crd), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := generateDocFromCRD(crdFilename)
+
+	for _, want := range []string{
+		"| **sink**  | string (uri) |  |",
+		"| **size**  | integer (int64) |  |",
+		"| **certificates**  | \\[\\]string (byte) |  |",
+		"| **lastUpdateTime**  | string (date\\-time) (nullable) |  |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generateDocFromCRD() = %v, want it to contain %v", got, want)
+		}
+	}
+}